@@ -106,6 +106,10 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		})
 	})
 
+	// the callbacks of IM platforms are verified by the signing secret instead of the token of SQLE.
+	e.POST(fmt.Sprintf("/%s/im/slack/callback", apiV1), v1.SlackCallbackV1)
+	e.POST(fmt.Sprintf("/%s/im/teams/callback", apiV1), v1.TeamsCallbackV1)
+
 	v1Router := e.Group(apiV1)
 	v1Router.Use(sqleMiddleware.JWTTokenAdapter(), sqleMiddleware.JWTWithConfig(dmsV1.JwtSigningKey), sqleMiddleware.VerifyUserIsDisabled(), sqleMiddleware.OperationLogRecord(), accesstoken.CheckLatestAccessToken(controller.GetDMSServerAddress(), jwtPkg.GetTokenDetailFromContextWithOldJwt))
 	v2Router := e.Group(apiV2)
@@ -121,6 +125,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1Router.GET("/rule_templates/:rule_template_name/export", v1.ExportRuleTemplateFile, sqleMiddleware.AdminUserAllowed())
 		v1Router.DELETE("/custom_rules/:rule_id", v1.DeleteCustomRule, sqleMiddleware.AdminUserAllowed())
 		v1Router.POST("/custom_rules", v1.CreateCustomRule, sqleMiddleware.AdminUserAllowed())
+		v1Router.POST("/custom_rules/dry_run", v1.DryRunCustomRule, sqleMiddleware.AdminUserAllowed())
 		v1Router.PATCH("/custom_rules/:rule_id", v1.UpdateCustomRule, sqleMiddleware.AdminUserAllowed())
 		v1Router.PATCH("/rule_knowledge/db_types/:db_type/rules/:rule_name/", v1.UpdateRuleKnowledgeV1, sqleMiddleware.AdminUserAllowed())
 		v1Router.PATCH("/rule_knowledge/db_types/:db_type/custom_rules/:rule_name/", v1.UpdateCustomRuleKnowledgeV1, sqleMiddleware.AdminUserAllowed())
@@ -130,6 +135,9 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1Router.POST("/configurations/ding_talk/test", v1.TestDingTalkConfigV1, sqleMiddleware.AdminUserAllowed())
 		v1Router.GET("/configurations/system_variables", v1.GetSystemVariables, sqleMiddleware.AdminUserAllowed())
 		v1Router.PATCH("/configurations/system_variables", v1.UpdateSystemVariables, sqleMiddleware.AdminUserAllowed())
+		v1Router.GET("/configurations/action_queue", v1.GetActionQueueV1, sqleMiddleware.AdminUserAllowed())
+		v1Router.POST("/configurations/drivers/reload", v1.ReloadDriversV1, sqleMiddleware.AdminUserAllowed())
+		v1Router.GET("/cluster/status", v1.GetClusterStatusV1, sqleMiddleware.AdminUserAllowed())
 		v1Router.GET("/configurations/license", v1.GetLicense, sqleMiddleware.AdminUserAllowed())
 		v1Router.POST("/configurations/license", v1.SetLicense, sqleMiddleware.AdminUserAllowed())
 		v1Router.GET("/configurations/license/info", v1.GetSQLELicenseInfo, sqleMiddleware.AdminUserAllowed())
@@ -140,6 +148,12 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1Router.PATCH("/configurations/wechat_audit", v1.UpdateWechatAuditConfigurationV1, sqleMiddleware.AdminUserAllowed())
 		v1Router.GET("/configurations/wechat_audit", v1.GetWechatAuditConfigurationV1, sqleMiddleware.AdminUserAllowed())
 		v1Router.POST("/configurations/wechat_audit/test", v1.TestWechatAuditConfigV1, sqleMiddleware.AdminUserAllowed())
+		v1Router.PATCH("/configurations/slack", v1.UpdateSlackConfigurationV1, sqleMiddleware.AdminUserAllowed())
+		v1Router.GET("/configurations/slack", v1.GetSlackConfigurationV1, sqleMiddleware.AdminUserAllowed())
+		v1Router.POST("/configurations/slack/test", v1.TestSlackConfigV1, sqleMiddleware.AdminUserAllowed())
+		v1Router.PATCH("/configurations/teams", v1.UpdateTeamsConfigurationV1, sqleMiddleware.AdminUserAllowed())
+		v1Router.GET("/configurations/teams", v1.GetTeamsConfigurationV1, sqleMiddleware.AdminUserAllowed())
+		v1Router.POST("/configurations/teams/test", v1.TestTeamsConfigV1, sqleMiddleware.AdminUserAllowed())
 
 		// statistic
 		v1Router.GET("/statistic/instances/type_percent", v1.GetInstancesTypePercentV1, sqleMiddleware.AdminUserAllowed())
@@ -183,6 +197,17 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 
 		// workflow template
 		v1ProjectAdminRouter.PATCH("/:project_name/workflow_template", v1.UpdateWorkflowTemplate)
+
+		// audit suppression setting
+		v1ProjectAdminRouter.PATCH("/:project_name/audit_suppression_setting", v1.UpdateAuditSuppressionSettingV1)
+
+		// event subscription
+		v1ProjectAdminRouter.POST("/:project_name/event_subscriptions", v1.CreateEventSubscriptionV1)
+		v1ProjectAdminRouter.GET("/:project_name/event_subscriptions", v1.GetEventSubscriptionsV1)
+		v1ProjectAdminRouter.PATCH("/:project_name/event_subscriptions/:subscription_id/", v1.UpdateEventSubscriptionV1)
+		v1ProjectAdminRouter.DELETE("/:project_name/event_subscriptions/:subscription_id/", v1.DeleteEventSubscriptionV1)
+		v1ProjectAdminRouter.GET("/:project_name/event_subscriptions/:subscription_id/deliveries", v1.GetEventDeliveriesV1)
+		v1ProjectAdminRouter.POST("/:project_name/event_subscriptions/:subscription_id/deliveries/:delivery_id/redeliver", v1.RedeliverEventV1)
 	}
 
 	// project member router
@@ -222,6 +247,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 
 		// workflow template
 		v1ProjectRouter.GET("/:project_name/workflow_template", v1.GetWorkflowTemplate)
+		v1ProjectRouter.GET("/:project_name/audit_suppression_setting", v1.GetAuditSuppressionSettingV1)
 
 		// workflow
 		v1ProjectRouter.POST("/:project_name/workflows", DeprecatedBy(apiV2))
@@ -241,6 +267,14 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1ProjectRouter.PATCH("/:project_name/workflows/:workflow_name/", DeprecatedBy(apiV2))
 		v1ProjectRouter.GET("/:project_name/workflows/exports", v1.ExportWorkflowV1)
 		v1ProjectRouter.POST("/:project_name/workflows/:workflow_id/tasks/:task_id/order_file", v1.UpdateSqlFileOrderByWorkflowV1)
+		v1ProjectRouter.POST("/:project_name/workflows/:workflow_id/tasks/:task_id/rehearse", v1.RehearseTaskByWorkflowV1)
+		v1ProjectRouter.GET("/:project_name/workflows/:workflow_id/tasks/:task_id/rehearsal", v1.GetTaskRehearsalByWorkflowV1)
+		v1ProjectRouter.POST("/:project_name/workflows/:workflow_id/tasks/:task_id/resume", v1.ResumeTaskByWorkflowV1)
+		v1ProjectRouter.GET("/:project_name/workflows/:workflow_id/tasks/:task_id/execution_attempts", v1.GetTaskExecutionAttemptsByWorkflowV1)
+		v1ProjectRouter.GET("/:project_name/workflows/:workflow_id/tasks/:task_id/ghost_config", v1.GetTaskGhostConfigByWorkflowV1)
+		v1ProjectRouter.PATCH("/:project_name/workflows/:workflow_id/tasks/:task_id/ghost_config", v1.UpdateTaskGhostConfigByWorkflowV1)
+		v1ProjectRouter.GET("/:project_name/workflows/:workflow_id/tasks/:task_id/ghost_progress", v1.GetTaskGhostProgressByWorkflowV1)
+		v1ProjectRouter.POST("/:project_name/workflows/:workflow_id/tasks/:task_id/ghost_actions", v1.OperateTaskGhostMigrationByWorkflowV1)
 
 		// audit plan; 智能扫描任务
 		v1ProjectRouter.POST("/:project_name/audit_plans", v1.CreateAuditPlan)
@@ -251,6 +285,9 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 		v1ProjectRouter.GET("/:project_name/audit_plans/:audit_plan_name/reports", v1.GetAuditPlanReports)
 		v1ProjectRouter.GET("/:project_name/audit_plans/:audit_plan_name/reports/:audit_plan_report_id/", v1.GetAuditPlanReport)
 		v1ProjectRouter.GET("/:project_name/audit_plans/:audit_plan_name/sqls", v1.GetAuditPlanSQLs)
+		v1ProjectRouter.GET("/:project_name/audit_plans/:audit_plan_name/index_advice", v1.GetAuditPlanIndexAdvice)
+		v1ProjectRouter.GET("/:project_name/audit_plans/:audit_plan_name/schema_snapshots", v1.GetSchemaSnapshots)
+		v1ProjectRouter.GET("/:project_name/audit_plans/:audit_plan_name/schema_drifts", v1.GetSchemaDrifts)
 		v1ProjectRouter.POST("/:project_name/audit_plans/:audit_plan_name/trigger", v1.TriggerAuditPlan)
 		v1ProjectRouter.PATCH("/:project_name/audit_plans/:audit_plan_name/notify_config", v1.UpdateAuditPlanNotifyConfig)
 		v1ProjectRouter.GET("/:project_name/audit_plans/:audit_plan_name/notify_config", v1.GetAuditPlanNotifyConfig)
@@ -325,6 +362,8 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 
 	{
 		v1Router.GET("/user_tips", v1.GetUserTips)
+		v1Router.GET("/user/preference", v1.GetUserPreference)
+		v1Router.PATCH("/user/preference", v1.UpdateUserPreference)
 
 		// 全局 rule template
 		v1Router.GET("/rule_templates", v1.GetRuleTemplates)
@@ -346,6 +385,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config *config.SqleOpti
 
 		// task
 		v1Router.GET("/tasks/audits/:task_id/", v1.GetTask)
+		v1Router.GET("/tasks/audits/:task_id/audit_progress", v1.GetTaskAuditProgress)
 		v1Router.GET("/tasks/audits/:task_id/sqls", v1.GetTaskSQLs)
 		v2Router.GET("/tasks/audits/:task_id/sqls", v2.GetTaskSQLs)
 		v2Router.GET("/tasks/audits/:task_id/files", v2.GetAuditFileList)
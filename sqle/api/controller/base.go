@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/model"
 
 	dmsJWT "github.com/actiontech/dms/pkg/dms-common/api/jwt"
@@ -100,6 +101,30 @@ func GetCurrentUser(c echo.Context, getUser func(context.Context, string, string
 	return user, nil
 }
 
+// GetLanguage returns the language of messages in response, it is the preference
+// of current user, or the Accept-Language of request, or the default language.
+func GetLanguage(c echo.Context) string {
+	key := "language"
+	if lang, ok := c.Get(key).(string); ok {
+		return lang
+	}
+	lang := ""
+	if uid := GetUserID(c); uid != "" {
+		preference, exist, err := model.GetStorage().GetUserPreference(uid)
+		if err == nil && exist {
+			lang = locale.Normalize(preference.Language)
+		}
+	}
+	if lang == "" {
+		lang = locale.ParseAcceptLanguage(c.Request().Header.Get("Accept-Language"))
+	}
+	if lang == "" {
+		lang = locale.DefaultLang()
+	}
+	c.Set(key, lang)
+	return lang
+}
+
 func JSONBaseErrorReq(c echo.Context, err error) error {
 	return c.JSON(http.StatusOK, NewBaseReq(err))
 }
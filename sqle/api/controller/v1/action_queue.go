@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+
+	"github.com/labstack/echo/v4"
+)
+
+type GetActionQueueResV1 struct {
+	controller.BaseRes
+	Data ActionQueueResV1 `json:"data"`
+}
+
+type ActionQueueResV1 struct {
+	// 当前节点正在运行的动作
+	Running []ActionQueueItemResV1 `json:"running"`
+	// 当前节点等待运行的动作，按启动顺序排列
+	Waiting []ActionQueueItemResV1 `json:"waiting"`
+	// 所有节点持有的工单任务动作
+	SqledActions []SqledActionResV1 `json:"sqled_actions"`
+}
+
+type ActionQueueItemResV1 struct {
+	Lane       string     `json:"lane" enums:"interactive,batch"`
+	Kind       string     `json:"kind" enums:"audit,execute"`
+	TaskId     uint       `json:"task_id"`
+	InstanceId uint64     `json:"instance_id"`
+	DDL        bool       `json:"ddl"`
+	EnqueuedAt time.Time  `json:"enqueued_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+}
+
+type SqledActionResV1 struct {
+	TaskId         uint      `json:"task_id"`
+	ActionType     string    `json:"action_type" enums:"audit,execute,rollback,rehearsal,resume"`
+	LeaseOwner     string    `json:"lease_owner"`
+	LeaseExpiredAt time.Time `json:"lease_expired_at"`
+}
+
+// @Summary 获取任务队列状态
+// @Description get the running and waiting actions of audit, execution and rollback
+// @Id getActionQueueV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetActionQueueResV1
+// @router /v1/configurations/action_queue [get]
+func GetActionQueueV1(c echo.Context) error {
+	sqledActions, err := model.GetStorage().GetAllSqledActions()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	status := server.GetActionQueueStatus()
+
+	data := ActionQueueResV1{
+		Running:      convertSlotsToActionQueueItemsResV1(status.Running),
+		Waiting:      convertSlotsToActionQueueItemsResV1(status.Waiting),
+		SqledActions: make([]SqledActionResV1, 0, len(sqledActions)),
+	}
+	for _, action := range sqledActions {
+		data.SqledActions = append(data.SqledActions, SqledActionResV1{
+			TaskId:         action.TaskId,
+			ActionType:     convertActionTypeToString(action.Type),
+			LeaseOwner:     action.LeaseOwner,
+			LeaseExpiredAt: action.LeaseExpiredAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, &GetActionQueueResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func convertSlotsToActionQueueItemsResV1(slots []server.Slot) []ActionQueueItemResV1 {
+	items := make([]ActionQueueItemResV1, 0, len(slots))
+	for _, slot := range slots {
+		items = append(items, ActionQueueItemResV1{
+			Lane:       slot.Lane,
+			Kind:       slot.Kind,
+			TaskId:     slot.TaskId,
+			InstanceId: slot.InstanceId,
+			DDL:        slot.DDL,
+			EnqueuedAt: slot.EnqueuedAt,
+			StartedAt:  slot.StartedAt,
+		})
+	}
+	return items
+}
+
+func convertActionTypeToString(typ int) string {
+	switch typ {
+	case server.ActionTypeAudit:
+		return "audit"
+	case server.ActionTypeExecute:
+		return "execute"
+	case server.ActionTypeRollback:
+		return "rollback"
+	case server.ActionTypeRehearsal:
+		return "rehearsal"
+	case server.ActionTypeResume:
+		return "resume"
+	}
+	return ""
+}
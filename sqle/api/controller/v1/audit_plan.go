@@ -1426,6 +1426,245 @@ func GetAuditPlanSQLs(c echo.Context) error {
 	})
 }
 
+type GetAuditPlanIndexAdviceReqV1 struct {
+	MaxIndexPerTable int `json:"max_index_per_table" query:"max_index_per_table"`
+}
+
+type GetAuditPlanIndexAdviceResV1 struct {
+	controller.BaseRes
+	Data []*AuditPlanTableIndexAdviceResV1 `json:"data"`
+}
+
+type AuditPlanTableIndexAdviceResV1 struct {
+	Schema  string                       `json:"schema" example:"db1"`
+	Table   string                       `json:"table" example:"t1"`
+	Weight  float64                      `json:"weight"`
+	Indexes []*AuditPlanIndexAdviceResV1 `json:"indexes"`
+}
+
+type AuditPlanIndexAdviceResV1 struct {
+	Columns        []string `json:"columns"`
+	CreateIndexSQL string   `json:"create_index_sql" example:"CREATE INDEX idx_t1_c1 ON db1.t1 (c1);"`
+	Weight         float64  `json:"weight"`
+	Fingerprints   []string `json:"fingerprints"`
+	Reasons        []string `json:"reasons"`
+}
+
+// @Summary 获取扫描任务负载级别的索引建议
+// @Description get index advice merged from all SQLs of audit plan, weighted by counter and query time
+// @Id getAuditPlanIndexAdviceV1
+// @Tags audit_plan
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param audit_plan_name path string true "audit plan name"
+// @Param max_index_per_table query int false "max index advice per table, default 3"
+// @Success 200 {object} v1.GetAuditPlanIndexAdviceResV1
+// @router /v1/projects/{project_name}/audit_plans/{audit_plan_name}/index_advice [get]
+func GetAuditPlanIndexAdvice(c echo.Context) error {
+	req := new(GetAuditPlanIndexAdviceReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+
+	projectUid, err := dms.GetPorjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	apName := c.Param("audit_plan_name")
+
+	ap, exist, err := GetAuditPlanIfCurrentUserCanAccess(c, projectUid, apName, v1.OpPermissionTypeViewOtherAuditPlan)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errAuditPlanNotExist)
+	}
+
+	advices, err := auditplan.AdviseWorkloadIndexes(log.NewEntry(), ap, req.MaxIndexPerTable)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]*AuditPlanTableIndexAdviceResV1, 0, len(advices))
+	for _, advice := range advices {
+		table := &AuditPlanTableIndexAdviceResV1{
+			Schema: advice.Schema,
+			Table:  advice.Table,
+			Weight: advice.Weight,
+		}
+		for _, index := range advice.Indexes {
+			table.Indexes = append(table.Indexes, &AuditPlanIndexAdviceResV1{
+				Columns:        index.Columns,
+				CreateIndexSQL: index.CreateIndexSQL,
+				Weight:         index.Weight,
+				Fingerprints:   index.Fingerprints,
+				Reasons:        index.Reasons,
+			})
+		}
+		data = append(data, table)
+	}
+	return c.JSON(http.StatusOK, &GetAuditPlanIndexAdviceResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type GetSchemaSnapshotsReqV1 struct {
+	PageIndex uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize  uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type GetSchemaSnapshotsResV1 struct {
+	controller.BaseRes
+	Data      []*SchemaSnapshotResV1 `json:"data"`
+	TotalNums uint64                 `json:"total_nums"`
+}
+
+type SchemaSnapshotResV1 struct {
+	Version             uint   `json:"version" example:"1"`
+	Schema              string `json:"schema" example:"db1"`
+	TableCount          int    `json:"table_count"`
+	DriftCount          int    `json:"drift_count"`
+	OutOfBandDriftCount int    `json:"out_of_band_drift_count"`
+	CreatedAt           string `json:"created_at"`
+	LastCollectedAt     string `json:"last_collected_at"`
+}
+
+// @Summary 获取库表元数据扫描任务的表结构快照
+// @Description get schema snapshots of schema meta audit plan, a new version is saved when the table structures changed
+// @Id getSchemaSnapshotsV1
+// @Tags audit_plan
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param audit_plan_name path string true "audit plan name"
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Success 200 {object} v1.GetSchemaSnapshotsResV1
+// @router /v1/projects/{project_name}/audit_plans/{audit_plan_name}/schema_snapshots [get]
+func GetSchemaSnapshots(c echo.Context) error {
+	req := new(GetSchemaSnapshotsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+
+	projectUid, err := dms.GetPorjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	ap, exist, err := GetAuditPlanIfCurrentUserCanAccess(c, projectUid, c.Param("audit_plan_name"), v1.OpPermissionTypeViewOtherAuditPlan)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errAuditPlanNotExist)
+	}
+
+	limit, offset := controller.GetLimitAndOffset(req.PageIndex, req.PageSize)
+	snapshots, count, err := model.GetStorage().GetSchemaSnapshotSummaries(ap.ID, int(limit), int(offset))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*SchemaSnapshotResV1, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		data = append(data, &SchemaSnapshotResV1{
+			Version:             snapshot.Version,
+			Schema:              snapshot.Schema,
+			TableCount:          snapshot.TableCount,
+			DriftCount:          snapshot.DriftCount,
+			OutOfBandDriftCount: snapshot.OutOfBandDriftCount,
+			CreatedAt:           snapshot.CreatedAt.Format(time.RFC3339),
+			LastCollectedAt:     snapshot.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSchemaSnapshotsResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: uint64(count),
+	})
+}
+
+type GetSchemaDriftsReqV1 struct {
+	SnapshotVersion uint   `json:"snapshot_version" query:"snapshot_version"`
+	OnlyOutOfBand   bool   `json:"only_out_of_band" query:"only_out_of_band"`
+	PageIndex       uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize        uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type GetSchemaDriftsResV1 struct {
+	controller.BaseRes
+	Data      []*SchemaDriftResV1 `json:"data"`
+	TotalNums uint64              `json:"total_nums"`
+}
+
+type SchemaDriftResV1 struct {
+	TableName  string `json:"table_name" example:"t1"`
+	ObjectType string `json:"object_type" enums:"table,column,index"`
+	ObjectName string `json:"object_name" example:"c1"`
+	ChangeType string `json:"change_type" enums:"created,dropped,modified"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	OutOfBand  bool   `json:"out_of_band"`
+	TaskId     uint   `json:"task_id"`
+	DetectedAt string `json:"detected_at"`
+}
+
+// @Summary 获取库表元数据扫描任务发现的表结构变更
+// @Description get schema drifts between schema snapshots, the drifts not made by executed workflow tasks are out of band
+// @Id getSchemaDriftsV1
+// @Tags audit_plan
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param audit_plan_name path string true "audit plan name"
+// @Param snapshot_version query uint false "the version of snapshot which the drifts are detected in"
+// @Param only_out_of_band query bool false "only the drifts not made by workflow"
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Success 200 {object} v1.GetSchemaDriftsResV1
+// @router /v1/projects/{project_name}/audit_plans/{audit_plan_name}/schema_drifts [get]
+func GetSchemaDrifts(c echo.Context) error {
+	req := new(GetSchemaDriftsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+
+	projectUid, err := dms.GetPorjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	ap, exist, err := GetAuditPlanIfCurrentUserCanAccess(c, projectUid, c.Param("audit_plan_name"), v1.OpPermissionTypeViewOtherAuditPlan)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errAuditPlanNotExist)
+	}
+
+	limit, offset := controller.GetLimitAndOffset(req.PageIndex, req.PageSize)
+	drifts, count, err := model.GetStorage().GetSchemaDrifts(ap.ID, req.SnapshotVersion, req.OnlyOutOfBand, int(limit), int(offset))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]*SchemaDriftResV1, 0, len(drifts))
+	for _, drift := range drifts {
+		data = append(data, &SchemaDriftResV1{
+			TableName:  drift.TableName,
+			ObjectType: drift.ObjectType,
+			ObjectName: drift.ObjectName,
+			ChangeType: drift.ChangeType,
+			Before:     drift.Before,
+			After:      drift.After,
+			OutOfBand:  drift.OutOfBand,
+			TaskId:     drift.TaskId,
+			DetectedAt: drift.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSchemaDriftsResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: uint64(count),
+	})
+}
+
 type GetAuditPlanReportSQLsReqV1 struct {
 	PageIndex uint32 `json:"page_index" query:"page_index" valid:"required"`
 	PageSize  uint32 `json:"page_size" query:"page_size" valid:"required"`
@@ -1495,7 +1734,7 @@ func GetAuditPlanReportSQLsV1(c echo.Context) error {
 	for i, auditPlanReportSQL := range auditPlanReportSQLs {
 		auditPlanReportSQLsResV1[i] = AuditPlanReportSQLResV1{
 			SQL:         auditPlanReportSQL.SQL,
-			AuditResult: auditPlanReportSQL.AuditResults.String(),
+			AuditResult: auditPlanReportSQL.AuditResults.Render(controller.GetLanguage(c)),
 			Number:      auditPlanReportSQL.Number,
 		}
 	}
@@ -1506,10 +1745,10 @@ func GetAuditPlanReportSQLsV1(c echo.Context) error {
 	})
 }
 
-func spliceAuditResults(auditResults []model.AuditResult) string {
+func spliceAuditResults(auditResults []model.AuditResult, lang string) string {
 	results := []string{}
 	for _, auditResult := range auditResults {
-		results = append(results, fmt.Sprintf("[%v]%v", auditResult.Level, auditResult.Message))
+		results = append(results, fmt.Sprintf("[%v]%v", auditResult.Level, auditResult.GetMessage(lang)))
 	}
 	return strings.Join(results, "\n")
 }
@@ -1578,7 +1817,7 @@ func ExportAuditPlanReportV1(c echo.Context) error {
 
 	sqlInfo := [][]string{}
 	for idx, sql := range reportInfo.AuditPlanReportSQLs {
-		sqlInfo = append(sqlInfo, []string{strconv.Itoa(idx + 1), sql.SQL, spliceAuditResults(sql.AuditResults)})
+		sqlInfo = append(sqlInfo, []string{strconv.Itoa(idx + 1), sql.SQL, spliceAuditResults(sql.AuditResults, controller.GetLanguage(c))})
 	}
 
 	err = csvWriter.WriteAll(sqlInfo)
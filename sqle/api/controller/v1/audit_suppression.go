@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+type GetAuditSuppressionSettingResV1 struct {
+	controller.BaseRes
+	Data *AuditSuppressionSettingResV1 `json:"data"`
+}
+
+type AuditSuppressionSettingResV1 struct {
+	SuppressibleLevels  []string `json:"suppressible_levels"`
+	NeedAcknowledgement bool     `json:"need_acknowledgement"`
+}
+
+// GetAuditSuppressionSettingV1
+// @Summary 获取项目 SQL 注释忽略审核结果的配置
+// @Description get the setting of ignoring audit results by annotation in SQL comment, e.g. /* sqle:ignore rule_name reason="..." */
+// @Tags project
+// @Id getAuditSuppressionSettingV1
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Success 200 {object} v1.GetAuditSuppressionSettingResV1
+// @router /v1/projects/{project_name}/audit_suppression_setting [get]
+func GetAuditSuppressionSettingV1(c echo.Context) error {
+	projectUid, err := dms.GetPorjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	setting, err := model.GetStorage().GetAuditSuppressionSettingByProjectId(model.ProjectUID(projectUid))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetAuditSuppressionSettingResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &AuditSuppressionSettingResV1{
+			SuppressibleLevels:  setting.GetSuppressibleLevels(),
+			NeedAcknowledgement: setting.NeedAcknowledgement,
+		},
+	})
+}
+
+type UpdateAuditSuppressionSettingReqV1 struct {
+	// 允许通过注释忽略的审核结果等级
+	SuppressibleLevels *[]string `json:"suppressible_levels" form:"suppressible_levels" valid:"omitempty,dive,oneof=normal notice warn error" enums:"normal,notice,warn,error"`
+	// 审批人审批通过前是否需要确认被忽略的审核结果
+	NeedAcknowledgement *bool `json:"need_acknowledgement" form:"need_acknowledgement"`
+}
+
+// UpdateAuditSuppressionSettingV1
+// @Summary 更新项目 SQL 注释忽略审核结果的配置
+// @Description update the setting of ignoring audit results by annotation in SQL comment
+// @Accept json
+// @Produce json
+// @Tags project
+// @Id updateAuditSuppressionSettingV1
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param setting body v1.UpdateAuditSuppressionSettingReqV1 true "update audit suppression setting request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/audit_suppression_setting [patch]
+func UpdateAuditSuppressionSettingV1(c echo.Context) error {
+	req := new(UpdateAuditSuppressionSettingReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	projectUid, err := dms.GetPorjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	setting, err := s.GetAuditSuppressionSettingByProjectId(model.ProjectUID(projectUid))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.SuppressibleLevels != nil {
+		setting.SuppressibleLevels = strings.Join(*req.SuppressibleLevels, ",")
+	}
+	if req.NeedAcknowledgement != nil {
+		setting.NeedAcknowledgement = *req.NeedAcknowledgement
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(setting))
+}
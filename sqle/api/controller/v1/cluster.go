@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/config"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/cluster"
+	"github.com/labstack/echo/v4"
+)
+
+type GetClusterStatusResV1 struct {
+	controller.BaseRes
+	Data *ClusterStatusResV1 `json:"data"`
+}
+
+type ClusterStatusResV1 struct {
+	IsClusterMode   bool   `json:"is_cluster_mode"`
+	CurrentServerId string `json:"current_server_id"`
+	// 当前持有租约的节点, 租约过期时为空
+	LeaderServerId string              `json:"leader_server_id"`
+	LeaseExpireAt  *time.Time          `json:"lease_expire_at,omitempty"`
+	Nodes          []*ClusterNodeResV1 `json:"nodes"`
+}
+
+type ClusterNodeResV1 struct {
+	ServerId    string    `json:"server_id"`
+	ReportHost  string    `json:"report_host"`
+	StartedAt   time.Time `json:"started_at"`
+	HeartbeatAt time.Time `json:"heartbeat_at"`
+	// 心跳超时的节点为 false
+	IsAlive  bool `json:"is_alive"`
+	IsLeader bool `json:"is_leader"`
+}
+
+// GetClusterStatusV1
+// @Summary 获取集群状态
+// @Description get the leader and the nodes of cluster
+// @Id getClusterStatusV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetClusterStatusResV1
+// @router /v1/cluster/status [get]
+func GetClusterStatusV1(c echo.Context) error {
+	status := &ClusterStatusResV1{
+		IsClusterMode:   cluster.IsClusterMode,
+		CurrentServerId: fmt.Sprintf("%v", config.GetOptions().SqleOptions.ID),
+		Nodes:           []*ClusterNodeResV1{},
+	}
+	if !cluster.IsClusterMode {
+		status.LeaderServerId = status.CurrentServerId
+		return c.JSON(http.StatusOK, &GetClusterStatusResV1{
+			BaseRes: controller.NewBaseReq(nil),
+			Data:    status,
+		})
+	}
+
+	s := model.GetStorage()
+	leader, exist, err := s.GetClusterLeader()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if exist && leader.IsValid {
+		status.LeaderServerId = leader.ServerId
+		status.LeaseExpireAt = &leader.LeaseExpireAt
+	}
+	// the node is considered down if it misses the heartbeats in a lease
+	nodes, err := s.GetClusterNodes(cluster.DefaultLeaseDuration)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	for _, node := range nodes {
+		status.Nodes = append(status.Nodes, &ClusterNodeResV1{
+			ServerId:    node.ServerId,
+			ReportHost:  node.ReportHost,
+			StartedAt:   node.StartedAt,
+			HeartbeatAt: node.HeartbeatAt,
+			IsAlive:     node.IsAlive,
+			IsLeader:    node.ServerId == status.LeaderServerId,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetClusterStatusResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    status,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/driver"
@@ -83,6 +84,11 @@ type UpdateSystemVariablesReqV1 struct {
 	Url                         *string `json:"url" form:"url" example:"http://10.186.61.32:8080" validate:"url"`
 	OperationRecordExpiredHours *int    `json:"operation_record_expired_hours" form:"operation_record_expired_hours" example:"2160"`
 	CbOperationLogsExpiredHours *int    `json:"cb_operation_logs_expired_hours" form:"cb_operation_logs_expired_hours" example:"2160"`
+	RollbackMode                *string `json:"rollback_mode" form:"rollback_mode" enums:"snapshot,binlog" validate:"omitempty,oneof=snapshot binlog"`
+	// 0 表示不限制
+	MaxConcurrentAudits                   *int `json:"max_concurrent_audits" form:"max_concurrent_audits" example:"10" validate:"omitempty,min=0"`
+	MaxConcurrentExecutionsPerInstance    *int `json:"max_concurrent_executions_per_instance" form:"max_concurrent_executions_per_instance" example:"2" validate:"omitempty,min=0"`
+	MaxConcurrentDDLExecutionsPerInstance *int `json:"max_concurrent_ddl_executions_per_instance" form:"max_concurrent_ddl_executions_per_instance" example:"1" validate:"omitempty,min=0"`
 }
 
 // @Summary 修改系统变量
@@ -124,6 +130,26 @@ func UpdateSystemVariables(c echo.Context) error {
 		})
 	}
 
+	if req.RollbackMode != nil {
+		systemVariables = append(systemVariables, model.SystemVariable{
+			Key:   model.SystemVariableRollbackMode,
+			Value: *req.RollbackMode,
+		})
+	}
+
+	for key, limit := range map[string]*int{
+		model.SystemVariableMaxConcurrentAudits:                   req.MaxConcurrentAudits,
+		model.SystemVariableMaxConcurrentExecutionsPerInstance:    req.MaxConcurrentExecutionsPerInstance,
+		model.SystemVariableMaxConcurrentDDLExecutionsPerInstance: req.MaxConcurrentDDLExecutionsPerInstance,
+	} {
+		if limit != nil {
+			systemVariables = append(systemVariables, model.SystemVariable{
+				Key:   key,
+				Value: strconv.Itoa(*limit),
+			})
+		}
+	}
+
 	if req.Url != nil {
 		systemVariables = append(systemVariables, model.SystemVariable{
 			Key:   model.SystemVariableSqleUrl,
@@ -148,6 +174,11 @@ type SystemVariablesResV1 struct {
 	Url                         string `json:"url"`
 	OperationRecordExpiredHours int    `json:"operation_record_expired_hours"`
 	CbOperationLogsExpiredHours int    `json:"cb_operation_logs_expired_hours"`
+	RollbackMode                string `json:"rollback_mode" enums:"snapshot,binlog"`
+	// 0 表示不限制
+	MaxConcurrentAudits                   int `json:"max_concurrent_audits"`
+	MaxConcurrentExecutionsPerInstance    int `json:"max_concurrent_executions_per_instance"`
+	MaxConcurrentDDLExecutionsPerInstance int `json:"max_concurrent_ddl_executions_per_instance"`
 }
 
 // @Summary 获取系统变量
@@ -179,13 +210,22 @@ func GetSystemVariables(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
+	limits, err := s.GetConcurrencyLimits()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
 	return c.JSON(http.StatusOK, &GetSystemVariablesResV1{
 		BaseRes: controller.NewBaseReq(nil),
 		Data: SystemVariablesResV1{
-			WorkflowExpiredHours:        expiredHours,
-			Url:                         systemVariables[model.SystemVariableSqleUrl].Value,
-			OperationRecordExpiredHours: operationRecordExpiredHours,
-			CbOperationLogsExpiredHours: cbOperationLogsExpiredHours,
+			WorkflowExpiredHours:                  expiredHours,
+			Url:                                   systemVariables[model.SystemVariableSqleUrl].Value,
+			OperationRecordExpiredHours:           operationRecordExpiredHours,
+			CbOperationLogsExpiredHours:           cbOperationLogsExpiredHours,
+			RollbackMode:                          systemVariables[model.SystemVariableRollbackMode].Value,
+			MaxConcurrentAudits:                   limits.MaxAudits,
+			MaxConcurrentExecutionsPerInstance:    limits.MaxExecutionsPerInstance,
+			MaxConcurrentDDLExecutionsPerInstance: limits.MaxDDLExecutionsPerInstance,
 		},
 	})
 }
@@ -196,12 +236,45 @@ type GetDriversResV1 struct {
 }
 
 type DriversResV1 struct {
-	Drivers []string `json:"driver_name_list"`
+	Drivers        []string          `json:"driver_name_list"`
+	DriverStatuses []*DriverStatusV1 `json:"driver_status_list"`
+}
+
+type DriverStatusV1 struct {
+	DriverName string `json:"driver_name"`
+	// 插件目录中的插件文件, 内置插件为空
+	FileName string `json:"file_name"`
+	// 插件协议版本
+	Version      int        `json:"version"`
+	Status       string     `json:"status" enums:"running,restarting,failed"`
+	Pid          int        `json:"pid"`
+	RestartCount int        `json:"restart_count"`
+	LastError    string     `json:"last_error"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+}
+
+func convertDriverStatuses(statuses []*driver.PluginStatus) []*DriverStatusV1 {
+	data := make([]*DriverStatusV1, 0, len(statuses))
+	for _, s := range statuses {
+		data = append(data, &DriverStatusV1{
+			DriverName:   s.PluginName,
+			FileName:     s.FileName,
+			Version:      s.Version,
+			Status:       s.Status,
+			Pid:          s.Pid,
+			RestartCount: s.RestartCount,
+			LastError:    s.LastError,
+			LastErrorAt:  s.LastErrorAt,
+			StartedAt:    s.StartedAt,
+		})
+	}
+	return data
 }
 
 // GetDrivers get support Driver list.
 // @Summary 获取当前 server 支持的审核类型
-// @Description get drivers
+// @Description get drivers and the status of driver plugins, the plugin files failed to load are also listed in driver_status_list
 // @Id getDriversV1
 // @Tags configuration
 // @Security ApiKeyAuth
@@ -210,7 +283,29 @@ type DriversResV1 struct {
 func GetDrivers(c echo.Context) error {
 	return c.JSON(http.StatusOK, &GetDriversResV1{
 		BaseRes: controller.NewBaseReq(nil),
-		Data:    DriversResV1{Drivers: driver.GetPluginManager().AllDrivers()},
+		Data: DriversResV1{
+			Drivers:        driver.GetPluginManager().AllDrivers(),
+			DriverStatuses: convertDriverStatuses(driver.GetPluginManager().PluginStatuses()),
+		},
+	})
+}
+
+// ReloadDriversV1
+// @Summary 重新加载插件目录中的插件
+// @Description load the plugins added or updated in plugin dir and the plugins failed to load before, the updated plugin replaces the running one
+// @Id reloadDriversV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetDriversResV1
+// @router /v1/configurations/drivers/reload [post]
+func ReloadDriversV1(c echo.Context) error {
+	err := driver.GetPluginManager().Reload(true)
+	return c.JSON(http.StatusOK, &GetDriversResV1{
+		BaseRes: controller.NewBaseReq(err),
+		Data: DriversResV1{
+			Drivers:        driver.GetPluginManager().AllDrivers(),
+			DriverStatuses: convertDriverStatuses(driver.GetPluginManager().PluginStatuses()),
+		},
 	})
 }
 
@@ -0,0 +1,377 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/eventbus"
+	"github.com/actiontech/sqle/sqle/utils"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EventSubscriptionV1 struct {
+	Id         uint     `json:"id"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	IsEnabled  bool     `json:"is_enabled"`
+}
+
+func convertEventSubscriptionToRes(subscription *model.EventSubscription) EventSubscriptionV1 {
+	return EventSubscriptionV1{
+		Id:         subscription.ID,
+		Name:       subscription.Name,
+		URL:        subscription.URL,
+		EventTypes: subscription.GetEventTypes(),
+		IsEnabled:  subscription.IsEnabled,
+	}
+}
+
+func checkEventSubscription(ctx context.Context, webhookUrl string, eventTypes []string) error {
+	u, err := url.ParseRequestURI(webhookUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New(errors.DataInvalid, fmt.Errorf("invalid webhook url %s", webhookUrl))
+	}
+	if err := eventbus.CheckWebhookHost(ctx, u.Hostname()); err != nil {
+		return errors.New(errors.DataInvalid, fmt.Errorf("invalid webhook url %s: %v", webhookUrl, err))
+	}
+	for _, eventType := range eventTypes {
+		if !eventbus.IsValidEventType(eventType) {
+			return errors.New(errors.DataInvalid, fmt.Errorf("unknown event type %s", eventType))
+		}
+	}
+	return nil
+}
+
+type CreateEventSubscriptionReqV1 struct {
+	Name string `json:"name" form:"name" valid:"required" description:"订阅名称"`
+	URL  string `json:"url" form:"url" valid:"required" description:"接收事件的Webhook地址"`
+	// 为空时自动生成
+	Secret     string   `json:"secret" form:"secret" description:"签名密钥，为空时自动生成"`
+	EventTypes []string `json:"event_types" form:"event_types" description:"订阅的事件类型，为空时订阅全部事件"`
+	IsEnabled  *bool    `json:"is_enabled" form:"is_enabled" valid:"required" description:"是否启用"`
+}
+
+type CreateEventSubscriptionResV1 struct {
+	controller.BaseRes
+	Data CreateEventSubscriptionResDataV1 `json:"data"`
+}
+
+type CreateEventSubscriptionResDataV1 struct {
+	EventSubscriptionV1
+	// 签名密钥仅在创建时返回
+	Secret string `json:"secret"`
+}
+
+// CreateEventSubscriptionV1
+// @Summary 添加事件订阅
+// @Description create an event subscription of project, the secret is only returned in the response
+// @Accept json
+// @Id createEventSubscriptionV1
+// @Tags event_subscription
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param subscription body v1.CreateEventSubscriptionReqV1 true "create event subscription req"
+// @Success 200 {object} v1.CreateEventSubscriptionResV1
+// @router /v1/projects/{project_name}/event_subscriptions [post]
+func CreateEventSubscriptionV1(c echo.Context) error {
+	req := new(CreateEventSubscriptionReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetPorjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := checkEventSubscription(c.Request().Context(), req.URL, req.EventTypes); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret = utils.GenerateRandomString(16)
+	}
+	subscription := &model.EventSubscription{
+		ProjectId:    projectUid,
+		Name:         req.Name,
+		URL:          req.URL,
+		Secret:       secret,
+		IsEnabled:    *req.IsEnabled,
+		CreateUserId: controller.GetUserID(c),
+	}
+	subscription.SetEventTypes(req.EventTypes)
+	if err := model.GetStorage().Save(subscription); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &CreateEventSubscriptionResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: CreateEventSubscriptionResDataV1{
+			EventSubscriptionV1: convertEventSubscriptionToRes(subscription),
+			Secret:              secret,
+		},
+	})
+}
+
+type GetEventSubscriptionsResV1 struct {
+	controller.BaseRes
+	Data []EventSubscriptionV1 `json:"data"`
+}
+
+// GetEventSubscriptionsV1
+// @Summary 获取事件订阅列表
+// @Description get event subscriptions of project
+// @Id getEventSubscriptionsV1
+// @Tags event_subscription
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Success 200 {object} v1.GetEventSubscriptionsResV1
+// @router /v1/projects/{project_name}/event_subscriptions [get]
+func GetEventSubscriptionsV1(c echo.Context) error {
+	projectUid, err := dms.GetPorjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	subscriptions, err := model.GetStorage().GetEventSubscriptionsByProject(projectUid)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]EventSubscriptionV1, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		data = append(data, convertEventSubscriptionToRes(subscription))
+	}
+	return c.JSON(http.StatusOK, &GetEventSubscriptionsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type UpdateEventSubscriptionReqV1 struct {
+	Name       *string   `json:"name" form:"name" description:"订阅名称"`
+	URL        *string   `json:"url" form:"url" description:"接收事件的Webhook地址"`
+	Secret     *string   `json:"secret" form:"secret" description:"签名密钥"`
+	EventTypes *[]string `json:"event_types" form:"event_types" description:"订阅的事件类型，为空时订阅全部事件"`
+	IsEnabled  *bool     `json:"is_enabled" form:"is_enabled" description:"是否启用"`
+}
+
+// UpdateEventSubscriptionV1
+// @Summary 更新事件订阅
+// @Description update event subscription of project
+// @Accept json
+// @Id updateEventSubscriptionV1
+// @Tags event_subscription
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param subscription_id path string true "event subscription id"
+// @Param subscription body v1.UpdateEventSubscriptionReqV1 true "update event subscription req"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/event_subscriptions/{subscription_id}/ [patch]
+func UpdateEventSubscriptionV1(c echo.Context) error {
+	req := new(UpdateEventSubscriptionReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	subscription, err := getEventSubscriptionByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	if req.Name != nil {
+		subscription.Name = *req.Name
+	}
+	if req.URL != nil {
+		subscription.URL = *req.URL
+	}
+	if req.Secret != nil {
+		if *req.Secret == "" {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("secret should not be empty")))
+		}
+		subscription.Secret = *req.Secret
+	}
+	if req.EventTypes != nil {
+		subscription.SetEventTypes(*req.EventTypes)
+	}
+	if req.IsEnabled != nil {
+		subscription.IsEnabled = *req.IsEnabled
+	}
+	if err := checkEventSubscription(c.Request().Context(), subscription.URL, subscription.GetEventTypes()); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Save(subscription))
+}
+
+// DeleteEventSubscriptionV1
+// @Summary 删除事件订阅
+// @Description delete event subscription of project, the pending deliveries of it are given up
+// @Id deleteEventSubscriptionV1
+// @Tags event_subscription
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param subscription_id path string true "event subscription id"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/event_subscriptions/{subscription_id}/ [delete]
+func DeleteEventSubscriptionV1(c echo.Context) error {
+	subscription, err := getEventSubscriptionByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, model.GetStorage().Delete(subscription))
+}
+
+type GetEventDeliveriesReqV1 struct {
+	FilterStatus string `json:"filter_status" query:"filter_status" valid:"omitempty,oneof=pending succeeded failed"`
+	PageIndex    uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize     uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type EventDeliveryV1 struct {
+	Id             uint       `json:"id"`
+	EventId        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status" enums:"pending,succeeded,failed"`
+	Attempts       uint       `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body"`
+	Error          string     `json:"error"`
+	DurationMs     int64      `json:"duration_ms"`
+	RedeliveryOf   uint       `json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type GetEventDeliveriesResV1 struct {
+	controller.BaseRes
+	Data      []EventDeliveryV1 `json:"data"`
+	TotalNums uint64            `json:"total_nums"`
+}
+
+// GetEventDeliveriesV1
+// @Summary 获取事件订阅的投递记录
+// @Description get deliveries of event subscription
+// @Id getEventDeliveriesV1
+// @Tags event_subscription
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param subscription_id path string true "event subscription id"
+// @Param filter_status query string false "filter delivery status" Enums(pending,succeeded,failed)
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Success 200 {object} v1.GetEventDeliveriesResV1
+// @router /v1/projects/{project_name}/event_subscriptions/{subscription_id}/deliveries [get]
+func GetEventDeliveriesV1(c echo.Context) error {
+	req := new(GetEventDeliveriesReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	subscription, err := getEventSubscriptionByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	limit, offset := controller.GetLimitAndOffset(req.PageIndex, req.PageSize)
+	deliveries, count, err := model.GetStorage().GetEventDeliveriesBySubscription(subscription.ID, req.FilterStatus, limit, offset)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]EventDeliveryV1, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		data = append(data, EventDeliveryV1{
+			Id:             delivery.ID,
+			EventId:        delivery.EventId,
+			EventType:      delivery.EventType,
+			Payload:        delivery.Payload,
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			LastAttemptAt:  delivery.LastAttemptAt,
+			ResponseStatus: delivery.ResponseStatus,
+			ResponseBody:   delivery.ResponseBody,
+			Error:          delivery.Error,
+			DurationMs:     delivery.DurationMs,
+			RedeliveryOf:   delivery.RedeliveryOf,
+			CreatedAt:      delivery.CreatedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetEventDeliveriesResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: uint64(count),
+	})
+}
+
+type RedeliverEventResV1 struct {
+	controller.BaseRes
+	Data RedeliverEventResDataV1 `json:"data"`
+}
+
+type RedeliverEventResDataV1 struct {
+	DeliveryId uint `json:"delivery_id"`
+}
+
+// RedeliverEventV1
+// @Summary 重新投递事件
+// @Description redeliver the event of a delivery with the same payload, a new delivery is created
+// @Id redeliverEventV1
+// @Tags event_subscription
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param subscription_id path string true "event subscription id"
+// @Param delivery_id path string true "event delivery id"
+// @Success 200 {object} v1.RedeliverEventResV1
+// @router /v1/projects/{project_name}/event_subscriptions/{subscription_id}/deliveries/{delivery_id}/redeliver [post]
+func RedeliverEventV1(c echo.Context) error {
+	subscription, err := getEventSubscriptionByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	deliveryId, err := strconv.ParseUint(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("invalid delivery id: %v", err)))
+	}
+	s := model.GetStorage()
+	delivery, exist, err := s.GetEventDeliveryBySubscriptionAndId(subscription.ID, uint(deliveryId))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("event delivery is not exist")))
+	}
+
+	redelivery, err := eventbus.NewBus(s).Redeliver(delivery)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &RedeliverEventResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    RedeliverEventResDataV1{DeliveryId: redelivery.ID},
+	})
+}
+
+func getEventSubscriptionByParam(c echo.Context) (*model.EventSubscription, error) {
+	projectUid, err := dms.GetPorjectUIDByName(context.TODO(), c.Param("project_name"))
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(c.Param("subscription_id"), 10, 64)
+	if err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("invalid subscription id: %v", err))
+	}
+	subscription, exist, err := model.GetStorage().GetEventSubscriptionByProjectAndId(projectUid, uint(id))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("event subscription is not exist"))
+	}
+	return subscription, nil
+}
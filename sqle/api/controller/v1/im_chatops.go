@@ -0,0 +1,272 @@
+package v1
+
+import (
+	e "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/im"
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops"
+
+	"github.com/labstack/echo/v4"
+)
+
+type GetSlackConfigurationResV1 struct {
+	controller.BaseRes
+	Data SlackConfigurationV1 `json:"data"`
+}
+
+type SlackConfigurationV1 struct {
+	BaseUrl                string `json:"base_url"`
+	IsSlackApprovalEnabled bool   `json:"is_slack_approval_enabled"`
+}
+
+// GetSlackConfigurationV1
+// @Summary 获取Slack审批配置
+// @Description get slack approval configuration
+// @Id getSlackConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetSlackConfigurationResV1
+// @router /v1/configurations/slack [get]
+func GetSlackConfigurationV1(c echo.Context) error {
+	imConfig, _, err := model.GetStorage().GetImConfigByType(model.ImTypeSlack)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetSlackConfigurationResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: SlackConfigurationV1{
+			BaseUrl:                imConfig.BaseUrl,
+			IsSlackApprovalEnabled: imConfig.IsEnable,
+		},
+	})
+}
+
+type UpdateSlackConfigurationReqV1 struct {
+	BotToken               *string `json:"bot_token" form:"bot_token" description:"Slack应用的Bot Token"`
+	SigningSecret          *string `json:"signing_secret" form:"signing_secret" description:"Slack应用的Signing Secret，用于校验交互回调"`
+	BaseUrl                *string `json:"base_url" form:"base_url" description:"Slack API地址，为空时使用官方地址"`
+	IsSlackApprovalEnabled *bool   `json:"is_slack_approval_enabled" form:"is_slack_approval_enabled" validate:"required" description:"是否启用Slack审批"`
+}
+
+// UpdateSlackConfigurationV1
+// @Summary 添加或更新Slack审批配置
+// @Description update slack approval configuration
+// @Accept json
+// @Id updateSlackConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param param body v1.UpdateSlackConfigurationReqV1 true "update slack configuration req"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/configurations/slack [patch]
+func UpdateSlackConfigurationV1(c echo.Context) error {
+	req := new(UpdateSlackConfigurationReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	imConfig, _, err := s.GetImConfigByType(model.ImTypeSlack)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	imConfig.Type = model.ImTypeSlack
+	if req.BotToken != nil {
+		imConfig.AppSecret = *req.BotToken
+	}
+	if req.SigningSecret != nil {
+		imConfig.SigningSecret = *req.SigningSecret
+	}
+	if req.BaseUrl != nil {
+		imConfig.BaseUrl = *req.BaseUrl
+	}
+	imConfig.IsEnable = *req.IsSlackApprovalEnabled
+	if imConfig.IsEnable && (imConfig.AppSecret == "" || imConfig.SigningSecret == "") {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("bot token and signing secret are required to enable slack approval")))
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(imConfig))
+}
+
+type GetTeamsConfigurationResV1 struct {
+	controller.BaseRes
+	Data TeamsConfigurationV1 `json:"data"`
+}
+
+type TeamsConfigurationV1 struct {
+	AppId                  string `json:"app_id"`
+	TenantId               string `json:"tenant_id"`
+	BaseUrl                string `json:"base_url"`
+	IsTeamsApprovalEnabled bool   `json:"is_teams_approval_enabled"`
+}
+
+// GetTeamsConfigurationV1
+// @Summary 获取Microsoft Teams审批配置
+// @Description get microsoft teams approval configuration
+// @Id getTeamsConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetTeamsConfigurationResV1
+// @router /v1/configurations/teams [get]
+func GetTeamsConfigurationV1(c echo.Context) error {
+	imConfig, _, err := model.GetStorage().GetImConfigByType(model.ImTypeTeams)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetTeamsConfigurationResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: TeamsConfigurationV1{
+			AppId:                  imConfig.AppKey,
+			TenantId:               imConfig.TenantId,
+			BaseUrl:                imConfig.BaseUrl,
+			IsTeamsApprovalEnabled: imConfig.IsEnable,
+		},
+	})
+}
+
+type UpdateTeamsConfigurationReqV1 struct {
+	AppId                  *string `json:"app_id" form:"app_id" description:"Teams机器人的应用ID"`
+	AppPassword            *string `json:"app_password" form:"app_password" description:"Teams机器人的应用密码"`
+	TenantId               *string `json:"tenant_id" form:"tenant_id" description:"Azure AD租户ID"`
+	BaseUrl                *string `json:"base_url" form:"base_url" description:"Teams服务地址，为空时使用官方地址"`
+	IsTeamsApprovalEnabled *bool   `json:"is_teams_approval_enabled" form:"is_teams_approval_enabled" validate:"required" description:"是否启用Teams审批"`
+}
+
+// UpdateTeamsConfigurationV1
+// @Summary 添加或更新Microsoft Teams审批配置
+// @Description update microsoft teams approval configuration
+// @Accept json
+// @Id updateTeamsConfigurationV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param param body v1.UpdateTeamsConfigurationReqV1 true "update teams configuration req"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/configurations/teams [patch]
+func UpdateTeamsConfigurationV1(c echo.Context) error {
+	req := new(UpdateTeamsConfigurationReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	imConfig, _, err := s.GetImConfigByType(model.ImTypeTeams)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	imConfig.Type = model.ImTypeTeams
+	if req.AppId != nil {
+		imConfig.AppKey = *req.AppId
+	}
+	if req.AppPassword != nil {
+		imConfig.AppSecret = *req.AppPassword
+	}
+	if req.TenantId != nil {
+		imConfig.TenantId = *req.TenantId
+	}
+	if req.BaseUrl != nil {
+		imConfig.BaseUrl = *req.BaseUrl
+	}
+	imConfig.IsEnable = *req.IsTeamsApprovalEnabled
+	if imConfig.IsEnable && (imConfig.AppKey == "" || imConfig.AppSecret == "" || imConfig.TenantId == "") {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("app id, app password and tenant id are required to enable teams approval")))
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(imConfig))
+}
+
+type TestChatOpsConfigurationReqV1 struct {
+	Email string `json:"email" form:"email" valid:"required" description:"接收测试消息的用户邮箱"`
+}
+
+type TestChatOpsConfigResDataV1 struct {
+	IsMessageSentNormally bool   `json:"is_message_sent_normally"`
+	ErrorMessage          string `json:"error_message,omitempty"`
+}
+
+type TestChatOpsConfigResV1 struct {
+	controller.BaseRes
+	Data TestChatOpsConfigResDataV1 `json:"data"`
+}
+
+// TestSlackConfigV1
+// @Summary 测试Slack审批配置
+// @Description test slack approval configuration
+// @Accept json
+// @Id testSlackConfigV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param req body v1.TestChatOpsConfigurationReqV1 true "test slack configuration req"
+// @Success 200 {object} v1.TestChatOpsConfigResV1
+// @router /v1/configurations/slack/test [post]
+func TestSlackConfigV1(c echo.Context) error {
+	return testChatOpsConfig(c, model.ImTypeSlack)
+}
+
+// TestTeamsConfigV1
+// @Summary 测试Microsoft Teams审批配置
+// @Description test microsoft teams approval configuration
+// @Accept json
+// @Id testTeamsConfigV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param req body v1.TestChatOpsConfigurationReqV1 true "test teams configuration req"
+// @Success 200 {object} v1.TestChatOpsConfigResV1
+// @router /v1/configurations/teams/test [post]
+func TestTeamsConfigV1(c echo.Context) error {
+	return testChatOpsConfig(c, model.ImTypeTeams)
+}
+
+func testChatOpsConfig(c echo.Context, imType string) error {
+	req := new(TestChatOpsConfigurationReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	imConfig, exist, err := model.GetStorage().GetImConfigByType(imType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("%s configuration is not found", imType)))
+	}
+
+	data := TestChatOpsConfigResDataV1{IsMessageSentNormally: true}
+	if err := im.SendChatOpsTestMessage(c.Request().Context(), *imConfig, req.Email, controller.GetLanguage(c)); err != nil {
+		data = TestChatOpsConfigResDataV1{IsMessageSentNormally: false, ErrorMessage: err.Error()}
+	}
+	return c.JSON(http.StatusOK, &TestChatOpsConfigResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+// SlackCallbackV1
+// @Summary Slack审批消息的交互回调
+// @Description receive the interaction of slack approval message, the request is verified by the signing secret
+// @Accept x-www-form-urlencoded
+// @Id slackCallbackV1
+// @Tags workflow
+// @Success 200 {object} controller.BaseRes
+// @router /v1/im/slack/callback [post]
+func SlackCallbackV1(c echo.Context) error {
+	return chatOpsCallback(c, model.ImTypeSlack)
+}
+
+// TeamsCallbackV1
+// @Summary Microsoft Teams审批消息的交互回调
+// @Description receive the interaction of teams approval message, the request is verified by the token of Bot Framework
+// @Accept json
+// @Id teamsCallbackV1
+// @Tags workflow
+// @Success 200 {object} controller.BaseRes
+// @router /v1/im/teams/callback [post]
+func TeamsCallbackV1(c echo.Context) error {
+	return chatOpsCallback(c, model.ImTypeTeams)
+}
+
+func chatOpsCallback(c echo.Context, imType string) error {
+	err := im.HandleChatOpsCallback(imType, c.Request())
+	if e.Is(err, chatops.ErrInvalidSignature) {
+		return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+	}
+	return controller.JSONBaseErrorReq(c, err)
+}
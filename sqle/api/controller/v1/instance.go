@@ -465,7 +465,7 @@ func GetInstanceRules(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, &GetRulesResV1{
 		BaseRes: controller.NewBaseReq(nil),
-		Data:    convertRulesToRes(rules),
+		Data:    convertRulesToRes(rules, controller.GetLanguage(c)),
 	})
 }
 
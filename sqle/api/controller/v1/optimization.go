@@ -107,7 +107,7 @@ type GetOptimizationRecordsReq struct {
 	FuzzySearch          string `json:"fuzzy_search" query:"fuzzy_search"`
 	FilterInstanceName   string `json:"filter_instance_name" query:"filter_instance_name"`
 	FilterCreateTimeFrom string `json:"filter_create_time_from" query:"filter_create_time_from"`
-	FilterCreateTimeTo   string `json:"filter_create_time_to" query:"filter_create_time_from"`
+	FilterCreateTimeTo   string `json:"filter_create_time_to" query:"filter_create_time_to"`
 	PageIndex            uint32 `json:"page_index" query:"page_index" valid:"required"`
 	PageSize             uint32 `json:"page_size" query:"page_size" valid:"required"`
 }
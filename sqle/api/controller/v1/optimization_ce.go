@@ -4,36 +4,356 @@
 package v1
 
 import (
+	"context"
+	"encoding/json"
 	e "errors"
+	"fmt"
+	"net/http"
+	"strconv"
 
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/common"
+	"github.com/actiontech/sqle/sqle/dms"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+	"github.com/actiontech/sqle/sqle/utils"
 	"github.com/labstack/echo/v4"
 )
 
 func sqlOptimizate(c echo.Context) error {
-	return errors.New(errors.SQLOptimizationCommunityNotSupported, e.New("sql optimization community not supported"))
+	req := new(OptimizeSQLReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetPorjectUIDByName(c.Request().Context(), c.Param("project_name"), true)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	user, err := controller.GetCurrentUser(c, dms.GetUser)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	var instance *model.Instance
+	if req.InstanceName != nil && *req.InstanceName != "" {
+		inst, exist, err := dms.GetInstanceInProjectByName(c.Request().Context(), projectUid, *req.InstanceName)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			return controller.JSONBaseErrorReq(c, ErrInstanceNoAccess)
+		}
+		can, err := CheckCurrentUserCanAccessInstances(c.Request().Context(), projectUid, user.GetIDStr(), []*model.Instance{inst})
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !can {
+			return controller.JSONBaseErrorReq(c, ErrInstanceNoAccess)
+		}
+		instance = inst
+		req.DBType = inst.DbType
+	}
+	if req.DBType == "" {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, e.New("db_type and instance_name can't both be empty")))
+	}
+	if req.DBType != driverV2.DriverTypeMySQL {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("sql optimization of db type %v is not supported", req.DBType)))
+	}
+
+	sqls, err := getSQLsForOptimization(c, req)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	optimizationId, err := utils.GenUid()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, fmt.Errorf("generate optimization id failed: %v", err))
+	}
+	record := &model.SQLOptimizationRecord{
+		OptimizationId:   optimizationId,
+		ProjectId:        projectUid,
+		OptimizationName: req.OptimizationName,
+		DBType:           req.DBType,
+		CreatorId:        user.GetIDStr(),
+	}
+	if instance != nil {
+		record.InstanceId = instance.ID
+		record.InstanceName = instance.Name
+		if req.SchemaName != nil {
+			record.SchemaName = *req.SchemaName
+		}
+	}
+	if err := server.OptimizeSQLs(log.NewEntry(), record, instance, sqls); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := model.GetStorage().CreateSQLOptimizationRecord(record); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &OptimizeSQLRes{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &OptimizeSQLResData{
+			OptimizationRecordId: record.OptimizationId,
+		},
+	})
+}
+
+// getSQLsForOptimization splits the SQLs from form data, files or git repository.
+func getSQLsForOptimization(c echo.Context, req *OptimizeSQLReq) ([]string, error) {
+	sqls := getSQLFromFileResp{}
+	if req.SQLContent != "" {
+		sqls = getSQLFromFileResp{
+			SourceType:       model.TaskSQLSourceFromFormData,
+			SQLsFromFormData: req.SQLContent,
+		}
+	} else {
+		var err error
+		sqls, err = getSQLFromFile(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plugin, err := common.NewDriverManagerWithoutCfg(log.NewEntry(), req.DBType)
+	if err != nil {
+		return nil, fmt.Errorf("open plugin failed: %v", err)
+	}
+	defer plugin.Close(context.TODO())
+
+	task := &model.Task{}
+	if err := addSQLsFromFileToTasks(sqls, task, plugin); err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(task.ExecuteSQLs))
+	for _, sql := range task.ExecuteSQLs {
+		result = append(result, sql.Content)
+	}
+	if len(result) == 0 {
+		return nil, errors.New(errors.DataInvalid, e.New("input sql is empty"))
+	}
+	return result, nil
+}
+
+func getSQLOptimizationRecordFromContext(c echo.Context) (*model.SQLOptimizationRecord, error) {
+	projectUid, err := dms.GetPorjectUIDByName(c.Request().Context(), c.Param("project_name"), false)
+	if err != nil {
+		return nil, err
+	}
+	record, exist, err := model.GetStorage().GetSQLOptimizationRecord(projectUid, c.Param("optimization_record_id"))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.New(errors.DataNotExist, e.New("sql optimization record is not exist"))
+	}
+	return record, nil
 }
 
 func getOptimizationRecord(c echo.Context) error {
-	return errors.New(errors.SQLOptimizationCommunityNotSupported, e.New("sql optimization community not supported"))
+	record, err := getSQLOptimizationRecordFromContext(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetOptimizationRecordRes{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &OptimizationDetail{
+			OptimizationID:   record.OptimizationId,
+			OptimizationName: record.OptimizationName,
+			InstanceName:     record.InstanceName,
+			DBType:           record.DBType,
+			CreatedTime:      record.CreatedAt,
+			CreatedUser:      dms.GetUserNameWithDelTag(record.CreatorId),
+			Optimizationsummary: Optimizationsummary{
+				NumberOfQuery:          record.NumberOfQuery,
+				NumberOfSyntaxError:    record.NumberOfSyntaxError,
+				NumberOfRewrite:        record.NumberOfRewrite,
+				NumberOfRewrittenQuery: record.NumberOfRewrittenQuery,
+				PerformanceGain:        record.PerformanceGain,
+			},
+			IndexRecommendations: []string{},
+			Status:               record.Status,
+		},
+	})
 }
 
 func getOptimizationRecords(c echo.Context) error {
-	return errors.New(errors.SQLOptimizationCommunityNotSupported, e.New("sql optimization community not supported"))
+	req := new(GetOptimizationRecordsReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetPorjectUIDByName(c.Request().Context(), c.Param("project_name"), false)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	limit, offset := controller.GetLimitAndOffset(req.PageIndex, req.PageSize)
+	records, total, err := model.GetStorage().GetSQLOptimizationRecords(model.SQLOptimizationRecordFilter{
+		ProjectId:      projectUid,
+		FuzzySearch:    req.FuzzySearch,
+		InstanceName:   req.FilterInstanceName,
+		CreateTimeFrom: req.FilterCreateTimeFrom,
+		CreateTimeTo:   req.FilterCreateTimeTo,
+	}, int(limit), int(offset))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]OptimizationRecord, 0, len(records))
+	for _, record := range records {
+		data = append(data, OptimizationRecord{
+			OptimizationID:   record.OptimizationId,
+			OptimizationName: record.OptimizationName,
+			InstanceName:     record.InstanceName,
+			DBType:           record.DBType,
+			PerformanceGain:  record.PerformanceGain,
+			CreatedTime:      record.CreatedAt,
+			CreatedUser:      dms.GetUserNameWithDelTag(record.CreatorId),
+			Status:           record.Status,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetOptimizationRecordsRes{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: uint64(total),
+	})
 }
 
 func getOptimizationSQL(c echo.Context) error {
-	return errors.New(errors.SQLOptimizationCommunityNotSupported, e.New("sql optimization community not supported"))
+	record, err := getSQLOptimizationRecordFromContext(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	number, err := strconv.ParseUint(c.Param("number"), 10, 64)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("invalid sql number: %v", err)))
+	}
+	sql, exist, err := model.GetStorage().GetSQLOptimizationRecordSQL(record.ID, number)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, e.New("sql is not exist")))
+	}
+
+	triggeredRules := []server.SQLOptimizationTriggeredRule{}
+	if len(sql.TriggeredRules) > 0 {
+		if err := json.Unmarshal(sql.TriggeredRules, &triggeredRules); err != nil {
+			return controller.JSONBaseErrorReq(c, fmt.Errorf("unmarshal triggered rules failed: %v", err))
+		}
+	}
+	rules := make([]RewriteRule, 0, len(triggeredRules))
+	for _, rule := range triggeredRules {
+		rules = append(rules, RewriteRule{
+			RuleCode:            rule.RuleCode,
+			RuleName:            rule.RuleName,
+			Message:             rule.Message,
+			RewrittenQueriesStr: rule.RewrittenSQL,
+			ViolatedQueriesStr:  sql.OriginalSQL,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetOptimizationSQLRes{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &OptimizationSQLDetail{
+			OriginalSQL:          sql.OriginalSQL,
+			OptimizedSQL:         sql.OptimizedSQL,
+			TriggeredRule:        rules,
+			IndexRecommendations: []string{},
+			ExplainValidationDetails: ExplainValidationDetail{
+				BeforeCost:        sql.BeforeCost,
+				AfterCost:         sql.AfterCost,
+				BeforePlan:        sql.BeforePlan,
+				AfterPlan:         sql.AfterPlan,
+				PerformImprovePer: sql.PerformanceImprove,
+			},
+		},
+	})
 }
 
 func getOptimizationSQLs(c echo.Context) error {
-	return errors.New(errors.SQLOptimizationCommunityNotSupported, e.New("sql optimization community not supported"))
+	req := new(GetOptimizationSQLsReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	record, err := getSQLOptimizationRecordFromContext(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	limit, offset := controller.GetLimitAndOffset(req.PageIndex, req.PageSize)
+	sqls, total, err := model.GetStorage().GetSQLOptimizationRecordSQLs(record.ID, int(limit), int(offset))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]OptimizationSQL, 0, len(sqls))
+	for _, sql := range sqls {
+		optimizationSQL := OptimizationSQL{
+			Number:          sql.Number,
+			OriginalSQL:     sql.OriginalSQL,
+			NumberOfRewrite: sql.NumberOfRewrite,
+			Performance:     sql.PerformanceImprove,
+		}
+		if sql.SyntaxError {
+			optimizationSQL.NumberOfSyntaxError = 1
+		}
+		data = append(data, optimizationSQL)
+	}
+	return c.JSON(http.StatusOK, &GetOptimizationSQLsRes{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: uint64(total),
+	})
 }
 
 func getDBPerformanceImproveOverview(c echo.Context) error {
-	return errors.New(errors.SQLOptimizationCommunityNotSupported, e.New("sql optimization community not supported"))
+	projectUid, err := dms.GetPorjectUIDByName(c.Request().Context(), c.Param("project_name"), false)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	improves, err := model.GetStorage().GetAvgPerformanceImproveByInstance(projectUid)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]DBPerformanceImproveOverview, 0, len(improves))
+	for _, improve := range improves {
+		data = append(data, DBPerformanceImproveOverview{
+			InstanceName:          improve.InstanceName,
+			AvgPerformanceImprove: improve.AvgPerformanceImprove,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetDBPerformanceImproveOverviewResp{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
 
 func getOptimizationRecordOverview(c echo.Context) error {
-	return errors.New(errors.SQLOptimizationCommunityNotSupported, e.New("sql optimization community not supported"))
+	req := new(GetOptimizationOverviewReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectUid, err := dms.GetPorjectUIDByName(c.Request().Context(), c.Param("project_name"), false)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	counts, err := model.GetStorage().GetSQLOptimizationRecordCountsByDay(projectUid, req.FilterCreateTimeFrom, req.FilterCreateTimeTo)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]OptimizationRecordOverview, 0, len(counts))
+	for _, count := range counts {
+		data = append(data, OptimizationRecordOverview{
+			RecordNumber: count.Count,
+			Time:         count.Date,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetOptimizationOverviewResp{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
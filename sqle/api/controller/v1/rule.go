@@ -12,7 +12,9 @@ import (
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/dms"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/eventbus"
 
 	"github.com/labstack/echo/v4"
 )
@@ -95,17 +97,26 @@ func checkAndGenerateCustomRules(rulesReq []RuleReqV1, template *model.RuleTempl
 	for _, r := range rulesReq {
 		ruleNames = append(ruleNames, r.Name)
 	}
-	_, err = s.GetAndCheckCustomRuleExist(ruleNames)
+	rules, err := s.GetAndCheckCustomRuleExist(ruleNames)
 	if err != nil {
 		return nil, err
 	}
 
 	templateCustomRules := make([]model.RuleTemplateCustomRule, 0, len(rulesReq))
 	for _, r := range rulesReq {
+		params := rules[r.Name].Params
+		for _, p := range r.Params {
+			// set and valid param.
+			err := params.SetParamValue(p.Key, p.Value)
+			if err != nil {
+				return nil, fmt.Errorf("set rule %s param error: %s", r.Name, err)
+			}
+		}
 		templateCustomRules = append(templateCustomRules, model.NewRuleTemplateCustomRule(template, &model.CustomRule{
 			RuleId: r.Name,
 			Level:  r.Level,
 			DBType: template.DBType,
+			Params: params,
 		}))
 	}
 	return templateCustomRules, nil
@@ -194,6 +205,7 @@ func CreateRuleTemplate(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
+	eventbus.PublishRuleTemplateChanged(string(ruleTemplate.ProjectId), ruleTemplate, eventbus.RuleTemplateActionCreated, controller.GetUserID(c))
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
@@ -253,6 +265,7 @@ func UpdateRuleTemplate(c echo.Context) error {
 		}
 	}
 
+	eventbus.PublishRuleTemplateChanged(string(template.ProjectId), template, eventbus.RuleTemplateActionUpdated, controller.GetUserID(c))
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
@@ -272,14 +285,14 @@ type RuleTemplateDetailResV1 struct {
 	RuleList []RuleResV1 `json:"rule_list,omitempty"`
 }
 
-func convertRuleTemplateToRes(template *model.RuleTemplate) *RuleTemplateDetailResV1 {
+func convertRuleTemplateToRes(template *model.RuleTemplate, lang string) *RuleTemplateDetailResV1 {
 
 	ruleList := make([]RuleResV1, 0, len(template.RuleList))
 	for _, r := range template.RuleList {
 		if r.Rule == nil {
 			continue
 		}
-		ruleList = append(ruleList, convertRuleToRes(r.GetRule()))
+		ruleList = append(ruleList, convertRuleToRes(r.GetRule(), lang))
 	}
 	for _, r := range template.CustomRuleList {
 		if r.CustomRule == nil {
@@ -322,7 +335,7 @@ func GetRuleTemplate(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, &GetRuleTemplateResV1{
 		BaseRes: controller.NewBaseReq(nil),
-		Data:    convertRuleTemplateToRes(template),
+		Data:    convertRuleTemplateToRes(template, controller.GetLanguage(c)),
 	})
 }
 
@@ -376,6 +389,7 @@ func DeleteRuleTemplate(c echo.Context) error {
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
+	eventbus.PublishRuleTemplateChanged(string(template.ProjectId), template, eventbus.RuleTemplateActionDeleted, controller.GetUserID(c))
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
@@ -484,13 +498,13 @@ type RuleParamResV1 struct {
 	Type  string `json:"type" form:"type" enums:"string,int,bool"`
 }
 
-func convertRuleToRes(rule *model.Rule) RuleResV1 {
+func convertRuleToRes(rule *model.Rule, lang string) RuleResV1 {
 	ruleRes := RuleResV1{
 		Name:            rule.Name,
-		Desc:            rule.Desc,
-		Annotation:      rule.Annotation,
+		Desc:            localizeRuleField(lang, rule, locale.RuleFieldDesc, rule.Desc),
+		Annotation:      localizeRuleField(lang, rule, locale.RuleFieldAnnotation, rule.Annotation),
 		Level:           rule.Level,
-		Typ:             rule.Typ,
+		Typ:             localizeRuleCategory(lang, rule),
 		DBType:          rule.DBType,
 		HasAuditPower:   rule.HasAuditPower,
 		HasRewritePower: rule.HasRewritePower,
@@ -500,7 +514,7 @@ func convertRuleToRes(rule *model.Rule) RuleResV1 {
 		for _, p := range rule.Params {
 			paramRes := RuleParamResV1{
 				Key:   p.Key,
-				Desc:  p.Desc,
+				Desc:  localizeRuleParam(lang, rule, p.Key, p.Desc),
 				Type:  string(p.Type),
 				Value: p.Value,
 			}
@@ -511,6 +525,34 @@ func convertRuleToRes(rule *model.Rule) RuleResV1 {
 	return ruleRes
 }
 
+// localizeRuleField returns the field of rule in the language, the rule stored in
+// database is in locale.SourceLang.
+func localizeRuleField(lang string, rule *model.Rule, field, text string) string {
+	if lang == locale.SourceLang {
+		return text
+	}
+	return locale.Localize(lang, locale.RuleMessageId(rule.DBType, rule.Name, field), text)
+}
+
+func localizeRuleParam(lang string, rule *model.Rule, key, desc string) string {
+	if lang == locale.SourceLang {
+		return desc
+	}
+	return locale.Localize(lang, locale.RuleParamMessageId(rule.DBType, rule.Name, key), desc)
+}
+
+// localizeRuleCategory translates the common categories such as "DML规范", or
+// takes the category given by the plugin.
+func localizeRuleCategory(lang string, rule *model.Rule) string {
+	if lang == locale.SourceLang {
+		return rule.Typ
+	}
+	if category := locale.Translate(lang, rule.Typ); category != rule.Typ {
+		return category
+	}
+	return localizeRuleField(lang, rule, locale.RuleFieldCategory, rule.Typ)
+}
+
 func convertCustomRuleToRuleResV1(rule *model.CustomRule) RuleResV1 {
 	ruleRes := RuleResV1{
 		Name:            rule.RuleId,
@@ -523,15 +565,27 @@ func convertCustomRuleToRuleResV1(rule *model.CustomRule) RuleResV1 {
 		HasAuditPower:   true,
 		HasRewritePower: false,
 	}
+	if len(rule.Params) > 0 {
+		paramsRes := make([]RuleParamResV1, 0, len(rule.Params))
+		for _, p := range rule.Params {
+			paramsRes = append(paramsRes, RuleParamResV1{
+				Key:   p.Key,
+				Desc:  p.Desc,
+				Type:  string(p.Type),
+				Value: p.Value,
+			})
+		}
+		ruleRes.Params = paramsRes
+	}
 	return ruleRes
 }
 
-func convertRulesToRes(rules interface{}) []RuleResV1 {
+func convertRulesToRes(rules interface{}, lang string) []RuleResV1 {
 	rulesRes := []RuleResV1{}
 	switch ruleSlice := rules.(type) {
 	case []*model.Rule:
 		for _, rule := range ruleSlice {
-			rulesRes = append(rulesRes, convertRuleToRes(rule))
+			rulesRes = append(rulesRes, convertRuleToRes(rule, lang))
 		}
 	case []*model.CustomRule:
 		for _, rule := range ruleSlice {
@@ -580,8 +634,8 @@ func GetRules(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
-	ruleRes := convertRulesToRes(rules)
-	customRuleRes := convertRulesToRes(customRules)
+	ruleRes := convertRulesToRes(rules, controller.GetLanguage(c))
+	customRuleRes := convertRulesToRes(customRules, controller.GetLanguage(c))
 	ruleRes = append(ruleRes, customRuleRes...)
 	return c.JSON(http.StatusOK, &GetRulesResV1{
 		BaseRes: controller.NewBaseReq(nil),
@@ -701,6 +755,7 @@ func CloneRuleTemplate(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
+	eventbus.PublishRuleTemplateChanged(string(ruleTemplate.ProjectId), ruleTemplate, eventbus.RuleTemplateActionCreated, controller.GetUserID(c))
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
@@ -804,6 +859,8 @@ func CreateProjectRuleTemplate(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
+	eventbus.PublishRuleTemplateChanged(string(ruleTemplate.ProjectId), ruleTemplate, eventbus.RuleTemplateActionCreated, controller.GetUserID(c))
+
 	// TODO SQLE会移除instance参数
 	// err = s.UpdateRuleTemplateInstances(ruleTemplate, instances...)
 	// if err != nil {
@@ -878,6 +935,7 @@ func UpdateProjectRuleTemplate(c echo.Context) error {
 		}
 	}
 
+	eventbus.PublishRuleTemplateChanged(string(template.ProjectId), template, eventbus.RuleTemplateActionUpdated, controller.GetUserID(c))
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
@@ -930,17 +988,17 @@ func GetProjectRuleTemplate(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, &GetProjectRuleTemplateResV1{
 		BaseRes: controller.NewBaseReq(nil),
-		Data:    convertProjectRuleTemplateToRes(template),
+		Data:    convertProjectRuleTemplateToRes(template, controller.GetLanguage(c)),
 	})
 }
 
-func convertProjectRuleTemplateToRes(template *model.RuleTemplate) *RuleProjectTemplateDetailResV1 {
+func convertProjectRuleTemplateToRes(template *model.RuleTemplate, lang string) *RuleProjectTemplateDetailResV1 {
 	ruleList := make([]RuleResV1, 0, len(template.RuleList))
 	for _, r := range template.RuleList {
 		if r.Rule == nil {
 			continue
 		}
-		ruleList = append(ruleList, convertRuleToRes(r.GetRule()))
+		ruleList = append(ruleList, convertRuleToRes(r.GetRule(), lang))
 	}
 	for _, r := range template.CustomRuleList {
 		if r.CustomRule == nil {
@@ -1018,6 +1076,7 @@ func DeleteProjectRuleTemplate(c echo.Context) error {
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
+	eventbus.PublishRuleTemplateChanged(string(template.ProjectId), template, eventbus.RuleTemplateActionDeleted, controller.GetUserID(c))
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
@@ -1167,6 +1226,7 @@ func CloneProjectRuleTemplate(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
+	eventbus.PublishRuleTemplateChanged(string(ruleTemplate.ProjectId), ruleTemplate, eventbus.RuleTemplateActionCreated, controller.GetUserID(c))
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
@@ -1342,13 +1402,28 @@ func exportRuleTemplateFile(c echo.Context, projectID string, ruleTemplateName s
 }
 
 type CustomRuleResV1 struct {
-	RuleId     string `json:"rule_id"`
-	Desc       string `json:"desc" example:"this is test rule"`
-	Annotation string `json:"annotation" example:"this is test rule"`
-	DBType     string `json:"db_type" example:"MySQL"`
-	Level      string `json:"level" example:"notice" enums:"normal,notice,warn,error"`
-	Type       string `json:"type" example:"DDL规则"`
-	RuleScript string `json:"rule_script,omitempty"`
+	RuleId     string                 `json:"rule_id"`
+	Desc       string                 `json:"desc" example:"this is test rule"`
+	Annotation string                 `json:"annotation" example:"this is test rule"`
+	DBType     string                 `json:"db_type" example:"MySQL"`
+	Level      string                 `json:"level" example:"notice" enums:"normal,notice,warn,error"`
+	Type       string                 `json:"type" example:"DDL规则"`
+	RuleScript string                 `json:"rule_script,omitempty"`
+	ScriptType string                 `json:"script_type" enums:"regular,cel"`
+	Params     []RuleParamResV1       `json:"params,omitempty"`
+	TestCases  []CustomRuleTestCaseV1 `json:"test_cases,omitempty"`
+}
+
+type CustomRuleParamReqV1 struct {
+	Key   string `json:"key" form:"key" valid:"required"`
+	Value string `json:"value" form:"value"`
+	Desc  string `json:"desc" form:"desc"`
+	Type  string `json:"type" form:"type" valid:"required,oneof=string int bool float64" enums:"string,int,bool,float64"`
+}
+
+type CustomRuleTestCaseV1 struct {
+	SQL       string `json:"sql" form:"sql" valid:"required"`
+	ExpectHit bool   `json:"expect_hit" form:"expect_hit"`
 }
 
 type GetCustomRulesResV1 struct {
@@ -1393,6 +1468,11 @@ type CreateCustomRuleReqV1 struct {
 	Level      string `json:"level" form:"level" example:"notice" valid:"required" enums:"normal,notice,warn,error"`
 	Type       string `json:"type" form:"type" example:"DDL规则" valid:"required"`
 	RuleScript string `json:"rule_script" form:"rule_script" valid:"required"`
+	// regular: 正则表达式匹配 SQL; cel: CEL 表达式, 仅支持 MySQL
+	ScriptType string                 `json:"script_type" form:"script_type" valid:"omitempty,oneof=regular cel" enums:"regular,cel"`
+	Params     []CustomRuleParamReqV1 `json:"params" form:"params" valid:"dive,required"`
+	// 保存规则前会校验所有测试用例
+	TestCases []CustomRuleTestCaseV1 `json:"test_cases" form:"test_cases" valid:"dive,required"`
 }
 
 // @Summary 添加自定义规则
@@ -1408,11 +1488,14 @@ func CreateCustomRule(c echo.Context) error {
 }
 
 type UpdateCustomRuleReqV1 struct {
-	Desc       *string `json:"desc" form:"desc" example:"this is test rule"`
-	Annotation *string `json:"annotation" form:"annotation" example:"this is test rule"`
-	Level      *string `json:"level" form:"level" example:"notice" enums:"normal,notice,warn,error"`
-	Type       *string `json:"type" form:"type" example:"DDL规则"`
-	RuleScript *string `json:"rule_script" form:"rule_script"`
+	Desc       *string                 `json:"desc" form:"desc" example:"this is test rule"`
+	Annotation *string                 `json:"annotation" form:"annotation" example:"this is test rule"`
+	Level      *string                 `json:"level" form:"level" example:"notice" enums:"normal,notice,warn,error"`
+	Type       *string                 `json:"type" form:"type" example:"DDL规则"`
+	RuleScript *string                 `json:"rule_script" form:"rule_script"`
+	ScriptType *string                 `json:"script_type" form:"script_type" valid:"omitempty,oneof=regular cel" enums:"regular,cel"`
+	Params     *[]CustomRuleParamReqV1 `json:"params" form:"params" valid:"omitempty,dive,required"`
+	TestCases  *[]CustomRuleTestCaseV1 `json:"test_cases" form:"test_cases" valid:"omitempty,dive,required"`
 }
 
 // @Summary 更新自定义规则
@@ -1428,6 +1511,56 @@ func UpdateCustomRule(c echo.Context) error {
 	return updateCustomRule(c)
 }
 
+type DryRunCustomRuleReqV1 struct {
+	DBType     string                 `json:"db_type" form:"db_type" example:"MySQL" valid:"required"`
+	Desc       string                 `json:"desc" form:"desc" example:"this is test rule"`
+	RuleScript string                 `json:"rule_script" form:"rule_script" valid:"required"`
+	ScriptType string                 `json:"script_type" form:"script_type" valid:"omitempty,oneof=regular cel" enums:"regular,cel"`
+	Params     []CustomRuleParamReqV1 `json:"params" form:"params" valid:"dive,required"`
+	// 样例 SQL, 可以包含多条语句
+	SQL       string                 `json:"sql" form:"sql"`
+	TestCases []CustomRuleTestCaseV1 `json:"test_cases" form:"test_cases" valid:"dive,required"`
+}
+
+type DryRunCustomRuleResV1 struct {
+	controller.BaseRes
+	Data *DryRunCustomRuleResDataV1 `json:"data"`
+}
+
+type DryRunCustomRuleResDataV1 struct {
+	SQLResults      []*CustomRuleSQLResultV1      `json:"sql_results"`
+	TestCaseResults []*CustomRuleTestCaseResultV1 `json:"test_case_results"`
+}
+
+type CustomRuleSQLResultV1 struct {
+	SQL     string `json:"sql"`
+	Hit     bool   `json:"hit"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type CustomRuleTestCaseResultV1 struct {
+	SQL       string `json:"sql"`
+	ExpectHit bool   `json:"expect_hit"`
+	Hit       bool   `json:"hit"`
+	Passed    bool   `json:"passed"`
+	Error     string `json:"error,omitempty"`
+}
+
+// @Summary 试运行自定义规则
+// @Description dry run custom rule against the sample SQL and test cases offline, the table metadata is only available for the tables created by the former statements, and the current schema is "dry_run"
+// @Id dryRunCustomRuleV1
+// @Tags rule_template
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param instance body v1.DryRunCustomRuleReqV1 true "dry run custom rule"
+// @Success 200 {object} v1.DryRunCustomRuleResV1
+// @router /v1/custom_rules/dry_run [post]
+func DryRunCustomRule(c echo.Context) error {
+	return dryRunCustomRule(c)
+}
+
 type GetCustomRuleResV1 struct {
 	controller.BaseRes
 	Data CustomRuleResV1 `json:"data"`
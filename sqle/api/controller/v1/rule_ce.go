@@ -5,36 +5,303 @@ package v1
 
 import (
 	e "errors"
+	"fmt"
+	"net/http"
 
+	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/server"
+	"github.com/actiontech/sqle/sqle/utils"
+
 	"github.com/labstack/echo/v4"
 )
 
-var errCommunityEditionNotSupportCustomRule = errors.New(errors.CustomRuleEditionNotSupported, e.New("custom rule community not supported"))
 var errCommunityEditionNotSupportRuleKnowledge = errors.New(errors.CustomRuleEditionNotSupported, e.New("community do not support rule knowledge"))
 
+var errCustomRuleNotExist = errors.New(errors.DataNotExist, e.New("custom rule is not exist"))
+
+func convertCustomRuleToCustomRuleResV1(rule *model.CustomRule, withScript bool) CustomRuleResV1 {
+	res := CustomRuleResV1{
+		RuleId:     rule.RuleId,
+		Desc:       rule.Desc,
+		Annotation: rule.Annotation,
+		DBType:     rule.DBType,
+		Level:      rule.Level,
+		Type:       rule.Typ,
+		ScriptType: rule.ScriptType,
+	}
+	if !withScript {
+		return res
+	}
+	res.RuleScript = rule.RuleScript
+	for _, p := range rule.Params {
+		res.Params = append(res.Params, RuleParamResV1{
+			Key:   p.Key,
+			Value: p.Value,
+			Desc:  p.Desc,
+			Type:  string(p.Type),
+		})
+	}
+	for _, testCase := range rule.TestCases {
+		res.TestCases = append(res.TestCases, CustomRuleTestCaseV1{
+			SQL:       testCase.SQL,
+			ExpectHit: testCase.ExpectHit,
+		})
+	}
+	return res
+}
+
+func convertCustomRuleParams(ps []CustomRuleParamReqV1) params.Params {
+	res := make(params.Params, 0, len(ps))
+	for _, p := range ps {
+		res = append(res, &params.Param{
+			Key:   p.Key,
+			Value: p.Value,
+			Desc:  p.Desc,
+			Type:  params.ParamType(p.Type),
+		})
+	}
+	return res
+}
+
+func convertCustomRuleTestCases(testCases []CustomRuleTestCaseV1) model.RuleTestCases {
+	res := make(model.RuleTestCases, 0, len(testCases))
+	for _, testCase := range testCases {
+		res = append(res, model.RuleTestCase{
+			SQL:       testCase.SQL,
+			ExpectHit: testCase.ExpectHit,
+		})
+	}
+	return res
+}
+
 func getCustomRules(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	req := new(GetCustomRulesReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	rules, err := model.GetStorage().GetCustomRulesByDBTypeAndFuzzyDesc(
+		"rule_id, `desc`, annotation, db_type, level, type, script_type", req.FilterDBType, req.FilterDesc)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]CustomRuleResV1, 0, len(rules))
+	for _, rule := range rules {
+		data = append(data, convertCustomRuleToCustomRuleResV1(rule, false))
+	}
+	return c.JSON(http.StatusOK, &GetCustomRulesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
 
 func deleteCustomRule(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	s := model.GetStorage()
+	ruleId := c.Param("rule_id")
+	_, exist, err := s.GetCustomRuleByRuleId(ruleId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errCustomRuleNotExist)
+	}
+	return controller.JSONBaseErrorReq(c, s.DeleteCustomRule(ruleId))
 }
 
 func createCustomRule(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	req := new(CreateCustomRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	_, exist, err := s.GetCustomRulesByDescAndDBType(req.Desc, req.DBType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataExist, fmt.Errorf("custom rule with the same desc is exist")))
+	}
+
+	uid, err := utils.GenUid()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rule := &model.CustomRule{
+		RuleId:     fmt.Sprintf("rule_id_%s", uid),
+		Desc:       req.Desc,
+		Annotation: req.Annotation,
+		DBType:     req.DBType,
+		Level:      req.Level,
+		Typ:        req.Type,
+		RuleScript: req.RuleScript,
+		ScriptType: req.ScriptType,
+		Params:     convertCustomRuleParams(req.Params),
+		TestCases:  convertCustomRuleTestCases(req.TestCases),
+	}
+	if rule.ScriptType == "" {
+		rule.ScriptType = model.CustomRuleScriptTypeRegular
+	}
+	if err := server.ValidateCustomRule(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(rule))
 }
 
 func updateCustomRule(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	req := new(UpdateCustomRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	rule, exist, err := s.GetCustomRuleByRuleId(c.Param("rule_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errCustomRuleNotExist)
+	}
+
+	if req.Desc != nil && *req.Desc != rule.Desc {
+		_, exist, err := s.GetCustomRulesByDescAndDBType(*req.Desc, rule.DBType)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if exist {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataExist, fmt.Errorf("custom rule with the same desc is exist")))
+		}
+		rule.Desc = *req.Desc
+	}
+	if req.Annotation != nil {
+		rule.Annotation = *req.Annotation
+	}
+	if req.Level != nil {
+		rule.Level = *req.Level
+	}
+	if req.Type != nil {
+		rule.Typ = *req.Type
+	}
+	if req.RuleScript != nil {
+		rule.RuleScript = *req.RuleScript
+	}
+	if req.ScriptType != nil {
+		rule.ScriptType = *req.ScriptType
+	}
+	if req.Params != nil {
+		rule.Params = convertCustomRuleParams(*req.Params)
+	}
+	if req.TestCases != nil {
+		rule.TestCases = convertCustomRuleTestCases(*req.TestCases)
+	}
+	if err := server.ValidateCustomRule(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+	}
+	return controller.JSONBaseErrorReq(c, s.Save(rule))
 }
 
 func getCustomRule(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	rule, exist, err := model.GetStorage().GetCustomRuleByRuleId(c.Param("rule_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errCustomRuleNotExist)
+	}
+	return c.JSON(http.StatusOK, &GetCustomRuleResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertCustomRuleToCustomRuleResV1(rule, true),
+	})
 }
 
 func getRuleTypeByDBType(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	dbType := c.QueryParam("db_type")
+	if dbType == "" {
+		dbType = c.Param("db_type")
+	}
+	s := model.GetStorage()
+	ruleTypes, err := s.GetRuleTypeByDBType(dbType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	customTypeCounts, err := s.GetCustomRuleTypeCountByDBType(dbType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	// RuleCount is the count of custom rules in the type.
+	customRuleCounts := map[string]uint{}
+	for _, count := range customTypeCounts {
+		customRuleCounts[count.Type] = count.TypeCount
+	}
+	data := make([]RuleTypeV1, 0, len(ruleTypes)+len(customTypeCounts))
+	builtinTypes := map[string]struct{}{}
+	for _, ruleType := range ruleTypes {
+		builtinTypes[ruleType] = struct{}{}
+		data = append(data, RuleTypeV1{RuleType: ruleType, RuleCount: customRuleCounts[ruleType]})
+	}
+	for _, count := range customTypeCounts {
+		if _, ok := builtinTypes[count.Type]; !ok {
+			data = append(data, RuleTypeV1{RuleType: count.Type, RuleCount: count.TypeCount, IsCustomRuleType: true})
+		}
+	}
+	return c.JSON(http.StatusOK, &GetRuleTypeByDBTypeResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func dryRunCustomRule(c echo.Context) error {
+	req := new(DryRunCustomRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	rule := &model.CustomRule{
+		RuleId:     "dry_run",
+		Desc:       req.Desc,
+		DBType:     req.DBType,
+		RuleScript: req.RuleScript,
+		ScriptType: req.ScriptType,
+		Params:     convertCustomRuleParams(req.Params),
+	}
+
+	data := &DryRunCustomRuleResDataV1{
+		SQLResults:      []*CustomRuleSQLResultV1{},
+		TestCaseResults: []*CustomRuleTestCaseResultV1{},
+	}
+	if req.SQL != "" {
+		results, err := server.DryRunCustomRule(rule, req.SQL)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+		}
+		for _, result := range results {
+			data.SQLResults = append(data.SQLResults, &CustomRuleSQLResultV1{
+				SQL:     result.SQL,
+				Hit:     result.Hit,
+				Message: result.Message,
+				Error:   result.Error,
+			})
+		}
+	}
+	for _, testCase := range req.TestCases {
+		res := &CustomRuleTestCaseResultV1{SQL: testCase.SQL, ExpectHit: testCase.ExpectHit}
+		results, err := server.DryRunCustomRule(rule, testCase.SQL)
+		if err != nil {
+			res.Error = err.Error()
+		}
+		for _, result := range results {
+			res.Hit = res.Hit || result.Hit
+			if result.Error != "" {
+				res.Error = result.Error
+			}
+		}
+		res.Passed = res.Error == "" && res.Hit == testCase.ExpectHit
+		data.TestCaseResults = append(data.TestCaseResults, res)
+	}
+	return c.JSON(http.StatusOK, &DryRunCustomRuleResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
 
 func getRuleKnowledge(c echo.Context) error {
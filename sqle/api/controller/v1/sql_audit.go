@@ -118,9 +118,9 @@ func DirectAudit(c echo.Context) error {
 
 	var task *model.Task
 	if instance != nil && schemaName != "" {
-		task, err = server.DirectAuditByInstance(l, sql, schemaName, instance)
+		task, err = server.DirectAuditByInstance(c.Request().Context(), l, sql, schemaName, instance)
 	} else {
-		task, err = server.AuditSQLByDBType(l, sql, req.InstanceType, "", "")
+		task, err = server.AuditSQLByDBType(c.Request().Context(), l, sql, req.InstanceType, "", "")
 	}
 	if err != nil {
 		l.Errorf("audit sqls failed: %v", err)
@@ -129,17 +129,17 @@ func DirectAudit(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, DirectAuditResV1{
 		BaseRes: controller.BaseRes{},
-		Data:    convertTaskResultToAuditResV1(task),
+		Data:    convertTaskResultToAuditResV1(task, controller.GetLanguage(c)),
 	})
 }
 
-func convertTaskResultToAuditResV1(task *model.Task) *AuditResDataV1 {
+func convertTaskResultToAuditResV1(task *model.Task, lang string) *AuditResDataV1 {
 	results := make([]AuditSQLResV1, len(task.ExecuteSQLs))
 	for i, sql := range task.ExecuteSQLs {
 		results[i] = AuditSQLResV1{
 			Number:      sql.Number,
 			ExecSQL:     sql.Content,
-			AuditResult: sql.GetAuditResults(),
+			AuditResult: sql.GetAuditResults(lang),
 			AuditLevel:  sql.AuditLevel,
 		}
 	}
@@ -225,9 +225,9 @@ func DirectAuditFiles(c echo.Context) error {
 
 	var task *model.Task
 	if instance != nil && schemaName != "" {
-		task, err = server.DirectAuditByInstance(l, sqls, schemaName, instance)
+		task, err = server.DirectAuditByInstance(c.Request().Context(), l, sqls, schemaName, instance)
 	} else {
-		task, err = server.AuditSQLByDBType(l, sqls, req.InstanceType, "", "")
+		task, err = server.AuditSQLByDBType(c.Request().Context(), l, sqls, req.InstanceType, "", "")
 	}
 	if err != nil {
 		l.Errorf("audit sqls failed: %v", err)
@@ -236,7 +236,7 @@ func DirectAuditFiles(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, DirectAuditResV1{
 		BaseRes: controller.BaseRes{},
-		Data:    convertTaskResultToAuditResV1(task),
+		Data:    convertTaskResultToAuditResV1(task, controller.GetLanguage(c)),
 	})
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/gitdiff"
 	"github.com/actiontech/sqle/sqle/server"
 	"github.com/actiontech/sqle/sqle/utils"
 	goGit "github.com/go-git/go-git/v5"
@@ -44,8 +45,27 @@ type CreateSQLAuditRecordResV1 struct {
 }
 
 type SQLAuditRecordResData struct {
-	Id   string          `json:"sql_audit_record_id"`
-	Task *AuditTaskResV1 `json:"task"`
+	Id   string                  `json:"sql_audit_record_id"`
+	Task *AuditTaskResV1         `json:"task"`
+	Git  *SQLAuditRecordGitResV1 `json:"git,omitempty"`
+}
+
+type SQLAuditRecordGitResV1 struct {
+	RepositoryURL string `json:"repository_url"`
+	// 为空时审核了仓库中的全部 SQL, 否则只审核了从该提交到 head_commit 变更的 SQL
+	BaseCommit string `json:"base_commit"`
+	HeadCommit string `json:"head_commit"`
+}
+
+func convertSQLAuditRecordGitToRes(record *model.SQLAuditRecord) *SQLAuditRecordGitResV1 {
+	if record.GitHeadCommit == "" {
+		return nil
+	}
+	return &SQLAuditRecordGitResV1{
+		RepositoryURL: record.GitRepository,
+		BaseCommit:    record.GitBaseCommit,
+		HeadCommit:    record.GitHeadCommit,
+	}
 }
 
 // 10M
@@ -62,6 +82,8 @@ var maxZipFileSize int64 = 1024 * 1024 * 10
 // @Description 5. formData[git_http_url]:the url which scheme is http(s) and end with .git.
 // @Description 6. formData[git_user_name]:The name of the user who owns the repository read access.
 // @Description 7. formData[git_user_password]:The password corresponding to git_user_name.
+// @Description 8. formData[git_head_revision]:the revision of git repository to audit, it is the default branch if it is empty.
+// @Description 9. formData[git_base_revision]:only the SQLs changed from the merge base of it and git_head_revision are audited if it is not empty.
 // @Accept mpfd
 // @Produce json
 // @Tags sql_audit_record
@@ -77,6 +99,8 @@ var maxZipFileSize int64 = 1024 * 1024 * 10
 // @Param git_http_url formData string false "git repository url"
 // @Param git_user_name formData string false "the name of user to clone the repository"
 // @Param git_user_password formData string false "the password corresponding to git_user_name"
+// @Param git_head_revision formData string false "the revision of git repository to audit, such as branch, tag or commit"
+// @Param git_base_revision formData string false "the base revision of git repository, only the changed SQLs are audited"
 // @Success 200 {object} v1.CreateSQLAuditRecordResV1
 // @router /v1/projects/{project_name}/sql_audit_records [post]
 func CreateSQLAuditRecord(c echo.Context) error {
@@ -141,6 +165,11 @@ func CreateSQLAuditRecord(c echo.Context) error {
 		TaskId:        task.ID,
 		Task:          task,
 	}
+	if sqls.GitSource != nil {
+		record.GitRepository = sqls.GitSource.RepositoryURL
+		record.GitBaseCommit = sqls.GitSource.BaseCommit
+		record.GitHeadCommit = sqls.GitSource.HeadCommit
+	}
 	if err := s.Save(&record); err != nil {
 		return controller.JSONBaseErrorReq(c, fmt.Errorf("save sql audit record failed: %v", err))
 	}
@@ -167,6 +196,7 @@ func CreateSQLAuditRecord(c echo.Context) error {
 				ExecStartTime:  task.ExecStartAt,
 				ExecEndTime:    task.ExecEndAt,
 			},
+			Git: convertSQLAuditRecordGitToRes(&record),
 		},
 	})
 }
@@ -176,11 +206,20 @@ type getSQLFromFileResp struct {
 	SQLsFromFormData string
 	SQLsFromSQLFiles []SQLsFromSQLFile
 	SQLsFromXMLs     []SQLFromXML
+	GitSource        *gitSource
+}
+
+type gitSource struct {
+	RepositoryURL string
+	BaseCommit    string
+	HeadCommit    string
 }
 
 type SQLsFromSQLFile struct {
 	FilePath string
 	SQLs     string
+	// only the SQLs changed in diff are audited if it is not nil
+	diff *gitdiff.Diff
 }
 
 type SQLFromXML struct {
@@ -202,7 +241,7 @@ func (s getSQLFromFileResp) MergeSQLs() (sqls string) {
 func addSQLsFromFileToTasks(sqls getSQLFromFileResp, task *model.Task, plugin driver.Plugin) error {
 	var num uint = 1
 
-	fileTask := func(sqlsText, filePath string, defaultStartLine uint64) error {
+	fileTask := func(sqlsText, filePath string, defaultStartLine uint64, diff *gitdiff.Diff) error {
 		nodes, err := plugin.Parse(context.TODO(), sqlsText)
 		if err != nil {
 			return fmt.Errorf("parse sqls failed: %v", err)
@@ -212,6 +251,9 @@ func addSQLsFromFileToTasks(sqls getSQLFromFileResp, task *model.Task, plugin dr
 			if startLine == 0 {
 				startLine = node.StartLine
 			}
+			if diff != nil && !diff.SQLChanged(filePath, startLine, node.Text) {
+				continue
+			}
 			task.ExecuteSQLs = append(task.ExecuteSQLs, &model.ExecuteSQL{
 				BaseSQL: model.BaseSQL{
 					Number:      num,
@@ -228,14 +270,14 @@ func addSQLsFromFileToTasks(sqls getSQLFromFileResp, task *model.Task, plugin dr
 	}
 
 	// 处理从页面输入的SQL
-	err := fileTask(sqls.SQLsFromFormData, "", 0)
+	err := fileTask(sqls.SQLsFromFormData, "", 0, nil)
 	if err != nil {
 		return fmt.Errorf("parse sqls failed: %v", err)
 	}
 
 	for _, sqlsFromOneFile := range sqls.SQLsFromSQLFiles {
 		// SQL文件和Java文件里的SQL都在这里处理
-		err := fileTask(sqlsFromOneFile.SQLs, sqlsFromOneFile.FilePath, 0)
+		err := fileTask(sqlsFromOneFile.SQLs, sqlsFromOneFile.FilePath, 0, sqlsFromOneFile.diff)
 		if err != nil {
 			return fmt.Errorf("parse sqls failed: %v", err)
 		}
@@ -243,7 +285,7 @@ func addSQLsFromFileToTasks(sqls getSQLFromFileResp, task *model.Task, plugin dr
 
 	for _, sqlsFromOneFile := range sqls.SQLsFromXMLs {
 		// 这里遍历的一个元素里只有一条SQL
-		err := fileTask(sqlsFromOneFile.SQL, sqlsFromOneFile.FilePath, sqlsFromOneFile.StartLine)
+		err := fileTask(sqlsFromOneFile.SQL, sqlsFromOneFile.FilePath, sqlsFromOneFile.StartLine, nil)
 		if err != nil {
 			return fmt.Errorf("parse sqls failed: %v", err)
 		}
@@ -415,17 +457,17 @@ func parseXMLsWithFilePath(xmlContents []xmlParser.XmlFile) ([]SQLFromXML, error
 }
 
 // todo 此处跳过了不支持的编码格式文件
-func getSqlsFromGit(c echo.Context) (sqlsFromSQLFiles, sqlsFromJavaFiles []SQLsFromSQLFile, sqlsFromXMLs []SQLFromXML, exist bool, err error) {
+func getSqlsFromGit(c echo.Context) (sqls getSQLFromFileResp, exist bool, err error) {
 	// make a temp dir and clean up befor return
 	dir, err := os.MkdirTemp("./", "git-repo-")
 	if err != nil {
-		return nil, nil, nil, false, err
+		return sqls, false, err
 	}
 	defer os.RemoveAll(dir)
 	// read http url from form and check if it's a git url
 	url := c.FormValue(GitHttpURL)
 	if !utils.IsGitHttpURL(url) {
-		return nil, nil, nil, false, errors.New(errors.DataInvalid, fmt.Errorf("url is not a git url"))
+		return sqls, false, errors.New(errors.DataInvalid, fmt.Errorf("url is not a git url"))
 	}
 	cloneOpts := &goGit.CloneOptions{
 		URL: url,
@@ -440,92 +482,205 @@ func getSqlsFromGit(c echo.Context) (sqlsFromSQLFiles, sqlsFromJavaFiles []SQLsF
 		}
 	}
 	// clone from git
-	_, err = goGit.PlainCloneContext(c.Request().Context(), dir, false, cloneOpts)
+	repo, err := goGit.PlainCloneContext(c.Request().Context(), dir, false, cloneOpts)
 	if err != nil {
-		return nil, nil, nil, false, err
+		return sqls, false, err
 	}
+	headCommit, diff, err := checkoutGitRevisions(c.Request().Context(), repo, c.FormValue(GitHeadRevision), c.FormValue(GitBaseRevision))
+	if err != nil {
+		return sqls, false, errors.New(errors.DataInvalid, err)
+	}
+	sqls = getSQLFromFileResp{
+		SourceType: model.TaskSQLSourceFromGitRepository,
+		GitSource: &gitSource{
+			RepositoryURL: gitdiff.RepositoryURL(url),
+			HeadCommit:    headCommit,
+		},
+	}
+	if diff != nil {
+		sqls.GitSource.BaseCommit = diff.BaseCommit
+	}
+
 	l := log.NewEntry().WithField("function", "getSqlsFromGit")
 	var xmlContents []xmlParser.XmlFile
+	var sqlsFromJavaFiles []SQLsFromSQLFile
 	// traverse the repository, parse and put SQL into sqlBuffer
 	err = filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
 		gitPath := strings.TrimPrefix(path, strings.TrimPrefix(dir, "./"))
-		if !info.IsDir() {
-			var sqlBuffer strings.Builder
-			switch {
-			case strings.HasSuffix(path, ".xml"):
-				content, err := os.ReadFile(path)
-				if err != nil {
-					l.Errorf("skip file [%v]. because read file failed: %v", path, err)
-					return nil
-				}
-				content, err = utils.ConvertToUtf8(content)
-				if err != nil {
-					l.Errorf("skip file [%v]. because convert to utf8 failed: %v", path, err)
-					return nil
-				}
+		if info.IsDir() {
+			// the git metadata is not the source of repository.
+			if info.Name() == goGit.GitDirName {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		var changedFile *gitdiff.ChangedFile
+		if diff != nil && !strings.HasSuffix(path, ".xml") {
+			// only the changed files are audited, the XML files are all parsed
+			// for the SQL fragments referenced across files.
+			var changed bool
+			if changedFile, changed = diff.File(gitPath); !changed {
+				return nil
+			}
+		}
+		switch {
+		case strings.HasSuffix(path, ".xml"):
+			content, err := os.ReadFile(path)
+			if err != nil {
+				l.Errorf("skip file [%v]. because read file failed: %v", path, err)
+				return nil
+			}
+			content, err = utils.ConvertToUtf8(content)
+			if err != nil {
+				l.Errorf("skip file [%v]. because convert to utf8 failed: %v", path, err)
+				return nil
+			}
 
-				xmlContents = append(xmlContents, xmlParser.XmlFile{
-					FilePath: gitPath,
-					Content:  string(content),
-				})
-			case strings.HasSuffix(path, ".sql"):
-				content, err := os.ReadFile(path)
-				if err != nil {
-					l.Errorf("skip file [%v]. because read file failed: %v", path, err)
-					return nil
-				}
-				content, err = utils.ConvertToUtf8(content)
-				if err != nil {
-					l.Errorf("skip file [%v]. because convert to utf8 failed: %v", path, err)
-					return nil
-				}
+			xmlContents = append(xmlContents, xmlParser.XmlFile{
+				FilePath: gitPath,
+				Content:  string(content),
+			})
+		case strings.HasSuffix(path, ".sql"):
+			content, err := os.ReadFile(path)
+			if err != nil {
+				l.Errorf("skip file [%v]. because read file failed: %v", path, err)
+				return nil
+			}
+			content, err = utils.ConvertToUtf8(content)
+			if err != nil {
+				l.Errorf("skip file [%v]. because convert to utf8 failed: %v", path, err)
+				return nil
+			}
 
-				sqlsFromSQLFiles = append(sqlsFromSQLFiles, SQLsFromSQLFile{
-					FilePath: gitPath,
-					SQLs:     string(content),
-				})
-			case strings.HasSuffix(path, ".java"):
-				sqls, err := javaParser.GetSqlFromJavaFile(path)
+			sqls.SQLsFromSQLFiles = append(sqls.SQLsFromSQLFiles, SQLsFromSQLFile{
+				FilePath: gitPath,
+				SQLs:     string(content),
+				diff:     diff,
+			})
+		case strings.HasSuffix(path, ".java"):
+			javaSQLs, err := javaParser.GetSqlFromJavaFile(path)
+			if err != nil {
+				l.Errorf("skip file [%v]. because get sql from java file failed: %v", path, err)
+				return nil
+			}
+			if changedFile != nil {
+				javaSQLs, err = getChangedSQLsFromJavaFile(diff, changedFile, dir, javaSQLs)
 				if err != nil {
-					l.Errorf("skip file [%v]. because get sql from java file failed: %v", path, err)
+					l.Errorf("skip file [%v]. because get changed sql from java file failed: %v", path, err)
 					return nil
 				}
-				for _, sql := range sqls {
-					if !strings.HasSuffix(sql, ";") {
-						sql += ";"
-					}
-					_, err = sqlBuffer.WriteString(sql)
-					if err != nil {
-						return fmt.Errorf("gather sqls from java file failed: %v", err)
-					}
+			}
+			var sqlBuffer strings.Builder
+			for _, sql := range javaSQLs {
+				if !strings.HasSuffix(sql, ";") {
+					sql += ";"
 				}
-
-				content, err := utils.ConvertToUtf8([]byte(sqlBuffer.String()))
+				_, err = sqlBuffer.WriteString(sql)
 				if err != nil {
-					l.Errorf("skip file [%v]. because convert to utf8 failed: %v", path, err)
-					return nil
+					return fmt.Errorf("gather sqls from java file failed: %v", err)
 				}
+			}
 
-				sqlsFromJavaFiles = append(sqlsFromJavaFiles, SQLsFromSQLFile{
-					FilePath: gitPath,
-					SQLs:     string(content),
-				})
+			content, err := utils.ConvertToUtf8([]byte(sqlBuffer.String()))
+			if err != nil {
+				l.Errorf("skip file [%v]. because convert to utf8 failed: %v", path, err)
+				return nil
 			}
+
+			sqlsFromJavaFiles = append(sqlsFromJavaFiles, SQLsFromSQLFile{
+				FilePath: gitPath,
+				SQLs:     string(content),
+			})
 		}
 		return nil
 	})
 	if err != nil {
-		return nil, nil, nil, false, err
+		return sqls, false, err
 	}
+	sqls.SQLsFromSQLFiles = append(sqls.SQLsFromSQLFiles, sqlsFromJavaFiles...)
 
 	// parse xml content
 	// xml文件需要把所有文件内容同时解析，否则会无法解析跨namespace引用的SQL
-	sqlsFromXMLs, err = parseXMLsWithFilePath(xmlContents)
+	if diff != nil {
+		stmts, err := diff.ChangedMyBatisStmts(xmlContents)
+		if err != nil {
+			return sqls, false, fmt.Errorf("parse sqls from xml failed: %v", err)
+		}
+		for _, stmt := range stmts {
+			sqls.SQLsFromXMLs = append(sqls.SQLsFromXMLs, SQLFromXML{
+				FilePath:  stmt.FilePath,
+				StartLine: stmt.StartLine,
+				SQL:       stmt.SQL,
+			})
+		}
+	} else {
+		sqls.SQLsFromXMLs, err = parseXMLsWithFilePath(xmlContents)
+		if err != nil {
+			return sqls, false, err
+		}
+	}
+
+	return sqls, true, nil
+}
+
+// checkoutGitRevisions checks out the head revision if it is not empty, and
+// returns the diff from base revision to head revision if base is not empty.
+func checkoutGitRevisions(ctx context.Context, repo *goGit.Repository, head, base string) (headCommit string, diff *gitdiff.Diff, err error) {
+	if head != "" {
+		commit, err := gitdiff.ResolveCommit(repo, head)
+		if err != nil {
+			return "", nil, err
+		}
+		wt, err := repo.Worktree()
+		if err != nil {
+			return "", nil, err
+		}
+		if err := wt.Checkout(&goGit.CheckoutOptions{Hash: commit.Hash, Force: true}); err != nil {
+			return "", nil, fmt.Errorf("checkout %s failed: %v", head, err)
+		}
+	}
+	ref, err := repo.Head()
+	if err != nil {
+		return "", nil, err
+	}
+	if base == "" {
+		return ref.Hash().String(), nil, nil
+	}
+	diff, err = gitdiff.Compute(ctx, repo, base, ref.Hash().String())
 	if err != nil {
-		return nil, nil, nil, false, err
+		return "", nil, err
 	}
+	return ref.Hash().String(), diff, nil
+}
 
-	return sqlsFromSQLFiles, sqlsFromJavaFiles, sqlsFromXMLs, true, nil
+// getChangedSQLsFromJavaFile returns the SQL literals which are not in the
+// Java file of base revision, the SQLs of Java file do not have lines.
+func getChangedSQLsFromJavaFile(diff *gitdiff.Diff, f *gitdiff.ChangedFile, tempDir string, headSQLs []string) ([]string, error) {
+	content, exist, err := diff.BaseContent(f)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return headSQLs, nil
+	}
+	baseFile, err := os.CreateTemp(tempDir, "base-*.java")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(baseFile.Name())
+	_, err = baseFile.WriteString(content)
+	if closeErr := baseFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	baseSQLs, err := javaParser.GetSqlFromJavaFile(baseFile.Name())
+	if err != nil {
+		// all SQLs are regarded as changed if the base file can not be parsed.
+		return headSQLs, nil
+	}
+	return gitdiff.ChangedSQLs(headSQLs, baseSQLs), nil
 }
 
 type UpdateSQLAuditRecordReqV1 struct {
@@ -629,6 +784,7 @@ type SQLAuditRecord struct {
 	Instance         *SQLAuditRecordInstance `json:"instance,omitempty"`
 	Task             *AuditTaskResV1         `json:"task,omitempty"`
 	CreatedAt        *time.Time              `json:"created_at,omitempty"`
+	Git              *SQLAuditRecordGitResV1 `json:"git,omitempty"`
 }
 
 type SQLAuditRecordInstance struct {
@@ -860,6 +1016,7 @@ func GetSQLAuditRecordV1(c echo.Context) error {
 			Tags:             tags,
 			SQLAuditRecordId: auditRecordId,
 			Task:             task,
+			Git:              convertSQLAuditRecordGitToRes(record),
 		},
 	})
 }
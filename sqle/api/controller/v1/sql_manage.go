@@ -1,7 +1,11 @@
 package v1
 
 import (
+	"net/http"
+
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/server/eventbus"
 	"github.com/labstack/echo/v4"
 )
 
@@ -111,7 +115,27 @@ type BatchUpdateSqlManageReq struct {
 // @Success 200 {object} controller.BaseRes
 // @router /v1/projects/{project_name}/sql_manages/batch [PATCH]
 func BatchUpdateSqlManage(c echo.Context) error {
-	return batchUpdateSqlManage(c)
+	req := new(BatchUpdateSqlManageReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	if err := batchUpdateSqlManage(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.Status != nil {
+		projectUid, err := dms.GetPorjectUIDByName(c.Request().Context(), c.Param("project_name"))
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		sqlManageIds := make([]uint, 0, len(req.SqlManageIdList))
+		for _, id := range req.SqlManageIdList {
+			if id != nil {
+				sqlManageIds = append(sqlManageIds, uint(*id))
+			}
+		}
+		eventbus.PublishSqlManageStatusChanged(projectUid, sqlManageIds, *req.Status, controller.GetUserID(c))
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
 type ExportSqlManagesReq struct {
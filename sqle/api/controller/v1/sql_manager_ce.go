@@ -16,7 +16,7 @@ func getSqlManageList(c echo.Context) error {
 	return ErrCommunityEditionNotSupportSqlManage
 }
 
-func batchUpdateSqlManage(c echo.Context) error {
+func batchUpdateSqlManage(c echo.Context, req *BatchUpdateSqlManageReq) error {
 	return ErrCommunityEditionNotSupportSqlManage
 }
 
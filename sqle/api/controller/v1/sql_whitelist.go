@@ -3,6 +3,8 @@ package v1
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"time"
 
 	"net/http"
 
@@ -16,8 +18,27 @@ import (
 
 type CreateAuditWhitelistReqV1 struct {
 	Value     string `json:"value" example:"create table" valid:"required"`
-	MatchType string `json:"match_type" example:"exact_match" enums:"exact_match,fp_match" valid:"omitempty,oneof=exact_match fp_match"`
+	MatchType string `json:"match_type" example:"exact_match" enums:"exact_match,fp_match,regex_match" valid:"omitempty,oneof=exact_match fp_match regex_match"`
 	Desc      string `json:"desc" example:"used for rapid release"`
+	// 生效的数据源, 为空时对所有数据源生效
+	InstanceNames []string `json:"instance_names"`
+	// 生效的 schema, 为空时对所有 schema 生效
+	SchemaNames []string `json:"schema_names"`
+	// 跳过的规则, 为空时跳过所有规则
+	RuleNames []string   `json:"rule_names"`
+	ExpiredAt *time.Time `json:"expired_at" example:"2024-01-01T00:00:00Z"`
+	// 负责人, 为空时为当前用户
+	Owner string `json:"owner"`
+}
+
+func checkSqlWhitelist(sqlWhitelist *model.SqlWhitelist) error {
+	if sqlWhitelist.MatchType != model.SQLWhitelistRegexMatch {
+		return nil
+	}
+	if _, err := regexp.Compile(sqlWhitelist.Value); err != nil {
+		return errors.New(errors.DataInvalid, fmt.Errorf("invalid regular expression: %v", err))
+	}
+	return nil
 }
 
 // @Summary 添加SQL白名单
@@ -41,11 +62,28 @@ func CreateAuditWhitelist(c echo.Context) error {
 	}
 	s := model.GetStorage()
 
+	owner := req.Owner
+	if owner == "" {
+		user, err := controller.GetCurrentUser(c, dms.GetUser)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		owner = user.Name
+	}
+
 	sqlWhitelist := &model.SqlWhitelist{
-		ProjectId: model.ProjectUID(projectUid),
-		Value:     req.Value,
-		Desc:      req.Desc,
-		MatchType: req.MatchType,
+		ProjectId:     model.ProjectUID(projectUid),
+		Value:         req.Value,
+		Desc:          req.Desc,
+		MatchType:     req.MatchType,
+		InstanceNames: req.InstanceNames,
+		SchemaNames:   req.SchemaNames,
+		RuleNames:     req.RuleNames,
+		ExpiredAt:     req.ExpiredAt,
+		Owner:         owner,
+	}
+	if err := checkSqlWhitelist(sqlWhitelist); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
 	}
 
 	err = s.Save(sqlWhitelist)
@@ -57,9 +95,16 @@ func CreateAuditWhitelist(c echo.Context) error {
 }
 
 type UpdateAuditWhitelistReqV1 struct {
-	Value     *string `json:"value" example:"create table"`
-	MatchType *string `json:"match_type" example:"exact_match" enums:"exact_match,fp_match"`
-	Desc      *string `json:"desc" example:"used for rapid release"`
+	Value         *string    `json:"value" example:"create table"`
+	MatchType     *string    `json:"match_type" example:"exact_match" enums:"exact_match,fp_match,regex_match" valid:"omitempty,oneof=exact_match fp_match regex_match"`
+	Desc          *string    `json:"desc" example:"used for rapid release"`
+	InstanceNames *[]string  `json:"instance_names"`
+	SchemaNames   *[]string  `json:"schema_names"`
+	RuleNames     *[]string  `json:"rule_names"`
+	ExpiredAt     *time.Time `json:"expired_at" example:"2024-01-01T00:00:00Z"`
+	// 为 true 时清除过期时间
+	NeverExpire *bool   `json:"never_expire"`
+	Owner       *string `json:"owner"`
 }
 
 // @Summary 更新SQL白名单
@@ -95,11 +140,6 @@ func UpdateAuditWhitelistById(c echo.Context) error {
 			fmt.Errorf("sql audit whitelist is not exist")))
 	}
 
-	// nothing to update
-	if req.Value == nil && req.Desc == nil && req.MatchType == nil {
-		return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
-	}
-
 	if req.Value != nil {
 		sqlWhitelist.Value = *req.Value
 	}
@@ -109,6 +149,27 @@ func UpdateAuditWhitelistById(c echo.Context) error {
 	if req.Desc != nil {
 		sqlWhitelist.Desc = *req.Desc
 	}
+	if req.InstanceNames != nil {
+		sqlWhitelist.InstanceNames = *req.InstanceNames
+	}
+	if req.SchemaNames != nil {
+		sqlWhitelist.SchemaNames = *req.SchemaNames
+	}
+	if req.RuleNames != nil {
+		sqlWhitelist.RuleNames = *req.RuleNames
+	}
+	if req.ExpiredAt != nil {
+		sqlWhitelist.ExpiredAt = req.ExpiredAt
+	}
+	if req.NeverExpire != nil && *req.NeverExpire {
+		sqlWhitelist.ExpiredAt = nil
+	}
+	if req.Owner != nil {
+		sqlWhitelist.Owner = *req.Owner
+	}
+	if err := checkSqlWhitelist(sqlWhitelist); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
 
 	err = s.Save(sqlWhitelist)
 	if err != nil {
@@ -164,10 +225,18 @@ type GetAuditWhitelistResV1 struct {
 }
 
 type AuditWhitelistResV1 struct {
-	Id        uint   `json:"audit_whitelist_id"`
-	Value     string `json:"value"`
-	MatchType string `json:"match_type"`
-	Desc      string `json:"desc"`
+	Id              uint       `json:"audit_whitelist_id"`
+	Value           string     `json:"value"`
+	MatchType       string     `json:"match_type"`
+	Desc            string     `json:"desc"`
+	InstanceNames   []string   `json:"instance_names"`
+	SchemaNames     []string   `json:"schema_names"`
+	RuleNames       []string   `json:"rule_names"`
+	ExpiredAt       *time.Time `json:"expired_at,omitempty"`
+	IsExpired       bool       `json:"is_expired"`
+	Owner           string     `json:"owner"`
+	MatchedCount    uint64     `json:"matched_count"`
+	LastMatchedTime *time.Time `json:"last_matched_time,omitempty"`
 }
 
 // @Summary 获取Sql审核白名单
@@ -195,13 +264,22 @@ func GetSqlWhitelist(c echo.Context) error {
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
+	now := time.Now()
 	whitelistRes := make([]*AuditWhitelistResV1, 0, len(sqlWhitelist))
 	for _, v := range sqlWhitelist {
 		whitelistRes = append(whitelistRes, &AuditWhitelistResV1{
-			Id:        v.ID,
-			Value:     v.Value,
-			Desc:      v.Desc,
-			MatchType: v.MatchType,
+			Id:              v.ID,
+			Value:           v.Value,
+			Desc:            v.Desc,
+			MatchType:       v.MatchType,
+			InstanceNames:   v.InstanceNames,
+			SchemaNames:     v.SchemaNames,
+			RuleNames:       v.RuleNames,
+			ExpiredAt:       v.ExpiredAt,
+			IsExpired:       v.IsExpired(now),
+			Owner:           v.Owner,
+			MatchedCount:    v.MatchedCount,
+			LastMatchedTime: v.LastMatchedTime,
 		})
 	}
 	return c.JSON(http.StatusOK, &GetAuditWhitelistResV1{
@@ -100,6 +100,8 @@ const (
 	GitHttpURL              = "git_http_url"
 	GitUserName             = "git_user_name"
 	GitPassword             = "git_user_password"
+	GitBaseRevision         = "git_base_revision"
+	GitHeadRevision         = "git_head_revision"
 	ZIPFileExtension        = ".zip"
 )
 
@@ -156,16 +158,12 @@ func getSQLFromFile(c echo.Context) (getSQLFromFileResp, error) {
 	}
 
 	// If zip file is not exist, read it from git repository
-	sqlsFromSQLFiles, sqlsFromJavaFiles, sqlsFromXMLs, exist, err := getSqlsFromGit(c)
+	sqlsFromGit, exist, err := getSqlsFromGit(c)
 	if err != nil {
 		return getSQLFromFileResp{}, err
 	}
 	if exist {
-		return getSQLFromFileResp{
-			SourceType:       model.TaskSQLSourceFromGitRepository,
-			SQLsFromSQLFiles: append(sqlsFromSQLFiles, sqlsFromJavaFiles...),
-			SQLsFromXMLs:     sqlsFromXMLs,
-		}, nil
+		return sqlsFromGit, nil
 	}
 	return getSQLFromFileResp{}, errors.New(errors.DataInvalid, fmt.Errorf("input sql is empty"))
 }
@@ -413,6 +411,56 @@ func GetTask(c echo.Context) error {
 		Data:    convertTaskToRes(task),
 	})
 }
+
+type GetAuditTaskProgressResV1 struct {
+	controller.BaseRes
+	Data *AuditTaskProgressResV1 `json:"data"`
+}
+
+type AuditTaskProgressResV1 struct {
+	Status          string  `json:"status" enums:"initialized,auditing,audited,executing,exec_success,exec_failed,manually_executed"`
+	TotalSQLCount   int     `json:"total_sql_count"`
+	AuditedSQLCount int     `json:"audited_sql_count"`
+	ProgressPct     float64 `json:"progress_pct"`
+}
+
+// @Summary 获取Sql扫描任务的审核进度
+// @Description get the audit progress of task, the SQL count is only returned while the task is auditing
+// @Tags task
+// @Id getAuditTaskProgressV1
+// @Security ApiKeyAuth
+// @Param task_id path string true "task id"
+// @Success 200 {object} v1.GetAuditTaskProgressResV1
+// @router /v1/tasks/audits/{task_id}/audit_progress [get]
+func GetTaskAuditProgress(c echo.Context) error {
+	taskId := c.Param("task_id")
+	task, err := getTaskById(c.Request().Context(), taskId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	err = CheckCurrentUserCanViewTask(c, task)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := &AuditTaskProgressResV1{Status: task.Status}
+	if p, ok := server.GetAuditProgress(task.ID); ok {
+		data.Status = "auditing"
+		data.TotalSQLCount = p.TotalSQLCount
+		data.AuditedSQLCount = p.AuditedSQLCount
+		if p.TotalSQLCount > 0 {
+			data.ProgressPct = utils.Round(float64(p.AuditedSQLCount)*100/float64(p.TotalSQLCount), 2)
+		}
+	} else if task.Status != model.TaskStatusInit {
+		data.ProgressPct = 100
+	}
+	return c.JSON(http.StatusOK, &GetAuditTaskProgressResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
 func GetTaskById(ctx context.Context, taskId string) (*model.Task, error) {
 	return getTaskById(ctx, taskId)
 }
@@ -522,7 +570,7 @@ func GetTaskSQLs(c echo.Context) error {
 			Number:      taskSQL.Number,
 			Description: taskSQL.Description,
 			ExecSQL:     taskSQL.ExecSQL,
-			AuditResult: taskSQL.GetAuditResults(),
+			AuditResult: taskSQL.GetAuditResults(controller.GetLanguage(c)),
 			AuditLevel:  taskSQL.AuditLevel,
 			AuditStatus: taskSQL.AuditStatus,
 			ExecResult:  taskSQL.ExecResult,
@@ -595,7 +643,7 @@ func DownloadTaskSQLReportFile(c echo.Context) error {
 			strconv.FormatUint(uint64(td.Number), 10),
 			td.ExecSQL,
 			taskSql.GetAuditStatusDesc(),
-			taskSql.GetAuditResultDesc(),
+			taskSql.GetAuditResultDesc(controller.GetLanguage(c)),
 			taskSql.GetExecStatusDesc(),
 			td.ExecResult,
 			td.RollbackSQL.String,
@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	dmsV1 "github.com/actiontech/dms/pkg/dms-common/api/dms/v1"
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResumeTaskReqV1 struct {
+	Operation string `json:"operation" form:"operation" valid:"required,oneof=resume skip retry" enums:"resume,skip,retry"`
+	// 跳过失败的 SQL 时必填
+	Reason string `json:"reason" form:"reason"`
+}
+
+// ResumeTaskByWorkflowV1
+// @Summary 继续上线执行失败的工单数据源任务
+// @Description resume the failed execution of task from its checkpoint. resume: execute from the failed SQL to the end; skip: skip the failed SQL with reason and execute the rest; retry: execute the failed SQL only
+// @Tags workflow
+// @Id resumeTaskByWorkflowV1
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param project_name path string true "project name"
+// @Param workflow_id path string true "workflow id"
+// @Param task_id path string true "task id"
+// @Param instance body v1.ResumeTaskReqV1 true "resume task request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/resume [post]
+func ResumeTaskByWorkflowV1(c echo.Context) error {
+	req := new(ResumeTaskReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	if req.Operation == model.ExecutionAttemptOperationSkip && req.Reason == "" {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("reason is required when skipping the failed SQL")))
+	}
+
+	projectUid, workflow, taskId, err := getWorkflowAndTaskIdByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if workflow.Record.Status != model.WorkflowStatusExecFailed &&
+		workflow.Record.Status != model.WorkflowStatusExecuting {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("workflow status is %s, the task can not be resumed", workflow.Record.Status)))
+	}
+
+	user, err := controller.GetCurrentUser(c, dms.GetUser)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	err = CheckCurrentUserCanOperateTasks(c, projectUid, workflow, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeExecuteWorkflow}, []uint{taskId})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := checkUserIsTaskExecutionAssignee(workflow, user, taskId); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	task, err := getTaskById(c.Request().Context(), strconv.Itoa(int(taskId)))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if task.Instance != nil && len(task.Instance.MaintenancePeriod) > 0 &&
+		!task.Instance.MaintenancePeriod.IsWithinScope(time.Now()) {
+		return controller.JSONBaseErrorReq(c, ErrWorkflowExecuteTimeIncorrect)
+	}
+
+	err = server.ResumeTaskExecution(workflow, taskId, req.Operation, req.Reason, user.GetIDStr())
+	return c.JSON(http.StatusOK, controller.NewBaseReq(err))
+}
+
+type GetTaskExecutionAttemptsResV1 struct {
+	controller.BaseRes
+	Data []*TaskExecutionAttemptResV1 `json:"data"`
+}
+
+type TaskExecutionAttemptResV1 struct {
+	Id           uint                            `json:"id"`
+	Operation    string                          `json:"operation" enums:"execute,resume,skip,retry"`
+	Status       string                          `json:"status" enums:"pending,executing,succeeded,failed"`
+	OperatorName string                          `json:"operator_name"`
+	Reason       string                          `json:"reason,omitempty"`
+	FromNumber   uint                            `json:"from_number"`
+	Checkpoint   uint                            `json:"checkpoint"`
+	ExecStartAt  *time.Time                      `json:"exec_start_at,omitempty"`
+	ExecEndAt    *time.Time                      `json:"exec_end_at,omitempty"`
+	SQLs         []*TaskExecutionAttemptSQLResV1 `json:"sql_list"`
+}
+
+type TaskExecutionAttemptSQLResV1 struct {
+	Number     uint   `json:"number"`
+	ExecStatus string `json:"exec_status"`
+	ExecResult string `json:"exec_result"`
+	RowAffects int64  `json:"row_affects"`
+}
+
+// GetTaskExecutionAttemptsByWorkflowV1
+// @Summary 获取工单数据源任务的上线执行历史
+// @Description get the execution attempts of task
+// @Tags workflow
+// @Id getTaskExecutionAttemptsByWorkflowV1
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param workflow_id path string true "workflow id"
+// @Param task_id path string true "task id"
+// @Success 200 {object} v1.GetTaskExecutionAttemptsResV1
+// @router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/execution_attempts [get]
+func GetTaskExecutionAttemptsByWorkflowV1(c echo.Context) error {
+	projectUid, workflow, taskId, err := getWorkflowAndTaskIdByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	err = CheckCurrentUserCanOperateTasks(c, projectUid, workflow, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeViewOthersWorkflow}, []uint{taskId})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	attempts, err := model.GetStorage().GetTaskExecutionAttempts(taskId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	// the first execution is started by the execution user of workflow.
+	var executionUserId string
+	for _, record := range workflow.Record.InstanceRecords {
+		if record.TaskId == taskId {
+			executionUserId = record.ExecutionUserId
+		}
+	}
+
+	data := make([]*TaskExecutionAttemptResV1, 0, len(attempts))
+	for _, attempt := range attempts {
+		if attempt.Operation == model.ExecutionAttemptOperationExecute && attempt.OperatorUserId == "" {
+			attempt.OperatorUserId = executionUserId
+		}
+		attemptRes := &TaskExecutionAttemptResV1{
+			Id:          attempt.ID,
+			Operation:   attempt.Operation,
+			Status:      attempt.Status,
+			Reason:      attempt.Reason,
+			FromNumber:  attempt.FromNumber,
+			Checkpoint:  attempt.Checkpoint,
+			ExecStartAt: attempt.ExecStartAt,
+			ExecEndAt:   attempt.ExecEndAt,
+			SQLs:        make([]*TaskExecutionAttemptSQLResV1, 0, len(attempt.SQLs)),
+		}
+		if attempt.OperatorUserId != "" {
+			attemptRes.OperatorName = dms.GetUserNameWithDelTag(attempt.OperatorUserId)
+		}
+		for _, sql := range attempt.SQLs {
+			attemptRes.SQLs = append(attemptRes.SQLs, &TaskExecutionAttemptSQLResV1{
+				Number:     sql.Number,
+				ExecStatus: sql.ExecStatus,
+				ExecResult: sql.ExecResult,
+				RowAffects: sql.RowAffects,
+			})
+		}
+		data = append(data, attemptRes)
+	}
+	return c.JSON(http.StatusOK, &GetTaskExecutionAttemptsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
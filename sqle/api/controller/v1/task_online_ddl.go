@@ -0,0 +1,261 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	dmsV1 "github.com/actiontech/dms/pkg/dms-common/api/dms/v1"
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver/mysql/onlineddl"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+
+	"github.com/labstack/echo/v4"
+)
+
+type GetTaskGhostConfigResV1 struct {
+	controller.BaseRes
+	Data []*TaskGhostConfigResV1 `json:"data"`
+}
+
+type TaskGhostConfigResV1 struct {
+	Key  string `json:"key"`
+	Desc string `json:"desc"`
+	Type string `json:"type" enums:"string,int,bool,float64"`
+	// 任务的配置, 为空时使用数据源或配置文件的配置
+	Value string `json:"value"`
+}
+
+// GetTaskGhostConfigByWorkflowV1
+// @Summary 获取工单数据源任务的 gh-ost 配置
+// @Description get the gh-ost config of task which overrides the config of instance
+// @Tags workflow
+// @Id getTaskGhostConfigByWorkflowV1
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param workflow_id path string true "workflow id"
+// @Param task_id path string true "task id"
+// @Success 200 {object} v1.GetTaskGhostConfigResV1
+// @router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/ghost_config [get]
+func GetTaskGhostConfigByWorkflowV1(c echo.Context) error {
+	projectUid, workflow, taskId, err := getWorkflowAndTaskIdByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	err = CheckCurrentUserCanOperateTasks(c, projectUid, workflow, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeViewOthersWorkflow}, []uint{taskId})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	task, exist, err := model.GetStorage().GetTaskById(strconv.Itoa(int(taskId)))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.NewTaskNoExistOrNoAccessErr())
+	}
+	config, err := task.GetGhostConfig()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]*TaskGhostConfigResV1, 0, len(onlineddl.Options))
+	for _, o := range onlineddl.Options {
+		if o.InstanceOnly {
+			continue
+		}
+		data = append(data, &TaskGhostConfigResV1{
+			Key:   o.Key,
+			Desc:  o.Desc,
+			Type:  string(o.Type),
+			Value: config[o.Key],
+		})
+	}
+	return c.JSON(http.StatusOK, &GetTaskGhostConfigResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type UpdateTaskGhostConfigReqV1 struct {
+	// 任务的全部配置, 未包含的配置使用数据源或配置文件的配置
+	Config []*TaskGhostConfigReqV1 `json:"config" valid:"dive,required"`
+}
+
+type TaskGhostConfigReqV1 struct {
+	Key   string `json:"key" valid:"required"`
+	Value string `json:"value"`
+}
+
+// UpdateTaskGhostConfigByWorkflowV1
+// @Summary 更新工单数据源任务的 gh-ost 配置
+// @Description update the gh-ost config of task before execution, it overrides the config of instance except throttle_query, max_load and critical_load, which can only be set in the instance config
+// @Tags workflow
+// @Id updateTaskGhostConfigByWorkflowV1
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param project_name path string true "project name"
+// @Param workflow_id path string true "workflow id"
+// @Param task_id path string true "task id"
+// @Param instance body v1.UpdateTaskGhostConfigReqV1 true "update gh-ost config request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/ghost_config [patch]
+func UpdateTaskGhostConfigByWorkflowV1(c echo.Context) error {
+	req := new(UpdateTaskGhostConfigReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	projectUid, workflow, taskId, err := getWorkflowAndTaskIdByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if workflow.Record.Status != model.WorkflowStatusWaitForAudit &&
+		workflow.Record.Status != model.WorkflowStatusWaitForExecution {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("workflow status is %s, gh-ost config can not be updated", workflow.Record.Status)))
+	}
+	err = CheckCurrentUserCanOperateTasks(c, projectUid, workflow, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeExecuteWorkflow}, []uint{taskId})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	s := model.GetStorage()
+	task, exist, err := s.GetTaskById(strconv.Itoa(int(taskId)))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.NewTaskNoExistOrNoAccessErr())
+	}
+	if task.DBType != driverV2.DriverTypeMySQL {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("gh-ost only supports %s", driverV2.DriverTypeMySQL)))
+	}
+
+	config := map[string]string{}
+	for _, item := range req.Config {
+		if item.Value != "" {
+			config[item.Key] = item.Value
+		}
+	}
+	if err := onlineddl.CheckTaskConfig(config); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+	}
+	b, err := json.Marshal(config)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return controller.JSONBaseErrorReq(c, s.UpdateTask(task, map[string]interface{}{"ghost_config": model.JSON(b)}))
+}
+
+type GetTaskGhostProgressResV1 struct {
+	controller.BaseRes
+	Data []*TaskGhostProgressResV1 `json:"data"`
+}
+
+type TaskGhostProgressResV1 struct {
+	Number       uint    `json:"number"`
+	Table        string  `json:"table"`
+	State        string  `json:"state" enums:"migrating,throttled,postponing cut-over"`
+	RowsCopied   int64   `json:"rows_copied"`
+	RowsEstimate int64   `json:"rows_estimate"`
+	ProgressPct  float64 `json:"progress_pct"`
+	// 预计剩余时间, 单位秒, -1 表示未知
+	ETASeconds         int64   `json:"eta_seconds"`
+	LagSeconds         float64 `json:"lag_seconds"`
+	Throttled          bool    `json:"throttled"`
+	ThrottleReason     string  `json:"throttle_reason"`
+	CanPostponeCutOver bool    `json:"can_postpone_cut_over"`
+	PostponingCutOver  bool    `json:"postponing_cut_over"`
+	ElapsedSeconds     int64   `json:"elapsed_seconds"`
+}
+
+// GetTaskGhostProgressByWorkflowV1
+// @Summary 获取工单数据源任务正在执行的 gh-ost 进度
+// @Description get the progress of gh-ost migrations running in the task, the progress is also saved to the exec result of SQL periodically
+// @Tags workflow
+// @Id getTaskGhostProgressByWorkflowV1
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param workflow_id path string true "workflow id"
+// @Param task_id path string true "task id"
+// @Success 200 {object} v1.GetTaskGhostProgressResV1
+// @router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/ghost_progress [get]
+func GetTaskGhostProgressByWorkflowV1(c echo.Context) error {
+	projectUid, workflow, taskId, err := getWorkflowAndTaskIdByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	err = CheckCurrentUserCanOperateTasks(c, projectUid, workflow, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeViewOthersWorkflow}, []uint{taskId})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	progress := server.GetGhostProgress(taskId)
+	data := make([]*TaskGhostProgressResV1, 0, len(progress))
+	for _, p := range progress {
+		data = append(data, &TaskGhostProgressResV1{
+			Number:             p.Number,
+			Table:              p.Table,
+			State:              p.State,
+			RowsCopied:         p.RowsCopied,
+			RowsEstimate:       p.RowsEstimate,
+			ProgressPct:        p.ProgressPct,
+			ETASeconds:         p.ETASeconds,
+			LagSeconds:         p.LagSeconds,
+			Throttled:          p.Throttled,
+			ThrottleReason:     p.ThrottleReason,
+			CanPostponeCutOver: p.CanPostponeCutOver,
+			PostponingCutOver:  p.PostponingCutOver,
+			ElapsedSeconds:     p.ElapsedSeconds,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetTaskGhostProgressResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+type OperateTaskGhostMigrationReqV1 struct {
+	Number uint `json:"number" valid:"required"`
+	// throttle: 暂停拷贝; unthrottle: 恢复拷贝; postpone_cut_over: 推迟 cut-over; cut_over: 执行被推迟的 cut-over
+	Action string `json:"action" valid:"required,oneof=throttle unthrottle postpone_cut_over cut_over" enums:"throttle,unthrottle,postpone_cut_over,cut_over"`
+}
+
+// OperateTaskGhostMigrationByWorkflowV1
+// @Summary 操作工单数据源任务正在执行的 gh-ost 迁移
+// @Description throttle, unthrottle, postpone cut-over or cut-over the running gh-ost migration, postponing cut-over requires the task is executed with the config postpone_cut_over
+// @Tags workflow
+// @Id operateTaskGhostMigrationByWorkflowV1
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param project_name path string true "project name"
+// @Param workflow_id path string true "workflow id"
+// @Param task_id path string true "task id"
+// @Param instance body v1.OperateTaskGhostMigrationReqV1 true "operate gh-ost migration request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/ghost_actions [post]
+func OperateTaskGhostMigrationByWorkflowV1(c echo.Context) error {
+	req := new(OperateTaskGhostMigrationReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	projectUid, workflow, taskId, err := getWorkflowAndTaskIdByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	err = CheckCurrentUserCanOperateTasks(c, projectUid, workflow, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeExecuteWorkflow}, []uint{taskId})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	if err := server.OperateGhostMigration(taskId, req.Number, req.Action); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+	}
+	return controller.JSONBaseErrorReq(c, nil)
+}
@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	dmsV1 "github.com/actiontech/dms/pkg/dms-common/api/dms/v1"
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+
+	"github.com/labstack/echo/v4"
+)
+
+// getWorkflowAndTaskIdByParam returns the workflow and checks the task belongs to it.
+func getWorkflowAndTaskIdByParam(c echo.Context) (projectUid string, workflow *model.Workflow, taskId uint, err error) {
+	projectUid, err = dms.GetPorjectUIDByName(context.TODO(), c.Param("project_name"), true)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	id, err := strconv.Atoi(c.Param("task_id"))
+	if err != nil {
+		return "", nil, 0, errors.New(errors.DataInvalid, fmt.Errorf("invalid task id: %v", c.Param("task_id")))
+	}
+	taskId = uint(id)
+
+	s := model.GetStorage()
+	workflow, err = dms.GetWorkflowDetailByWorkflowId(projectUid, c.Param("workflow_id"), s.GetWorkflowDetailWithoutInstancesByWorkflowID)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	for _, record := range workflow.Record.InstanceRecords {
+		if record.TaskId == taskId {
+			return projectUid, workflow, taskId, nil
+		}
+	}
+	return "", nil, 0, errors.New(errors.DataNotExist, fmt.Errorf("task %v is not in workflow", taskId))
+}
+
+// RehearseTaskByWorkflowV1
+// @Summary 演练工单数据源任务
+// @Description rehearse the SQLs of approved task before execution, DMLs are executed in a transaction which is always rolled back and ALTER TABLEs are executed by gh-ost with dry-run
+// @Tags workflow
+// @Id rehearseTaskByWorkflowV1
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param workflow_id path string true "workflow id"
+// @Param task_id path string true "task id"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/rehearse [post]
+func RehearseTaskByWorkflowV1(c echo.Context) error {
+	projectUid, workflow, taskId, err := getWorkflowAndTaskIdByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	// the SQLs are executed on the instance in rehearsal, only the approved
+	// workflow can be rehearsed.
+	if workflow.Record.Status != model.WorkflowStatusWaitForExecution {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("workflow status is %s, only the approved workflow can be rehearsed", workflow.Record.Status)))
+	}
+
+	err = CheckCurrentUserCanOperateTasks(c, projectUid, workflow, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeExecuteWorkflow}, []uint{taskId})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	err = server.GetSqled().AddTask(strconv.Itoa(int(taskId)), server.ActionTypeRehearsal)
+	return c.JSON(http.StatusOK, controller.NewBaseReq(err))
+}
+
+type GetTaskRehearsalResV1 struct {
+	controller.BaseRes
+	Data *TaskRehearsalResV1 `json:"data"`
+}
+
+type TaskRehearsalResV1 struct {
+	Status        string                   `json:"status" enums:"rehearsing,succeeded,failed"`
+	RehearsedAt   *time.Time               `json:"rehearsed_at,omitempty"`
+	RehearsalSQLs []*TaskRehearsalSQLResV1 `json:"rehearsal_sql_list"`
+}
+
+type TaskRehearsalSQLResV1 struct {
+	Number       uint   `json:"number"`
+	SQL          string `json:"sql"`
+	Method       string `json:"method" enums:"transaction,gh-ost"`
+	Status       string `json:"status" enums:"succeeded,failed,skipped"`
+	RowsAffected int64  `json:"rows_affected"`
+	// 执行耗时，单位毫秒
+	ExecutionTime uint64 `json:"execution_time"`
+	Message       string `json:"message"`
+}
+
+// GetTaskRehearsalByWorkflowV1
+// @Summary 获取工单数据源任务的演练报告
+// @Description get the rehearsal report of task
+// @Tags workflow
+// @Id getTaskRehearsalByWorkflowV1
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param workflow_id path string true "workflow id"
+// @Param task_id path string true "task id"
+// @Success 200 {object} v1.GetTaskRehearsalResV1
+// @router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/rehearsal [get]
+func GetTaskRehearsalByWorkflowV1(c echo.Context) error {
+	projectUid, workflow, taskId, err := getWorkflowAndTaskIdByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	err = CheckCurrentUserCanOperateTasks(c, projectUid, workflow, []dmsV1.OpPermissionType{dmsV1.OpPermissionTypeViewOthersWorkflow}, []uint{taskId})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	s := model.GetStorage()
+	task, exist, err := s.GetTaskById(strconv.Itoa(int(taskId)))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.NewTaskNoExistOrNoAccessErr())
+	}
+	rehearsalSQLs, err := s.GetRehearsalSQLsByTaskId(taskId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := &TaskRehearsalResV1{
+		Status:        task.RehearsalStatus,
+		RehearsedAt:   task.RehearsedAt,
+		RehearsalSQLs: make([]*TaskRehearsalSQLResV1, 0, len(rehearsalSQLs)),
+	}
+	for _, rehearsalSQL := range rehearsalSQLs {
+		data.RehearsalSQLs = append(data.RehearsalSQLs, &TaskRehearsalSQLResV1{
+			Number:        rehearsalSQL.Number,
+			SQL:           rehearsalSQL.Content,
+			Method:        rehearsalSQL.Method,
+			Status:        rehearsalSQL.Status,
+			RowsAffected:  rehearsalSQL.RowsAffected,
+			ExecutionTime: rehearsalSQL.ExecutionTime,
+			Message:       rehearsalSQL.Message,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetTaskRehearsalResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
@@ -1,10 +1,14 @@
 package v1
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/actiontech/sqle/sqle/model"
 
 	"github.com/labstack/echo/v4"
 )
@@ -58,3 +62,83 @@ func GetUserTips(c echo.Context) error {
 		Data:    userTipsRes,
 	})
 }
+
+type GetUserPreferenceResV1 struct {
+	controller.BaseRes
+	Data *UserPreferenceResV1 `json:"data"`
+}
+
+type UserPreferenceResV1 struct {
+	// 用户选择的语言, 未选择时为空
+	Language string `json:"language" example:"en"`
+	// 当前生效的语言
+	EffectiveLanguage  string   `json:"effective_language" example:"en"`
+	SupportedLanguages []string `json:"supported_languages"`
+}
+
+// @Summary 获取当前用户的偏好设置
+// @Description get the preference of current user
+// @Tags user
+// @Id getUserPreferenceV1
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetUserPreferenceResV1
+// @router /v1/user/preference [get]
+func GetUserPreference(c echo.Context) error {
+	preference, _, err := model.GetStorage().GetUserPreference(controller.GetUserID(c))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	res := &UserPreferenceResV1{
+		EffectiveLanguage:  controller.GetLanguage(c),
+		SupportedLanguages: locale.SupportedLangs,
+	}
+	if preference != nil {
+		res.Language = preference.Language
+	}
+	return c.JSON(http.StatusOK, &GetUserPreferenceResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    res,
+	})
+}
+
+type UpdateUserPreferenceReqV1 struct {
+	// 为空时使用请求头 Accept-Language 或系统默认语言
+	Language *string `json:"language" example:"en"`
+}
+
+// @Summary 更新当前用户的偏好设置
+// @Description update the preference of current user
+// @Tags user
+// @Id updateUserPreferenceV1
+// @Security ApiKeyAuth
+// @Accept json
+// @Param instance body v1.UpdateUserPreferenceReqV1 true "update user preference request"
+// @Success 200 {object} controller.BaseRes
+// @router /v1/user/preference [patch]
+func UpdateUserPreference(c echo.Context) error {
+	req := new(UpdateUserPreferenceReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	userId := controller.GetUserID(c)
+	preference, exist, err := s.GetUserPreference(userId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		preference = &model.UserPreference{UserId: userId}
+	}
+	if req.Language != nil {
+		lang := ""
+		if *req.Language != "" {
+			lang = locale.Normalize(*req.Language)
+			if lang == "" {
+				return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+					fmt.Errorf("language %v is not supported, supported languages are %v", *req.Language, locale.SupportedLangs)))
+			}
+		}
+		preference.Language = lang
+	}
+	return controller.JSONBaseErrorReq(c, s.SaveUserPreference(preference))
+}
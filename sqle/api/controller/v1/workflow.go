@@ -360,8 +360,12 @@ func PrepareForTaskExecution(c echo.Context, projectID string, workflow *model.W
 		return err
 	}
 
+	return checkUserIsTaskExecutionAssignee(workflow, user, uint(TaskId))
+}
+
+func checkUserIsTaskExecutionAssignee(workflow *model.Workflow, user *model.User, taskId uint) error {
 	for _, record := range workflow.Record.InstanceRecords {
-		if record.TaskId != uint(TaskId) {
+		if record.TaskId != taskId {
 			continue
 		}
 
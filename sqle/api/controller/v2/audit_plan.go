@@ -214,6 +214,7 @@ func GetAuditPlanReportSQLs(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
+	lang := controller.GetLanguage(c)
 	auditPlanReportSQLsRes := make([]*AuditPlanReportSQLResV2, len(auditPlanReportSQLs))
 	for i, auditPlanReportSQL := range auditPlanReportSQLs {
 		auditPlanReportSQLsRes[i] = &AuditPlanReportSQLResV2{
@@ -224,7 +225,7 @@ func GetAuditPlanReportSQLs(c echo.Context) error {
 			ar := auditPlanReportSQL.AuditResults[j]
 			auditPlanReportSQLsRes[i].AuditResult = append(auditPlanReportSQLsRes[i].AuditResult, &AuditResult{
 				Level:    ar.Level,
-				Message:  ar.Message,
+				Message:  ar.GetMessage(lang),
 				RuleName: ar.RuleName,
 				DbType:   ap.DBType,
 			})
@@ -309,6 +310,8 @@ type AuditPlanSQLReqV2 struct {
 	FirstQueryAt         time.Time `json:"first_query_at" from:"first_query_at" example:"2023-09-12T02:48:01.317880Z"`
 	DBUser               string    `json:"db_user" from:"db_user" example:"database_user001"`
 	Endpoints            []string  `json:"endpoints" from:"endpoints"`
+	FilePath             string    `json:"file_path" from:"file_path" example:"src/main/java/com/example/UserDao.java"`
+	StartLine            uint64    `json:"start_line" from:"start_line" example:"12"`
 }
 
 func filterSQLsByBlackList(sqls []*AuditPlanSQLReqV2, blackList []*model.BlackListAuditPlanSQL) []*AuditPlanSQLReqV2 {
@@ -398,6 +401,10 @@ func convertToModelAuditPlanSQL(c echo.Context, auditPlan *model.AuditPlan, reqS
 		if reqSQL.DBUser != "" {
 			info["db_user"] = reqSQL.DBUser
 		}
+		if reqSQL.FilePath != "" {
+			info["file_path"] = reqSQL.FilePath
+			info["start_line"] = reqSQL.StartLine
+		}
 		sqls = append(sqls, &auditplan.SQL{
 			Fingerprint: fp,
 			SQLContent:  reqSQL.LastReceiveText,
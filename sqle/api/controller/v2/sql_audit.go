@@ -38,6 +38,8 @@ type AuditSQLResV2 struct {
 	ExecSQL     string        `json:"exec_sql"`
 	AuditResult []AuditResult `json:"audit_result"`
 	AuditLevel  string        `json:"audit_level"`
+	// SQL 在提交内容中的起始行号
+	StartLine uint64 `json:"start_line"`
 }
 
 type DirectAuditResV2 struct {
@@ -70,7 +72,7 @@ func DirectAudit(c echo.Context) error {
 
 	l := log.NewEntry().WithField(c.Path(), "direct audit failed")
 
-	task, err := server.AuditSQLByDBType(l, sql, req.InstanceType, req.ProjectId, req.RuleTemplateName)
+	task, err := server.AuditSQLByDBType(c.Request().Context(), l, sql, req.InstanceType, req.ProjectId, req.RuleTemplateName)
 	if err != nil {
 		l.Errorf("audit sqls failed: %v", err)
 		return controller.JSONBaseErrorReq(c, v1.ErrDirectAudit)
@@ -78,29 +80,32 @@ func DirectAudit(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, DirectAuditResV2{
 		BaseRes: controller.BaseRes{},
-		Data:    convertTaskResultToAuditResV2(task),
+		Data:    convertTaskResultToAuditResV2(task, controller.GetLanguage(c)),
 	})
 }
 
-func convertTaskResultToAuditResV2(task *model.Task) *AuditResDataV2 {
+func convertTaskResultToAuditResV2(task *model.Task, lang string) *AuditResDataV2 {
 	results := make([]AuditSQLResV2, len(task.ExecuteSQLs))
 	for i, sql := range task.ExecuteSQLs {
 
 		ar := make([]*AuditResult, len(sql.AuditResults))
 		for j := range sql.AuditResults {
 			ar[j] = &AuditResult{
-				Level:    sql.AuditResults[j].Level,
-				Message:  sql.AuditResults[j].Message,
-				RuleName: sql.AuditResults[j].RuleName,
-				DbType:   task.DBType,
+				Level:          sql.AuditResults[j].Level,
+				Message:        sql.AuditResults[j].GetMessage(lang),
+				RuleName:       sql.AuditResults[j].RuleName,
+				DbType:         task.DBType,
+				Suppressed:     sql.AuditResults[j].Suppressed,
+				SuppressReason: sql.AuditResults[j].SuppressReason,
 			}
 		}
 
 		results[i] = AuditSQLResV2{
 			Number:      sql.Number,
 			ExecSQL:     sql.Content,
-			AuditResult: convertAuditResultToAuditResV2(sql.AuditResults),
+			AuditResult: convertAuditResultToAuditResV2(sql.AuditResults, lang),
 			AuditLevel:  sql.AuditLevel,
+			StartLine:   sql.StartLine,
 		}
 
 	}
@@ -187,9 +192,9 @@ func DirectAuditFiles(c echo.Context) error {
 
 	var task *model.Task
 	if instance != nil && schemaName != "" {
-		task, err = server.DirectAuditByInstance(l, sqls, schemaName, instance)
+		task, err = server.DirectAuditByInstance(c.Request().Context(), l, sqls, schemaName, instance)
 	} else {
-		task, err = server.AuditSQLByDBType(l, sqls, req.InstanceType, "", "")
+		task, err = server.AuditSQLByDBType(c.Request().Context(), l, sqls, req.InstanceType, "", "")
 	}
 	if err != nil {
 		l.Errorf("audit sqls failed: %v", err)
@@ -209,18 +214,19 @@ func DirectAuditFiles(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, DirectAuditResV2{
 		BaseRes: controller.BaseRes{},
-		Data:    convertFileAuditTaskResultToAuditResV2(task),
+		Data:    convertFileAuditTaskResultToAuditResV2(task, controller.GetLanguage(c)),
 	})
 }
 
-func convertFileAuditTaskResultToAuditResV2(task *model.Task) *AuditResDataV2 {
+func convertFileAuditTaskResultToAuditResV2(task *model.Task, lang string) *AuditResDataV2 {
 	results := make([]AuditSQLResV2, len(task.ExecuteSQLs))
 	for i, sql := range task.ExecuteSQLs {
 		results[i] = AuditSQLResV2{
 			Number:      sql.Number,
 			ExecSQL:     sql.Content,
-			AuditResult: convertAuditResultToAuditResV2(sql.AuditResults),
+			AuditResult: convertAuditResultToAuditResV2(sql.AuditResults, lang),
 			AuditLevel:  sql.AuditLevel,
+			StartLine:   sql.StartLine,
 		}
 
 	}
@@ -232,13 +238,15 @@ func convertFileAuditTaskResultToAuditResV2(task *model.Task) *AuditResDataV2 {
 	}
 }
 
-func convertAuditResultToAuditResV2(auditResults model.AuditResults) []AuditResult {
+func convertAuditResultToAuditResV2(auditResults model.AuditResults, lang string) []AuditResult {
 	ar := make([]AuditResult, len(auditResults))
 	for i := range auditResults {
 		ar[i] = AuditResult{
-			Level:    auditResults[i].Level,
-			Message:  auditResults[i].Message,
-			RuleName: auditResults[i].RuleName,
+			Level:          auditResults[i].Level,
+			Message:        auditResults[i].GetMessage(lang),
+			RuleName:       auditResults[i].RuleName,
+			Suppressed:     auditResults[i].Suppressed,
+			SuppressReason: auditResults[i].SuppressReason,
 		}
 	}
 	return ar
@@ -6,6 +6,7 @@ import (
 	"github.com/actiontech/sqle/sqle/api/controller"
 	v1 "github.com/actiontech/sqle/sqle/api/controller/v1"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/gitdiff"
 
 	"github.com/labstack/echo/v4"
 )
@@ -39,13 +40,17 @@ type AuditTaskSQLResV2 struct {
 	RollbackSQL   string         `json:"rollback_sql,omitempty"`
 	Description   string         `json:"description"`
 	SQLType       string         `json:"sql_type"`
+	// 从 git 仓库审核时为 SQL 所在文件的提交链接
+	SQLSourceURL string `json:"sql_source_url,omitempty"`
 }
 
 type AuditResult struct {
-	Level    string `json:"level" example:"warn"`
-	Message  string `json:"message" example:"避免使用不必要的内置函数md5()"`
-	RuleName string `json:"rule_name"`
-	DbType   string `json:"db_type"`
+	Level          string `json:"level" example:"warn"`
+	Message        string `json:"message" example:"避免使用不必要的内置函数md5()"`
+	RuleName       string `json:"rule_name"`
+	DbType         string `json:"db_type"`
+	Suppressed     bool   `json:"suppressed"`
+	SuppressReason string `json:"suppress_reason,omitempty"`
 }
 
 // @Summary 获取指定扫描任务的SQLs信息
@@ -97,6 +102,18 @@ func GetTaskSQLs(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
+	var gitRecord *model.SQLAuditRecord
+	if task.SQLSource == model.TaskSQLSourceFromGitRepository {
+		record, exist, err := s.GetSQLAuditRecordByTaskId(task.ID)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if exist {
+			gitRecord = record
+		}
+	}
+
+	lang := controller.GetLanguage(c)
 	taskSQLsRes := make([]*AuditTaskSQLResV2, 0, len(taskSQLs))
 	for _, taskSQL := range taskSQLs {
 		taskSQLRes := &AuditTaskSQLResV2{
@@ -112,13 +129,19 @@ func GetTaskSQLs(c echo.Context) error {
 			RollbackSQL:   taskSQL.RollbackSQL.String,
 			SQLType:       taskSQL.SQLType.String,
 		}
+		if gitRecord != nil {
+			taskSQLRes.SQLSourceURL = gitdiff.FileURL(gitRecord.GitRepository, gitRecord.GitHeadCommit,
+				taskSQL.SQLSourceFile.String, taskSQL.SQLStartLine)
+		}
 		for i := range taskSQL.AuditResults {
 			ar := taskSQL.AuditResults[i]
 			taskSQLRes.AuditResult = append(taskSQLRes.AuditResult, &AuditResult{
-				Level:    ar.Level,
-				Message:  ar.Message,
-				RuleName: ar.RuleName,
-				DbType:   task.DBType,
+				Level:          ar.Level,
+				Message:        ar.GetMessage(lang),
+				RuleName:       ar.RuleName,
+				DbType:         task.DBType,
+				Suppressed:     ar.Suppressed,
+				SuppressReason: ar.SuppressReason,
 			})
 		}
 
@@ -38,6 +38,11 @@ type WorkflowStepResV2 struct {
 	Reason        string     `json:"reason,omitempty"`
 }
 
+type ApproveWorkflowReqV2 struct {
+	// 项目要求审批人确认 SQL 中通过注释忽略的审核结果时必须为 true
+	AcknowledgeSuppressions bool `json:"acknowledge_suppressions" form:"acknowledge_suppressions"`
+}
+
 // @Summary 审批通过
 // @Description approve workflow
 // @Tags workflow
@@ -46,9 +51,15 @@ type WorkflowStepResV2 struct {
 // @Param workflow_id path string true "workflow id"
 // @Param workflow_step_id path string true "workflow step id"
 // @Param project_name path string true "project name"
+// @param workflow_approve body v2.ApproveWorkflowReqV2 false "workflow approve request"
 // @Success 200 {object} controller.BaseRes
 // @router /v2/projects/{project_name}/workflows/{workflow_id}/steps/{workflow_step_id}/approve [post]
 func ApproveWorkflowV2(c echo.Context) error {
+	req := new(ApproveWorkflowReqV2)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
 	projectUid, err := dms.GetPorjectUIDByName(c.Request().Context(), c.Param("project_name"), true)
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
@@ -86,7 +97,16 @@ func ApproveWorkflowV2(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
 	}
 
-	if err := server.ApproveWorkflowProcess(workflow, user, s); err != nil {
+	unacknowledged, err := server.GetUnacknowledgedSuppressions(workflow)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if len(unacknowledged) > 0 && !req.AcknowledgeSuppressions {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("%d SQLs have audit results ignored by comment, the approver should acknowledge them", len(unacknowledged))))
+	}
+
+	if err := server.ApproveWorkflowProcess(workflow, user, s, unacknowledged); err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
 
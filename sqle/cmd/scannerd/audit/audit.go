@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	mybatisParser "github.com/actiontech/mybatis-mapper-2-sql"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+	"github.com/actiontech/sqle/sqle/utils"
+)
+
+const (
+	SQLTypeSQL     = "sql"
+	SQLTypeMyBatis = "mybatis"
+)
+
+// File is a SQL file or MyBatis XML file to be audited.
+type File struct {
+	// Path is relative to the working directory if possible, it is the path
+	// shown in the reports so that CI can annotate the file.
+	Path    string
+	SQLType string
+	Content string
+
+	// changed is set by GitFilter, it returns false if the SQL is not changed.
+	changed func(line uint64, sql string) bool
+	// stmtLines is set by GitFilter, only the MyBatis statements start from
+	// the lines are audited.
+	stmtLines map[uint64]struct{}
+}
+
+// CollectFiles returns the SQL files and MyBatis XML files in the path, the
+// path can be a file or a directory. sqlType filters the files, empty means both.
+func CollectFiles(path string, sqlType string) ([]*File, error) {
+	files := []*File{}
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		var fileType string
+		switch {
+		case strings.HasSuffix(info.Name(), utils.SQLFileSuffix):
+			fileType = SQLTypeSQL
+		case strings.HasSuffix(info.Name(), utils.MybatisFileSuffix):
+			fileType = SQLTypeMyBatis
+		default:
+			return nil
+		}
+		if sqlType != "" && sqlType != fileType {
+			return nil
+		}
+		content, err := common.ReadFileContent(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, &File{
+			Path:    reportPath(p),
+			SQLType: fileType,
+			Content: content,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+func reportPath(p string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if abs, err := filepath.Abs(p); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				p = rel
+			}
+		}
+	}
+	return filepath.ToSlash(p)
+}
+
+// alignMyBatisSQLs extracts the SQLs from MyBatis XML, each SQL is placed on
+// the line of its statement in XML, so that the start line of SQL returned by
+// SQLE is the line in XML file. All statements are extracted if stmtLines is nil.
+func alignMyBatisSQLs(content string, skipErrorQuery bool, stmtLines map[uint64]struct{}) (string, error) {
+	stmts, err := mybatisParser.ParseXMLs([]mybatisParser.XmlFile{{Content: content}}, skipErrorQuery)
+	if err != nil {
+		return "", err
+	}
+	buf := strings.Builder{}
+	line := uint64(1)
+	for _, stmt := range stmts {
+		if _, ok := stmtLines[stmt.StartLine]; stmtLines != nil && !ok {
+			continue
+		}
+		if line > 1 {
+			buf.WriteString("\n")
+			line++
+		}
+		for ; line < stmt.StartLine; line++ {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(stmt.SQL)
+		buf.WriteString(";")
+		line += uint64(strings.Count(stmt.SQL, "\n"))
+	}
+	return buf.String(), nil
+}
+
+type Params struct {
+	InstanceType   string
+	InstanceName   string
+	SchemaName     string
+	SkipErrorQuery bool
+}
+
+// Auditor audits the files by the direct audit API of SQLE.
+type Auditor struct {
+	c      *scanner.Client
+	params *Params
+}
+
+func NewAuditor(c *scanner.Client, params *Params) *Auditor {
+	return &Auditor{c: c, params: params}
+}
+
+func (a *Auditor) Audit(ctx context.Context, files []*File) (*Report, error) {
+	report := &Report{}
+	for _, f := range files {
+		fr, err := a.auditFile(ctx, f)
+		if err != nil {
+			return nil, fmt.Errorf("audit file %s error: %v", f.Path, err)
+		}
+		report.Files = append(report.Files, fr)
+	}
+	return report, nil
+}
+
+func (a *Auditor) auditFile(ctx context.Context, f *File) (*FileResult, error) {
+	fr := &FileResult{Path: f.Path}
+	content := f.Content
+	if f.SQLType == SQLTypeMyBatis {
+		var err error
+		content, err = alignMyBatisSQLs(f.Content, a.params.SkipErrorQuery, f.stmtLines)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if strings.TrimSpace(content) == "" {
+		return fr, nil
+	}
+
+	req := &scanner.DirectAuditFileReq{
+		InstanceType: a.params.InstanceType,
+		FileContents: []string{content},
+		SQLType:      SQLTypeSQL,
+	}
+	if a.params.InstanceName != "" {
+		req.InstanceName = &a.params.InstanceName
+	}
+	if a.params.SchemaName != "" {
+		req.SchemaName = &a.params.SchemaName
+	}
+	data, err := a.c.DirectAuditFilesReq(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for _, sql := range data.SQLResults {
+		if f.changed != nil && !f.changed(sql.StartLine, sql.ExecSQL) {
+			continue
+		}
+		sr := &SQLResult{
+			Number: sql.Number,
+			Line:   sqlLine(sql.StartLine, sql.ExecSQL),
+			SQL:    strings.TrimSpace(sql.ExecSQL),
+		}
+		for _, result := range sql.AuditResult {
+			// the suppressed result is ignored by the annotation in SQL.
+			if result.Suppressed || !driverV2.RuleLevel(result.Level).More(driverV2.RuleLevelNormal) {
+				continue
+			}
+			sr.Findings = append(sr.Findings, &Finding{
+				RuleName: result.RuleName,
+				Level:    driverV2.RuleLevel(result.Level),
+				Message:  result.Message,
+			})
+		}
+		fr.SQLs = append(fr.SQLs, sr)
+	}
+	return fr, nil
+}
+
+// sqlLine returns the line of the first statement token, the start line of SQL
+// is the line of its leading comments.
+func sqlLine(startLine uint64, sql string) uint64 {
+	if startLine == 0 {
+		startLine = 1
+	}
+	sql = strings.TrimLeft(sql, " \t\r\n")
+	for {
+		switch {
+		case strings.HasPrefix(sql, "--"), strings.HasPrefix(sql, "#"):
+			idx := strings.Index(sql, "\n")
+			if idx < 0 {
+				return startLine
+			}
+			sql = sql[idx:]
+		case strings.HasPrefix(sql, "/*"):
+			idx := strings.Index(sql, "*/")
+			if idx < 0 {
+				return startLine
+			}
+			startLine += uint64(strings.Count(sql[:idx], "\n"))
+			sql = sql[idx+2:]
+		default:
+			return startLine
+		}
+		trimmed := strings.TrimLeft(sql, " \t\r\n")
+		startLine += uint64(strings.Count(sql[:len(sql)-len(trimmed)], "\n"))
+		sql = trimmed
+	}
+}
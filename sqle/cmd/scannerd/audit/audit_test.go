@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v2 "github.com/actiontech/sqle/sqle/api/controller/v2"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMyBatisXML = `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="Test">
+    <select id="getUser">
+        SELECT * FROM users
+    </select>
+
+    <delete id="deleteUser">
+        DELETE FROM users
+    </delete>
+</mapper>`
+
+func TestAlignMyBatisSQLs(t *testing.T) {
+	content, err := alignMyBatisSQLs(testMyBatisXML, false, nil)
+	assert.NoError(t, err)
+	lines := strings.Split(content, "\n")
+	assert.Equal(t, "SELECT * FROM `users`;", lines[2])
+	assert.Equal(t, "DELETE FROM `users`;", lines[6])
+}
+
+func TestSqlLine(t *testing.T) {
+	assert.Equal(t, uint64(1), sqlLine(0, "select 1"))
+	assert.Equal(t, uint64(3), sqlLine(3, "\nselect 1"))
+	assert.Equal(t, uint64(4), sqlLine(3, "\n-- comment\nselect 1"))
+	assert.Equal(t, uint64(7), sqlLine(3, "/* a\n b */\n# c\n\nselect 1"))
+	assert.Equal(t, uint64(3), sqlLine(3, "-- comment only"))
+}
+
+func TestCollectFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "mapper"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.sql"), []byte("select 1;"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mapper", "a.xml"), []byte(testMyBatisXML), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("select 1;"), 0644))
+
+	files, err := CollectFiles(dir, "")
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	types := map[string]string{}
+	for _, f := range files {
+		types[filepath.Base(f.Path)] = f.SQLType
+	}
+	assert.Equal(t, map[string]string{"b.sql": SQLTypeSQL, "a.xml": SQLTypeMyBatis}, types)
+
+	files, err = CollectFiles(dir, SQLTypeMyBatis)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	_, err = CollectFiles(filepath.Join(dir, "not_exist"), "")
+	assert.Error(t, err)
+}
+
+func TestAuditor(t *testing.T) {
+	var req scanner.DirectAuditFileReq
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, scanner.DirectAuditFiles, r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		_ = json.NewEncoder(w).Encode(&scanner.DirectAuditRes{Data: &scanner.AuditResData{
+			AuditLevel: "error",
+			SQLResults: []v2.AuditSQLResV2{
+				{Number: 1, ExecSQL: "SELECT * FROM users;", StartLine: 3, AuditResult: []v2.AuditResult{
+					{Level: "error", RuleName: "dml_check_select_with_asterisk", Message: "禁止使用 SELECT *"},
+					{Level: "warn", RuleName: "dml_check_where_is_invalid", Message: "suppressed", Suppressed: true},
+				}},
+				{Number: 2, ExecSQL: "\nDELETE FROM users;", StartLine: 7, AuditResult: []v2.AuditResult{
+					{Level: "normal", RuleName: "", Message: "白名单"},
+				}},
+			},
+		}})
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	c := scanner.NewSQLEClient(time.Second, u.Hostname(), u.Port()).WithToken("token").WithProject("p1")
+	auditor := NewAuditor(c, &Params{InstanceType: "MySQL", SchemaName: "db1"})
+	report, err := auditor.Audit(context.TODO(), []*File{{Path: "mapper/a.xml", SQLType: SQLTypeMyBatis, Content: testMyBatisXML}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "p1", req.ProjectName)
+	assert.Equal(t, SQLTypeSQL, req.SQLType)
+	assert.Nil(t, req.InstanceName)
+	assert.Equal(t, "db1", *req.SchemaName)
+	assert.Len(t, req.FileContents, 1)
+
+	assert.Len(t, report.Files, 1)
+	fr := report.Files[0]
+	assert.Equal(t, "mapper/a.xml", fr.Path)
+	assert.Len(t, fr.SQLs, 2)
+	assert.Equal(t, uint64(3), fr.SQLs[0].Line)
+	assert.Equal(t, []*Finding{{RuleName: "dml_check_select_with_asterisk", Level: driverV2.RuleLevelError, Message: "禁止使用 SELECT *"}}, fr.SQLs[0].Findings)
+	assert.Equal(t, "DELETE FROM users;", fr.SQLs[1].SQL)
+	assert.Len(t, fr.SQLs[1].Findings, 0)
+	assert.Equal(t, driverV2.RuleLevelError, report.Level())
+}
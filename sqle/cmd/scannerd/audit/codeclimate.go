@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+// codeClimateIssue is the issue of Code Climate, which is used by GitLab code
+// quality, see https://docs.gitlab.com/ee/ci/testing/code_quality.html
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin uint64 `json:"begin"`
+}
+
+func codeClimateSeverity(level driverV2.RuleLevel) string {
+	switch level {
+	case driverV2.RuleLevelError:
+		return "critical"
+	case driverV2.RuleLevelWarn:
+		return "major"
+	default:
+		return "minor"
+	}
+}
+
+// WriteCodeClimate writes the report in Code Climate JSON.
+func (r *Report) WriteCodeClimate(w io.Writer) error {
+	issues := []codeClimateIssue{}
+	for _, file := range r.Files {
+		for _, sql := range file.SQLs {
+			for _, f := range sql.Findings {
+				issues = append(issues, codeClimateIssue{
+					Type:        "issue",
+					CheckName:   f.ruleId(),
+					Description: f.Message,
+					Categories:  []string{"Bug Risk"},
+					Severity:    codeClimateSeverity(f.Level),
+					Fingerprint: fingerprint(file.Path, sql, f),
+					Location: codeClimateLocation{
+						Path:  file.Path,
+						Lines: codeClimateLines{Begin: sql.Line},
+					},
+				})
+			}
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
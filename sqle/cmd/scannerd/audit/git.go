@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	xmlParser "github.com/actiontech/mybatis-mapper-2-sql"
+	"github.com/actiontech/sqle/sqle/pkg/gitdiff"
+
+	goGit "github.com/go-git/go-git/v5"
+)
+
+// GitFilter keeps the SQLs changed from the merge base of base revision and
+// HEAD of the git repository which contains the working directory.
+type GitFilter struct {
+	diff *gitdiff.Diff
+	root string
+	// RepositoryURL is the URL of remote origin, it is empty if there is no origin.
+	RepositoryURL string
+}
+
+func NewGitFilter(ctx context.Context, base string) (*GitFilter, error) {
+	repo, err := goGit.PlainOpenWithOptions(".", &goGit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("open git repository failed: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	diff, err := gitdiff.Compute(ctx, repo, base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	f := &GitFilter{diff: diff, root: wt.Filesystem.Root()}
+	if remote, err := repo.Remote(goGit.DefaultRemoteName); err == nil && len(remote.Config().URLs) > 0 {
+		f.RepositoryURL = gitdiff.RepositoryURL(remote.Config().URLs[0])
+	}
+	return f, nil
+}
+
+func (g *GitFilter) HeadCommit() string {
+	return g.diff.HeadCommit
+}
+
+func (g *GitFilter) repoPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(g.root, abs)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Filter returns the files which have changed SQLs, the SQLs of MyBatis XML are
+// filtered by the statements before audit, and the SQLs of SQL file are filtered
+// by lines after audit.
+func (g *GitFilter) Filter(files []*File) ([]*File, error) {
+	xmls := []xmlParser.XmlFile{}
+	xmlFiles := map[string]*File{}
+	changedFiles := []*File{}
+	for _, f := range files {
+		repoPath := g.repoPath(f.Path)
+		if f.SQLType == SQLTypeMyBatis {
+			// all XML files are parsed for the SQL fragments referenced across files.
+			xmls = append(xmls, xmlParser.XmlFile{FilePath: repoPath, Content: f.Content})
+			xmlFiles[repoPath] = f
+			continue
+		}
+		if _, ok := g.diff.File(repoPath); ok {
+			f.changed = func(line uint64, sql string) bool {
+				return g.diff.SQLChanged(repoPath, line, sql)
+			}
+			changedFiles = append(changedFiles, f)
+		}
+	}
+	if len(xmls) == 0 {
+		return changedFiles, nil
+	}
+
+	stmts, err := g.diff.ChangedMyBatisStmts(xmls)
+	if err != nil {
+		return nil, err
+	}
+	stmtLines := map[string]map[uint64]struct{}{}
+	for _, stmt := range stmts {
+		if stmtLines[stmt.FilePath] == nil {
+			stmtLines[stmt.FilePath] = map[uint64]struct{}{}
+		}
+		stmtLines[stmt.FilePath][stmt.StartLine] = struct{}{}
+	}
+	for _, x := range xmls {
+		lines, ok := stmtLines[x.FilePath]
+		if !ok {
+			continue
+		}
+		f := xmlFiles[x.FilePath]
+		f.stmtLines = lines
+		changedFiles = append(changedFiles, f)
+	}
+	sort.Slice(changedFiles, func(i, j int) bool {
+		return changedFiles[i].Path < changedFiles[j].Path
+	})
+	return changedFiles, nil
+}
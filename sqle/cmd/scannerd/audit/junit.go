@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      uint64        `xml:"line,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report in JUnit XML, each SQL is a test case which is
+// failed if it has findings whose level is not less than failLevel.
+func (r *Report) WriteJUnit(w io.Writer, failLevel driverV2.RuleLevel) error {
+	suites := junitTestSuites{Name: "SQLE"}
+	for _, file := range r.Files {
+		suite := junitTestSuite{Name: file.Path}
+		for _, sql := range file.SQLs {
+			tc := junitTestCase{
+				Name:      fmt.Sprintf("%s:%d", file.Path, sql.Line),
+				ClassName: file.Path,
+				File:      file.Path,
+				Line:      sql.Line,
+			}
+			failures, others := []string{}, []string{}
+			level := driverV2.RuleLevelNull
+			for _, f := range sql.Findings {
+				text := fmt.Sprintf("[%s] %s: %s", f.Level, f.ruleId(), f.Message)
+				if failLevel != driverV2.RuleLevelNull && f.Level.MoreOrEqual(failLevel) {
+					failures = append(failures, text)
+					if f.Level.More(level) {
+						level = f.Level
+					}
+				} else {
+					others = append(others, text)
+				}
+			}
+			if len(failures) > 0 {
+				tc.Failure = &junitFailure{
+					Message: failures[0],
+					Type:    string(level),
+					Text:    fmt.Sprintf("%s\n\n%s", sql.SQL, strings.Join(failures, "\n")),
+				}
+				suite.Failures++
+			}
+			if len(others) > 0 {
+				tc.SystemOut = strings.Join(others, "\n")
+			}
+			suite.Tests++
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(&suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
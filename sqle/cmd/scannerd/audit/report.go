@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+// Report is the audit result of files, it is written in the formats which CI
+// platforms can show as annotations.
+type Report struct {
+	Files []*FileResult
+	// RepositoryURL and Commit are the git revision of files, they are set if
+	// the files are filtered by git diff.
+	RepositoryURL string
+	Commit        string
+}
+
+type FileResult struct {
+	Path string
+	SQLs []*SQLResult
+}
+
+type SQLResult struct {
+	Number   uint
+	Line     uint64
+	SQL      string
+	Findings []*Finding
+}
+
+type Finding struct {
+	RuleName string
+	Level    driverV2.RuleLevel
+	Message  string
+}
+
+const (
+	FormatSARIF       = "sarif"
+	FormatJUnit       = "junit"
+	FormatCodeClimate = "codeclimate"
+)
+
+// defaultRuleId is used for the result without rule, e.g. the SQL syntax error.
+const defaultRuleId = "sqle"
+
+func (f *Finding) ruleId() string {
+	if f.RuleName == "" {
+		return defaultRuleId
+	}
+	return f.RuleName
+}
+
+// fingerprint identifies the finding regardless of its line, so that the finding
+// is not reported as new one after the lines above it are changed.
+func fingerprint(path string, sql *SQLResult, f *Finding) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s", path, f.ruleId(), strings.Join(strings.Fields(sql.SQL), " "), f.Message)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Level returns the highest level of findings.
+func (r *Report) Level() driverV2.RuleLevel {
+	level := driverV2.RuleLevelNull
+	for _, file := range r.Files {
+		for _, sql := range file.SQLs {
+			for _, f := range sql.Findings {
+				if f.Level.More(level) {
+					level = f.Level
+				}
+			}
+		}
+	}
+	return level
+}
+
+// Count returns the number of findings whose level is not less than the level.
+func (r *Report) Count(level driverV2.RuleLevel) int {
+	count := 0
+	for _, file := range r.Files {
+		for _, sql := range file.SQLs {
+			for _, f := range sql.Findings {
+				if f.Level.MoreOrEqual(level) {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// Write writes the report in format to the file.
+func (r *Report) Write(format, file, version string, failLevel driverV2.RuleLevel) (err error) {
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return r.write(out, format, version, failLevel)
+}
+
+func (r *Report) write(w io.Writer, format, version string, failLevel driverV2.RuleLevel) error {
+	switch format {
+	case FormatSARIF:
+		return r.WriteSARIF(w, version)
+	case FormatJUnit:
+		return r.WriteJUnit(w, failLevel)
+	case FormatCodeClimate:
+		return r.WriteCodeClimate(w)
+	default:
+		return fmt.Errorf("report format %s is not supported", format)
+	}
+}
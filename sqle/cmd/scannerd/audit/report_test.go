@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"path/filepath"
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestReport() *Report {
+	return &Report{Files: []*FileResult{
+		{Path: "db/a.sql", SQLs: []*SQLResult{
+			{Number: 1, Line: 1, SQL: "select * from t1;", Findings: []*Finding{
+				{RuleName: "dml_check_select_with_asterisk", Level: driverV2.RuleLevelError, Message: "禁止使用 SELECT *"},
+				{RuleName: "dml_check_select_limit", Level: driverV2.RuleLevelNotice, Message: "建议使用 LIMIT"},
+			}},
+			{Number: 2, Line: 3, SQL: "select id from t1;"},
+		}},
+		{Path: "mapper/b.xml", SQLs: []*SQLResult{
+			{Number: 1, Line: 5, SQL: "delete from t2;", Findings: []*Finding{
+				{RuleName: "", Level: driverV2.RuleLevelWarn, Message: "schema not exist"},
+			}},
+		}},
+	}}
+}
+
+func TestReportLevel(t *testing.T) {
+	r := newTestReport()
+	assert.Equal(t, driverV2.RuleLevelError, r.Level())
+	assert.Equal(t, 1, r.Count(driverV2.RuleLevelError))
+	assert.Equal(t, 2, r.Count(driverV2.RuleLevelWarn))
+	assert.Equal(t, 3, r.Count(driverV2.RuleLevelNotice))
+	assert.Equal(t, driverV2.RuleLevelNull, (&Report{}).Level())
+}
+
+func TestWriteSARIF(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, newTestReport().WriteSARIF(buf, "v3.0.0"))
+
+	log := sarifLog{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "v3.0.0", run.Tool.Driver.Version)
+	assert.Len(t, run.Tool.Driver.Rules, 3)
+	assert.Len(t, run.Results, 3)
+
+	assert.Equal(t, "dml_check_select_with_asterisk", run.Results[0].RuleId)
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "note", run.Results[1].Level)
+	assert.Equal(t, "sqle", run.Results[2].RuleId)
+	assert.Equal(t, 2, run.Results[2].RuleIndex)
+	assert.Equal(t, "warning", run.Results[2].Level)
+	location := run.Results[2].Locations[0].PhysicalLocation
+	assert.Equal(t, "mapper/b.xml", location.ArtifactLocation.Uri)
+	assert.Equal(t, uint64(5), location.Region.StartLine)
+	assert.NotEmpty(t, run.Results[0].PartialFingerprints["sqleFinding/v1"])
+	assert.Empty(t, run.VersionControlProvenance)
+
+	r := newTestReport()
+	r.RepositoryURL = "https://github.com/actiontech/demo.git"
+	r.Commit = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4"
+	buf.Reset()
+	assert.NoError(t, r.WriteSARIF(buf, "v3.0.0"))
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, []sarifVersionControlDetails{{
+		RepositoryUri: r.RepositoryURL,
+		RevisionId:    r.Commit,
+	}}, log.Runs[0].VersionControlProvenance)
+}
+
+func TestWriteCodeClimate(t *testing.T) {
+	r := newTestReport()
+	buf := &bytes.Buffer{}
+	assert.NoError(t, r.WriteCodeClimate(buf))
+
+	issues := []codeClimateIssue{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+	assert.Len(t, issues, 3)
+	assert.Equal(t, "critical", issues[0].Severity)
+	assert.Equal(t, "minor", issues[1].Severity)
+	assert.Equal(t, "major", issues[2].Severity)
+	assert.Equal(t, "db/a.sql", issues[0].Location.Path)
+	assert.Equal(t, uint64(1), issues[0].Location.Lines.Begin)
+	assert.NotEqual(t, issues[0].Fingerprint, issues[1].Fingerprint)
+
+	// the fingerprint does not change with the line.
+	r.Files[0].SQLs[0].Line = 10
+	buf.Reset()
+	assert.NoError(t, r.WriteCodeClimate(buf))
+	moved := []codeClimateIssue{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &moved))
+	assert.Equal(t, issues[0].Fingerprint, moved[0].Fingerprint)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, newTestReport().WriteJUnit(buf, driverV2.RuleLevelWarn))
+
+	suites := junitTestSuites{}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 2, suites.Failures)
+	assert.Len(t, suites.Suites, 2)
+
+	cases := suites.Suites[0].TestCases
+	assert.Equal(t, "db/a.sql:1", cases[0].Name)
+	assert.Equal(t, "error", cases[0].Failure.Type)
+	assert.Contains(t, cases[0].SystemOut, "dml_check_select_limit")
+	assert.Nil(t, cases[1].Failure)
+
+	// nothing fails if fail level is none.
+	buf.Reset()
+	assert.NoError(t, newTestReport().WriteJUnit(buf, driverV2.RuleLevelNull))
+	suites = junitTestSuites{}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	assert.Equal(t, 0, suites.Failures)
+}
+
+func TestReportWrite(t *testing.T) {
+	dir := t.TempDir()
+	r := newTestReport()
+	for _, format := range []string{FormatSARIF, FormatJUnit, FormatCodeClimate} {
+		assert.NoError(t, r.Write(format, filepath.Join(dir, format), "", driverV2.RuleLevelError))
+	}
+	assert.Error(t, r.Write("html", filepath.Join(dir, "html"), "", driverV2.RuleLevelError))
+}
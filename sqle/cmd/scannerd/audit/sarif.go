@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+// The subset of SARIF 2.1.0, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool                     sarifTool                    `json:"tool"`
+	Results                  []sarifResult                `json:"results"`
+	VersionControlProvenance []sarifVersionControlDetails `json:"versionControlProvenance,omitempty"`
+}
+
+type sarifVersionControlDetails struct {
+	RepositoryUri string `json:"repositoryUri"`
+	RevisionId    string `json:"revisionId,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine uint64 `json:"startLine"`
+}
+
+func sarifLevel(level driverV2.RuleLevel) string {
+	switch level {
+	case driverV2.RuleLevelError:
+		return "error"
+	case driverV2.RuleLevelWarn:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// WriteSARIF writes the report in SARIF, which is used by GitHub code scanning.
+func (r *Report) WriteSARIF(w io.Writer, version string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "SQLE",
+			InformationUri: "https://github.com/actiontech/sqle",
+			Version:        version,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	if r.RepositoryURL != "" {
+		run.VersionControlProvenance = []sarifVersionControlDetails{{
+			RepositoryUri: r.RepositoryURL,
+			RevisionId:    r.Commit,
+		}}
+	}
+	ruleIndex := map[string]int{}
+	for _, file := range r.Files {
+		for _, sql := range file.SQLs {
+			for _, f := range sql.Findings {
+				idx, ok := ruleIndex[f.ruleId()]
+				if !ok {
+					idx = len(run.Tool.Driver.Rules)
+					ruleIndex[f.ruleId()] = idx
+					run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+						Id:               f.ruleId(),
+						ShortDescription: sarifMessage{Text: f.ruleId()},
+					})
+				}
+				run.Results = append(run.Results, sarifResult{
+					RuleId:    f.ruleId(),
+					RuleIndex: idx,
+					Level:     sarifLevel(f.Level),
+					Message:   sarifMessage{Text: f.Message},
+					Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{Uri: file.Path},
+						Region:           sarifRegion{StartLine: sql.Line},
+					}}},
+					PartialFingerprints: map[string]string{
+						"sqleFinding/v1": fingerprint(file.Path, sql, f),
+					},
+				})
+			}
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/audit"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const failLevelNone = "none"
+
+var (
+	auditCmdFlags struct {
+		path            string
+		sqlType         string
+		instanceType    string
+		instanceName    string
+		schemaName      string
+		failLevel       string
+		sarifFile       string
+		junitFile       string
+		codeClimateFile string
+		gitBase         string
+		skipErrorQuery  bool
+	}
+
+	auditCmd = &cobra.Command{
+		Use:   "audit",
+		Short: "Audit sql files and MyBatis XML files for CI",
+		Run: func(cmd *cobra.Command, args []string) {
+			failLevel, err := parseFailLevel(auditCmdFlags.failLevel)
+			if err != nil {
+				fmt.Println(color.RedString(err.Error()))
+				os.Exit(1)
+			}
+			files, err := audit.CollectFiles(auditCmdFlags.path, auditCmdFlags.sqlType)
+			if err != nil {
+				fmt.Println(color.RedString(err.Error()))
+				os.Exit(1)
+			}
+			var gitFilter *audit.GitFilter
+			if auditCmdFlags.gitBase != "" {
+				gitFilter, err = audit.NewGitFilter(cmd.Context(), auditCmdFlags.gitBase)
+				if err != nil {
+					fmt.Println(color.RedString(err.Error()))
+					os.Exit(1)
+				}
+				files, err = gitFilter.Filter(files)
+				if err != nil {
+					fmt.Println(color.RedString(err.Error()))
+					os.Exit(1)
+				}
+			}
+
+			client := scanner.NewSQLEClient(time.Second*time.Duration(rootCmdFlags.timeout), rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token).WithProject(rootCmdFlags.project)
+			auditor := audit.NewAuditor(client, &audit.Params{
+				InstanceType:   auditCmdFlags.instanceType,
+				InstanceName:   auditCmdFlags.instanceName,
+				SchemaName:     auditCmdFlags.schemaName,
+				SkipErrorQuery: auditCmdFlags.skipErrorQuery,
+			})
+			report, err := auditor.Audit(cmd.Context(), files)
+			if err != nil {
+				fmt.Println(color.RedString(err.Error()))
+				os.Exit(1)
+			}
+			if gitFilter != nil {
+				report.RepositoryURL = gitFilter.RepositoryURL
+				report.Commit = gitFilter.HeadCommit()
+			}
+
+			version, _ := cmd.Context().Value(VersionKey).(string)
+			for format, file := range map[string]string{
+				audit.FormatSARIF:       auditCmdFlags.sarifFile,
+				audit.FormatJUnit:       auditCmdFlags.junitFile,
+				audit.FormatCodeClimate: auditCmdFlags.codeClimateFile,
+			} {
+				if file == "" {
+					continue
+				}
+				if err := report.Write(format, file, version, failLevel); err != nil {
+					fmt.Println(color.RedString("write %s report error: %v", format, err))
+					os.Exit(1)
+				}
+			}
+
+			fmt.Printf("audited %d files, found %d errors, %d warnings, %d notices\n", len(files),
+				report.Count(driverV2.RuleLevelError),
+				report.Count(driverV2.RuleLevelWarn)-report.Count(driverV2.RuleLevelError),
+				report.Count(driverV2.RuleLevelNotice)-report.Count(driverV2.RuleLevelWarn))
+			if failLevel != driverV2.RuleLevelNull && report.Level().MoreOrEqual(failLevel) {
+				fmt.Println(color.RedString("the audit level %s reaches the fail level %s", report.Level(), failLevel))
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func parseFailLevel(level string) (driverV2.RuleLevel, error) {
+	switch l := driverV2.RuleLevel(level); l {
+	case driverV2.RuleLevelNotice, driverV2.RuleLevelWarn, driverV2.RuleLevelError:
+		return l, nil
+	case failLevelNone:
+		return driverV2.RuleLevelNull, nil
+	default:
+		return driverV2.RuleLevelNull, fmt.Errorf("fail level %s is invalid, it should be one of notice, warn, error and none", level)
+	}
+}
+
+func init() {
+	auditCmd.Flags().StringVarP(&auditCmdFlags.path, "path", "D", "", "sql file or xml file, or the directory of them")
+	auditCmd.Flags().StringVarP(&auditCmdFlags.sqlType, "sql-type", "", "", "only audit the files of the type, sql or mybatis, audit both if it is empty")
+	auditCmd.Flags().StringVarP(&auditCmdFlags.instanceType, "instance-type", "", driverV2.DriverTypeMySQL, "instance type of sql")
+	auditCmd.Flags().StringVarP(&auditCmdFlags.instanceName, "instance-name", "", "", "audit with the instance in project, it requires schema-name")
+	auditCmd.Flags().StringVarP(&auditCmdFlags.schemaName, "schema-name", "", "", "audit with the schema of instance")
+	auditCmd.Flags().StringVarP(&auditCmdFlags.failLevel, "fail-level", "", string(driverV2.RuleLevelError), "exit with code 1 if the audit level is not less than it, one of notice, warn, error and none")
+	auditCmd.Flags().StringVarP(&auditCmdFlags.sarifFile, "sarif", "", "", "write SARIF 2.1.0 report to the file")
+	auditCmd.Flags().StringVarP(&auditCmdFlags.junitFile, "junit", "", "", "write JUnit XML report to the file")
+	auditCmd.Flags().StringVarP(&auditCmdFlags.codeClimateFile, "codeclimate", "", "", "write Code Climate JSON report to the file")
+	auditCmd.Flags().StringVarP(&auditCmdFlags.gitBase, "git-base", "", "", "only audit the sql changed from the merge base of the git revision and HEAD")
+	auditCmd.Flags().BoolVarP(&auditCmdFlags.skipErrorQuery, "skip-error-query", "S", false, "skip the statement that the scanner failed to parse from within the xml file")
+	_ = auditCmd.MarkFlagRequired("path")
+	rootCmd.AddCommand(auditCmd)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/java"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/supervisor"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	skipErrorJava bool
+
+	javaCmd = &cobra.Command{
+		Use:   "java",
+		Short: "Parse Java source file",
+		Run: func(cmd *cobra.Command, args []string) {
+			param := &java.Params{
+				Dir:            dir,
+				APName:         rootCmdFlags.auditPlanName,
+				SkipErrorQuery: skipErrorQuery,
+				SkipErrorJava:  skipErrorJava,
+				SkipAudit:      skipAudit,
+			}
+			log := logrus.WithField("scanner", "java")
+			client := scanner.NewSQLEClient(time.Second*time.Duration(rootCmdFlags.timeout), rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token).WithProject(rootCmdFlags.project)
+			scanner, err := java.New(param, log, client)
+			if err != nil {
+				fmt.Println(color.RedString(err.Error()))
+				os.Exit(1)
+			}
+
+			err = supervisor.Start(context.TODO(), scanner, 30, 1024)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+)
+
+func init() {
+	javaCmd.Flags().StringVarP(&dir, "dir", "D", "", "java source directory")
+	javaCmd.Flags().BoolVarP(&skipErrorQuery, "skip-error-query", "S", false, "skip the sql that the scanner failed to parse from within the java file")
+	javaCmd.Flags().BoolVarP(&skipErrorJava, "skip-error-java", "X", false, "skip the java file that failed to parse")
+	javaCmd.Flags().BoolVarP(&skipAudit, "skip-audit", "K", false, "only upload sql to sqle, not audit")
+	_ = javaCmd.MarkFlagRequired("dir")
+	addAuditPlanNameFlag(javaCmd)
+	rootCmd.AddCommand(javaCmd)
+}
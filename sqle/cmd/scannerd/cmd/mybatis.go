@@ -56,5 +56,6 @@ func init() {
 	mybatisCmd.Flags().BoolVarP(&skipErrorXml, "skip-error-xml", "X", false, "skip the xml file that failed to parse")
 	mybatisCmd.Flags().BoolVarP(&skipAudit, "skip-audit", "K", false, "only upload sql to sqle, not audit")
 	_ = mybatisCmd.MarkFlagRequired("dir")
+	addAuditPlanNameFlag(mybatisCmd)
 	rootCmd.AddCommand(mybatisCmd)
 }
@@ -29,15 +29,20 @@ var (
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&rootCmdFlags.host, "host", "H", "127.0.0.1", "sqle host")
 	rootCmd.PersistentFlags().StringVarP(&rootCmdFlags.port, "port", "P", "10000", "sqle port")
-	rootCmd.PersistentFlags().StringVarP(&rootCmdFlags.auditPlanName, "name", "N", "", "audit plan name")
 	rootCmd.PersistentFlags().StringVarP(&rootCmdFlags.token, "token", "A", "", "sqle token")
 	rootCmd.PersistentFlags().IntVarP(&rootCmdFlags.timeout, "timeout", "T", pkgScanner.DefaultTimeoutNum, "request sqle timeout in seconds")
 	rootCmd.PersistentFlags().StringVarP(&rootCmdFlags.project, "project", "J", "default", "project name")
-	_ = rootCmd.MarkPersistentFlagRequired("name")
 	_ = rootCmd.MarkPersistentFlagRequired("token")
 }
 
+// addAuditPlanNameFlag adds the required audit plan name flag to the command
+// which uploads SQL to audit plan.
+func addAuditPlanNameFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&rootCmdFlags.auditPlanName, "name", "N", "", "audit plan name")
+	_ = cmd.MarkFlagRequired("name")
+}
+
 func Execute(ctx context.Context) error {
 	rootCmd.SetVersionTemplate(fmt.Sprintln(ctx.Value(VersionKey)))
-	return rootCmd.Execute()
+	return rootCmd.ExecuteContext(ctx)
 }
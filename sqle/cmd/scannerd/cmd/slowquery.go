@@ -21,6 +21,7 @@ var (
 	excludeUsers   string
 	includeSchemas string
 	excludeSchemas string
+	offsetFile     string
 
 	slowlogCmd = &cobra.Command{
 		Use:   "slowquery",
@@ -33,6 +34,7 @@ var (
 				ExcludeUsers:   excludeUsers,
 				IncludeSchemas: includeSchemas,
 				ExcludeSchemas: excludeSchemas,
+				OffsetFile:     offsetFile,
 			}
 			log := logrus.WithField("scanner", "slowquery")
 			client := scanner.NewSQLEClient(time.Second*time.Duration(rootCmdFlags.timeout), rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token).WithProject(rootCmdFlags.project)
@@ -52,11 +54,13 @@ var (
 )
 
 func init() {
-	slowlogCmd.Flags().StringVarP(&logFilePath, "log-file", "", "", "log file absolute path")
+	slowlogCmd.Flags().StringVarP(&logFilePath, "log-file", "", "", "slow log file path, the file is tailed across log rotation")
 	slowlogCmd.Flags().StringVarP(&includeUsers, "include-user-list", "", "", "include mysql user list, split by \",\"")
 	slowlogCmd.Flags().StringVarP(&excludeUsers, "exclude-user-list", "", "", "exclude mysql user list, split by \",\"")
 	slowlogCmd.Flags().StringVarP(&includeSchemas, "include-schema-list", "", "", "include mysql schema list, split by \",\"")
 	slowlogCmd.Flags().StringVarP(&excludeSchemas, "exclude-schema-list", "", "", "exclude mysql schema list, split by \",\"")
+	slowlogCmd.Flags().StringVarP(&offsetFile, "offset-file", "", "", "file to save the offset of uploaded log, default is \"<log file name>.<audit plan name>.offset\" in working directory")
 	_ = slowlogCmd.MarkFlagRequired("log-file")
+	addAuditPlanNameFlag(slowlogCmd)
 	rootCmd.AddCommand(slowlogCmd)
 }
@@ -52,5 +52,6 @@ func init() {
 	sqlFileCmd.Flags().BoolVarP(&skipErrorSqlFile, "skip-error-sql-file", "S", false, "skip the sql file that failed to parse")
 	sqlFileCmd.Flags().BoolVarP(&skipAudit, "skip-sql-file-audit", "K", false, "only upload sql to sqle, not audit")
 	_ = sqlFileCmd.MarkFlagRequired("dir")
+	addAuditPlanNameFlag(sqlFileCmd)
 	rootCmd.AddCommand(sqlFileCmd)
 }
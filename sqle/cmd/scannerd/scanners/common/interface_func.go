@@ -29,6 +29,8 @@ func Upload(ctx context.Context, sqls []scanners.SQL, c *scanner.Client, apName
 			Counter:              fmt.Sprintf("%v", counterMap[sql.Fingerprint]),
 			LastReceiveText:      sql.RawText,
 			LastReceiveTimestamp: now,
+			FilePath:             sql.FilePath,
+			StartLine:            sql.StartLine,
 		})
 	}
 
@@ -0,0 +1,170 @@
+package java
+
+import (
+	"strconv"
+	"strings"
+
+	javaAntlr "github.com/actiontech/java-sql-extractor/java_antlr"
+	javaParser "github.com/actiontech/java-sql-extractor/parser"
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// SQL is the SQL extracted from Java source file.
+type SQL struct {
+	Text string
+	// StartLine is the line of the string literal which the SQL comes from, it
+	// is 0 if the literal is not found.
+	StartLine uint64
+}
+
+type literal struct {
+	raw   string
+	value string
+	line  uint64
+	used  bool
+}
+
+// ExtractSQLs extracts the SQLs from the arguments of JDBC methods, such as
+// executeQuery and prepareStatement, and from the native queries of JPA
+// annotations @Query(nativeQuery = true) and @NamedNativeQuery.
+func ExtractSQLs(file, content string) ([]SQL, error) {
+	jdbcSQLs, err := javaParser.GetSqlFromJavaFile(file)
+	if err != nil {
+		return nil, err
+	}
+	tokens := lexTokens(content)
+	literals := []*literal{}
+	for _, token := range tokens {
+		if token.GetTokenType() == javaAntlr.JavaLexerSTRING_LITERAL || token.GetTokenType() == javaAntlr.JavaLexerTEXT_BLOCK {
+			literals = append(literals, &literal{
+				raw:   strings.Trim(token.GetText(), "\""),
+				value: unquote(token.GetText()),
+				line:  uint64(token.GetLine()),
+			})
+		}
+	}
+
+	sqls := make([]SQL, 0, len(jdbcSQLs))
+	for _, sql := range jdbcSQLs {
+		// the SQL returned by extractor is the literal without quotes, the first
+		// unused literal with the same text is regarded as its source.
+		found := false
+		for _, l := range literals {
+			if !l.used && l.raw == sql {
+				l.used = true
+				found = true
+				sqls = append(sqls, SQL{Text: l.value, StartLine: l.line})
+				break
+			}
+		}
+		if !found {
+			sqls = append(sqls, SQL{Text: sql})
+		}
+	}
+	return append(sqls, annotationSQLs(tokens)...), nil
+}
+
+func lexTokens(content string) []antlr.Token {
+	lexer := javaAntlr.NewJavaLexer(antlr.NewInputStream(content))
+	lexer.RemoveErrorListeners()
+	tokens := []antlr.Token{}
+	for _, token := range lexer.GetAllTokens() {
+		if token.GetChannel() == antlr.TokenDefaultChannel {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// unquote returns the value of Java string literal or text block.
+func unquote(text string) string {
+	if strings.HasPrefix(text, `"""`) {
+		text = strings.TrimSuffix(strings.TrimPrefix(text, `"""`), `"""`)
+		return strings.TrimSpace(text)
+	}
+	value, err := strconv.Unquote(text)
+	if err != nil {
+		return strings.Trim(text, "\"")
+	}
+	return value
+}
+
+// nativeQueryAnnotations are the annotations of native SQL, and the attribute
+// which is the SQL.
+var nativeQueryAnnotations = map[string]string{
+	"Query":            "value",
+	"NamedNativeQuery": "query",
+}
+
+// annotationSQLs returns the native queries of annotations. The JPQL of @Query
+// is skipped since it is not SQL.
+func annotationSQLs(tokens []antlr.Token) []SQL {
+	sqls := []SQL{}
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].GetTokenType() != javaAntlr.JavaLexerAT {
+			continue
+		}
+		// the annotation name may be qualified, e.g. @org.springframework.data.jpa.repository.Query
+		j := i + 1
+		name := ""
+		for ; j < len(tokens); j++ {
+			if tokens[j].GetTokenType() == javaAntlr.JavaLexerIDENTIFIER {
+				name = tokens[j].GetText()
+			} else if tokens[j].GetTokenType() != javaAntlr.JavaLexerDOT {
+				break
+			}
+		}
+		sqlAttr, ok := nativeQueryAnnotations[name]
+		if !ok || j >= len(tokens) || tokens[j].GetTokenType() != javaAntlr.JavaLexerLPAREN {
+			continue
+		}
+		attrs, lines, end := annotationAttrs(tokens, j)
+		i = end
+		if name == "Query" && attrs["nativeQuery"] != "true" {
+			continue
+		}
+		if sql := attrs[sqlAttr]; sql != "" {
+			sqls = append(sqls, SQL{Text: sql, StartLine: lines[sqlAttr]})
+		}
+	}
+	return sqls
+}
+
+// annotationAttrs parses the attributes of annotation whose left parenthesis is
+// at tokens[start], the value of attribute is the concatenation of its literals.
+// It returns the index of the right parenthesis.
+func annotationAttrs(tokens []antlr.Token, start int) (attrs map[string]string, lines map[string]uint64, end int) {
+	attrs = map[string]string{}
+	lines = map[string]uint64{}
+	depth := 0
+	// the value without name is the attribute "value".
+	attr := "value"
+	for end = start; end < len(tokens); end++ {
+		token := tokens[end]
+		switch token.GetTokenType() {
+		case javaAntlr.JavaLexerLPAREN, javaAntlr.JavaLexerLBRACE:
+			depth++
+		case javaAntlr.JavaLexerRPAREN, javaAntlr.JavaLexerRBRACE:
+			depth--
+			if depth == 0 {
+				return attrs, lines, end
+			}
+		case javaAntlr.JavaLexerCOMMA:
+			if depth == 1 {
+				attr = "value"
+			}
+		case javaAntlr.JavaLexerIDENTIFIER:
+			if depth == 1 && end+1 < len(tokens) && tokens[end+1].GetTokenType() == javaAntlr.JavaLexerASSIGN {
+				attr = token.GetText()
+			}
+		case javaAntlr.JavaLexerSTRING_LITERAL, javaAntlr.JavaLexerTEXT_BLOCK:
+			if _, ok := lines[attr]; !ok {
+				lines[attr] = uint64(token.GetLine())
+			}
+			attrs[attr] += unquote(token.GetText())
+		case javaAntlr.JavaLexerBOOL_LITERAL:
+			attrs[attr] = token.GetText()
+		}
+	}
+	return attrs, lines, end
+}
@@ -0,0 +1,53 @@
+package java
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractSQLs(t *testing.T) {
+	file := "./testdata/dao/UserDao.java"
+	content, err := common.ReadFileContent(file)
+	assert.NoError(t, err)
+	sqls, err := ExtractSQLs(file, content)
+	assert.NoError(t, err)
+	assert.Equal(t, []SQL{
+		{Text: "SELECT * FROM users", StartLine: 15},
+		{Text: "DELETE FROM users WHERE id = ?", StartLine: 9},
+	}, sqls)
+
+	file = "./testdata/UserRepository.java"
+	content, err = common.ReadFileContent(file)
+	assert.NoError(t, err)
+	sqls, err = ExtractSQLs(file, content)
+	assert.NoError(t, err)
+	assert.Equal(t, []SQL{
+		{Text: "SELECT * FROM users WHERE status = ?1", StartLine: 12},
+		{Text: "UPDATE users SET status = 0\n            WHERE id = ?1", StartLine: 17},
+	}, sqls)
+}
+
+func TestAnnotationSQLs(t *testing.T) {
+	tokens := lexTokens(`
+@NamedNativeQueries({
+    @NamedNativeQuery(name = "User.count", query = "SELECT COUNT(*) FROM users"),
+    @NamedNativeQuery(name = "User.names", query = "SELECT name FROM users", resultClass = String.class)
+})
+class User {
+    @Query(value = "SELECT * FROM users", nativeQuery = false)
+    List<User> all();
+}`)
+	assert.Equal(t, []SQL{
+		{Text: "SELECT COUNT(*) FROM users", StartLine: 3},
+		{Text: "SELECT name FROM users", StartLine: 4},
+	}, annotationSQLs(tokens))
+}
+
+func TestUnquote(t *testing.T) {
+	assert.Equal(t, `SELECT * FROM t WHERE a = "b"`, unquote(`"SELECT * FROM t WHERE a = \"b\""`))
+	assert.Equal(t, "SELECT 1", unquote(`"""
+        SELECT 1
+        """`))
+}
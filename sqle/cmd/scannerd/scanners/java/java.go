@@ -0,0 +1,148 @@
+package java
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+	"github.com/actiontech/sqle/sqle/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Java struct {
+	l *logrus.Entry
+	c *scanner.Client
+
+	sqls []scanners.SQL
+
+	allSQL []scanners.SQL
+	getAll chan struct{}
+
+	apName         string
+	dir            string
+	skipErrorQuery bool
+	skipErrorJava  bool
+	skipAudit      bool
+}
+
+type Params struct {
+	Dir            string
+	APName         string
+	SkipErrorQuery bool
+	SkipErrorJava  bool
+	SkipAudit      bool
+}
+
+func New(params *Params, l *logrus.Entry, c *scanner.Client) (*Java, error) {
+	return &Java{
+		dir:            params.Dir,
+		apName:         params.APName,
+		skipErrorQuery: params.SkipErrorQuery,
+		skipErrorJava:  params.SkipErrorJava,
+		skipAudit:      params.SkipAudit,
+		l:              l,
+		c:              c,
+		getAll:         make(chan struct{}),
+	}, nil
+}
+
+func (j *Java) Run(ctx context.Context) error {
+	sqls, err := j.getSQLFromPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	j.allSQL = sqls
+	close(j.getAll)
+
+	<-ctx.Done()
+	return nil
+}
+
+func (j *Java) getSQLFromPath(ctx context.Context) ([]scanners.SQL, error) {
+	allSQL := []scanners.SQL{}
+	err := filepath.Walk(j.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), "."+utils.JavaFileSuffix) {
+			return nil
+		}
+		sqls, err := j.getSQLFromFile(ctx, path)
+		if err != nil {
+			if !j.skipErrorJava {
+				return fmt.Errorf("parse file %s error: %v", path, err)
+			}
+			fmt.Printf("[parse %s file error] parse file %s error: %v\n", utils.JavaFileSuffix, path, err)
+		}
+		allSQL = append(allSQL, sqls...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allSQL, nil
+}
+
+func (j *Java) getSQLFromFile(ctx context.Context, path string) ([]scanners.SQL, error) {
+	content, err := common.ReadFileContent(path)
+	if err != nil {
+		return nil, err
+	}
+	extracted, err := ExtractSQLs(path, content)
+	if err != nil {
+		return nil, err
+	}
+	sqls := make([]scanners.SQL, 0, len(extracted))
+	for _, sql := range extracted {
+		nodes, err := common.Parse(ctx, sql.Text)
+		if err != nil {
+			if !j.skipErrorQuery {
+				return nil, fmt.Errorf("parse sql at line %d error: %v", sql.StartLine, err)
+			}
+			fmt.Printf("[parse sql error] parse sql in file %s at line %d error: %v\n", path, sql.StartLine, err)
+			continue
+		}
+		for _, node := range nodes {
+			sqls = append(sqls, scanners.SQL{
+				Fingerprint: node.Fingerprint,
+				RawText:     node.Text,
+				FilePath:    filepath.ToSlash(path),
+				StartLine:   sql.StartLine,
+			})
+		}
+	}
+	return sqls, nil
+}
+
+func (j *Java) SQLs() <-chan scanners.SQL {
+	// todo: channel size configurable
+	sqlCh := make(chan scanners.SQL, 10240)
+
+	go func() {
+		<-j.getAll
+		for _, sql := range j.allSQL {
+			sqlCh <- sql
+		}
+		close(sqlCh)
+	}()
+	return sqlCh
+}
+
+func (j *Java) Upload(ctx context.Context, sqls []scanners.SQL) error {
+	j.sqls = append(j.sqls, sqls...)
+	err := common.Upload(ctx, j.sqls, j.c, j.apName)
+	if err != nil {
+		return err
+	}
+	if j.skipAudit {
+		return nil
+	}
+	return common.Audit(j.c, j.apName)
+}
@@ -0,0 +1,42 @@
+package java
+
+import (
+	"context"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJava(t *testing.T) {
+	params := &Params{
+		Dir: "./not-exist-directory/",
+	}
+	scanner, err := New(params, logrus.New().WithField("test", "test"), nil)
+	assert.NoError(t, err)
+
+	err = scanner.Run(context.TODO())
+	assert.Error(t, err)
+
+	params = &Params{
+		Dir: "./testdata/",
+	}
+	scanner, err = New(params, logrus.New().WithField("test", "test"), nil)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scanner.Run(ctx)
+
+	sqlBuf := []scanners.SQL{}
+	for v := range scanner.SQLs() {
+		sqlBuf = append(sqlBuf, v)
+	}
+	assert.Len(t, sqlBuf, 4)
+	assert.Equal(t, "testdata/UserRepository.java", sqlBuf[0].FilePath)
+	assert.Equal(t, uint64(12), sqlBuf[0].StartLine)
+	assert.Equal(t, "SELECT * FROM users WHERE status = ?1", sqlBuf[0].RawText)
+	assert.Equal(t, "testdata/dao/UserDao.java", sqlBuf[3].FilePath)
+	assert.Equal(t, uint64(9), sqlBuf[3].StartLine)
+}
@@ -0,0 +1,22 @@
+package com.example.repository;
+
+import java.util.List;
+
+import org.springframework.data.jpa.repository.JpaRepository;
+import org.springframework.data.jpa.repository.Query;
+
+public interface UserRepository extends JpaRepository<User, Long> {
+    @Query("SELECT u FROM User u WHERE u.name = ?1")
+    List<User> findByName(String name);
+
+    @Query(value = "SELECT * FROM users " +
+            "WHERE status = ?1",
+            nativeQuery = true)
+    List<User> findByStatus(int status);
+
+    @org.springframework.data.jpa.repository.Query(nativeQuery = true, value = """
+            UPDATE users SET status = 0
+            WHERE id = ?1
+            """)
+    void disable(long id);
+}
@@ -0,0 +1,23 @@
+package com.example.dao;
+
+import java.sql.Connection;
+import java.sql.PreparedStatement;
+import java.sql.ResultSet;
+import java.sql.Statement;
+
+public class UserDao {
+    private static final String DELETE_USER = "DELETE FROM users WHERE id = ?";
+
+    private Connection conn;
+
+    public ResultSet listUsers() throws Exception {
+        Statement stmt = conn.createStatement();
+        return stmt.executeQuery("SELECT * FROM users");
+    }
+
+    public void deleteUser(long id) throws Exception {
+        PreparedStatement stmt = conn.prepareStatement(DELETE_USER);
+        stmt.setLong(1, id);
+        stmt.executeUpdate();
+    }
+}
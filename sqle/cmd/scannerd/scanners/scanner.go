@@ -15,6 +15,8 @@ type SQL struct {
 	DBUser      string    // 执行SQL的用户
 	Endpoint    string    // 下发SQL的端点信息
 	RowExamined float64   // 扫描行数
+	FilePath    string    // SQL 所在的源文件
+	StartLine   uint64    // SQL 在源文件中的起始行号
 }
 
 // Scanner is a interface for all Scanners.
@@ -0,0 +1,56 @@
+package slowquery
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// position is the position of log file which has been uploaded, it is saved
+// on disk so that the scanner resumes from it after restart.
+type position struct {
+	LogFile string `json:"log_file"`
+	Offset  int64  `json:"offset"`
+}
+
+// loadOffset returns 0 if the position is not saved or it is not of the log file.
+func loadOffset(offsetFile, logFile string) (int64, error) {
+	data, err := os.ReadFile(offsetFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	pos := position{}
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return 0, err
+	}
+	if pos.LogFile != logFile {
+		return 0, nil
+	}
+	return pos.Offset, nil
+}
+
+// saveOffset writes the position to a temporary file and renames it, so that
+// the offset file is not corrupted if the scanner exits while writing.
+func saveOffset(offsetFile, logFile string, offset int64) error {
+	data, err := json.Marshal(&position{LogFile: logFile, Offset: offset})
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(offsetFile), filepath.Base(offsetFile)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), offsetFile)
+}
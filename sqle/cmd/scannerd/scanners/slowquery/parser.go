@@ -0,0 +1,179 @@
+package slowquery
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// event is an entry of MySQL slow log, e.g.
+//
+//	# Time: 2023-09-12T02:48:01.317880Z
+//	# User@Host: root[root] @ localhost [127.0.0.1]  Id:     8
+//	# Query_time: 2.000180  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 0
+//	use db1;
+//	SET timestamp=1694486881;
+//	select sleep(2);
+type event struct {
+	query       string
+	user        string
+	endpoint    string
+	schema      string
+	queryAt     time.Time
+	queryTime   float64
+	rowExamined float64
+	// admin is true if the entry is an administrator command, e.g. Quit.
+	admin bool
+	// offset is the offset of file to resume reading after the event.
+	offset int64
+}
+
+var (
+	userHostRe  = regexp.MustCompile(`^# User@Host: ([^\[]*)\[([^\]]*)\] @ (\S*)\s*\[([^\]]*)\]`)
+	metricRe    = regexp.MustCompile(`(\w+): (\S+)`)
+	useRe       = regexp.MustCompile(`(?i)^use\s+` + "`?" + `([^` + "`" + `;\s]+)`)
+	timestampRe = regexp.MustCompile(`(?i)^SET timestamp=(\d+);$`)
+)
+
+// parser parses the lines of slow log into events.
+type parser struct {
+	ev       *event
+	queryLen int
+	query    strings.Builder
+}
+
+// feed returns the previous event if the line starts a new event.
+func (p *parser) feed(l line) *event {
+	text := strings.TrimRight(l.text, "\r")
+	if isMetaLine(text) {
+		return nil
+	}
+	if strings.HasPrefix(text, "#") {
+		var done *event
+		if p.queryLen > 0 && isHeaderLine(text) {
+			done = p.flush(true)
+		}
+		if p.queryLen > 0 {
+			// the comments in query, e.g. "# explain:" of MariaDB
+			return done
+		}
+		if p.ev == nil {
+			p.ev = &event{}
+		}
+		p.parseHeader(text)
+		p.ev.offset = l.offset
+		return done
+	}
+	if p.ev == nil {
+		// the query without header is incomplete, it is written before the
+		// offset where the scanner starts.
+		return nil
+	}
+	if p.queryLen == 0 {
+		if m := useRe.FindStringSubmatch(text); m != nil {
+			p.ev.schema = m[1]
+			p.ev.offset = l.offset
+			return nil
+		}
+		if m := timestampRe.FindStringSubmatch(text); m != nil {
+			if p.ev.queryAt.IsZero() {
+				if ts, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+					p.ev.queryAt = time.Unix(ts, 0)
+				}
+			}
+			p.ev.offset = l.offset
+			return nil
+		}
+	}
+	if p.queryLen > 0 {
+		p.query.WriteString("\n")
+	}
+	p.query.WriteString(text)
+	p.queryLen++
+	p.ev.offset = l.offset
+	return nil
+}
+
+// flush returns the pending event. If force is false, the event is returned
+// only if its query is terminated by semicolon, since MySQL writes the whole
+// entry at once.
+func (p *parser) flush(force bool) *event {
+	if p.ev == nil || p.queryLen == 0 {
+		return nil
+	}
+	query := strings.TrimSpace(p.query.String())
+	if !force && !strings.HasSuffix(query, ";") {
+		return nil
+	}
+	ev := p.ev
+	ev.query = strings.TrimSpace(strings.TrimSuffix(query, ";"))
+	p.ev = nil
+	p.queryLen = 0
+	p.query.Reset()
+	return ev
+}
+
+func (p *parser) parseHeader(text string) {
+	switch {
+	case strings.HasPrefix(text, "# Time:"):
+		p.ev.queryAt = parseTime(strings.TrimSpace(strings.TrimPrefix(text, "# Time:")))
+	case strings.HasPrefix(text, "# User@Host:"):
+		if m := userHostRe.FindStringSubmatch(text); m != nil {
+			p.ev.user = strings.TrimSpace(m[1])
+			if p.ev.user == "" {
+				p.ev.user = m[2]
+			}
+			p.ev.endpoint = m[4]
+			if p.ev.endpoint == "" {
+				p.ev.endpoint = m[3]
+			}
+		}
+	case strings.HasPrefix(text, "# administrator command:"):
+		p.ev.admin = true
+		// the command is the query of event.
+		p.query.WriteString(text)
+		p.queryLen++
+	default:
+		for _, m := range metricRe.FindAllStringSubmatch(text, -1) {
+			switch m[1] {
+			case "Query_time":
+				p.ev.queryTime, _ = strconv.ParseFloat(m[2], 64)
+			case "Rows_examined":
+				p.ev.rowExamined, _ = strconv.ParseFloat(m[2], 64)
+			case "Schema":
+				p.ev.schema = m[2]
+			}
+		}
+	}
+}
+
+func isHeaderLine(text string) bool {
+	return strings.HasPrefix(text, "# Time:") ||
+		strings.HasPrefix(text, "# User@Host:") ||
+		strings.HasPrefix(text, "# Query_time:")
+}
+
+// isMetaLine returns true for the lines written when the log is opened, e.g.
+//
+//	/usr/sbin/mysqld, Version: 8.0.33 (MySQL Community Server - GPL). started with:
+//	Tcp port: 3306  Unix socket: /var/run/mysqld/mysqld.sock
+//	Time                 Id Command    Argument
+func isMetaLine(text string) bool {
+	return (strings.HasPrefix(text, "/") && strings.HasSuffix(text, "started with:")) ||
+		strings.HasPrefix(text, "Tcp port:") ||
+		strings.HasPrefix(text, "TCP Port:") ||
+		(strings.HasPrefix(text, "Time ") && strings.HasSuffix(text, "Argument"))
+}
+
+// parseTime parses the time in the format of MySQL 5.7 and later, e.g.
+// "2023-09-12T02:48:01.317880Z", or MySQL 5.6, e.g. "230912  2:48:01".
+func parseTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation("060102 15:04:05", strings.Join(strings.Fields(s), " "), time.Local); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,88 @@
+package slowquery
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSlowLog = `/usr/sbin/mysqld, Version: 8.0.33 (MySQL Community Server - GPL). started with:
+Tcp port: 3306  Unix socket: /var/run/mysqld/mysqld.sock
+Time                 Id Command    Argument
+# Time: 2023-09-12T02:48:01.317880Z
+# User@Host: root[root] @ localhost [127.0.0.1]  Id:     8
+# Query_time: 2.000180  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 10
+use db1;
+SET timestamp=1694486881;
+select sleep(2);
+# Time: 2023-09-12T02:49:01.000000Z
+# User@Host: app[app] @  [10.0.0.5]  Id:     9
+# Query_time: 1.5  Lock_time: 0.000000 Rows_sent: 0  Rows_examined: 100
+SET timestamp=1694486941;
+UPDATE t1
+SET a = 1
+WHERE id = 2;
+# Time: 2023-09-12T02:50:01.000000Z
+# User@Host: app[app] @  [10.0.0.5]  Id:     9
+# Query_time: 0.5  Lock_time: 0.000000 Rows_sent: 0  Rows_examined: 0
+SET timestamp=1694487001;
+# administrator command: Quit;
+# Time: 230912  2:51:01
+# User@Host: app[app] @ app-host []  Id:     9
+# Query_time: 3.000000  Lock_time: 0.000000 Rows_sent: 0  Rows_examined: 0
+SET timestamp=1694487061;
+select * from t2`
+
+func parseLines(p *parser, content string) []*event {
+	events := []*event{}
+	offset := int64(0)
+	for _, text := range strings.Split(content, "\n") {
+		offset += int64(len(text)) + 1
+		if ev := p.feed(line{text: text, offset: offset}); ev != nil {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+func TestParser(t *testing.T) {
+	p := &parser{}
+	events := parseLines(p, testSlowLog)
+	assert.Len(t, events, 3)
+
+	assert.Equal(t, "select sleep(2)", events[0].query)
+	assert.Equal(t, "root", events[0].user)
+	assert.Equal(t, "127.0.0.1", events[0].endpoint)
+	assert.Equal(t, "db1", events[0].schema)
+	assert.Equal(t, 2.000180, events[0].queryTime)
+	assert.Equal(t, float64(10), events[0].rowExamined)
+	assert.Equal(t, time.Date(2023, 9, 12, 2, 48, 1, 317880000, time.UTC), events[0].queryAt)
+	assert.Equal(t, int64(strings.Index(testSlowLog, "# Time: 2023-09-12T02:49")), events[0].offset)
+
+	assert.Equal(t, "UPDATE t1\nSET a = 1\nWHERE id = 2", events[1].query)
+	assert.Equal(t, "app", events[1].user)
+	assert.Equal(t, "10.0.0.5", events[1].endpoint)
+	assert.Equal(t, "", events[1].schema)
+
+	assert.True(t, events[2].admin)
+
+	// the last query is not terminated, it may be still being written.
+	assert.Nil(t, p.flush(false))
+	ev := p.flush(true)
+	if assert.NotNil(t, ev) {
+		assert.Equal(t, "select * from t2", ev.query)
+		assert.Equal(t, "app-host", ev.endpoint)
+		assert.Equal(t, time.Date(2023, 9, 12, 2, 51, 1, 0, time.Local), ev.queryAt)
+		assert.Equal(t, int64(len(testSlowLog)+1), ev.offset)
+	}
+	assert.Nil(t, p.flush(true))
+}
+
+func TestParserStartInQuery(t *testing.T) {
+	p := &parser{}
+	events := parseLines(p, "WHERE id = 2;\n"+testSlowLog[strings.Index(testSlowLog, "# Time: 2023-09-12T02:49"):])
+	assert.Len(t, events, 2)
+	assert.Equal(t, "UPDATE t1\nSET a = 1\nWHERE id = 2", events[0].query)
+}
@@ -0,0 +1,296 @@
+// Package slowquery tails the MySQL slow log on the host of database, it is the
+// same in community and enterprise editions, so it is not gated by build tags.
+package slowquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SlowQuery tails the MySQL slow log file and uploads the slow queries to the
+// audit plan, the queries are aggregated by fingerprint in each upload.
+type SlowQuery struct {
+	l *logrus.Entry
+	c *scanner.Client
+
+	sqlCh chan scanners.SQL
+
+	mu sync.Mutex
+	// offsets are the offsets of log file after the SQLs sent to sqlCh, the
+	// offset is saved when the SQLs are uploaded.
+	offsets []int64
+
+	apName         string
+	logFilePath    string
+	offsetFile     string
+	pollInterval   time.Duration
+	includeUsers   map[string]struct{}
+	excludeUsers   map[string]struct{}
+	includeSchemas map[string]struct{}
+	excludeSchemas map[string]struct{}
+}
+
+type Params struct {
+	LogFilePath    string
+	APName         string
+	IncludeUsers   string
+	ExcludeUsers   string
+	IncludeSchemas string
+	ExcludeSchemas string
+	// OffsetFile saves the offset of log file which has been uploaded, it is
+	// "<log file name>.<audit plan name>.offset" in working directory if empty.
+	OffsetFile   string
+	PollInterval time.Duration
+}
+
+func New(params *Params, l *logrus.Entry, c *scanner.Client) (*SlowQuery, error) {
+	logFilePath, err := filepath.Abs(params.LogFilePath)
+	if err != nil {
+		return nil, err
+	}
+	offsetFile := params.OffsetFile
+	if offsetFile == "" {
+		offsetFile = fmt.Sprintf("%s.%s.offset", filepath.Base(logFilePath), params.APName)
+	}
+	pollInterval := params.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	return &SlowQuery{
+		l:              l,
+		c:              c,
+		sqlCh:          make(chan scanners.SQL, 10240),
+		apName:         params.APName,
+		logFilePath:    logFilePath,
+		offsetFile:     offsetFile,
+		pollInterval:   pollInterval,
+		includeUsers:   splitList(params.IncludeUsers),
+		excludeUsers:   splitList(params.ExcludeUsers),
+		includeSchemas: splitList(params.IncludeSchemas),
+		excludeSchemas: splitList(params.ExcludeSchemas),
+	}, nil
+}
+
+func splitList(list string) map[string]struct{} {
+	m := map[string]struct{}{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			m[item] = struct{}{}
+		}
+	}
+	return m
+}
+
+func (sq *SlowQuery) Run(ctx context.Context) error {
+	offset, err := loadOffset(sq.offsetFile, sq.logFilePath)
+	if err != nil {
+		return fmt.Errorf("load offset from %s failed: %v", sq.offsetFile, err)
+	}
+	t, err := newTailer(sq.logFilePath, offset)
+	if err != nil {
+		return err
+	}
+	defer t.close()
+	sq.l.Infof("tail slow log %s from offset %d", sq.logFilePath, t.offset)
+
+	p := &parser{}
+	for {
+		l, err := t.readLine()
+		if err == nil {
+			if ev := p.feed(l); ev != nil {
+				sq.send(ctx, ev)
+			}
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if ev := p.flush(false); ev != nil {
+			sq.send(ctx, ev)
+		}
+		reopened, err := t.follow()
+		if err != nil {
+			return err
+		}
+		if reopened {
+			if ev := p.flush(true); ev != nil {
+				sq.send(ctx, ev)
+			}
+			p = &parser{}
+			sq.resetOffsets()
+			sq.l.Infof("slow log %s is rotated, tail it from the beginning", sq.logFilePath)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(sq.pollInterval):
+		}
+	}
+}
+
+func (sq *SlowQuery) filter(ev *event) bool {
+	if ev.admin || ev.query == "" {
+		return false
+	}
+	if _, ok := sq.excludeUsers[ev.user]; ok {
+		return false
+	}
+	if _, ok := sq.includeUsers[ev.user]; len(sq.includeUsers) > 0 && !ok {
+		return false
+	}
+	if _, ok := sq.excludeSchemas[ev.schema]; ok {
+		return false
+	}
+	if _, ok := sq.includeSchemas[ev.schema]; len(sq.includeSchemas) > 0 && !ok {
+		return false
+	}
+	return true
+}
+
+func (sq *SlowQuery) send(ctx context.Context, ev *event) {
+	if !sq.filter(ev) {
+		return
+	}
+	nodes, err := common.Parse(ctx, ev.query)
+	if err != nil {
+		sq.l.Warnf("skip the slow query which failed to parse, query: %s, error: %v", ev.query, err)
+		return
+	}
+	if len(nodes) == 0 {
+		return
+	}
+	sql := scanners.SQL{
+		Fingerprint: nodes[0].Fingerprint,
+		RawText:     ev.query,
+		Counter:     1,
+		Schema:      ev.schema,
+		QueryTime:   ev.queryTime,
+		QueryAt:     ev.queryAt,
+		DBUser:      ev.user,
+		Endpoint:    ev.endpoint,
+		RowExamined: ev.rowExamined,
+	}
+	sq.mu.Lock()
+	sq.offsets = append(sq.offsets, ev.offset)
+	sq.mu.Unlock()
+	select {
+	case sq.sqlCh <- sql:
+	case <-ctx.Done():
+	}
+}
+
+// resetOffsets is called when the log file is rotated, the offsets of SQLs
+// which are not uploaded yet belong to the old file, so the new file should
+// be read from the beginning if the scanner restarts after uploading them.
+func (sq *SlowQuery) resetOffsets() {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+	for i := range sq.offsets {
+		sq.offsets[i] = 0
+	}
+}
+
+func (sq *SlowQuery) SQLs() <-chan scanners.SQL {
+	return sq.sqlCh
+}
+
+func (sq *SlowQuery) Upload(ctx context.Context, sqls []scanners.SQL) error {
+	err := sq.c.UploadReq(scanner.PartialUpload, sq.apName, aggregate(sqls, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	// the SQLs are uploaded in the order of sending.
+	sq.mu.Lock()
+	n := len(sqls)
+	if n > len(sq.offsets) {
+		n = len(sq.offsets)
+	}
+	var offset int64 = -1
+	if n > 0 {
+		offset = sq.offsets[n-1]
+		sq.offsets = sq.offsets[n:]
+	}
+	sq.mu.Unlock()
+	if offset < 0 {
+		return nil
+	}
+	if err := saveOffset(sq.offsetFile, sq.logFilePath, offset); err != nil {
+		return fmt.Errorf("save offset to %s failed: %v", sq.offsetFile, err)
+	}
+	return nil
+}
+
+// aggregate merges the SQLs with the same fingerprint, the query time and rows
+// examined are averaged and the last SQL text is kept.
+func aggregate(sqls []scanners.SQL, now time.Time) []*scanner.AuditPlanSQLReq {
+	type agg struct {
+		req            *scanner.AuditPlanSQLReq
+		counter        int
+		queryTimeSum   float64
+		queryTimeMax   float64
+		rowExaminedSum float64
+		endpoints      map[string]struct{}
+	}
+	aggs := map[string]*agg{}
+	fps := []string{}
+	for _, sql := range sqls {
+		a, ok := aggs[sql.Fingerprint]
+		if !ok {
+			a = &agg{
+				req: &scanner.AuditPlanSQLReq{
+					Fingerprint:  sql.Fingerprint,
+					FirstQueryAt: sql.QueryAt,
+					DBUser:       sql.DBUser,
+					Endpoints:    []string{},
+				},
+				endpoints: map[string]struct{}{},
+			}
+			aggs[sql.Fingerprint] = a
+			fps = append(fps, sql.Fingerprint)
+		}
+		a.req.LastReceiveText = sql.RawText
+		a.req.Schema = sql.Schema
+		if !sql.QueryAt.IsZero() && (a.req.FirstQueryAt.IsZero() || sql.QueryAt.Before(a.req.FirstQueryAt)) {
+			a.req.FirstQueryAt = sql.QueryAt
+		}
+		if _, ok := a.endpoints[sql.Endpoint]; !ok && sql.Endpoint != "" {
+			a.endpoints[sql.Endpoint] = struct{}{}
+			a.req.Endpoints = append(a.req.Endpoints, sql.Endpoint)
+		}
+		a.counter += sql.Counter
+		a.queryTimeSum += sql.QueryTime * float64(sql.Counter)
+		a.rowExaminedSum += sql.RowExamined * float64(sql.Counter)
+		if sql.QueryTime > a.queryTimeMax {
+			a.queryTimeMax = sql.QueryTime
+		}
+	}
+
+	reqs := make([]*scanner.AuditPlanSQLReq, 0, len(fps))
+	for _, fp := range fps {
+		a := aggs[fp]
+		queryTimeAvg := a.queryTimeSum / float64(a.counter)
+		rowExaminedAvg := a.rowExaminedSum / float64(a.counter)
+		a.req.Counter = fmt.Sprintf("%v", a.counter)
+		a.req.LastReceiveTimestamp = now.Format(time.RFC3339)
+		a.req.QueryTimeAvg = &queryTimeAvg
+		a.req.QueryTimeMax = &a.queryTimeMax
+		a.req.RowExaminedAvg = &rowExaminedAvg
+		reqs = append(reqs, a.req)
+	}
+	return reqs
+}
@@ -0,0 +1,151 @@
+package slowquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/pkg/scanner"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func slowLogEntry(user, query string, queryTime float64) string {
+	return fmt.Sprintf(`# Time: 2023-09-12T02:48:01.000000Z
+# User@Host: %s[%s] @  [10.0.0.5]  Id:     8
+# Query_time: %v  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 10
+SET timestamp=1694486881;
+%s;
+`, user, user, queryTime, query)
+}
+
+func receive(t *testing.T, ch <-chan scanners.SQL, n int) []scanners.SQL {
+	sqls := []scanners.SQL{}
+	for i := 0; i < n; i++ {
+		select {
+		case sql := <-ch:
+			sqls = append(sqls, sql)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("receive %d sqls, want %d", len(sqls), n)
+		}
+	}
+	return sqls
+}
+
+func TestSlowQuery(t *testing.T) {
+	var req scanner.FullSyncAuditPlanSQLsReq
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, fmt.Sprintf(scanner.PartialUpload, "p1", "ap1"), r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		_ = json.NewEncoder(w).Encode(&scanner.BaseRes{})
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	c := scanner.NewSQLEClient(time.Second, u.Hostname(), u.Port()).WithProject("p1")
+
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "slow.log")
+	content := slowLogEntry("app", "select * from t1 where id = 1", 1) +
+		slowLogEntry("root", "select 1", 1) +
+		slowLogEntry("app", "select * from t1 where id = 2", 3)
+	appendFile(t, logFile, content)
+	params := &Params{
+		LogFilePath:  logFile,
+		APName:       "ap1",
+		ExcludeUsers: "root",
+		OffsetFile:   filepath.Join(dir, "slow.log.offset"),
+		PollInterval: 10 * time.Millisecond,
+	}
+
+	sq, err := New(params, logrus.New().WithField("test", "test"), c)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		assert.NoError(t, sq.Run(ctx))
+	}()
+	sqls := receive(t, sq.SQLs(), 2)
+	assert.NoError(t, sq.Upload(context.TODO(), sqls))
+	cancel()
+
+	if assert.Len(t, req.SQLs, 1) {
+		sql := req.SQLs[0]
+		assert.Equal(t, "SELECT * FROM `t1` WHERE `id`=?", sql.Fingerprint)
+		assert.Equal(t, "select * from t1 where id = 2", sql.LastReceiveText)
+		assert.Equal(t, "2", sql.Counter)
+		assert.Equal(t, float64(2), *sql.QueryTimeAvg)
+		assert.Equal(t, float64(3), *sql.QueryTimeMax)
+		assert.Equal(t, float64(10), *sql.RowExaminedAvg)
+		assert.Equal(t, "app", sql.DBUser)
+		assert.Equal(t, []string{"10.0.0.5"}, sql.Endpoints)
+	}
+	offset, err := loadOffset(params.OffsetFile, logFile)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), offset)
+
+	// the scanner resumes from the saved offset after restart.
+	appendFile(t, logFile, slowLogEntry("app", "delete from t2", 1))
+	sq, err = New(params, logrus.New().WithField("test", "test"), c)
+	assert.NoError(t, err)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		assert.NoError(t, sq.Run(ctx))
+	}()
+	sqls = receive(t, sq.SQLs(), 1)
+	assert.True(t, strings.HasPrefix(sqls[0].RawText, "delete from t2"))
+}
+
+func TestSlowQuery_Rotated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&scanner.BaseRes{})
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	c := scanner.NewSQLEClient(time.Second, u.Hostname(), u.Port()).WithProject("p1")
+
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "slow.log")
+	appendFile(t, logFile, slowLogEntry("app", "select * from t1 where id = 1", 1)+
+		slowLogEntry("app", "select * from t1 where id = 2", 1))
+	params := &Params{
+		LogFilePath:  logFile,
+		APName:       "ap1",
+		OffsetFile:   filepath.Join(dir, "slow.log.offset"),
+		PollInterval: 10 * time.Millisecond,
+	}
+	sq, err := New(params, logrus.New().WithField("test", "test"), c)
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		assert.NoError(t, sq.Run(ctx))
+	}()
+	oldSQLs := receive(t, sq.SQLs(), 2)
+
+	// the log is truncated by logrotate copytruncate before the SQLs are uploaded.
+	content := slowLogEntry("app", "delete from t2", 1)
+	assert.NoError(t, os.WriteFile(logFile, []byte(content), 0644))
+	newSQLs := receive(t, sq.SQLs(), 1)
+
+	// the offset of the old file is not applied to the new one.
+	assert.NoError(t, sq.Upload(context.TODO(), oldSQLs))
+	offset, err := loadOffset(params.OffsetFile, logFile)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), offset)
+
+	assert.NoError(t, sq.Upload(context.TODO(), newSQLs))
+	offset, err = loadOffset(params.OffsetFile, logFile)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), offset)
+}
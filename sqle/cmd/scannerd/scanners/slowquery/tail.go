@@ -0,0 +1,119 @@
+package slowquery
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+)
+
+type line struct {
+	text string
+	// offset is the offset of file to resume reading after the line.
+	offset int64
+}
+
+// tailer reads the lines appended to the file. It follows the file when it is
+// rotated by renaming (logrotate create) or truncated (logrotate copytruncate).
+type tailer struct {
+	path   string
+	file   *os.File
+	info   os.FileInfo
+	reader *bufio.Reader
+	// offset is the offset of the next complete line.
+	offset int64
+	// partial is the incomplete last line which is being written.
+	partial string
+	// rotated is set when a new file is created at the path, the old file is
+	// read until no data is written to it.
+	rotated bool
+}
+
+// newTailer opens the file from offset, it starts from the beginning if the
+// file is smaller than offset, which means it is rotated.
+func newTailer(path string, offset int64) (*tailer, error) {
+	t := &tailer{path: path}
+	if err := t.open(offset); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *tailer) open(offset int64) error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+	if t.file != nil {
+		t.file.Close()
+	}
+	t.file = file
+	t.info = info
+	t.reader = bufio.NewReader(file)
+	t.offset = offset
+	t.partial = ""
+	t.rotated = false
+	return nil
+}
+
+// readLine returns io.EOF if there is no complete line to read.
+func (t *tailer) readLine() (line, error) {
+	text, err := t.reader.ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			t.partial += text
+		}
+		return line{}, err
+	}
+	text = t.partial + text
+	t.partial = ""
+	t.offset += int64(len(text))
+	l := line{text: text[:len(text)-1], offset: t.offset}
+	if t.rotated {
+		// the file at path is the new one, it should be read from the beginning.
+		l.offset = 0
+	}
+	return l, nil
+}
+
+// follow is called when all lines of file are read, it reopens the file if
+// the file is rotated or truncated. It returns true if the file is reopened.
+func (t *tailer) follow() (bool, error) {
+	info, err := os.Stat(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		// the file is renamed and the new one is not created yet.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !os.SameFile(t.info, info) {
+		if !t.rotated {
+			// the server may still write to the old file before it reopens the
+			// log, so the old file is read once more before switching.
+			t.rotated = true
+			return false, nil
+		}
+		return true, t.open(0)
+	}
+	if info.Size() < t.offset+int64(len(t.partial)) {
+		return true, t.open(0)
+	}
+	return false, nil
+}
+
+func (t *tailer) close() error {
+	return t.file.Close()
+}
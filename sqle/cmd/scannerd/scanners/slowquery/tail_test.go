@@ -0,0 +1,98 @@
+package slowquery
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func appendFile(t *testing.T, path, content string) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	_, err = f.WriteString(content)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func readLines(t *testing.T, tl *tailer) []line {
+	lines := []line{}
+	for {
+		l, err := tl.readLine()
+		if errors.Is(err, io.EOF) {
+			return lines
+		}
+		assert.NoError(t, err)
+		lines = append(lines, l)
+	}
+}
+
+func TestTailer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slow.log")
+	appendFile(t, path, "a\nb\nc")
+
+	// start from the offset of line b.
+	tl, err := newTailer(path, 2)
+	assert.NoError(t, err)
+	defer tl.close()
+	assert.Equal(t, []line{{text: "b", offset: 4}}, readLines(t, tl))
+
+	// the incomplete line is read after it is written.
+	appendFile(t, path, "c\nd\n")
+	assert.Equal(t, []line{{text: "cc", offset: 7}, {text: "d", offset: 9}}, readLines(t, tl))
+	reopened, err := tl.follow()
+	assert.NoError(t, err)
+	assert.False(t, reopened)
+
+	// rotated by renaming, the old file is read once more before switching.
+	assert.NoError(t, os.Rename(path, path+".1"))
+	reopened, err = tl.follow()
+	assert.NoError(t, err)
+	assert.False(t, reopened)
+	appendFile(t, path+".1", "e\n")
+	appendFile(t, path, "f\n")
+	reopened, err = tl.follow()
+	assert.NoError(t, err)
+	assert.False(t, reopened)
+	assert.Equal(t, []line{{text: "e", offset: 0}}, readLines(t, tl))
+	reopened, err = tl.follow()
+	assert.NoError(t, err)
+	assert.True(t, reopened)
+	assert.Equal(t, []line{{text: "f", offset: 2}}, readLines(t, tl))
+
+	// truncated
+	assert.NoError(t, os.Truncate(path, 0))
+	reopened, err = tl.follow()
+	assert.NoError(t, err)
+	assert.True(t, reopened)
+	appendFile(t, path, "g\n")
+	assert.Equal(t, []line{{text: "g", offset: 2}}, readLines(t, tl))
+}
+
+func TestNewTailerFromRotatedOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slow.log")
+	appendFile(t, path, "a\n")
+	tl, err := newTailer(path, 100)
+	assert.NoError(t, err)
+	defer tl.close()
+	assert.Equal(t, []line{{text: "a", offset: 2}}, readLines(t, tl))
+}
+
+func TestOffset(t *testing.T) {
+	offsetFile := filepath.Join(t.TempDir(), "slow.log.offset")
+	offset, err := loadOffset(offsetFile, "/var/log/slow.log")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), offset)
+
+	assert.NoError(t, saveOffset(offsetFile, "/var/log/slow.log", 100))
+	offset, err = loadOffset(offsetFile, "/var/log/slow.log")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), offset)
+
+	offset, err = loadOffset(offsetFile, "/var/log/other.log")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), offset)
+}
@@ -52,6 +52,16 @@ type SeviceOpts struct {
 	PluginPath         string         `yaml:"plugin_path"`
 	Database           Database       `yaml:"database"`
 	PluginConfig       []PluginConfig `yaml:"plugin_config"`
+	// ActionQueueBackend is the scheduling backend of audit/execute/rollback
+	// actions, "memory"(default) or "storage". Actions in memory are lost when SQLE restarts,
+	// the storage backend persists them with leases so they are resumed by other nodes.
+	ActionQueueBackend string `yaml:"action_queue_backend"`
+	// DefaultLanguage is the language of messages when the user does not choose one
+	// and the request has no Accept-Language, such as "zh"(default) and "en".
+	DefaultLanguage string `yaml:"default_language"`
+	// EventWebhookAllowPrivateNetwork allows the webhooks of event subscriptions to
+	// be sent to loopback, private and link-local addresses, it is false by default.
+	EventWebhookAllowPrivateNetwork bool `yaml:"event_webhook_allow_private_network"`
 }
 
 type Database struct {
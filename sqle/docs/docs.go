@@ -113,6 +113,29 @@ var doc = `{
                 }
             }
         },
+        "/v1/cluster/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the leader and the nodes of cluster",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取集群状态",
+                "operationId": "getClusterStatusV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetClusterStatusResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/company_notice": {
             "get": {
                 "security": [
@@ -174,6 +197,29 @@ var doc = `{
                 }
             }
         },
+        "/v1/configurations/action_queue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the running and waiting actions of audit, execution and rollback",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取任务队列状态",
+                "operationId": "getActionQueueV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetActionQueueResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/configurations/ding_talk": {
             "get": {
                 "security": [
@@ -265,7 +311,7 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get drivers",
+                "description": "get drivers and the status of driver plugins, the plugin files failed to load are also listed in driver_status_list",
                 "tags": [
                     "configuration"
                 ],
@@ -281,6 +327,29 @@ var doc = `{
                 }
             }
         },
+        "/v1/configurations/drivers/reload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "load the plugins added or updated in plugin dir and the plugins failed to load before, the updated plugin replaces the running one",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "重新加载插件目录中的插件",
+                "operationId": "reloadDriversV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetDriversResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/configurations/feishu_audit": {
             "get": {
                 "security": [
@@ -490,6 +559,101 @@ var doc = `{
                 }
             }
         },
+        "/v1/configurations/slack": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get slack approval configuration",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取Slack审批配置",
+                "operationId": "getSlackConfigurationV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSlackConfigurationResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update slack approval configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "添加或更新Slack审批配置",
+                "operationId": "updateSlackConfigurationV1",
+                "parameters": [
+                    {
+                        "description": "update slack configuration req",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSlackConfigurationReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/slack/test": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "test slack approval configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "测试Slack审批配置",
+                "operationId": "testSlackConfigV1",
+                "parameters": [
+                    {
+                        "description": "test slack configuration req",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TestChatOpsConfigurationReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.TestChatOpsConfigResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/configurations/system_variables": {
             "get": {
                 "security": [
@@ -548,6 +712,101 @@ var doc = `{
                 }
             }
         },
+        "/v1/configurations/teams": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get microsoft teams approval configuration",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "获取Microsoft Teams审批配置",
+                "operationId": "getTeamsConfigurationV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetTeamsConfigurationResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update microsoft teams approval configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "添加或更新Microsoft Teams审批配置",
+                "operationId": "updateTeamsConfigurationV1",
+                "parameters": [
+                    {
+                        "description": "update teams configuration req",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateTeamsConfigurationReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/teams/test": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "test microsoft teams approval configuration",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "测试Microsoft Teams审批配置",
+                "operationId": "testTeamsConfigV1",
+                "parameters": [
+                    {
+                        "description": "test teams configuration req",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TestChatOpsConfigurationReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.TestChatOpsConfigResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/configurations/wechat_audit": {
             "get": {
                 "security": [
@@ -735,6 +994,46 @@ var doc = `{
                 }
             }
         },
+        "/v1/custom_rules/dry_run": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "dry run custom rule against the sample SQL and test cases offline, the table metadata is only available for the tables created by the former statements, and the current schema is \"dry_run\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "rule_template"
+                ],
+                "summary": "试运行自定义规则",
+                "operationId": "dryRunCustomRuleV1",
+                "parameters": [
+                    {
+                        "description": "dry run custom rule",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.DryRunCustomRuleReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.DryRunCustomRuleResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/custom_rules/{db_type}/rule_types": {
             "get": {
                 "security": [
@@ -902,16 +1201,58 @@ var doc = `{
                 }
             }
         },
-        "/v1/operation_records": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+        "/v1/im/slack/callback": {
+            "post": {
+                "description": "receive the interaction of slack approval message, the request is verified by the signing secret",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
                 ],
-                "description": "Get operation record list",
                 "tags": [
-                    "OperationRecord"
+                    "workflow"
+                ],
+                "summary": "Slack审批消息的交互回调",
+                "operationId": "slackCallbackV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/im/teams/callback": {
+            "post": {
+                "description": "receive the interaction of teams approval message, the request is verified by the token of Bot Framework",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "Microsoft Teams审批消息的交互回调",
+                "operationId": "teamsCallbackV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/operation_records": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get operation record list",
+                "tags": [
+                    "OperationRecord"
                 ],
                 "summary": "获取操作记录列表",
                 "operationId": "getOperationRecordListV1",
@@ -1341,6 +1682,51 @@ var doc = `{
                 }
             }
         },
+        "/v1/projects/{project_name}/audit_plans/{audit_plan_name}/index_advice": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get index advice merged from all SQLs of audit plan, weighted by counter and query time",
+                "tags": [
+                    "audit_plan"
+                ],
+                "summary": "获取扫描任务负载级别的索引建议",
+                "operationId": "getAuditPlanIndexAdviceV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "audit plan name",
+                        "name": "audit_plan_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max index advice per table, default 3",
+                        "name": "max_index_per_table",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetAuditPlanIndexAdviceResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/projects/{project_name}/audit_plans/{audit_plan_name}/notify_config": {
             "get": {
                 "security": [
@@ -1723,6 +2109,124 @@ var doc = `{
                 }
             }
         },
+        "/v1/projects/{project_name}/audit_plans/{audit_plan_name}/schema_drifts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get schema drifts between schema snapshots, the drifts not made by executed workflow tasks are out of band",
+                "tags": [
+                    "audit_plan"
+                ],
+                "summary": "获取库表元数据扫描任务发现的表结构变更",
+                "operationId": "getSchemaDriftsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "audit plan name",
+                        "name": "audit_plan_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the version of snapshot which the drifts are detected in",
+                        "name": "snapshot_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only the drifts not made by workflow",
+                        "name": "only_out_of_band",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSchemaDriftsResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/audit_plans/{audit_plan_name}/schema_snapshots": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get schema snapshots of schema meta audit plan, a new version is saved when the table structures changed",
+                "tags": [
+                    "audit_plan"
+                ],
+                "summary": "获取库表元数据扫描任务的表结构快照",
+                "operationId": "getSchemaSnapshotsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "audit plan name",
+                        "name": "audit_plan_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSchemaSnapshotsResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/projects/{project_name}/audit_plans/{audit_plan_name}/sqls": {
             "get": {
                 "security": [
@@ -1913,6 +2417,83 @@ var doc = `{
                 }
             }
         },
+        "/v1/projects/{project_name}/audit_suppression_setting": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the setting of ignoring audit results by annotation in SQL comment, e.g. /* sqle:ignore rule_name reason=\"...\" */",
+                "tags": [
+                    "project"
+                ],
+                "summary": "获取项目 SQL 注释忽略审核结果的配置",
+                "operationId": "getAuditSuppressionSettingV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetAuditSuppressionSettingResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update the setting of ignoring audit results by annotation in SQL comment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "project"
+                ],
+                "summary": "更新项目 SQL 注释忽略审核结果的配置",
+                "operationId": "updateAuditSuppressionSettingV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update audit suppression setting request",
+                        "name": "setting",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateAuditSuppressionSettingReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/projects/{project_name}/audit_whitelist": {
             "get": {
                 "security": [
@@ -2089,19 +2670,19 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/instance_tips": {
+        "/v1/projects/{project_name}/event_subscriptions": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get instance tip list",
+                "description": "get event subscriptions of project",
                 "tags": [
-                    "instance"
+                    "event_subscription"
                 ],
-                "summary": "获取实例提示列表",
-                "operationId": "getInstanceTipListV1",
+                "summary": "获取事件订阅列表",
+                "operationId": "getEventSubscriptionsV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -2109,13 +2690,285 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "filter db type",
-                        "name": "filter_db_type",
-                        "in": "query"
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetEventSubscriptionsResV1"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "create an event subscription of project, the secret is only returned in the response",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event_subscription"
+                ],
+                "summary": "添加事件订阅",
+                "operationId": "createEventSubscriptionV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "create event subscription req",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateEventSubscriptionReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CreateEventSubscriptionResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/event_subscriptions/{subscription_id}/": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "delete event subscription of project, the pending deliveries of it are given up",
+                "tags": [
+                    "event_subscription"
+                ],
+                "summary": "删除事件订阅",
+                "operationId": "deleteEventSubscriptionV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event subscription id",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update event subscription of project",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event_subscription"
+                ],
+                "summary": "更新事件订阅",
+                "operationId": "updateEventSubscriptionV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event subscription id",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update event subscription req",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateEventSubscriptionReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/event_subscriptions/{subscription_id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get deliveries of event subscription",
+                "tags": [
+                    "event_subscription"
+                ],
+                "summary": "获取事件订阅的投递记录",
+                "operationId": "getEventDeliveriesV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event subscription id",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "succeeded",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "filter delivery status",
+                        "name": "filter_status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetEventDeliveriesResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/event_subscriptions/{subscription_id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "redeliver the event of a delivery with the same payload, a new delivery is created",
+                "tags": [
+                    "event_subscription"
+                ],
+                "summary": "重新投递事件",
+                "operationId": "redeliverEventV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event subscription id",
+                        "name": "subscription_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event delivery id",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RedeliverEventResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/instance_tips": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get instance tip list",
+                "tags": [
+                    "instance"
+                ],
+                "summary": "获取实例提示列表",
+                "operationId": "getInstanceTipListV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter db type",
+                        "name": "filter_db_type",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "filter workflow template id",
@@ -2837,7 +3690,7 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "SQL audit\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.\n4. file[input_zip_file]: it is ZIP file that sql will be parsed from xml or sql file inside it.\n5. formData[git_http_url]:the url which scheme is http(s) and end with .git.\n6. formData[git_user_name]:The name of the user who owns the repository read access.\n7. formData[git_user_password]:The password corresponding to git_user_name.",
+                "description": "SQL audit\n1. formData[sql]: sql content;\n2. file[input_sql_file]: it is a sql file;\n3. file[input_mybatis_xml_file]: it is mybatis xml file, sql will be parsed from it.\n4. file[input_zip_file]: it is ZIP file that sql will be parsed from xml or sql file inside it.\n5. formData[git_http_url]:the url which scheme is http(s) and end with .git.\n6. formData[git_user_name]:The name of the user who owns the repository read access.\n7. formData[git_user_password]:The password corresponding to git_user_name.\n8. formData[git_head_revision]:the revision of git repository to audit, it is the default branch if it is empty.\n9. formData[git_base_revision]:only the SQLs changed from the merge base of it and git_head_revision are audited if it is not empty.",
                 "consumes": [
                     "multipart/form-data"
                 ],
@@ -2916,6 +3769,18 @@ var doc = `{
                         "description": "the password corresponding to git_user_name",
                         "name": "git_user_password",
                         "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the revision of git repository to audit, such as branch, tag or commit",
+                        "name": "git_head_revision",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the base revision of git repository, only the changed SQLs are audited",
+                        "name": "git_base_revision",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
@@ -4878,25 +5743,19 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/order_file": {
-            "post": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/execution_attempts": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update sql file order",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "get the execution attempts of task",
                 "tags": [
-                    "task"
+                    "workflow"
                 ],
-                "summary": "修改文件上线顺序",
-                "operationId": "updateSqlFileOrderV1",
+                "summary": "获取工单数据源任务的上线执行历史",
+                "operationId": "getTaskExecutionAttemptsByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -4918,52 +5777,49 @@ var doc = `{
                         "name": "task_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "instance body v1.UpdateSqlFileOrderV1Req true",
-                        "name": "instance",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpdateSqlFileOrderV1Req"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetSqlFileOrderMethodResV1"
+                            "$ref": "#/definitions/v1.GetTaskExecutionAttemptsResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/terminate": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/ghost_actions": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "execute one task on workflow",
+                "description": "throttle, unthrottle, postpone cut-over or cut-over the running gh-ost migration, postponing cut-over requires the task is executed with the config postpone_cut_over",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "workflow"
                 ],
-                "summary": "终止单个上线任务",
-                "operationId": "terminateSingleTaskByWorkflowV1",
+                "summary": "操作工单数据源任务正在执行的 gh-ost 迁移",
+                "operationId": "operateTaskGhostMigrationByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow id",
-                        "name": "workflow_id",
+                        "description": "project name",
+                        "name": "project_name",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
                         "in": "path",
                         "required": true
                     },
@@ -4973,6 +5829,15 @@ var doc = `{
                         "name": "task_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "operate gh-ost migration request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.OperateTaskGhostMigrationReqV1"
+                        }
                     }
                 ],
                 "responses": {
@@ -4985,32 +5850,38 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/ghost_config": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get workflow detail",
+                "description": "get the gh-ost config of task which overrides the config of instance",
                 "tags": [
                     "workflow"
                 ],
-                "summary": "获取工单详情",
-                "operationId": "getWorkflowV1",
-                "deprecated": true,
+                "summary": "获取工单数据源任务的 gh-ost 配置",
+                "operationId": "getTaskGhostConfigByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "project name",
+                        "name": "project_name",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
                         "in": "path",
                         "required": true
                     }
@@ -5019,7 +5890,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetWorkflowResV1"
+                            "$ref": "#/definitions/v1.GetTaskGhostConfigResV1"
                         }
                     }
                 }
@@ -5030,7 +5901,7 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update workflow when it is rejected to creator.",
+                "description": "update the gh-ost config of task before execution, it overrides the config of instance except throttle_query, max_load and critical_load, which can only be set in the instance config",
                 "consumes": [
                     "application/json"
                 ],
@@ -5040,31 +5911,37 @@ var doc = `{
                 "tags": [
                     "workflow"
                 ],
-                "summary": "更新工单（驳回后才可更新）",
-                "operationId": "updateWorkflowV1",
-                "deprecated": true,
+                "summary": "更新工单数据源任务的 gh-ost 配置",
+                "operationId": "updateTaskGhostConfigByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "project name",
+                        "name": "project_name",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "update workflow request",
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update gh-ost config request",
                         "name": "instance",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/v1.UpdateWorkflowReqV1"
+                            "$ref": "#/definitions/v1.UpdateTaskGhostConfigReqV1"
                         }
                     }
                 ],
@@ -5078,20 +5955,19 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/cancel": {
-            "post": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/ghost_progress": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "cancel workflow",
+                "description": "get the progress of gh-ost migrations running in the task, the progress is also saved to the exec result of SQL periodically",
                 "tags": [
                     "workflow"
                 ],
-                "summary": "审批关闭（中止）",
-                "operationId": "cancelWorkflowV1",
-                "deprecated": true,
+                "summary": "获取工单数据源任务正在执行的 gh-ost 进度",
+                "operationId": "getTaskGhostProgressByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5102,8 +5978,15 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
                         "in": "path",
                         "required": true
                     }
@@ -5112,141 +5995,151 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetTaskGhostProgressResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/steps/{workflow_step_id}/approve": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/order_file": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "approve workflow",
+                "description": "update sql file order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "workflow"
+                    "task"
                 ],
-                "summary": "审批通过",
-                "operationId": "approveWorkflowV1",
-                "deprecated": true,
+                "summary": "修改文件上线顺序",
+                "operationId": "updateSqlFileOrderV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "project name",
+                        "name": "project_name",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "workflow step id",
-                        "name": "workflow_step_id",
+                        "description": "workflow id",
+                        "name": "workflow_id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "task id",
+                        "name": "task_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "instance body v1.UpdateSqlFileOrderV1Req true",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateSqlFileOrderV1Req"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetSqlFileOrderMethodResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/steps/{workflow_step_id}/reject": {
-            "post": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/rehearsal": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "reject workflow",
+                "description": "get the rehearsal report of task",
                 "tags": [
                     "workflow"
                 ],
-                "summary": "审批驳回",
-                "operationId": "rejectWorkflowV1",
-                "deprecated": true,
+                "summary": "获取工单数据源任务的演练报告",
+                "operationId": "getTaskRehearsalByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "project name",
+                        "name": "project_name",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "workflow step id",
-                        "name": "workflow_step_id",
+                        "description": "task id",
+                        "name": "task_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "workflow approve request",
-                        "name": "workflow_approve",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.RejectWorkflowReqV1"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.BaseRes"
+                            "$ref": "#/definitions/v1.GetTaskRehearsalResV1"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/tasks": {
-            "get": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/rehearse": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get summary of workflow instance tasks",
+                "description": "rehearse the SQLs of approved task before execution, DMLs are executed in a transaction which is always rolled back and ALTER TABLEs are executed by gh-ost with dry-run",
                 "tags": [
                     "workflow"
                 ],
-                "summary": "获取工单数据源任务概览",
-                "operationId": "getSummaryOfInstanceTasksV1",
-                "deprecated": true,
+                "summary": "演练工单数据源任务",
+                "operationId": "rehearseTaskByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "project name",
+                        "name": "project_name",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
                         "in": "path",
                         "required": true
                     }
@@ -5255,40 +6148,61 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetWorkflowTasksResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/tasks/execute": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/resume": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "execute tasks on workflow",
+                "description": "resume the failed execution of task from its checkpoint. resume: execute from the failed SQL to the end; skip: skip the failed SQL with reason and execute the rest; retry: execute the failed SQL only",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "workflow"
                 ],
-                "summary": "多数据源批量上线",
-                "operationId": "executeTasksOnWorkflowV1",
-                "deprecated": true,
+                "summary": "继续上线执行失败的工单数据源任务",
+                "operationId": "resumeTaskByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "project name",
+                        "name": "project_name",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "resume task request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ResumeTaskReqV1"
+                        }
                     }
                 ],
                 "responses": {
@@ -5301,7 +6215,7 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/tasks/{task_id}/execute": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/terminate": {
             "post": {
                 "security": [
                     {
@@ -5312,14 +6226,13 @@ var doc = `{
                 "tags": [
                     "workflow"
                 ],
-                "summary": "工单提交单个数据源上线",
-                "operationId": "executeOneTaskOnWorkflowV1",
-                "deprecated": true,
+                "summary": "终止单个上线任务",
+                "operationId": "terminateSingleTaskByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
                         "in": "path",
                         "required": true
                     },
@@ -5348,25 +6261,19 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/tasks/{task_id}/schedule": {
-            "put": {
+        "/v1/projects/{project_name}/workflows/{workflow_name}/": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update workflow schedule.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "get workflow detail",
                 "tags": [
                     "workflow"
                 ],
-                "summary": "设置工单数据源定时上线时间（设置为空则代表取消定时时间，需要SQL审核流程都通过后才可以设置）",
-                "operationId": "updateWorkflowScheduleV1",
+                "summary": "获取工单详情",
+                "operationId": "getWorkflowV1",
                 "deprecated": true,
                 "parameters": [
                     {
@@ -5376,20 +6283,389 @@ var doc = `{
                         "in": "path",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "task id",
-                        "name": "task_id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "project name",
                         "name": "project_name",
                         "in": "path",
                         "required": true
-                    },
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetWorkflowResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update workflow when it is rejected to creator.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "更新工单（驳回后才可更新）",
+                "operationId": "updateWorkflowV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update workflow request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateWorkflowReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_name}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "cancel workflow",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "审批关闭（中止）",
+                "operationId": "cancelWorkflowV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_name}/steps/{workflow_step_id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "approve workflow",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "审批通过",
+                "operationId": "approveWorkflowV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "workflow step id",
+                        "name": "workflow_step_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_name}/steps/{workflow_step_id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "reject workflow",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "审批驳回",
+                "operationId": "rejectWorkflowV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "workflow step id",
+                        "name": "workflow_step_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "workflow approve request",
+                        "name": "workflow_approve",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.RejectWorkflowReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_name}/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get summary of workflow instance tasks",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "获取工单数据源任务概览",
+                "operationId": "getSummaryOfInstanceTasksV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetWorkflowTasksResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_name}/tasks/execute": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "execute tasks on workflow",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "多数据源批量上线",
+                "operationId": "executeTasksOnWorkflowV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_name}/tasks/{task_id}/execute": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "execute one task on workflow",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "工单提交单个数据源上线",
+                "operationId": "executeOneTaskOnWorkflowV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_name}/tasks/{task_id}/schedule": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update workflow schedule.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "设置工单数据源定时上线时间（设置为空则代表取消定时时间，需要SQL审核流程都通过后才可以设置）",
+                "operationId": "updateWorkflowScheduleV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "update workflow schedule request",
                         "name": "instance",
@@ -6565,6 +7841,38 @@ var doc = `{
                 }
             }
         },
+        "/v1/tasks/audits/{task_id}/audit_progress": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the audit progress of task, the SQL count is only returned while the task is auditing",
+                "tags": [
+                    "task"
+                ],
+                "summary": "获取Sql扫描任务的审核进度",
+                "operationId": "getAuditTaskProgressV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetAuditTaskProgressResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/tasks/audits/{task_id}/origin_file": {
             "get": {
                 "security": [
@@ -6906,6 +8214,64 @@ var doc = `{
                 }
             }
         },
+        "/v1/user/preference": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get the preference of current user",
+                "tags": [
+                    "user"
+                ],
+                "summary": "获取当前用户的偏好设置",
+                "operationId": "getUserPreferenceV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetUserPreferenceResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update the preference of current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "更新当前用户的偏好设置",
+                "operationId": "updateUserPreferenceV1",
+                "parameters": [
+                    {
+                        "description": "update user preference request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateUserPreferenceReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/user_tips": {
             "get": {
                 "security": [
@@ -7863,6 +9229,14 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "workflow approve request",
+                        "name": "workflow_approve",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v2.ApproveWorkflowReqV2"
+                        }
                     }
                 ],
                 "responses": {
@@ -8385,6 +9759,66 @@ var doc = `{
                 }
             }
         },
+        "v1.ActionQueueItemResV1": {
+            "type": "object",
+            "properties": {
+                "ddl": {
+                    "type": "boolean"
+                },
+                "enqueued_at": {
+                    "type": "string"
+                },
+                "instance_id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "audit",
+                        "execute"
+                    ]
+                },
+                "lane": {
+                    "type": "string",
+                    "enum": [
+                        "interactive",
+                        "batch"
+                    ]
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.ActionQueueResV1": {
+            "type": "object",
+            "properties": {
+                "running": {
+                    "description": "当前节点正在运行的动作",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ActionQueueItemResV1"
+                    }
+                },
+                "sqled_actions": {
+                    "description": "所有节点持有的工单任务动作",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SqledActionResV1"
+                    }
+                },
+                "waiting": {
+                    "description": "当前节点等待运行的动作，按启动顺序排列",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ActionQueueItemResV1"
+                    }
+                }
+            }
+        },
         "v1.AffectRows": {
             "type": "object",
             "properties": {
@@ -8413,8 +9847,38 @@ var doc = `{
                 "audit_plan_desc": {
                     "type": "string"
                 },
-                "audit_plan_type": {
-                    "type": "string"
+                "audit_plan_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.AuditPlanIndexAdviceResV1": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "create_index_sql": {
+                    "type": "string",
+                    "example": "CREATE INDEX idx_t1_c1 ON db1.t1 (c1);"
+                },
+                "fingerprints": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "weight": {
+                    "type": "number"
                 }
             }
         },
@@ -8637,6 +10101,28 @@ var doc = `{
                 }
             }
         },
+        "v1.AuditPlanTableIndexAdviceResV1": {
+            "type": "object",
+            "properties": {
+                "indexes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditPlanIndexAdviceResV1"
+                    }
+                },
+                "schema": {
+                    "type": "string",
+                    "example": "db1"
+                },
+                "table": {
+                    "type": "string",
+                    "example": "t1"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
         "v1.AuditPlanTypesV1": {
             "type": "object",
             "properties": {
@@ -8718,6 +10204,20 @@ var doc = `{
                 }
             }
         },
+        "v1.AuditSuppressionSettingResV1": {
+            "type": "object",
+            "properties": {
+                "need_acknowledgement": {
+                    "type": "boolean"
+                },
+                "suppressible_levels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "v1.AuditTaskGroupRes": {
             "type": "object",
             "properties": {
@@ -8749,6 +10249,32 @@ var doc = `{
                 }
             }
         },
+        "v1.AuditTaskProgressResV1": {
+            "type": "object",
+            "properties": {
+                "audited_sql_count": {
+                    "type": "integer"
+                },
+                "progress_pct": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "initialized",
+                        "auditing",
+                        "audited",
+                        "executing",
+                        "exec_success",
+                        "exec_failed",
+                        "manually_executed"
+                    ]
+                },
+                "total_sql_count": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.AuditTaskResV1": {
             "type": "object",
             "properties": {
@@ -8875,9 +10401,42 @@ var doc = `{
                 "desc": {
                     "type": "string"
                 },
+                "expired_at": {
+                    "type": "string"
+                },
+                "instance_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_expired": {
+                    "type": "boolean"
+                },
+                "last_matched_time": {
+                    "type": "string"
+                },
                 "match_type": {
                     "type": "string"
                 },
+                "matched_count": {
+                    "type": "integer"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "rule_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schema_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "value": {
                     "type": "string"
                 }
@@ -9018,6 +10577,54 @@ var doc = `{
                 }
             }
         },
+        "v1.ClusterNodeResV1": {
+            "type": "object",
+            "properties": {
+                "heartbeat_at": {
+                    "type": "string"
+                },
+                "is_alive": {
+                    "description": "心跳超时的节点为 false",
+                    "type": "boolean"
+                },
+                "is_leader": {
+                    "type": "boolean"
+                },
+                "report_host": {
+                    "type": "string"
+                },
+                "server_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ClusterStatusResV1": {
+            "type": "object",
+            "properties": {
+                "current_server_id": {
+                    "type": "string"
+                },
+                "is_cluster_mode": {
+                    "type": "boolean"
+                },
+                "leader_server_id": {
+                    "description": "当前持有租约的节点, 租约过期时为空",
+                    "type": "string"
+                },
+                "lease_expire_at": {
+                    "type": "string"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ClusterNodeResV1"
+                    }
+                }
+            }
+        },
         "v1.CompanyNotice": {
             "type": "object",
             "properties": {
@@ -9113,7 +10720,190 @@ var doc = `{
                 }
             }
         },
-        "v1.CreateAuditTasksGroupResV1": {
+        "v1.CreateAuditTasksGroupResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditTasksGroupResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.CreateAuditWhitelistReqV1": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string",
+                    "example": "used for rapid release"
+                },
+                "expired_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "instance_names": {
+                    "description": "生效的数据源, 为空时对所有数据源生效",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "match_type": {
+                    "type": "string",
+                    "enum": [
+                        "exact_match",
+                        "fp_match",
+                        "regex_match"
+                    ],
+                    "example": "exact_match"
+                },
+                "owner": {
+                    "description": "负责人, 为空时为当前用户",
+                    "type": "string"
+                },
+                "rule_names": {
+                    "description": "跳过的规则, 为空时跳过所有规则",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schema_names": {
+                    "description": "生效的 schema, 为空时对所有 schema 生效",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "value": {
+                    "type": "string",
+                    "example": "create table"
+                }
+            }
+        },
+        "v1.CreateCustomRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "annotation": {
+                    "type": "string",
+                    "example": "this is test rule"
+                },
+                "db_type": {
+                    "type": "string",
+                    "example": "MySQL"
+                },
+                "desc": {
+                    "type": "string",
+                    "example": "this is test rule"
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ],
+                    "example": "notice"
+                },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CustomRuleParamReqV1"
+                    }
+                },
+                "rule_script": {
+                    "type": "string"
+                },
+                "script_type": {
+                    "description": "regular: 正则表达式匹配 SQL; cel: CEL 表达式, 仅支持 MySQL",
+                    "type": "string",
+                    "enum": [
+                        "regular",
+                        "cel"
+                    ]
+                },
+                "test_cases": {
+                    "description": "保存规则前会校验所有测试用例",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CustomRuleTestCaseV1"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "DDL规则"
+                }
+            }
+        },
+        "v1.CreateEventSubscriptionReqV1": {
+            "type": "object",
+            "required": [
+                "is_enabled",
+                "name",
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "description": "订阅的事件类型，为空时订阅全部事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_enabled": {
+                    "description": "是否启用",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "订阅名称",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "签名密钥，为空时自动生成",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "接收事件的Webhook地址",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CreateEventSubscriptionResDataV1": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "签名密钥仅在创建时返回",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CreateEventSubscriptionResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -9122,7 +10912,7 @@ var doc = `{
                 },
                 "data": {
                     "type": "object",
-                    "$ref": "#/definitions/v1.AuditTasksGroupResV1"
+                    "$ref": "#/definitions/v1.CreateEventSubscriptionResDataV1"
                 },
                 "message": {
                     "type": "string",
@@ -9130,61 +10920,6 @@ var doc = `{
                 }
             }
         },
-        "v1.CreateAuditWhitelistReqV1": {
-            "type": "object",
-            "properties": {
-                "desc": {
-                    "type": "string",
-                    "example": "used for rapid release"
-                },
-                "match_type": {
-                    "type": "string",
-                    "enum": [
-                        "exact_match",
-                        "fp_match"
-                    ],
-                    "example": "exact_match"
-                },
-                "value": {
-                    "type": "string",
-                    "example": "create table"
-                }
-            }
-        },
-        "v1.CreateCustomRuleReqV1": {
-            "type": "object",
-            "properties": {
-                "annotation": {
-                    "type": "string",
-                    "example": "this is test rule"
-                },
-                "db_type": {
-                    "type": "string",
-                    "example": "MySQL"
-                },
-                "desc": {
-                    "type": "string",
-                    "example": "this is test rule"
-                },
-                "level": {
-                    "type": "string",
-                    "enum": [
-                        "normal",
-                        "notice",
-                        "warn",
-                        "error"
-                    ],
-                    "example": "notice"
-                },
-                "rule_script": {
-                    "type": "string"
-                },
-                "type": {
-                    "type": "string",
-                    "example": "DDL规则"
-                }
-            }
-        },
         "v1.CreateProjectRuleTemplateReqV1": {
             "type": "object",
             "properties": {
@@ -9259,6 +10994,29 @@ var doc = `{
                 }
             }
         },
+        "v1.CustomRuleParamReqV1": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "int",
+                        "bool",
+                        "float64"
+                    ]
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.CustomRuleResV1": {
             "type": "object",
             "properties": {
@@ -9284,18 +11042,85 @@ var doc = `{
                     ],
                     "example": "notice"
                 },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleParamResV1"
+                    }
+                },
                 "rule_id": {
                     "type": "string"
                 },
                 "rule_script": {
                     "type": "string"
                 },
+                "script_type": {
+                    "type": "string",
+                    "enum": [
+                        "regular",
+                        "cel"
+                    ]
+                },
+                "test_cases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CustomRuleTestCaseV1"
+                    }
+                },
                 "type": {
                     "type": "string",
                     "example": "DDL规则"
                 }
             }
         },
+        "v1.CustomRuleSQLResultV1": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "hit": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "sql": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CustomRuleTestCaseResultV1": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "expect_hit": {
+                    "type": "boolean"
+                },
+                "hit": {
+                    "type": "boolean"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "sql": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CustomRuleTestCaseV1": {
+            "type": "object",
+            "properties": {
+                "expect_hit": {
+                    "type": "boolean"
+                },
+                "sql": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.DBPerformanceImproveOverview": {
             "type": "object",
             "properties": {
@@ -9425,18 +11250,166 @@ var doc = `{
                     "type": "string",
                     "example": "select * from t1; select * from t2;"
                 },
-                "sql_type": {
-                    "type": "string",
-                    "enum": [
-                        "sql",
-                        "mybatis",
-                        ""
-                    ],
-                    "example": "sql"
+                "sql_type": {
+                    "type": "string",
+                    "enum": [
+                        "sql",
+                        "mybatis",
+                        ""
+                    ],
+                    "example": "sql"
+                }
+            }
+        },
+        "v1.DirectAuditResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditResDataV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.DirectGetSQLAnalysisResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SqlAnalysisResDataV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.DriverStatusV1": {
+            "type": "object",
+            "properties": {
+                "driver_name": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "description": "插件目录中的插件文件, 内置插件为空",
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_error_at": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "integer"
+                },
+                "restart_count": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "restarting",
+                        "failed"
+                    ]
+                },
+                "version": {
+                    "description": "插件协议版本",
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.DriversResV1": {
+            "type": "object",
+            "properties": {
+                "driver_name_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "driver_status_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.DriverStatusV1"
+                    }
+                }
+            }
+        },
+        "v1.DryRunCustomRuleReqV1": {
+            "type": "object",
+            "properties": {
+                "db_type": {
+                    "type": "string",
+                    "example": "MySQL"
+                },
+                "desc": {
+                    "type": "string",
+                    "example": "this is test rule"
+                },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CustomRuleParamReqV1"
+                    }
+                },
+                "rule_script": {
+                    "type": "string"
+                },
+                "script_type": {
+                    "type": "string",
+                    "enum": [
+                        "regular",
+                        "cel"
+                    ]
+                },
+                "sql": {
+                    "description": "样例 SQL, 可以包含多条语句",
+                    "type": "string"
+                },
+                "test_cases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CustomRuleTestCaseV1"
+                    }
+                }
+            }
+        },
+        "v1.DryRunCustomRuleResDataV1": {
+            "type": "object",
+            "properties": {
+                "sql_results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CustomRuleSQLResultV1"
+                    }
+                },
+                "test_case_results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CustomRuleTestCaseResultV1"
+                    }
                 }
             }
         },
-        "v1.DirectAuditResV1": {
+        "v1.DryRunCustomRuleResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -9445,7 +11418,7 @@ var doc = `{
                 },
                 "data": {
                     "type": "object",
-                    "$ref": "#/definitions/v1.AuditResDataV1"
+                    "$ref": "#/definitions/v1.DryRunCustomRuleResDataV1"
                 },
                 "message": {
                     "type": "string",
@@ -9453,33 +11426,78 @@ var doc = `{
                 }
             }
         },
-        "v1.DirectGetSQLAnalysisResV1": {
+        "v1.EventDeliveryV1": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 0
+                "attempts": {
+                    "type": "integer"
                 },
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.SqlAnalysisResDataV1"
-                    }
+                "created_at": {
+                    "type": "string"
                 },
-                "message": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "redelivery_of": {
+                    "type": "integer"
+                },
+                "response_body": {
+                    "type": "string"
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
                     "type": "string",
-                    "example": "ok"
+                    "enum": [
+                        "pending",
+                        "succeeded",
+                        "failed"
+                    ]
                 }
             }
         },
-        "v1.DriversResV1": {
+        "v1.EventSubscriptionV1": {
             "type": "object",
             "properties": {
-                "driver_name_list": {
+                "event_types": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
@@ -9556,6 +11574,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetActionQueueResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.ActionQueueResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetAuditPlanAnalysisDataResV1": {
             "type": "object",
             "properties": {
@@ -9573,6 +11608,25 @@ var doc = `{
                 }
             }
         },
+        "v1.GetAuditPlanIndexAdviceResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.AuditPlanTableIndexAdviceResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetAuditPlanMetasResV1": {
             "type": "object",
             "properties": {
@@ -9771,6 +11825,40 @@ var doc = `{
                 }
             }
         },
+        "v1.GetAuditSuppressionSettingResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditSuppressionSettingResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetAuditTaskProgressResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditTaskProgressResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetAuditTaskResV1": {
             "type": "object",
             "properties": {
@@ -9849,6 +11937,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetClusterStatusResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.ClusterStatusResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetCompanyNoticeResp": {
             "type": "object",
             "properties": {
@@ -9972,6 +12077,47 @@ var doc = `{
                 }
             }
         },
+        "v1.GetEventDeliveriesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.EventDeliveryV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.GetEventSubscriptionsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.EventSubscriptionV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetFeishuAuditConfigurationResV1": {
             "type": "object",
             "properties": {
@@ -10529,7 +12675,77 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.RuleTypeV1"
+                        "$ref": "#/definitions/v1.RuleTypeV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetRulesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.RuleResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetSQLAnalysisDataResItemV1": {
+            "type": "object",
+            "properties": {
+                "sql_explain": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SQLExplain"
+                },
+                "table_metas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TableMeta"
+                    }
+                }
+            }
+        },
+        "v1.GetSQLAuditRecordResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SQLAuditRecord"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetSQLAuditRecordTagTipsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 },
                 "message": {
@@ -10538,7 +12754,7 @@ var doc = `{
                 }
             }
         },
-        "v1.GetRulesResV1": {
+        "v1.GetSQLAuditRecordsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -10548,31 +12764,19 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.RuleResV1"
+                        "$ref": "#/definitions/v1.SQLAuditRecord"
                     }
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
-                }
-            }
-        },
-        "v1.GetSQLAnalysisDataResItemV1": {
-            "type": "object",
-            "properties": {
-                "sql_explain": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.SQLExplain"
                 },
-                "table_metas": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.TableMeta"
-                    }
+                "total_nums": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.GetSQLAuditRecordResV1": {
+        "v1.GetSchemaDriftsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -10580,16 +12784,21 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.SQLAuditRecord"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SchemaDriftResV1"
+                    }
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.GetSQLAuditRecordTagTipsResV1": {
+        "v1.GetSchemaSnapshotsResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -10599,16 +12808,19 @@ var doc = `{
                 "data": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/v1.SchemaSnapshotResV1"
                     }
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
                 }
             }
         },
-        "v1.GetSQLAuditRecordsResV1": {
+        "v1.GetSlackConfigurationResV1": {
             "type": "object",
             "properties": {
                 "code": {
@@ -10616,17 +12828,12 @@ var doc = `{
                     "example": 0
                 },
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.SQLAuditRecord"
-                    }
+                    "type": "object",
+                    "$ref": "#/definitions/v1.SlackConfigurationV1"
                 },
                 "message": {
                     "type": "string",
                     "example": "ok"
-                },
-                "total_nums": {
-                    "type": "integer"
                 }
             }
         },
@@ -10838,6 +13045,114 @@ var doc = `{
                 }
             }
         },
+        "v1.GetTaskExecutionAttemptsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TaskExecutionAttemptResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetTaskGhostConfigResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TaskGhostConfigResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetTaskGhostProgressResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TaskGhostProgressResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetTaskRehearsalResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.TaskRehearsalResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetTeamsConfigurationResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.TeamsConfigurationV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.GetUserPreferenceResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.UserPreferenceResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetUserTipsResV1": {
             "type": "object",
             "properties": {
@@ -11414,6 +13729,24 @@ var doc = `{
                 }
             }
         },
+        "v1.OperateTaskGhostMigrationReqV1": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "throttle: 暂停拷贝; unthrottle: 恢复拷贝; postpone_cut_over: 推迟 cut-over; cut_over: 执行被推迟的 cut-over",
+                    "type": "string",
+                    "enum": [
+                        "throttle",
+                        "unthrottle",
+                        "postpone_cut_over",
+                        "cut_over"
+                    ]
+                },
+                "number": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.OperationActionList": {
             "type": "object",
             "properties": {
@@ -11808,6 +14141,31 @@ var doc = `{
                 }
             }
         },
+        "v1.RedeliverEventResDataV1": {
+            "type": "object",
+            "properties": {
+                "delivery_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.RedeliverEventResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.RedeliverEventResDataV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.RejectWorkflowReqV1": {
             "type": "object",
             "properties": {
@@ -11816,6 +14174,23 @@ var doc = `{
                 }
             }
         },
+        "v1.ResumeTaskReqV1": {
+            "type": "object",
+            "properties": {
+                "operation": {
+                    "type": "string",
+                    "enum": [
+                        "resume",
+                        "skip",
+                        "retry"
+                    ]
+                },
+                "reason": {
+                    "description": "跳过失败的 SQL 时必填",
+                    "type": "string"
+                }
+            }
+        },
         "v1.RewriteRule": {
             "type": "object",
             "properties": {
@@ -12131,6 +14506,9 @@ var doc = `{
                 "creator": {
                     "type": "string"
                 },
+                "git": {
+                    "$ref": "#/definitions/v1.SQLAuditRecordGitResV1"
+                },
                 "instance": {
                     "type": "object",
                     "$ref": "#/definitions/v1.SQLAuditRecordInstance"
@@ -12153,6 +14531,21 @@ var doc = `{
                 }
             }
         },
+        "v1.SQLAuditRecordGitResV1": {
+            "type": "object",
+            "properties": {
+                "base_commit": {
+                    "description": "为空时审核了仓库中的全部 SQL, 否则只审核了从该提交到 head_commit 变更的 SQL",
+                    "type": "string"
+                },
+                "head_commit": {
+                    "type": "string"
+                },
+                "repository_url": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.SQLAuditRecordInstance": {
             "type": "object",
             "properties": {
@@ -12169,6 +14562,9 @@ var doc = `{
         "v1.SQLAuditRecordResData": {
             "type": "object",
             "properties": {
+                "git": {
+                    "$ref": "#/definitions/v1.SQLAuditRecordGitResV1"
+                },
                 "sql_audit_record_id": {
                     "type": "string"
                 },
@@ -12200,49 +14596,132 @@ var doc = `{
                 "allow_query_when_less_than_audit_level": {
                     "type": "string",
                     "enum": [
-                        "normal",
-                        "notice",
-                        "warn",
-                        "error"
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "audit_enabled": {
+                    "type": "boolean"
+                },
+                "max_pre_query_rows": {
+                    "type": "integer"
+                },
+                "query_timeout_second": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.ScheduleTaskDefaultOption": {
+            "type": "object",
+            "properties": {
+                "default_selector": {
+                    "type": "string",
+                    "enum": [
+                        "wechat",
+                        "feishu"
+                    ]
+                }
+            }
+        },
+        "v1.ScheduledTaskDefaultOptionV1Rsp": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.ScheduleTaskDefaultOption"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.SchemaDriftResV1": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "string"
+                },
+                "change_type": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "dropped",
+                        "modified"
+                    ]
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "object_name": {
+                    "type": "string",
+                    "example": "c1"
+                },
+                "object_type": {
+                    "type": "string",
+                    "enum": [
+                        "table",
+                        "column",
+                        "index"
                     ]
                 },
-                "audit_enabled": {
+                "out_of_band": {
                     "type": "boolean"
                 },
-                "max_pre_query_rows": {
-                    "type": "integer"
+                "table_name": {
+                    "type": "string",
+                    "example": "t1"
                 },
-                "query_timeout_second": {
+                "task_id": {
                     "type": "integer"
                 }
             }
         },
-        "v1.ScheduleTaskDefaultOption": {
+        "v1.SchemaSnapshotResV1": {
             "type": "object",
             "properties": {
-                "default_selector": {
+                "created_at": {
+                    "type": "string"
+                },
+                "drift_count": {
+                    "type": "integer"
+                },
+                "last_collected_at": {
+                    "type": "string"
+                },
+                "out_of_band_drift_count": {
+                    "type": "integer"
+                },
+                "schema": {
                     "type": "string",
-                    "enum": [
-                        "wechat",
-                        "feishu"
-                    ]
+                    "example": "db1"
+                },
+                "table_count": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "v1.ScheduledTaskDefaultOptionV1Rsp": {
+        "v1.SlackConfigurationV1": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "data": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.ScheduleTaskDefaultOption"
+                "base_url": {
+                    "type": "string"
                 },
-                "message": {
-                    "type": "string",
-                    "example": "ok"
+                "is_slack_approval_enabled": {
+                    "type": "boolean"
                 }
             }
         },
@@ -12452,6 +14931,30 @@ var doc = `{
                 }
             }
         },
+        "v1.SqledActionResV1": {
+            "type": "object",
+            "properties": {
+                "action_type": {
+                    "type": "string",
+                    "enum": [
+                        "audit",
+                        "execute",
+                        "rollback",
+                        "rehearsal",
+                        "resume"
+                    ]
+                },
+                "lease_expired_at": {
+                    "type": "string"
+                },
+                "lease_owner": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.StatisticAuditPlanResV1": {
             "type": "object",
             "properties": {
@@ -12516,9 +15019,28 @@ var doc = `{
                 "cb_operation_logs_expired_hours": {
                     "type": "integer"
                 },
+                "max_concurrent_audits": {
+                    "description": "0 表示不限制",
+                    "type": "integer"
+                },
+                "max_concurrent_ddl_executions_per_instance": {
+                    "description": "0 表示不限制",
+                    "type": "integer"
+                },
+                "max_concurrent_executions_per_instance": {
+                    "description": "0 表示不限制",
+                    "type": "integer"
+                },
                 "operation_record_expired_hours": {
                     "type": "integer"
                 },
+                "rollback_mode": {
+                    "type": "string",
+                    "enum": [
+                        "snapshot",
+                        "binlog"
+                    ]
+                },
                 "url": {
                     "type": "string"
                 },
@@ -12555,73 +15077,300 @@ var doc = `{
                 }
             }
         },
-        "v1.TableIndexes": {
+        "v1.TableIndexes": {
+            "type": "object",
+            "properties": {
+                "head": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TableMetaItemHeadResV1"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "v1.TableMeta": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.TableColumns"
+                },
+                "create_table_sql": {
+                    "type": "string"
+                },
+                "indexes": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.TableIndexes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TableMetaItemHeadResV1": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "field_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TableMetas": {
+            "type": "object",
+            "properties": {
+                "err_message": {
+                    "type": "string"
+                },
+                "table_meta_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TableMeta"
+                    }
+                }
+            }
+        },
+        "v1.TaskExecutionAttemptResV1": {
+            "type": "object",
+            "properties": {
+                "checkpoint": {
+                    "type": "integer"
+                },
+                "exec_end_at": {
+                    "type": "string"
+                },
+                "exec_start_at": {
+                    "type": "string"
+                },
+                "from_number": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operation": {
+                    "type": "string",
+                    "enum": [
+                        "execute",
+                        "resume",
+                        "skip",
+                        "retry"
+                    ]
+                },
+                "operator_name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "sql_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TaskExecutionAttemptSQLResV1"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "executing",
+                        "succeeded",
+                        "failed"
+                    ]
+                }
+            }
+        },
+        "v1.TaskExecutionAttemptSQLResV1": {
+            "type": "object",
+            "properties": {
+                "exec_result": {
+                    "type": "string"
+                },
+                "exec_status": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "row_affects": {
+                    "type": "integer"
+                }
+            }
+        },
+        "v1.TaskGhostConfigReqV1": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TaskGhostConfigResV1": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "int",
+                        "bool",
+                        "float64"
+                    ]
+                },
+                "value": {
+                    "description": "任务的配置, 为空时使用数据源或配置文件的配置",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.TaskGhostProgressResV1": {
+            "type": "object",
+            "properties": {
+                "can_postpone_cut_over": {
+                    "type": "boolean"
+                },
+                "elapsed_seconds": {
+                    "type": "integer"
+                },
+                "eta_seconds": {
+                    "description": "预计剩余时间, 单位秒, -1 表示未知",
+                    "type": "integer"
+                },
+                "lag_seconds": {
+                    "type": "number"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "postponing_cut_over": {
+                    "type": "boolean"
+                },
+                "progress_pct": {
+                    "type": "number"
+                },
+                "rows_copied": {
+                    "type": "integer"
+                },
+                "rows_estimate": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "migrating",
+                        "throttled",
+                        "postponing cut-over"
+                    ]
+                },
+                "table": {
+                    "type": "string"
+                },
+                "throttle_reason": {
+                    "type": "string"
+                },
+                "throttled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.TaskRehearsalResV1": {
             "type": "object",
             "properties": {
-                "head": {
+                "rehearsal_sql_list": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/v1.TableMetaItemHeadResV1"
+                        "$ref": "#/definitions/v1.TaskRehearsalSQLResV1"
                     }
                 },
-                "rows": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "additionalProperties": {
-                            "type": "string"
-                        }
-                    }
+                "rehearsed_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "rehearsing",
+                        "succeeded",
+                        "failed"
+                    ]
                 }
             }
         },
-        "v1.TableMeta": {
+        "v1.TaskRehearsalSQLResV1": {
             "type": "object",
             "properties": {
-                "columns": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.TableColumns"
+                "execution_time": {
+                    "description": "执行耗时，单位毫秒",
+                    "type": "integer"
                 },
-                "create_table_sql": {
+                "message": {
                     "type": "string"
                 },
-                "indexes": {
-                    "type": "object",
-                    "$ref": "#/definitions/v1.TableIndexes"
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "transaction",
+                        "gh-ost"
+                    ]
                 },
-                "message": {
-                    "type": "string"
+                "number": {
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "rows_affected": {
+                    "type": "integer"
                 },
-                "schema": {
+                "sql": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "succeeded",
+                        "failed",
+                        "skipped"
+                    ]
                 }
             }
         },
-        "v1.TableMetaItemHeadResV1": {
+        "v1.TeamsConfigurationV1": {
             "type": "object",
             "properties": {
-                "desc": {
+                "app_id": {
                     "type": "string"
                 },
-                "field_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "v1.TableMetas": {
-            "type": "object",
-            "properties": {
-                "err_message": {
+                "base_url": {
                     "type": "string"
                 },
-                "table_meta_items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/v1.TableMeta"
-                    }
+                "is_teams_approval_enabled": {
+                    "type": "boolean"
+                },
+                "tenant_id": {
+                    "type": "string"
                 }
             }
         },
@@ -12653,6 +15402,43 @@ var doc = `{
                 }
             }
         },
+        "v1.TestChatOpsConfigResDataV1": {
+            "type": "object",
+            "properties": {
+                "error_message": {
+                    "type": "string"
+                },
+                "is_message_sent_normally": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "v1.TestChatOpsConfigResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.TestChatOpsConfigResDataV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "v1.TestChatOpsConfigurationReqV1": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "接收测试消息的用户邮箱",
+                    "type": "string"
+                }
+            }
+        },
         "v1.TestDingTalkConfigResDataV1": {
             "type": "object",
             "properties": {
@@ -12846,6 +15632,28 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateAuditSuppressionSettingReqV1": {
+            "type": "object",
+            "properties": {
+                "need_acknowledgement": {
+                    "description": "审批人审批通过前是否需要确认被忽略的审核结果",
+                    "type": "boolean"
+                },
+                "suppressible_levels": {
+                    "description": "允许通过注释忽略的审核结果等级",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "normal",
+                            "notice",
+                            "warn",
+                            "error"
+                        ]
+                    }
+                }
+            }
+        },
         "v1.UpdateAuditTaskSQLsReqV1": {
             "type": "object",
             "properties": {
@@ -12861,14 +15669,44 @@ var doc = `{
                     "type": "string",
                     "example": "used for rapid release"
                 },
+                "expired_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "instance_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "match_type": {
                     "type": "string",
                     "enum": [
                         "exact_match",
-                        "fp_match"
+                        "fp_match",
+                        "regex_match"
                     ],
                     "example": "exact_match"
                 },
+                "never_expire": {
+                    "description": "为 true 时清除过期时间",
+                    "type": "boolean"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "rule_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "schema_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "value": {
                     "type": "string",
                     "example": "create table"
@@ -12904,9 +15742,30 @@ var doc = `{
                     ],
                     "example": "notice"
                 },
+                "params": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CustomRuleParamReqV1"
+                    }
+                },
                 "rule_script": {
                     "type": "string"
                 },
+                "script_type": {
+                    "description": "regular: 正则表达式匹配 SQL; cel: CEL 表达式, 仅支持 MySQL",
+                    "type": "string",
+                    "enum": [
+                        "regular",
+                        "cel"
+                    ]
+                },
+                "test_cases": {
+                    "description": "保存规则前会校验所有测试用例",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.CustomRuleTestCaseV1"
+                    }
+                },
                 "type": {
                     "type": "string",
                     "example": "DDL规则"
@@ -12932,6 +15791,34 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateEventSubscriptionReqV1": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "description": "订阅的事件类型，为空时订阅全部事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_enabled": {
+                    "description": "是否启用",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "订阅名称",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "签名密钥",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "接收事件的Webhook地址",
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdateFeishuConfigurationReqV1": {
             "type": "object",
             "required": [
@@ -12998,6 +15885,30 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateSlackConfigurationReqV1": {
+            "type": "object",
+            "required": [
+                "is_slack_approval_enabled"
+            ],
+            "properties": {
+                "base_url": {
+                    "description": "Slack API地址，为空时使用官方地址",
+                    "type": "string"
+                },
+                "bot_token": {
+                    "description": "Slack应用的Bot Token",
+                    "type": "string"
+                },
+                "is_slack_approval_enabled": {
+                    "description": "是否启用Slack审批",
+                    "type": "boolean"
+                },
+                "signing_secret": {
+                    "description": "Slack应用的Signing Secret，用于校验交互回调",
+                    "type": "string"
+                }
+            }
+        },
         "v1.UpdateSqlFileOrderV1Req": {
             "type": "object",
             "properties": {
@@ -13016,10 +15927,32 @@ var doc = `{
                     "type": "integer",
                     "example": 2160
                 },
+                "max_concurrent_audits": {
+                    "description": "0 表示不限制",
+                    "type": "integer",
+                    "example": 10
+                },
+                "max_concurrent_ddl_executions_per_instance": {
+                    "description": "0 表示不限制",
+                    "type": "integer",
+                    "example": 1
+                },
+                "max_concurrent_executions_per_instance": {
+                    "description": "0 表示不限制",
+                    "type": "integer",
+                    "example": 2
+                },
                 "operation_record_expired_hours": {
                     "type": "integer",
                     "example": 2160
                 },
+                "rollback_mode": {
+                    "type": "string",
+                    "enum": [
+                        "snapshot",
+                        "binlog"
+                    ]
+                },
                 "url": {
                     "type": "string",
                     "example": "http://10.186.61.32:8080"
@@ -13030,6 +15963,56 @@ var doc = `{
                 }
             }
         },
+        "v1.UpdateTaskGhostConfigReqV1": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "description": "任务的全部配置, 未包含的配置使用数据源或配置文件的配置",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.TaskGhostConfigReqV1"
+                    }
+                }
+            }
+        },
+        "v1.UpdateTeamsConfigurationReqV1": {
+            "type": "object",
+            "required": [
+                "is_teams_approval_enabled"
+            ],
+            "properties": {
+                "app_id": {
+                    "description": "Teams机器人的应用ID",
+                    "type": "string"
+                },
+                "app_password": {
+                    "description": "Teams机器人的应用密码",
+                    "type": "string"
+                },
+                "base_url": {
+                    "description": "Teams服务地址，为空时使用官方地址",
+                    "type": "string"
+                },
+                "is_teams_approval_enabled": {
+                    "description": "是否启用Teams审批",
+                    "type": "boolean"
+                },
+                "tenant_id": {
+                    "description": "Azure AD租户ID",
+                    "type": "string"
+                }
+            }
+        },
+        "v1.UpdateUserPreferenceReqV1": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "description": "为空时使用请求头 Accept-Language 或系统默认语言",
+                    "type": "string",
+                    "example": "en"
+                }
+            }
+        },
         "v1.UpdateWechatConfigurationReqV1": {
             "type": "object",
             "required": [
@@ -13089,6 +16072,27 @@ var doc = `{
                 }
             }
         },
+        "v1.UserPreferenceResV1": {
+            "type": "object",
+            "properties": {
+                "effective_language": {
+                    "description": "当前生效的语言",
+                    "type": "string",
+                    "example": "en"
+                },
+                "language": {
+                    "description": "用户选择的语言, 未选择时为空",
+                    "type": "string",
+                    "example": "en"
+                },
+                "supported_languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "v1.UserTipResV1": {
             "type": "object",
             "properties": {
@@ -13538,6 +16542,15 @@ var doc = `{
                 }
             }
         },
+        "v2.ApproveWorkflowReqV2": {
+            "type": "object",
+            "properties": {
+                "acknowledge_suppressions": {
+                    "description": "项目要求审批人确认 SQL 中通过注释忽略的审核结果时必须为 true",
+                    "type": "boolean"
+                }
+            }
+        },
         "v2.AuditFileExecStatistic": {
             "type": "object",
             "properties": {
@@ -13666,6 +16679,10 @@ var doc = `{
                         "type": "string"
                     }
                 },
+                "file_path": {
+                    "type": "string",
+                    "example": "src/main/java/com/example/UserDao.java"
+                },
                 "first_query_at": {
                     "type": "string",
                     "example": "2023-09-12T02:48:01.317880Z"
@@ -13681,6 +16698,10 @@ var doc = `{
                 "row_examined_avg": {
                     "type": "number",
                     "example": 100.22
+                },
+                "start_line": {
+                    "type": "integer",
+                    "example": 12
                 }
             }
         },
@@ -13727,6 +16748,12 @@ var doc = `{
                 },
                 "rule_name": {
                     "type": "string"
+                },
+                "suppress_reason": {
+                    "type": "string"
+                },
+                "suppressed": {
+                    "type": "boolean"
                 }
             }
         },
@@ -13764,6 +16791,10 @@ var doc = `{
                 },
                 "number": {
                     "type": "integer"
+                },
+                "start_line": {
+                    "description": "SQL 在提交内容中的起始行号",
+                    "type": "integer"
                 }
             }
         },
@@ -13803,6 +16834,10 @@ var doc = `{
                 "sql_source_file": {
                     "type": "string"
                 },
+                "sql_source_url": {
+                    "description": "从 git 仓库审核时为 SQL 所在文件的提交链接",
+                    "type": "string"
+                },
                 "sql_start_line": {
                     "type": "integer"
                 },
@@ -0,0 +1,17 @@
+package driver
+
+import "context"
+
+type auditProgressCtxKey struct{}
+
+// NewAuditProgressContext returns the context for Plugin.Audit, report is called
+// with the count of audited SQLs when the plugin audits the SQLs in chunks.
+func NewAuditProgressContext(ctx context.Context, report func(audited, total int)) context.Context {
+	return context.WithValue(ctx, auditProgressCtxKey{}, report)
+}
+
+func reportAuditProgress(ctx context.Context, audited, total int) {
+	if report, ok := ctx.Value(auditProgressCtxKey{}).(func(audited, total int)); ok {
+		report(audited, total)
+	}
+}
@@ -32,10 +32,18 @@ const (
 	ColumnsValuesNotMatchMessage       = "指定的值列数与字段列数不匹配"
 	DuplicatePrimaryKeyedColumnMessage = "主键字段 %s 重复"
 	DuplicateIndexedColumnMessage      = "索引 %s 字段 %s重复"
+	AnonymousMessage                   = "(匿名)"
+	UnparsedSQLMessage                 = "语法错误或者解析器不支持，请人工确认SQL正确性"
 )
 
 const CheckInvalidErrorFormat = "预检查失败: %v"
 const CheckInvalidError = "预检查失败"
+const ParseCreateTableFailedMessage = "解析建表语句失败，部分在线审核规则可能失效，请人工确认"
+
+const (
+	GhostUsedMessage         = "表空间大小超过%vMB, 将使用gh-ost进行上线"
+	GhostDryRunFailedMessage = "表空间大小超过%vMB, 将使用gh-ost进行上线, 但是dry-run抛出如下错误: %v"
+)
 
 func (i *MysqlDriverImpl) CheckInvalid(node ast.Node) error {
 	var err error
@@ -87,7 +95,7 @@ func (i *MysqlDriverImpl) CheckExplain(node ast.Node) error {
 		_, err = i.Ctx.GetExecutionPlan(node.Text())
 	}
 	if err != nil {
-		i.result.Add(driverV2.RuleLevelWarn, rulepkg.ConfigDMLExplainPreCheckEnable, fmt.Sprintf(CheckInvalidErrorFormat, err))
+		i.result.Add(driverV2.RuleLevelWarn, rulepkg.ConfigDMLExplainPreCheckEnable, CheckInvalidErrorFormat, err)
 	}
 	return nil
 
@@ -198,7 +206,7 @@ func (i *MysqlDriverImpl) checkInvalidCreateTableOffline(stmt *ast.CreateTableSt
 			if constraintName != "" {
 				indexesName = append(indexesName, constraint.Name)
 			} else {
-				constraintName = "(匿名)"
+				constraintName = AnonymousMessage
 			}
 			names := []string{}
 			for _, col := range constraint.Keys {
@@ -425,7 +433,7 @@ func (i *MysqlDriverImpl) checkInvalidAlterTable(stmt *ast.AlterTableStmt) error
 					indexLowerCaseNameMap.Add(indexName)
 				}
 			} else {
-				indexName = "(匿名)"
+				indexName = AnonymousMessage
 			}
 			names := []string{}
 			for _, col := range spec.Constraint.Keys {
@@ -511,7 +519,7 @@ func (i *MysqlDriverImpl) checkInvalidAlterTableOffline(stmt *ast.AlterTableStmt
 		case ast.ConstraintUniq, ast.ConstraintIndex, ast.ConstraintFulltext:
 			indexName := spec.Constraint.Name
 			if indexName == "" {
-				indexName = "(匿名)"
+				indexName = AnonymousMessage
 			}
 			names := []string{}
 			for _, col := range spec.Constraint.Keys {
@@ -1177,6 +1185,6 @@ func (i *MysqlDriverImpl) checkInvalidSelect(stmt *ast.SelectStmt) error {
 
 // checkUnparsedStmt might add more check in future.
 func (i *MysqlDriverImpl) checkUnparsedStmt(stmt *ast.UnparsedStmt) error {
-	i.result.Add(driverV2.RuleLevelWarn, "", "语法错误或者解析器不支持，请人工确认SQL正确性")
+	i.result.Add(driverV2.RuleLevelWarn, "", UnparsedSQLMessage)
 	return nil
 }
@@ -0,0 +1,72 @@
+package binlog
+
+import (
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+)
+
+// Statement is an executed statement and the binlog positions around its execution.
+type Statement struct {
+	SQL   string
+	Start *Position
+	End   *Position
+}
+
+// MatchChanges finds the row changes made by each statement, the result has
+// the same order as stmts.
+//
+// Transactions of other sessions may be committed during the execution, a row
+// change is matched to a statement when:
+//  1. the transaction is committed between the start and end position of the statement, and
+//  2. the original statement recorded in binlog is the same as the statement.
+//
+// The original statement is recorded only if binlog_rows_query_log_events is ON,
+// the changes without it are never matched, since the changes of other sessions on
+// the same table can not be told apart, see CheckRowFormat.
+func MatchChanges(stmts []*Statement, txs []*Transaction) [][]*RowChange {
+	result := make([][]*RowChange, len(stmts))
+	for _, tx := range txs {
+		var candidates []int
+		for idx, stmt := range stmts {
+			if stmt.Start == nil || stmt.End == nil {
+				continue
+			}
+			if tx.Position.Compare(stmt.Start) > 0 && tx.Position.Compare(stmt.End) <= 0 {
+				candidates = append(candidates, idx)
+			}
+		}
+		for _, change := range tx.Changes {
+			if change.Query == "" {
+				continue
+			}
+			for _, idx := range candidates {
+				if normalizeSQL(change.Query) == normalizeSQL(stmts[idx].SQL) {
+					result[idx] = append(result[idx], change)
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
+func normalizeSQL(sql string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(sql), ";"))
+}
+
+// IsDML reports whether the SQL changes rows, the rollback SQL of other SQLs
+// can not be generated from binlog.
+func IsDML(sql string) bool {
+	node, err := util.ParseOneSql(sql)
+	if err != nil {
+		return false
+	}
+	switch node.(type) {
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		return true
+	}
+	return false
+}
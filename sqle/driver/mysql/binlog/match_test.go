@@ -0,0 +1,48 @@
+package binlog
+
+import (
+	"testing"
+
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchChanges(t *testing.T) {
+	stmts := []*Statement{
+		{
+			SQL:   "UPDATE t1 SET v1 = 1 WHERE v2 = 2;",
+			Start: &Position{File: "mysql-bin.000001", Pos: 100},
+			End:   &Position{File: "mysql-bin.000001", Pos: 500},
+		},
+		{
+			SQL:   "DELETE FROM db2.t2",
+			Start: &Position{File: "mysql-bin.000001", Pos: 500},
+			End:   &Position{File: "mysql-bin.000002", Pos: 200},
+		},
+		{
+			// not executed
+			SQL: "DELETE FROM t1",
+		},
+	}
+	update := &RowChange{Schema: "db1", Table: "t1", Type: ChangeTypeUpdate, Query: "UPDATE t1 SET v1 = 1 WHERE v2 = 2"}
+	updateByOthers := &RowChange{Schema: "db1", Table: "t3", Type: ChangeTypeUpdate, Query: "UPDATE t3 SET v1 = 1"}
+	// the changes without original statement may be made by other sessions on the same table
+	updateWithoutQuery := &RowChange{Schema: "db1", Table: "t1", Type: ChangeTypeUpdate}
+	deleteRow := &RowChange{Schema: "db2", Table: "t2", Type: ChangeTypeDelete, Query: "DELETE FROM db2.t2"}
+	deleteByOthers := &RowChange{Schema: "db2", Table: "t2", Type: ChangeTypeDelete, Query: "DELETE FROM db2.t2 WHERE id = 1"}
+	txs := []*Transaction{
+		{Position: &Position{File: "mysql-bin.000001", Pos: 300}, Changes: []*RowChange{update, updateByOthers, updateWithoutQuery}},
+		{Position: &Position{File: "mysql-bin.000002", Pos: 100}, Changes: []*RowChange{deleteByOthers}},
+		{Position: &Position{File: "mysql-bin.000002", Pos: 200}, Changes: []*RowChange{deleteRow}},
+		{Position: &Position{File: "mysql-bin.000002", Pos: 300}, Changes: []*RowChange{update}},
+	}
+	assert.Equal(t, [][]*RowChange{{update}, {deleteRow}, nil}, MatchChanges(stmts, txs))
+}
+
+func TestPositionCompare(t *testing.T) {
+	p := &Position{File: "mysql-bin.000002", Pos: 100}
+	assert.Equal(t, 0, p.Compare(&Position{File: "mysql-bin.000002", Pos: 100}))
+	assert.Equal(t, -1, p.Compare(&Position{File: "mysql-bin.000002", Pos: 200}))
+	assert.Equal(t, 1, p.Compare(&Position{File: "mysql-bin.000001", Pos: 200}))
+	assert.Equal(t, -1, p.Compare(&Position{File: "mysql-bin.000010", Pos: 4}))
+}
@@ -0,0 +1,93 @@
+package binlog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+)
+
+// Position is a position in the binlog of MySQL.
+type Position struct {
+	File string
+	Pos  uint32
+	// GTIDSet is the executed GTID set at this position, it is empty if GTID mode is off.
+	GTIDSet string
+}
+
+func (p *Position) String() string {
+	return fmt.Sprintf("%s:%d", p.File, p.Pos)
+}
+
+// Compare returns -1 if p is before o, 1 if p is after o, otherwise 0.
+// Binlog files share the same base name with a zero padded sequence number,
+// so the file names can be compared as strings.
+func (p *Position) Compare(o *Position) int {
+	switch {
+	case p.File < o.File:
+		return -1
+	case p.File > o.File:
+		return 1
+	case p.Pos < o.Pos:
+		return -1
+	case p.Pos > o.Pos:
+		return 1
+	}
+	return 0
+}
+
+// GetCurrentPosition returns the current binlog position of the MySQL instance.
+func GetCurrentPosition(conn *executor.Executor) (*Position, error) {
+	records, err := conn.Db.Query("SHOW MASTER STATUS")
+	if err != nil {
+		// "SHOW MASTER STATUS" is removed since MySQL 8.4.
+		var retryErr error
+		records, retryErr = conn.Db.Query("SHOW BINARY LOG STATUS")
+		if retryErr != nil {
+			return nil, err
+		}
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("binlog is not enabled")
+	}
+	record := records[0]
+	pos, err := strconv.ParseUint(record["Position"].String, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parse binlog position %s failed: %v", record["Position"].String, err)
+	}
+	return &Position{
+		File:    record["File"].String,
+		Pos:     uint32(pos),
+		GTIDSet: strings.ReplaceAll(record["Executed_Gtid_Set"].String, "\n", ""),
+	}, nil
+}
+
+// CheckRowFormat checks the binlog contains the full before and after image
+// of each row and the original statement which made the changes, which are
+// required to generate rollback SQL from binlog.
+func CheckRowFormat(conn *executor.Executor) error {
+	records, err := conn.Db.Query("SELECT @@global.log_bin AS log_bin, @@global.binlog_format AS binlog_format, " +
+		"@@global.binlog_row_image AS binlog_row_image, @@global.binlog_rows_query_log_events AS binlog_rows_query_log_events")
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("get binlog variables failed")
+	}
+	record := records[0]
+	if record["log_bin"].String != "1" && !strings.EqualFold(record["log_bin"].String, "ON") {
+		return fmt.Errorf("binlog is not enabled")
+	}
+	if !strings.EqualFold(record["binlog_format"].String, "ROW") {
+		return fmt.Errorf("binlog_format is %s, ROW is required", record["binlog_format"].String)
+	}
+	if !strings.EqualFold(record["binlog_row_image"].String, "FULL") {
+		return fmt.Errorf("binlog_row_image is %s, FULL is required", record["binlog_row_image"].String)
+	}
+	// the changes are matched to the executed statements by the original statement.
+	if record["binlog_rows_query_log_events"].String != "1" && !strings.EqualFold(record["binlog_rows_query_log_events"].String, "ON") {
+		return fmt.Errorf("binlog_rows_query_log_events is OFF, ON is required")
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package binlog
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+)
+
+type ChangeType string
+
+const (
+	ChangeTypeInsert ChangeType = "insert"
+	ChangeTypeUpdate ChangeType = "update"
+	ChangeTypeDelete ChangeType = "delete"
+)
+
+// RowChange is a row changed by a rows event in the binlog.
+type RowChange struct {
+	Schema string
+	Table  string
+	Type   ChangeType
+	// Query is the original statement which made the change, it is empty
+	// unless binlog_rows_query_log_events is ON.
+	Query string
+	// Columns is the column names recorded in binlog, it is empty unless
+	// binlog_row_metadata is FULL (MySQL 8.0.1+).
+	Columns     []string
+	ColumnTypes []byte
+	// Before is the row image before change, it is nil for insert.
+	Before []interface{}
+	// After is the row image after change, it is nil for delete.
+	After []interface{}
+}
+
+// Transaction is the row changes committed in one transaction.
+type Transaction struct {
+	// Position is the end position of the transaction.
+	Position *Position
+	Changes  []*RowChange
+}
+
+// Reader reads row changes from binlog by pretending to be a replica.
+type Reader struct {
+	cfg replication.BinlogSyncerConfig
+}
+
+func NewReader(dsn *driverV2.DSN) (*Reader, error) {
+	port, err := strconv.ParseUint(dsn.Port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %s: %v", dsn.Port, err)
+	}
+	return &Reader{
+		cfg: replication.BinlogSyncerConfig{
+			// the server id should be unique in the replication topology.
+			ServerID:   uint32(rand.New(rand.NewSource(time.Now().UnixNano())).Intn(1<<30) + 1<<30),
+			Flavor:     mysql.MySQLFlavor,
+			Host:       dsn.Host,
+			Port:       uint16(port),
+			User:       dsn.User,
+			Password:   dsn.Password,
+			Charset:    "utf8mb4",
+			UseDecimal: true,
+			// TIMESTAMP values are formatted in UTC, see formatValue.
+			TimestampStringLocation: time.UTC,
+		},
+	}, nil
+}
+
+// ReadTransactions reads transactions which have row changes between start and end.
+func (r *Reader) ReadTransactions(ctx context.Context, start, end *Position) ([]*Transaction, error) {
+	if start.Compare(end) >= 0 {
+		return nil, nil
+	}
+	syncer := replication.NewBinlogSyncer(r.cfg)
+	defer syncer.Close()
+
+	streamer, err := syncer.StartSync(mysql.Position{Name: start.File, Pos: start.Pos})
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []*Transaction
+	var current *Transaction
+	var query string
+	file := start.File
+	for {
+		ev, err := streamer.GetEvent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch e := ev.Event.(type) {
+		case *replication.RotateEvent:
+			file = string(e.NextLogName)
+			continue
+		case *replication.QueryEvent:
+			switch string(e.Query) {
+			case "BEGIN":
+				current = &Transaction{}
+				query = ""
+			case "COMMIT":
+				// transaction of non-transactional engine, such as MyISAM.
+				txs = appendTransaction(txs, current, file, ev.Header.LogPos)
+				current = nil
+			}
+		case *replication.RowsQueryEvent:
+			query = string(e.Query)
+		case *replication.RowsEvent:
+			if current == nil {
+				current = &Transaction{}
+			}
+			changes, err := newRowChanges(ev.Header.EventType, e)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range changes {
+				c.Query = query
+			}
+			current.Changes = append(current.Changes, changes...)
+		case *replication.XIDEvent:
+			txs = appendTransaction(txs, current, file, ev.Header.LogPos)
+			current = nil
+		}
+		// the LogPos of fake events sent at the start of dump is 0.
+		if ev.Header.LogPos > 0 && (&Position{File: file, Pos: ev.Header.LogPos}).Compare(end) >= 0 {
+			return txs, nil
+		}
+	}
+}
+
+func appendTransaction(txs []*Transaction, tx *Transaction, file string, pos uint32) []*Transaction {
+	if tx == nil || len(tx.Changes) == 0 {
+		return txs
+	}
+	tx.Position = &Position{File: file, Pos: pos}
+	return append(txs, tx)
+}
+
+func newRowChanges(typ replication.EventType, e *replication.RowsEvent) ([]*RowChange, error) {
+	newChange := func(changeType ChangeType) *RowChange {
+		c := &RowChange{
+			Schema:      string(e.Table.Schema),
+			Table:       string(e.Table.Table),
+			Type:        changeType,
+			ColumnTypes: e.Table.ColumnType,
+		}
+		for _, name := range e.Table.ColumnName {
+			c.Columns = append(c.Columns, string(name))
+		}
+		return c
+	}
+
+	var changes []*RowChange
+	switch typ {
+	case replication.WRITE_ROWS_EVENTv0, replication.WRITE_ROWS_EVENTv1, replication.WRITE_ROWS_EVENTv2:
+		for _, row := range e.Rows {
+			c := newChange(ChangeTypeInsert)
+			c.After = row
+			changes = append(changes, c)
+		}
+	case replication.DELETE_ROWS_EVENTv0, replication.DELETE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv2:
+		for _, row := range e.Rows {
+			c := newChange(ChangeTypeDelete)
+			c.Before = row
+			changes = append(changes, c)
+		}
+	case replication.UPDATE_ROWS_EVENTv0, replication.UPDATE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv2:
+		// the rows of update event are pairs of before image and after image.
+		if len(e.Rows)%2 != 0 {
+			return nil, fmt.Errorf("unexpected rows count %d of update event on %s.%s", len(e.Rows), e.Table.Schema, e.Table.Table)
+		}
+		for i := 0; i < len(e.Rows); i += 2 {
+			c := newChange(ChangeTypeUpdate)
+			c.Before = e.Rows[i]
+			c.After = e.Rows[i+1]
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
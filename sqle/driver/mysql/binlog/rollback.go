@@ -0,0 +1,208 @@
+package binlog
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+)
+
+// Table is the definition of a table which is used to generate rollback SQL.
+type Table struct {
+	Schema  string
+	Name    string
+	Columns []string
+	// PrimaryKey is the index of primary key columns in Columns.
+	PrimaryKey []int
+	// Unsigned reports whether the column is an unsigned integer, integers in
+	// binlog are decoded as signed.
+	Unsigned []bool
+}
+
+// GetTable gets the current definition of the table from information_schema.
+func GetTable(conn *executor.Executor, schema, table string) (*Table, error) {
+	records, err := conn.Db.Query(
+		"SELECT COLUMN_NAME, COLUMN_KEY, COLUMN_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION",
+		schema, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("table %s.%s not exist", schema, table)
+	}
+	t := &Table{Schema: schema, Name: table}
+	for idx, record := range records {
+		t.Columns = append(t.Columns, record["COLUMN_NAME"].String)
+		if record["COLUMN_KEY"].String == "PRI" {
+			t.PrimaryKey = append(t.PrimaryKey, idx)
+		}
+		t.Unsigned = append(t.Unsigned, strings.Contains(strings.ToLower(record["COLUMN_TYPE"].String), "unsigned"))
+	}
+	return t, nil
+}
+
+// GenerateRollbackSQL generates the SQL which reverts the row change.
+//
+// insert -> DELETE the inserted row
+// delete -> INSERT the deleted row
+// update -> UPDATE the row back to the before image
+func GenerateRollbackSQL(c *RowChange, t *Table) (string, error) {
+	columns := t.Columns
+	if len(c.Columns) > 0 {
+		columns = c.Columns
+	}
+	for _, row := range [][]interface{}{c.Before, c.After} {
+		if row != nil && len(row) != len(columns) {
+			return "", fmt.Errorf("the columns count of %s.%s is %d in binlog but %d in table, the table structure may be changed",
+				c.Schema, c.Table, len(row), len(columns))
+		}
+	}
+	table := fmt.Sprintf("`%s`.`%s`", c.Schema, c.Table)
+
+	switch c.Type {
+	case ChangeTypeInsert:
+		return fmt.Sprintf("DELETE FROM %s WHERE %s;", table, rowCondition(c, t, columns, c.After)), nil
+	case ChangeTypeDelete:
+		names := make([]string, 0, len(columns))
+		values := make([]string, 0, len(columns))
+		for idx, col := range columns {
+			names = append(names, fmt.Sprintf("`%s`", col))
+			values = append(values, formatValue(c, t, idx, c.Before[idx]))
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);", table, strings.Join(names, ", "), strings.Join(values, ", ")), nil
+	case ChangeTypeUpdate:
+		sets := make([]string, 0, len(columns))
+		for idx, col := range columns {
+			sets = append(sets, fmt.Sprintf("`%s`=%s", col, formatValue(c, t, idx, c.Before[idx])))
+		}
+		return fmt.Sprintf("UPDATE %s SET %s WHERE %s;", table, strings.Join(sets, ", "), rowCondition(c, t, columns, c.After)), nil
+	}
+	return "", fmt.Errorf("unknown row change type %s", c.Type)
+}
+
+// rowCondition returns the condition which matches the row by primary key,
+// or by all columns with "LIMIT 1" if the table has no primary key.
+func rowCondition(c *RowChange, t *Table, columns []string, row []interface{}) string {
+	var indexes []int
+	for _, pk := range t.PrimaryKey {
+		for idx, col := range columns {
+			if strings.EqualFold(col, t.Columns[pk]) {
+				indexes = append(indexes, idx)
+			}
+		}
+	}
+	byPrimaryKey := len(indexes) > 0 && len(indexes) == len(t.PrimaryKey)
+	if !byPrimaryKey {
+		indexes = make([]int, 0, len(columns))
+		for idx := range columns {
+			indexes = append(indexes, idx)
+		}
+	}
+	conditions := make([]string, 0, len(indexes))
+	for _, idx := range indexes {
+		if row[idx] == nil {
+			conditions = append(conditions, fmt.Sprintf("`%s` IS NULL", columns[idx]))
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("`%s`=%s", columns[idx], formatValue(c, t, idx, row[idx])))
+	}
+	where := strings.Join(conditions, " AND ")
+	if !byPrimaryKey {
+		where += " LIMIT 1"
+	}
+	return where
+}
+
+func formatValue(c *RowChange, t *Table, idx int, v interface{}) string {
+	unsigned := idx < len(t.Unsigned) && t.Unsigned[idx]
+	var colType byte
+	if idx < len(c.ColumnTypes) {
+		colType = c.ColumnTypes[idx]
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case int8:
+		if unsigned {
+			return strconv.FormatUint(uint64(uint8(v)), 10)
+		}
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		if unsigned {
+			return strconv.FormatUint(uint64(uint16(v)), 10)
+		}
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		if unsigned && colType == mysql.MYSQL_TYPE_INT24 {
+			return strconv.FormatUint(uint64(uint32(v)&0xFFFFFF), 10)
+		}
+		if unsigned {
+			return strconv.FormatUint(uint64(uint32(v)), 10)
+		}
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		if unsigned {
+			return strconv.FormatUint(uint64(v), 10)
+		}
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		if colType == mysql.MYSQL_TYPE_TIMESTAMP || colType == mysql.MYSQL_TYPE_TIMESTAMP2 {
+			// TIMESTAMP values are read in UTC, convert them to the time zone of the session which executes rollback SQL.
+			return fmt.Sprintf("CONVERT_TZ(%s, '+00:00', @@session.time_zone)", quote(v))
+		}
+		return quote(v)
+	case []byte:
+		if utf8.Valid(v) {
+			return quote(string(v))
+		}
+		return "0x" + hex.EncodeToString(v)
+	case fmt.Stringer:
+		// decimal
+		return v.String()
+	}
+	return quote(fmt.Sprintf("%v", v))
+}
+
+var quoteReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+)
+
+func quote(s string) string {
+	return "'" + quoteReplacer.Replace(s) + "'"
+}
+
+// GenerateRollbackSQLs generates rollback SQLs of the changes, the changes are
+// reverted in the reverse order of they are made.
+func GenerateRollbackSQLs(changes []*RowChange, getTable func(schema, table string) (*Table, error)) ([]string, error) {
+	sqls := make([]string, 0, len(changes))
+	for idx := len(changes) - 1; idx >= 0; idx-- {
+		c := changes[idx]
+		t, err := getTable(c.Schema, c.Table)
+		if err != nil {
+			return nil, err
+		}
+		sql, err := GenerateRollbackSQL(c, t)
+		if err != nil {
+			return nil, err
+		}
+		sqls = append(sqls, sql)
+	}
+	return sqls, nil
+}
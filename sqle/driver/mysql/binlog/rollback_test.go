@@ -0,0 +1,104 @@
+package binlog
+
+import (
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateRollbackSQL(t *testing.T) {
+	table := &Table{
+		Schema:     "db1",
+		Name:       "t1",
+		Columns:    []string{"id", "v1", "v2"},
+		PrimaryKey: []int{0},
+		Unsigned:   []bool{true, false, false},
+	}
+	tableWithoutPK := &Table{
+		Schema:  "db1",
+		Name:    "t2",
+		Columns: []string{"v1", "v2"},
+	}
+
+	cases := []struct {
+		desc   string
+		change *RowChange
+		table  *Table
+		sql    string
+	}{
+		{
+			desc:   "insert",
+			change: &RowChange{Schema: "db1", Table: "t1", Type: ChangeTypeInsert, After: []interface{}{int32(1), "a", nil}},
+			table:  table,
+			sql:    "DELETE FROM `db1`.`t1` WHERE `id`=1;",
+		},
+		{
+			desc:   "delete",
+			change: &RowChange{Schema: "db1", Table: "t1", Type: ChangeTypeDelete, Before: []interface{}{int32(1), "it's", nil}},
+			table:  table,
+			sql:    "INSERT INTO `db1`.`t1` (`id`, `v1`, `v2`) VALUES (1, 'it\\'s', NULL);",
+		},
+		{
+			desc: "update",
+			change: &RowChange{Schema: "db1", Table: "t1", Type: ChangeTypeUpdate,
+				Before: []interface{}{int32(1), "a", decimal.RequireFromString("1.50")},
+				After:  []interface{}{int32(2), "b", decimal.RequireFromString("2.00")},
+			},
+			table: table,
+			sql:   "UPDATE `db1`.`t1` SET `id`=1, `v1`='a', `v2`=1.5 WHERE `id`=2;",
+		},
+		{
+			desc:   "unsigned integer",
+			change: &RowChange{Schema: "db1", Table: "t1", Type: ChangeTypeInsert, After: []interface{}{int32(-1), "a", nil}},
+			table:  table,
+			sql:    "DELETE FROM `db1`.`t1` WHERE `id`=4294967295;",
+		},
+		{
+			desc:   "table without primary key",
+			change: &RowChange{Schema: "db1", Table: "t2", Type: ChangeTypeUpdate, Before: []interface{}{"a", nil}, After: []interface{}{"b", nil}},
+			table:  tableWithoutPK,
+			sql:    "UPDATE `db1`.`t2` SET `v1`='a', `v2`=NULL WHERE `v1`='b' AND `v2` IS NULL LIMIT 1;",
+		},
+		{
+			desc: "columns from binlog",
+			change: &RowChange{Schema: "db1", Table: "t1", Type: ChangeTypeInsert,
+				Columns: []string{"v1", "id", "v2"}, After: []interface{}{"a", int32(3), nil}},
+			table: table,
+			sql:   "DELETE FROM `db1`.`t1` WHERE `id`=3;",
+		},
+		{
+			desc: "timestamp and binary",
+			change: &RowChange{Schema: "db1", Table: "t2", Type: ChangeTypeDelete,
+				ColumnTypes: []byte{mysql.MYSQL_TYPE_TIMESTAMP2, mysql.MYSQL_TYPE_BLOB},
+				Before:      []interface{}{"2024-01-01 00:00:00", []byte{0xff, 0x00}}},
+			table: tableWithoutPK,
+			sql:   "INSERT INTO `db1`.`t2` (`v1`, `v2`) VALUES (CONVERT_TZ('2024-01-01 00:00:00', '+00:00', @@session.time_zone), 0xff00);",
+		},
+	}
+	for _, c := range cases {
+		sql, err := GenerateRollbackSQL(c.change, c.table)
+		assert.NoError(t, err, c.desc)
+		assert.Equal(t, c.sql, sql, c.desc)
+	}
+
+	_, err := GenerateRollbackSQL(&RowChange{Schema: "db1", Table: "t1", Type: ChangeTypeInsert, After: []interface{}{int32(1)}}, table)
+	assert.Error(t, err)
+}
+
+func TestGenerateRollbackSQLs(t *testing.T) {
+	table := &Table{Schema: "db1", Name: "t1", Columns: []string{"id", "v1"}, PrimaryKey: []int{0}}
+	changes := []*RowChange{
+		{Schema: "db1", Table: "t1", Type: ChangeTypeInsert, After: []interface{}{int64(1), "a"}},
+		{Schema: "db1", Table: "t1", Type: ChangeTypeUpdate, Before: []interface{}{int64(1), "a"}, After: []interface{}{int64(1), "b"}},
+	}
+	sqls, err := GenerateRollbackSQLs(changes, func(schema, name string) (*Table, error) {
+		return table, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"UPDATE `db1`.`t1` SET `id`=1, `v1`='a' WHERE `id`=1;",
+		"DELETE FROM `db1`.`t1` WHERE `id`=1;",
+	}, sqls)
+}
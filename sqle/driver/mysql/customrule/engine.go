@@ -0,0 +1,98 @@
+// Package customrule evaluates the custom rules written in CEL
+// (https://github.com/google/cel-go) against the structured view of the
+// parsed MySQL statement.
+//
+// A rule is an expression of bool or string, e.g.
+//
+//	stmt.type in ["update", "delete"] && !stmt.has_where
+//	stmt.joins.size() > params.max_join ? "too many joins: " + string(stmt.joins.size()) : ""
+//
+// The statement is hit if the expression returns true or a non-empty string,
+// and the string is used as the message of audit result. The variable "stmt"
+// is described in NewStatementView, "params" is the map of rule parameters.
+package customrule
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+// costLimit prevents the expressions with large comprehensions from blocking the audit.
+const costLimit = 1000000
+
+var env, envErr = cel.NewEnv(
+	cel.Variable("stmt", cel.MapType(cel.StringType, cel.DynType)),
+	cel.Variable("params", cel.MapType(cel.StringType, cel.DynType)),
+	ext.Strings(),
+)
+
+type Program struct {
+	prg cel.Program
+}
+
+func Compile(script string) (*Program, error) {
+	if envErr != nil {
+		return nil, envErr
+	}
+	ast, issues := env.Compile(script)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("compile rule script failed: %v", issues.Err())
+	}
+	switch ast.OutputType() {
+	case cel.BoolType, cel.StringType, cel.DynType:
+	default:
+		return nil, fmt.Errorf("rule script should return bool or string, but it returns %v", ast.OutputType())
+	}
+	prg, err := env.Program(ast, cel.CostLimit(costLimit))
+	if err != nil {
+		return nil, fmt.Errorf("compile rule script failed: %v", err)
+	}
+	return &Program{prg: prg}, nil
+}
+
+// Eval returns whether the statement is hit by the rule, the message is
+// empty if the rule returns bool.
+func (p *Program) Eval(stmt map[string]interface{}, params map[string]interface{}) (hit bool, message string, err error) {
+	out, _, err := p.prg.Eval(map[string]interface{}{
+		"stmt":   stmt,
+		"params": params,
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("evaluate rule script failed: %v", err)
+	}
+	switch v := out.Value().(type) {
+	case bool:
+		return v, "", nil
+	case string:
+		return v != "", v, nil
+	default:
+		return false, "", fmt.Errorf("rule script should return bool or string, but it returns %v", out.Type())
+	}
+}
+
+// ConvertParams converts the rule parameters to the values of their types.
+func ConvertParams(ps params.Params) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(ps))
+	for _, p := range ps {
+		var err error
+		switch p.Type {
+		case params.ParamTypeInt:
+			values[p.Key], err = strconv.ParseInt(p.Value, 10, 64)
+		case params.ParamTypeBool:
+			values[p.Key], err = strconv.ParseBool(p.Value)
+		case params.ParamTypeFloat64:
+			values[p.Key], err = strconv.ParseFloat(p.Value, 64)
+		default:
+			values[p.Key] = p.Value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("param %s value don't match \"%s\"", p.Key, p.Type)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,100 @@
+package customrule
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func evalRule(t *testing.T, script string, ps params.Params, sqls ...string) (bool, string) {
+	prg, err := Compile(script)
+	assert.NoError(t, err)
+	values, err := ConvertParams(ps)
+	assert.NoError(t, err)
+
+	ctx := session.NewMockContext(nil)
+	var hit bool
+	var message string
+	for _, sql := range sqls {
+		node, err := util.ParseOneSql(sql)
+		assert.NoError(t, err)
+		hit, message, err = prg.Eval(NewStatementView(ctx, node), values)
+		assert.NoError(t, err)
+		ctx.UpdateContext(node)
+	}
+	return hit, message
+}
+
+func TestCompile(t *testing.T) {
+	_, err := Compile(`stmt.type == "select"`)
+	assert.NoError(t, err)
+	_, err = Compile(`stmt.type == `)
+	assert.Error(t, err)
+	_, err = Compile(`1 + 1`)
+	assert.Error(t, err)
+	_, err = Compile(`unknown.type == "select"`)
+	assert.Error(t, err)
+}
+
+func TestEval(t *testing.T) {
+	noWhere := `stmt.type in ["update", "delete"] && !stmt.has_where`
+	hit, _ := evalRule(t, noWhere, nil, "DELETE FROM t1")
+	assert.True(t, hit)
+	hit, _ = evalRule(t, noWhere, nil, "DELETE FROM t1 WHERE id = 1")
+	assert.False(t, hit)
+
+	maxJoin := `stmt.joins.size() > params.max_join ? "too many joins: " + string(stmt.joins.size()) : ""`
+	ps := params.Params{{Key: "max_join", Value: "1", Type: params.ParamTypeInt}}
+	hit, message := evalRule(t, maxJoin, ps, "SELECT * FROM t1 JOIN t2 ON t1.id = t2.id LEFT JOIN t3 ON t2.id = t3.id")
+	assert.True(t, hit)
+	assert.Equal(t, "too many joins: 2", message)
+	hit, _ = evalRule(t, maxJoin, ps, "SELECT * FROM t1 JOIN t2 ON t1.id = t2.id")
+	assert.False(t, hit)
+
+	funcOnColumn := `stmt.where.exists(p, p.on_function && p.column == "created_at")`
+	hit, _ = evalRule(t, funcOnColumn, nil, "SELECT * FROM t1 WHERE date(created_at) = '2024-01-01'")
+	assert.True(t, hit)
+	hit, _ = evalRule(t, funcOnColumn, nil, "SELECT * FROM t1 WHERE created_at > '2024-01-01'")
+	assert.False(t, hit)
+
+	// the table metadata of former statements.
+	noIndex := `stmt.tables.exists(t, stmt.table_meta[t.name].exists &&
+		!stmt.table_meta[t.name].indexes.exists(i, stmt.where.exists(p, p.column in i.columns)))`
+	createTable := []string{"CREATE TABLE t1 (id INT PRIMARY KEY, name VARCHAR(32), KEY idx_name (name))"}
+	hit, _ = evalRule(t, noIndex, nil, append(createTable, "SELECT * FROM t1 WHERE age = 1")...)
+	assert.True(t, hit)
+	hit, _ = evalRule(t, noIndex, nil, append(createTable, "SELECT * FROM t1 WHERE name = 'a'")...)
+	assert.False(t, hit)
+
+	dropColumn := `"drop_column" in stmt.alter_actions`
+	hit, _ = evalRule(t, dropColumn, nil, "ALTER TABLE t1 ADD COLUMN c1 INT, DROP COLUMN c2")
+	assert.True(t, hit)
+
+	limit := `stmt.type == "select" && (!stmt.has_limit || stmt.limit > 1000)`
+	hit, _ = evalRule(t, limit, nil, "SELECT * FROM t1 LIMIT 10")
+	assert.False(t, hit)
+	hit, _ = evalRule(t, limit, nil, "SELECT * FROM t1 LIMIT 10000")
+	assert.True(t, hit)
+	// the limit of prepared statement is unknown
+	hit, _ = evalRule(t, `stmt.has_limit && stmt.limit == -1`, nil, "SELECT * FROM t1 LIMIT ?")
+	assert.True(t, hit)
+	hit, _ = evalRule(t, `stmt.has_limit && stmt.limit == -1`, nil, "SELECT * FROM t1 LIMIT 1, ?")
+	assert.True(t, hit)
+}
+
+func TestConvertParams(t *testing.T) {
+	values, err := ConvertParams(params.Params{
+		{Key: "a", Value: "1", Type: params.ParamTypeInt},
+		{Key: "b", Value: "true", Type: params.ParamTypeBool},
+		{Key: "c", Value: "x", Type: params.ParamTypeString},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": int64(1), "b": true, "c": "x"}, values)
+
+	_, err = ConvertParams(params.Params{{Key: "a", Value: "x", Type: params.ParamTypeInt}})
+	assert.Error(t, err)
+}
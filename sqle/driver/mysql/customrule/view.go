@@ -0,0 +1,423 @@
+package customrule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/pingcap/parser/ast"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+)
+
+// NewStatementView returns the structured view of the statement which is
+// bound to the variable "stmt" of custom rule, its fields are:
+//
+//	type           string, e.g. "select", "insert", "update", "delete", "create_table", "alter_table"
+//	sql            string, the text of statement
+//	tables         list of {schema, name, alias}
+//	columns        list of the column names referenced, in lower case
+//	has_where      bool
+//	where          list of predicates {column, op, value, on_function, function}
+//	has_subquery   bool
+//	has_limit      bool
+//	limit          int, -1 if the limit is not a constant
+//	joins          list of {type, table, has_on, has_using}, type is one of "inner", "cross", "left", "right"
+//	alter_actions  list of the ALTER TABLE actions, e.g. "add_column", "drop_index"
+//	table_meta     map of table name to {exists, engine, charset, comment, columns, indexes},
+//	               columns is list of {name, type, nullable, has_default, comment},
+//	               indexes is list of {name, columns, primary, unique}
+//
+// The table metadata comes from ctx, it is empty if ctx knows nothing about the
+// table, e.g. ctx has no executor connected to the instance. ctx must not be nil.
+func NewStatementView(ctx *session.Context, node ast.StmtNode) map[string]interface{} {
+	v := &statementVisitor{
+		ctx:        ctx,
+		tables:     []interface{}{},
+		columns:    []interface{}{},
+		joins:      []interface{}{},
+		seenTables: map[*ast.TableName]struct{}{},
+		seenCols:   map[string]struct{}{},
+		tableNames: []*ast.TableName{},
+	}
+	node.Accept(v)
+
+	view := map[string]interface{}{
+		"type":          statementType(node),
+		"sql":           node.Text(),
+		"tables":        v.tables,
+		"columns":       v.columns,
+		"joins":         v.joins,
+		"has_where":     false,
+		"where":         []interface{}{},
+		"has_subquery":  v.hasSubquery,
+		"has_limit":     false,
+		"limit":         int64(-1),
+		"alter_actions": []interface{}{},
+		"table_meta":    v.tableMeta(node),
+	}
+
+	var where ast.ExprNode
+	var limit *ast.Limit
+	switch stmt := node.(type) {
+	case *ast.SelectStmt:
+		where, limit = stmt.Where, stmt.Limit
+	case *ast.UpdateStmt:
+		where, limit = stmt.Where, stmt.Limit
+	case *ast.DeleteStmt:
+		where, limit = stmt.Where, stmt.Limit
+	case *ast.UnionStmt:
+		limit = stmt.Limit
+	case *ast.AlterTableStmt:
+		actions := make([]interface{}, 0, len(stmt.Specs))
+		for _, spec := range stmt.Specs {
+			actions = append(actions, alterActionName(spec.Tp))
+		}
+		view["alter_actions"] = actions
+	}
+	if where != nil {
+		view["has_where"] = true
+		view["where"] = wherePredicates(where)
+	}
+	if limit != nil {
+		view["has_limit"] = true
+		// LIMIT ? of prepared statement is not a constant, and ExprFormat panics on it.
+		if _, ok := limit.Count.(*driver.ValueExpr); ok {
+			if count, err := util.GetLimitCount(limit, -1); err == nil {
+				view["limit"] = count
+			}
+		}
+	}
+	return view
+}
+
+func statementType(node ast.StmtNode) string {
+	switch stmt := node.(type) {
+	case *ast.SelectStmt:
+		return "select"
+	case *ast.UnionStmt:
+		return "union"
+	case *ast.InsertStmt:
+		if stmt.IsReplace {
+			return "replace"
+		}
+		return "insert"
+	case *ast.UpdateStmt:
+		return "update"
+	case *ast.DeleteStmt:
+		return "delete"
+	case *ast.CreateTableStmt:
+		return "create_table"
+	case *ast.AlterTableStmt:
+		return "alter_table"
+	case *ast.DropTableStmt:
+		if stmt.IsView {
+			return "drop_view"
+		}
+		return "drop_table"
+	case *ast.TruncateTableStmt:
+		return "truncate_table"
+	case *ast.RenameTableStmt:
+		return "rename_table"
+	case *ast.CreateIndexStmt:
+		return "create_index"
+	case *ast.DropIndexStmt:
+		return "drop_index"
+	case *ast.CreateViewStmt:
+		return "create_view"
+	case *ast.CreateDatabaseStmt:
+		return "create_database"
+	case *ast.DropDatabaseStmt:
+		return "drop_database"
+	default:
+		return "other"
+	}
+}
+
+var alterActionNames = map[ast.AlterTableType]string{
+	ast.AlterTableOption:         "option",
+	ast.AlterTableAddColumns:     "add_column",
+	ast.AlterTableAddConstraint:  "add_constraint",
+	ast.AlterTableDropColumn:     "drop_column",
+	ast.AlterTableDropPrimaryKey: "drop_primary_key",
+	ast.AlterTableDropIndex:      "drop_index",
+	ast.AlterTableDropForeignKey: "drop_foreign_key",
+	ast.AlterTableModifyColumn:   "modify_column",
+	ast.AlterTableChangeColumn:   "change_column",
+	ast.AlterTableRenameColumn:   "rename_column",
+	ast.AlterTableRenameTable:    "rename_table",
+	ast.AlterTableAlterColumn:    "alter_column",
+	ast.AlterTableRenameIndex:    "rename_index",
+	ast.AlterTableDropPartition:  "drop_partition",
+}
+
+func alterActionName(tp ast.AlterTableType) string {
+	if name, ok := alterActionNames[tp]; ok {
+		return name
+	}
+	return "other"
+}
+
+type statementVisitor struct {
+	ctx         *session.Context
+	tables      []interface{}
+	columns     []interface{}
+	joins       []interface{}
+	hasSubquery bool
+
+	seenTables map[*ast.TableName]struct{}
+	seenCols   map[string]struct{}
+	tableNames []*ast.TableName
+}
+
+func (v *statementVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	switch n := in.(type) {
+	case *ast.TableSource:
+		if tn, ok := n.Source.(*ast.TableName); ok {
+			v.addTable(tn, n.AsName.O)
+		}
+	case *ast.TableName:
+		v.addTable(n, "")
+	case *ast.ColumnName:
+		name := strings.ToLower(n.Name.O)
+		if _, ok := v.seenCols[name]; !ok {
+			v.seenCols[name] = struct{}{}
+			v.columns = append(v.columns, name)
+		}
+	case *ast.Join:
+		if n.Right != nil {
+			v.joins = append(v.joins, joinView(n))
+		}
+	case *ast.SubqueryExpr:
+		v.hasSubquery = true
+	}
+	return in, false
+}
+
+func (v *statementVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *statementVisitor) addTable(tn *ast.TableName, alias string) {
+	if _, ok := v.seenTables[tn]; ok {
+		return
+	}
+	v.seenTables[tn] = struct{}{}
+	v.tableNames = append(v.tableNames, tn)
+	v.tables = append(v.tables, map[string]interface{}{
+		"schema": v.ctx.GetSchemaName(tn),
+		"name":   tn.Name.O,
+		"alias":  alias,
+	})
+}
+
+func joinView(join *ast.Join) map[string]interface{} {
+	tp := "inner"
+	switch join.Tp {
+	case ast.LeftJoin:
+		tp = "left"
+	case ast.RightJoin:
+		tp = "right"
+	default:
+		if join.On == nil && len(join.Using) == 0 && !join.NaturalJoin {
+			tp = "cross"
+		}
+	}
+	table := ""
+	if source, ok := join.Right.(*ast.TableSource); ok {
+		table = util.GetTableNameFromTableSource(source)
+	}
+	return map[string]interface{}{
+		"type":      tp,
+		"table":     table,
+		"has_on":    join.On != nil,
+		"has_using": len(join.Using) > 0,
+	}
+}
+
+func (v *statementVisitor) tableMeta(node ast.StmtNode) map[string]interface{} {
+	metas := map[string]interface{}{}
+	for _, tn := range v.tableNames {
+		if _, ok := metas[tn.Name.O]; ok {
+			continue
+		}
+		var stmt *ast.CreateTableStmt
+		if create, ok := node.(*ast.CreateTableStmt); ok && create.Table == tn {
+			stmt = create
+		} else {
+			// the error is ignored since the metadata is optional for custom rule.
+			stmt, _, _ = v.ctx.GetCreateTableStmt(tn)
+		}
+		metas[tn.Name.O] = createTableView(stmt)
+	}
+	return metas
+}
+
+func createTableView(stmt *ast.CreateTableStmt) map[string]interface{} {
+	meta := map[string]interface{}{
+		"exists":  stmt != nil,
+		"engine":  "",
+		"charset": "",
+		"comment": "",
+		"columns": []interface{}{},
+		"indexes": []interface{}{},
+	}
+	if stmt == nil {
+		return meta
+	}
+	for _, option := range stmt.Options {
+		switch option.Tp {
+		case ast.TableOptionEngine:
+			meta["engine"] = option.StrValue
+		case ast.TableOptionCharset:
+			meta["charset"] = option.StrValue
+		case ast.TableOptionComment:
+			meta["comment"] = option.StrValue
+		}
+	}
+
+	columns := make([]interface{}, 0, len(stmt.Cols))
+	indexes := []interface{}{}
+	for _, col := range stmt.Cols {
+		name := col.Name.Name.O
+		column := map[string]interface{}{
+			"name":        name,
+			"type":        "",
+			"nullable":    !util.HasOneInOptions(col.Options, ast.ColumnOptionNotNull, ast.ColumnOptionPrimaryKey),
+			"has_default": util.HasOneInOptions(col.Options, ast.ColumnOptionDefaultValue),
+			"comment":     "",
+		}
+		if col.Tp != nil {
+			column["type"] = col.Tp.CompactStr()
+		}
+		for _, option := range col.Options {
+			switch option.Tp {
+			case ast.ColumnOptionComment:
+				column["comment"] = exprValue(option.Expr)
+			case ast.ColumnOptionPrimaryKey:
+				indexes = append(indexes, indexView("PRIMARY", []string{name}, true, true))
+			case ast.ColumnOptionUniqKey:
+				indexes = append(indexes, indexView(name, []string{name}, false, true))
+			}
+		}
+		columns = append(columns, column)
+	}
+	meta["columns"] = columns
+
+	for _, constraint := range stmt.Constraints {
+		var primary, unique bool
+		switch constraint.Tp {
+		case ast.ConstraintPrimaryKey:
+			primary, unique = true, true
+		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+			unique = true
+		case ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintFulltext, ast.ConstraintSpatial:
+		default:
+			continue
+		}
+		name := constraint.Name
+		if primary {
+			name = "PRIMARY"
+		}
+		keys := make([]string, 0, len(constraint.Keys))
+		for _, key := range constraint.Keys {
+			if key.Column != nil {
+				keys = append(keys, key.Column.Name.O)
+			}
+		}
+		indexes = append(indexes, indexView(name, keys, primary, unique))
+	}
+	meta["indexes"] = indexes
+	return meta
+}
+
+func indexView(name string, columns []string, primary, unique bool) map[string]interface{} {
+	cols := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		cols = append(cols, col)
+	}
+	return map[string]interface{}{
+		"name":    name,
+		"columns": cols,
+		"primary": primary,
+		"unique":  unique,
+	}
+}
+
+func wherePredicates(where ast.ExprNode) []interface{} {
+	predicates := []interface{}{}
+	add := func(column ast.ExprNode, op, value string) {
+		predicate := map[string]interface{}{
+			"column":      "",
+			"op":          op,
+			"value":       value,
+			"on_function": false,
+			"function":    "",
+		}
+		switch col := column.(type) {
+		case *ast.ColumnNameExpr:
+			predicate["column"] = strings.ToLower(col.Name.Name.O)
+		case *ast.FuncCallExpr:
+			predicate["on_function"] = true
+			predicate["function"] = col.FnName.L
+			for _, arg := range col.Args {
+				if c, ok := arg.(*ast.ColumnNameExpr); ok {
+					predicate["column"] = strings.ToLower(c.Name.Name.O)
+					break
+				}
+			}
+		default:
+			return
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	util.ScanWhereStmt(func(expr ast.ExprNode) (skip bool) {
+		switch x := expr.(type) {
+		case *ast.BinaryOperationExpr:
+			op := strings.Builder{}
+			x.Op.Format(&op)
+			switch op.String() {
+			case "=", "!=", "<", "<=", ">", ">=", "<=>":
+				if _, ok := x.L.(*driver.ValueExpr); ok {
+					add(x.R, op.String(), exprValue(x.L))
+				} else {
+					add(x.L, op.String(), exprValue(x.R))
+				}
+			}
+		case *ast.PatternLikeExpr:
+			add(x.Expr, notOp(x.Not, "like"), exprValue(x.Pattern))
+		case *ast.PatternInExpr:
+			values := make([]string, 0, len(x.List))
+			for _, item := range x.List {
+				values = append(values, exprValue(item))
+			}
+			add(x.Expr, notOp(x.Not, "in"), strings.Join(values, ","))
+		case *ast.IsNullExpr:
+			add(x.Expr, notOp(x.Not, "is null"), "")
+		case *ast.BetweenExpr:
+			add(x.Expr, notOp(x.Not, "between"), fmt.Sprintf("%s,%s", exprValue(x.Left), exprValue(x.Right)))
+		}
+		return false
+	}, where)
+	return predicates
+}
+
+func notOp(not bool, op string) string {
+	if !not {
+		return op
+	}
+	if op == "is null" {
+		return "is not null"
+	}
+	return "not " + op
+}
+
+// exprValue returns the value of constant expression, it is empty for others.
+func exprValue(expr ast.ExprNode) string {
+	if v, ok := expr.(*driver.ValueExpr); ok && v.GetValue() != nil {
+		return fmt.Sprint(v.GetValue())
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package mysql
+
+import "github.com/actiontech/sqle/sqle/locale"
+
+// the messages of audit results and rollback reasons, they are translated in
+// "locale/messages/mysql.<language>.yaml".
+func init() {
+	for id, message := range map[string]string{
+		"mysql.audit.schema_not_exist":                SchemaNotExistMessage,
+		"mysql.audit.schema_exist":                    SchemaExistMessage,
+		"mysql.audit.table_not_exist":                 TableNotExistMessage,
+		"mysql.audit.table_exist":                     TableExistMessage,
+		"mysql.audit.column_not_exist":                ColumnNotExistMessage,
+		"mysql.audit.column_exist":                    ColumnExistMessage,
+		"mysql.audit.column_is_ambiguous":             ColumnIsAmbiguousMessage,
+		"mysql.audit.index_not_exist":                 IndexNotExistMessage,
+		"mysql.audit.index_exist":                     IndexExistMessage,
+		"mysql.audit.duplicate_columns":               DuplicateColumnsMessage,
+		"mysql.audit.duplicate_indexes":               DuplicateIndexesMessage,
+		"mysql.audit.multi_primary_key":               MultiPrimaryKeyMessage,
+		"mysql.audit.keyed_column_not_exist":          KeyedColumnNotExistMessage,
+		"mysql.audit.primary_key_exist":               PrimaryKeyExistMessage,
+		"mysql.audit.primary_key_not_exist":           PrimaryKeyNotExistMessage,
+		"mysql.audit.columns_values_not_match":        ColumnsValuesNotMatchMessage,
+		"mysql.audit.duplicate_primary_keyed_column":  DuplicatePrimaryKeyedColumnMessage,
+		"mysql.audit.duplicate_indexed_column":        DuplicateIndexedColumnMessage,
+		"mysql.audit.anonymous":                       AnonymousMessage,
+		"mysql.audit.unparsed_sql":                    UnparsedSQLMessage,
+		"mysql.audit.check_invalid_error_format":      CheckInvalidErrorFormat,
+		"mysql.audit.check_invalid_error":             CheckInvalidError,
+		"mysql.audit.parse_create_table_failed":       ParseCreateTableFailedMessage,
+		"mysql.audit.ghost_used":                      GhostUsedMessage,
+		"mysql.audit.ghost_dry_run_failed":            GhostDryRunFailedMessage,
+		"mysql.audit.osc":                             OSCMessageFormat,
+		"mysql.audit.ptosc_no_unique_index_or_pk":     PTOSCNoUniqueIndexOrPrimaryKey,
+		"mysql.audit.ptosc_avoid_unique_index":        PTOSCAvoidUniqueIndex,
+		"mysql.audit.ptosc_avoid_rename_table":        PTOSCAvoidRenameTable,
+		"mysql.audit.ptosc_avoid_no_default_value":    PTOSCAvoidNoDefaultValueOnNotNullColumn,
+		"mysql.rollback.not_support_statement":        NotSupportStatementRollback,
+		"mysql.rollback.not_support_multi_table":      NotSupportMultiTableStatementRollback,
+		"mysql.rollback.not_support_on_duplicate":     NotSupportOnDuplicatStatementRollback,
+		"mysql.rollback.not_support_sub_query":        NotSupportSubQueryStatementRollback,
+		"mysql.rollback.no_primary_key_table":         NotSupportNoPrimaryKeyTableRollback,
+		"mysql.rollback.insert_without_primary_key":   NotSupportInsertWithoutPrimaryKeyRollback,
+		"mysql.rollback.param_marker":                 NotSupportParamMarkerStatementRollback,
+		"mysql.rollback.has_variable":                 NotSupportHasVariableRollback,
+		"mysql.rollback.exceed_max_rows":              NotSupportExceedMaxRowsRollback,
+		"mysql.rollback.update_primary_key_with_expr": NotSupportUpdatePrimaryKeyWithExprRollback,
+		"mysql.rollback.on_duplicate_update_pk":       NotSupportOnDuplicateUpdatePrimaryKeyRollback,
+		"mysql.rollback.on_duplicate_conflict_multi":  NotSupportOnDuplicateConflictMultiRowsRollback,
+		"mysql.rollback.truncate_table":               NotSupportTruncateTableRollback,
+		"mysql.rollback.no_object_definition":         NotSupportNoObjectDefinitionRollback,
+	} {
+		locale.RegisterSource(id, message)
+	}
+}
@@ -0,0 +1,51 @@
+package mysql
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/stretchr/testify/assert"
+)
+
+var verbRegexp = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]`)
+
+func TestMessagesTranslated(t *testing.T) {
+	zh := locale.Messages(locale.LangZh)
+	en := locale.Messages(locale.LangEn)
+	for id, message := range zh {
+		if !strings.HasPrefix(id, "mysql.") && !strings.HasPrefix(id, "rule_category.") {
+			continue
+		}
+		assert.Contains(t, en, id)
+		// the args of message are formatted by the pattern in any language
+		assert.Equal(t, len(verbRegexp.FindAllString(message, -1)), len(verbRegexp.FindAllString(en[id], -1)), id)
+	}
+	for _, rh := range rulepkg.RuleHandlers {
+		for field, source := range map[string]string{
+			locale.RuleFieldDesc:       rh.Rule.Desc,
+			locale.RuleFieldAnnotation: rh.Rule.Annotation,
+			locale.RuleFieldMessage:    rh.Message,
+		} {
+			if source == "" {
+				continue
+			}
+			id := locale.RuleMessageId(driverV2.DriverTypeMySQL, rh.Rule.Name, field)
+			assert.NotEmpty(t, en[id], id)
+		}
+		id := locale.RuleMessageId(driverV2.DriverTypeMySQL, rh.Rule.Name, locale.RuleFieldMessage)
+		assert.Equal(t, len(verbRegexp.FindAllString(rh.Message, -1)), len(verbRegexp.FindAllString(en[id], -1)), id)
+	}
+}
+
+func TestAuditResultMessageId(t *testing.T) {
+	results := driverV2.NewAuditResults()
+	results.Add(driverV2.RuleLevelError, "", TableNotExistMessage, "`db1`.`t1`")
+	results.Add(driverV2.RuleLevelWarn, "", "unknown message")
+	assert.Equal(t, "mysql.audit.table_not_exist", results.Results[0].MessageId)
+	assert.Equal(t, []string{"`db1`.`t1`"}, results.Results[0].MessageArgs)
+	assert.Equal(t, "", results.Results[1].MessageId)
+}
@@ -328,7 +328,7 @@ func (i *MysqlDriverImpl) audit(ctx context.Context, sql string) (*driverV2.Audi
 	}
 	if err != nil && session.IsParseShowCreateTableContentErr(err) {
 		i.Logger().Errorf("check invalid failed: %v", err)
-		i.result.Add(driverV2.RuleLevelWarn, CheckInvalidError, fmt.Sprintf(CheckInvalidErrorFormat, "解析建表语句失败，部分在线审核规则可能失效，请人工确认"))
+		i.result.Add(driverV2.RuleLevelWarn, CheckInvalidError, CheckInvalidErrorFormat, ParseCreateTableFailedMessage)
 	} else if err != nil {
 		return nil, err
 	}
@@ -413,9 +413,9 @@ func (i *MysqlDriverImpl) audit(ctx context.Context, sql string) (*driverV2.Audi
 	}
 	if useGhost {
 		if _, err := i.executeByGhost(ctx, sql, true); err != nil {
-			i.result.Add(driverV2.RuleLevelError, ghostRule.Name, fmt.Sprintf("表空间大小超过%vMB, 将使用gh-ost进行上线, 但是dry-run抛出如下错误: %v", i.cnf.DDLGhostMinSize, err))
+			i.result.Add(driverV2.RuleLevelError, ghostRule.Name, GhostDryRunFailedMessage, i.cnf.DDLGhostMinSize, err)
 		} else {
-			i.result.Add(ghostRule.Level, ghostRule.Name, fmt.Sprintf("表空间大小超过%vMB, 将使用gh-ost进行上线", i.cnf.DDLGhostMinSize))
+			i.result.Add(ghostRule.Level, ghostRule.Name, GhostUsedMessage, i.cnf.DDLGhostMinSize)
 		}
 	}
 
@@ -427,7 +427,7 @@ func (i *MysqlDriverImpl) audit(ctx context.Context, sql string) (*driverV2.Audi
 		return nil, err
 	}
 	if oscCommandLine != "" {
-		i.result.Add(driverV2.RuleLevelNotice, rulepkg.ConfigDDLOSCMinSize, fmt.Sprintf("[osc]%s", oscCommandLine))
+		i.result.Add(driverV2.RuleLevelNotice, rulepkg.ConfigDDLOSCMinSize, OSCMessageFormat, oscCommandLine)
 	}
 
 	if !i.IsExecutedSQL() {
@@ -614,7 +614,7 @@ func (p *PluginProcessor) GetDriverMetas() (*driverV2.DriverMetas, error) {
 		DatabaseDefaultPort:      3306,
 		Logo:                     logo,
 		Rules:                    allRules,
-		DatabaseAdditionalParams: params.Params{},
+		DatabaseAdditionalParams: onlineddl.InstanceParams(),
 		EnabledOptionalModule: []driverV2.OptionalModule{
 			driverV2.OptionalModuleGenRollbackSQL,
 			driverV2.OptionalModuleQuery,
@@ -24,6 +24,9 @@ import (
 type Executor struct {
 	l  base.Logger
 	mc *base.MigrationContext
+	// postponeFlagFile is the postpone cut-over flag file created at the
+	// default path, it is removed after migration.
+	postponeFlagFile string
 }
 
 func NewExecutor(logger *logrus.Entry, inst *driverV2.DSN, schema string, query string) (*Executor, error) {
@@ -60,6 +63,7 @@ func NewExecutor(logger *logrus.Entry, inst *driverV2.DSN, schema string, query
 		mc.AlterStatementOptions = alterOpts
 	}
 
+	var postponeFlagFile string
 	// get migration context fields from config file
 	{
 		cfg := newDefaultConfig()
@@ -75,9 +79,17 @@ func NewExecutor(logger *logrus.Entry, inst *driverV2.DSN, schema string, query
 			}
 		}
 
+		// the config of instance and task overrides the config file.
+		if err := cfg.override(ConfigFromParams(inst.AdditionalParams)); err != nil {
+			return nil, errors.Wrap(err, "override config for gh-ost")
+		}
+
 		if err := cfg.apply(mc); err != nil {
 			return nil, errors.Wrap(err, "apply config to migration context")
 		}
+		if cfg.PostponeCutOver && cfg.PostponeCutOverFlagFile == "" {
+			postponeFlagFile = mc.PostponeCutOverFlagFile
+		}
 	}
 
 	if err := checkMigrationContext(mc); err != nil {
@@ -85,16 +97,31 @@ func NewExecutor(logger *logrus.Entry, inst *driverV2.DSN, schema string, query
 	}
 
 	return &Executor{
-		l:  la,
-		mc: mc,
+		l:                la,
+		mc:               mc,
+		postponeFlagFile: postponeFlagFile,
 	}, nil
 }
 
+// Execute runs the migration, it is registered by the key of ctx created by
+// NewContext if it is not dry-run.
 func (e *Executor) Execute(ctx context.Context, dryRun bool) error {
 	if dryRun {
 		e.mc.Noop = true
 	}
 
+	if v, ok := ctx.Value(migrationCtxKey{}).(*migrationCtxValue); ok && !dryRun {
+		register(v.key, e)
+		defer unregister(v.key)
+		if v.report != nil {
+			stop := e.reportProgress(ctx, v.report)
+			defer stop()
+		}
+	}
+	if e.postponeFlagFile != "" {
+		defer os.Remove(e.postponeFlagFile)
+	}
+
 	m := logic.NewMigrator(e.mc)
 	err := m.Migrate()
 	if err != nil {
@@ -174,6 +201,8 @@ type config struct {
 	ThrottleAdditionalFlagFile string `ini:"throttle_additional_flag_file"`
 	PostponeCutOverFlagFile    string `ini:"postpone_cut_over_flag_file"`
 	PanicFlagFile              string `ini:"panic_flag_file"`
+	// PostponeCutOver creates the postpone cut-over flag file at the default path if it is not set.
+	PostponeCutOver bool `ini:"postpone_cut_over"`
 
 	InitiallyDropSocketFile bool   `ini:"initially_drop_socket_file"`
 	ServeSocketFile         string `ini:"serve_socket_file"`
@@ -249,6 +278,7 @@ func newDefaultConfig() *config {
 		ThrottleAdditionalFlagFile:    "/tmp/gh-ost.throttle",
 		PostponeCutOverFlagFile:       "",
 		PanicFlagFile:                 "",
+		PostponeCutOver:               false,
 		InitiallyDropSocketFile:       false,
 		ServeSocketFile:               "",
 		ServeTCPPort:                  0,
@@ -293,6 +323,9 @@ func (cfg *config) apply(mc *base.MigrationContext) error {
 	mc.ThrottleFlagFile = cfg.ThrottleFlagFile
 	mc.ThrottleAdditionalFlagFile = cfg.ThrottleAdditionalFlagFile
 	mc.PostponeCutOverFlagFile = cfg.PostponeCutOverFlagFile
+	if cfg.PostponeCutOver && mc.PostponeCutOverFlagFile == "" {
+		mc.PostponeCutOverFlagFile = fmt.Sprintf("/tmp/gh-ost.%s.%s.postpone.flag", mc.DatabaseName, mc.OriginalTableName)
+	}
 	mc.IgnoreHTTPErrors = cfg.IgnoreHTTPErrors
 	mc.DropServeSocket = cfg.InitiallyDropSocketFile
 	mc.ServeTCPPort = cfg.ServeTCPPort
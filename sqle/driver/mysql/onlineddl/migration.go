@@ -0,0 +1,204 @@
+package onlineddl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/github/gh-ost/go/base"
+)
+
+// reportInterval is the interval of reporting the progress of running migration.
+const reportInterval = 5 * time.Second
+
+var ErrMigrationNotRunning = errors.New("gh-ost migration is not running")
+
+// Progress is the snapshot of a running gh-ost migration.
+type Progress struct {
+	Table        string
+	State        string
+	RowsCopied   int64
+	RowsEstimate int64
+	ProgressPct  float64
+	// ETASeconds is -1 if it is unknown.
+	ETASeconds     int64
+	LagSeconds     float64
+	Throttled      bool
+	ThrottleReason string
+	// CanPostponeCutOver is true if the migration is started with postpone_cut_over.
+	CanPostponeCutOver bool
+	PostponingCutOver  bool
+	ElapsedSeconds     int64
+}
+
+func (p *Progress) String() string {
+	eta := "N/A"
+	if p.ETASeconds >= 0 {
+		eta = (time.Duration(p.ETASeconds) * time.Second).String()
+	}
+	return fmt.Sprintf("gh-ost %s: copy %d/%d %.1f%%; lag: %.2fs; state: %s; ETA: %s",
+		p.Table, p.RowsCopied, p.RowsEstimate, p.ProgressPct, p.LagSeconds, p.State, eta)
+}
+
+type migrationCtxKey struct{}
+
+type migrationCtxValue struct {
+	key    string
+	report func(*Progress)
+}
+
+// NewContext returns a context which registers the migration executed with it
+// by key, so that it can be operated by the key, and report is called
+// periodically with its progress. report may be nil.
+func NewContext(ctx context.Context, key string, report func(*Progress)) context.Context {
+	return context.WithValue(ctx, migrationCtxKey{}, &migrationCtxValue{key: key, report: report})
+}
+
+var runningMigrations = struct {
+	sync.Mutex
+	executors map[string]*Executor
+}{executors: map[string]*Executor{}}
+
+func register(key string, e *Executor) {
+	runningMigrations.Lock()
+	runningMigrations.executors[key] = e
+	runningMigrations.Unlock()
+}
+
+func unregister(key string) {
+	runningMigrations.Lock()
+	delete(runningMigrations.executors, key)
+	runningMigrations.Unlock()
+}
+
+func getRunning(key string) (*Executor, error) {
+	runningMigrations.Lock()
+	defer runningMigrations.Unlock()
+	e, ok := runningMigrations.executors[key]
+	if !ok {
+		return nil, ErrMigrationNotRunning
+	}
+	return e, nil
+}
+
+// ListProgress returns the progress of running migrations whose key has the prefix.
+func ListProgress(keyPrefix string) map[string]*Progress {
+	runningMigrations.Lock()
+	defer runningMigrations.Unlock()
+	progress := map[string]*Progress{}
+	for key, e := range runningMigrations.executors {
+		if strings.HasPrefix(key, keyPrefix) {
+			progress[key] = e.Progress()
+		}
+	}
+	return progress
+}
+
+// Progress reads the status of migration context, which is updated by gh-ost atomically.
+func (e *Executor) Progress() *Progress {
+	mc := e.mc
+	p := &Progress{
+		Table:              fmt.Sprintf("%s.%s", mc.DatabaseName, mc.OriginalTableName),
+		State:              "migrating",
+		RowsCopied:         mc.GetTotalRowsCopied(),
+		RowsEstimate:       atomic.LoadInt64(&mc.RowsEstimate) + atomic.LoadInt64(&mc.RowsDeltaEstimate),
+		ProgressPct:        mc.GetProgressPct(),
+		ETASeconds:         mc.GetETASeconds(),
+		LagSeconds:         mc.GetCurrentLagDuration().Seconds(),
+		CanPostponeCutOver: mc.PostponeCutOverFlagFile != "",
+		PostponingCutOver:  atomic.LoadInt64(&mc.IsPostponingCutOver) > 0,
+	}
+	if !mc.StartTime.IsZero() {
+		p.ElapsedSeconds = int64(mc.ElapsedTime().Seconds())
+	}
+	if p.ETASeconds < 0 {
+		p.ETASeconds = -1
+	}
+	p.Throttled, p.ThrottleReason, _ = mc.IsThrottled()
+	switch {
+	case p.PostponingCutOver:
+		p.State = "postponing cut-over"
+	case p.Throttled:
+		p.State = "throttled"
+	}
+	return p
+}
+
+// reportProgress reports the progress periodically until stop is called, stop
+// waits for the last report so that it does not overwrite the result of migration.
+func (e *Executor) reportProgress(ctx context.Context, report func(*Progress)) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(reportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report(e.Progress())
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// Throttle pauses the row copy and binlog apply of the running migration.
+func Throttle(key string) error {
+	e, err := getRunning(key)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt64(&e.mc.ThrottleCommandedByUser, 1)
+	return nil
+}
+
+func Unthrottle(key string) error {
+	e, err := getRunning(key)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt64(&e.mc.ThrottleCommandedByUser, 0)
+	return nil
+}
+
+// PostponeCutOver postpones the cut-over after the row copy is completed, it
+// is only supported for the migration started with postpone_cut_over.
+func PostponeCutOver(key string) error {
+	e, err := getRunning(key)
+	if err != nil {
+		return err
+	}
+	if e.mc.PostponeCutOverFlagFile == "" {
+		return errors.New("the migration is not started with postpone_cut_over")
+	}
+	return base.TouchFile(e.mc.PostponeCutOverFlagFile)
+}
+
+// CutOver triggers the postponed cut-over.
+func CutOver(key string) error {
+	e, err := getRunning(key)
+	if err != nil {
+		return err
+	}
+	if e.mc.PostponeCutOverFlagFile == "" {
+		return errors.New("the migration is not started with postpone_cut_over")
+	}
+	if err := os.Remove(e.mc.PostponeCutOverFlagFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	atomic.StoreInt64(&e.mc.UserCommandedUnpostponeFlag, 1)
+	return nil
+}
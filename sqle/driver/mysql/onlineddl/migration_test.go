@@ -0,0 +1,53 @@
+package onlineddl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/github/gh-ost/go/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationActions(t *testing.T) {
+	mc := base.NewMigrationContext()
+	mc.DatabaseName, mc.OriginalTableName = "db1", "t1"
+	e := &Executor{mc: mc}
+
+	assert.Equal(t, ErrMigrationNotRunning, Throttle("1:1"))
+	register("1:1", e)
+	defer unregister("1:1")
+
+	assert.NoError(t, Throttle("1:1"))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&mc.ThrottleCommandedByUser))
+	assert.NoError(t, Unthrottle("1:1"))
+	assert.Equal(t, int64(0), atomic.LoadInt64(&mc.ThrottleCommandedByUser))
+
+	// not started with postpone_cut_over
+	assert.Error(t, PostponeCutOver("1:1"))
+	assert.Error(t, CutOver("1:1"))
+
+	mc.PostponeCutOverFlagFile = filepath.Join(t.TempDir(), "postpone.flag")
+	assert.NoError(t, PostponeCutOver("1:1"))
+	assert.FileExists(t, mc.PostponeCutOverFlagFile)
+	assert.NoError(t, CutOver("1:1"))
+	_, err := os.Stat(mc.PostponeCutOverFlagFile)
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&mc.UserCommandedUnpostponeFlag))
+
+	progress := ListProgress("1:")
+	assert.Len(t, progress, 1)
+	assert.Equal(t, "db1.t1", progress["1:1"].Table)
+	assert.Equal(t, int64(-1), progress["1:1"].ETASeconds)
+	assert.True(t, progress["1:1"].CanPostponeCutOver)
+	assert.Len(t, ListProgress("2:"), 0)
+}
+
+func TestNewContext(t *testing.T) {
+	ctx := NewContext(context.Background(), "1:2", nil)
+	v, ok := ctx.Value(migrationCtxKey{}).(*migrationCtxValue)
+	assert.True(t, ok)
+	assert.Equal(t, "1:2", v.key)
+}
@@ -0,0 +1,134 @@
+package onlineddl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/go-ini/ini"
+)
+
+// ParamKeyPrefix is the prefix of instance additional params which override
+// the gh-ost config, e.g. "ghost_chunk_size" overrides "chunk_size".
+const ParamKeyPrefix = "ghost_"
+
+// Option is a gh-ost config which can be overridden per instance or per task.
+type Option struct {
+	Key  string
+	Desc string
+	Type params.ParamType
+	// InstanceOnly means the config can only be overridden by the instance
+	// additional params, which are managed by admin. The config runs queries
+	// on the instance or guards its load, so it is not open to task submitters.
+	InstanceOnly bool
+}
+
+var Options = []Option{
+	{Key: "chunk_size", Desc: "每次拷贝的行数, 范围为 10-100000", Type: params.ParamTypeInt},
+	{Key: "max_lag_millis", Desc: "复制延迟超过该值时暂停拷贝, 单位毫秒", Type: params.ParamTypeInt},
+	{Key: "max_load", Desc: "状态变量超过阈值时暂停拷贝, 如 Threads_running=80,Threads_connected=1000", Type: params.ParamTypeString, InstanceOnly: true},
+	{Key: "critical_load", Desc: "状态变量超过阈值时中止迁移, 如 Threads_running=200", Type: params.ParamTypeString, InstanceOnly: true},
+	{Key: "cut_over_lock_timeout_seconds", Desc: "cut-over 时等待表锁的超时时间, 单位秒", Type: params.ParamTypeInt},
+	{Key: "dml_batch_size", Desc: "每批应用的 binlog 事件数, 范围为 1-100", Type: params.ParamTypeInt},
+	{Key: "nice_ratio", Desc: "每批拷贝后的休眠时间与拷贝耗时的比例, 如 0.5", Type: params.ParamTypeFloat64},
+	{Key: "default_retries", Desc: "失败操作的重试次数", Type: params.ParamTypeInt},
+	{Key: "heartbeat_interval_millis", Desc: "心跳间隔, 单位毫秒", Type: params.ParamTypeInt},
+	{Key: "throttle_query", Desc: "返回值大于 0 时暂停拷贝的查询语句", Type: params.ParamTypeString, InstanceOnly: true},
+	{Key: "postpone_cut_over", Desc: "为 true 时数据拷贝完成后推迟 cut-over, 直到手动触发", Type: params.ParamTypeBool},
+}
+
+func getOption(key string) (Option, bool) {
+	for _, o := range Options {
+		if o.Key == key {
+			return o, true
+		}
+	}
+	return Option{}, false
+}
+
+// InstanceParams returns the instance additional params of gh-ost config, the
+// empty value means the value of config file is used.
+func InstanceParams() params.Params {
+	ps := make(params.Params, 0, len(Options))
+	for _, o := range Options {
+		ps = append(ps, &params.Param{
+			Key:  ParamKeyPrefix + o.Key,
+			Desc: fmt.Sprintf("gh-ost: %s", o.Desc),
+			// the type is string so that it can be empty.
+			Type: params.ParamTypeString,
+		})
+	}
+	return ps
+}
+
+// ConfigFromParams returns the gh-ost config overridden by the instance additional params.
+func ConfigFromParams(ps params.Params) map[string]string {
+	overrides := map[string]string{}
+	for _, p := range ps {
+		if p == nil || p.Value == "" || !strings.HasPrefix(p.Key, ParamKeyPrefix) {
+			continue
+		}
+		overrides[strings.TrimPrefix(p.Key, ParamKeyPrefix)] = p.Value
+	}
+	return overrides
+}
+
+// CheckConfig returns error if the key is not in Options or the value does not match its type.
+func CheckConfig(overrides map[string]string) error {
+	for key, value := range overrides {
+		o, ok := getOption(key)
+		if !ok {
+			return fmt.Errorf("gh-ost config %s is not supported", key)
+		}
+		var err error
+		switch o.Type {
+		case params.ParamTypeInt:
+			_, err = strconv.ParseInt(value, 10, 64)
+		case params.ParamTypeFloat64:
+			_, err = strconv.ParseFloat(value, 64)
+		case params.ParamTypeBool:
+			_, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			return fmt.Errorf("gh-ost config %s value don't match \"%s\"", key, o.Type)
+		}
+	}
+	return nil
+}
+
+// CheckTaskConfig is CheckConfig for the config of task, which should not contain the instance only config.
+func CheckTaskConfig(overrides map[string]string) error {
+	if err := CheckConfig(overrides); err != nil {
+		return err
+	}
+	for key := range overrides {
+		if IsInstanceOnly(key) {
+			return fmt.Errorf("gh-ost config %s can only be set in the instance config", key)
+		}
+	}
+	return nil
+}
+
+func IsInstanceOnly(key string) bool {
+	o, ok := getOption(key)
+	return ok && o.InstanceOnly
+}
+
+func (cfg *config) override(overrides map[string]string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	if err := CheckConfig(overrides); err != nil {
+		return err
+	}
+	f := ini.Empty()
+	section := f.Section(ini.DefaultSection)
+	for key, value := range overrides {
+		if _, err := section.NewKey(key, value); err != nil {
+			return err
+		}
+	}
+	return section.MapTo(cfg)
+}
@@ -0,0 +1,55 @@
+package onlineddl
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromParams(t *testing.T) {
+	overrides := ConfigFromParams(params.Params{
+		{Key: "ghost_chunk_size", Value: "500"},
+		{Key: "ghost_max_load", Value: ""},
+		{Key: "other", Value: "1"},
+	})
+	assert.Equal(t, map[string]string{"chunk_size": "500"}, overrides)
+}
+
+func TestCheckConfig(t *testing.T) {
+	assert.NoError(t, CheckConfig(map[string]string{
+		"chunk_size":        "500",
+		"nice_ratio":        "0.5",
+		"postpone_cut_over": "true",
+		"max_load":          "Threads_running=50",
+	}))
+	assert.Error(t, CheckConfig(map[string]string{"chunk_size": "a"}))
+	assert.Error(t, CheckConfig(map[string]string{"postpone_cut_over": "a"}))
+	assert.Error(t, CheckConfig(map[string]string{"master_password": "123"}))
+}
+
+func TestCheckTaskConfig(t *testing.T) {
+	assert.NoError(t, CheckTaskConfig(map[string]string{"chunk_size": "500", "postpone_cut_over": "true"}))
+	assert.Error(t, CheckTaskConfig(map[string]string{"chunk_size": "a"}))
+	assert.Error(t, CheckTaskConfig(map[string]string{"throttle_query": "select 1"}))
+	assert.Error(t, CheckTaskConfig(map[string]string{"max_load": "Threads_running=50"}))
+	assert.Error(t, CheckTaskConfig(map[string]string{"critical_load": "Threads_running=200"}))
+}
+
+func TestConfigOverride(t *testing.T) {
+	cfg := newDefaultConfig()
+	err := cfg.override(map[string]string{
+		"chunk_size":        "500",
+		"max_lag_millis":    "3000",
+		"postpone_cut_over": "true",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), cfg.ChunkSize)
+	assert.Equal(t, int64(3000), cfg.MaxLagMillis)
+	assert.True(t, cfg.PostponeCutOver)
+	// not overridden
+	assert.Equal(t, int64(10), cfg.DMLBatchSize)
+
+	assert.Error(t, cfg.override(map[string]string{"chunk_size": "a"}))
+}
@@ -30,6 +30,10 @@ func LoadPtTemplateFromFile(fileName string) error {
 	return nil
 }
 
+// OSCMessageFormat is the format of audit result with the pt-online-schema-change
+// command line or the reason why it can not be used.
+const OSCMessageFormat = "[osc]%s"
+
 const (
 	PTOSCNoUniqueIndexOrPrimaryKey          = "至少要包含主键或者唯一键索引才能使用 pt-online-schema-change"
 	PTOSCAvoidUniqueIndex                   = "添加唯一键使用 pt-online-schema-change，可能会导致数据丢失，在数据迁移到新表时使用了insert ignore"
@@ -0,0 +1,251 @@
+// Package rehearsal executes SQLs against MySQL without changing the data or
+// schema, so that the problems such as lock wait timeout and constraint
+// violation can be found before the real execution.
+package rehearsal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/onlineddl"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+)
+
+const (
+	// DefaultLockWaitTimeout is the innodb_lock_wait_timeout of the rehearsal
+	// session, the rehearsal does not wait for the locks held by the business.
+	DefaultLockWaitTimeout = 5 * time.Second
+	// DefaultMaxTxDuration is the max time the rehearsal transaction holds the
+	// locks, the SQLs after it are skipped.
+	DefaultMaxTxDuration = 30 * time.Second
+)
+
+const (
+	// MethodTransaction executes the SQL in a transaction which is always rolled back.
+	MethodTransaction = "transaction"
+	// MethodGhost executes the ALTER TABLE by gh-ost with dry-run.
+	MethodGhost = "gh-ost"
+)
+
+// Result is the result of rehearsing a SQL.
+type Result struct {
+	Method       string
+	Status       string
+	RowsAffected int64
+	Duration     time.Duration
+	Message      string
+}
+
+type Rehearser struct {
+	entry  *logrus.Entry
+	conn   *executor.Executor
+	dsn    *driverV2.DSN
+	schema string
+
+	lockWaitTimeout time.Duration
+	maxTxDuration   time.Duration
+
+	// ghostDryRun is replaced in test.
+	ghostDryRun func(ctx context.Context, query string) error
+	// inTx reports whether there are changes to be rolled back.
+	inTx        bool
+	txStartedAt time.Time
+	// txExpired reports whether the transaction is rolled back since it holds
+	// the locks too long.
+	txExpired bool
+	// schemaChanged reports whether there are DDLs rehearsed before, which are
+	// not applied actually.
+	schemaChanged bool
+}
+
+func NewRehearser(entry *logrus.Entry, conn *executor.Executor, dsn *driverV2.DSN, schema string) *Rehearser {
+	r := &Rehearser{
+		entry:           entry,
+		conn:            conn,
+		dsn:             dsn,
+		schema:          schema,
+		lockWaitTimeout: DefaultLockWaitTimeout,
+		maxTxDuration:   DefaultMaxTxDuration,
+	}
+	r.ghostDryRun = func(ctx context.Context, query string) error {
+		e, err := onlineddl.NewExecutor(r.entry, r.dsn, r.schema, query)
+		if err != nil {
+			return err
+		}
+		return e.Execute(ctx, true)
+	}
+	return r
+}
+
+// Rehearse rehearses the SQLs in order, the result has the same order as sqls.
+//
+// DMLs and queries are executed in a transaction which is rolled back at the
+// end, so the later SQLs see the changes of the earlier ones. ALTER TABLEs are
+// executed by gh-ost with dry-run, which rolls back the transaction before it.
+// The other SQLs are skipped since they can not be rolled back.
+//
+// The time of waiting for locks and running queries is limited in the session,
+// and the transaction is rolled back if it holds the locks longer than the max
+// duration, the SQLs after it are skipped.
+func (r *Rehearser) Rehearse(ctx context.Context, sqls []string) ([]*Result, error) {
+	if err := r.limitSession(); err != nil {
+		return nil, err
+	}
+	results := make([]*Result, 0, len(sqls))
+	for _, sql := range sqls {
+		if r.inTx && time.Since(r.txStartedAt) > r.maxTxDuration {
+			if err := r.rollback(); err != nil {
+				return results, err
+			}
+			r.txExpired = true
+		}
+		if r.txExpired {
+			results = append(results, &Result{Status: StatusSkipped,
+				Message: fmt.Sprintf("演练事务持有锁的时间超过 %v，已提前回滚", r.maxTxDuration)})
+			continue
+		}
+		schemaChanged := r.schemaChanged
+		result, err := r.rehearse(ctx, sql)
+		if err != nil {
+			return results, err
+		}
+		if result.Status == StatusFailed && schemaChanged {
+			result.Message += "; 该 SQL 之前的 DDL 在演练中未实际生效，失败可能由此导致"
+		}
+		results = append(results, result)
+	}
+	return results, r.rollback()
+}
+
+// limitSession caps the time of waiting for locks, and the time of queries if
+// the MySQL supports max_execution_time.
+func (r *Rehearser) limitSession() error {
+	seconds := int64(r.lockWaitTimeout / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	if _, err := r.conn.Db.Exec(fmt.Sprintf("SET SESSION innodb_lock_wait_timeout = %d", seconds)); err != nil {
+		return err
+	}
+	// max_execution_time is supported since MySQL 5.7.8, it only limits SELECT.
+	if _, err := r.conn.Db.Exec(fmt.Sprintf("SET SESSION max_execution_time = %d", r.maxTxDuration.Milliseconds())); err != nil {
+		r.entry.Warnf("set max_execution_time of rehearsal session failed, error: %v", err)
+	}
+	return nil
+}
+
+// rehearse returns error only if the changes may not be rolled back.
+func (r *Rehearser) rehearse(ctx context.Context, sql string) (*Result, error) {
+	node, err := util.ParseOneSql(sql)
+	if err != nil {
+		return &Result{Status: StatusSkipped, Message: fmt.Sprintf("解析 SQL 失败: %v", err)}, nil
+	}
+
+	switch stmt := node.(type) {
+	case *ast.AlterTableStmt:
+		if err := r.rollback(); err != nil {
+			return nil, err
+		}
+		r.schemaChanged = true
+		result := &Result{Method: MethodGhost}
+		start := time.Now()
+		err := r.ghostDryRun(ctx, sql)
+		result.Duration = time.Since(start)
+		setStatus(result, err)
+		return result, nil
+	case ast.DDLNode:
+		r.schemaChanged = true
+		return &Result{Status: StatusSkipped, Message: "仅支持通过 gh-ost 演练 ALTER TABLE 语句"}, nil
+	case *ast.SelectStmt:
+		if stmt.SelectIntoOpt != nil {
+			return &Result{Status: StatusSkipped, Message: "不支持演练 SELECT ... INTO 语句"}, nil
+		}
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt, *ast.UnionStmt:
+	default:
+		return &Result{Status: StatusSkipped, Message: "不支持演练该类型的 SQL"}, nil
+	}
+
+	table, err := r.nonTransactionalTable(node)
+	if err != nil {
+		return &Result{Method: MethodTransaction, Status: StatusFailed, Message: err.Error()}, nil
+	}
+	if table != "" {
+		return &Result{Status: StatusSkipped, Message: fmt.Sprintf("表 %s 不支持事务，无法回滚", table)}, nil
+	}
+
+	if !r.inTx {
+		// "BEGIN" is not used, the statements after a deadlock would be
+		// committed automatically since the transaction is rolled back by MySQL.
+		if _, err := r.conn.Db.Exec("SET SESSION autocommit = 0"); err != nil {
+			return nil, err
+		}
+		r.inTx = true
+		r.txStartedAt = time.Now()
+	}
+	result := &Result{Method: MethodTransaction}
+	start := time.Now()
+	res, err := r.conn.Db.Exec(sql)
+	result.Duration = time.Since(start)
+	if err == nil && res != nil {
+		result.RowsAffected, _ = res.RowsAffected()
+	}
+	setStatus(result, err)
+	return result, nil
+}
+
+func (r *Rehearser) rollback() error {
+	if !r.inTx {
+		return nil
+	}
+	if _, err := r.conn.Db.Exec("ROLLBACK"); err != nil {
+		return err
+	}
+	r.inTx = false
+	return nil
+}
+
+// nonTransactionalTable returns the first table referenced by the SQL whose
+// engine does not support transaction.
+func (r *Rehearser) nonTransactionalTable(node ast.Node) (string, error) {
+	extractor := util.TableNameExtractor{TableNames: map[string]*ast.TableName{}}
+	node.Accept(&extractor)
+	for _, t := range extractor.TableNames {
+		schema := t.Schema.O
+		if schema == "" {
+			schema = r.schema
+		}
+		records, err := r.conn.Db.Query(
+			"SELECT ENGINE FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?",
+			schema, t.Name.O)
+		if err != nil {
+			return "", err
+		}
+		// views have no engine
+		if len(records) > 0 && records[0]["ENGINE"].Valid && !strings.EqualFold(records[0]["ENGINE"].String, "InnoDB") {
+			return fmt.Sprintf("%s.%s", schema, t.Name.O), nil
+		}
+	}
+	return "", nil
+}
+
+func setStatus(result *Result, err error) {
+	if err != nil {
+		result.Status = StatusFailed
+		result.Message = err.Error()
+		return
+	}
+	result.Status = StatusSucceeded
+}
@@ -0,0 +1,112 @@
+package rehearsal
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/log"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/assert"
+)
+
+func expectEngine(mock sqlmock.Sqlmock, table, engine string) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT ENGINE FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?")).
+		WithArgs("db1", table).
+		WillReturnRows(sqlmock.NewRows([]string{"ENGINE"}).AddRow(engine))
+}
+
+func expectLimitSession(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SET SESSION innodb_lock_wait_timeout = 5")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SET SESSION max_execution_time = 30000")).WillReturnError(errors.New("Unknown system variable 'max_execution_time'"))
+}
+
+func TestRehearse(t *testing.T) {
+	conn, mock, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+
+	var ghostQueries []string
+	r := NewRehearser(log.NewEntry(), conn, nil, "db1")
+	r.ghostDryRun = func(ctx context.Context, query string) error {
+		ghostQueries = append(ghostQueries, query)
+		return errors.New("lock wait timeout")
+	}
+
+	expectLimitSession(mock)
+	expectEngine(mock, "t1", "InnoDB")
+	mock.ExpectExec(regexp.QuoteMeta("SET SESSION autocommit = 0")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE t1 SET a = 1 WHERE id > 10")).WillReturnResult(sqlmock.NewResult(0, 3))
+	expectEngine(mock, "t2", "MyISAM")
+	expectEngine(mock, "t1", "InnoDB")
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO t1 (id) VALUES (1)")).WillReturnError(errors.New("Duplicate entry '1' for key 'PRIMARY'"))
+	mock.ExpectExec(regexp.QuoteMeta("ROLLBACK")).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectEngine(mock, "t1", "InnoDB")
+	mock.ExpectExec(regexp.QuoteMeta("SET SESSION autocommit = 0")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM t1 WHERE id = 2")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("ROLLBACK")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	results, err := r.Rehearse(context.Background(), []string{
+		"UPDATE t1 SET a = 1 WHERE id > 10",
+		"DELETE FROM t2",
+		"INSERT INTO t1 (id) VALUES (1)",
+		"ALTER TABLE t1 ADD COLUMN b INT",
+		"CREATE TABLE t3 (id INT)",
+		"SET NAMES utf8mb4",
+		"DELETE FROM t1 WHERE id = 2",
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []string{"ALTER TABLE t1 ADD COLUMN b INT"}, ghostQueries)
+
+	expected := []struct {
+		method       string
+		status       string
+		rowsAffected int64
+	}{
+		{MethodTransaction, StatusSucceeded, 3},
+		{"", StatusSkipped, 0},
+		{MethodTransaction, StatusFailed, 0},
+		{MethodGhost, StatusFailed, 0},
+		{"", StatusSkipped, 0},
+		{"", StatusSkipped, 0},
+		{MethodTransaction, StatusSucceeded, 1},
+	}
+	assert.Len(t, results, len(expected))
+	for idx, e := range expected {
+		assert.Equal(t, e.method, results[idx].Method, idx)
+		assert.Equal(t, e.status, results[idx].Status, idx)
+		assert.Equal(t, e.rowsAffected, results[idx].RowsAffected, idx)
+	}
+	assert.Equal(t, "Duplicate entry '1' for key 'PRIMARY'", results[2].Message)
+	assert.Equal(t, "lock wait timeout", results[3].Message)
+}
+
+func TestRehearse_TxExpired(t *testing.T) {
+	conn, mock, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+
+	r := NewRehearser(log.NewEntry(), conn, nil, "db1")
+	r.maxTxDuration = time.Nanosecond
+
+	mock.ExpectExec(regexp.QuoteMeta("SET SESSION innodb_lock_wait_timeout = 5")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("SET SESSION max_execution_time = 0")).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectEngine(mock, "t1", "InnoDB")
+	mock.ExpectExec(regexp.QuoteMeta("SET SESSION autocommit = 0")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE t1 SET a = 1 WHERE id > 10")).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("ROLLBACK")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	results, err := r.Rehearse(context.Background(), []string{
+		"UPDATE t1 SET a = 1 WHERE id > 10",
+		"DELETE FROM t1 WHERE id = 2",
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, results, 2)
+	assert.Equal(t, StatusSucceeded, results[0].Status)
+	assert.Equal(t, StatusSkipped, results[1].Status)
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -16,7 +17,10 @@ import (
 )
 
 func (i *MysqlDriverImpl) GenerateRollbackSql(node ast.Node) (string, string, error) {
-	switch node.(type) {
+	switch stmt := node.(type) {
+	case *ast.UnparsedStmt:
+		// CREATE/DROP TRIGGER, PROCEDURE, FUNCTION and EVENT are not supported by parser.
+		return i.generateUnparsedStmtRollbackSql(stmt)
 	case ast.DDLNode:
 		return i.GenerateDDLStmtRollbackSql(node)
 	case ast.DMLNode:
@@ -33,8 +37,20 @@ func (i *MysqlDriverImpl) GenerateDDLStmtRollbackSql(node ast.Node) (rollbackSql
 		rollbackSql, unableRollbackReason, err = i.generateCreateTableRollbackSql(stmt)
 	case *ast.CreateDatabaseStmt:
 		rollbackSql, unableRollbackReason, err = i.generateCreateSchemaRollbackSql(stmt)
+	case *ast.DropDatabaseStmt:
+		rollbackSql, unableRollbackReason, err = i.generateDropSchemaRollbackSql(stmt)
 	case *ast.DropTableStmt:
-		rollbackSql, unableRollbackReason, err = i.generateDropTableRollbackSql(stmt)
+		if stmt.IsView {
+			rollbackSql, unableRollbackReason, err = i.generateDropViewRollbackSql(stmt)
+		} else {
+			rollbackSql, unableRollbackReason, err = i.generateDropTableRollbackSql(stmt)
+		}
+	case *ast.RenameTableStmt:
+		rollbackSql, unableRollbackReason, err = i.generateRenameTableRollbackSql(stmt)
+	case *ast.TruncateTableStmt:
+		unableRollbackReason = NotSupportTruncateTableRollback
+	case *ast.CreateViewStmt:
+		rollbackSql, unableRollbackReason, err = i.generateCreateViewRollbackSql(stmt)
 	case *ast.CreateIndexStmt:
 		rollbackSql, unableRollbackReason, err = i.generateCreateIndexRollbackSql(stmt)
 	case *ast.DropIndexStmt:
@@ -82,6 +98,12 @@ const (
 	NotSupportParamMarkerStatementRollback    = "不支持回滚包含指纹的语句"
 	NotSupportHasVariableRollback             = "不支持回滚包含变量的 DML 语句"
 	NotSupportExceedMaxRowsRollback           = "预计影响行数超过配置的最大值，不生成回滚语句"
+
+	NotSupportUpdatePrimaryKeyWithExprRollback     = "不支持回滚将主键更新为非常量表达式的语句"
+	NotSupportOnDuplicateUpdatePrimaryKeyRollback  = "不支持回滚 ON DUPLICATE 更新主键的语句"
+	NotSupportOnDuplicateConflictMultiRowsRollback = "不支持回滚与多行已有数据冲突的 ON DUPLICATE 语句"
+	NotSupportTruncateTableRollback                = "TRUNCATE 语句会清空表数据，不支持回滚"
+	NotSupportNoObjectDefinitionRollback           = "无法获取对象定义，不支持回滚"
 )
 
 // generateAlterTableRollbackSql generate alter table SQL for alter table.
@@ -331,6 +353,320 @@ func (i *MysqlDriverImpl) generateDropTableRollbackSql(stmt *ast.DropTableStmt)
 	return rollbackSql, "", nil
 }
 
+// generateDropSchemaRollbackSql generate create database and create table SQL for drop database.
+// The data and the objects other than tables in the schema can not be restored.
+func (i *MysqlDriverImpl) generateDropSchemaRollbackSql(stmt *ast.DropDatabaseStmt) (string, string, error) {
+	schemaName := stmt.Name
+	schemaExist, err := i.Ctx.IsSchemaExist(schemaName)
+	if err != nil || !schemaExist {
+		return "", "", err
+	}
+	rollbackSql := fmt.Sprintf("CREATE DATABASE IF NOT EXISTS `%s`", schemaName)
+	// the schema may be created in context, it does not exist in database yet.
+	records, err := i.queryRecords(fmt.Sprintf("SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME "+
+		"FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = '%s';", escapeSingleQuote(schemaName)))
+	if err != nil {
+		return "", "", err
+	}
+	if len(records) == 1 {
+		rollbackSql = fmt.Sprintf("%s DEFAULT CHARACTER SET %s COLLATE %s", rollbackSql,
+			records[0]["DEFAULT_CHARACTER_SET_NAME"].String, records[0]["DEFAULT_COLLATION_NAME"].String)
+	}
+	rollbackSql += ";\n"
+
+	tables, err := i.Ctx.GetSchemaTables(schemaName)
+	if err != nil {
+		return "", "", err
+	}
+	createTableStmts := make([]*ast.CreateTableStmt, 0, len(tables))
+	for _, tableName := range tables {
+		table := util.NewTableName(schemaName, tableName)
+		createTableStmt, exist, err := i.Ctx.GetCreateTableStmt(table)
+		if err != nil {
+			return "", "", err
+		}
+		if !exist {
+			continue
+		}
+		// the table name in "SHOW CREATE TABLE" is not qualified by schema.
+		qualified := *createTableStmt
+		qualified.Table = table
+		qualified.Constraints = qualifyForeignKeys(schemaName, createTableStmt.Constraints)
+		createTableStmts = append(createTableStmts, &qualified)
+	}
+	createTableStmts, hasCycle := sortTablesByForeignKey(schemaName, createTableStmts)
+	createTablesSql := ""
+	for _, createTableStmt := range createTableStmts {
+		createTableSql, err := util.RestoreToSql(createTableStmt)
+		if err != nil {
+			return "", "", err
+		}
+		createTablesSql += createTableSql + ";\n"
+	}
+	// the tables which reference each other can not be created one by one with foreign key checks.
+	if hasCycle {
+		createTablesSql = "SET FOREIGN_KEY_CHECKS = 0;\n" + createTablesSql + "SET FOREIGN_KEY_CHECKS = 1;\n"
+	}
+	return rollbackSql + createTablesSql, "", nil
+}
+
+// qualifyForeignKeys qualifies the tables referenced by foreign keys with the schema, so
+// the rollback SQL does not depend on the current schema. The constraints are copied since
+// they are shared with the context.
+func qualifyForeignKeys(schemaName string, constraints []*ast.Constraint) []*ast.Constraint {
+	qualified := make([]*ast.Constraint, 0, len(constraints))
+	for _, constraint := range constraints {
+		if constraint.Tp == ast.ConstraintForeignKey && constraint.Refer != nil && constraint.Refer.Table.Schema.O == "" {
+			c := *constraint
+			refer := *constraint.Refer
+			refer.Table = util.NewTableName(schemaName, refer.Table.Name.O)
+			c.Refer = &refer
+			constraint = &c
+		}
+		qualified = append(qualified, constraint)
+	}
+	return qualified
+}
+
+// sortTablesByForeignKey sorts the tables of schema so that the referenced tables are
+// created before the tables which reference them, the tables are kept in the original
+// order otherwise. It reports whether there are tables referencing each other.
+func sortTablesByForeignKey(schemaName string, stmts []*ast.CreateTableStmt) ([]*ast.CreateTableStmt, bool) {
+	byName := make(map[string]*ast.CreateTableStmt, len(stmts))
+	for _, stmt := range stmts {
+		byName[stmt.Table.Name.L] = stmt
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(stmts))
+	sorted := make([]*ast.CreateTableStmt, 0, len(stmts))
+	hasCycle := false
+	var visit func(stmt *ast.CreateTableStmt)
+	visit = func(stmt *ast.CreateTableStmt) {
+		name := stmt.Table.Name.L
+		switch state[name] {
+		case visiting:
+			hasCycle = true
+			return
+		case visited:
+			return
+		}
+		state[name] = visiting
+		for _, constraint := range stmt.Constraints {
+			if constraint.Tp != ast.ConstraintForeignKey || constraint.Refer == nil {
+				continue
+			}
+			refer := constraint.Refer.Table
+			if refer.Schema.L != strings.ToLower(schemaName) {
+				continue
+			}
+			// the table referencing itself does not depend on others.
+			if referStmt, ok := byName[refer.Name.L]; ok && refer.Name.L != name {
+				visit(referStmt)
+			}
+		}
+		state[name] = visited
+		sorted = append(sorted, stmt)
+	}
+	for _, stmt := range stmts {
+		visit(stmt)
+	}
+	return sorted, hasCycle
+}
+
+// generateRenameTableRollbackSql generate rename table SQL for rename table.
+func (i *MysqlDriverImpl) generateRenameTableRollbackSql(stmt *ast.RenameTableStmt) (string, string, error) {
+	renamed := map[string]struct{}{}
+	pairs := []string{}
+	for _, t2t := range stmt.TableToTables {
+		oldName := i.getTableNameWithQuote(t2t.OldTable)
+		newName := i.getTableNameWithQuote(t2t.NewTable)
+		// the table may be renamed by the previous pair, such as "RENAME TABLE a TO b, b TO c".
+		if _, ok := renamed[oldName]; !ok {
+			exist, err := i.Ctx.IsTableExist(t2t.OldTable)
+			if err != nil {
+				return "", "", err
+			}
+			// if table not exist, rename table will failed.
+			if !exist {
+				return "", "", nil
+			}
+		}
+		renamed[newName] = struct{}{}
+		pairs = append([]string{fmt.Sprintf("%s TO %s", newName, oldName)}, pairs...)
+	}
+	if len(pairs) == 0 {
+		return "", "", nil
+	}
+	return fmt.Sprintf("RENAME TABLE %s", strings.Join(pairs, ", ")), "", nil
+}
+
+// generateCreateViewRollbackSql generate drop view SQL for create view, or
+// generate the old definition for create or replace view.
+func (i *MysqlDriverImpl) generateCreateViewRollbackSql(stmt *ast.CreateViewStmt) (string, string, error) {
+	schemaName := i.Ctx.GetSchemaName(stmt.ViewName)
+	viewName := stmt.ViewName.Name.String()
+	createViewSql, exist, err := i.showCreateSchemaObject(schemaObjectView, schemaName, viewName)
+	if err != nil {
+		return "", "", err
+	}
+	if !exist {
+		return fmt.Sprintf("DROP VIEW IF EXISTS %s", i.getTableNameWithQuote(stmt.ViewName)), "", nil
+	}
+	// if view exist, create view will failed.
+	if !stmt.OrReplace {
+		return "", "", nil
+	}
+	if createViewSql == "" {
+		return "", NotSupportNoObjectDefinitionRollback, nil
+	}
+	return createViewSql, "", nil
+}
+
+// generateDropViewRollbackSql generate create view SQL for drop view.
+func (i *MysqlDriverImpl) generateDropViewRollbackSql(stmt *ast.DropTableStmt) (string, string, error) {
+	rollbackSql := ""
+	for _, view := range stmt.Tables {
+		createViewSql, exist, err := i.showCreateSchemaObject(schemaObjectView, i.Ctx.GetSchemaName(view), view.Name.String())
+		if err != nil {
+			return "", "", err
+		}
+		// if view not exist, can not rollback it.
+		if !exist {
+			continue
+		}
+		if createViewSql == "" {
+			return "", NotSupportNoObjectDefinitionRollback, nil
+		}
+		rollbackSql += createViewSql + ";\n"
+	}
+	return rollbackSql, "", nil
+}
+
+// schemaObjectName matches "name", "`name`", "schema.name" and "`schema`.`name`".
+const schemaObjectName = "(?:(`(?:[^`]|``)+`|[\\w$]+)\\s*\\.\\s*)?(`(?:[^`]|``)+`|[\\w$]+)"
+
+var (
+	createSchemaObjectReg = regexp.MustCompile("(?is)^\\s*create\\s+(?:definer\\s*=\\s*\\S+\\s+)?(?:aggregate\\s+)?" +
+		"(trigger|procedure|function|event)\\s+(?:if\\s+not\\s+exists\\s+)?" + schemaObjectName)
+	dropSchemaObjectReg = regexp.MustCompile("(?is)^\\s*drop\\s+(trigger|procedure|function|event)\\s+(?:if\\s+exists\\s+)?" + schemaObjectName)
+)
+
+// generateUnparsedStmtRollbackSql generate rollback SQL for CREATE/DROP TRIGGER, PROCEDURE, FUNCTION and EVENT.
+func (i *MysqlDriverImpl) generateUnparsedStmtRollbackSql(stmt *ast.UnparsedStmt) (string, string, error) {
+	sql := stmt.Text()
+	isCreate := true
+	matches := createSchemaObjectReg.FindStringSubmatch(sql)
+	if matches == nil {
+		isCreate = false
+		matches = dropSchemaObjectReg.FindStringSubmatch(sql)
+	}
+	if matches == nil {
+		return "", "", nil
+	}
+	objectType := schemaObjectTypes[strings.ToLower(matches[1])]
+	schemaName := unquoteIdentifier(matches[2])
+	if schemaName == "" {
+		schemaName = i.Ctx.CurrentSchema()
+	}
+	objectName := unquoteIdentifier(matches[3])
+
+	createSql, exist, err := i.showCreateSchemaObject(objectType, schemaName, objectName)
+	if err != nil {
+		return "", "", err
+	}
+	if isCreate {
+		// if object exist, create will failed or do nothing.
+		if exist {
+			return "", "", nil
+		}
+		return fmt.Sprintf("DROP %s IF EXISTS `%s`.`%s`", objectType.name, schemaName, objectName), "", nil
+	}
+	// if object not exist, drop will failed or do nothing.
+	if !exist {
+		return "", "", nil
+	}
+	if createSql == "" {
+		return "", NotSupportNoObjectDefinitionRollback, nil
+	}
+	return createSql, "", nil
+}
+
+type schemaObjectType struct {
+	// name is the keyword in SHOW CREATE and DROP statement, such as VIEW.
+	name string
+	// existQuery checks the object exist or not by schema name and object name.
+	existQuery string
+	// definitionColumn is the column of the definition in SHOW CREATE result.
+	definitionColumn string
+}
+
+var (
+	schemaObjectView = schemaObjectType{
+		name:             "VIEW",
+		existQuery:       "SELECT TABLE_NAME FROM information_schema.VIEWS WHERE TABLE_SCHEMA = '%s' AND TABLE_NAME = '%s';",
+		definitionColumn: "Create View",
+	}
+
+	schemaObjectTypes = map[string]schemaObjectType{
+		"trigger": {
+			name:             "TRIGGER",
+			existQuery:       "SELECT TRIGGER_NAME FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = '%s' AND TRIGGER_NAME = '%s';",
+			definitionColumn: "SQL Original Statement",
+		},
+		"procedure": {
+			name:             "PROCEDURE",
+			existQuery:       "SELECT ROUTINE_NAME FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = '%s' AND ROUTINE_NAME = '%s' AND ROUTINE_TYPE = 'PROCEDURE';",
+			definitionColumn: "Create Procedure",
+		},
+		"function": {
+			name:             "FUNCTION",
+			existQuery:       "SELECT ROUTINE_NAME FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = '%s' AND ROUTINE_NAME = '%s' AND ROUTINE_TYPE = 'FUNCTION';",
+			definitionColumn: "Create Function",
+		},
+		"event": {
+			name:             "EVENT",
+			existQuery:       "SELECT EVENT_NAME FROM information_schema.EVENTS WHERE EVENT_SCHEMA = '%s' AND EVENT_NAME = '%s';",
+			definitionColumn: "Create Event",
+		},
+	}
+)
+
+// showCreateSchemaObject returns the definition of the object by SHOW CREATE statement, the definition
+// is empty if the user has no privilege to see it. The object name in the definition is qualified by schema.
+func (i *MysqlDriverImpl) showCreateSchemaObject(tp schemaObjectType, schemaName, objectName string) (createSql string, exist bool, err error) {
+	records, err := i.queryRecords(fmt.Sprintf(tp.existQuery, escapeSingleQuote(schemaName), escapeSingleQuote(objectName)))
+	if err != nil || len(records) == 0 {
+		return "", false, err
+	}
+	records, err = i.queryRecords(fmt.Sprintf("SHOW CREATE %s `%s`.`%s`;", tp.name, schemaName, objectName))
+	if err != nil {
+		return "", true, err
+	}
+	if len(records) != 1 || !records[0][tp.definitionColumn].Valid {
+		return "", true, nil
+	}
+	createSql = records[0][tp.definitionColumn].String
+	// the definition is executed in the schema of the task, which may be different from the schema of the object.
+	createSql = strings.Replace(createSql, fmt.Sprintf("%s `%s`", tp.name, objectName),
+		fmt.Sprintf("%s `%s`.`%s`", tp.name, schemaName, objectName), 1)
+	return createSql, true, nil
+}
+
+func unquoteIdentifier(name string) string {
+	if len(name) >= 2 && strings.HasPrefix(name, "`") && strings.HasSuffix(name, "`") {
+		return strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+	}
+	return name
+}
+
+func escapeSingleQuote(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
 // generateCreateIndexRollbackSql generate drop index SQL for create index.
 func (i *MysqlDriverImpl) generateCreateIndexRollbackSql(stmt *ast.CreateIndexStmt) (string, string, error) {
 	return fmt.Sprintf("DROP INDEX `%s` ON %s", stmt.IndexName, i.getTableNameWithQuote(stmt.Table)), "", nil
@@ -377,9 +713,6 @@ func (i *MysqlDriverImpl) generateInsertRollbackSql(stmt *ast.InsertStmt) (strin
 	if len(tables) != 1 {
 		return "", NotSupportMultiTableStatementRollback, nil
 	}
-	if stmt.OnDuplicate != nil {
-		return "", NotSupportOnDuplicatStatementRollback, nil
-	}
 	table := tables[0]
 	createTableStmt, exist, err := i.Ctx.GetCreateTableStmt(table)
 	if err != nil {
@@ -397,15 +730,12 @@ func (i *MysqlDriverImpl) generateInsertRollbackSql(stmt *ast.InsertStmt) (strin
 		return "", NotSupportNoPrimaryKeyTableRollback, nil
 	}
 
-	rollbackSql := ""
-
+	columnsName := []string{}
+	rows := [][]ast.ExprNode{}
+	switch {
 	// match "insert into table_name (column_name,...) value (v1,...)"
 	// match "insert into table_name value (v1,...)"
-	if stmt.Lists != nil {
-		if int64(len(stmt.Lists)) > i.cnf.DMLRollbackMaxRows {
-			return "", NotSupportExceedMaxRowsRollback, nil
-		}
-		columnsName := []string{}
+	case stmt.Lists != nil:
 		if stmt.Columns != nil {
 			for _, col := range stmt.Columns {
 				columnsName = append(columnsName, col.Name.String())
@@ -415,47 +745,173 @@ func (i *MysqlDriverImpl) generateInsertRollbackSql(stmt *ast.InsertStmt) (strin
 				columnsName = append(columnsName, col.Name.String())
 			}
 		}
-		for _, value := range stmt.Lists {
-			where := []string{}
-			// mysql will throw error: 1136 (21S01): Column count doesn't match value count
-			if len(columnsName) != len(value) {
-				return "", "", nil
-			}
-			for n, name := range columnsName {
-				_, isPk := pkColumnsName[name]
-				if isPk {
-					where = append(where, fmt.Sprintf("%s = '%s'", name, util.ExprFormat(value[n])))
+		rows = stmt.Lists
+	// match "insert into table_name set col_name = value1, ..."
+	case stmt.Setlist != nil:
+		row := []ast.ExprNode{}
+		for _, setExpr := range stmt.Setlist {
+			columnsName = append(columnsName, setExpr.Column.Name.String())
+			row = append(row, setExpr.Expr)
+		}
+		rows = append(rows, row)
+	default:
+		return "", "", nil
+	}
+	if int64(len(rows)) > i.cnf.DMLRollbackMaxRows {
+		return "", NotSupportExceedMaxRowsRollback, nil
+	}
+	for _, value := range rows {
+		// mysql will throw error: 1136 (21S01): Column count doesn't match value count
+		if len(columnsName) != len(value) {
+			return "", "", nil
+		}
+	}
+	if stmt.OnDuplicate != nil {
+		return i.generateInsertOnDuplicateRollbackSql(stmt, table, createTableStmt, pkColumnsName, columnsName, rows)
+	}
+
+	rollbackSql := ""
+	for _, value := range rows {
+		where, ok := getInsertedRowPrimaryKeyWhere(pkColumnsName, columnsName, value)
+		if !ok {
+			return "", NotSupportInsertWithoutPrimaryKeyRollback, nil
+		}
+		rollbackSql += fmt.Sprintf("DELETE FROM %s WHERE %s;\n",
+			i.getTableNameWithQuote(table), strings.Join(where, " AND "))
+	}
+	return rollbackSql, "", nil
+}
+
+// getInsertedRowPrimaryKeyWhere returns the conditions which match the inserted
+// row by primary key, ok is false if the primary key is not in the inserted columns.
+func getInsertedRowPrimaryKeyWhere(pkColumnsName map[string]struct{}, columnsName []string, value []ast.ExprNode) (where []string, ok bool) {
+	where = []string{}
+	for n, name := range columnsName {
+		if _, isPk := pkColumnsName[strings.ToLower(name)]; isPk {
+			where = append(where, fmt.Sprintf("%s = '%s'", name, util.ExprFormat(value[n])))
+		}
+	}
+	return where, len(where) == len(pkColumnsName)
+}
+
+// generateInsertOnDuplicateRollbackSql generate rollback SQL for "INSERT ... ON DUPLICATE KEY UPDATE".
+// The row which conflicts with an existing row on primary key or unique key is
+// restored by update SQL, the other rows are new rows and deleted by delete SQL.
+func (i *MysqlDriverImpl) generateInsertOnDuplicateRollbackSql(stmt *ast.InsertStmt, table *ast.TableName,
+	createTableStmt *ast.CreateTableStmt, pkColumnsName map[string]struct{}, columnsName []string,
+	rows [][]ast.ExprNode) (string, string, error) {
+	for _, assignment := range stmt.OnDuplicate {
+		if _, isPk := pkColumnsName[assignment.Column.Name.L]; isPk {
+			return "", NotSupportOnDuplicateUpdatePrimaryKeyRollback, nil
+		}
+	}
+	colNameDefMap := make(map[string]*ast.ColumnDef)
+	for _, col := range createTableStmt.Cols {
+		colNameDefMap[col.Name.Name.L] = col
+	}
+	uniqueKeys := getUniqueKeys(createTableStmt)
+
+	rollbackSql := ""
+	for _, value := range rows {
+		values := make(map[string]ast.ExprNode, len(columnsName))
+		for n, name := range columnsName {
+			values[strings.ToLower(name)] = value[n]
+		}
+		// the keys which are not in the inserted columns use default value,
+		// they can not be matched here.
+		conflicts := []string{}
+		for _, key := range uniqueKeys {
+			conditions := []string{}
+			for _, name := range key {
+				v, ok := values[name]
+				if !ok {
+					conditions = nil
+					break
+				}
+				expr, err := util.RestoreToSql(v)
+				if err != nil {
+					return "", "", err
 				}
+				conditions = append(conditions, fmt.Sprintf("`%s` = %s", name, expr))
 			}
-			if len(where) != len(pkColumnsName) {
+			if len(conditions) > 0 {
+				conflicts = append(conflicts, fmt.Sprintf("(%s)", strings.Join(conditions, " AND ")))
+			}
+		}
+		var records []map[string]sql.NullString
+		if len(conflicts) > 0 {
+			var err error
+			// only one conflicting row is updated by mysql, 2 rows is enough to check it.
+			records, err = i.queryRecords(fmt.Sprintf("SELECT * FROM %s WHERE %s LIMIT 2;",
+				i.getTableNameWithQuote(table), strings.Join(conflicts, " OR ")))
+			if err != nil {
+				return "", "", err
+			}
+		}
+
+		switch len(records) {
+		case 0:
+			where, ok := getInsertedRowPrimaryKeyWhere(pkColumnsName, columnsName, value)
+			if !ok {
 				return "", NotSupportInsertWithoutPrimaryKeyRollback, nil
 			}
 			rollbackSql += fmt.Sprintf("DELETE FROM %s WHERE %s;\n",
 				i.getTableNameWithQuote(table), strings.Join(where, " AND "))
+		case 1:
+			record := records[0]
+			set := []string{}
+			for _, assignment := range stmt.OnDuplicate {
+				colDef, ok := colNameDefMap[assignment.Column.Name.L]
+				if !ok {
+					return "", "", nil
+				}
+				name := colDef.Name.Name.O
+				set = append(set, fmt.Sprintf("%s = %s", name, getRecordValue(colDef, record[name])))
+			}
+			where := []string{}
+			for _, col := range createTableStmt.Cols {
+				if _, isPk := pkColumnsName[col.Name.Name.L]; isPk {
+					name := col.Name.Name.O
+					where = append(where, fmt.Sprintf("%s = %s", name, getRecordValue(col, record[name])))
+				}
+			}
+			rollbackSql += fmt.Sprintf("UPDATE %s SET %s WHERE %s;\n", i.getTableNameWithQuote(table),
+				strings.Join(set, ", "), strings.Join(where, " AND "))
+		default:
+			return "", NotSupportOnDuplicateConflictMultiRowsRollback, nil
 		}
-		return rollbackSql, "", nil
 	}
+	return rollbackSql, "", nil
+}
 
-	// match "insert into table_name set col_name = value1, ..."
-	if stmt.Setlist != nil {
-		if 1 > i.cnf.DMLRollbackMaxRows {
-			return "", NotSupportExceedMaxRowsRollback, nil
+// getUniqueKeys returns the columns of primary key and unique keys, the column names are in lower case.
+func getUniqueKeys(stmt *ast.CreateTableStmt) [][]string {
+	keys := [][]string{}
+	for _, constraint := range stmt.Constraints {
+		switch constraint.Tp {
+		case ast.ConstraintPrimaryKey, ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		default:
+			continue
 		}
-		where := []string{}
-		for _, setExpr := range stmt.Setlist {
-			name := setExpr.Column.Name.String()
-			_, isPk := pkColumnsName[name]
-			if isPk {
-				where = append(where, fmt.Sprintf("%s = '%s'", name, util.ExprFormat(setExpr.Expr)))
+		key := []string{}
+		for _, part := range constraint.Keys {
+			// functional key part can not be matched by column value.
+			if part.Column == nil {
+				key = nil
+				break
 			}
+			key = append(key, part.Column.Name.L)
 		}
-		if len(where) != len(pkColumnsName) {
-			return "", "", nil
+		if len(key) > 0 {
+			keys = append(keys, key)
 		}
-		rollbackSql = fmt.Sprintf("DELETE FROM %s WHERE %s;\n",
-			i.getTableNameWithQuote(table), strings.Join(where, " AND "))
 	}
-	return rollbackSql, "", nil
+	for _, col := range stmt.Cols {
+		if util.HasOneInOptions(col.Options, ast.ColumnOptionPrimaryKey, ast.ColumnOptionUniqKey) {
+			keys = append(keys, []string{col.Name.Name.L})
+		}
+	}
+	return keys
 }
 
 // 将二进制字段转化为十六进制字段
@@ -464,17 +920,21 @@ func getHexStrFromBytesStr(byteStr string) string {
 	return hex.EncodeToString(encode)
 }
 
+// getRecordValue returns the value of the column in record as SQL literal.
+func getRecordValue(colDef *ast.ColumnDef, value sql.NullString) string {
+	if !value.Valid {
+		return "NULL"
+	}
+	if colDef != nil && parserMysql.HasBinaryFlag(colDef.Tp.Flag) {
+		return fmt.Sprintf("X'%s'", getHexStrFromBytesStr(value.String))
+	}
+	return fmt.Sprintf("'%s'", value.String)
+}
+
 // generateDeleteRollbackSql generate insert SQL for delete.
 func (i *MysqlDriverImpl) generateDeleteRollbackSql(stmt *ast.DeleteStmt) (string, string, error) {
-	// not support multi-table syntax
 	if stmt.IsMultiTable {
-		i.Logger().Infof("not support generate rollback sql with multi-delete statement")
-		return "", NotSupportMultiTableStatementRollback, nil
-	}
-	// sub query statement
-	if util.WhereStmtHasSubQuery(stmt.Where) {
-		i.Logger().Infof("not support generate rollback sql with sub query")
-		return "", NotSupportSubQueryStatementRollback, nil
+		return i.generateMultiTableDeleteRollbackSql(stmt)
 	}
 	var err error
 	tables := util.GetTables(stmt.TableRefs.TableRefs)
@@ -509,6 +969,12 @@ func (i *MysqlDriverImpl) generateDeleteRollbackSql(stmt *ast.DeleteStmt) (strin
 	if err != nil {
 		return "", "", err
 	}
+	return i.generateDeleteRollbackSqlByRecords(table, createTableStmt, records), "", nil
+}
+
+// generateDeleteRollbackSqlByRecords generate insert SQL which restores the deleted records.
+func (i *MysqlDriverImpl) generateDeleteRollbackSqlByRecords(table *ast.TableName, createTableStmt *ast.CreateTableStmt,
+	records []map[string]sql.NullString) string {
 	values := []string{}
 
 	columnsName := []string{}
@@ -519,21 +985,11 @@ func (i *MysqlDriverImpl) generateDeleteRollbackSql(stmt *ast.DeleteStmt) (strin
 	}
 	for _, record := range records {
 		if len(record) != len(columnsName) {
-			return "", "", nil
+			return ""
 		}
 		vs := []string{}
 		for _, name := range columnsName {
-			v := "NULL"
-			if record[name].Valid {
-				colDef := colNameDefMap[name]
-				if parserMysql.HasBinaryFlag(colDef.Tp.Flag) {
-					hexStr := getHexStrFromBytesStr(record[name].String)
-					v = fmt.Sprintf("X'%s'", hexStr)
-				} else {
-					v = fmt.Sprintf("'%s'", record[name].String)
-				}
-			}
-			vs = append(vs, v)
+			vs = append(vs, getRecordValue(colNameDefMap[name], record[name]))
 		}
 		values = append(values, fmt.Sprintf("(%s)", strings.Join(vs, ", ")))
 	}
@@ -543,6 +999,39 @@ func (i *MysqlDriverImpl) generateDeleteRollbackSql(stmt *ast.DeleteStmt) (strin
 			i.getTableNameWithQuote(table), strings.Join(columnsName, "`, `"),
 			strings.Join(values, ", "))
 	}
+	return rollbackSql
+}
+
+// generateMultiTableDeleteRollbackSql generate insert SQL for each table deleted by multi-table delete,
+// such as "DELETE t1, t2 FROM t1 JOIN t2 ON t1.id = t2.t1_id WHERE ...".
+func (i *MysqlDriverImpl) generateMultiTableDeleteRollbackSql(stmt *ast.DeleteStmt) (string, string, error) {
+	if stmt.Tables == nil {
+		return "", NotSupportStatementRollback, nil
+	}
+	targets, err := i.getDMLTargets(stmt.TableRefs.TableRefs)
+	if err != nil || targets == nil {
+		return "", "", err
+	}
+	deleted := []*dmlTarget{}
+	for _, name := range stmt.Tables.Tables {
+		target := i.findDMLTarget(targets, name.Schema.L, name.Name.L)
+		if target == nil {
+			return "", NotSupportStatementRollback, nil
+		}
+		deleted = append(deleted, target)
+	}
+
+	recordsList, reason, err := i.getMultiTableRecords(deleted, stmt.TableRefs, stmt.Where)
+	if err != nil || reason != "" {
+		return "", reason, err
+	}
+	rollbackSql := ""
+	for n, target := range deleted {
+		sql := i.generateDeleteRollbackSqlByRecords(target.table, target.createTableStmt, recordsList[n])
+		if sql != "" {
+			rollbackSql += sql + "\n"
+		}
+	}
 	return rollbackSql, "", nil
 }
 
@@ -551,13 +1040,7 @@ func (i *MysqlDriverImpl) generateUpdateRollbackSql(stmt *ast.UpdateStmt) (strin
 	tableSources := util.GetTableSources(stmt.TableRefs.TableRefs)
 	// multi table syntax
 	if len(tableSources) != 1 {
-		i.Logger().Infof("not support generate rollback sql with multi-update statement")
-		return "", NotSupportMultiTableStatementRollback, nil
-	}
-	// sub query statement
-	if util.WhereStmtHasSubQuery(stmt.Where) {
-		i.Logger().Infof("not support generate rollback sql with sub query")
-		return "", NotSupportSubQueryStatementRollback, nil
+		return i.generateMultiTableUpdateRollbackSql(stmt)
 	}
 	var (
 		table      *ast.TableName
@@ -604,6 +1087,12 @@ func (i *MysqlDriverImpl) generateUpdateRollbackSql(stmt *ast.UpdateStmt) (strin
 	if err != nil {
 		return "", "", err
 	}
+	return i.generateUpdateRollbackSqlByRecords(table, createTableStmt, pkColumnsName, stmt.List, records)
+}
+
+// generateUpdateRollbackSqlByRecords generate update SQL which restores the records changed by assignments.
+func (i *MysqlDriverImpl) generateUpdateRollbackSqlByRecords(table *ast.TableName, createTableStmt *ast.CreateTableStmt,
+	pkColumnsName map[string]struct{}, assignments []*ast.Assignment, records []map[string]sql.NullString) (string, string, error) {
 	rollbackSql := ""
 	colNameDefMap := make(map[string]*ast.ColumnDef)
 	for _, col := range createTableStmt.Cols {
@@ -621,26 +1110,21 @@ func (i *MysqlDriverImpl) generateUpdateRollbackSql(stmt *ast.UpdateStmt) (strin
 			isPkChanged := false
 			pkValue := ""
 
-			for _, l := range stmt.List {
+			for _, l := range assignments {
 				if col.Name.Name.L == l.Column.Name.L {
 					colChanged = true
 					if isPk {
+						// the new primary key is used to locate the updated row, so it must be a constant.
+						if _, ok := l.Expr.(ast.ValueExpr); !ok {
+							return "", NotSupportUpdatePrimaryKeyWithExprRollback, nil
+						}
 						isPkChanged = true
 						pkValue = util.ExprFormat(l.Expr)
 					}
 				}
 			}
 			name := col.Name.Name.O
-			v := "NULL"
-			if record[name].Valid {
-				colDef := colNameDefMap[name]
-				if parserMysql.HasBinaryFlag(colDef.Tp.Flag) {
-					hexStr := getHexStrFromBytesStr(record[name].String)
-					v = fmt.Sprintf("X'%s'", hexStr)
-				} else {
-					v = fmt.Sprintf("'%s'", record[name].String)
-				}
-			}
+			v := getRecordValue(colNameDefMap[name], record[name])
 
 			if colChanged {
 				value = append(value, fmt.Sprintf("%s = %s", name, v))
@@ -660,29 +1144,196 @@ func (i *MysqlDriverImpl) generateUpdateRollbackSql(stmt *ast.UpdateStmt) (strin
 	return rollbackSql, "", nil
 }
 
+// generateMultiTableUpdateRollbackSql generate update SQL for each table changed by multi-table update,
+// such as "UPDATE t1 JOIN t2 ON t1.id = t2.t1_id SET t1.v1 = 1, t2.v1 = 2 WHERE ...".
+func (i *MysqlDriverImpl) generateMultiTableUpdateRollbackSql(stmt *ast.UpdateStmt) (string, string, error) {
+	targets, err := i.getDMLTargets(stmt.TableRefs.TableRefs)
+	if err != nil || targets == nil {
+		return "", "", err
+	}
+	updated := []*dmlTarget{}
+	for _, assignment := range stmt.List {
+		target := i.findDMLTargetByColumn(targets, assignment.Column)
+		if target == nil {
+			return "", NotSupportStatementRollback, nil
+		}
+		if len(target.assignments) == 0 {
+			updated = append(updated, target)
+		}
+		target.assignments = append(target.assignments, assignment)
+	}
+
+	recordsList, reason, err := i.getMultiTableRecords(updated, stmt.TableRefs, stmt.Where)
+	if err != nil || reason != "" {
+		return "", reason, err
+	}
+	rollbackSql := ""
+	for n, target := range updated {
+		pkColumnsName, _, err := i.getPrimaryKey(target.createTableStmt)
+		if err != nil {
+			return "", "", err
+		}
+		sql, reason, err := i.generateUpdateRollbackSqlByRecords(target.table, target.createTableStmt,
+			pkColumnsName, target.assignments, recordsList[n])
+		if err != nil || reason != "" {
+			return "", reason, err
+		}
+		if sql != "" {
+			rollbackSql += sql + "\n"
+		}
+	}
+	return rollbackSql, "", nil
+}
+
+// dmlTarget is a table referenced by multi-table UPDATE/DELETE.
+type dmlTarget struct {
+	table           *ast.TableName
+	alias           string
+	createTableStmt *ast.CreateTableStmt
+	// assignments is the SET list on the table for UPDATE.
+	assignments []*ast.Assignment
+}
+
+// qualifier returns the name which refers to the table in the statement.
+func (t *dmlTarget) qualifier() string {
+	if t.alias != "" {
+		return fmt.Sprintf("`%s`", t.alias)
+	}
+	return util.GetTableNameWithQuote(t.table)
+}
+
+// getDMLTargets returns the tables in the table references, it returns nil
+// if one of the tables not exist, the statement will fail in this case.
+func (i *MysqlDriverImpl) getDMLTargets(refs *ast.Join) ([]*dmlTarget, error) {
+	targets := []*dmlTarget{}
+	for _, source := range util.GetTableSources(refs) {
+		table, ok := source.Source.(*ast.TableName)
+		if !ok {
+			continue
+		}
+		createTableStmt, exist, err := i.Ctx.GetCreateTableStmt(table)
+		if err != nil || !exist {
+			return nil, err
+		}
+		targets = append(targets, &dmlTarget{
+			table:           table,
+			alias:           source.AsName.L,
+			createTableStmt: createTableStmt,
+		})
+	}
+	return targets, nil
+}
+
+// findDMLTarget finds the table by the name which refers to it, the name is
+// the alias if the table has alias, otherwise it is the table name.
+func (i *MysqlDriverImpl) findDMLTarget(targets []*dmlTarget, schema, name string) *dmlTarget {
+	for _, target := range targets {
+		if target.alias != "" {
+			if schema == "" && target.alias == name {
+				return target
+			}
+			continue
+		}
+		if target.table.Name.L != name {
+			continue
+		}
+		if schema == "" || strings.EqualFold(schema, i.Ctx.GetSchemaName(target.table)) {
+			return target
+		}
+	}
+	return nil
+}
+
+// findDMLTargetByColumn finds the table which the column belongs to.
+func (i *MysqlDriverImpl) findDMLTargetByColumn(targets []*dmlTarget, col *ast.ColumnName) *dmlTarget {
+	if col.Table.L != "" {
+		return i.findDMLTarget(targets, col.Schema.L, col.Table.L)
+	}
+	var found *dmlTarget
+	for _, target := range targets {
+		if util.TableExistCol(target.createTableStmt, col.Name.L) {
+			// ambiguous column, mysql will throw error
+			if found != nil {
+				return nil
+			}
+			found = target
+		}
+	}
+	return found
+}
+
+// getMultiTableRecords select the records of each target which will be update
+// or delete by the multi-table statement. The total rows is limited by DMLRollbackMaxRows.
+func (i *MysqlDriverImpl) getMultiTableRecords(targets []*dmlTarget, refs *ast.TableRefsClause,
+	where ast.ExprNode) ([][]map[string]sql.NullString, string, error) {
+	from, err := util.RestoreToSql(refs)
+	if err != nil {
+		return nil, "", err
+	}
+	condition := ""
+	if where != nil {
+		expr, err := util.RestoreToSql(where)
+		if err != nil {
+			return nil, "", err
+		}
+		condition = fmt.Sprintf(" WHERE %s", expr)
+	}
+
+	var max = i.cnf.DMLRollbackMaxRows
+	var total int64
+	recordsList := make([][]map[string]sql.NullString, 0, len(targets))
+	for _, target := range targets {
+		_, hasPk, err := i.getPrimaryKey(target.createTableStmt)
+		if err != nil {
+			return nil, "", err
+		}
+		if !hasPk {
+			return nil, NotSupportNoPrimaryKeyTableRollback, nil
+		}
+		// a row is matched more than once in joined tables, DISTINCT is required.
+		records, err := i.queryRecords(fmt.Sprintf("SELECT DISTINCT %s.* FROM %s%s LIMIT %d;",
+			target.qualifier(), from, condition, max-total+1))
+		if err != nil {
+			return nil, "", err
+		}
+		total += int64(len(records))
+		if total > max {
+			return nil, NotSupportExceedMaxRowsRollback, nil
+		}
+		recordsList = append(recordsList, records)
+	}
+	return recordsList, "", nil
+}
+
+func (i *MysqlDriverImpl) queryRecords(query string) ([]map[string]sql.NullString, error) {
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	return conn.Db.Query(query)
+}
+
 // getRecords select all data which will be update or delete.
 func (i *MysqlDriverImpl) getRecords(tableName *ast.TableName, tableAlias string, where ast.ExprNode,
 	order *ast.OrderByClause, limit int64) ([]map[string]sql.NullString, error) {
-	conn, err := i.getDbConn()
+	sql, err := i.generateGetRecordsSql("*", tableName, tableAlias, where, order, limit)
 	if err != nil {
 		return nil, err
 	}
-	sql := i.generateGetRecordsSql("*", tableName, tableAlias, where, order, limit)
-	return conn.Db.Query(sql)
+	return i.queryRecords(sql)
 }
 
 // getRecordCount select all data count which will be update or delete.
 func (i *MysqlDriverImpl) getRecordCount(tableName *ast.TableName, tableAlias string, where ast.ExprNode,
 	order *ast.OrderByClause, limit int64) (int64, error) {
-	conn, err := i.getDbConn()
+	sql, err := i.generateGetRecordsSql("count(*) as count", tableName, tableAlias, where, order, limit)
 	if err != nil {
 		return 0, err
 	}
-	sql := i.generateGetRecordsSql("count(*) as count", tableName, tableAlias, where, order, limit)
 
 	var count int64
 	var ok bool
-	records, err := conn.Db.Query(sql)
+	records, err := i.queryRecords(sql)
 	if err != nil {
 		return 0, err
 	}
@@ -705,13 +1356,18 @@ ERROR:
 
 // generateGetRecordsSql generate select SQL.
 func (i *MysqlDriverImpl) generateGetRecordsSql(expr string, tableName *ast.TableName, tableAlias string, where ast.ExprNode,
-	order *ast.OrderByClause, limit int64) string {
+	order *ast.OrderByClause, limit int64) (string, error) {
 	recordSql := fmt.Sprintf("SELECT %s FROM %s", expr, i.getTableNameWithQuote(tableName))
 	if tableAlias != "" {
 		recordSql = fmt.Sprintf("%s AS %s", recordSql, tableAlias)
 	}
 	if where != nil {
-		recordSql = fmt.Sprintf("%s WHERE %s", recordSql, util.ExprFormat(where))
+		// where may contain sub query, which is not supported by ExprFormat.
+		condition, err := util.RestoreToSql(where)
+		if err != nil {
+			return "", err
+		}
+		recordSql = fmt.Sprintf("%s WHERE %s", recordSql, condition)
 	}
 	if order != nil {
 		recordSql = fmt.Sprintf("%s ORDER BY", recordSql)
@@ -726,5 +1382,5 @@ func (i *MysqlDriverImpl) generateGetRecordsSql(expr string, tableName *ast.Tabl
 		recordSql = fmt.Sprintf("%s LIMIT %d", recordSql, limit)
 	}
 	recordSql += ";"
-	return recordSql
+	return recordSql, nil
 }
@@ -2,9 +2,13 @@ package mysql
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -197,3 +201,345 @@ func TestInsertRollbackSql(t *testing.T) {
 		"DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '10';\n",
 	)
 }
+
+func TestDMLRollbackSqlWithRecords(t *testing.T) {
+	cases := []struct {
+		desc         string
+		sql          string
+		maxRows      int64
+		queryResults []*queryResult
+		rollbackSql  string
+		reason       string
+	}{
+		{
+			desc: "delete with sub query",
+			sql:  "DELETE FROM exist_tb_1 WHERE id IN (SELECT id FROM exist_tb_4)",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT count(*) as count FROM `exist_db`.`exist_tb_1` WHERE `id` IN (SELECT `id` FROM `exist_tb_4`) LIMIT 1001;",
+					result: sqlmock.NewRows([]string{"count"}).AddRow("1"),
+				},
+				{
+					query:  "SELECT * FROM `exist_db`.`exist_tb_1` WHERE `id` IN (SELECT `id` FROM `exist_tb_4`) LIMIT 1001;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "a", nil),
+				},
+			},
+			rollbackSql: "INSERT INTO `exist_db`.`exist_tb_1` (`id`, `v1`, `v2`) VALUES ('1', 'a', NULL);",
+		},
+		{
+			desc: "multi-table delete",
+			sql:  "DELETE t1, t4 FROM exist_tb_1 AS t1 JOIN exist_tb_4 AS t4 ON t1.id = t4.id WHERE t4.v3 = 1",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT DISTINCT `t1`.* FROM `exist_tb_1` AS `t1` JOIN `exist_tb_4` AS `t4` ON `t1`.`id`=`t4`.`id` WHERE `t4`.`v3`=1 LIMIT 1001;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "a", nil),
+				},
+				{
+					query:  "SELECT DISTINCT `t4`.* FROM `exist_tb_1` AS `t1` JOIN `exist_tb_4` AS `t4` ON `t1`.`id`=`t4`.`id` WHERE `t4`.`v3`=1 LIMIT 1000;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2", "v3"}).AddRow("1", "b", "c", "1"),
+				},
+			},
+			rollbackSql: "INSERT INTO `exist_db`.`exist_tb_1` (`id`, `v1`, `v2`) VALUES ('1', 'a', NULL);\n" +
+				"INSERT INTO `exist_db`.`exist_tb_4` (`id`, `v1`, `v2`, `v3`) VALUES ('1', 'b', 'c', '1');\n",
+		},
+		{
+			desc:    "multi-table delete exceed max rows",
+			sql:     "DELETE exist_tb_1 FROM exist_tb_1 JOIN exist_tb_4 ON exist_tb_1.id = exist_tb_4.id",
+			maxRows: 1,
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT DISTINCT `exist_tb_1`.* FROM `exist_tb_1` JOIN `exist_tb_4` ON `exist_tb_1`.`id`=`exist_tb_4`.`id` LIMIT 2;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "a", nil).AddRow("2", "b", nil),
+				},
+			},
+			reason: NotSupportExceedMaxRowsRollback,
+		},
+		{
+			desc: "multi-table update",
+			sql:  "UPDATE exist_tb_1 JOIN exist_tb_4 ON exist_tb_1.id = exist_tb_4.id SET exist_tb_1.v1 = 'x', v3 = 2 WHERE exist_tb_4.v2 = 'c'",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT DISTINCT `exist_tb_1`.* FROM `exist_tb_1` JOIN `exist_tb_4` ON `exist_tb_1`.`id`=`exist_tb_4`.`id` WHERE `exist_tb_4`.`v2`='c' LIMIT 1001;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "a", nil),
+				},
+				{
+					query:  "SELECT DISTINCT `exist_tb_4`.* FROM `exist_tb_1` JOIN `exist_tb_4` ON `exist_tb_1`.`id`=`exist_tb_4`.`id` WHERE `exist_tb_4`.`v2`='c' LIMIT 1000;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2", "v3"}).AddRow("1", "b", "c", "1"),
+				},
+			},
+			rollbackSql: "UPDATE `exist_db`.`exist_tb_1` SET v1 = 'a' WHERE id = '1';\n" +
+				"UPDATE `exist_db`.`exist_tb_4` SET v3 = '1' WHERE id = '1';\n",
+		},
+		{
+			desc:   "multi-table update with ambiguous column",
+			sql:    "UPDATE exist_tb_1 JOIN exist_tb_4 ON exist_tb_1.id = exist_tb_4.id SET v1 = 'x'",
+			reason: NotSupportStatementRollback,
+		},
+		{
+			desc: "insert on duplicate key update",
+			sql:  "INSERT INTO exist_tb_1 (id, v1, v2) VALUES (1, 'a', 'b'), (2, 'c', 'd') ON DUPLICATE KEY UPDATE v2 = VALUES(v2)",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT * FROM `exist_db`.`exist_tb_1` WHERE (`id` = 1) OR (`v1` = 'a' AND `v2` = 'b') LIMIT 2;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "a", "old"),
+				},
+				{
+					query:  "SELECT * FROM `exist_db`.`exist_tb_1` WHERE (`id` = 2) OR (`v1` = 'c' AND `v2` = 'd') LIMIT 2;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2"}),
+				},
+			},
+			rollbackSql: "UPDATE `exist_db`.`exist_tb_1` SET v2 = 'old' WHERE id = '1';\n" +
+				"DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '2';\n",
+		},
+		{
+			desc: "insert on duplicate key update conflicts with multi rows",
+			sql:  "INSERT INTO exist_tb_1 (id, v1, v2) VALUES (1, 'a', 'b') ON DUPLICATE KEY UPDATE v2 = 'x'",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT * FROM `exist_db`.`exist_tb_1` WHERE (`id` = 1) OR (`v1` = 'a' AND `v2` = 'b') LIMIT 2;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "x", "y").AddRow("3", "a", "b"),
+				},
+			},
+			reason: NotSupportOnDuplicateConflictMultiRowsRollback,
+		},
+		{
+			desc:   "insert on duplicate key update primary key",
+			sql:    "INSERT INTO exist_tb_1 (id, v1, v2) VALUES (1, 'a', 'b') ON DUPLICATE KEY UPDATE id = 2",
+			reason: NotSupportOnDuplicateUpdatePrimaryKeyRollback,
+		},
+		{
+			desc: "update primary key with expression",
+			sql:  "UPDATE exist_tb_1 SET id = id + 1 WHERE v1 = 'a'",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT count(*) as count FROM `exist_db`.`exist_tb_1` WHERE `v1`='a' LIMIT 1001;",
+					result: sqlmock.NewRows([]string{"count"}).AddRow("1"),
+				},
+				{
+					query:  "SELECT * FROM `exist_db`.`exist_tb_1` WHERE `v1`='a' LIMIT 1001;",
+					result: sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "a", nil),
+				},
+			},
+			reason: NotSupportUpdatePrimaryKeyWithExprRollback,
+		},
+	}
+	for _, c := range cases {
+		e, handler, err := executor.NewMockExecutor()
+		assert.NoError(t, err)
+		i := NewMockInspect(e)
+		i.isConnected = true
+		if c.maxRows > 0 {
+			i.cnf.DMLRollbackMaxRows = c.maxRows
+		}
+		for _, r := range c.queryResults {
+			handler.ExpectQuery(regexp.QuoteMeta(r.query)).WillReturnRows(r.result)
+		}
+		rollbackSql, reason, err := i.GenRollbackSQL(context.TODO(), c.sql)
+		assert.NoError(t, err, c.desc)
+		assert.Equal(t, c.reason, reason, c.desc)
+		assert.Equal(t, c.rollbackSql, rollbackSql, c.desc)
+		assert.NoError(t, handler.ExpectationsWereMet(), c.desc)
+	}
+}
+
+func TestDDLRollbackSqlForSchemaObjects(t *testing.T) {
+	cases := []struct {
+		desc         string
+		preSqls      []string
+		sql          string
+		queryResults []*queryResult
+		rollbackSql  string
+		reason       string
+	}{
+		{
+			desc:   "truncate table",
+			sql:    "TRUNCATE TABLE exist_tb_1",
+			reason: NotSupportTruncateTableRollback,
+		},
+		{
+			desc:        "rename table",
+			sql:         "RENAME TABLE exist_db.exist_tb_1 TO exist_db.tb_1_new, exist_tb_4 TO tb_4_new",
+			rollbackSql: "RENAME TABLE `exist_db`.`tb_4_new` TO `exist_db`.`exist_tb_4`, `exist_db`.`tb_1_new` TO `exist_db`.`exist_tb_1`",
+		},
+		{
+			desc:        "rename table chain",
+			sql:         "RENAME TABLE exist_tb_1 TO tb_tmp, tb_tmp TO tb_new",
+			rollbackSql: "RENAME TABLE `exist_db`.`tb_new` TO `exist_db`.`tb_tmp`, `exist_db`.`tb_tmp` TO `exist_db`.`exist_tb_1`",
+		},
+		{
+			desc: "rename not exist table",
+			sql:  "RENAME TABLE not_exist_tb TO tb_new",
+		},
+		{
+			desc: "create view",
+			sql:  "CREATE VIEW v1 AS SELECT * FROM exist_tb_1",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT TABLE_NAME FROM information_schema.VIEWS WHERE TABLE_SCHEMA = 'exist_db' AND TABLE_NAME = 'v1';",
+					result: sqlmock.NewRows([]string{"TABLE_NAME"}),
+				},
+			},
+			rollbackSql: "DROP VIEW IF EXISTS `exist_db`.`v1`",
+		},
+		{
+			desc: "create or replace view",
+			sql:  "CREATE OR REPLACE VIEW v1 AS SELECT * FROM exist_tb_1",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT TABLE_NAME FROM information_schema.VIEWS WHERE TABLE_SCHEMA = 'exist_db' AND TABLE_NAME = 'v1';",
+					result: sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("v1"),
+				},
+				{
+					query: "SHOW CREATE VIEW `exist_db`.`v1`;",
+					result: sqlmock.NewRows([]string{"View", "Create View"}).
+						AddRow("v1", "CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `v1` AS select `id` from `exist_tb_1`"),
+				},
+			},
+			rollbackSql: "CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%` SQL SECURITY DEFINER VIEW `exist_db`.`v1` AS select `id` from `exist_tb_1`",
+		},
+		{
+			desc: "drop view",
+			sql:  "DROP VIEW v1, v2",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT TABLE_NAME FROM information_schema.VIEWS WHERE TABLE_SCHEMA = 'exist_db' AND TABLE_NAME = 'v1';",
+					result: sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("v1"),
+				},
+				{
+					query:  "SHOW CREATE VIEW `exist_db`.`v1`;",
+					result: sqlmock.NewRows([]string{"View", "Create View"}).AddRow("v1", "CREATE VIEW `v1` AS select 1"),
+				},
+				{
+					query:  "SELECT TABLE_NAME FROM information_schema.VIEWS WHERE TABLE_SCHEMA = 'exist_db' AND TABLE_NAME = 'v2';",
+					result: sqlmock.NewRows([]string{"TABLE_NAME"}),
+				},
+			},
+			rollbackSql: "CREATE VIEW `exist_db`.`v1` AS select 1;\n",
+		},
+		{
+			desc: "create procedure",
+			sql:  "CREATE DEFINER=`root`@`%` PROCEDURE p1(IN a INT) BEGIN SELECT a; END",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT ROUTINE_NAME FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = 'exist_db' AND ROUTINE_NAME = 'p1' AND ROUTINE_TYPE = 'PROCEDURE';",
+					result: sqlmock.NewRows([]string{"ROUTINE_NAME"}),
+				},
+			},
+			rollbackSql: "DROP PROCEDURE IF EXISTS `exist_db`.`p1`",
+		},
+		{
+			desc: "create existing event",
+			sql:  "CREATE EVENT IF NOT EXISTS `db1`.`e1` ON SCHEDULE EVERY 1 DAY DO DELETE FROM t",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT EVENT_NAME FROM information_schema.EVENTS WHERE EVENT_SCHEMA = 'db1' AND EVENT_NAME = 'e1';",
+					result: sqlmock.NewRows([]string{"EVENT_NAME"}).AddRow("e1"),
+				},
+				{
+					query:  "SHOW CREATE EVENT `db1`.`e1`;",
+					result: sqlmock.NewRows([]string{"Event", "Create Event"}).AddRow("e1", "CREATE EVENT `e1` ON SCHEDULE EVERY 1 DAY DO DELETE FROM t"),
+				},
+			},
+		},
+		{
+			desc: "drop trigger",
+			sql:  "DROP TRIGGER IF EXISTS exist_db.t1",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT TRIGGER_NAME FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = 'exist_db' AND TRIGGER_NAME = 't1';",
+					result: sqlmock.NewRows([]string{"TRIGGER_NAME"}).AddRow("t1"),
+				},
+				{
+					query: "SHOW CREATE TRIGGER `exist_db`.`t1`;",
+					result: sqlmock.NewRows([]string{"Trigger", "SQL Original Statement"}).
+						AddRow("t1", "CREATE DEFINER=`root`@`%` TRIGGER `t1` BEFORE INSERT ON `exist_tb_1` FOR EACH ROW SET NEW.v1 = 'v'"),
+				},
+			},
+			rollbackSql: "CREATE DEFINER=`root`@`%` TRIGGER `exist_db`.`t1` BEFORE INSERT ON `exist_tb_1` FOR EACH ROW SET NEW.v1 = 'v'",
+		},
+		{
+			desc: "drop function without privilege",
+			sql:  "DROP FUNCTION f1",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT ROUTINE_NAME FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = 'exist_db' AND ROUTINE_NAME = 'f1' AND ROUTINE_TYPE = 'FUNCTION';",
+					result: sqlmock.NewRows([]string{"ROUTINE_NAME"}).AddRow("f1"),
+				},
+				{
+					query:  "SHOW CREATE FUNCTION `exist_db`.`f1`;",
+					result: sqlmock.NewRows([]string{"Function", "Create Function"}).AddRow("f1", nil),
+				},
+			},
+			reason: NotSupportNoObjectDefinitionRollback,
+		},
+		{
+			desc:    "drop database",
+			preSqls: []string{"CREATE DATABASE db2", "CREATE TABLE db2.t1 (id int PRIMARY KEY, v1 varchar(10))"},
+			sql:     "DROP DATABASE db2",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = 'db2';",
+					result: sqlmock.NewRows([]string{"DEFAULT_CHARACTER_SET_NAME", "DEFAULT_COLLATION_NAME"}),
+				},
+			},
+			rollbackSql: "CREATE DATABASE IF NOT EXISTS `db2`;\n" +
+				"CREATE TABLE `db2`.`t1` (`id` INT PRIMARY KEY,`v1` VARCHAR(10));\n",
+		},
+		{
+			desc: "drop database with foreign keys",
+			preSqls: []string{"CREATE DATABASE db3",
+				"CREATE TABLE db3.a (id int PRIMARY KEY, b_id int, FOREIGN KEY (b_id) REFERENCES b (id))",
+				"CREATE TABLE db3.b (id int PRIMARY KEY, c_id int, FOREIGN KEY (c_id) REFERENCES db3.c (id))",
+				"CREATE TABLE db3.c (id int PRIMARY KEY, parent_id int, FOREIGN KEY (parent_id) REFERENCES c (id))",
+			},
+			sql: "DROP DATABASE db3",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = 'db3';",
+					result: sqlmock.NewRows([]string{"DEFAULT_CHARACTER_SET_NAME", "DEFAULT_COLLATION_NAME"}),
+				},
+			},
+			rollbackSql: "CREATE DATABASE IF NOT EXISTS `db3`;\n" +
+				"CREATE TABLE `db3`.`c` (`id` INT PRIMARY KEY,`parent_id` INT,CONSTRAINT FOREIGN KEY (`parent_id`) REFERENCES `db3`.`c`(`id`));\n" +
+				"CREATE TABLE `db3`.`b` (`id` INT PRIMARY KEY,`c_id` INT,CONSTRAINT FOREIGN KEY (`c_id`) REFERENCES `db3`.`c`(`id`));\n" +
+				"CREATE TABLE `db3`.`a` (`id` INT PRIMARY KEY,`b_id` INT,CONSTRAINT FOREIGN KEY (`b_id`) REFERENCES `db3`.`b`(`id`));\n",
+		},
+		{
+			desc: "drop database with tables referencing each other",
+			preSqls: []string{"CREATE DATABASE db4",
+				"CREATE TABLE db4.a (id int PRIMARY KEY, b_id int, FOREIGN KEY (b_id) REFERENCES b (id))",
+				"CREATE TABLE db4.b (id int PRIMARY KEY, a_id int, FOREIGN KEY (a_id) REFERENCES a (id))",
+			},
+			sql: "DROP DATABASE db4",
+			queryResults: []*queryResult{
+				{
+					query:  "SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = 'db4';",
+					result: sqlmock.NewRows([]string{"DEFAULT_CHARACTER_SET_NAME", "DEFAULT_COLLATION_NAME"}),
+				},
+			},
+			rollbackSql: "CREATE DATABASE IF NOT EXISTS `db4`;\n" +
+				"SET FOREIGN_KEY_CHECKS = 0;\n" +
+				"CREATE TABLE `db4`.`b` (`id` INT PRIMARY KEY,`a_id` INT,CONSTRAINT FOREIGN KEY (`a_id`) REFERENCES `db4`.`a`(`id`));\n" +
+				"CREATE TABLE `db4`.`a` (`id` INT PRIMARY KEY,`b_id` INT,CONSTRAINT FOREIGN KEY (`b_id`) REFERENCES `db4`.`b`(`id`));\n" +
+				"SET FOREIGN_KEY_CHECKS = 1;\n",
+		},
+	}
+	for _, c := range cases {
+		e, handler, err := executor.NewMockExecutor()
+		assert.NoError(t, err)
+		i := NewMockInspect(e)
+		i.isConnected = true
+		for _, sql := range c.preSqls {
+			_, _, err := i.GenRollbackSQL(context.TODO(), sql)
+			assert.NoError(t, err, c.desc)
+		}
+		for _, r := range c.queryResults {
+			handler.ExpectQuery(regexp.QuoteMeta(r.query)).WillReturnRows(r.result)
+		}
+		rollbackSql, reason, err := i.GenRollbackSQL(context.TODO(), c.sql)
+		assert.NoError(t, err, c.desc)
+		assert.Equal(t, c.reason, reason, c.desc)
+		assert.Equal(t, c.rollbackSql, rollbackSql, c.desc)
+		assert.NoError(t, handler.ExpectationsWereMet(), c.desc)
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/actiontech/sqle/sqle/driver/mysql/session"
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/utils"
 
@@ -246,6 +247,19 @@ func init() {
 			RuleHandlers[i] = rh
 		}
 		RuleHandlerMap[rh.Rule.Name] = rh
+		locale.RegisterSource(locale.RuleMessageId(driverV2.DriverTypeMySQL, rh.Rule.Name, locale.RuleFieldMessage), rh.Message)
+	}
+	for key, category := range map[string]string{
+		"global_config":       RuleTypeGlobalConfig,
+		"naming_convention":   RuleTypeNamingConvention,
+		"indexing_convention": RuleTypeIndexingConvention,
+		"ddl_convention":      RuleTypeDDLConvention,
+		"dml_convention":      RuleTypeDMLConvention,
+		"usage_suggestion":    RuleTypeUsageSuggestion,
+		"index_optimization":  RuleTypeIndexOptimization,
+		"index_invalidation":  RuleTypeIndexInvalidation,
+	} {
+		locale.RegisterSource("rule_category."+key, category)
 	}
 }
 
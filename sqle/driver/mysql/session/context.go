@@ -3,6 +3,7 @@ package session
 import (
 	"database/sql"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -140,6 +141,18 @@ func NewContext(parent *Context, opts ...contextOption) *Context {
 	return ctx
 }
 
+// NewOfflineContext returns a context without instance, it keeps the schemas
+// and tables created by the SQLs applied by UpdateContext, and the tables
+// without schema are created in the given current schema.
+func NewOfflineContext(currentSchema string) *Context {
+	ctx := NewContext(nil)
+	ctx.AddSystemVariable(SysVarLowerCaseTableNames, "0")
+	ctx.setSchemasLoad()
+	ctx.addSchema(currentSchema)
+	ctx.SetCurrentSchema(currentSchema)
+	return ctx
+}
+
 func WithExecutor(e *executor.Executor) contextOption {
 	return func(ctx *Context) {
 		ctx.e = e
@@ -492,6 +505,34 @@ func (c *Context) IsTableExist(stmt *ast.TableName) (bool, error) {
 	return c.hasTable(schemaName, stmt.Name.String()), nil
 }
 
+// GetSchemaTables get the table names of schema, the tables created in context are included.
+func (c *Context) GetSchemaTables(schemaName string) ([]string, error) {
+	schemaExist, err := c.IsSchemaExist(schemaName)
+	if err != nil || !schemaExist {
+		return nil, err
+	}
+	if !c.hasLoadTables(schemaName) {
+		if c.e == nil {
+			return nil, nil
+		}
+		tables, err := c.e.ShowSchemaTables(schemaName)
+		if err != nil {
+			return nil, err
+		}
+		c.loadTables(schemaName, tables)
+	}
+	schema, ok := c.getSchema(schemaName)
+	if !ok {
+		return nil, nil
+	}
+	tables := make([]string, 0, len(schema.Tables))
+	for name := range schema.Tables {
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
 const (
 	SysVarLowerCaseTableNames = "lower_case_table_names"
 )
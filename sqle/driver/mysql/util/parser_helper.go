@@ -851,6 +851,12 @@ func restoreToSqlWithFlag(restoreFlag format.RestoreFlags, node ast.Node) (sqlSt
 	return buf.String(), nil
 }
 
+// RestoreToSql restores node to SQL text, unlike ExprFormat it supports sub
+// query and table references.
+func RestoreToSql(node ast.Node) (string, error) {
+	return restoreToSqlWithFlag(format.DefaultRestoreFlags, node)
+}
+
 func Fingerprint(oneSql string, isCaseSensitive bool) (fingerprint string, err error) {
 	stmts, _, err := parser.New().PerfectParse(oneSql, "", "")
 	if err != nil {
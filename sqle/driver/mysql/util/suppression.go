@@ -0,0 +1,75 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+const suppressionPrefix = "sqle:ignore"
+
+// Suppression is an annotation in the comment of SQL which ignores the audit
+// results of some rules, e.g.
+//
+//	/* sqle:ignore dml_check_select_limit,dml_check_where_is_invalid reason="admin report" */
+type Suppression struct {
+	RuleNames []string
+	// Reason is empty if it is not given, such suppression should not be honoured.
+	Reason string
+}
+
+var suppressionReasonPattern = regexp.MustCompile(`\breason\s*=\s*"((?:[^"\\]|\\.)*)"`)
+
+// ParseSuppressions returns the suppressions in the block comments of SQL,
+// the comments in quoted strings and identifiers are not counted.
+func ParseSuppressions(sql string) []*Suppression {
+	suppressions := []*Suppression{}
+	for _, comment := range blockComments(sql) {
+		comment = strings.TrimSpace(comment)
+		if !strings.HasPrefix(comment, suppressionPrefix) {
+			continue
+		}
+		body := comment[len(suppressionPrefix):]
+		suppression := &Suppression{}
+		if loc := suppressionReasonPattern.FindStringSubmatchIndex(body); loc != nil {
+			suppression.Reason = strings.TrimSpace(strings.ReplaceAll(body[loc[2]:loc[3]], `\"`, `"`))
+			body = body[:loc[0]] + body[loc[1]:]
+		}
+		for _, name := range strings.FieldsFunc(body, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+		}) {
+			suppression.RuleNames = append(suppression.RuleNames, name)
+		}
+		if len(suppression.RuleNames) > 0 {
+			suppressions = append(suppressions, suppression)
+		}
+	}
+	return suppressions
+}
+
+func blockComments(sql string) []string {
+	comments := []string{}
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if quote != 0 {
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return comments
+			}
+			comments = append(comments, sql[i+2:i+2+end])
+			i += end + 3
+		}
+	}
+	return comments
+}
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSuppressions(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect []*Suppression
+	}{
+		{"SELECT * FROM t1", []*Suppression{}},
+		{
+			`SELECT * FROM t1 /* sqle:ignore dml_check_select_limit reason="admin report" */`,
+			[]*Suppression{{RuleNames: []string{"dml_check_select_limit"}, Reason: "admin report"}},
+		},
+		{
+			`/*sqle:ignore rule_a, rule_b reason = "say \"hi\""*/ DELETE FROM t1`,
+			[]*Suppression{{RuleNames: []string{"rule_a", "rule_b"}, Reason: `say "hi"`}},
+		},
+		{
+			`SELECT 1 /* sqle:ignore rule_a */ /* normal comment */ /* sqle:ignore reason="x" rule_b */`,
+			[]*Suppression{{RuleNames: []string{"rule_a"}}, {RuleNames: []string{"rule_b"}, Reason: "x"}},
+		},
+		{`SELECT '/* sqle:ignore rule_a reason="x" */', "it\"s /*" FROM t1`, []*Suppression{}},
+		{"SELECT `/* sqle:ignore rule_a */` FROM t1", []*Suppression{}},
+		{`SELECT 1 /* sqle:ignore reason="no rule" */`, []*Suppression{}},
+		{`SELECT 1 /* sqle:ignore rule_a`, []*Suppression{}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expect, ParseSuppressions(test.input), test.input)
+	}
+}
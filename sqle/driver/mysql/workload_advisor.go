@@ -0,0 +1,355 @@
+package mysql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/pingcap/parser/ast"
+)
+
+const (
+	WorkloadMaxIndexPerTableDefaultValue int = 3
+
+	workloadIndexNameMaxLength int = 64
+)
+
+// 只有给出普通列索引的建议者参与负载级别的合并，函数索引和前缀模糊匹配的建议无法与列索引合并
+var workloadAdvisorMetaList []AdvisorMeta = []AdvisorMeta{
+	{
+		advisorName: "join_index_advisor",
+		newFunction: newJoinIndexAdvisor,
+	},
+	{
+		advisorName: "three_star_index_advisor",
+		newFunction: newThreeStarIndexAdvisor,
+	},
+}
+
+// WorkloadSQL 负载中的一条SQL指纹，Weight 表示该SQL在负载中的权重，一般为执行次数与平均执行时间的乘积
+type WorkloadSQL struct {
+	Fingerprint string
+	SQL         string
+	Weight      float64
+}
+
+// WorkloadIndexAdvice 合并后的一条索引建议，以及该索引能够服务的SQL指纹
+type WorkloadIndexAdvice struct {
+	Columns        []string
+	CreateIndexSQL string
+	Weight         float64
+	Fingerprints   []string
+	Reasons        []string
+}
+
+type WorkloadTableIndexAdvice struct {
+	Schema  string
+	Table   string
+	Weight  float64
+	Indexes []*WorkloadIndexAdvice
+}
+
+type workloadIndexCandidate struct {
+	schema       string
+	table        string
+	columns      []string
+	fingerprints []string
+	weights      map[string] /*fingerprint*/ float64
+	reasons      []string
+}
+
+func (c *workloadIndexCandidate) tableKey() string {
+	return fmt.Sprintf("%s.%s", c.schema, c.table)
+}
+
+// 同一条SQL只计算一次权重
+func (c *workloadIndexCandidate) weight() float64 {
+	var weight float64
+	for _, w := range c.weights {
+		weight += w
+	}
+	return weight
+}
+
+func (c *workloadIndexCandidate) merge(other *workloadIndexCandidate) {
+	for _, fingerprint := range other.fingerprints {
+		if _, ok := c.weights[fingerprint]; !ok {
+			c.fingerprints = append(c.fingerprints, fingerprint)
+			c.weights[fingerprint] = other.weights[fingerprint]
+		}
+	}
+	for _, reason := range other.reasons {
+		if !containsString(c.reasons, reason) {
+			c.reasons = append(c.reasons, reason)
+		}
+	}
+}
+
+/*
+WorkloadIndexAdvisor 负载级别的索引建议者
+
+ 1. 对负载中每一条SQL使用三星索引、关联字段索引建议者给出候选索引
+ 2. 相同列的候选索引合并，列为其他候选索引最左前缀的候选索引并入较长的索引
+ 3. 丢弃能够被已有索引最左前缀覆盖的候选索引
+ 4. 每张表按权重保留若干条索引建议，并给出每条索引服务的SQL
+*/
+type WorkloadIndexAdvisor struct {
+	maxIndexPerTable int
+	candidates       map[string] /*schema.table.columns*/ *workloadIndexCandidate
+	existingIndexes  map[string] /*schema.table*/ [][]string
+}
+
+func NewWorkloadIndexAdvisor(maxIndexPerTable int) *WorkloadIndexAdvisor {
+	if maxIndexPerTable <= 0 {
+		maxIndexPerTable = WorkloadMaxIndexPerTableDefaultValue
+	}
+	return &WorkloadIndexAdvisor{
+		maxIndexPerTable: maxIndexPerTable,
+		candidates:       map[string]*workloadIndexCandidate{},
+		existingIndexes:  map[string][][]string{},
+	}
+}
+
+// Collect 在inspect连接的库上为SQL生成候选索引，inspect的当前库即为SQL执行时所在的库
+func (w *WorkloadIndexAdvisor) Collect(inspect *MysqlDriverImpl, sqls []*WorkloadSQL) {
+	log := inspect.Logger().WithField("optimizer", "workload_index")
+	p := params.Params{
+		{
+			Key:   MAX_INDEX_COLUMN,
+			Value: fmt.Sprint(MAX_INDEX_COLUMN_DEFAULT_VALUE),
+			Type:  params.ParamTypeInt,
+		}, {
+			Key:   MIN_COLUMN_SELECTIVITY,
+			Value: fmt.Sprint(MIN_COLUMN_SELECTIVITY_DEFAULT_VALUE),
+			Type:  params.ParamTypeFloat64,
+		},
+	}
+	for _, sql := range sqls {
+		nodes, err := inspect.ParseSql(sql.SQL)
+		if err != nil || len(nodes) != 1 {
+			log.Warnf("skip sql which can not be parsed to one statement, fingerprint: %s", sql.Fingerprint)
+			continue
+		}
+		node := nodes[0]
+		if !canOptimize(log, inspect.Ctx, node) {
+			continue
+		}
+		extractor := util.TableSourceExtractor{TableSources: map[string]*ast.TableSource{}}
+		node.Accept(&extractor)
+
+		for _, meta := range workloadAdvisorMetaList {
+			for _, advice := range meta.newFunction(inspect.Ctx, log, node, p).GiveAdvices() {
+				tableName := findTableName(extractor.TableSources, advice.TableName)
+				if tableName == nil {
+					continue
+				}
+				columns := advice.IndexedColumns
+				if meta.advisorName == "join_index_advisor" {
+					// 关联字段索引建议的列没有先后顺序
+					sort.Strings(columns)
+				}
+				schema := inspect.Ctx.GetSchemaName(tableName)
+				w.addExistingIndexes(schema, tableName.Name.L, inspect, tableName)
+				w.addCandidate(schema, tableName.Name.L, columns, sql, advice.Reason)
+			}
+		}
+	}
+}
+
+// 建议中的表名可能是别名，需要找到真实的表
+func findTableName(sources map[string]*ast.TableSource, name string) *ast.TableName {
+	source, ok := sources[name]
+	if !ok {
+		for key, ts := range sources {
+			if strings.EqualFold(key, name) {
+				source = ts
+				break
+			}
+		}
+	}
+	if source == nil {
+		return nil
+	}
+	tableName, ok := source.Source.(*ast.TableName)
+	if !ok {
+		return nil
+	}
+	return tableName
+}
+
+func (w *WorkloadIndexAdvisor) addExistingIndexes(schema, table string, inspect *MysqlDriverImpl, tableName *ast.TableName) {
+	key := fmt.Sprintf("%s.%s", schema, table)
+	if _, ok := w.existingIndexes[key]; ok {
+		return
+	}
+	createTable, exist, err := inspect.Ctx.GetCreateTableStmt(tableName)
+	if err != nil || !exist {
+		w.existingIndexes[key] = nil
+		return
+	}
+	indexes := make([][]string, 0, len(createTable.Constraints))
+	for _, constraint := range createTable.Constraints {
+		if len(constraint.Keys) == 0 {
+			continue
+		}
+		columns := make([]string, 0, len(constraint.Keys))
+		for _, key := range constraint.Keys {
+			if key.Column == nil {
+				break
+			}
+			columns = append(columns, key.Column.Name.L)
+		}
+		indexes = append(indexes, columns)
+	}
+	w.existingIndexes[key] = indexes
+}
+
+func (w *WorkloadIndexAdvisor) addCandidate(schema, table string, columns []string, sql *WorkloadSQL, reason string) {
+	if len(columns) == 0 {
+		return
+	}
+	lowerColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		lowerColumns = append(lowerColumns, strings.ToLower(column))
+	}
+	weight := sql.Weight
+	if weight <= 0 {
+		weight = 1
+	}
+	candidate := &workloadIndexCandidate{
+		schema:       schema,
+		table:        table,
+		columns:      lowerColumns,
+		fingerprints: []string{sql.Fingerprint},
+		weights:      map[string]float64{sql.Fingerprint: weight},
+		reasons:      []string{reason},
+	}
+	key := fmt.Sprintf("%s.%s", candidate.tableKey(), strings.Join(lowerColumns, ","))
+	if exist, ok := w.candidates[key]; ok {
+		exist.merge(candidate)
+		return
+	}
+	w.candidates[key] = candidate
+}
+
+// Advise 合并候选索引，返回按权重排序的每张表的索引建议
+func (w *WorkloadIndexAdvisor) Advise() []*WorkloadTableIndexAdvice {
+	tables := map[string][]*workloadIndexCandidate{}
+	for _, candidate := range w.candidates {
+		if w.isRedundant(candidate) {
+			continue
+		}
+		tables[candidate.tableKey()] = append(tables[candidate.tableKey()], candidate)
+	}
+
+	advices := make([]*WorkloadTableIndexAdvice, 0, len(tables))
+	for _, candidates := range tables {
+		// 先处理列多的候选索引，列较少的候选索引若为其最左前缀则并入
+		sort.Slice(candidates, func(i, j int) bool {
+			if len(candidates[i].columns) != len(candidates[j].columns) {
+				return len(candidates[i].columns) > len(candidates[j].columns)
+			}
+			return lessCandidate(candidates[i], candidates[j])
+		})
+		var kept []*workloadIndexCandidate
+		for _, candidate := range candidates {
+			merged := false
+			for _, k := range kept {
+				if isLeftPrefix(candidate.columns, k.columns) {
+					k.merge(candidate)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				kept = append(kept, candidate)
+			}
+		}
+
+		sort.Slice(kept, func(i, j int) bool {
+			return lessCandidate(kept[i], kept[j])
+		})
+		if len(kept) > w.maxIndexPerTable {
+			kept = kept[:w.maxIndexPerTable]
+		}
+
+		advice := &WorkloadTableIndexAdvice{
+			Schema: kept[0].schema,
+			Table:  kept[0].table,
+		}
+		for _, k := range kept {
+			advice.Weight += k.weight()
+			advice.Indexes = append(advice.Indexes, &WorkloadIndexAdvice{
+				Columns:        k.columns,
+				CreateIndexSQL: genCreateIndexSQL(k.schema, k.table, k.columns),
+				Weight:         k.weight(),
+				Fingerprints:   k.fingerprints,
+				Reasons:        k.reasons,
+			})
+		}
+		advices = append(advices, advice)
+	}
+
+	sort.Slice(advices, func(i, j int) bool {
+		if advices[i].Weight != advices[j].Weight {
+			return advices[i].Weight > advices[j].Weight
+		}
+		return fmt.Sprintf("%s.%s", advices[i].Schema, advices[i].Table) < fmt.Sprintf("%s.%s", advices[j].Schema, advices[j].Table)
+	})
+	return advices
+}
+
+// 候选索引的列是已有索引的最左前缀时，已有索引即可满足查询
+func (w *WorkloadIndexAdvisor) isRedundant(candidate *workloadIndexCandidate) bool {
+	for _, index := range w.existingIndexes[candidate.tableKey()] {
+		if isLeftPrefix(candidate.columns, index) {
+			return true
+		}
+	}
+	return false
+}
+
+func lessCandidate(a, b *workloadIndexCandidate) bool {
+	if a.weight() != b.weight() {
+		return a.weight() > b.weight()
+	}
+	return strings.Join(a.columns, ",") < strings.Join(b.columns, ",")
+}
+
+func isLeftPrefix(prefix, columns []string) bool {
+	if len(prefix) > len(columns) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != columns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func genCreateIndexSQL(schema, table string, columns []string) string {
+	indexName := fmt.Sprintf("idx_%s_%s", table, strings.Join(columns, "_"))
+	if len(indexName) > workloadIndexNameMaxLength {
+		indexName = indexName[:workloadIndexNameMaxLength]
+	}
+	quoted := make([]string, 0, len(columns))
+	for _, column := range columns {
+		quoted = append(quoted, fmt.Sprintf("`%s`", column))
+	}
+	if schema == "" {
+		return fmt.Sprintf("CREATE INDEX `%s` ON `%s` (%s);", indexName, table, strings.Join(quoted, ","))
+	}
+	return fmt.Sprintf("CREATE INDEX `%s` ON `%s`.`%s` (%s);", indexName, schema, table, strings.Join(quoted, ","))
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloadIndexAdvisor_Advise(t *testing.T) {
+	w := NewWorkloadIndexAdvisor(2)
+	w.existingIndexes["db1.t1"] = [][]string{{"id"}, {"c1", "c2"}}
+	w.existingIndexes["db1.t2"] = nil
+
+	// 已有索引(c1,c2)的最左前缀，应被丢弃
+	w.addCandidate("db1", "t1", []string{"c1"}, &WorkloadSQL{Fingerprint: "q1", Weight: 100}, "r1")
+	// 相同列的候选索引合并
+	w.addCandidate("db1", "t1", []string{"c3", "c4"}, &WorkloadSQL{Fingerprint: "q2", Weight: 10}, "r2")
+	w.addCandidate("db1", "t1", []string{"C3", "c4"}, &WorkloadSQL{Fingerprint: "q3", Weight: 5}, "r2")
+	// (c3)是(c3,c4)的最左前缀，并入(c3,c4)
+	w.addCandidate("db1", "t1", []string{"c3"}, &WorkloadSQL{Fingerprint: "q4", Weight: 1}, "r3")
+	// 同一条SQL不重复计算权重
+	w.addCandidate("db1", "t1", []string{"c3"}, &WorkloadSQL{Fingerprint: "q2", Weight: 10}, "r3")
+	w.addCandidate("db1", "t1", []string{"c5"}, &WorkloadSQL{Fingerprint: "q5", Weight: 3}, "r4")
+	// 超出每张表的建议数量，权重最低的被丢弃
+	w.addCandidate("db1", "t1", []string{"c6"}, &WorkloadSQL{Fingerprint: "q6", Weight: 2}, "r5")
+	// 没有权重的SQL按1计算
+	w.addCandidate("db1", "t2", []string{"c1"}, &WorkloadSQL{Fingerprint: "q7"}, "r6")
+	w.addCandidate("db1", "t2", []string{}, &WorkloadSQL{Fingerprint: "q8"}, "r7")
+
+	advices := w.Advise()
+	assert.Len(t, advices, 2)
+
+	t1 := advices[0]
+	assert.Equal(t, "db1", t1.Schema)
+	assert.Equal(t, "t1", t1.Table)
+	assert.Len(t, t1.Indexes, 2)
+	assert.Equal(t, []string{"c3", "c4"}, t1.Indexes[0].Columns)
+	assert.Equal(t, float64(16), t1.Indexes[0].Weight)
+	assert.ElementsMatch(t, []string{"q2", "q3", "q4"}, t1.Indexes[0].Fingerprints)
+	assert.ElementsMatch(t, []string{"r2", "r3"}, t1.Indexes[0].Reasons)
+	assert.Equal(t, "CREATE INDEX `idx_t1_c3_c4` ON `db1`.`t1` (`c3`,`c4`);", t1.Indexes[0].CreateIndexSQL)
+	assert.Equal(t, []string{"c5"}, t1.Indexes[1].Columns)
+	assert.Equal(t, float64(19), t1.Weight)
+
+	t2 := advices[1]
+	assert.Equal(t, "t2", t2.Table)
+	assert.Len(t, t2.Indexes, 1)
+	assert.Equal(t, float64(1), t2.Indexes[0].Weight)
+}
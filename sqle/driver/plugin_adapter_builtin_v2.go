@@ -0,0 +1,123 @@
+package driver
+
+import (
+	"context"
+	sqlDriver "database/sql/driver"
+	"errors"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BuiltInPluginProcessorV2 runs a driverV2.Driver inside the sqled process. It
+// lets a driver written against the v2 plugin interface be registered in
+// BuiltInPluginProcessors without being launched as a separate plugin process.
+type BuiltInPluginProcessorV2 struct {
+	Meta          *driverV2.DriverMetas
+	DriverFactory func(l *logrus.Entry, cfg *driverV2.Config) (driverV2.Driver, error)
+}
+
+func (p *BuiltInPluginProcessorV2) GetDriverMetas() (*driverV2.DriverMetas, error) {
+	return p.Meta, nil
+}
+
+func (p *BuiltInPluginProcessorV2) Open(l *logrus.Entry, cfg *driverV2.Config) (Plugin, error) {
+	l = l.WithField("plugin", p.Meta.PluginName)
+	d, err := p.DriverFactory(l, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &PluginImplBuiltInV2{l: l, driver: d}, nil
+}
+
+func (p *BuiltInPluginProcessorV2) Stop() error {
+	return nil
+}
+
+// PluginImplBuiltInV2 adapts driverV2.Driver to Plugin, it is the in-process
+// counterpart of PluginImplV2.
+type PluginImplBuiltInV2 struct {
+	l      *logrus.Entry
+	driver driverV2.Driver
+}
+
+var _ Plugin = &PluginImplBuiltInV2{}
+
+func (s *PluginImplBuiltInV2) Close(ctx context.Context) {
+	s.driver.Close(ctx)
+}
+
+func (s *PluginImplBuiltInV2) KillProcess(ctx context.Context) error {
+	info, err := s.driver.KillProcess(ctx)
+	if err != nil {
+		return err
+	}
+	if info != nil && info.ErrMessage != "" {
+		return errors.New(info.ErrMessage)
+	}
+	return nil
+}
+
+func (s *PluginImplBuiltInV2) Parse(ctx context.Context, sqlText string) ([]driverV2.Node, error) {
+	return s.driver.Parse(ctx, sqlText)
+}
+
+func (s *PluginImplBuiltInV2) Audit(ctx context.Context, sqls []string) ([]*driverV2.AuditResults, error) {
+	return s.driver.Audit(ctx, sqls)
+}
+
+func (s *PluginImplBuiltInV2) GenRollbackSQL(ctx context.Context, sql string) (string, string, error) {
+	return s.driver.GenRollbackSQL(ctx, sql)
+}
+
+func (s *PluginImplBuiltInV2) Ping(ctx context.Context) error {
+	return s.driver.Ping(ctx)
+}
+
+func (s *PluginImplBuiltInV2) Exec(ctx context.Context, query string) (sqlDriver.Result, error) {
+	return s.driver.Exec(ctx, query)
+}
+
+func (s *PluginImplBuiltInV2) ExecBatch(ctx context.Context, sqls ...string) ([]sqlDriver.Result, error) {
+	return s.driver.ExecBatch(ctx, sqls...)
+}
+
+func (s *PluginImplBuiltInV2) Tx(ctx context.Context, queries ...string) ([]sqlDriver.Result, error) {
+	return s.driver.Tx(ctx, queries...)
+}
+
+func (s *PluginImplBuiltInV2) Query(ctx context.Context, sql string, conf *driverV2.QueryConf) (*driverV2.QueryResult, error) {
+	return s.driver.Query(ctx, sql, conf)
+}
+
+func (s *PluginImplBuiltInV2) Explain(ctx context.Context, conf *driverV2.ExplainConf) (*driverV2.ExplainResult, error) {
+	return s.driver.Explain(ctx, conf)
+}
+
+func (s *PluginImplBuiltInV2) Schemas(ctx context.Context) ([]string, error) {
+	return s.driver.GetDatabases(ctx)
+}
+
+func (s *PluginImplBuiltInV2) GetTableMetaBySQL(ctx context.Context, conf *GetTableMetaBySQLConf) (*GetTableMetaBySQLResult, error) {
+	tables, err := s.driver.ExtractTableFromSQL(ctx, conf.Sql)
+	if err != nil {
+		return nil, err
+	}
+	tableMetas := make([]*TableMeta, 0, len(tables))
+	for _, table := range tables {
+		tableMeta, err := s.driver.GetTableMeta(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		tableMetas = append(tableMetas, &TableMeta{
+			Table:     *table,
+			TableMeta: *tableMeta,
+		})
+	}
+	return &GetTableMetaBySQLResult{TableMetas: tableMetas}, nil
+}
+
+func (s *PluginImplBuiltInV2) EstimateSQLAffectRows(ctx context.Context, sql string) (*driverV2.EstimatedAffectRows, error) {
+	return s.driver.EstimateSQLAffectRows(ctx, sql)
+}
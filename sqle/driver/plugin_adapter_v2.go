@@ -5,8 +5,11 @@ import (
 	sqlDriver "database/sql/driver"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	protoV2 "github.com/actiontech/sqle/sqle/driver/v2/proto"
@@ -15,6 +18,7 @@ import (
 
 	goPlugin "github.com/hashicorp/go-plugin"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 )
 
@@ -25,6 +29,19 @@ type PluginProcessorV2 struct {
 	client            *goPlugin.Client
 	meta              *driverV2.DriverMetas
 	pluginPidFilePath string
+	// streamAudit is true if the plugin implements ParseStream and AuditStream.
+	streamAudit bool
+
+	// the status of plugin process, it is updated by health check and restart.
+	startedAt     time.Time
+	healthy       bool
+	restartCount  int
+	failures      int // the count of continuous failures
+	nextRestartAt time.Time
+	lastError     error
+	lastErrorAt   time.Time
+	// sessions is the count of the sessions opened and not closed yet.
+	sessions int
 	sync.Mutex
 }
 
@@ -34,15 +51,11 @@ func (d *PluginProcessorV2) getDriverClient(l *logrus.Entry) (protoV2.DriverClie
 	d.Lock()
 	if d.client.Exited() {
 		l.Infof("plugin process is exited, restart it")
-		newClient := goPlugin.NewClient(d.cfg(d.cmdBase, d.cmdArgs))
-		_, err := newClient.Client()
-		if err != nil {
+		if err := d.restart(l); err != nil {
 			d.Unlock()
 			return nil, err
 		}
 		l.Infof("restart plugin success")
-		d.client.Kill()
-		d.client = newClient
 	}
 
 	client = d.client
@@ -64,6 +77,128 @@ func (d *PluginProcessorV2) getDriverClient(l *logrus.Entry) (protoV2.DriverClie
 	return s, nil
 }
 
+// restart starts a new plugin process to replace the current one, it should be
+// called with the lock held.
+func (d *PluginProcessorV2) restart(l *logrus.Entry) error {
+	newClient := goPlugin.NewClient(d.cfg(d.cmdBase, d.cmdArgs))
+	if _, err := newClient.Client(); err != nil {
+		newClient.Kill()
+		d.setError(err)
+		return err
+	}
+	d.client.Kill()
+	d.client = newClient
+	d.startedAt = time.Now()
+	d.restartCount++
+	if d.pluginPidFilePath != "" {
+		if err := WritePidFile(d.pluginPidFilePath, int64(d.pid())); err != nil {
+			l.Warnf("write plugin pid file %s failed, error: %v", d.pluginPidFilePath, err)
+		}
+	}
+	return nil
+}
+
+func (d *PluginProcessorV2) pid() int {
+	if rc := d.client.ReattachConfig(); rc != nil {
+		return rc.Pid
+	}
+	return 0
+}
+
+func (d *PluginProcessorV2) setError(err error) {
+	d.lastError = err
+	d.lastErrorAt = time.Now()
+}
+
+func pingClient(client *goPlugin.Client, timeout time.Duration) error {
+	if client.Exited() {
+		return errors.New("plugin process is exited")
+	}
+	cp, err := client.Client()
+	if err != nil {
+		return err
+	}
+	gc, ok := cp.(*goPlugin.GRPCClient)
+	if !ok {
+		return cp.Ping()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err = grpc_health_v1.NewHealthClient(gc.Conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: goPlugin.GRPCServiceName,
+	})
+	return err
+}
+
+// check pings the plugin process and restarts it if the process is exited or
+// unhealthy. If the plugin keeps failing, the restart is delayed by backoff,
+// the failures are reset after the plugin passes a health check. The unhealthy
+// process is not restarted while there are active sessions on it.
+func (d *PluginProcessorV2) check(l *logrus.Entry, now time.Time) {
+	d.Lock()
+	client := d.client
+	d.Unlock()
+	// the lock is not held while pinging, so that a hung plugin does not block
+	// the sessions and the status query.
+	err := pingClient(client, pluginPingTimeout)
+
+	d.Lock()
+	defer d.Unlock()
+	if d.client != client {
+		// the process is restarted by others while pinging.
+		return
+	}
+	if err == nil {
+		d.healthy = true
+		d.failures = 0
+		return
+	}
+	if d.healthy {
+		l.Warnf("plugin is unhealthy, error: %v", err)
+		d.healthy = false
+		d.setError(err)
+	}
+	if now.Before(d.nextRestartAt) {
+		return
+	}
+	if d.sessions > 0 && !client.Exited() {
+		l.Warnf("plugin is unhealthy but has %d active sessions, restart it after they are closed", d.sessions)
+		return
+	}
+	d.failures++
+	d.nextRestartAt = now.Add(restartBackoff(d.failures))
+	if err := d.restart(l); err != nil {
+		l.Errorf("restart plugin failed, retry after %v, error: %v", d.nextRestartAt.Sub(now), err)
+		return
+	}
+	l.Infof("restart plugin success, pid: %d", d.pid())
+	d.healthy = true
+}
+
+func (d *PluginProcessorV2) status() *PluginStatus {
+	d.Lock()
+	defer d.Unlock()
+	s := &PluginStatus{
+		Version:      driverV2.ProtocolVersion,
+		Status:       PluginStatusRunning,
+		Pid:          d.pid(),
+		RestartCount: d.restartCount,
+	}
+	if !d.healthy {
+		s.Status = PluginStatusRestarting
+	}
+	if !d.startedAt.IsZero() {
+		startedAt := d.startedAt
+		s.StartedAt = &startedAt
+	}
+	if d.lastError != nil {
+		lastErrorAt := d.lastErrorAt
+		s.LastError = d.lastError.Error()
+		s.LastErrorAt = &lastErrorAt
+	}
+	return s
+}
+
 func (d *PluginProcessorV2) GetDriverMetas() (*driverV2.DriverMetas, error) {
 	c, err := d.getDriverClient(log.NewEntry())
 	if err != nil {
@@ -93,6 +228,12 @@ func (d *PluginProcessorV2) GetDriverMetas() (*driverV2.DriverMetas, error) {
 		EnabledOptionalModule:    ms,
 	}
 	d.meta = meta
+	d.streamAudit = false
+	for _, c := range result.Capabilities {
+		if c == protoV2.Capability_StreamAudit {
+			d.streamAudit = true
+		}
+	}
 	return meta, nil
 }
 
@@ -132,27 +273,67 @@ func (d *PluginProcessorV2) Open(l *logrus.Entry, cfgV2 *driverV2.Config) (Plugi
 		return nil, err
 	}
 	l.Infof("call plugin interface [Init] success")
+	d.Lock()
+	d.sessions++
+	d.Unlock()
 	return &PluginImplV2{
-		client:  c,
-		Session: result.Session,
-		l:       l.WithField("session_id", result.Session.Id),
+		client:      c,
+		Session:     result.Session,
+		l:           l.WithField("session_id", result.Session.Id),
+		streamAudit: d.streamAudit,
+		release:     d.releaseSession,
 	}, nil
 }
 
+func (d *PluginProcessorV2) releaseSession() {
+	d.Lock()
+	d.sessions--
+	d.Unlock()
+}
+
+func (d *PluginProcessorV2) activeSessions() int {
+	d.Lock()
+	defer d.Unlock()
+	return d.sessions
+}
+
+// drain waits for the sessions on the plugin to be closed, it returns false if
+// there are still active sessions after timeout.
+func (d *PluginProcessorV2) drain(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for d.activeSessions() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(pluginDrainCheckInterval)
+	}
+	return true
+}
+
 func (d *PluginProcessorV2) Stop() error {
 	d.Lock()
+	pid := 0
 	if d.client != nil {
+		pid = d.pid()
 		d.client.Kill()
 	}
-	os.Remove(d.pluginPidFilePath)
+	// the pid file may be rewritten by the new process of plugin which replaces
+	// this one, so it is removed only if it is written by this process.
+	if content, err := os.ReadFile(d.pluginPidFilePath); err == nil && string(content) == strconv.Itoa(pid) {
+		os.Remove(d.pluginPidFilePath)
+	}
 	d.Unlock()
 	return nil
 }
 
 type PluginImplV2 struct {
-	l       *logrus.Entry
-	client  protoV2.DriverClient
-	Session *protoV2.Session
+	l           *logrus.Entry
+	client      protoV2.DriverClient
+	Session     *protoV2.Session
+	streamAudit bool
+	// release is called once when the session is closed.
+	release     func()
+	releaseOnce sync.Once
 }
 
 func (s *PluginImplV2) preLog(ApiName string) {
@@ -174,6 +355,9 @@ func (s *PluginImplV2) Close(ctx context.Context) {
 		Session: s.Session,
 	})
 	s.afterLog(api, err)
+	if s.release != nil {
+		s.releaseOnce.Do(s.release)
+	}
 }
 
 func (s *PluginImplV2) KillProcess(ctx context.Context) error {
@@ -195,6 +379,9 @@ func (s *PluginImplV2) KillProcess(ctx context.Context) error {
 // audit
 
 func (s *PluginImplV2) Parse(ctx context.Context, sqlText string) ([]driverV2.Node, error) {
+	if s.streamAudit && len(sqlText) > driverV2.StreamChunkSize {
+		return s.parseStream(ctx, sqlText)
+	}
 	api := "Parse"
 	s.preLog(api)
 	resp, err := s.client.Parse(ctx, &protoV2.ParseRequest{
@@ -207,9 +394,59 @@ func (s *PluginImplV2) Parse(ctx context.Context, sqlText string) ([]driverV2.No
 	if err != nil {
 		return nil, err
 	}
+	return convertNodesFromProto(resp.Nodes), nil
+}
+
+// parseStream sends the SQL text in chunks, and receives the nodes in chunks.
+func (s *PluginImplV2) parseStream(ctx context.Context, sqlText string) ([]driverV2.Node, error) {
+	api := "ParseStream"
+	s.preLog(api)
+	nodes, err := func() ([]driverV2.Node, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := s.client.ParseStream(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(sqlText); i += driverV2.StreamChunkSize {
+			end := i + driverV2.StreamChunkSize
+			if end > len(sqlText) {
+				end = len(sqlText)
+			}
+			err := stream.Send(&protoV2.ParseStreamRequest{
+				Session:  s.Session,
+				SqlChunk: []byte(sqlText[i:end]),
+			})
+			if errors.Is(err, io.EOF) {
+				// the stream is closed by plugin, the error is returned by Recv.
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := stream.CloseSend(); err != nil {
+			return nil, err
+		}
+		nodes := []driverV2.Node{}
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nodes, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, convertNodesFromProto(resp.Nodes)...)
+		}
+	}()
+	s.afterLog(api, err)
+	return nodes, err
+}
 
-	nodes := make([]driverV2.Node, len(resp.Nodes))
-	for i, node := range resp.Nodes {
+func convertNodesFromProto(pNodes []*protoV2.Node) []driverV2.Node {
+	nodes := make([]driverV2.Node, len(pNodes))
+	for i, node := range pNodes {
 		nodes[i] = driverV2.Node{
 			Type:        node.Type,
 			Text:        node.Text,
@@ -218,38 +455,92 @@ func (s *PluginImplV2) Parse(ctx context.Context, sqlText string) ([]driverV2.No
 			ExecBatchId: node.BatchId,
 		}
 	}
-	return nodes, nil
+	return nodes
 }
 
 func (s *PluginImplV2) Audit(ctx context.Context, sqls []string) ([]*driverV2.AuditResults, error) {
+	if s.streamAudit {
+		if chunks := driverV2.SplitAuditChunks(sqls); len(chunks) > 1 {
+			return s.auditStream(ctx, chunks, len(sqls))
+		}
+	}
 	api := "Audit"
 	s.preLog(api)
-	auditSqls := make([]*protoV2.AuditSQL, 0, len(sqls))
-	for _, sql := range sqls {
-		auditSqls = append(auditSqls, &protoV2.AuditSQL{Query: sql})
-	}
 	resp, err := s.client.Audit(ctx, &protoV2.AuditRequest{
 		Session: s.Session,
-		Sqls:    auditSqls,
+		Sqls:    convertAuditSQLsToProto(sqls),
 	})
 	s.afterLog(api, err)
 	if err != nil {
 		return nil, err
 	}
+	return convertAuditResultsFromProto(resp.AuditResults), nil
+}
+
+// auditStream sends the chunks of SQLs one by one, the progress is reported
+// after the results of each chunk are received.
+func (s *PluginImplV2) auditStream(ctx context.Context, chunks [][]string, total int) ([]*driverV2.AuditResults, error) {
+	api := "AuditStream"
+	s.preLog(api)
+	rets, err := func() ([]*driverV2.AuditResults, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := s.client.AuditStream(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rets := make([]*driverV2.AuditResults, 0, total)
+		for i, chunk := range chunks {
+			err := stream.Send(&protoV2.AuditRequest{
+				Session: s.Session,
+				Sqls:    convertAuditSQLsToProto(chunk),
+			})
+			// if the stream is closed by plugin, the error is returned by Recv.
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			resp, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			if len(resp.AuditResults) != len(chunk) {
+				return nil, fmt.Errorf("audit results [%d] of chunk %d does not match the number of SQL [%d]", len(resp.AuditResults), i, len(chunk))
+			}
+			rets = append(rets, convertAuditResultsFromProto(resp.AuditResults)...)
+			s.l.Infof("audit chunk %d/%d, %d/%d SQLs are audited", i+1, len(chunks), len(rets), total)
+			reportAuditProgress(ctx, len(rets), total)
+		}
+		return rets, stream.CloseSend()
+	}()
+	s.afterLog(api, err)
+	return rets, err
+}
+
+func convertAuditSQLsToProto(sqls []string) []*protoV2.AuditSQL {
+	auditSqls := make([]*protoV2.AuditSQL, 0, len(sqls))
+	for _, sql := range sqls {
+		auditSqls = append(auditSqls, &protoV2.AuditSQL{Query: sql})
+	}
+	return auditSqls
+}
 
+func convertAuditResultsFromProto(auditResults []*protoV2.AuditResults) []*driverV2.AuditResults {
 	rets := []*driverV2.AuditResults{}
-	for _, results := range resp.AuditResults {
+	for _, results := range auditResults {
 		ret := &driverV2.AuditResults{}
 		for _, result := range results.Results {
 			ret.Results = append(ret.Results, &driverV2.AuditResult{
-				Level:    driverV2.RuleLevel(result.Level),
-				Message:  result.Message,
-				RuleName: result.RuleName,
+				Level:        driverV2.RuleLevel(result.Level),
+				Message:      result.Message,
+				RuleName:     result.RuleName,
+				MessageId:    result.MessageId,
+				MessageArgs:  result.MessageArgs,
+				I18nMessages: result.I18NMessages,
 			})
 		}
 		rets = append(rets, ret)
 	}
-	return rets, nil
+	return rets
 }
 
 func (s *PluginImplV2) GenRollbackSQL(ctx context.Context, sql string) (string, string, error) {
@@ -37,12 +37,27 @@ type pluginManager struct {
 	pluginNames      []string
 	metas            map[string]driverV2.DriverMetas
 	pluginProcessors map[string]PluginProcessor
+	// builtInPlugins record the plugins registered from BuiltInPluginProcessors,
+	// a plugin in plugin dir with the same name will replace the built-in one.
+	builtInPlugins map[string]struct{}
+	// files record the plugin files loaded from plugin dir, the key is file name.
+	files map[string]*pluginFile
+	sync.RWMutex
+
+	pluginDir        string
+	pluginConfigList []config.PluginConfig
+	// reloadMu serializes the reloading of plugins.
+	reloadMu   sync.Mutex
+	onReloaded func()
+	stopCh     chan struct{}
 }
 
 var PluginManager = &pluginManager{
 	pluginNames:      []string{},
 	metas:            map[string]driverV2.DriverMetas{},
 	pluginProcessors: map[string]PluginProcessor{},
+	builtInPlugins:   map[string]struct{}{},
+	files:            map[string]*pluginFile{},
 }
 
 func GetPluginManager() *pluginManager {
@@ -50,6 +65,8 @@ func GetPluginManager() *pluginManager {
 }
 
 func (pm *pluginManager) GetAllRules() map[string][]*driverV2.Rule {
+	pm.RLock()
+	defer pm.RUnlock()
 	rules := map[string][]*driverV2.Rule{}
 	for _, p := range pm.pluginNames {
 		meta := pm.metas[p]
@@ -59,6 +76,8 @@ func (pm *pluginManager) GetAllRules() map[string][]*driverV2.Rule {
 }
 
 func (pm *pluginManager) GetDriverMetasOfPlugin(pluginName string) *driverV2.DriverMetas {
+	pm.RLock()
+	defer pm.RUnlock()
 	if dm, exist := pm.metas[pluginName]; exist {
 		return &dm
 	}
@@ -66,10 +85,14 @@ func (pm *pluginManager) GetDriverMetasOfPlugin(pluginName string) *driverV2.Dri
 }
 
 func (pm *pluginManager) AllDrivers() []string {
-	return pm.pluginNames
+	pm.RLock()
+	defer pm.RUnlock()
+	return append([]string{}, pm.pluginNames...)
 }
 
 func (pm *pluginManager) AllDriverMetas() []*driverV2.DriverMetas {
+	pm.RLock()
+	defer pm.RUnlock()
 	metas := make([]*driverV2.DriverMetas, len(pm.metas))
 
 	for i := range pm.pluginNames {
@@ -87,6 +110,8 @@ func (pm *pluginManager) AllDriverMetas() []*driverV2.DriverMetas {
 }
 
 func (pm *pluginManager) AllLogo() map[string][]byte {
+	pm.RLock()
+	defer pm.RUnlock()
 	logoMap := map[string][]byte{}
 	for _, pluginName := range pm.pluginNames {
 		meta := pm.metas[pluginName]
@@ -98,6 +123,8 @@ func (pm *pluginManager) AllLogo() map[string][]byte {
 }
 
 func (pm *pluginManager) AllAdditionalParams() map[string] /*driver name*/ params.Params {
+	pm.RLock()
+	defer pm.RUnlock()
 	newParams := map[string]params.Params{}
 	for k, v := range pm.metas {
 		newParams[k] = v.DatabaseAdditionalParams.Copy()
@@ -106,6 +133,8 @@ func (pm *pluginManager) AllAdditionalParams() map[string] /*driver name*/ param
 }
 
 func (pm *pluginManager) IsOptionalModuleEnabled(pluginName string, expectModule driverV2.OptionalModule) bool {
+	pm.RLock()
+	defer pm.RUnlock()
 	meta, ok := pm.metas[pluginName]
 	if !ok {
 		return false
@@ -118,18 +147,33 @@ func (pm *pluginManager) IsOptionalModuleEnabled(pluginName string, expectModule
 	return false
 }
 
-func (pm *pluginManager) register(pp PluginProcessor) error {
+// register registers the plugin loaded from the file of plugin dir, or the
+// built-in plugin if fileName is empty. The plugin replaces the built-in one or
+// the one loaded from the same file, the replaced plugin is returned.
+func (pm *pluginManager) register(pp PluginProcessor, fileName string) (string, PluginProcessor, error) {
 	meta, err := pp.GetDriverMetas()
 	if err != nil {
-		return err
+		return "", nil, err
 	}
+	pm.Lock()
+	defer pm.Unlock()
+	var replaced PluginProcessor
 	if _, ok := pm.metas[meta.PluginName]; ok {
-		return fmt.Errorf("duplicated driver name %s", meta.PluginName)
+		if _, isBuiltIn := pm.builtInPlugins[meta.PluginName]; isBuiltIn {
+			log.NewEntry().Warnf("plugin %s in plugin dir replaces the built-in driver", meta.PluginName)
+			delete(pm.builtInPlugins, meta.PluginName)
+		} else if f, ok := pm.files[fileName]; ok && f.pluginName == meta.PluginName {
+			replaced = pm.pluginProcessors[meta.PluginName]
+		} else {
+			return "", nil, fmt.Errorf("duplicated driver name %s", meta.PluginName)
+		}
+	} else {
+		pm.pluginNames = append(pm.pluginNames, meta.PluginName)
 	}
-	pm.pluginNames = append(pm.pluginNames, meta.PluginName)
 	pm.metas[meta.PluginName] = *meta
 	pm.pluginProcessors[meta.PluginName] = pp
-	return nil
+	driverV2.RegisterRuleMessages(meta.PluginName, meta.Rules)
+	return meta.PluginName, replaced, nil
 }
 
 func getClientConfig(cmdBase string, cmdArgs []string) *goPlugin.ClientConfig {
@@ -154,29 +198,22 @@ func getClientConfig(cmdBase string, cmdArgs []string) *goPlugin.ClientConfig {
 func (pm *pluginManager) Start(pluginDir string, pluginConfigList []config.PluginConfig) error {
 	// register built-in plugin, now is MySQL.
 	for name, b := range BuiltInPluginProcessors {
-		err := pm.register(b)
+		_, _, err := pm.register(b, "")
 		if err != nil {
 			return fmt.Errorf("start built-in %s plugin failed, error: %v", name, err)
 		}
+		pm.builtInPlugins[name] = struct{}{}
 	}
 
 	if pluginDir == "" {
 		return nil
 	}
+	pm.pluginDir = pluginDir
+	pm.pluginConfigList = pluginConfigList
 
 	// read plugin file
-	var plugins []os.FileInfo
-	if err := filepath.Walk(pluginDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return errors.Wrap(err, "init plugin")
-		}
-
-		if info.IsDir() || info.Mode()&0111 == 0 {
-			return nil
-		}
-		plugins = append(plugins, info)
-		return nil
-	}); err != nil {
+	plugins, err := scanPluginFiles(pluginDir)
+	if err != nil {
 		return err
 	}
 
@@ -205,54 +242,95 @@ func (pm *pluginManager) Start(pluginDir string, pluginConfigList []config.Plugi
 
 	// register plugin
 	for _, p := range plugins {
-		cmdBase := filepath.Join(pluginDir, p.Name())
-		cmdArgs := make([]string, 0)
-
-		for _, pluginConfig := range pluginConfigList {
-			if p.Name() == pluginConfig.PluginName {
-				cmdBase = "sh"
-				cmdArgs = append(cmdArgs, "-c", pluginConfig.CMD)
-				break
-			}
-		}
-
-		if len(cmdArgs) == 0 && strings.HasSuffix(p.Name(), ".jar") {
-			javaPluginCmd := fmt.Sprintf("java -jar %s", cmdBase)
-			cmdBase = "sh"
-			cmdArgs = append(cmdArgs, "-c", javaPluginCmd)
-		}
-
-		client := goPlugin.NewClient(getClientConfig(cmdBase, cmdArgs))
-		_, err := client.Client()
+		pp, err := pm.startPlugin(p.Name())
 		if err != nil {
-			return fmt.Errorf("plugin %v failed to start, error: %v Please check the sqled.log for more details", p.Name(), err)
+			return err
 		}
-
-		pluginPidFilePath := GetPluginPidFilePath(pluginDir, p.Name())
-		err = WritePidFile(pluginPidFilePath, int64(client.ReattachConfig().Pid))
+		name, _, err := pm.register(pp, p.Name())
 		if err != nil {
-			return fmt.Errorf("write plugin %s pid file failed, error: %v", pluginPidFilePath, err)
-		}
-		var pp PluginProcessor
-		switch client.NegotiatedVersion() {
-		case driverV1.ProtocolVersion:
-			pp = &PluginProcessorV1{cfg: getClientConfig, cmdBase: cmdBase, cmdArgs: cmdArgs, client: client}
-		case driverV2.ProtocolVersion:
-			pp = &PluginProcessorV2{cfg: getClientConfig, cmdBase: cmdBase, cmdArgs: cmdArgs, client: client, pluginPidFilePath: pluginPidFilePath}
-		}
-		if err := pm.register(pp); err != nil {
 			stopErr := pp.Stop()
 			if stopErr != nil {
 				log.NewEntry().Warnf("stop plugin %s failed, error: %v", p.Name(), stopErr)
 			}
 			return fmt.Errorf("unable to load plugin: %v, error: %v", p.Name(), err)
 		}
-
+		pm.setPluginFile(p, name, nil)
 	}
+
+	pm.stopCh = make(chan struct{})
+	go pm.supervise(pm.stopCh)
 	return nil
 }
 
+// scanPluginFiles returns the executable files in plugin dir.
+func scanPluginFiles(pluginDir string) ([]os.FileInfo, error) {
+	var plugins []os.FileInfo
+	if err := filepath.Walk(pluginDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Wrap(err, "init plugin")
+		}
+
+		if info.IsDir() || info.Mode()&0111 == 0 {
+			return nil
+		}
+		plugins = append(plugins, info)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
+
+// startPlugin starts the process of plugin file and writes its pid file.
+func (pm *pluginManager) startPlugin(fileName string) (PluginProcessor, error) {
+	cmdBase := filepath.Join(pm.pluginDir, fileName)
+	cmdArgs := make([]string, 0)
+
+	for _, pluginConfig := range pm.pluginConfigList {
+		if fileName == pluginConfig.PluginName {
+			cmdBase = "sh"
+			cmdArgs = append(cmdArgs, "-c", pluginConfig.CMD)
+			break
+		}
+	}
+
+	if len(cmdArgs) == 0 && strings.HasSuffix(fileName, ".jar") {
+		javaPluginCmd := fmt.Sprintf("java -jar %s", cmdBase)
+		cmdBase = "sh"
+		cmdArgs = append(cmdArgs, "-c", javaPluginCmd)
+	}
+
+	client := goPlugin.NewClient(getClientConfig(cmdBase, cmdArgs))
+	_, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("plugin %v failed to start, error: %v Please check the sqled.log for more details", fileName, err)
+	}
+
+	pluginPidFilePath := GetPluginPidFilePath(pm.pluginDir, fileName)
+	err = WritePidFile(pluginPidFilePath, int64(client.ReattachConfig().Pid))
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("write plugin %s pid file failed, error: %v", pluginPidFilePath, err)
+	}
+	var pp PluginProcessor
+	switch client.NegotiatedVersion() {
+	case driverV1.ProtocolVersion:
+		pp = &PluginProcessorV1{cfg: getClientConfig, cmdBase: cmdBase, cmdArgs: cmdArgs, client: client}
+	case driverV2.ProtocolVersion:
+		pp = &PluginProcessorV2{cfg: getClientConfig, cmdBase: cmdBase, cmdArgs: cmdArgs, client: client, pluginPidFilePath: pluginPidFilePath,
+			startedAt: time.Now(), healthy: true}
+	}
+	return pp, nil
+}
+
 func (pm *pluginManager) Stop() {
+	pm.Lock()
+	defer pm.Unlock()
+	if pm.stopCh != nil {
+		close(pm.stopCh)
+		pm.stopCh = nil
+	}
 	for name, pp := range pm.pluginProcessors {
 		err := pp.Stop()
 		if err != nil {
@@ -261,18 +339,14 @@ func (pm *pluginManager) Stop() {
 	}
 }
 
-func (pm *pluginManager) isPluginExists(pluginName string) bool {
-	if _, ok := pm.metas[pluginName]; ok {
-		return true
-	}
-	return false
-}
-
 func (pm *pluginManager) OpenPlugin(l *logrus.Entry, pluginName string, cfg *driverV2.Config) (Plugin, error) {
-	if !pm.isPluginExists(pluginName) {
+	pm.RLock()
+	pp, ok := pm.pluginProcessors[pluginName]
+	pm.RUnlock()
+	if !ok {
 		return nil, ErrPluginNotFound
 	}
-	return pm.pluginProcessors[pluginName].Open(l, cfg)
+	return pp.Open(l, cfg)
 }
 
 func KillResidualPluginsProcess(pidFile string) error {
@@ -0,0 +1,241 @@
+package driver
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	driverV1 "github.com/actiontech/sqle/sqle/driver/v1"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/log"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	pluginCheckInterval       = 10 * time.Second
+	pluginRestartBackoffBase  = 5 * time.Second
+	pluginRestartBackoffLimit = 5 * time.Minute
+	pluginPingTimeout         = 5 * time.Second
+	// pluginDrainTimeout is the max time to wait for the sessions on the
+	// replaced plugin to be closed before stopping it.
+	pluginDrainTimeout       = 30 * time.Minute
+	pluginDrainCheckInterval = time.Second
+)
+
+const (
+	PluginStatusRunning    = "running"
+	PluginStatusRestarting = "restarting"
+	// PluginStatusFailed is the status of plugin file which is failed to load.
+	PluginStatusFailed = "failed"
+)
+
+type PluginStatus struct {
+	PluginName string
+	// FileName is the plugin file in plugin dir, it is empty for built-in plugin.
+	FileName string
+	// Version is the protocol version of plugin.
+	Version      int
+	Status       string
+	Pid          int
+	RestartCount int
+	LastError    string
+	LastErrorAt  *time.Time
+	StartedAt    *time.Time
+}
+
+type pluginFile struct {
+	// pluginName is empty if the file is failed to load.
+	pluginName string
+	modTime    time.Time
+	size       int64
+	err        error
+	errAt      time.Time
+}
+
+func (pm *pluginManager) setPluginFile(info os.FileInfo, pluginName string, err error) {
+	pm.Lock()
+	defer pm.Unlock()
+	pm.files[info.Name()] = &pluginFile{
+		pluginName: pluginName,
+		modTime:    info.ModTime(),
+		size:       info.Size(),
+		err:        err,
+		errAt:      time.Now(),
+	}
+}
+
+// restartBackoff returns the delay of next restart after the continuous failures.
+func restartBackoff(failures int) time.Duration {
+	backoff := pluginRestartBackoffBase
+	for i := 1; i < failures && backoff < pluginRestartBackoffLimit; i++ {
+		backoff *= 2
+	}
+	if backoff > pluginRestartBackoffLimit {
+		backoff = pluginRestartBackoffLimit
+	}
+	return backoff
+}
+
+// OnPluginsReloaded sets the function which is called after some plugins are
+// added or updated by reloading.
+func (pm *pluginManager) OnPluginsReloaded(fn func()) {
+	pm.reloadMu.Lock()
+	pm.onReloaded = fn
+	pm.reloadMu.Unlock()
+}
+
+// supervise checks the health of plugins and reloads the plugin files changed
+// in plugin dir periodically.
+func (pm *pluginManager) supervise(stopCh chan struct{}) {
+	ticker := time.NewTicker(pluginCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+		pm.checkPlugins(time.Now())
+		if err := pm.Reload(false); err != nil {
+			log.NewEntry().Errorf("reload plugins error: %v", err)
+		}
+	}
+}
+
+func (pm *pluginManager) checkPlugins(now time.Time) {
+	pm.RLock()
+	processors := map[string]*PluginProcessorV2{}
+	for name, pp := range pm.pluginProcessors {
+		if p, ok := pp.(*PluginProcessorV2); ok {
+			processors[name] = p
+		}
+	}
+	pm.RUnlock()
+	for name, p := range processors {
+		p.check(log.NewEntry().WithField("plugin", name), now)
+	}
+}
+
+// Reload loads the plugin files which are added or updated in plugin dir, the
+// updated plugin replaces the running one. The files failed to load before are
+// loaded again if retryFailed is true, otherwise they are loaded after updated.
+func (pm *pluginManager) Reload(retryFailed bool) error {
+	if pm.pluginDir == "" {
+		return fmt.Errorf("plugin dir is not configured")
+	}
+	pm.reloadMu.Lock()
+	defer pm.reloadMu.Unlock()
+
+	plugins, err := scanPluginFiles(pm.pluginDir)
+	if err != nil {
+		return err
+	}
+	reloaded := 0
+	errs := []string{}
+	for _, p := range plugins {
+		pm.RLock()
+		f, ok := pm.files[p.Name()]
+		pm.RUnlock()
+		if ok && f.modTime.Equal(p.ModTime()) && f.size == p.Size() && (f.err == nil || !retryFailed) {
+			continue
+		}
+		l := log.NewEntry().WithField("plugin_file", p.Name())
+		l.Infof("load plugin file")
+		if err := pm.reloadPlugin(p); err != nil {
+			l.Errorf("load plugin file failed, error: %v", err)
+			errs = append(errs, fmt.Sprintf("%s: %v", p.Name(), err))
+			continue
+		}
+		reloaded++
+	}
+	if reloaded > 0 && pm.onReloaded != nil {
+		pm.onReloaded()
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("load plugin files failed, %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (pm *pluginManager) reloadPlugin(info os.FileInfo) error {
+	pp, err := pm.startPlugin(info.Name())
+	if err != nil {
+		pm.setPluginFile(info, "", err)
+		return err
+	}
+	name, replaced, err := pm.register(pp, info.Name())
+	if err != nil {
+		if stopErr := pp.Stop(); stopErr != nil {
+			log.NewEntry().Warnf("stop plugin %s failed, error: %v", info.Name(), stopErr)
+		}
+		pm.setPluginFile(info, "", err)
+		return err
+	}
+	pm.setPluginFile(info, name, nil)
+	if replaced != nil {
+		// the new sessions are opened on the new plugin after registered, the
+		// replaced one is stopped after the sessions on it are closed.
+		go stopReplacedPlugin(log.NewEntry().WithField("plugin", name), replaced, pluginDrainTimeout)
+	}
+	return nil
+}
+
+func stopReplacedPlugin(l *logrus.Entry, replaced PluginProcessor, timeout time.Duration) {
+	if p, ok := replaced.(*PluginProcessorV2); ok && !p.drain(timeout) {
+		l.Warnf("stop replaced plugin with %d active sessions after waiting %v", p.activeSessions(), timeout)
+	}
+	if err := replaced.Stop(); err != nil {
+		l.Warnf("stop replaced plugin failed, error: %v", err)
+	}
+}
+
+// PluginStatuses returns the status of plugins, and the plugin files which are
+// failed to load.
+func (pm *pluginManager) PluginStatuses() []*PluginStatus {
+	pm.RLock()
+	fileNames := map[string]string{}
+	failed := []*PluginStatus{}
+	for fileName, f := range pm.files {
+		if f.pluginName != "" {
+			fileNames[f.pluginName] = fileName
+			continue
+		}
+		errAt := f.errAt
+		failed = append(failed, &PluginStatus{
+			FileName:    fileName,
+			Status:      PluginStatusFailed,
+			LastError:   f.err.Error(),
+			LastErrorAt: &errAt,
+		})
+	}
+	names := append([]string{}, pm.pluginNames...)
+	processors := map[string]PluginProcessor{}
+	for name, pp := range pm.pluginProcessors {
+		processors[name] = pp
+	}
+	pm.RUnlock()
+
+	statuses := make([]*PluginStatus, 0, len(names)+len(failed))
+	for _, name := range names {
+		var s *PluginStatus
+		switch p := processors[name].(type) {
+		case *PluginProcessorV2:
+			s = p.status()
+		case *PluginProcessorV1:
+			// the process of v1 plugin is started for each session.
+			s = &PluginStatus{Version: driverV1.ProtocolVersion, Status: PluginStatusRunning}
+		default:
+			s = &PluginStatus{Version: driverV2.ProtocolVersion, Status: PluginStatusRunning}
+		}
+		s.PluginName = name
+		s.FileName = fileNames[name]
+		statuses = append(statuses, s)
+	}
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].FileName < failed[j].FileName
+	})
+	return append(statuses, failed...)
+}
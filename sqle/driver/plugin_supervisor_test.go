@@ -0,0 +1,121 @@
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProcessor struct {
+	name    string
+	stopped bool
+}
+
+func (f *fakeProcessor) GetDriverMetas() (*driverV2.DriverMetas, error) {
+	return &driverV2.DriverMetas{PluginName: f.name}, nil
+}
+
+func (f *fakeProcessor) Open(*logrus.Entry, *driverV2.Config) (Plugin, error) {
+	return nil, nil
+}
+
+func (f *fakeProcessor) Stop() error {
+	f.stopped = true
+	return nil
+}
+
+func newTestPluginManager() *pluginManager {
+	return &pluginManager{
+		pluginNames:      []string{},
+		metas:            map[string]driverV2.DriverMetas{},
+		pluginProcessors: map[string]PluginProcessor{},
+		builtInPlugins:   map[string]struct{}{},
+		files:            map[string]*pluginFile{},
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, restartBackoff(1))
+	assert.Equal(t, 10*time.Second, restartBackoff(2))
+	assert.Equal(t, 40*time.Second, restartBackoff(4))
+	assert.Equal(t, pluginRestartBackoffLimit, restartBackoff(10))
+	assert.Equal(t, pluginRestartBackoffLimit, restartBackoff(100))
+}
+
+func TestPluginManagerRegister(t *testing.T) {
+	pm := newTestPluginManager()
+	_, _, err := pm.register(&fakeProcessor{name: "MySQL"}, "")
+	assert.NoError(t, err)
+	pm.builtInPlugins["MySQL"] = struct{}{}
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mysql-plugin"), []byte("v1"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "pg-plugin"), []byte("v1"), 0755))
+	mysqlFile, err := os.Stat(filepath.Join(dir, "mysql-plugin"))
+	assert.NoError(t, err)
+	pgFile, err := os.Stat(filepath.Join(dir, "pg-plugin"))
+	assert.NoError(t, err)
+
+	// the plugin replaces the built-in one.
+	mysql := &fakeProcessor{name: "MySQL"}
+	name, replaced, err := pm.register(mysql, "mysql-plugin")
+	assert.NoError(t, err)
+	assert.Equal(t, "MySQL", name)
+	assert.Nil(t, replaced)
+	pm.setPluginFile(mysqlFile, name, nil)
+
+	// the plugin from other file can not use the same name.
+	_, _, err = pm.register(&fakeProcessor{name: "MySQL"}, "pg-plugin")
+	assert.Error(t, err)
+	pm.setPluginFile(pgFile, "", err)
+
+	// the plugin replaces the one loaded from the same file.
+	_, replaced, err = pm.register(&fakeProcessor{name: "MySQL"}, "mysql-plugin")
+	assert.NoError(t, err)
+	assert.Equal(t, mysql, replaced)
+	assert.Equal(t, []string{"MySQL"}, pm.AllDrivers())
+
+	statuses := pm.PluginStatuses()
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "MySQL", statuses[0].PluginName)
+	assert.Equal(t, "mysql-plugin", statuses[0].FileName)
+	assert.Equal(t, PluginStatusRunning, statuses[0].Status)
+	assert.Equal(t, "pg-plugin", statuses[1].FileName)
+	assert.Equal(t, PluginStatusFailed, statuses[1].Status)
+	assert.Equal(t, "duplicated driver name MySQL", statuses[1].LastError)
+}
+
+func TestPluginManagerReloadWithoutDir(t *testing.T) {
+	pm := newTestPluginManager()
+	assert.Error(t, pm.Reload(true))
+}
+
+func TestStopReplacedPlugin(t *testing.T) {
+	l := logrus.NewEntry(logrus.New())
+
+	fake := &fakeProcessor{name: "MySQL"}
+	stopReplacedPlugin(l, fake, time.Minute)
+	assert.True(t, fake.stopped)
+
+	// the replaced plugin is stopped after the sessions on it are closed.
+	p := &PluginProcessorV2{sessions: 1}
+	impl := &PluginImplV2{release: p.releaseSession}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		impl.releaseOnce.Do(impl.release)
+		impl.releaseOnce.Do(impl.release)
+	}()
+	assert.True(t, p.drain(time.Minute))
+	assert.Equal(t, 0, p.activeSessions())
+
+	// the replaced plugin is stopped anyway after timeout.
+	p = &PluginProcessorV2{sessions: 1}
+	assert.False(t, p.drain(0))
+	stopReplacedPlugin(l, p, 0)
+}
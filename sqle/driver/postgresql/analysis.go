@@ -0,0 +1,310 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+// planNode is a node of the plan returned by EXPLAIN (FORMAT JSON).
+type planNode struct {
+	NodeType     string      `json:"Node Type"`
+	RelationName string      `json:"Relation Name"`
+	PlanRows     float64     `json:"Plan Rows"`
+	Plans        []*planNode `json:"Plans"`
+}
+
+func (p *planNode) walk(fn func(p *planNode)) {
+	fn(p)
+	for _, child := range p.Plans {
+		child.walk(fn)
+	}
+}
+
+func (d *PostgreSQLDriverImpl) explainJSON(ctx context.Context, sql string) (*planNode, error) {
+	records, err := d.query(ctx, fmt.Sprintf("EXPLAIN (FORMAT JSON) %s", sql))
+	if err != nil {
+		return nil, err
+	}
+	if len(records) != 1 {
+		return nil, fmt.Errorf("unexpected explain result, rows: %d", len(records))
+	}
+	var plans []struct {
+		Plan *planNode `json:"Plan"`
+	}
+	for _, v := range records[0] {
+		if err := json.Unmarshal([]byte(v.String), &plans); err != nil {
+			return nil, fmt.Errorf("unmarshal explain result failed: %v", err)
+		}
+	}
+	if len(plans) == 0 || plans[0].Plan == nil {
+		return nil, fmt.Errorf("unexpected explain result")
+	}
+	return plans[0].Plan, nil
+}
+
+// estimateRows returns the estimated rows of the SQL, the rows of the
+// ModifyTable node is always 0, so its child's rows is used for DML.
+func (d *PostgreSQLDriverImpl) estimateRows(ctx context.Context, sql string) (int64, error) {
+	plan, err := d.explainJSON(ctx, sql)
+	if err != nil {
+		return 0, err
+	}
+	if plan.NodeType == "ModifyTable" && len(plan.Plans) > 0 {
+		plan = plan.Plans[0]
+	}
+	return int64(plan.PlanRows), nil
+}
+
+// Explain get explain result for SQL.
+func (d *PostgreSQLDriverImpl) Explain(ctx context.Context, conf *driverV2.ExplainConf) (*driverV2.ExplainResult, error) {
+	if d.IsOfflineAudit() {
+		return nil, nil
+	}
+	s, err := parseOneStatement(conf.Sql)
+	if err != nil {
+		return nil, err
+	}
+	// only support dml
+	if !s.isDML() || s.kind == stmtExplain || s.kind == stmtShow || s.kind == stmtCopy {
+		return nil, driverV2.ErrSQLIsNotSupported
+	}
+
+	records, err := d.query(ctx, fmt.Sprintf("EXPLAIN %s", s.text))
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, len(records))
+	for i, record := range records {
+		rows[i] = []string{record["QUERY PLAN"].String}
+	}
+	return &driverV2.ExplainResult{
+		ClassicResult: driverV2.ExplainClassicResult{
+			TabularData: driverV2.TabularData{
+				Columns: []driverV2.TabularDataHead{{Name: "QUERY PLAN"}},
+				Rows:    rows,
+			},
+		},
+	}, nil
+}
+
+func (d *PostgreSQLDriverImpl) EstimateSQLAffectRows(ctx context.Context, sql string) (*driverV2.EstimatedAffectRows, error) {
+	if d.IsOfflineAudit() {
+		return nil, nil
+	}
+	s, err := parseOneStatement(sql)
+	if err != nil {
+		return nil, err
+	}
+	switch s.kind {
+	case stmtSelect, stmtInsert, stmtUpdate, stmtDelete:
+	default:
+		return &driverV2.EstimatedAffectRows{ErrMessage: "unsupported sql type"}, nil
+	}
+	count, err := d.estimateRows(ctx, s.text)
+	if err != nil {
+		return nil, fmt.Errorf("get affected row num failed: %w", err)
+	}
+	return &driverV2.EstimatedAffectRows{Count: count}, nil
+}
+
+func (d *PostgreSQLDriverImpl) GetTableMeta(ctx context.Context, table *driverV2.Table) (*driverV2.TableMeta, error) {
+	if d.IsOfflineAudit() {
+		return nil, nil
+	}
+	name := d.Ctx.completeTableName(tableName{schema: table.Schema, name: table.Name})
+
+	columnsInfo, err := d.getTableColumnsInfo(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(columnsInfo.Rows) == 0 {
+		return &driverV2.TableMeta{Message: fmt.Sprintf(TableNotExistMessage, name)}, nil
+	}
+	indexesInfo, err := d.getTableIndexesInfo(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	createTableSQL, err := d.showCreateTable(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &driverV2.TableMeta{
+		ColumnsInfo:    columnsInfo,
+		IndexesInfo:    indexesInfo,
+		CreateTableSQL: createTableSQL,
+	}, nil
+}
+
+const queryTableColumns = `SELECT a.attname AS column_name,
+	pg_catalog.format_type(a.atttypid, a.atttypmod) AS column_type,
+	CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END AS is_nullable,
+	pg_catalog.pg_get_expr(ad.adbin, ad.adrelid) AS column_default,
+	pg_catalog.col_description(a.attrelid, a.attnum) AS column_comment
+FROM pg_catalog.pg_attribute a
+JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_catalog.pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum
+WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`
+
+func (d *PostgreSQLDriverImpl) getTableColumnsInfo(ctx context.Context, table tableName) (driverV2.ColumnsInfo, error) {
+	columns := []driverV2.TabularDataHead{
+		{
+			Name: "COLUMN_NAME",
+			Desc: "列名",
+		}, {
+			Name: "COLUMN_TYPE",
+			Desc: "列类型",
+		}, {
+			Name: "IS_NULLABLE",
+			Desc: "是否可以为空",
+		}, {
+			Name: "COLUMN_DEFAULT",
+			Desc: "默认值",
+		}, {
+			Name: "COLUMN_COMMENT",
+			Desc: "列说明",
+		},
+	}
+	records, err := d.query(ctx, queryTableColumns, table.schema, table.name)
+	if err != nil {
+		return driverV2.ColumnsInfo{}, err
+	}
+	rows := make([][]string, len(records))
+	for i, record := range records {
+		rows[i] = []string{
+			record["column_name"].String,
+			record["column_type"].String,
+			record["is_nullable"].String,
+			record["column_default"].String,
+			record["column_comment"].String,
+		}
+	}
+	ret := driverV2.ColumnsInfo{}
+	ret.Columns = columns
+	ret.Rows = rows
+	return ret, nil
+}
+
+const queryTableIndexes = `SELECT i.relname AS index_name,
+	pg_catalog.array_to_string(ARRAY(
+		SELECT pg_catalog.pg_get_indexdef(ix.indexrelid, k, true) FROM generate_series(1, ix.indnatts) AS k ORDER BY k
+	), ',') AS column_names,
+	CASE WHEN ix.indisunique THEN 'YES' ELSE 'NO' END AS is_unique,
+	CASE WHEN ix.indisprimary THEN 'YES' ELSE 'NO' END AS is_primary,
+	am.amname AS index_type,
+	pg_catalog.pg_get_indexdef(ix.indexrelid) AS index_def,
+	EXISTS (SELECT 1 FROM pg_catalog.pg_constraint con WHERE con.conindid = ix.indexrelid AND con.conrelid = ix.indrelid) AS is_constraint
+FROM pg_catalog.pg_index ix
+JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid
+JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_catalog.pg_am am ON am.oid = i.relam
+WHERE n.nspname = $1 AND t.relname = $2
+ORDER BY i.relname`
+
+func (d *PostgreSQLDriverImpl) getTableIndexesInfo(ctx context.Context, table tableName) (driverV2.IndexesInfo, error) {
+	columns := []driverV2.TabularDataHead{
+		{
+			Name: "Column_name",
+			Desc: "列名",
+		}, {
+			Name: "Key_name",
+			Desc: "索引名",
+		}, {
+			Name: "Unique",
+			Desc: "唯一性",
+		}, {
+			Name: "Primary",
+			Desc: "主键",
+		}, {
+			Name: "Index_type",
+			Desc: "索引类型",
+		}, {
+			Name: "Definition",
+			Desc: "索引定义",
+		},
+	}
+	records, err := d.query(ctx, queryTableIndexes, table.schema, table.name)
+	if err != nil {
+		return driverV2.IndexesInfo{}, err
+	}
+	rows := make([][]string, len(records))
+	for i, record := range records {
+		rows[i] = []string{
+			record["column_names"].String,
+			record["index_name"].String,
+			record["is_unique"].String,
+			record["is_primary"].String,
+			record["index_type"].String,
+			record["index_def"].String,
+		}
+	}
+	ret := driverV2.IndexesInfo{}
+	ret.Columns = columns
+	ret.Rows = rows
+	return ret, nil
+}
+
+const queryTableConstraints = `SELECT con.conname AS name, pg_catalog.pg_get_constraintdef(con.oid, true) AS definition
+FROM pg_catalog.pg_constraint con
+JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2
+ORDER BY CASE con.contype WHEN 'p' THEN 0 WHEN 'u' THEN 1 WHEN 'c' THEN 2 ELSE 3 END, con.conname`
+
+// showCreateTable rebuilds the CREATE TABLE statement of the table from
+// catalog, PostgreSQL has no built-in SHOW CREATE TABLE. The indexes which
+// are not created by constraints are appended as CREATE INDEX statements.
+func (d *PostgreSQLDriverImpl) showCreateTable(ctx context.Context, table tableName) (string, error) {
+	columns, err := d.query(ctx, queryTableColumns, table.schema, table.name)
+	if err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf(TableNotExistMessage, table)
+	}
+	constraints, err := d.query(ctx, queryTableConstraints, table.schema, table.name)
+	if err != nil {
+		return "", err
+	}
+	indexes, err := d.query(ctx, queryTableIndexes, table.schema, table.name)
+	if err != nil {
+		return "", err
+	}
+
+	defs := make([]string, 0, len(columns)+len(constraints))
+	for _, col := range columns {
+		def := fmt.Sprintf("%s %s", quoteIdent(col["column_name"].String), col["column_type"].String)
+		if col["column_default"].Valid {
+			def += " DEFAULT " + col["column_default"].String
+		}
+		if col["is_nullable"].String == "NO" {
+			def += " NOT NULL"
+		}
+		defs = append(defs, def)
+	}
+	for _, con := range constraints {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s %s", quoteIdent(con["name"].String), con["definition"].String))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s (\n    %s\n);", table.quoted(), strings.Join(defs, ",\n    "))
+	for _, index := range indexes {
+		if index["is_constraint"].String == "true" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s;", index["index_def"].String)
+	}
+	for _, col := range columns {
+		if col["column_comment"].Valid {
+			fmt.Fprintf(&b, "\nCOMMENT ON COLUMN %s.%s IS %s;", table.quoted(),
+				quoteIdent(col["column_name"].String), quoteLiteral(col["column_comment"].String))
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,165 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+const (
+	SchemaNotExistMessage = "schema %s 不存在"
+	SchemaExistMessage    = "schema %s 已存在"
+	TableNotExistMessage  = "表 %s 不存在"
+	TableExistMessage     = "表 %s 已存在"
+)
+
+func (d *PostgreSQLDriverImpl) Audit(ctx context.Context, sqls []string) ([]*driverV2.AuditResults, error) {
+	for _, sql := range sqls {
+		if sql == "" {
+			return nil, errors.New("has empty sql")
+		}
+	}
+	results := make([]*driverV2.AuditResults, 0, len(sqls))
+	for _, sql := range sqls {
+		result, err := d.audit(ctx, sql)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (d *PostgreSQLDriverImpl) audit(ctx context.Context, sql string) (*driverV2.AuditResults, error) {
+	d.result = driverV2.NewAuditResults()
+
+	s, err := parseOneStatement(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.IsOfflineAudit() {
+		if err := d.checkInvalid(s); err != nil {
+			return nil, err
+		}
+		if d.result.HasResult() {
+			d.hasInvalidSql = true
+			d.Logger().Warnf("SQL %s invalid, %s", s.text, d.result.Message())
+		}
+	}
+
+	for _, rule := range d.rules {
+		handler, ok := RuleHandlerMap[rule.Name]
+		if !ok || handler.Func == nil {
+			continue
+		}
+		if d.IsOfflineAudit() && !handler.AllowOffline {
+			continue
+		}
+		input := &RuleHandlerInput{
+			Ctx:    d.Ctx,
+			Rule:   *rule,
+			Res:    d.result,
+			Stmt:   s,
+			Driver: d,
+		}
+		if err := handler.Func(input); err != nil {
+			return nil, err
+		}
+	}
+
+	d.Ctx.UpdateContext(s)
+	return d.result, nil
+}
+
+// checkInvalid checks whether the objects which the SQL works on exist.
+func (d *PostgreSQLDriverImpl) checkInvalid(s *stmt) error {
+	var notExist, exist []string
+	switch s.kind {
+	case stmtCreateTable:
+		ct := s.parseCreateTable()
+		if ct == nil {
+			return nil
+		}
+		if ct.table.schema != "" {
+			schemaExist, err := d.Ctx.IsSchemaExist(ct.table.schema)
+			if err != nil {
+				return err
+			}
+			if !schemaExist {
+				d.result.Add(driverV2.RuleLevelError, "", SchemaNotExistMessage, ct.table.schema)
+				return nil
+			}
+		}
+		tableExist, err := d.Ctx.IsTableExist(ct.table)
+		if err != nil {
+			return err
+		}
+		if tableExist && !ct.ifNotExists {
+			exist = append(exist, ct.table.String())
+		}
+		// tables in LIKE or AS SELECT should exist.
+		for _, t := range s.tables()[1:] {
+			if err := d.appendIfTableNotExist(t, &notExist); err != nil {
+				return err
+			}
+		}
+	case stmtCreateSchema:
+		name := s.at(s.objectNameIndex()).ident()
+		schemaExist, err := d.Ctx.IsSchemaExist(name)
+		if err != nil {
+			return err
+		}
+		if schemaExist && !s.ifNotExists() {
+			d.result.Add(driverV2.RuleLevelError, "", SchemaExistMessage, name)
+		}
+		return nil
+	case stmtDropTable, stmtTruncate, stmtAlterTable:
+		// DROP TABLE IF EXISTS / ALTER TABLE IF EXISTS
+		if s.skip(2, "if", "exists") != 2 {
+			return nil
+		}
+		for _, t := range s.tables() {
+			if err := d.appendIfTableNotExist(t, &notExist); err != nil {
+				return err
+			}
+		}
+	case stmtCreateIndex:
+		for _, t := range s.tables() {
+			if err := d.appendIfTableNotExist(t, &notExist); err != nil {
+				return err
+			}
+		}
+	case stmtInsert, stmtUpdate, stmtDelete, stmtSelect, stmtMerge:
+		for _, t := range s.tables() {
+			if err := d.appendIfTableNotExist(t, &notExist); err != nil {
+				return err
+			}
+		}
+	}
+	if len(exist) > 0 {
+		d.result.Add(driverV2.RuleLevelError, "", TableExistMessage, strings.Join(exist, ", "))
+	}
+	if len(notExist) > 0 {
+		d.result.Add(driverV2.RuleLevelError, "", TableNotExistMessage, strings.Join(notExist, ", "))
+	}
+	return nil
+}
+
+func (d *PostgreSQLDriverImpl) appendIfTableNotExist(t tableName, notExist *[]string) error {
+	// pg_catalog and information_schema are searched implicitly, the relations in them are not checked.
+	if t.schema == "pg_catalog" || t.schema == "information_schema" || strings.HasPrefix(t.name, "pg_") {
+		return nil
+	}
+	exist, err := d.Ctx.IsTableExist(t)
+	if err != nil {
+		return fmt.Errorf("check table %s exist failed: %v", t, err)
+	}
+	if !exist {
+		*notExist = append(*notExist, t.String())
+	}
+	return nil
+}
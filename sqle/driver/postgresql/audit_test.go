@@ -0,0 +1,291 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runSingleRuleInspectCase(t *testing.T, ruleName string, cases map[string]bool, sqls ...string) {
+	rule := RuleHandlerMap[ruleName].Rule
+	for sql, triggered := range cases {
+		d := newOfflineDriver(t, &rule)
+		// the SQLs before the audited SQL, such as CREATE TABLE.
+		if len(sqls) > 0 {
+			_, err := d.Audit(context.TODO(), sqls)
+			assert.NoError(t, err)
+		}
+		results, err := d.Audit(context.TODO(), []string{sql})
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		if triggered {
+			assert.Len(t, results[0].Results, 1, sql)
+			if len(results[0].Results) == 1 {
+				assert.Equal(t, ruleName, results[0].Results[0].RuleName, sql)
+			}
+		} else {
+			assert.Empty(t, results[0].Results, sql)
+		}
+	}
+}
+
+func TestRuleHandlers(t *testing.T) {
+	for _, rh := range RuleHandlers {
+		assert.NotEmpty(t, rh.Rule.Name)
+		assert.NotEmpty(t, rh.Rule.Desc, rh.Rule.Name)
+		assert.NotEmpty(t, rh.Rule.Category, rh.Rule.Name)
+		if rh.Func != nil {
+			assert.NotEmpty(t, rh.Message, rh.Rule.Name)
+		}
+	}
+	assert.Len(t, RuleHandlerMap, len(RuleHandlers))
+}
+
+func TestCheckIfNotExist(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLCheckPKWithoutIfNotExists, map[string]bool{
+		"CREATE TABLE t (id int PRIMARY KEY)":               true,
+		"CREATE TABLE IF NOT EXISTS t (id int PRIMARY KEY)": false,
+		"CREATE INDEX idx_a ON t (a)":                       false,
+	})
+}
+
+func TestCheckObjectName(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLCheckObjectNameLength, map[string]bool{
+		"CREATE TABLE t (id int PRIMARY KEY)":                                                      false,
+		"CREATE TABLE t (aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa int)":    true,
+		"ALTER TABLE t RENAME TO aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": true,
+	})
+	runSingleRuleInspectCase(t, DDLCheckObjectNameUsingKeyword, map[string]bool{
+		`CREATE TABLE "order" (id int)`:     true,
+		`CREATE TABLE t ("user" int)`:       true,
+		"CREATE TABLE orders (id int)":      false,
+		`CREATE INDEX "select" ON t (a)`:    true,
+		"ALTER TABLE t ADD COLUMN name int": false,
+	})
+}
+
+func TestCheckPrimaryKey(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLCheckPKNotExist, map[string]bool{
+		"CREATE TABLE t (id int PRIMARY KEY)":                     false,
+		"CREATE TABLE t (id int, CONSTRAINT pk PRIMARY KEY (id))": false,
+		"CREATE TABLE t (id int)":                                 true,
+		"CREATE TABLE t AS SELECT * FROM t1":                      false,
+		"CREATE TABLE t PARTITION OF p FOR VALUES IN (1)":         false,
+		"CREATE TABLE t (id int, name text, UNIQUE (id))":         true,
+	})
+}
+
+func TestCheckForeignKey(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLDisableFK, map[string]bool{
+		"CREATE TABLE t (id int PRIMARY KEY, r int REFERENCES r (id))":                  true,
+		"CREATE TABLE t (id int PRIMARY KEY, r int, FOREIGN KEY (r) REFERENCES r (id))": true,
+		"ALTER TABLE t ADD CONSTRAINT fk FOREIGN KEY (r) REFERENCES r (id)":             true,
+		"ALTER TABLE t ADD COLUMN r int REFERENCES r (id)":                              true,
+		"CREATE TABLE t (id int PRIMARY KEY)":                                           false,
+	})
+}
+
+func TestDisableDropStmt(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLDisableDropStatement, map[string]bool{
+		"DROP TABLE t":          true,
+		"DROP SCHEMA s":         true,
+		"DROP DATABASE db":      true,
+		"DROP INDEX idx_a":      false,
+		"DROP VIEW IF EXISTS v": true,
+	})
+}
+
+func TestCheckIndexPrefix(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLCheckIndexPrefix, map[string]bool{
+		"CREATE INDEX idx_a ON t (a)":        false,
+		"CREATE INDEX a_idx ON t (a)":        true,
+		"CREATE UNIQUE INDEX a_idx ON t (a)": false,
+		"CREATE INDEX ON t (a)":              false,
+	})
+	runSingleRuleInspectCase(t, DDLCheckUniqueIndexPrefix, map[string]bool{
+		"CREATE UNIQUE INDEX uniq_a ON t (a)": false,
+		"CREATE UNIQUE INDEX idx_a ON t (a)":  true,
+		"CREATE INDEX a ON t (a)":             false,
+	})
+}
+
+func TestCheckIndexWithoutConcurrently(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLCheckIndexWithoutConcurrently, map[string]bool{
+		"CREATE INDEX idx_a ON t (a)":              true,
+		"CREATE INDEX CONCURRENTLY idx_a ON t (a)": false,
+		"DROP INDEX idx_a":                         true,
+		"DROP INDEX CONCURRENTLY idx_a":            false,
+		"CREATE INDEX idx_a ON new_t (a)":          false,
+	}, "CREATE TABLE new_t (id int PRIMARY KEY, a int)")
+}
+
+func TestCheckFieldNotNullMustContainDefaultValue(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLCheckFieldNotNUllMustContainDefaultValue, map[string]bool{
+		"CREATE TABLE t (id serial PRIMARY KEY, a int NOT NULL)":           true,
+		"CREATE TABLE t (id bigserial NOT NULL, a int NOT NULL DEFAULT 0)": false,
+		"ALTER TABLE t ADD COLUMN a text NOT NULL":                         true,
+		"ALTER TABLE t ADD COLUMN a text NOT NULL DEFAULT ''":              false,
+	})
+}
+
+func TestCheckColumnType(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLRecommendIdentityColumn, map[string]bool{
+		"CREATE TABLE t (id serial PRIMARY KEY)":                              true,
+		"CREATE TABLE t (id bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY)": false,
+	})
+	runSingleRuleInspectCase(t, DDLCheckFixedLengthChar, map[string]bool{
+		"CREATE TABLE t (a char(10))":              true,
+		"CREATE TABLE t (a character(10))":         true,
+		"CREATE TABLE t (a character varying(10))": false,
+		"ALTER TABLE t ADD COLUMN a varchar(10)":   false,
+	})
+	runSingleRuleInspectCase(t, DDLCheckTimestampWithoutTimeZone, map[string]bool{
+		"CREATE TABLE t (a timestamp)":                      true,
+		"CREATE TABLE t (a timestamp(3) without time zone)": true,
+		"CREATE TABLE t (a timestamp with time zone)":       false,
+		"CREATE TABLE t (a timestamptz)":                    false,
+	})
+}
+
+func TestCheckAlterTableLock(t *testing.T) {
+	runSingleRuleInspectCase(t, DDLCheckAlterColumnType, map[string]bool{
+		"ALTER TABLE t ALTER COLUMN a TYPE bigint":          true,
+		"ALTER TABLE t ALTER COLUMN a SET DATA TYPE bigint": true,
+		"ALTER TABLE new_t ALTER COLUMN a TYPE bigint":      false,
+		"ALTER TABLE t ALTER COLUMN a SET DEFAULT 1":        false,
+	}, "CREATE TABLE new_t (id int PRIMARY KEY, a int)")
+	runSingleRuleInspectCase(t, DDLCheckSetNotNull, map[string]bool{
+		"ALTER TABLE t ALTER COLUMN a SET NOT NULL":  true,
+		"ALTER TABLE t ALTER COLUMN a DROP NOT NULL": false,
+	})
+	runSingleRuleInspectCase(t, DDLCheckAddConstraintWithoutNotValid, map[string]bool{
+		"ALTER TABLE t ADD CONSTRAINT fk FOREIGN KEY (a) REFERENCES r (id)":           true,
+		"ALTER TABLE t ADD CONSTRAINT fk FOREIGN KEY (a) REFERENCES r (id) NOT VALID": false,
+		"ALTER TABLE t ADD CONSTRAINT ck CHECK (a > 0)":                               true,
+		"ALTER TABLE t ADD CONSTRAINT ck CHECK (a > 0) NOT VALID":                     false,
+		"ALTER TABLE t ADD CONSTRAINT uk UNIQUE (a)":                                  false,
+	})
+}
+
+func TestCheckWhereIsInvalid(t *testing.T) {
+	runSingleRuleInspectCase(t, DMLCheckWhereIsInvalid, map[string]bool{
+		"SELECT * FROM t":                      true,
+		"SELECT * FROM t WHERE 1 = 1":          true,
+		"SELECT * FROM t WHERE true":           true,
+		"SELECT * FROM t WHERE id = 1":         false,
+		"SELECT 1":                             false,
+		"UPDATE t SET a = 1":                   true,
+		"UPDATE t SET a = 1 WHERE id = 1":      false,
+		"DELETE FROM t":                        true,
+		"DELETE FROM t WHERE 'a' = 'a'":        true,
+		"DELETE FROM t WHERE CURRENT OF c":     false,
+		"SELECT * FROM t WHERE id = 1 LIMIT 1": false,
+	})
+}
+
+func TestCheckSelectAll(t *testing.T) {
+	runSingleRuleInspectCase(t, DMLDisableSelectAllColumn, map[string]bool{
+		"SELECT * FROM t":                true,
+		"SELECT t.* FROM t":              true,
+		"SELECT a, * FROM t":             true,
+		"SELECT count(*) FROM t":         false,
+		"SELECT a * 2 FROM t":            false,
+		"SELECT DISTINCT * FROM t":       true,
+		"INSERT INTO t SELECT * FROM t1": false,
+	})
+}
+
+func TestCheckSelectLimit(t *testing.T) {
+	runSingleRuleInspectCase(t, DMLCheckSelectLimit, map[string]bool{
+		"SELECT a FROM t":                          true,
+		"SELECT a FROM t LIMIT 10":                 false,
+		"SELECT a FROM t LIMIT 10000":              true,
+		"SELECT a FROM t LIMIT ALL":                true,
+		"SELECT a FROM t FETCH FIRST 10 ROWS ONLY": false,
+		"SELECT a FROM t FETCH FIRST ROW ONLY":     false,
+		"SELECT now()":                             false,
+	})
+}
+
+func TestCheckFuzzySearch(t *testing.T) {
+	runSingleRuleInspectCase(t, DMLCheckFuzzySearch, map[string]bool{
+		"SELECT a FROM t WHERE a LIKE '%a'":   true,
+		"SELECT a FROM t WHERE a ILIKE '%a%'": true,
+		"SELECT a FROM t WHERE a LIKE 'a%'":   false,
+		"SELECT a FROM t WHERE a ~~ '_a'":     true,
+	})
+}
+
+func TestCheckInsert(t *testing.T) {
+	runSingleRuleInspectCase(t, DMLCheckInsertColumnsExist, map[string]bool{
+		"INSERT INTO t VALUES (1, 2)":        true,
+		"INSERT INTO t (a, b) VALUES (1, 2)": false,
+		"INSERT INTO t AS x (a) VALUES (1)":  false,
+		"INSERT INTO t SELECT * FROM t1":     true,
+		"INSERT INTO t (SELECT * FROM t1)":   true,
+	})
+	rule := RuleHandlerMap[DMLCheckBatchInsertListsMax].Rule
+	rule.Params = rule.Params.Copy()
+	assert.NoError(t, rule.Params.SetParamValue(DefaultSingleParamKeyName, "2"))
+	d := newOfflineDriver(t, &rule)
+	results, err := d.Audit(context.TODO(), []string{
+		"INSERT INTO t (a) VALUES (1), (2)",
+		"INSERT INTO t (a) VALUES (1), (2), (3)",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, results[0].Results)
+	assert.Len(t, results[1].Results, 1)
+}
+
+func TestCheckInQueryLimit(t *testing.T) {
+	rule := RuleHandlerMap[DMLCheckInQueryNumber].Rule
+	rule.Params = rule.Params.Copy()
+	assert.NoError(t, rule.Params.SetParamValue(DefaultSingleParamKeyName, "2"))
+	d := newOfflineDriver(t, &rule)
+	results, err := d.Audit(context.TODO(), []string{
+		"SELECT a FROM t WHERE a IN (1, 2)",
+		"SELECT a FROM t WHERE a IN (1, 2, 3)",
+		"SELECT a FROM t WHERE a IN (SELECT a FROM t1)",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, results[0].Results)
+	assert.Len(t, results[1].Results, 1)
+	assert.Equal(t, "WHERE条件内IN语句中的参数已有3个，不建议超过阙值2", results[1].Results[0].Message)
+	assert.Empty(t, results[2].Results)
+}
+
+func TestOtherDMLRules(t *testing.T) {
+	runSingleRuleInspectCase(t, DMLNotRecommendOrderByRandom, map[string]bool{
+		"SELECT a FROM t ORDER BY random() LIMIT 1": true,
+		"SELECT random() FROM t ORDER BY a":         false,
+	})
+	runSingleRuleInspectCase(t, DMLCheckSelectForUpdate, map[string]bool{
+		"SELECT a FROM t WHERE id = 1 FOR UPDATE": true,
+		"SELECT a FROM t WHERE id = 1 FOR SHARE":  false,
+		"SELECT a FROM t WHERE id = 1":            false,
+	})
+}
+
+func TestOfflineAuditSkipsOnlineRules(t *testing.T) {
+	rule := RuleHandlerMap[DMLCheckAffectedRows].Rule
+	d := newOfflineDriver(t, &rule)
+	results, err := d.Audit(context.TODO(), []string{"DELETE FROM t"})
+	assert.NoError(t, err)
+	assert.Empty(t, results[0].Results)
+
+	rollback, reason, err := d.GenRollbackSQL(context.TODO(), "DELETE FROM t")
+	assert.NoError(t, err)
+	assert.Empty(t, rollback)
+	assert.Empty(t, reason)
+}
+
+func TestAuditEmptySQL(t *testing.T) {
+	d := newOfflineDriver(t)
+	_, err := d.Audit(context.TODO(), []string{""})
+	assert.Error(t, err)
+	_, err = d.Audit(context.TODO(), []string{"SELECT 1; SELECT 2"})
+	assert.Equal(t, driverV2.ErrNodesCountExceedOne, err)
+}
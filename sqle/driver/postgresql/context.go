@@ -0,0 +1,147 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+)
+
+const defaultSchema = "public"
+
+// Context keeps the state changed by the audited SQLs in one session, so that
+// a SQL can be audited with the tables created or dropped by the SQLs before it.
+type Context struct {
+	// driver is nil when audit without instance.
+	driver *PostgreSQLDriverImpl
+
+	currentSchema string
+	// tables records the tables created (true) or dropped (false) by the audited SQLs.
+	tables map[tableName]bool
+	// schemas records the schemas created (true) or dropped (false) by the audited SQLs.
+	schemas map[string]bool
+}
+
+func NewContext(driver *PostgreSQLDriverImpl) *Context {
+	return &Context{
+		driver:  driver,
+		tables:  map[tableName]bool{},
+		schemas: map[string]bool{},
+	}
+}
+
+// CurrentSchema returns the first schema in search_path.
+func (c *Context) CurrentSchema() string {
+	if c.currentSchema != "" {
+		return c.currentSchema
+	}
+	c.currentSchema = defaultSchema
+	if c.driver != nil {
+		records, err := c.driver.query(context.TODO(), "SELECT current_schema() AS schema")
+		if err != nil {
+			c.driver.Logger().Warnf("get current schema failed, use %s, error: %v", defaultSchema, err)
+		} else if len(records) == 1 && records[0]["schema"].Valid {
+			c.currentSchema = records[0]["schema"].String
+		}
+	}
+	return c.currentSchema
+}
+
+// completeTableName fills the schema of table with current schema if it is empty.
+func (c *Context) completeTableName(t tableName) tableName {
+	if t.schema == "" {
+		t.schema = c.CurrentSchema()
+	}
+	return t
+}
+
+// IsTableExist checks whether table exists, it checks the audited SQLs at first
+// and then the instance. It always returns false when audit without instance.
+func (c *Context) IsTableExist(t tableName) (bool, error) {
+	t = c.completeTableName(t)
+	if exist, ok := c.tables[t]; ok {
+		return exist, nil
+	}
+	if exist, ok := c.schemas[t.schema]; ok && !exist {
+		return false, nil
+	}
+	if c.driver == nil {
+		return false, nil
+	}
+	records, err := c.driver.query(context.TODO(),
+		`SELECT 1 FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('r', 'p', 'v', 'm', 'f')`, t.schema, t.name)
+	if err != nil {
+		return false, err
+	}
+	return len(records) > 0, nil
+}
+
+// IsTableCreatedInContext reports whether table is created by the audited SQLs.
+func (c *Context) IsTableCreatedInContext(t tableName) bool {
+	return c.tables[c.completeTableName(t)]
+}
+
+func (c *Context) IsSchemaExist(schema string) (bool, error) {
+	if exist, ok := c.schemas[schema]; ok {
+		return exist, nil
+	}
+	if c.driver == nil {
+		return false, nil
+	}
+	records, err := c.driver.query(context.TODO(),
+		"SELECT 1 FROM pg_catalog.pg_namespace WHERE nspname = $1", schema)
+	if err != nil {
+		return false, err
+	}
+	return len(records) > 0, nil
+}
+
+// UpdateContext records the changes of s.
+func (c *Context) UpdateContext(s *stmt) {
+	switch s.kind {
+	case stmtCreateTable:
+		if ct := s.parseCreateTable(); ct != nil {
+			c.tables[c.completeTableName(ct.table)] = true
+		}
+	case stmtDropTable:
+		for _, t := range s.tables() {
+			c.tables[c.completeTableName(t)] = false
+		}
+	case stmtAlterTable:
+		at := s.parseAlterTable()
+		if at == nil {
+			return
+		}
+		for _, cmd := range at.cmds {
+			if cmd.action == alterTableRename {
+				table := c.completeTableName(at.table)
+				c.tables[table] = false
+				c.tables[tableName{schema: table.schema, name: cmd.newName}] = true
+			}
+		}
+	case stmtCreateSchema:
+		i := s.objectNameIndex()
+		if s.at(i).isName() && !s.at(i).is("authorization") {
+			c.schemas[s.at(i).ident()] = true
+		}
+	case stmtDropSchema:
+		for _, name := range s.tables() {
+			c.schemas[name.name] = false
+		}
+	case stmtSet:
+		// SET search_path TO a, b / SET search_path = a
+		if !s.at(1).is("search_path") && !(s.at(1).is("session", "local") && s.at(2).is("search_path")) {
+			return
+		}
+		for i := 2; i < len(s.tokens); i++ {
+			t := s.at(i)
+			if t.isName() && !t.is("to", "search_path") {
+				c.currentSchema = strings.TrimSpace(t.ident())
+				return
+			}
+			if t.kind == tokenString {
+				c.currentSchema = strings.Trim(t.text, "'")
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,434 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type columnDef struct {
+	name string
+	// typeName is the normalized type, such as "varchar(20)" or "timestamp with time zone".
+	typeName string
+	// baseType is the first word of the type in lower case, such as "varchar" or "timestamp".
+	baseType     string
+	notNull      bool
+	primaryKey   bool
+	unique       bool
+	references   bool
+	identity     bool
+	hasDefault   bool
+	defaultValue []token
+}
+
+type createTableStmt struct {
+	table       tableName
+	ifNotExists bool
+	columns     []*columnDef
+	// primaryKey is the columns of the primary key, defined either in column or table constraint.
+	primaryKey      []string
+	hasForeignKey   bool
+	constraintNames []string
+	// isDerived is true when the table is created by AS SELECT, PARTITION OF, OF type or LIKE,
+	// its columns are not fully listed in the statement.
+	isDerived bool
+}
+
+// elements splits the top-level comma separated list in the parenthesis starting at i.
+func (s *stmt) elements(open int) [][]token {
+	end := s.matchingParen(open)
+	var elems [][]token
+	start := open + 1
+	depth := 0
+	for i := open + 1; i <= end; i++ {
+		t := s.at(i)
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")") && i != end:
+			depth--
+		case (t.isPunct(",") && depth == 0) || i == end:
+			if i > start {
+				elems = append(elems, s.tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	return elems
+}
+
+func (s *stmt) parseCreateTable() *createTableStmt {
+	if s.kind != stmtCreateTable {
+		return nil
+	}
+	ct := &createTableStmt{ifNotExists: s.ifNotExists()}
+	i := s.objectNameIndex()
+	name, next, ok := s.parseQualifiedName(i)
+	if !ok {
+		return nil
+	}
+	ct.table = name
+	if s.at(next).is("of", "partition", "as") || !s.at(next).isPunct("(") {
+		ct.isDerived = true
+		return ct
+	}
+	for _, elem := range s.elements(next) {
+		e := &stmt{tokens: elem}
+		switch {
+		case e.at(0).is("constraint", "primary", "unique", "check", "foreign", "exclude", "like"):
+			ct.parseTableConstraint(e)
+		default:
+			col := parseColumnDef(e, 0)
+			if col.primaryKey {
+				ct.primaryKey = append(ct.primaryKey, col.name)
+			}
+			if col.references {
+				ct.hasForeignKey = true
+			}
+			ct.columns = append(ct.columns, col)
+		}
+	}
+	return ct
+}
+
+func (ct *createTableStmt) parseTableConstraint(e *stmt) {
+	i := 0
+	if e.at(0).is("constraint") {
+		ct.constraintNames = append(ct.constraintNames, e.at(1).ident())
+		i = 2
+	}
+	switch {
+	case e.at(i).is("like"):
+		ct.isDerived = true
+	case e.at(i).is("primary"):
+		if open := e.topLevelIndex(i, "key") + 1; open > 0 && e.at(open).isPunct("(") {
+			ct.primaryKey = append(ct.primaryKey, e.identList(open)...)
+		}
+	case e.at(i).is("foreign"):
+		ct.hasForeignKey = true
+	}
+}
+
+// identList returns identifiers in the parenthesis starting at open.
+func (s *stmt) identList(open int) []string {
+	var names []string
+	for _, elem := range s.elements(open) {
+		if len(elem) > 0 && elem[0].isName() {
+			names = append(names, elem[0].ident())
+		}
+	}
+	return names
+}
+
+var columnConstraintKeywords = map[string]struct{}{
+	"constraint": {}, "not": {}, "null": {}, "default": {}, "primary": {}, "unique": {}, "check": {},
+	"references": {}, "generated": {}, "collate": {}, "deferrable": {}, "initially": {}, "compression": {},
+	"storage": {},
+}
+
+// parseColumnDef parses "name type [constraints]" starting at i.
+func parseColumnDef(e *stmt, i int) *columnDef {
+	col := &columnDef{name: e.at(i).ident()}
+	i++
+
+	typeStart := i
+	depth := 0
+	for ; i < len(e.tokens); i++ {
+		t := e.at(i)
+		if t.isPunct("(") {
+			depth++
+		} else if t.isPunct(")") {
+			depth--
+		}
+		if _, ok := columnConstraintKeywords[t.ident()]; ok && depth == 0 && t.kind == tokenIdent {
+			break
+		}
+	}
+	col.typeName = tokensText(e.tokens[typeStart:i])
+	if typeStart < i {
+		col.baseType = e.at(typeStart).ident()
+	}
+
+	for ; i < len(e.tokens); i++ {
+		t := e.at(i)
+		switch {
+		case t.is("not") && e.at(i+1).is("null"):
+			col.notNull = true
+			i++
+		case t.is("primary"):
+			col.primaryKey = true
+			col.notNull = true
+		case t.is("unique"):
+			col.unique = true
+		case t.is("references"):
+			col.references = true
+		case t.is("identity"):
+			col.identity = true
+		case t.is("default"):
+			col.hasDefault = true
+			start := i + 1
+			depth := 0
+			for i = start; i < len(e.tokens); i++ {
+				t := e.at(i)
+				if t.isPunct("(") {
+					depth++
+				} else if t.isPunct(")") {
+					depth--
+				}
+				if _, ok := columnConstraintKeywords[t.ident()]; ok && depth == 0 && t.kind == tokenIdent && !t.is("null") {
+					break
+				}
+			}
+			col.defaultValue = e.tokens[start:i]
+			i--
+		}
+	}
+	return col
+}
+
+// tokensText joins the tokens as normalized text, keywords are in lower case.
+func tokensText(tokens []token) string {
+	var b strings.Builder
+	for i, t := range tokens {
+		text := t.text
+		if t.kind == tokenIdent {
+			text = strings.ToLower(text)
+		}
+		if i > 0 && needSpace(tokens[i-1].text, text) && !(t.isPunct("(") && tokens[i-1].kind == tokenIdent) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+func (c *columnDef) isTimestampWithoutTimeZone() bool {
+	return c.baseType == "timestamp" && !strings.Contains(c.typeName, "with time zone")
+}
+
+func (c *columnDef) isSerial() bool {
+	switch c.baseType {
+	case "serial", "serial2", "serial4", "serial8", "smallserial", "bigserial":
+		return true
+	}
+	return false
+}
+
+func (c *columnDef) isFixedLengthChar() bool {
+	switch c.baseType {
+	case "char", "bpchar":
+		return true
+	case "character":
+		return !strings.HasPrefix(c.typeName, "character varying")
+	}
+	return false
+}
+
+const (
+	alterTableAddColumn      = "add_column"
+	alterTableDropColumn     = "drop_column"
+	alterTableAlterType      = "alter_column_type"
+	alterTableSetNotNull     = "set_not_null"
+	alterTableDropNotNull    = "drop_not_null"
+	alterTableSetDefault     = "set_default"
+	alterTableDropDefault    = "drop_default"
+	alterTableAddConstraint  = "add_constraint"
+	alterTableDropConstraint = "drop_constraint"
+	alterTableRenameColumn   = "rename_column"
+	alterTableRename         = "rename_table"
+	alterTableOther          = "other"
+)
+
+type alterTableCmd struct {
+	action string
+	// column is the new column of ADD COLUMN.
+	column *columnDef
+	// columnName is the column which the command works on.
+	columnName string
+	// newName is the new name of RENAME.
+	newName string
+	// constraintName is the name of ADD/DROP CONSTRAINT, it may be empty.
+	constraintName string
+	// tokens is the tokens of the command after the column name or constraint name.
+	tokens     []token
+	notValid   bool
+	foreignKey bool
+	primaryKey bool
+	ifExists   bool
+}
+
+type alterTableStmt struct {
+	table tableName
+	cmds  []*alterTableCmd
+}
+
+func (s *stmt) parseAlterTable() *alterTableStmt {
+	if s.kind != stmtAlterTable {
+		return nil
+	}
+	at, err := s.parseAlterTableCmds()
+	if err != nil {
+		return nil
+	}
+	return at
+}
+
+// parseAlterTableCmds returns the syntax error if a command of ALTER TABLE is truncated,
+// e.g. "ALTER TABLE t ADD", the commands can not be parsed further.
+func (s *stmt) parseAlterTableCmds() (*alterTableStmt, error) {
+	name, next, ok := s.parseQualifiedName(s.objectNameIndex())
+	if !ok {
+		return nil, nil
+	}
+	at := &alterTableStmt{table: name}
+	if s.at(next).isOperator("*") {
+		next++
+	}
+
+	start := next
+	depth := 0
+	for i := next; i <= len(s.tokens); i++ {
+		t := s.at(i)
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+		case (t.isPunct(",") && depth == 0) || i == len(s.tokens):
+			if i > start {
+				cmd, err := parseAlterTableCmd(&stmt{tokens: s.tokens[start:i]})
+				if err != nil {
+					return nil, err
+				}
+				at.cmds = append(at.cmds, cmd)
+			}
+			start = i + 1
+		}
+	}
+	return at, nil
+}
+
+func parseAlterTableCmd(c *stmt) (*alterTableCmd, error) {
+	cmd := &alterTableCmd{action: alterTableOther, tokens: c.tokens}
+	switch {
+	case c.at(0).is("add"):
+		i := c.skip(1, "column")
+		if c.at(i).is("constraint", "primary", "unique", "check", "foreign", "exclude") {
+			cmd.action = alterTableAddConstraint
+			if c.at(i).is("constraint") {
+				cmd.constraintName = c.at(i + 1).ident()
+				i += 2
+			}
+			cmd.primaryKey = c.at(i).is("primary")
+			cmd.foreignKey = c.at(i).is("foreign")
+			if valid := c.topLevelIndex(i, "valid"); valid > 0 && c.at(valid-1).is("not") {
+				cmd.notValid = true
+			}
+			cmd.tokens = c.tokens[i:]
+			return cmd, nil
+		}
+		i, _ = c.skipIfExists(i)
+		if i >= len(c.tokens) {
+			return nil, missingAlterTableName(c)
+		}
+		cmd.action = alterTableAddColumn
+		cmd.column = parseColumnDef(c, i)
+		cmd.columnName = cmd.column.name
+		cmd.foreignKey = cmd.column.references
+		cmd.primaryKey = cmd.column.primaryKey
+	case c.at(0).is("drop"):
+		i := 1
+		if c.at(i).is("constraint") {
+			cmd.action = alterTableDropConstraint
+			i, cmd.ifExists = c.skipIfExists(i + 1)
+			cmd.constraintName = c.at(i).ident()
+			return cmd, nil
+		}
+		i = c.skip(i, "column")
+		i, cmd.ifExists = c.skipIfExists(i)
+		if i >= len(c.tokens) {
+			return nil, missingAlterTableName(c)
+		}
+		cmd.action = alterTableDropColumn
+		cmd.columnName = c.at(i).ident()
+	case c.at(0).is("alter"):
+		i := c.skip(1, "column")
+		if i >= len(c.tokens) {
+			return nil, missingAlterTableName(c)
+		}
+		cmd.columnName = c.at(i).ident()
+		i++
+		cmd.tokens = c.tokens[i:]
+		switch {
+		case c.at(i).is("type"), c.skip(i, "set", "data", "type") != i:
+			cmd.action = alterTableAlterType
+		case c.skip(i, "set", "not", "null") != i:
+			cmd.action = alterTableSetNotNull
+		case c.skip(i, "drop", "not", "null") != i:
+			cmd.action = alterTableDropNotNull
+		case c.skip(i, "set", "default") != i:
+			cmd.action = alterTableSetDefault
+		case c.skip(i, "drop", "default") != i:
+			cmd.action = alterTableDropDefault
+		}
+	case c.at(0).is("rename"):
+		if c.at(1).is("to") {
+			cmd.action = alterTableRename
+			cmd.newName = c.at(2).ident()
+			return cmd, nil
+		}
+		if c.at(1).is("constraint") {
+			return cmd, nil
+		}
+		i := c.skip(1, "column")
+		cmd.action = alterTableRenameColumn
+		cmd.columnName = c.at(i).ident()
+		cmd.newName = c.at(i + 2).ident()
+	}
+	return cmd, nil
+}
+
+func missingAlterTableName(c *stmt) error {
+	last := c.tokens[len(c.tokens)-1]
+	return fmt.Errorf("syntax error at line %d: missing column after %q", last.line, last.text)
+}
+
+type createIndexStmt struct {
+	name         string
+	table        tableName
+	unique       bool
+	concurrently bool
+	columns      []string
+}
+
+func (s *stmt) parseCreateIndex() *createIndexStmt {
+	if s.kind != stmtCreateIndex {
+		return nil
+	}
+	ci := &createIndexStmt{
+		unique:       s.at(1).is("unique"),
+		concurrently: s.hasKeyword("concurrently"),
+	}
+	on := s.topLevelIndex(0, "on")
+	if on < 0 {
+		return nil
+	}
+	if name := s.objectNameIndex(); name < on {
+		ci.name = s.at(name).ident()
+	}
+	table, next, ok := s.parseQualifiedName(s.skip(on+1, "only"))
+	if !ok {
+		return nil
+	}
+	ci.table = table
+	if s.at(next).is("using") {
+		next += 2
+	}
+	if s.at(next).isPunct("(") {
+		for _, elem := range s.elements(next) {
+			if len(elem) > 0 && elem[0].isName() {
+				ci.columns = append(ci.columns, elem[0].ident())
+			}
+		}
+	}
+	return ci
+}
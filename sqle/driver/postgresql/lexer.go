@@ -0,0 +1,325 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	// tokenIdent is a keyword or an unquoted identifier, PostgreSQL does not distinguish them lexically.
+	tokenIdent tokenKind = iota
+	// tokenQuotedIdent is a double-quoted identifier, such as "UserName".
+	tokenQuotedIdent
+	// tokenString is a string constant, such as 'abc', E'a\nb', B'0101' or $tag$abc$tag$.
+	tokenString
+	tokenNumber
+	// tokenParam is a positional parameter, such as $1.
+	tokenParam
+	tokenOperator
+	// tokenPunct is one of ( ) [ ] , ; . :
+	tokenPunct
+	tokenComment
+	tokenSpace
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// offset is the byte offset of the token in the source SQL.
+	offset int
+	// line is the 1-based line number of the token in the source SQL.
+	line int
+}
+
+// is reports whether the token is one of the keywords, case-insensitive.
+func (t token) is(keywords ...string) bool {
+	if t.kind != tokenIdent {
+		return false
+	}
+	for _, k := range keywords {
+		if strings.EqualFold(t.text, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t token) isPunct(p string) bool {
+	return t.kind == tokenPunct && t.text == p
+}
+
+func (t token) isOperator(op string) bool {
+	return t.kind == tokenOperator && t.text == op
+}
+
+func (t token) isName() bool {
+	return t.kind == tokenIdent || t.kind == tokenQuotedIdent
+}
+
+// ident returns the identifier the token refers to. Unquoted identifiers are
+// folded to lower case like PostgreSQL does, quoted identifiers keep their case.
+func (t token) ident() string {
+	switch t.kind {
+	case tokenQuotedIdent:
+		text := t.text
+		if strings.HasPrefix(text, "U&") || strings.HasPrefix(text, "u&") {
+			text = text[2:]
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], `""`, `"`)
+	case tokenIdent:
+		return strings.ToLower(t.text)
+	default:
+		return t.text
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || r >= utf8.RuneSelf
+}
+
+func isIdentChar(r rune) bool {
+	return isIdentStart(r) || r == '$' || (r >= '0' && r <= '9')
+}
+
+const operatorChars = "+-*/<>=~!@#%^&|`?"
+
+func isOperatorChar(c byte) bool {
+	return strings.IndexByte(operatorChars, c) >= 0
+}
+
+type lexer struct {
+	sql    string
+	pos    int
+	line   int
+	tokens []token
+}
+
+// lex splits sql into tokens, including spaces and comments.
+func lex(sql string) ([]token, error) {
+	l := &lexer{sql: sql, line: 1}
+	for l.pos < len(l.sql) {
+		if err := l.next(); err != nil {
+			return nil, err
+		}
+	}
+	return l.tokens, nil
+}
+
+func (l *lexer) emit(kind tokenKind, end int) {
+	text := l.sql[l.pos:end]
+	l.tokens = append(l.tokens, token{
+		kind:   kind,
+		text:   text,
+		offset: l.pos,
+		line:   l.line,
+	})
+	l.line += strings.Count(text, "\n")
+	l.pos = end
+}
+
+func (l *lexer) peek(offset int) byte {
+	if l.pos+offset < len(l.sql) {
+		return l.sql[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *lexer) next() error {
+	c := l.sql[l.pos]
+	switch {
+	case isSpace(c):
+		end := l.pos
+		for end < len(l.sql) && isSpace(l.sql[end]) {
+			end++
+		}
+		l.emit(tokenSpace, end)
+	case c == '-' && l.peek(1) == '-':
+		end := strings.IndexByte(l.sql[l.pos:], '\n')
+		if end < 0 {
+			end = len(l.sql)
+		} else {
+			end += l.pos
+		}
+		l.emit(tokenComment, end)
+	case c == '/' && l.peek(1) == '*':
+		return l.lexBlockComment()
+	case c == '\'':
+		return l.lexString(l.pos, false)
+	case (c == 'E' || c == 'e') && l.peek(1) == '\'':
+		return l.lexString(l.pos+1, true)
+	case (c == 'B' || c == 'b' || c == 'X' || c == 'x' || c == 'N' || c == 'n') && l.peek(1) == '\'':
+		return l.lexString(l.pos+1, false)
+	case (c == 'U' || c == 'u') && l.peek(1) == '&' && l.peek(2) == '\'':
+		return l.lexString(l.pos+2, false)
+	case (c == 'U' || c == 'u') && l.peek(1) == '&' && l.peek(2) == '"':
+		return l.lexQuotedIdent(l.pos + 2)
+	case c == '"':
+		return l.lexQuotedIdent(l.pos)
+	case c == '$':
+		return l.lexDollar()
+	case isDigit(c) || (c == '.' && isDigit(l.peek(1))):
+		l.lexNumber()
+	case strings.IndexByte("()[],;.:", c) >= 0:
+		if c == ':' && l.peek(1) == ':' {
+			l.emit(tokenOperator, l.pos+2)
+			return nil
+		}
+		l.emit(tokenPunct, l.pos+1)
+	case isOperatorChar(c):
+		end := l.pos
+		for end < len(l.sql) && isOperatorChar(l.sql[end]) {
+			// an operator can not contain the start of a comment.
+			if end > l.pos && ((l.sql[end] == '-' && end+1 < len(l.sql) && l.sql[end+1] == '-') ||
+				(l.sql[end] == '/' && end+1 < len(l.sql) && l.sql[end+1] == '*')) {
+				break
+			}
+			end++
+		}
+		l.emit(tokenOperator, end)
+	default:
+		r, size := utf8.DecodeRuneInString(l.sql[l.pos:])
+		if !isIdentStart(r) {
+			return fmt.Errorf("syntax error at line %d: unexpected character %q", l.line, r)
+		}
+		end := l.pos + size
+		for end < len(l.sql) {
+			r, size := utf8.DecodeRuneInString(l.sql[end:])
+			if !isIdentChar(r) {
+				break
+			}
+			end += size
+		}
+		l.emit(tokenIdent, end)
+	}
+	return nil
+}
+
+func (l *lexer) lexBlockComment() error {
+	depth := 0
+	end := l.pos
+	for end < len(l.sql) {
+		switch {
+		case strings.HasPrefix(l.sql[end:], "/*"):
+			depth++
+			end += 2
+		case strings.HasPrefix(l.sql[end:], "*/"):
+			depth--
+			end += 2
+			if depth == 0 {
+				l.emit(tokenComment, end)
+				return nil
+			}
+		default:
+			end++
+		}
+	}
+	return fmt.Errorf("syntax error at line %d: unterminated /* comment", l.line)
+}
+
+// lexString lexes a string constant whose opening quote is at quote.
+// The prefix before quote (E, B, X, N, U&) is kept in the token text.
+func (l *lexer) lexString(quote int, backslashEscape bool) error {
+	end := quote + 1
+	for end < len(l.sql) {
+		switch l.sql[end] {
+		case '\\':
+			if backslashEscape {
+				end += 2
+				continue
+			}
+		case '\'':
+			if end+1 < len(l.sql) && l.sql[end+1] == '\'' {
+				end += 2
+				continue
+			}
+			l.emit(tokenString, end+1)
+			return nil
+		}
+		end++
+	}
+	return fmt.Errorf("syntax error at line %d: unterminated quoted string", l.line)
+}
+
+func (l *lexer) lexQuotedIdent(quote int) error {
+	end := quote + 1
+	for end < len(l.sql) {
+		if l.sql[end] == '"' {
+			if end+1 < len(l.sql) && l.sql[end+1] == '"' {
+				end += 2
+				continue
+			}
+			l.emit(tokenQuotedIdent, end+1)
+			return nil
+		}
+		end++
+	}
+	return fmt.Errorf("syntax error at line %d: unterminated quoted identifier", l.line)
+}
+
+// lexDollar lexes a positional parameter ($1) or a dollar-quoted string ($tag$...$tag$).
+func (l *lexer) lexDollar() error {
+	if isDigit(l.peek(1)) {
+		end := l.pos + 1
+		for end < len(l.sql) && isDigit(l.sql[end]) {
+			end++
+		}
+		l.emit(tokenParam, end)
+		return nil
+	}
+	tagEnd := l.pos + 1
+	for tagEnd < len(l.sql) && l.sql[tagEnd] != '$' {
+		r, size := utf8.DecodeRuneInString(l.sql[tagEnd:])
+		if !isIdentChar(r) || r == '$' {
+			return fmt.Errorf("syntax error at line %d: unexpected character '$'", l.line)
+		}
+		tagEnd += size
+	}
+	if tagEnd >= len(l.sql) {
+		return fmt.Errorf("syntax error at line %d: unexpected character '$'", l.line)
+	}
+	tag := l.sql[l.pos : tagEnd+1]
+	closing := strings.Index(l.sql[tagEnd+1:], tag)
+	if closing < 0 {
+		return fmt.Errorf("syntax error at line %d: unterminated dollar-quoted string", l.line)
+	}
+	l.emit(tokenString, tagEnd+1+closing+len(tag))
+	return nil
+}
+
+func (l *lexer) lexNumber() {
+	end := l.pos
+	for end < len(l.sql) && (isDigit(l.sql[end]) || l.sql[end] == '_') {
+		end++
+	}
+	if end < len(l.sql) && l.sql[end] == '.' && !(end+1 < len(l.sql) && l.sql[end+1] == '.') {
+		end++
+		for end < len(l.sql) && isDigit(l.sql[end]) {
+			end++
+		}
+	}
+	if end < len(l.sql) && (l.sql[end] == 'e' || l.sql[end] == 'E') {
+		exp := end + 1
+		if exp < len(l.sql) && (l.sql[exp] == '+' || l.sql[exp] == '-') {
+			exp++
+		}
+		if exp < len(l.sql) && isDigit(l.sql[exp]) {
+			end = exp
+			for end < len(l.sql) && isDigit(l.sql[end]) {
+				end++
+			}
+		}
+	}
+	l.emit(tokenNumber, end)
+}
@@ -0,0 +1,190 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOfflineDriver(t *testing.T, rules ...*driverV2.Rule) *PostgreSQLDriverImpl {
+	d, err := NewPostgreSQLDriverImpl(logrus.NewEntry(logrus.New()), &driverV2.Config{Rules: rules})
+	assert.NoError(t, err)
+	return d
+}
+
+func TestParse(t *testing.T) {
+	d := newOfflineDriver(t)
+	sql := `select * from t1 where id = 1;
+CREATE TABLE t2 (id int PRIMARY KEY, name text DEFAULT ';');
+-- comment; with semicolon
+INSERT INTO t1 VALUES (1, 'a'), (2, 'b');
+CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql;
+update t1 set name = $1 where id = $2`
+	nodes, err := d.Parse(context.TODO(), sql)
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 5)
+
+	assert.Equal(t, "select * from t1 where id = 1", nodes[0].Text)
+	assert.Equal(t, driverV2.SQLTypeDQL, nodes[0].Type)
+	assert.Equal(t, "select * from t1 where id = ?", nodes[0].Fingerprint)
+	assert.Equal(t, uint64(1), nodes[0].StartLine)
+
+	assert.Equal(t, driverV2.SQLTypeDDL, nodes[1].Type)
+	assert.Equal(t, uint64(2), nodes[1].StartLine)
+
+	assert.Equal(t, driverV2.SQLTypeDML, nodes[2].Type)
+	assert.Equal(t, "insert into t1 values (?+)", nodes[2].Fingerprint)
+	assert.Equal(t, uint64(4), nodes[2].StartLine)
+
+	assert.Equal(t, "CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql", nodes[3].Text)
+
+	assert.Equal(t, "update t1 set name = ? where id = ?", nodes[4].Fingerprint)
+	assert.Equal(t, uint64(4), nodes[4].ExecBatchId)
+}
+
+func TestParseError(t *testing.T) {
+	d := newOfflineDriver(t)
+	for _, sql := range []string{
+		"select 'abc",
+		`select "abc`,
+		"select 1 /* comment",
+		"select $tag$abc",
+		"ALTER TABLE t ADD",
+		"ALTER TABLE t ADD COLUMN IF NOT EXISTS",
+		"ALTER TABLE t ADD COLUMN c1 int, ALTER",
+		"ALTER TABLE t ADD COLUMN c1 int, ALTER COLUMN",
+		"ALTER TABLE t DROP COLUMN",
+	} {
+		_, err := d.Parse(context.TODO(), sql)
+		assert.Error(t, err, sql)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	for sql, fingerprint := range map[string]string{
+		"SELECT a FROM T WHERE b IN (1, 2, 3)":                  "select a from t where b in (?+)",
+		`SELECT "A" FROM t WHERE c = E'x\'y'`:                   `select "A" from t where c = ?`,
+		"INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y')":        "insert into t (a, b) values (?+)",
+		"SELECT a::text FROM t LIMIT 10 OFFSET 20":              "select a::text from t limit ? offset ?",
+		"SELECT * FROM t WHERE a = $1 AND b = $tag$x$tag$":      "select * from t where a = ? and b = ?",
+		"select count(*) from   t\n where a > 1.5e3":            "select count(*) from t where a > ?",
+		"UPDATE t SET a = a + 1 WHERE id = ANY('{1,2}'::int[])": "update t set a = a + ? where id = any(?::int[])",
+	} {
+		s, err := parseOneStatement(sql)
+		assert.NoError(t, err)
+		assert.Equal(t, fingerprint, s.fingerprint(), sql)
+	}
+}
+
+func TestExtractTableFromSQL(t *testing.T) {
+	d := newOfflineDriver(t)
+	for sql, expected := range map[string][]*driverV2.Table{
+		"SELECT * FROM a JOIN s.b ON a.id = b.id, c WHERE EXTRACT(year FROM now()) > 1": {
+			{Name: "a"}, {Name: "b", Schema: "s"}, {Name: "c"},
+		},
+		"WITH x AS (SELECT * FROM a) SELECT * FROM x, generate_series(1, 10) g": {
+			{Name: "a"},
+		},
+		"UPDATE a SET v = 1 FROM b WHERE a.id = b.id": {
+			{Name: "a"}, {Name: "b"},
+		},
+		"DELETE FROM ONLY a USING b WHERE a.id = b.id": {
+			{Name: "a"}, {Name: "b"},
+		},
+		"INSERT INTO a (id) SELECT id FROM b ON CONFLICT (id) DO UPDATE SET id = excluded.id": {
+			{Name: "a"}, {Name: "b"},
+		},
+		`CREATE TABLE "S"."T" (id int)`: {
+			{Name: "T", Schema: "S"},
+		},
+		"DROP TABLE IF EXISTS a, b.c": {
+			{Name: "a"}, {Name: "c", Schema: "b"},
+		},
+		"CREATE INDEX CONCURRENTLY idx_a ON ONLY a (id)": {
+			{Name: "a"},
+		},
+		"SELECT * FROM a WHERE id IN (SELECT id FROM b) FOR UPDATE": {
+			{Name: "a"}, {Name: "b"},
+		},
+	} {
+		tables, err := d.ExtractTableFromSQL(context.TODO(), sql)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, expected, tables, sql)
+	}
+}
+
+func TestParseCreateTable(t *testing.T) {
+	s, err := parseOneStatement(`CREATE TABLE IF NOT EXISTS s.t (
+	id bigint GENERATED ALWAYS AS IDENTITY,
+	name varchar(20) NOT NULL DEFAULT '',
+	code char(4),
+	created_at timestamp(3) without time zone,
+	updated_at timestamptz,
+	ref int REFERENCES r (id),
+	CONSTRAINT pk_t PRIMARY KEY (id, name)
+)`)
+	assert.NoError(t, err)
+	ct := s.parseCreateTable()
+	assert.NotNil(t, ct)
+	assert.True(t, ct.ifNotExists)
+	assert.Equal(t, tableName{schema: "s", name: "t"}, ct.table)
+	assert.Equal(t, []string{"id", "name"}, ct.primaryKey)
+	assert.Equal(t, []string{"pk_t"}, ct.constraintNames)
+	assert.True(t, ct.hasForeignKey)
+	assert.Len(t, ct.columns, 6)
+
+	assert.True(t, ct.columns[0].identity)
+	assert.Equal(t, "varchar(20)", ct.columns[1].typeName)
+	assert.True(t, ct.columns[1].notNull)
+	assert.True(t, ct.columns[1].hasDefault)
+	assert.True(t, ct.columns[2].isFixedLengthChar())
+	assert.True(t, ct.columns[3].isTimestampWithoutTimeZone())
+	assert.False(t, ct.columns[4].isTimestampWithoutTimeZone())
+}
+
+func TestParseAlterTable(t *testing.T) {
+	s, err := parseOneStatement(`ALTER TABLE ONLY t
+	ADD COLUMN IF NOT EXISTS c1 int NOT NULL,
+	DROP COLUMN c2,
+	ALTER COLUMN c3 TYPE bigint,
+	ALTER c4 SET NOT NULL,
+	ALTER COLUMN c5 SET DEFAULT now(),
+	ADD CONSTRAINT fk FOREIGN KEY (c1) REFERENCES r (id) NOT VALID,
+	DROP CONSTRAINT IF EXISTS ck`)
+	assert.NoError(t, err)
+	at := s.parseAlterTable()
+	assert.NotNil(t, at)
+	assert.Equal(t, tableName{name: "t"}, at.table)
+
+	actions := make([]string, len(at.cmds))
+	for i, cmd := range at.cmds {
+		actions[i] = cmd.action
+	}
+	assert.Equal(t, []string{
+		alterTableAddColumn, alterTableDropColumn, alterTableAlterType, alterTableSetNotNull,
+		alterTableSetDefault, alterTableAddConstraint, alterTableDropConstraint,
+	}, actions)
+	assert.Equal(t, "c1", at.cmds[0].columnName)
+	assert.True(t, at.cmds[0].column.notNull)
+	assert.Equal(t, "c4", at.cmds[3].columnName)
+	assert.Equal(t, "fk", at.cmds[5].constraintName)
+	assert.True(t, at.cmds[5].foreignKey)
+	assert.True(t, at.cmds[5].notValid)
+	assert.Equal(t, "ck", at.cmds[6].constraintName)
+	assert.True(t, at.cmds[6].ifExists)
+}
+
+func TestAuditTruncatedAlterTable(t *testing.T) {
+	d := newOfflineDriver(t)
+	for _, sql := range []string{
+		"ALTER TABLE t ADD",
+		"ALTER TABLE t ADD COLUMN c1 int, ALTER COLUMN",
+	} {
+		_, err := d.Audit(context.TODO(), []string{sql})
+		assert.Error(t, err, sql)
+	}
+}
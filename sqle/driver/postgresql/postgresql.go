@@ -0,0 +1,428 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	_driver "database/sql/driver"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// PostgreSQLDriverImpl implements driverV2.Driver interface for PostgreSQL.
+type PostgreSQLDriverImpl struct {
+	// Ctx is the audit session.
+	Ctx *Context
+	cnf *Config
+
+	rules  []*driverV2.Rule
+	result *driverV2.AuditResults
+	// hasInvalidSql represent one of the commit sql base-validation failed.
+	hasInvalidSql bool
+
+	log *logrus.Entry
+	db  *sql.DB
+	// conn is the session which all SQLs are executed on, so that SET and temporary tables take effect.
+	conn *sql.Conn
+	// backendPid is the process id of conn, it is used to cancel the running SQL.
+	backendPid int64
+	dsn        *driverV2.DSN
+	// isOfflineAudit represent Audit without instance.
+	isOfflineAudit bool
+}
+
+type Config struct {
+	DMLRollbackMaxRows int64
+}
+
+var _ driverV2.Driver = &PostgreSQLDriverImpl{}
+
+func NewPostgreSQLDriverImpl(log *logrus.Entry, cfg *driverV2.Config) (*PostgreSQLDriverImpl, error) {
+	d := &PostgreSQLDriverImpl{
+		log:            log,
+		rules:          cfg.Rules,
+		result:         driverV2.NewAuditResults(),
+		isOfflineAudit: cfg.DSN == nil,
+		dsn:            cfg.DSN,
+		cnf:            &Config{DMLRollbackMaxRows: -1},
+	}
+	for _, rule := range cfg.Rules {
+		if rule.Name == ConfigDMLRollbackMaxRows {
+			d.cnf.DMLRollbackMaxRows = int64(rule.Params.GetParam(DefaultSingleParamKeyName).Int())
+		}
+	}
+
+	if d.isOfflineAudit {
+		d.Ctx = NewContext(nil)
+		return d, nil
+	}
+
+	db, conn, err := open(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	d.db = db
+	d.conn = conn
+	d.Ctx = NewContext(d)
+	if err := conn.QueryRowContext(context.TODO(), "SELECT pg_backend_pid()").Scan(&d.backendPid); err != nil {
+		d.Close(context.TODO())
+		return nil, errors.Wrap(err, "get backend pid")
+	}
+	return d, nil
+}
+
+func open(dsn *driverV2.DSN) (*sql.DB, *sql.Conn, error) {
+	db, err := sql.Open("pgx", connString(dsn))
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := db.Conn(context.TODO())
+	if err != nil {
+		db.Close()
+		return nil, nil, errors.Wrap(err, "get database connection failed when new driver")
+	}
+	if err := conn.PingContext(context.TODO()); err != nil {
+		conn.Close()
+		db.Close()
+		return nil, nil, errors.Wrap(err, "ping database connection failed when new driver")
+	}
+	return db, conn, nil
+}
+
+func connString(dsn *driverV2.DSN) string {
+	database := dsn.DatabaseName
+	if database == "" {
+		database = "postgres"
+	}
+	u := &url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(dsn.User, dsn.Password),
+		Host:   net.JoinHostPort(dsn.Host, dsn.Port),
+		Path:   "/" + database,
+	}
+	// the additional params such as sslmode are the connection params of libpq.
+	query := url.Values{}
+	for _, p := range dsn.AdditionalParams {
+		if p != nil && p.Value != "" {
+			query.Set(p.Key, p.Value)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (d *PostgreSQLDriverImpl) IsOfflineAudit() bool {
+	return d.isOfflineAudit
+}
+
+func (d *PostgreSQLDriverImpl) Logger() *logrus.Entry {
+	return d.log
+}
+
+func (d *PostgreSQLDriverImpl) GetConn() (*sql.Conn, error) {
+	if d.conn == nil {
+		return nil, fmt.Errorf("database conn not initialized")
+	}
+	return d.conn, nil
+}
+
+func (d *PostgreSQLDriverImpl) Close(ctx context.Context) {
+	if d.conn != nil {
+		d.conn.Close()
+	}
+	if d.db != nil {
+		d.db.Close()
+	}
+}
+
+// query runs a query and returns the rows as string maps keyed by column name.
+func (d *PostgreSQLDriverImpl) query(ctx context.Context, query string, args ...interface{}) ([]map[string]sql.NullString, error) {
+	conn, err := d.GetConn()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var result []map[string]sql.NullString
+	for rows.Next() {
+		buf := make([]interface{}, len(columns))
+		data := make([]sql.NullString, len(columns))
+		for i := range buf {
+			buf[i] = &data[i]
+		}
+		if err := rows.Scan(buf...); err != nil {
+			return nil, err
+		}
+		record := make(map[string]sql.NullString, len(columns))
+		for i, column := range columns {
+			record[column] = data[i]
+		}
+		result = append(result, record)
+	}
+	return result, rows.Err()
+}
+
+func (d *PostgreSQLDriverImpl) Exec(ctx context.Context, query string) (_driver.Result, error) {
+	if d.IsOfflineAudit() {
+		return nil, nil
+	}
+	conn, err := d.GetConn()
+	if err != nil {
+		return nil, err
+	}
+	res, err := conn.ExecContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "exec sql")
+	}
+	return res, nil
+}
+
+func (d *PostgreSQLDriverImpl) ExecBatch(ctx context.Context, queries ...string) ([]_driver.Result, error) {
+	results := make([]_driver.Result, 0, len(queries))
+	for _, query := range queries {
+		result, err := d.Exec(ctx, query)
+		results = append(results, result)
+		if err != nil {
+			return results, fmt.Errorf("exec sql failed: \n%s \n%v", query, err)
+		}
+	}
+	return results, nil
+}
+
+func (d *PostgreSQLDriverImpl) Tx(ctx context.Context, queries ...string) (results []_driver.Result, err error) {
+	if d.IsOfflineAudit() {
+		return nil, nil
+	}
+	conn, err := d.GetConn()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				d.Logger().Errorf("rollback tx failed, error: %v", rollbackErr)
+			}
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = errors.Wrap(err, "commit tx")
+		}
+	}()
+
+	results = make([]_driver.Result, 0, len(queries))
+	for _, query := range queries {
+		result, err := tx.ExecContext(ctx, query)
+		if err != nil {
+			return nil, errors.Wrap(err, "exec sql in tx")
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (d *PostgreSQLDriverImpl) Query(ctx context.Context, query string, conf *driverV2.QueryConf) (*driverV2.QueryResult, error) {
+	conn, err := d.GetConn()
+	if err != nil {
+		return nil, err
+	}
+	if conf != nil && conf.TimeOutSecond > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(conf.TimeOutSecond)*time.Second)
+		defer cancel()
+	}
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &driverV2.QueryResult{
+		Column: params.Params{},
+		Rows:   []*driverV2.QueryResultRow{},
+	}
+	for _, column := range columns {
+		result.Column = append(result.Column, &params.Param{
+			Key:   column,
+			Value: column,
+			Desc:  column,
+		})
+	}
+	for rows.Next() {
+		buf := make([]interface{}, len(columns))
+		data := make([]sql.NullString, len(columns))
+		for i := range buf {
+			buf[i] = &data[i]
+		}
+		if err := rows.Scan(buf...); err != nil {
+			return nil, err
+		}
+		row := &driverV2.QueryResultRow{Values: make([]*driverV2.QueryResultValue, len(columns))}
+		for i := range columns {
+			row.Values[i] = &driverV2.QueryResultValue{Value: data[i].String}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, rows.Err()
+}
+
+func (d *PostgreSQLDriverImpl) Ping(ctx context.Context) error {
+	if d.IsOfflineAudit() {
+		return nil
+	}
+	conn, err := d.GetConn()
+	if err != nil {
+		return err
+	}
+	return conn.PingContext(ctx)
+}
+
+func (d *PostgreSQLDriverImpl) GetDatabases(ctx context.Context) ([]string, error) {
+	if d.IsOfflineAudit() {
+		return nil, nil
+	}
+	records, err := d.query(ctx, "SELECT datname FROM pg_catalog.pg_database WHERE NOT datistemplate ORDER BY datname")
+	if err != nil {
+		return nil, errors.Wrap(err, "query databases")
+	}
+	databases := make([]string, len(records))
+	for i, record := range records {
+		databases[i] = record["datname"].String
+	}
+	return databases, nil
+}
+
+// KillProcess cancels the SQL running on the connection of the driver by a new connection.
+func (d *PostgreSQLDriverImpl) KillProcess(ctx context.Context) (*driverV2.KillProcessInfo, error) {
+	if d.IsOfflineAudit() || d.backendPid == 0 {
+		return driverV2.NewKillProcessInfo("cannot find postgresql backend pid"), nil
+	}
+	dsn := *d.dsn
+	db, conn, err := open(&dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	defer conn.Close()
+
+	var canceled bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_cancel_backend($1)", d.backendPid).Scan(&canceled); err != nil {
+		return driverV2.NewKillProcessInfo(err.Error()), nil
+	}
+	if !canceled {
+		return driverV2.NewKillProcessInfo(fmt.Sprintf("cancel backend %d failed", d.backendPid)), nil
+	}
+	return &driverV2.KillProcessInfo{}, nil
+}
+
+func (d *PostgreSQLDriverImpl) Parse(ctx context.Context, sqlText string) ([]driverV2.Node, error) {
+	stmts, err := splitStatements(sqlText)
+	if err != nil {
+		d.Logger().Errorf("parse sql failed, error: %v, sql: %s", err, sqlText)
+		return nil, err
+	}
+	nodes := make([]driverV2.Node, len(stmts))
+	for idx, s := range stmts {
+		nodes[idx] = driverV2.Node{
+			Text:        s.text,
+			Type:        s.sqlType(),
+			Fingerprint: s.fingerprint(),
+			StartLine:   uint64(s.startLine),
+			ExecBatchId: uint64(idx),
+		}
+	}
+	return nodes, nil
+}
+
+func (d *PostgreSQLDriverImpl) ExtractTableFromSQL(ctx context.Context, sql string) ([]*driverV2.Table, error) {
+	s, err := parseOneStatement(sql)
+	if err != nil {
+		return nil, err
+	}
+	names := s.tables()
+	tables := make([]*driverV2.Table, 0, len(names))
+	for _, name := range names {
+		tables = append(tables, &driverV2.Table{Name: name.name, Schema: name.schema})
+	}
+	return tables, nil
+}
+
+func (d *PostgreSQLDriverImpl) GenRollbackSQL(ctx context.Context, sql string) (string, string, error) {
+	if d.IsOfflineAudit() || d.hasInvalidSql {
+		return "", "", nil
+	}
+	s, err := parseOneStatement(sql)
+	if err != nil {
+		return "", "", err
+	}
+	rollback, reason, err := d.generateRollbackSQL(ctx, s)
+	if err != nil {
+		return "", "", err
+	}
+	d.Ctx.UpdateContext(s)
+	return rollback, reason, nil
+}
+
+func driverMetas() *driverV2.DriverMetas {
+	allRules := make([]*driverV2.Rule, len(RuleHandlers))
+	for i := range RuleHandlers {
+		allRules[i] = &RuleHandlers[i].Rule
+	}
+	return &driverV2.DriverMetas{
+		PluginName:          driverV2.DriverTypePostgreSQL,
+		DatabaseDefaultPort: 5432,
+		Rules:               allRules,
+		DatabaseAdditionalParams: params.Params{
+			{
+				Key:  "sslmode",
+				Desc: "SSL 模式（disable、allow、prefer、require、verify-ca、verify-full）",
+				Type: params.ParamTypeString,
+			},
+			{
+				Key:  "connect_timeout",
+				Desc: "连接超时时间（秒）",
+				Type: params.ParamTypeString,
+			},
+		},
+		EnabledOptionalModule: []driverV2.OptionalModule{
+			driverV2.OptionalModuleGenRollbackSQL,
+			driverV2.OptionalModuleQuery,
+			driverV2.OptionalModuleExplain,
+			driverV2.OptionalModuleGetTableMeta,
+			driverV2.OptionalModuleExtractTableFromSQL,
+			driverV2.OptionalModuleEstimateSQLAffectRows,
+			driverV2.OptionalModuleKillProcess,
+		},
+	}
+}
+
+func init() {
+	driver.BuiltInPluginProcessors[driverV2.DriverTypePostgreSQL] = &driver.BuiltInPluginProcessorV2{
+		Meta: driverMetas(),
+		DriverFactory: func(l *logrus.Entry, cfg *driverV2.Config) (driverV2.Driver, error) {
+			return NewPostgreSQLDriverImpl(l, cfg)
+		},
+	}
+}
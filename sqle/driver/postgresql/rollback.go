@@ -0,0 +1,616 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const (
+	NotSupportStatementRollback               = "暂不支持回滚该类型的语句"
+	NotSupportMultiTableStatementRollback     = "暂不支持回滚多表的 DML 语句"
+	NotSupportOnConflictStatementRollback     = "暂不支持回滚 ON CONFLICT 语句"
+	NotSupportNoPrimaryKeyTableRollback       = "不支持回滚没有主键的表的DML语句"
+	NotSupportInsertWithoutPrimaryKeyRollback = "不支持回滚 INSERT 没有指定主键的语句"
+	NotSupportUpdatePrimaryKeyRollback        = "不支持回滚修改主键的 UPDATE 语句"
+	NotSupportParamMarkerStatementRollback    = "不支持回滚包含指纹的语句"
+	NotSupportExceedMaxRowsRollback           = "预计影响行数超过配置的最大值，不生成回滚语句"
+	NotSupportUnnamedIndexRollback            = "不支持回滚未指定名称的索引或约束"
+	NotSupportObjectNotExistRollback          = "对象 %s 不存在，无法生成回滚语句"
+)
+
+func (d *PostgreSQLDriverImpl) generateRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	for _, t := range s.tokens {
+		if t.kind == tokenParam {
+			return "", NotSupportParamMarkerStatementRollback, nil
+		}
+	}
+	switch s.kind {
+	case stmtCreateTable, stmtCreateSequence, stmtCreateSchema:
+		return d.generateCreateObjectRollbackSQL(s)
+	case stmtCreateView:
+		return d.generateCreateViewRollbackSQL(ctx, s)
+	case stmtCreateIndex:
+		return d.generateCreateIndexRollbackSQL(s)
+	case stmtAlterTable:
+		return d.generateAlterTableRollbackSQL(ctx, s)
+	case stmtDropTable:
+		return d.generateDropTableRollbackSQL(ctx, s)
+	case stmtDropIndex:
+		return d.generateDropIndexRollbackSQL(ctx, s)
+	case stmtDropView:
+		return d.generateDropViewRollbackSQL(ctx, s)
+	case stmtInsert:
+		return d.generateInsertRollbackSQL(ctx, s)
+	case stmtDelete:
+		return d.generateDeleteRollbackSQL(ctx, s)
+	case stmtUpdate:
+		return d.generateUpdateRollbackSQL(ctx, s)
+	case stmtSelect, stmtShow, stmtExplain, stmtSet:
+		return "", "", nil
+	}
+	return "", NotSupportStatementRollback, nil
+}
+
+func (d *PostgreSQLDriverImpl) generateCreateObjectRollbackSQL(s *stmt) (string, string, error) {
+	// CREATE ... IF NOT EXISTS may do nothing, dropping the object is not safe.
+	if s.ifNotExists() {
+		return "", "", nil
+	}
+	i := s.objectNameIndex()
+	switch s.kind {
+	case stmtCreateTable:
+		name, _, ok := s.parseQualifiedName(i)
+		if !ok {
+			return "", "", nil
+		}
+		return fmt.Sprintf("DROP TABLE %s;", name.quoted()), "", nil
+	case stmtCreateSequence:
+		name, _, ok := s.parseQualifiedName(i)
+		if !ok {
+			return "", "", nil
+		}
+		return fmt.Sprintf("DROP SEQUENCE %s;", name.quoted()), "", nil
+	case stmtCreateSchema:
+		if s.at(i).is("authorization") {
+			// CREATE SCHEMA AUTHORIZATION role creates the schema named by the role.
+			i++
+		}
+		if !s.at(i).isName() {
+			return "", "", nil
+		}
+		return fmt.Sprintf("DROP SCHEMA %s;", quoteIdent(s.at(i).ident())), "", nil
+	}
+	return "", "", nil
+}
+
+func (d *PostgreSQLDriverImpl) generateCreateViewRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	name, _, ok := s.parseQualifiedName(s.objectNameIndex())
+	if !ok {
+		return "", "", nil
+	}
+	materialized := s.hasKeyword("materialized")
+	if s.at(1).is("or") {
+		definition, err := d.viewDefinition(ctx, d.Ctx.completeTableName(name))
+		if err != nil {
+			return "", "", err
+		}
+		if definition != "" {
+			return fmt.Sprintf("CREATE OR REPLACE VIEW %s AS %s", name.quoted(), definition), "", nil
+		}
+	}
+	if materialized {
+		return fmt.Sprintf("DROP MATERIALIZED VIEW %s;", name.quoted()), "", nil
+	}
+	return fmt.Sprintf("DROP VIEW %s;", name.quoted()), "", nil
+}
+
+func (d *PostgreSQLDriverImpl) generateCreateIndexRollbackSQL(s *stmt) (string, string, error) {
+	ci := s.parseCreateIndex()
+	if ci == nil {
+		return "", "", nil
+	}
+	if ci.name == "" {
+		return "", NotSupportUnnamedIndexRollback, nil
+	}
+	// the index is created in the schema of its table.
+	return fmt.Sprintf("DROP INDEX %s;", tableName{schema: ci.table.schema, name: ci.name}.quoted()), "", nil
+}
+
+func (d *PostgreSQLDriverImpl) generateAlterTableRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	at := s.parseAlterTable()
+	if at == nil {
+		return "", NotSupportStatementRollback, nil
+	}
+	table := d.Ctx.completeTableName(at.table)
+	columns, err := d.columnDefinitions(ctx, table)
+	if err != nil {
+		return "", "", err
+	}
+
+	var actions []string
+	var renameTable string
+	for i := len(at.cmds) - 1; i >= 0; i-- {
+		cmd := at.cmds[i]
+		col := columns[cmd.columnName]
+		switch cmd.action {
+		case alterTableAddColumn:
+			actions = append(actions, fmt.Sprintf("DROP COLUMN %s", quoteIdent(cmd.columnName)))
+		case alterTableDropColumn, alterTableAlterType, alterTableSetDefault, alterTableDropDefault:
+			if col == nil {
+				if cmd.ifExists {
+					continue
+				}
+				return "", fmt.Sprintf(NotSupportObjectNotExistRollback, cmd.columnName), nil
+			}
+			switch cmd.action {
+			case alterTableDropColumn:
+				actions = append(actions, fmt.Sprintf("ADD COLUMN %s", col.definition()))
+			case alterTableAlterType:
+				actions = append(actions, fmt.Sprintf("ALTER COLUMN %s TYPE %s", quoteIdent(col.name), col.typeName))
+			default:
+				if col.defaultValue.Valid {
+					actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", quoteIdent(col.name), col.defaultValue.String))
+				} else {
+					actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", quoteIdent(col.name)))
+				}
+			}
+		case alterTableSetNotNull:
+			if col != nil && col.notNull {
+				continue
+			}
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", quoteIdent(cmd.columnName)))
+		case alterTableDropNotNull:
+			if col != nil && !col.notNull {
+				continue
+			}
+			actions = append(actions, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", quoteIdent(cmd.columnName)))
+		case alterTableAddConstraint:
+			if cmd.constraintName == "" {
+				return "", NotSupportUnnamedIndexRollback, nil
+			}
+			actions = append(actions, fmt.Sprintf("DROP CONSTRAINT %s", quoteIdent(cmd.constraintName)))
+		case alterTableDropConstraint:
+			definition, err := d.constraintDefinition(ctx, table, cmd.constraintName)
+			if err != nil {
+				return "", "", err
+			}
+			if definition == "" {
+				if cmd.ifExists {
+					continue
+				}
+				return "", fmt.Sprintf(NotSupportObjectNotExistRollback, cmd.constraintName), nil
+			}
+			actions = append(actions, fmt.Sprintf("ADD CONSTRAINT %s %s", quoteIdent(cmd.constraintName), definition))
+		case alterTableRenameColumn:
+			actions = append(actions, fmt.Sprintf("RENAME COLUMN %s TO %s", quoteIdent(cmd.newName), quoteIdent(cmd.columnName)))
+		case alterTableRename:
+			renameTable = fmt.Sprintf("ALTER TABLE %s RENAME TO %s;",
+				tableName{schema: at.table.schema, name: cmd.newName}.quoted(), quoteIdent(at.table.name))
+		default:
+			return "", NotSupportStatementRollback, nil
+		}
+	}
+	if renameTable != "" {
+		// RENAME TO can not be combined with other actions.
+		return renameTable, "", nil
+	}
+	if len(actions) == 0 {
+		return "", "", nil
+	}
+	return fmt.Sprintf("ALTER TABLE %s %s;", at.table.quoted(), strings.Join(actions, ", ")), "", nil
+}
+
+func (d *PostgreSQLDriverImpl) generateDropTableRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	var rollbackSQLs []string
+	for _, t := range s.tables() {
+		t = d.Ctx.completeTableName(t)
+		exist, err := d.Ctx.IsTableExist(t)
+		if err != nil {
+			return "", "", err
+		}
+		if !exist {
+			continue
+		}
+		createTableSQL, err := d.showCreateTable(ctx, t)
+		if err != nil {
+			return "", "", err
+		}
+		rollbackSQLs = append(rollbackSQLs, createTableSQL)
+	}
+	return strings.Join(rollbackSQLs, "\n"), "", nil
+}
+
+func (d *PostgreSQLDriverImpl) generateDropIndexRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	names, _ := s.parseNameList(s.objectNameIndex())
+	var rollbackSQLs []string
+	for _, name := range names {
+		schema := name.schema
+		if schema == "" {
+			schema = d.Ctx.CurrentSchema()
+		}
+		records, err := d.query(ctx, `SELECT pg_catalog.pg_get_indexdef(c.oid) AS definition
+FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('i', 'I')`, schema, name.name)
+		if err != nil {
+			return "", "", err
+		}
+		if len(records) == 0 {
+			continue
+		}
+		rollbackSQLs = append(rollbackSQLs, records[0]["definition"].String+";")
+	}
+	return strings.Join(rollbackSQLs, "\n"), "", nil
+}
+
+func (d *PostgreSQLDriverImpl) generateDropViewRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	var rollbackSQLs []string
+	for _, t := range s.tables() {
+		definition, err := d.viewDefinition(ctx, d.Ctx.completeTableName(t))
+		if err != nil {
+			return "", "", err
+		}
+		if definition == "" {
+			continue
+		}
+		rollbackSQLs = append(rollbackSQLs, fmt.Sprintf("CREATE VIEW %s AS %s", t.quoted(), definition))
+	}
+	return strings.Join(rollbackSQLs, "\n"), "", nil
+}
+
+func (d *PostgreSQLDriverImpl) viewDefinition(ctx context.Context, view tableName) (string, error) {
+	records, err := d.query(ctx, `SELECT pg_catalog.pg_get_viewdef(c.oid, true) AS definition
+FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('v', 'm')`, view.schema, view.name)
+	if err != nil || len(records) == 0 {
+		return "", err
+	}
+	return records[0]["definition"].String, nil
+}
+
+func (d *PostgreSQLDriverImpl) constraintDefinition(ctx context.Context, table tableName, constraint string) (string, error) {
+	records, err := d.query(ctx, `SELECT pg_catalog.pg_get_constraintdef(con.oid) AS definition
+FROM pg_catalog.pg_constraint con
+JOIN pg_catalog.pg_class c ON c.oid = con.conrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2 AND con.conname = $3`, table.schema, table.name, constraint)
+	if err != nil || len(records) == 0 {
+		return "", err
+	}
+	return records[0]["definition"].String, nil
+}
+
+type columnInfo struct {
+	name         string
+	typeName     string
+	notNull      bool
+	defaultValue sql.NullString
+}
+
+func (c *columnInfo) definition() string {
+	def := fmt.Sprintf("%s %s", quoteIdent(c.name), c.typeName)
+	if c.defaultValue.Valid {
+		def += " DEFAULT " + c.defaultValue.String
+	}
+	if c.notNull {
+		def += " NOT NULL"
+	}
+	return def
+}
+
+// columnDefinitions returns the columns of the table keyed by column name.
+func (d *PostgreSQLDriverImpl) columnDefinitions(ctx context.Context, table tableName) (map[string]*columnInfo, error) {
+	records, err := d.query(ctx, queryTableColumns, table.schema, table.name)
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]*columnInfo, len(records))
+	for _, record := range records {
+		col := &columnInfo{
+			name:         record["column_name"].String,
+			typeName:     record["column_type"].String,
+			notNull:      record["is_nullable"].String == "NO",
+			defaultValue: record["column_default"],
+		}
+		columns[col.name] = col
+	}
+	return columns, nil
+}
+
+func (d *PostgreSQLDriverImpl) primaryKeyColumns(ctx context.Context, table tableName) ([]string, error) {
+	records, err := d.query(ctx, `SELECT a.attname AS column_name
+FROM pg_catalog.pg_index ix
+JOIN pg_catalog.pg_class c ON c.oid = ix.indrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_catalog.pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = ANY(ix.indkey)
+WHERE n.nspname = $1 AND c.relname = $2 AND ix.indisprimary
+ORDER BY array_position(ix.indkey::int2[], a.attnum)`, table.schema, table.name)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, len(records))
+	for i, record := range records {
+		columns[i] = record["column_name"].String
+	}
+	return columns, nil
+}
+
+// dmlTarget parses the target table of UPDATE/DELETE, it returns the table,
+// the text of the table with its alias and the index after them.
+func (s *stmt) dmlTarget() (table tableName, text string, next int, ok bool) {
+	i := s.mainVerbIndex() + 1
+	if s.kind == stmtDelete {
+		i = s.skip(i, "from")
+	}
+	start := i
+	table, next, ok = s.parseQualifiedName(s.skip(i, "only"))
+	if !ok {
+		return
+	}
+	next = s.skipAlias(next)
+	return table, tokensTextOf(s, s.tokens[start:next]), next, true
+}
+
+func (d *PostgreSQLDriverImpl) checkRollbackRows(ctx context.Context, from, where string) (string, error) {
+	if d.cnf.DMLRollbackMaxRows < 0 {
+		return "", nil
+	}
+	query := fmt.Sprintf("SELECT count(*) AS count FROM %s", from)
+	if where != "" {
+		query += " WHERE " + where
+	}
+	var count int64
+	conn, err := d.GetConn()
+	if err != nil {
+		return "", err
+	}
+	if err := conn.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		return "", err
+	}
+	if count > d.cnf.DMLRollbackMaxRows {
+		return NotSupportExceedMaxRowsRollback, nil
+	}
+	return "", nil
+}
+
+// selectRows returns the rows of the table matching the WHERE clause, the columns are in table order.
+func (d *PostgreSQLDriverImpl) selectRows(ctx context.Context, from, where string) ([]string, [][]sql.NullString, error) {
+	query := fmt.Sprintf("SELECT * FROM %s", from)
+	if where != "" {
+		query += " WHERE " + where
+	}
+	conn, err := d.GetConn()
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	var records [][]sql.NullString
+	for rows.Next() {
+		buf := make([]interface{}, len(columns))
+		data := make([]sql.NullString, len(columns))
+		for i := range buf {
+			buf[i] = &data[i]
+		}
+		if err := rows.Scan(buf...); err != nil {
+			return nil, nil, err
+		}
+		records = append(records, data)
+	}
+	return columns, records, rows.Err()
+}
+
+func literal(v sql.NullString) string {
+	if !v.Valid {
+		return "NULL"
+	}
+	return quoteLiteral(v.String)
+}
+
+func (d *PostgreSQLDriverImpl) generateDeleteRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	if s.at(0).is("with") || s.topLevelIndex(s.mainVerbIndex(), "using") >= 0 {
+		return "", NotSupportMultiTableStatementRollback, nil
+	}
+	table, from, _, ok := s.dmlTarget()
+	if !ok {
+		return "", NotSupportStatementRollback, nil
+	}
+	where := tokensTextOf(s, s.whereClause())
+	if reason, err := d.checkRollbackRows(ctx, from, where); err != nil || reason != "" {
+		return "", reason, err
+	}
+	columns, records, err := d.selectRows(ctx, from, where)
+	if err != nil {
+		return "", "", err
+	}
+	quotedColumns := make([]string, len(columns))
+	for i, c := range columns {
+		quotedColumns[i] = quoteIdent(c)
+	}
+	var rollbackSQLs []string
+	for _, record := range records {
+		values := make([]string, len(record))
+		for i, v := range record {
+			values[i] = literal(v)
+		}
+		rollbackSQLs = append(rollbackSQLs, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);",
+			table.quoted(), strings.Join(quotedColumns, ", "), strings.Join(values, ", ")))
+	}
+	return strings.Join(rollbackSQLs, "\n"), "", nil
+}
+
+func (d *PostgreSQLDriverImpl) generateUpdateRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	if s.at(0).is("with") || s.topLevelIndex(s.mainVerbIndex(), "from") >= 0 {
+		return "", NotSupportMultiTableStatementRollback, nil
+	}
+	table, from, next, ok := s.dmlTarget()
+	if !ok || !s.at(next).is("set") {
+		return "", NotSupportStatementRollback, nil
+	}
+	pk, err := d.primaryKeyColumns(ctx, d.Ctx.completeTableName(table))
+	if err != nil {
+		return "", "", err
+	}
+	if len(pk) == 0 {
+		return "", NotSupportNoPrimaryKeyTableRollback, nil
+	}
+
+	// collect the updated columns, "SET (a, b) = (...)" is supported too.
+	setEnd := s.topLevelIndex(next, "where", "returning")
+	if setEnd < 0 {
+		setEnd = len(s.tokens)
+	}
+	updated := map[string]struct{}{}
+	for _, elem := range (&stmt{tokens: s.tokens[next+1 : setEnd]}).topLevelElements() {
+		if len(elem) == 0 {
+			continue
+		}
+		e := &stmt{tokens: elem}
+		if e.at(0).isPunct("(") {
+			for _, name := range e.identList(0) {
+				updated[name] = struct{}{}
+			}
+		} else {
+			updated[e.at(0).ident()] = struct{}{}
+		}
+	}
+	for _, c := range pk {
+		if _, ok := updated[c]; ok {
+			return "", NotSupportUpdatePrimaryKeyRollback, nil
+		}
+	}
+
+	where := tokensTextOf(s, s.whereClause())
+	if reason, err := d.checkRollbackRows(ctx, from, where); err != nil || reason != "" {
+		return "", reason, err
+	}
+	columns, records, err := d.selectRows(ctx, from, where)
+	if err != nil {
+		return "", "", err
+	}
+	var rollbackSQLs []string
+	for _, record := range records {
+		var sets, conditions []string
+		for i, c := range columns {
+			if _, ok := updated[c]; ok {
+				sets = append(sets, fmt.Sprintf("%s = %s", quoteIdent(c), literal(record[i])))
+			}
+			for _, p := range pk {
+				if p == c {
+					conditions = append(conditions, fmt.Sprintf("%s = %s", quoteIdent(c), literal(record[i])))
+				}
+			}
+		}
+		if len(sets) == 0 {
+			continue
+		}
+		rollbackSQLs = append(rollbackSQLs, fmt.Sprintf("UPDATE %s SET %s WHERE %s;",
+			table.quoted(), strings.Join(sets, ", "), strings.Join(conditions, " AND ")))
+	}
+	return strings.Join(rollbackSQLs, "\n"), "", nil
+}
+
+func (d *PostgreSQLDriverImpl) generateInsertRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	if s.topLevelIndex(s.mainVerbIndex(), "on") >= 0 {
+		return "", NotSupportOnConflictStatementRollback, nil
+	}
+	table, _, ok := s.parseQualifiedName(s.topLevelIndex(s.mainVerbIndex(), "into") + 1)
+	if !ok {
+		return "", NotSupportStatementRollback, nil
+	}
+	pk, err := d.primaryKeyColumns(ctx, d.Ctx.completeTableName(table))
+	if err != nil {
+		return "", "", err
+	}
+	if len(pk) == 0 {
+		return "", NotSupportNoPrimaryKeyTableRollback, nil
+	}
+	target := insertTarget(s)
+	values := s.topLevelIndex(s.mainVerbIndex(), "values")
+	if !s.at(target).isPunct("(") || values < 0 {
+		return "", NotSupportInsertWithoutPrimaryKeyRollback, nil
+	}
+	columns := s.identList(target)
+	pkIndex := make([]int, 0, len(pk))
+	for _, p := range pk {
+		for i, c := range columns {
+			if c == p {
+				pkIndex = append(pkIndex, i)
+			}
+		}
+	}
+	if len(pkIndex) != len(pk) {
+		return "", NotSupportInsertWithoutPrimaryKeyRollback, nil
+	}
+
+	rows := 0
+	var conditions []string
+	for i := values + 1; s.at(i).isPunct("("); i++ {
+		rows++
+		row := s.elements(i)
+		var condition []string
+		for j, idx := range pkIndex {
+			if idx >= len(row) {
+				return "", NotSupportInsertWithoutPrimaryKeyRollback, nil
+			}
+			// the value must be a constant, DEFAULT or expressions can not be rolled back.
+			if len(row[idx]) != 1 || (row[idx][0].kind != tokenString && row[idx][0].kind != tokenNumber) {
+				return "", NotSupportInsertWithoutPrimaryKeyRollback, nil
+			}
+			condition = append(condition, fmt.Sprintf("%s = %s", quoteIdent(pk[j]), row[idx][0].text))
+		}
+		conditions = append(conditions, strings.Join(condition, " AND "))
+		i = s.matchingParen(i) + 1
+		if !s.at(i).isPunct(",") {
+			break
+		}
+	}
+	if d.cnf.DMLRollbackMaxRows >= 0 && int64(rows) > d.cnf.DMLRollbackMaxRows {
+		return "", NotSupportExceedMaxRowsRollback, nil
+	}
+	if len(conditions) == 0 {
+		return "", "", nil
+	}
+	if len(pk) > 1 {
+		for i := range conditions {
+			conditions[i] = "(" + conditions[i] + ")"
+		}
+	}
+	return fmt.Sprintf("DELETE FROM %s WHERE %s;", table.quoted(), strings.Join(conditions, " OR ")), "", nil
+}
+
+// topLevelElements splits all tokens of the statement by top-level commas.
+func (s *stmt) topLevelElements() [][]token {
+	var elems [][]token
+	start, depth := 0, 0
+	for i, t := range s.tokens {
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+		case t.isPunct(",") && depth == 0:
+			elems = append(elems, s.tokens[start:i])
+			start = i + 1
+		}
+	}
+	return append(elems, s.tokens[start:])
+}
+
+// tokensTextOf returns the original text of the tokens which are a sub slice of the statement tokens.
+func tokensTextOf(s *stmt, tokens []token) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	base := s.tokens[0].offset
+	first, last := tokens[0], tokens[len(tokens)-1]
+	return s.text[first.offset-base : last.offset+len(last.text)-base]
+}
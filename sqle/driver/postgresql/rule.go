@@ -0,0 +1,601 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+// rule type
+const (
+	RuleTypeGlobalConfig       = "全局配置"
+	RuleTypeNamingConvention   = "命名规范"
+	RuleTypeIndexingConvention = "索引规范"
+	RuleTypeDDLConvention      = "DDL规范"
+	RuleTypeDMLConvention      = "DML规范"
+	RuleTypeUsageSuggestion    = "使用建议"
+)
+
+const DefaultSingleParamKeyName = "first_key" // For most of the rules, it is just has one param, this is first params.
+
+// inspector config code
+const (
+	ConfigDMLRollbackMaxRows = "dml_rollback_max_rows"
+)
+
+// inspector DDL rules
+const (
+	DDLCheckPKWithoutIfNotExists                = "ddl_check_table_without_if_not_exists"
+	DDLCheckObjectNameLength                    = "ddl_check_object_name_length"
+	DDLCheckObjectNameUsingKeyword              = "ddl_check_object_name_using_keyword"
+	DDLCheckPKNotExist                          = "ddl_check_pk_not_exist"
+	DDLDisableFK                                = "ddl_disable_fk"
+	DDLDisableDropStatement                     = "ddl_disable_drop_statement"
+	DDLCheckIndexPrefix                         = "ddl_check_index_prefix"
+	DDLCheckUniqueIndexPrefix                   = "ddl_check_unique_index_prefix"
+	DDLCheckFieldNotNUllMustContainDefaultValue = "ddl_check_field_not_null_must_contain_default_value"
+	DDLCheckIndexWithoutConcurrently            = "ddl_check_index_without_concurrently"
+	DDLRecommendIdentityColumn                  = "ddl_recommend_identity_column"
+	DDLCheckFixedLengthChar                     = "ddl_check_fixed_length_char"
+	DDLCheckTimestampWithoutTimeZone            = "ddl_check_timestamp_without_time_zone"
+	DDLCheckAlterColumnType                     = "ddl_check_alter_column_type"
+	DDLCheckAddConstraintWithoutNotValid        = "ddl_check_add_constraint_without_not_valid"
+	DDLCheckSetNotNull                          = "ddl_check_set_not_null"
+)
+
+// inspector DML rules
+const (
+	DMLCheckWhereIsInvalid       = "all_check_where_is_invalid"
+	DMLDisableSelectAllColumn    = "dml_disable_select_all_column"
+	DMLCheckSelectLimit          = "dml_check_select_limit"
+	DMLCheckFuzzySearch          = "dml_check_fuzzy_search"
+	DMLCheckInsertColumnsExist   = "dml_check_insert_columns_exist"
+	DMLCheckBatchInsertListsMax  = "dml_check_batch_insert_lists_max"
+	DMLCheckInQueryNumber        = "dml_check_in_query_limit"
+	DMLNotRecommendOrderByRandom = "dml_not_recommend_order_by_random"
+	DMLCheckSelectForUpdate      = "dml_check_select_for_update"
+	DMLCheckSQLLength            = "dml_check_sql_length"
+	DMLCheckAffectedRows         = "dml_check_affected_rows"
+	DMLCheckExplainFullTableScan = "dml_check_explain_full_table_scan"
+)
+
+type RuleHandlerInput struct {
+	Ctx  *Context
+	Rule driverV2.Rule
+	Res  *driverV2.AuditResults
+	Stmt *stmt
+	// Driver is used by the rules which need instance, it is not nil when AllowOffline of the rule is false.
+	Driver *PostgreSQLDriverImpl
+}
+
+type RuleHandlerFunc func(input *RuleHandlerInput) error
+
+type RuleHandler struct {
+	Rule         driverV2.Rule
+	Message      string
+	Func         RuleHandlerFunc
+	AllowOffline bool
+}
+
+var RuleHandlerMap = map[string]RuleHandler{}
+
+func init() {
+	for _, rh := range RuleHandlers {
+		RuleHandlerMap[rh.Rule.Name] = rh
+	}
+}
+
+func addResult(result *driverV2.AuditResults, currentRule driverV2.Rule, ruleName string, args ...interface{}) {
+	// if rule is not current rule, ignore save the message.
+	if ruleName != currentRule.Name {
+		return
+	}
+	message := RuleHandlerMap[ruleName].Message
+	result.Add(currentRule.Level, ruleName, message, args...)
+}
+
+// reservedKeywords is the keywords which are reserved or can be function or type name only in PostgreSQL.
+var reservedKeywords = map[string]struct{}{}
+
+func init() {
+	for _, k := range strings.Fields(`all analyse analyze and any array as asc asymmetric authorization binary both case
+	cast check collate collation column concurrently constraint create cross current_catalog current_date
+	current_role current_schema current_time current_timestamp current_user default deferrable desc distinct
+	do else end except false fetch for foreign freeze from full grant group having ilike in initially inner
+	intersect into is isnull join lateral leading left like limit localtime localtimestamp natural not notnull
+	null offset on only or order outer overlaps placing primary references returning right select session_user
+	similar some symmetric system_user table tablesample then to trailing true union unique user using variadic
+	verbose when where window with`) {
+		reservedKeywords[k] = struct{}{}
+	}
+}
+
+// newObjectNames returns names of the objects created by the statement, such as
+// table, column, index and constraint names.
+func newObjectNames(s *stmt) []string {
+	var names []string
+	switch s.kind {
+	case stmtCreateTable:
+		ct := s.parseCreateTable()
+		if ct == nil {
+			return nil
+		}
+		names = append(names, ct.table.name)
+		for _, col := range ct.columns {
+			names = append(names, col.name)
+		}
+		names = append(names, ct.constraintNames...)
+	case stmtCreateIndex:
+		if ci := s.parseCreateIndex(); ci != nil && ci.name != "" {
+			names = append(names, ci.name)
+		}
+	case stmtCreateView, stmtCreateSequence, stmtCreateSchema:
+		i := s.objectNameIndex()
+		if s.kind == stmtCreateSchema && s.at(i).is("authorization") {
+			i++
+		}
+		if name, _, ok := s.parseQualifiedName(i); ok {
+			names = append(names, name.name)
+		}
+	case stmtAlterTable:
+		at := s.parseAlterTable()
+		if at == nil {
+			return nil
+		}
+		for _, cmd := range at.cmds {
+			switch cmd.action {
+			case alterTableAddColumn:
+				names = append(names, cmd.columnName)
+			case alterTableRenameColumn, alterTableRename:
+				names = append(names, cmd.newName)
+			case alterTableAddConstraint:
+				if cmd.constraintName != "" {
+					names = append(names, cmd.constraintName)
+				}
+			}
+		}
+	}
+	return names
+}
+
+func checkObjectNameLength(input *RuleHandlerInput) error {
+	max := input.Rule.Params.GetParam(DefaultSingleParamKeyName).Int()
+	for _, name := range newObjectNames(input.Stmt) {
+		if len(name) > max {
+			addResult(input.Res, input.Rule, DDLCheckObjectNameLength, max)
+			return nil
+		}
+	}
+	return nil
+}
+
+func checkObjectNameUsingKeyword(input *RuleHandlerInput) error {
+	var invalidNames []string
+	for _, name := range newObjectNames(input.Stmt) {
+		if _, ok := reservedKeywords[strings.ToLower(name)]; ok {
+			invalidNames = append(invalidNames, name)
+		}
+	}
+	if len(invalidNames) > 0 {
+		addResult(input.Res, input.Rule, DDLCheckObjectNameUsingKeyword, strings.Join(invalidNames, ", "))
+	}
+	return nil
+}
+
+func checkPrimaryKey(input *RuleHandlerInput) error {
+	ct := input.Stmt.parseCreateTable()
+	if ct == nil || ct.isDerived {
+		return nil
+	}
+	if len(ct.primaryKey) == 0 {
+		addResult(input.Res, input.Rule, DDLCheckPKNotExist)
+	}
+	return nil
+}
+
+func checkIfNotExist(input *RuleHandlerInput) error {
+	ct := input.Stmt.parseCreateTable()
+	if ct != nil && !ct.ifNotExists {
+		addResult(input.Res, input.Rule, DDLCheckPKWithoutIfNotExists)
+	}
+	return nil
+}
+
+func checkForeignKey(input *RuleHandlerInput) error {
+	hasForeignKey := false
+	switch input.Stmt.kind {
+	case stmtCreateTable:
+		if ct := input.Stmt.parseCreateTable(); ct != nil {
+			hasForeignKey = ct.hasForeignKey
+		}
+	case stmtAlterTable:
+		if at := input.Stmt.parseAlterTable(); at != nil {
+			for _, cmd := range at.cmds {
+				if cmd.foreignKey {
+					hasForeignKey = true
+				}
+			}
+		}
+	}
+	if hasForeignKey {
+		addResult(input.Res, input.Rule, DDLDisableFK)
+	}
+	return nil
+}
+
+func disableDropStmt(input *RuleHandlerInput) error {
+	switch input.Stmt.kind {
+	case stmtDropTable, stmtDropView, stmtDropSchema, stmtDropDatabase:
+		addResult(input.Res, input.Rule, DDLDisableDropStatement)
+	}
+	return nil
+}
+
+func checkIndexPrefix(input *RuleHandlerInput) error {
+	ci := input.Stmt.parseCreateIndex()
+	if ci == nil || ci.name == "" {
+		return nil
+	}
+	prefix := input.Rule.Params.GetParam(DefaultSingleParamKeyName).String()
+	if ci.unique == (input.Rule.Name == DDLCheckUniqueIndexPrefix) &&
+		!strings.HasPrefix(strings.ToLower(ci.name), strings.ToLower(prefix)) {
+		addResult(input.Res, input.Rule, input.Rule.Name, prefix)
+	}
+	return nil
+}
+
+func checkFieldNotNullMustContainDefaultValue(input *RuleHandlerInput) error {
+	var names []string
+	for _, col := range columnsOf(input.Stmt) {
+		if col.notNull && !col.hasDefault && !col.primaryKey && !col.identity && !col.isSerial() {
+			names = append(names, col.name)
+		}
+	}
+	if len(names) > 0 {
+		addResult(input.Res, input.Rule, DDLCheckFieldNotNUllMustContainDefaultValue, strings.Join(names, ","))
+	}
+	return nil
+}
+
+func checkIndexWithoutConcurrently(input *RuleHandlerInput) error {
+	s := input.Stmt
+	switch s.kind {
+	case stmtCreateIndex:
+		ci := s.parseCreateIndex()
+		// the index on a new table can be created without lock problem.
+		if ci != nil && !ci.concurrently && !input.Ctx.IsTableCreatedInContext(ci.table) {
+			addResult(input.Res, input.Rule, DDLCheckIndexWithoutConcurrently)
+		}
+	case stmtDropIndex:
+		if !s.hasKeyword("concurrently") {
+			addResult(input.Res, input.Rule, DDLCheckIndexWithoutConcurrently)
+		}
+	}
+	return nil
+}
+
+// columnsOf returns columns defined by CREATE TABLE or ALTER TABLE ADD COLUMN.
+func columnsOf(s *stmt) []*columnDef {
+	switch s.kind {
+	case stmtCreateTable:
+		if ct := s.parseCreateTable(); ct != nil {
+			return ct.columns
+		}
+	case stmtAlterTable:
+		var columns []*columnDef
+		if at := s.parseAlterTable(); at != nil {
+			for _, cmd := range at.cmds {
+				if cmd.action == alterTableAddColumn {
+					columns = append(columns, cmd.column)
+				}
+			}
+		}
+		return columns
+	}
+	return nil
+}
+
+func checkColumnType(input *RuleHandlerInput) error {
+	var names []string
+	for _, col := range columnsOf(input.Stmt) {
+		var invalid bool
+		switch input.Rule.Name {
+		case DDLRecommendIdentityColumn:
+			invalid = col.isSerial()
+		case DDLCheckFixedLengthChar:
+			invalid = col.isFixedLengthChar()
+		case DDLCheckTimestampWithoutTimeZone:
+			invalid = col.isTimestampWithoutTimeZone()
+		}
+		if invalid {
+			names = append(names, col.name)
+		}
+	}
+	if len(names) > 0 {
+		addResult(input.Res, input.Rule, input.Rule.Name, strings.Join(names, ","))
+	}
+	return nil
+}
+
+// checkAlterTableLock checks the ALTER TABLE commands which rewrite or scan the
+// whole table with ACCESS EXCLUSIVE lock. The table created in the same batch is ignored.
+func checkAlterTableLock(input *RuleHandlerInput) error {
+	at := input.Stmt.parseAlterTable()
+	if at == nil || input.Ctx.IsTableCreatedInContext(at.table) {
+		return nil
+	}
+	var names []string
+	for _, cmd := range at.cmds {
+		switch input.Rule.Name {
+		case DDLCheckAlterColumnType:
+			if cmd.action == alterTableAlterType {
+				names = append(names, cmd.columnName)
+			}
+		case DDLCheckSetNotNull:
+			if cmd.action == alterTableSetNotNull {
+				names = append(names, cmd.columnName)
+			}
+		case DDLCheckAddConstraintWithoutNotValid:
+			if cmd.action == alterTableAddConstraint && !cmd.notValid &&
+				(cmd.foreignKey || (len(cmd.tokens) > 0 && cmd.tokens[0].is("check"))) {
+				addResult(input.Res, input.Rule, DDLCheckAddConstraintWithoutNotValid)
+				return nil
+			}
+		}
+	}
+	if len(names) > 0 {
+		addResult(input.Res, input.Rule, input.Rule.Name, strings.Join(names, ","))
+	}
+	return nil
+}
+
+// isTautology reports whether the condition is always true, such as "true", "1=1" or "'a'='a'".
+func isTautology(cond []token) bool {
+	switch len(cond) {
+	case 1:
+		return cond[0].is("true")
+	case 3:
+		return cond[1].isOperator("=") && cond[0].kind == cond[2].kind &&
+			(cond[0].kind == tokenNumber || cond[0].kind == tokenString) && cond[0].text == cond[2].text
+	}
+	return false
+}
+
+func checkWhereIsInvalid(input *RuleHandlerInput) error {
+	s := input.Stmt
+	switch s.kind {
+	case stmtSelect:
+		if s.topLevelIndex(s.mainVerbIndex(), "from") < 0 {
+			return nil
+		}
+	case stmtUpdate, stmtDelete:
+	default:
+		return nil
+	}
+	// "UPDATE/DELETE ... WHERE CURRENT OF cursor" works on one row.
+	where := s.whereClause()
+	if len(where) > 0 && where[0].is("current") {
+		return nil
+	}
+	if len(where) == 0 || isTautology(where) {
+		addResult(input.Res, input.Rule, DMLCheckWhereIsInvalid)
+	}
+	return nil
+}
+
+func checkSelectAll(input *RuleHandlerInput) error {
+	s := input.Stmt
+	if s.kind != stmtSelect {
+		return nil
+	}
+	for i, t := range s.tokens {
+		if !t.isOperator("*") {
+			continue
+		}
+		prev := s.at(i - 1)
+		if prev.is("select", "distinct", "all") || prev.isPunct(",") || prev.isPunct(".") {
+			// "count(*)" is not a select all column.
+			if prev.isPunct(",") && s.at(i+1).isPunct(")") {
+				continue
+			}
+			addResult(input.Res, input.Rule, DMLDisableSelectAllColumn)
+			return nil
+		}
+	}
+	return nil
+}
+
+// limitCount returns the row count of LIMIT or FETCH FIRST clause, ok is false if there is no row count.
+func limitCount(s *stmt) (count int64, ok bool) {
+	verb := s.mainVerbIndex()
+	if i := s.topLevelIndex(verb, "limit"); i >= 0 && s.at(i+1).kind == tokenNumber {
+		count, err := strconv.ParseInt(s.at(i+1).text, 10, 64)
+		return count, err == nil
+	}
+	if i := s.topLevelIndex(verb, "fetch"); i >= 0 {
+		if s.at(i+2).kind != tokenNumber {
+			// FETCH FIRST ROW ONLY
+			return 1, true
+		}
+		count, err := strconv.ParseInt(s.at(i+2).text, 10, 64)
+		return count, err == nil
+	}
+	return 0, false
+}
+
+func checkSelectLimit(input *RuleHandlerInput) error {
+	s := input.Stmt
+	if s.kind != stmtSelect || s.topLevelIndex(s.mainVerbIndex(), "from") < 0 {
+		return nil
+	}
+	max := int64(input.Rule.Params.GetParam(DefaultSingleParamKeyName).Int())
+	if count, ok := limitCount(s); !ok || count > max {
+		addResult(input.Res, input.Rule, DMLCheckSelectLimit, max)
+	}
+	return nil
+}
+
+func checkFuzzySearch(input *RuleHandlerInput) error {
+	s := input.Stmt
+	for i, t := range s.tokens {
+		if !t.is("like", "ilike") && !t.isOperator("~~") && !t.isOperator("~~*") {
+			continue
+		}
+		pattern := s.at(i + 1)
+		if pattern.kind == tokenString && (strings.HasPrefix(pattern.text, "'%") || strings.HasPrefix(pattern.text, "'_")) {
+			addResult(input.Res, input.Rule, DMLCheckFuzzySearch)
+			return nil
+		}
+	}
+	return nil
+}
+
+// insertTarget returns the index after the target table and its alias of INSERT.
+func insertTarget(s *stmt) int {
+	into := s.topLevelIndex(s.mainVerbIndex(), "into")
+	if into < 0 {
+		return -1
+	}
+	_, next, ok := s.parseQualifiedName(into + 1)
+	if !ok {
+		return -1
+	}
+	if s.at(next).is("as") {
+		next += 2
+	}
+	return next
+}
+
+func checkInsertColumnsExist(input *RuleHandlerInput) error {
+	s := input.Stmt
+	if s.kind != stmtInsert {
+		return nil
+	}
+	i := insertTarget(s)
+	if i < 0 {
+		return nil
+	}
+	if !s.at(i).isPunct("(") || s.at(i+1).is("select", "with", "values") {
+		addResult(input.Res, input.Rule, DMLCheckInsertColumnsExist)
+	}
+	return nil
+}
+
+func checkBatchInsertListsMax(input *RuleHandlerInput) error {
+	s := input.Stmt
+	if s.kind != stmtInsert {
+		return nil
+	}
+	values := s.topLevelIndex(s.mainVerbIndex(), "values")
+	if values < 0 {
+		return nil
+	}
+	count := 0
+	for i := values + 1; s.at(i).isPunct("("); i++ {
+		count++
+		i = s.matchingParen(i) + 1
+		if !s.at(i).isPunct(",") {
+			break
+		}
+	}
+	max := input.Rule.Params.GetParam(DefaultSingleParamKeyName).Int()
+	if count > max {
+		addResult(input.Res, input.Rule, DMLCheckBatchInsertListsMax, max)
+	}
+	return nil
+}
+
+func checkInQueryLimit(input *RuleHandlerInput) error {
+	s := input.Stmt
+	max := input.Rule.Params.GetParam(DefaultSingleParamKeyName).Int()
+	for i, t := range s.tokens {
+		if !t.is("in") || !s.at(i+1).isPunct("(") || s.at(i+2).is("select", "with", "values") {
+			continue
+		}
+		if count := len(s.elements(i + 1)); count > max {
+			addResult(input.Res, input.Rule, DMLCheckInQueryNumber, count, max)
+			return nil
+		}
+	}
+	return nil
+}
+
+func notRecommendOrderByRandom(input *RuleHandlerInput) error {
+	s := input.Stmt
+	for i, t := range s.tokens {
+		if !t.is("order") || !s.at(i+1).is("by") {
+			continue
+		}
+		for j := i + 2; j < len(s.tokens); j++ {
+			if s.at(j).is("random") && s.at(j+1).isPunct("(") {
+				addResult(input.Res, input.Rule, DMLNotRecommendOrderByRandom)
+				return nil
+			}
+			if s.at(j).is("limit", "offset", "fetch", "for") || s.at(j).isPunct(")") {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func checkSelectForUpdate(input *RuleHandlerInput) error {
+	s := input.Stmt
+	if s.kind != stmtSelect {
+		return nil
+	}
+	if i := s.topLevelIndex(s.mainVerbIndex(), "for"); i >= 0 && s.at(i+1).is("update") {
+		addResult(input.Res, input.Rule, DMLCheckSelectForUpdate)
+	}
+	return nil
+}
+
+func checkSQLLength(input *RuleHandlerInput) error {
+	if len(input.Stmt.text) > input.Rule.Params.GetParam(DefaultSingleParamKeyName).Int() {
+		addResult(input.Res, input.Rule, DMLCheckSQLLength)
+	}
+	return nil
+}
+
+func checkAffectedRows(input *RuleHandlerInput) error {
+	s := input.Stmt
+	if s.kind != stmtUpdate && s.kind != stmtDelete {
+		return nil
+	}
+	affectedRows, err := input.Driver.estimateRows(context.TODO(), s.text)
+	if err != nil {
+		input.Driver.Logger().Warnf("estimate affected rows failed, sql: %s, error: %v", s.text, err)
+		return nil
+	}
+	max := int64(input.Rule.Params.GetParam(DefaultSingleParamKeyName).Int())
+	if affectedRows > max {
+		addResult(input.Res, input.Rule, DMLCheckAffectedRows, affectedRows, max)
+	}
+	return nil
+}
+
+func checkExplainFullTableScan(input *RuleHandlerInput) error {
+	s := input.Stmt
+	if s.kind != stmtSelect && s.kind != stmtUpdate && s.kind != stmtDelete && s.kind != stmtInsert {
+		return nil
+	}
+	plan, err := input.Driver.explainJSON(context.TODO(), s.text)
+	if err != nil {
+		input.Driver.Logger().Warnf("explain sql failed, sql: %s, error: %v", s.text, err)
+		return nil
+	}
+	max := input.Rule.Params.GetParam(DefaultSingleParamKeyName).Float64()
+	var rows []string
+	plan.walk(func(p *planNode) {
+		if p.NodeType == "Seq Scan" && p.PlanRows >= max {
+			rows = append(rows, fmt.Sprintf("%s(%v)", p.RelationName, int64(p.PlanRows)))
+		}
+	})
+	if len(rows) > 0 {
+		addResult(input.Res, input.Rule, DMLCheckExplainFullTableScan, strings.Join(rows, ", "))
+	}
+	return nil
+}
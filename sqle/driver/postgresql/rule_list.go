@@ -0,0 +1,437 @@
+package postgresql
+
+import (
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+)
+
+var RuleHandlers = []RuleHandler{
+	// config
+	{
+		Rule: driverV2.Rule{
+			Name:       ConfigDMLRollbackMaxRows,
+			Desc:       "在 DML 语句中预计影响行数超过指定值则不回滚",
+			Annotation: "大事务回滚，容易影响数据库性能，使得业务发生波动；具体规则阈值可以根据业务需求调整，默认值：1000",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeGlobalConfig,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "1000",
+					Desc:  "最大影响行数",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Func: nil,
+	},
+	// rule
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckPKWithoutIfNotExists,
+			Desc:       "新建表建议加入 IF NOT EXISTS，保证重复执行不报错",
+			Annotation: "新建表如果表已经存在，不添加IF NOT EXISTS CREATE执行SQL会报错，建议开启此规则，避免SQL实际执行报错",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeUsageSuggestion,
+		},
+		Message:      "新建表建议加入 IF NOT EXISTS，保证重复执行不报错",
+		AllowOffline: true,
+		Func:         checkIfNotExist,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckObjectNameLength,
+			Desc:       "表名、列名、索引名的长度不建议超过阈值",
+			Annotation: "通过配置该规则可以规范指定业务的对象命名长度，具体长度可以自定义设置，默认最大长度：63。PostgreSQL规定标识符命名最大长度为63字节，超出部分会被截断",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeNamingConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "63",
+					Desc:  "最大长度（字节）",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message:      "表名、列名、索引名的长度不建议大于%v字节",
+		AllowOffline: true,
+		Func:         checkObjectNameLength,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckObjectNameUsingKeyword,
+			Desc:       "数据库对象命名禁止使用保留字",
+			Annotation: "通过配置该规则可以规范指定业务的数据对象命名规则，避免发生冲突，以及混淆",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeNamingConvention,
+		},
+		Message:      "数据库对象命名禁止使用保留字 %s",
+		AllowOffline: true,
+		Func:         checkObjectNameUsingKeyword,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckPKNotExist,
+			Desc:       "表必须有主键",
+			Annotation: "主键使数据达到全局唯一，可提高数据检索效率，逻辑复制等功能也依赖主键定位数据行",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeIndexingConvention,
+		},
+		Message:      "表必须有主键",
+		AllowOffline: true,
+		Func:         checkPrimaryKey,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckFieldNotNUllMustContainDefaultValue,
+			Desc:       "建议字段约束为NOT NULL时带默认值",
+			Annotation: "如存在NOT NULL且不带默认值的字段，INSERT时不包含该字段，会导致插入报错；对已有数据的表添加此类字段会直接失败",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeDDLConvention,
+		},
+		Message:      "建议字段约束为NOT NULL时带默认值，以下字段不规范:%v",
+		AllowOffline: true,
+		Func:         checkFieldNotNullMustContainDefaultValue,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLDisableFK,
+			Desc:       "禁止使用外键",
+			Annotation: "外键在高并发场景下性能较差，容易造成死锁，同时不利于后期维护（拆分、迁移）",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeIndexingConvention,
+		},
+		Message:      "禁止使用外键",
+		AllowOffline: true,
+		Func:         checkForeignKey,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLDisableDropStatement,
+			Desc:       "禁止除索引外的DROP操作",
+			Annotation: "DROP是DDL，数据变更不会写入日志，无法进行回滚；建议开启此规则，避免误删除操作",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeUsageSuggestion,
+		},
+		Message:      "禁止除索引外的DROP操作",
+		AllowOffline: true,
+		Func:         disableDropStmt,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckIndexPrefix,
+			Desc:       "建议普通索引使用固定前缀",
+			Annotation: "通过配置该规则可以规范指定业务的索引命名规则，具体命名规范可以自定义设置，默认提示值：idx_",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeNamingConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "idx_",
+					Desc:  "索引前缀",
+					Type:  params.ParamTypeString,
+				},
+			},
+		},
+		Message:      "建议普通索引要以\"%v\"为前缀",
+		AllowOffline: true,
+		Func:         checkIndexPrefix,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckUniqueIndexPrefix,
+			Desc:       "建议UNIQUE索引使用固定前缀",
+			Annotation: "通过配置该规则可以规范指定业务的UNIQUE索引命名规则，具体命名规范可以自定义设置，默认提示值：uniq_",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeNamingConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "uniq_",
+					Desc:  "索引前缀",
+					Type:  params.ParamTypeString,
+				},
+			},
+		},
+		Message:      "建议UNIQUE索引要以\"%v\"为前缀",
+		AllowOffline: true,
+		Func:         checkIndexPrefix,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckIndexWithoutConcurrently,
+			Desc:       "建议使用 CONCURRENTLY 创建或删除索引",
+			Annotation: "不带 CONCURRENTLY 创建索引会阻塞表上的写入，删除索引会阻塞表上的读写，直到操作结束；对新建的表创建索引不受此规则限制",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeIndexingConvention,
+		},
+		Message:      "建议使用 CONCURRENTLY 创建或删除索引，避免长时间锁表",
+		AllowOffline: true,
+		Func:         checkIndexWithoutConcurrently,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLRecommendIdentityColumn,
+			Desc:       "建议使用 IDENTITY 列代替 serial 类型",
+			Annotation: "serial 类型依赖单独创建的序列，权限、复制表结构时容易出现问题；PostgreSQL 10 起建议使用 GENERATED AS IDENTITY",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDDLConvention,
+		},
+		Message:      "建议使用 GENERATED AS IDENTITY 代替 serial 类型，以下字段不规范:%v",
+		AllowOffline: true,
+		Func:         checkColumnType,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckFixedLengthChar,
+			Desc:       "不建议使用 char(n) 类型",
+			Annotation: "char(n) 会用空格补齐长度，比较和存储时容易产生非预期的结果，且性能上没有优势，建议使用 varchar(n) 或 text",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDDLConvention,
+		},
+		Message:      "不建议使用 char(n) 类型，以下字段不规范:%v",
+		AllowOffline: true,
+		Func:         checkColumnType,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckTimestampWithoutTimeZone,
+			Desc:       "建议使用 timestamptz 代替 timestamp",
+			Annotation: "timestamp without time zone 不保存时区信息，在不同时区的客户端之间使用时容易出现时间错乱",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDDLConvention,
+		},
+		Message:      "建议使用 timestamptz 代替 timestamp，以下字段不规范:%v",
+		AllowOffline: true,
+		Func:         checkColumnType,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckAlterColumnType,
+			Desc:       "修改字段类型需要谨慎",
+			Annotation: "修改字段类型大多会重写整张表及其索引，期间持有 ACCESS EXCLUSIVE 锁，阻塞表上的所有读写",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeDDLConvention,
+		},
+		Message:      "修改字段类型可能导致重写表并长时间锁表，以下字段:%v",
+		AllowOffline: true,
+		Func:         checkAlterTableLock,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckSetNotNull,
+			Desc:       "对已有的表设置 NOT NULL 需要谨慎",
+			Annotation: "SET NOT NULL 需要扫描全表校验数据，期间持有 ACCESS EXCLUSIVE 锁；建议先添加 NOT VALID 的 CHECK 约束并校验，再设置 NOT NULL",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeDDLConvention,
+		},
+		Message:      "SET NOT NULL 会扫描全表并长时间锁表，以下字段:%v",
+		AllowOffline: true,
+		Func:         checkAlterTableLock,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckAddConstraintWithoutNotValid,
+			Desc:       "对已有的表添加外键或CHECK约束时建议使用 NOT VALID",
+			Annotation: "直接添加约束需要在锁表期间校验全表数据；使用 NOT VALID 添加约束后再执行 VALIDATE CONSTRAINT，校验期间不会阻塞读写",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeDDLConvention,
+		},
+		Message:      "对已有的表添加外键或CHECK约束时建议使用 NOT VALID，再单独执行 VALIDATE CONSTRAINT",
+		AllowOffline: true,
+		Func:         checkAlterTableLock,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckWhereIsInvalid,
+			Desc:       "禁止使用没有WHERE条件或者WHERE条件恒为TRUE的SQL",
+			Annotation: "SQL缺少WHERE条件在执行时会进行全表扫描产生额外开销，建议在大数据量高并发环境下开启，避免影响数据库查询性能",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeDMLConvention,
+		},
+		Message:      "禁止使用没有WHERE条件或者WHERE条件恒为TRUE的SQL",
+		AllowOffline: true,
+		Func:         checkWhereIsInvalid,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLDisableSelectAllColumn,
+			Desc:       "不建议使用SELECT *",
+			Annotation: "当表结构变更时，使用*通配符选择所有列将导致查询行为会发生更改，与业务期望不符；同时SELECT * 中的无用字段会带来不必要的磁盘I/O，以及网络开销",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDMLConvention,
+		},
+		Message:      "不建议使用SELECT *",
+		AllowOffline: true,
+		Func:         checkSelectAll,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckSelectLimit,
+			Desc:       "SELECT 语句需要带LIMIT",
+			Annotation: "如果查询的扫描行数很大，可能会导致优化器选择错误的索引甚至不走索引；具体规则阈值可以根据业务需求调整，默认值：1000",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeDMLConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "1000",
+					Desc:  "最大查询行数",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message:      "SELECT 语句需要带LIMIT,且限制数不得超过%v",
+		AllowOffline: true,
+		Func:         checkSelectLimit,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckFuzzySearch,
+			Desc:       "禁止使用全模糊搜索或左模糊搜索",
+			Annotation: "使用全模糊搜索或左模糊搜索将导致查询无法使用B-tree索引，导致全表扫描",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeDMLConvention,
+		},
+		Message:      "禁止使用全模糊搜索或左模糊搜索",
+		AllowOffline: true,
+		Func:         checkFuzzySearch,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckInsertColumnsExist,
+			Desc:       "INSERT 语句需要指定COLUMN",
+			Annotation: "当表结构发生变更，INSERT请求不明确指定列名，会发生插入数据不匹配的情况；建议开启此规则，避免插入结果与业务预期不符",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDMLConvention,
+		},
+		Message:      "INSERT 语句需要指定COLUMN",
+		AllowOffline: true,
+		Func:         checkInsertColumnsExist,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckBatchInsertListsMax,
+			Desc:       "单条INSERT语句，建议批量插入不超过阈值",
+			Annotation: "避免大事务，以及降低发生回滚对业务的影响；具体规则阈值可以根据业务需求调整，默认值：100",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDMLConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "100",
+					Desc:  "最大插入行数",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message:      "单条INSERT语句，建议批量插入不超过%v条",
+		AllowOffline: true,
+		Func:         checkBatchInsertListsMax,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckInQueryNumber,
+			Desc:       "WHERE条件内IN语句中的参数个数不能超过阈值",
+			Annotation: "当IN值过多时，有可能会导致查询进行全表扫描，使得数据库性能急剧下降；具体规则阈值可以根据业务需求调整，默认值：50",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeDMLConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "50",
+					Desc:  "in语句参数最大个数",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message:      "WHERE条件内IN语句中的参数已有%v个，不建议超过阙值%v",
+		AllowOffline: true,
+		Func:         checkInQueryLimit,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLNotRecommendOrderByRandom,
+			Desc:       "不建议使用 ORDER BY random()",
+			Annotation: "ORDER BY random() 需要对全部结果排序，在数据量很大的情况下会增加服务器负载以及增加查询时间；建议使用 TABLESAMPLE 等方式代替",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeDMLConvention,
+		},
+		Message:      "不建议使用 ORDER BY random()",
+		AllowOffline: true,
+		Func:         notRecommendOrderByRandom,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckSelectForUpdate,
+			Desc:       "不建议使用SELECT FOR UPDATE",
+			Annotation: "SELECT FOR UPDATE 会对查询结果集中每行数据都添加行锁，其他事务对该记录的更新与删除操作都会阻塞，在高并发下，容易造成数据库大量锁等待",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDMLConvention,
+		},
+		Message:      "不建议使用SELECT FOR UPDATE",
+		AllowOffline: true,
+		Func:         checkSelectForUpdate,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckSQLLength,
+			Desc:       "建议将过长的SQL分解成几个简单的SQL",
+			Annotation: "过长的SQL可读性较差，难以维护，且容易引发性能问题；具体规则阈值可以根据业务需求调整，默认值：1024",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDMLConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "1024",
+					Desc:  "SQL最大长度",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message:      "建议将过长的SQL分解成几个简单的SQL",
+		AllowOffline: true,
+		Func:         checkSQLLength,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckAffectedRows,
+			Desc:       "UPDATE/DELETE操作影响行数不建议超过阈值",
+			Annotation: "如果 DML 操作影响行数过多，会产生大事务并导致表膨胀，影响数据库性能",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeDMLConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "10000",
+					Desc:  "最大影响行数",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		AllowOffline: false,
+		Message:      "UPDATE/DELETE操作影响行数不建议超过阈值，影响行数为 %v，超过设定阈值 %v",
+		Func:         checkAffectedRows,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckExplainFullTableScan,
+			Desc:       "全表扫描时，扫描行数不建议超过指定行数（默认值：10000）",
+			Annotation: "全表扫描时，扫描行数不建议超过指定行数是为了避免性能问题；具体规则阈值可以根据业务需求调整，默认值：10000；如果设置为0，全表扫描都会触发规则",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeDMLConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   DefaultSingleParamKeyName,
+					Value: "10000",
+					Desc:  "最大扫描行数",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message:      "该查询使用了全表扫描，表及预估扫描行数为 %v",
+		AllowOffline: false,
+		Func:         checkExplainFullTableScan,
+	},
+}
@@ -0,0 +1,414 @@
+package postgresql
+
+import (
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+type stmtKind string
+
+const (
+	stmtSelect         stmtKind = "select"
+	stmtInsert         stmtKind = "insert"
+	stmtUpdate         stmtKind = "update"
+	stmtDelete         stmtKind = "delete"
+	stmtMerge          stmtKind = "merge"
+	stmtCopy           stmtKind = "copy"
+	stmtExplain        stmtKind = "explain"
+	stmtShow           stmtKind = "show"
+	stmtSet            stmtKind = "set"
+	stmtTruncate       stmtKind = "truncate"
+	stmtCreateTable    stmtKind = "create_table"
+	stmtCreateIndex    stmtKind = "create_index"
+	stmtCreateView     stmtKind = "create_view"
+	stmtCreateSchema   stmtKind = "create_schema"
+	stmtCreateSequence stmtKind = "create_sequence"
+	stmtAlterTable     stmtKind = "alter_table"
+	stmtDropTable      stmtKind = "drop_table"
+	stmtDropIndex      stmtKind = "drop_index"
+	stmtDropView       stmtKind = "drop_view"
+	stmtDropSchema     stmtKind = "drop_schema"
+	stmtDropDatabase   stmtKind = "drop_database"
+	stmtOther          stmtKind = "other"
+)
+
+// stmt is a single SQL statement split from the input text.
+type stmt struct {
+	text      string
+	startLine int
+	// tokens is the significant tokens of the statement, spaces and comments are removed.
+	tokens []token
+	kind   stmtKind
+}
+
+// splitStatements splits sql into statements by top-level semicolons.
+func splitStatements(sql string) ([]*stmt, error) {
+	all, err := lex(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	var stmts []*stmt
+	var current []token
+	var parenDepth, atomicDepth, caseDepth int
+	flush := func() {
+		if len(current) > 0 {
+			stmts = append(stmts, newStmt(sql, current))
+		}
+		current = nil
+	}
+	for _, t := range all {
+		if t.kind == tokenSpace || t.kind == tokenComment {
+			continue
+		}
+		switch {
+		case t.isPunct("("):
+			parenDepth++
+		case t.isPunct(")"):
+			if parenDepth > 0 {
+				parenDepth--
+			}
+		case t.is("atomic") && len(current) > 0 && current[len(current)-1].is("begin"):
+			// SQL-standard function body: BEGIN ATOMIC ... END
+			atomicDepth++
+		case t.is("case") && atomicDepth > 0:
+			caseDepth++
+		case t.is("end") && atomicDepth > 0:
+			if caseDepth > 0 {
+				caseDepth--
+			} else {
+				atomicDepth--
+			}
+		}
+		if t.isPunct(";") && parenDepth == 0 && atomicDepth == 0 {
+			flush()
+			continue
+		}
+		current = append(current, t)
+	}
+	flush()
+	for _, s := range stmts {
+		if s.kind == stmtAlterTable {
+			if _, err := s.parseAlterTableCmds(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stmts, nil
+}
+
+func newStmt(sql string, tokens []token) *stmt {
+	first, last := tokens[0], tokens[len(tokens)-1]
+	s := &stmt{
+		text:      sql[first.offset : last.offset+len(last.text)],
+		startLine: first.line,
+		tokens:    tokens,
+	}
+	s.kind = s.resolveKind()
+	return s
+}
+
+// parseOneStatement parses sql which should contain only one statement.
+func parseOneStatement(sql string) (*stmt, error) {
+	stmts, err := splitStatements(sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) == 0 {
+		return nil, driverV2.ErrSQLisEmpty
+	}
+	if len(stmts) > 1 {
+		return nil, driverV2.ErrNodesCountExceedOne
+	}
+	return stmts[0], nil
+}
+
+func (s *stmt) at(i int) token {
+	if i >= 0 && i < len(s.tokens) {
+		return s.tokens[i]
+	}
+	return token{kind: tokenSpace}
+}
+
+// skip returns the index after the keywords sequence if tokens from i match it, otherwise i.
+func (s *stmt) skip(i int, keywords ...string) int {
+	for j, k := range keywords {
+		if !s.at(i + j).is(k) {
+			return i
+		}
+	}
+	return i + len(keywords)
+}
+
+// matchingParen returns the index of the ")" matching the "(" at i.
+func (s *stmt) matchingParen(i int) int {
+	depth := 0
+	for j := i; j < len(s.tokens); j++ {
+		switch {
+		case s.tokens[j].isPunct("("):
+			depth++
+		case s.tokens[j].isPunct(")"):
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(s.tokens) - 1
+}
+
+// mainVerbIndex returns the index of the main verb, it skips the WITH clause.
+func (s *stmt) mainVerbIndex() int {
+	if !s.at(0).is("with") {
+		return 0
+	}
+	depth := 0
+	for i, t := range s.tokens {
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+		case depth == 0 && i > 0 && t.is("select", "insert", "update", "delete", "merge", "values", "table"):
+			return i
+		}
+	}
+	return 0
+}
+
+func (s *stmt) resolveKind() stmtKind {
+	i := s.mainVerbIndex()
+	verb := s.at(i)
+	switch {
+	case verb.isPunct("("):
+		return stmtSelect
+	case verb.is("select", "values", "table"):
+		return stmtSelect
+	case verb.is("insert"):
+		return stmtInsert
+	case verb.is("update"):
+		return stmtUpdate
+	case verb.is("delete"):
+		return stmtDelete
+	case verb.is("merge"):
+		return stmtMerge
+	case verb.is("copy"):
+		return stmtCopy
+	case verb.is("explain"):
+		return stmtExplain
+	case verb.is("show"):
+		return stmtShow
+	case verb.is("set", "reset"):
+		return stmtSet
+	case verb.is("truncate"):
+		return stmtTruncate
+	case verb.is("create"):
+		j := s.skip(i+1, "or", "replace")
+		for s.at(j).is("global", "local", "temp", "temporary", "unlogged", "unique", "recursive", "materialized") {
+			j++
+		}
+		switch {
+		case s.at(j).is("table"):
+			return stmtCreateTable
+		case s.at(j).is("index"):
+			return stmtCreateIndex
+		case s.at(j).is("view"):
+			return stmtCreateView
+		case s.at(j).is("schema"):
+			return stmtCreateSchema
+		case s.at(j).is("sequence"):
+			return stmtCreateSequence
+		}
+	case verb.is("alter"):
+		if s.at(i + 1).is("table") {
+			return stmtAlterTable
+		}
+	case verb.is("drop"):
+		j := i + 1
+		if s.at(j).is("materialized") {
+			j++
+		}
+		switch {
+		case s.at(j).is("table"):
+			return stmtDropTable
+		case s.at(j).is("index"):
+			return stmtDropIndex
+		case s.at(j).is("view"):
+			return stmtDropView
+		case s.at(j).is("schema"):
+			return stmtDropSchema
+		case s.at(j).is("database"):
+			return stmtDropDatabase
+		}
+	}
+	return stmtOther
+}
+
+// sqlType returns the SQLE SQL type of the statement, such as DML/DDL/DQL.
+func (s *stmt) sqlType() string {
+	switch s.kind {
+	case stmtSelect, stmtShow, stmtExplain:
+		return driverV2.SQLTypeDQL
+	case stmtInsert, stmtUpdate, stmtDelete, stmtMerge, stmtCopy:
+		return driverV2.SQLTypeDML
+	default:
+		return driverV2.SQLTypeDDL
+	}
+}
+
+func (s *stmt) isDML() bool {
+	return s.sqlType() != driverV2.SQLTypeDDL
+}
+
+// topLevelIndex returns the index of the first keyword at parenthesis depth 0
+// from start, or -1 if not found.
+func (s *stmt) topLevelIndex(start int, keywords ...string) int {
+	depth := 0
+	for i := start; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+		case depth == 0 && t.is(keywords...):
+			return i
+		}
+	}
+	return -1
+}
+
+// clause returns the tokens of the top-level clause starting with keyword, till
+// one of the terminators at the same depth or the end of the statement.
+func (s *stmt) clause(keyword string, terminators ...string) []token {
+	start := s.topLevelIndex(s.mainVerbIndex(), keyword)
+	if start < 0 {
+		return nil
+	}
+	end := s.topLevelIndex(start+1, terminators...)
+	if end < 0 {
+		end = len(s.tokens)
+	}
+	return s.tokens[start+1 : end]
+}
+
+// whereClause returns tokens of the top-level WHERE clause, nil if the statement
+// has no WHERE clause.
+func (s *stmt) whereClause() []token {
+	return s.clause("where", "group", "having", "window", "order", "limit", "offset", "fetch",
+		"for", "returning", "union", "intersect", "except")
+}
+
+func (s *stmt) hasKeyword(keywords ...string) bool {
+	for _, t := range s.tokens {
+		if t.is(keywords...) {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprint returns the normalized text of the statement, literal values are
+// replaced by "?", unquoted identifiers and keywords are in lower case and
+// consecutive "?" lists are collapsed, so that the same SQL with different
+// values has the same fingerprint.
+func (s *stmt) fingerprint() string {
+	parts := make([]fingerprintPart, 0, len(s.tokens))
+	for i, t := range s.tokens {
+		p := fingerprintPart{text: t.text}
+		// keep the space before "(" as it is, both "IN (1)" and "count(*)" are common.
+		p.spaced = i > 0 && s.tokens[i-1].offset+len(s.tokens[i-1].text) < t.offset
+		switch t.kind {
+		case tokenString, tokenNumber, tokenParam:
+			p.text = "?"
+		case tokenIdent:
+			p.text = strings.ToLower(t.text)
+		}
+		parts = append(parts, p)
+	}
+	parts = collapseValueLists(parts)
+
+	var b strings.Builder
+	for i, p := range parts {
+		if i == 0 {
+			b.WriteString(p.text)
+			continue
+		}
+		space := needSpace(parts[i-1].text, p.text)
+		if p.text == "(" && isIdentLike(parts[i-1].text) {
+			space = p.spaced
+		}
+		if space {
+			b.WriteByte(' ')
+		}
+		b.WriteString(p.text)
+	}
+	return b.String()
+}
+
+type fingerprintPart struct {
+	text string
+	// spaced reports whether there is white space before the part in the source SQL.
+	spaced bool
+}
+
+// collapseValueLists replaces "(?, ?, ...)" by "(?+)" and "(?+), (?+), ..." by "(?+)".
+func collapseValueLists(parts []fingerprintPart) []fingerprintPart {
+	text := func(i int) string {
+		if i < 0 || i >= len(parts) {
+			return ""
+		}
+		return parts[i].text
+	}
+	out := make([]fingerprintPart, 0, len(parts))
+	for i := 0; i < len(parts); i++ {
+		if text(i) == "(" {
+			j := i + 1
+			for text(j) == "?" && text(j+1) == "," {
+				j += 2
+			}
+			if text(j) == "?" && text(j+1) == ")" && j > i+1 {
+				out = append(out, parts[i], fingerprintPart{text: "?+"}, fingerprintPart{text: ")"})
+				i = j + 1
+				continue
+			}
+		}
+		out = append(out, parts[i])
+	}
+
+	isList := func(ps []fingerprintPart) bool {
+		return ps[0].text == "(" && ps[1].text == "?+" && ps[2].text == ")"
+	}
+	deduplicated := make([]fingerprintPart, 0, len(out))
+	for i := 0; i < len(out); i++ {
+		n := len(deduplicated)
+		if out[i].text == "," && i+3 < len(out) && isList(out[i+1:i+4]) && n >= 3 && isList(deduplicated[n-3:]) {
+			i += 3
+			continue
+		}
+		deduplicated = append(deduplicated, out[i])
+	}
+	return deduplicated
+}
+
+func needSpace(prev, cur string) bool {
+	switch {
+	case cur == "," || cur == ")" || cur == "." || cur == "::" || cur == "]":
+		return false
+	case prev == "(" || prev == "." || prev == "::" || prev == "[":
+		return false
+	case cur == "(" || cur == "[":
+		// function call or type modifier, such as count(*) or varchar(10)
+		return !isIdentLike(prev)
+	}
+	return true
+}
+
+func isIdentLike(s string) bool {
+	if s == "" {
+		return false
+	}
+	r := rune(s[0])
+	return isIdentStart(r) || s[0] == '"'
+}
@@ -0,0 +1,339 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tableName struct {
+	schema string
+	name   string
+}
+
+func (t tableName) String() string {
+	if t.schema == "" {
+		return t.name
+	}
+	return fmt.Sprintf("%s.%s", t.schema, t.name)
+}
+
+// quoted returns the table name quoted as identifier, it is safe to be used in SQL.
+func (t tableName) quoted() string {
+	if t.schema == "" {
+		return quoteIdent(t.name)
+	}
+	return fmt.Sprintf("%s.%s", quoteIdent(t.schema), quoteIdent(t.name))
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// parseQualifiedName parses "[catalog.][schema.]name" from i, it returns the
+// name and the index after it. ok is false if there is no name at i.
+func (s *stmt) parseQualifiedName(i int) (name tableName, next int, ok bool) {
+	if !s.at(i).isName() {
+		return tableName{}, i, false
+	}
+	parts := []string{s.at(i).ident()}
+	next = i + 1
+	for s.at(next).isPunct(".") && s.at(next+1).isName() {
+		parts = append(parts, s.at(next+1).ident())
+		next += 2
+	}
+	name.name = parts[len(parts)-1]
+	if len(parts) > 1 {
+		name.schema = parts[len(parts)-2]
+	}
+	return name, next, true
+}
+
+// parseNameList parses a comma separated qualified name list from i.
+func (s *stmt) parseNameList(i int) ([]tableName, int) {
+	var names []tableName
+	for {
+		i = s.skip(i, "only")
+		name, next, ok := s.parseQualifiedName(i)
+		if !ok {
+			return names, i
+		}
+		names = append(names, name)
+		i = next
+		if s.at(i).isOperator("*") {
+			i++
+		}
+		if !s.at(i).isPunct(",") {
+			return names, i
+		}
+		i++
+	}
+}
+
+// skipIfExists skips "IF EXISTS" or "IF NOT EXISTS" from i.
+func (s *stmt) skipIfExists(i int) (int, bool) {
+	if j := s.skip(i, "if", "not", "exists"); j != i {
+		return j, true
+	}
+	if j := s.skip(i, "if", "exists"); j != i {
+		return j, true
+	}
+	return i, false
+}
+
+// ifNotExists reports whether the CREATE statement has "IF NOT EXISTS" before the object name.
+func (s *stmt) ifNotExists() bool {
+	exists := s.topLevelIndex(0, "exists")
+	return exists >= 0 && exists < s.objectNameIndex()
+}
+
+// objectNameIndex returns the index of the name of the object which the
+// CREATE/ALTER/DROP statement works on.
+func (s *stmt) objectNameIndex() int {
+	i := s.mainVerbIndex() + 1
+	i = s.skip(i, "or", "replace")
+	for s.at(i).is("global", "local", "temp", "temporary", "unlogged", "unique", "recursive", "materialized") {
+		i++
+	}
+	// object type, such as TABLE/INDEX/VIEW
+	i++
+	i = s.skip(i, "concurrently")
+	i, _ = s.skipIfExists(i)
+	return s.skip(i, "only")
+}
+
+// cteNames returns names defined by the WITH clause.
+func (s *stmt) cteNames() map[string]struct{} {
+	names := map[string]struct{}{}
+	if !s.at(0).is("with") {
+		return names
+	}
+	end := s.mainVerbIndex()
+	i := s.skip(1, "recursive")
+	for i < end {
+		if s.at(i).isName() {
+			names[s.at(i).ident()] = struct{}{}
+		}
+		as := s.topLevelIndex(i, "as")
+		if as < 0 || as >= end {
+			break
+		}
+		i = as + 1
+		for !s.at(i).isPunct("(") && i < end {
+			i++
+		}
+		i = s.matchingParen(i) + 1
+		if !s.at(i).isPunct(",") {
+			break
+		}
+		i++
+	}
+	return names
+}
+
+var tableRefKeywords = map[string]struct{}{
+	"from": {}, "join": {}, "update": {}, "into": {}, "using": {}, "table": {},
+}
+
+// functions in which FROM is a part of the arguments.
+var functionsWithFromSyntax = map[string]struct{}{
+	"extract": {}, "substring": {}, "trim": {}, "overlay": {}, "position": {},
+}
+
+// aliasTerminators are keywords which can not be a table alias.
+var aliasTerminators = map[string]struct{}{
+	"where": {}, "join": {}, "inner": {}, "left": {}, "right": {}, "full": {}, "cross": {}, "natural": {},
+	"on": {}, "using": {}, "group": {}, "order": {}, "limit": {}, "offset": {}, "having": {}, "window": {},
+	"union": {}, "intersect": {}, "except": {}, "set": {}, "returning": {}, "for": {}, "fetch": {},
+	"values": {}, "select": {}, "default": {}, "overriding": {}, "lateral": {}, "tablesample": {},
+	"when": {}, "do": {},
+}
+
+// tables returns all tables referenced by the statement, tables defined by CTE
+// are excluded and duplicated tables are removed.
+func (s *stmt) tables() []tableName {
+	var names []tableName
+	switch s.kind {
+	case stmtCreateTable, stmtAlterTable, stmtCreateView, stmtCreateSequence:
+		if name, _, ok := s.parseQualifiedName(s.objectNameIndex()); ok {
+			names = append(names, name)
+		}
+		if s.kind == stmtCreateTable || s.kind == stmtCreateView {
+			names = append(names, s.referencedTables()...)
+		}
+	case stmtDropTable, stmtDropView, stmtTruncate:
+		i := s.objectNameIndex()
+		if s.kind == stmtTruncate {
+			i = s.skip(s.skip(1, "table"), "only")
+		}
+		names, _ = s.parseNameList(i)
+	case stmtCreateIndex:
+		if on := s.topLevelIndex(0, "on"); on >= 0 {
+			if name, _, ok := s.parseQualifiedName(s.skip(on+1, "only")); ok {
+				names = append(names, name)
+			}
+		}
+	default:
+		if s.isDML() {
+			names = s.referencedTables()
+		}
+	}
+	return uniqueTables(names)
+}
+
+// referencedTables finds tables after FROM/JOIN/UPDATE/INTO/USING keywords.
+func (s *stmt) referencedTables() []tableName {
+	ctes := s.cteNames()
+	var names []tableName
+	for i := 0; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		if t.kind != tokenIdent {
+			continue
+		}
+		if _, ok := tableRefKeywords[strings.ToLower(t.text)]; !ok {
+			continue
+		}
+		if t.is("from") && s.insideFunctionWithFrom(i) {
+			continue
+		}
+		// "DELETE ... USING" and "MERGE ... USING" reference tables, "JOIN ... USING (col)" does not.
+		if t.is("using") && s.at(i+1).isPunct("(") {
+			continue
+		}
+		// "TABLE name" is a statement only when it is the main verb.
+		if t.is("table") && i != s.mainVerbIndex() {
+			continue
+		}
+		// "UPDATE" in "FOR UPDATE", "ON CONFLICT DO UPDATE" and "WHEN MATCHED THEN UPDATE" is not a table reference.
+		if t.is("update") && i != s.mainVerbIndex() && !s.at(i-1).isPunct("(") {
+			continue
+		}
+		// "INSERT INTO" and "MERGE INTO" reference tables, "SELECT ... INTO new_table" creates one.
+		if t.is("into") && !s.at(i-1).is("insert", "merge") {
+			continue
+		}
+		j := i + 1
+		for {
+			j = s.skip(j, "only")
+			j = s.skip(j, "lateral")
+			if s.at(j).isPunct("(") {
+				// sub query, it will be visited by the outer loop
+				j = s.matchingParen(j) + 1
+			} else {
+				name, next, ok := s.parseQualifiedName(j)
+				if !ok {
+					break
+				}
+				if s.at(next).isPunct("(") && !t.is("into") {
+					// table function, such as generate_series(1, 10)
+					j = s.matchingParen(next) + 1
+				} else {
+					if _, isCTE := ctes[name.name]; !isCTE || name.schema != "" {
+						names = append(names, name)
+					}
+					j = next
+				}
+			}
+			j = s.skipAlias(j)
+			if s.isInFromList(i) {
+				// "FROM a JOIN b ON a.id = b.id, c", the table list continues after the join condition.
+				j = s.skipJoinCondition(j)
+			}
+			if !s.at(j).isPunct(",") || !s.isInFromList(i) {
+				break
+			}
+			j++
+		}
+	}
+	return names
+}
+
+// isInFromList reports whether the keyword at i starts a comma separated table list.
+func (s *stmt) isInFromList(i int) bool {
+	return s.at(i).is("from", "using")
+}
+
+// skipJoinCondition skips joined tables and their ON/USING conditions from i,
+// joined tables are visited by the outer loop with JOIN keyword.
+func (s *stmt) skipJoinCondition(i int) int {
+	depth := 0
+	for ; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case depth > 0:
+		case t.isPunct(","):
+			return i
+		case t.kind == tokenIdent:
+			if _, ok := fromListTerminators[t.ident()]; ok {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// fromListTerminators are keywords which end the FROM list.
+var fromListTerminators = map[string]struct{}{
+	"where": {}, "group": {}, "order": {}, "limit": {}, "offset": {}, "having": {}, "window": {},
+	"union": {}, "intersect": {}, "except": {}, "returning": {}, "for": {}, "fetch": {}, "set": {},
+	"when": {}, "do": {}, "select": {}, "values": {},
+}
+
+func (s *stmt) skipAlias(i int) int {
+	if s.at(i).isOperator("*") {
+		i++
+	}
+	if s.at(i).is("as") {
+		i++
+	}
+	if s.at(i).isName() {
+		if _, ok := aliasTerminators[s.at(i).ident()]; !ok || s.at(i).kind == tokenQuotedIdent {
+			i++
+			if s.at(i).isPunct("(") {
+				i = s.matchingParen(i) + 1
+			}
+		}
+	}
+	return i
+}
+
+// insideFunctionWithFrom reports whether token i is inside a function call like EXTRACT(... FROM ...).
+func (s *stmt) insideFunctionWithFrom(i int) bool {
+	depth := 0
+	for j := i - 1; j >= 0; j-- {
+		switch {
+		case s.tokens[j].isPunct(")"):
+			depth++
+		case s.tokens[j].isPunct("("):
+			if depth == 0 {
+				_, ok := functionsWithFromSyntax[s.at(j-1).ident()]
+				return ok && s.at(j-1).kind == tokenIdent
+			}
+			depth--
+		}
+	}
+	return false
+}
+
+func uniqueTables(names []tableName) []tableName {
+	seen := map[tableName]struct{}{}
+	result := make([]tableName, 0, len(names))
+	for _, n := range names {
+		if _, ok := seen[n]; ok {
+			continue
+		}
+		seen[n] = struct{}{}
+		result = append(result, n)
+	}
+	return result
+}
@@ -1,8 +1,10 @@
 package driverV2
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"sync"
 
 	protoV2 "github.com/actiontech/sqle/sqle/driver/v2/proto"
@@ -40,6 +42,18 @@ type Rule struct {
 	Level     RuleLevel
 	Params    params.Params
 	Knowledge RuleKnowledge
+
+	// I18nRuleInfo is the rule info in other languages, keyed by language such
+	// as "en". The fields above are written in Chinese.
+	I18nRuleInfo map[string]*RuleI18nInfo
+}
+
+type RuleI18nInfo struct {
+	Desc       string
+	Annotation string
+	Category   string
+	// Params is the description of params, keyed by param key.
+	Params map[string]string
 }
 
 type Config struct {
@@ -89,6 +103,7 @@ func (d *DriverGrpcServer) Metas(ctx context.Context, req *protoV2.Empty) (*prot
 		DatabaseAdditionalParams: ConvertParamToProtoParam(d.Meta.DatabaseAdditionalParams),
 		Rules:                    rules,
 		EnabledOptionalModule:    ms,
+		Capabilities:             []protoV2.Capability{protoV2.Capability_StreamAudit},
 	}, nil
 }
 
@@ -195,9 +210,12 @@ func (d *DriverGrpcServer) Audit(ctx context.Context, req *protoV2.AuditRequest)
 		}
 		for _, result := range results.Results {
 			rets.Results = append(rets.Results, &protoV2.AuditResult{
-				Level:    string(result.Level),
-				Message:  result.Message,
-				RuleName: result.RuleName,
+				Level:        string(result.Level),
+				Message:      result.Message,
+				RuleName:     result.RuleName,
+				MessageId:    result.MessageId,
+				MessageArgs:  result.MessageArgs,
+				I18NMessages: result.I18nMessages,
 			})
 		}
 		resp.AuditResults = append(resp.AuditResults, rets)
@@ -205,6 +223,66 @@ func (d *DriverGrpcServer) Audit(ctx context.Context, req *protoV2.AuditRequest)
 	return resp, nil
 }
 
+func (d *DriverGrpcServer) ParseStream(stream protoV2.Driver_ParseStreamServer) error {
+	var session *protoV2.Session
+	var sql bytes.Buffer
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			session = req.Session
+		}
+		sql.Write(req.SqlChunk)
+	}
+	if session == nil {
+		return ErrSQLisEmpty
+	}
+
+	resp, err := d.Parse(stream.Context(), &protoV2.ParseRequest{
+		Session: session,
+		Sql:     &protoV2.ParsedSQL{Query: sql.String()},
+	})
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(resp.Nodes); i += StreamParseChunkCount {
+		end := i + StreamParseChunkCount
+		if end > len(resp.Nodes) {
+			end = len(resp.Nodes)
+		}
+		if err := stream.Send(&protoV2.ParseResponse{Nodes: resp.Nodes[i:end]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AuditStream audits the chunks of SQLs in order with the same driver, so the
+// context of the former chunks is kept.
+func (d *DriverGrpcServer) AuditStream(stream protoV2.Driver_AuditStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp, err := d.Audit(stream.Context(), req)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
 func (d *DriverGrpcServer) GenRollbackSQL(ctx context.Context, req *protoV2.GenRollbackSQLRequest) (*protoV2.GenRollbackSQLResponse, error) {
 	driver, err := d.getDriverBySession(req.Session)
 	if err != nil {
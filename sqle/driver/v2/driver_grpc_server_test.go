@@ -0,0 +1,139 @@
+package driverV2
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	protoV2 "github.com/actiontech/sqle/sqle/driver/v2/proto"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// auditDriver audits the SQL with the count of SQLs audited before it, to check
+// the chunks are audited in order with the same driver.
+type auditDriver struct {
+	Driver
+	audited int
+}
+
+func (d *auditDriver) Close(ctx context.Context) {}
+
+func (d *auditDriver) Parse(ctx context.Context, sql string) ([]Node, error) {
+	nodes := []Node{}
+	for _, text := range strings.Split(sql, ";") {
+		if text = strings.TrimSpace(text); text != "" {
+			nodes = append(nodes, Node{Text: text, Type: SQLTypeDQL, Fingerprint: text})
+		}
+	}
+	return nodes, nil
+}
+
+func (d *auditDriver) Audit(ctx context.Context, sqls []string) ([]*AuditResults, error) {
+	rets := []*AuditResults{}
+	for _, sql := range sqls {
+		ret := NewAuditResults()
+		ret.Add(RuleLevelNotice, "rule", fmt.Sprintf("%d:%s", d.audited, sql))
+		rets = append(rets, ret)
+		d.audited++
+	}
+	return rets, nil
+}
+
+func newTestDriverClient(t *testing.T) (protoV2.DriverClient, *protoV2.Session) {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	protoV2.RegisterDriverServer(s, &DriverGrpcServer{
+		DriverFactory: func(*Config) (Driver, error) { return &auditDriver{}, nil },
+		Drivers:       map[string]Driver{},
+	})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	c := protoV2.NewDriverClient(conn)
+	metas, err := c.Metas(context.TODO(), &protoV2.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, []protoV2.Capability{protoV2.Capability_StreamAudit}, metas.Capabilities)
+	// the default value of enum is not a capability
+	assert.NotEqual(t, protoV2.Capability(0), protoV2.Capability_StreamAudit)
+
+	resp, err := c.Init(context.TODO(), &protoV2.InitRequest{})
+	assert.NoError(t, err)
+	return c, resp.Session
+}
+
+func TestDriverGrpcServer_ParseStream(t *testing.T) {
+	c, session := newTestDriverClient(t)
+
+	sqls := []string{}
+	for i := 0; i < StreamParseChunkCount+1; i++ {
+		sqls = append(sqls, fmt.Sprintf("select %d", i))
+	}
+	text := strings.Join(sqls, ";")
+	stream, err := c.ParseStream(context.TODO())
+	assert.NoError(t, err)
+	// split the text in the middle of SQL.
+	for _, chunk := range []string{text[:5], text[5:]} {
+		assert.NoError(t, stream.Send(&protoV2.ParseStreamRequest{Session: session, SqlChunk: []byte(chunk)}))
+	}
+	assert.NoError(t, stream.CloseSend())
+
+	chunks := 0
+	nodes := []string{}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		chunks++
+		for _, node := range resp.Nodes {
+			nodes = append(nodes, node.Text)
+		}
+	}
+	assert.Equal(t, 2, chunks)
+	assert.Equal(t, sqls, nodes)
+}
+
+func TestDriverGrpcServer_AuditStream(t *testing.T) {
+	c, session := newTestDriverClient(t)
+
+	stream, err := c.AuditStream(context.TODO())
+	assert.NoError(t, err)
+	messages := []string{}
+	for _, chunk := range [][]string{{"select 1", "select 2"}, {"select 3"}} {
+		req := &protoV2.AuditRequest{Session: session}
+		for _, sql := range chunk {
+			req.Sqls = append(req.Sqls, &protoV2.AuditSQL{Query: sql})
+		}
+		assert.NoError(t, stream.Send(req))
+		resp, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Len(t, resp.AuditResults, len(chunk))
+		for _, results := range resp.AuditResults {
+			messages = append(messages, results.Results[0].Message)
+		}
+	}
+	assert.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"0:select 1", "1:select 2", "2:select 3"}, messages)
+
+	// the error of session is returned by stream.
+	stream, err = c.AuditStream(context.TODO())
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(&protoV2.AuditRequest{Session: &protoV2.Session{Id: "unknown"}, Sqls: []*protoV2.AuditSQL{{Query: "select 1"}}}))
+	_, err = stream.Recv()
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/actiontech/sqle/sqle/driver/common"
 	protoV2 "github.com/actiontech/sqle/sqle/driver/v2/proto"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/pkg/params"
 
 	goPlugin "github.com/hashicorp/go-plugin"
@@ -151,6 +152,14 @@ type AuditResult struct {
 	Level    RuleLevel
 	Message  string
 	RuleName string
+
+	// MessageId is the id of message pattern in locale catalogue, the message
+	// can be rendered in other languages with MessageArgs.
+	MessageId   string
+	MessageArgs []string
+	// I18nMessages is the message in other languages, keyed by language. It is
+	// set by the plugins which localize the messages themselves.
+	I18nMessages map[string]string
 }
 
 func NewAuditResults() *AuditResults {
@@ -202,11 +211,19 @@ func (rs *AuditResults) Add(level RuleLevel, ruleName string, messagePattern str
 	if len(args) > 0 {
 		message = fmt.Sprintf(message, args...)
 	}
-	rs.Results = append(rs.Results, &AuditResult{
+	result := &AuditResult{
 		Level:    level,
 		Message:  message,
 		RuleName: ruleName,
-	})
+	}
+	// keep the message id, so the result can be rendered in other languages
+	if id, ok := locale.IdOf(messagePattern); ok {
+		result.MessageId = id
+		for _, arg := range args {
+			result.MessageArgs = append(result.MessageArgs, fmt.Sprint(arg))
+		}
+	}
+	rs.Results = append(rs.Results, result)
 	rs.SortByLevel()
 }
 
@@ -0,0 +1,31 @@
+package driverV2
+
+import "github.com/actiontech/sqle/sqle/locale"
+
+// RegisterRuleMessages adds the desc, annotation, category and param descriptions
+// of rules to locale catalogue, the rules in other languages are taken from
+// I18nRuleInfo.
+func RegisterRuleMessages(dbType string, rules []*Rule) {
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		locale.Register(locale.SourceLang, locale.RuleMessageId(dbType, rule.Name, locale.RuleFieldDesc), rule.Desc)
+		locale.Register(locale.SourceLang, locale.RuleMessageId(dbType, rule.Name, locale.RuleFieldAnnotation), rule.Annotation)
+		locale.Register(locale.SourceLang, locale.RuleMessageId(dbType, rule.Name, locale.RuleFieldCategory), rule.Category)
+		for _, p := range rule.Params {
+			locale.Register(locale.SourceLang, locale.RuleParamMessageId(dbType, rule.Name, p.Key), p.Desc)
+		}
+		for lang, info := range rule.I18nRuleInfo {
+			if info == nil {
+				continue
+			}
+			locale.Register(lang, locale.RuleMessageId(dbType, rule.Name, locale.RuleFieldDesc), info.Desc)
+			locale.Register(lang, locale.RuleMessageId(dbType, rule.Name, locale.RuleFieldAnnotation), info.Annotation)
+			locale.Register(lang, locale.RuleMessageId(dbType, rule.Name, locale.RuleFieldCategory), info.Category)
+			for key, desc := range info.Params {
+				locale.Register(lang, locale.RuleParamMessageId(dbType, rule.Name, key), desc)
+			}
+		}
+	}
+}
@@ -70,6 +70,8 @@ It has these top-level messages:
 	EstimateSQLAffectRowsRequest
 	EstimateSQLAffectRowsResponse
 	KillProcessResponse
+	ParseStreamRequest
+	I18NRuleInfo
 */
 package protoV2
 
@@ -132,6 +134,29 @@ func (x OptionalModule) String() string {
 }
 func (OptionalModule) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
+// stream
+type Capability int32
+
+const (
+	// the zero value is reserved, so an unset capability is not taken as supported.
+	Capability_CAPABILITY_UNSPECIFIED Capability = 0
+	Capability_StreamAudit            Capability = 1
+)
+
+var Capability_name = map[int32]string{
+	0: "CAPABILITY_UNSPECIFIED",
+	1: "StreamAudit",
+}
+var Capability_value = map[string]int32{
+	"CAPABILITY_UNSPECIFIED": 0,
+	"StreamAudit":            1,
+}
+
+func (x Capability) String() string {
+	return proto.EnumName(Capability_name, int32(x))
+}
+func (Capability) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
 type Empty struct {
 }
 
@@ -260,6 +285,8 @@ type Rule struct {
 	Params     []*Param   `protobuf:"bytes,5,rep,name=params" json:"params,omitempty"`
 	Annotation string     `protobuf:"bytes,6,opt,name=annotation" json:"annotation,omitempty"`
 	Knowledge  *Knowledge `protobuf:"bytes,7,opt,name=knowledge" json:"knowledge,omitempty"`
+	// the rule info in other languages, keyed by language such as "en"
+	I18NRuleInfo map[string]*I18NRuleInfo `protobuf:"bytes,8,rep,name=i18nRuleInfo" json:"i18nRuleInfo,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *Rule) Reset()                    { *m = Rule{} }
@@ -316,6 +343,13 @@ func (m *Rule) GetKnowledge() *Knowledge {
 	return nil
 }
 
+func (m *Rule) GetI18NRuleInfo() map[string]*I18NRuleInfo {
+	if m != nil {
+		return m.I18NRuleInfo
+	}
+	return nil
+}
+
 type Knowledge struct {
 	Content string `protobuf:"bytes,1,opt,name=content" json:"content,omitempty"`
 }
@@ -340,6 +374,7 @@ type MetasResponse struct {
 	Rules                    []*Rule          `protobuf:"bytes,4,rep,name=rules" json:"rules,omitempty"`
 	EnabledOptionalModule    []OptionalModule `protobuf:"varint,5,rep,packed,name=enabledOptionalModule,enum=protoV2.OptionalModule" json:"enabledOptionalModule,omitempty"`
 	Logo                     []byte           `protobuf:"bytes,6,opt,name=logo,proto3" json:"logo,omitempty"`
+	Capabilities             []Capability     `protobuf:"varint,7,rep,packed,name=capabilities,enum=protoV2.Capability" json:"capabilities,omitempty"`
 }
 
 func (m *MetasResponse) Reset()                    { *m = MetasResponse{} }
@@ -389,6 +424,13 @@ func (m *MetasResponse) GetLogo() []byte {
 	return nil
 }
 
+func (m *MetasResponse) GetCapabilities() []Capability {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
 // Init
 type InitRequest struct {
 	Dsn   *DSN    `protobuf:"bytes,1,opt,name=dsn" json:"dsn,omitempty"`
@@ -611,9 +653,13 @@ func (m *AuditRequest) GetSqls() []*AuditSQL {
 }
 
 type AuditResult struct {
-	Message  string `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
-	Level    string `protobuf:"bytes,2,opt,name=level" json:"level,omitempty"`
-	RuleName string `protobuf:"bytes,3,opt,name=rule_name,json=ruleName" json:"rule_name,omitempty"`
+	Message     string   `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
+	Level       string   `protobuf:"bytes,2,opt,name=level" json:"level,omitempty"`
+	RuleName    string   `protobuf:"bytes,3,opt,name=rule_name,json=ruleName" json:"rule_name,omitempty"`
+	MessageId   string   `protobuf:"bytes,4,opt,name=messageId" json:"messageId,omitempty"`
+	MessageArgs []string `protobuf:"bytes,5,rep,name=messageArgs" json:"messageArgs,omitempty"`
+	// the message in other languages, keyed by language such as "en"
+	I18NMessages map[string]string `protobuf:"bytes,6,rep,name=i18nMessages" json:"i18nMessages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *AuditResult) Reset()                    { *m = AuditResult{} }
@@ -642,6 +688,27 @@ func (m *AuditResult) GetRuleName() string {
 	return ""
 }
 
+func (m *AuditResult) GetMessageId() string {
+	if m != nil {
+		return m.MessageId
+	}
+	return ""
+}
+
+func (m *AuditResult) GetMessageArgs() []string {
+	if m != nil {
+		return m.MessageArgs
+	}
+	return nil
+}
+
+func (m *AuditResult) GetI18NMessages() map[string]string {
+	if m != nil {
+		return m.I18NMessages
+	}
+	return nil
+}
+
 type AuditResults struct {
 	Results []*AuditResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
 }
@@ -1532,6 +1599,73 @@ func (m *KillProcessResponse) GetErrMessage() string {
 	return ""
 }
 
+type ParseStreamRequest struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	// sqlChunk is a part of SQL text, the text is parsed after all chunks are
+	// received.
+	SqlChunk []byte `protobuf:"bytes,2,opt,name=sqlChunk,proto3" json:"sqlChunk,omitempty"`
+}
+
+func (m *ParseStreamRequest) Reset()                    { *m = ParseStreamRequest{} }
+func (m *ParseStreamRequest) String() string            { return proto.CompactTextString(m) }
+func (*ParseStreamRequest) ProtoMessage()               {}
+func (*ParseStreamRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *ParseStreamRequest) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *ParseStreamRequest) GetSqlChunk() []byte {
+	if m != nil {
+		return m.SqlChunk
+	}
+	return nil
+}
+
+type I18NRuleInfo struct {
+	Desc       string `protobuf:"bytes,1,opt,name=desc" json:"desc,omitempty"`
+	Annotation string `protobuf:"bytes,2,opt,name=annotation" json:"annotation,omitempty"`
+	Category   string `protobuf:"bytes,3,opt,name=category" json:"category,omitempty"`
+	// the description of params, keyed by param key
+	Params map[string]string `protobuf:"bytes,4,rep,name=params" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *I18NRuleInfo) Reset()                    { *m = I18NRuleInfo{} }
+func (m *I18NRuleInfo) String() string            { return proto.CompactTextString(m) }
+func (*I18NRuleInfo) ProtoMessage()               {}
+func (*I18NRuleInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+
+func (m *I18NRuleInfo) GetDesc() string {
+	if m != nil {
+		return m.Desc
+	}
+	return ""
+}
+
+func (m *I18NRuleInfo) GetAnnotation() string {
+	if m != nil {
+		return m.Annotation
+	}
+	return ""
+}
+
+func (m *I18NRuleInfo) GetCategory() string {
+	if m != nil {
+		return m.Category
+	}
+	return ""
+}
+
+func (m *I18NRuleInfo) GetParams() map[string]string {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Empty)(nil), "protoV2.Empty")
 	proto.RegisterType((*Session)(nil), "protoV2.Session")
@@ -1595,7 +1729,10 @@ func init() {
 	proto.RegisterType((*EstimateSQLAffectRowsRequest)(nil), "protoV2.EstimateSQLAffectRowsRequest")
 	proto.RegisterType((*EstimateSQLAffectRowsResponse)(nil), "protoV2.EstimateSQLAffectRowsResponse")
 	proto.RegisterType((*KillProcessResponse)(nil), "protoV2.KillProcessResponse")
+	proto.RegisterType((*ParseStreamRequest)(nil), "protoV2.ParseStreamRequest")
+	proto.RegisterType((*I18NRuleInfo)(nil), "protoV2.I18nRuleInfo")
 	proto.RegisterEnum("protoV2.OptionalModule", OptionalModule_name, OptionalModule_value)
+	proto.RegisterEnum("protoV2.Capability", Capability_name, Capability_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1621,6 +1758,11 @@ type DriverClient interface {
 	// db audit
 	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
 	Audit(ctx context.Context, in *AuditRequest, opts ...grpc.CallOption) (*AuditResponse, error)
+	// ParseStream and AuditStream are Parse and Audit for large SQL text, the
+	// request and response are sent in chunks. They are called only if the
+	// plugin reports the capability StreamAudit in MetasResponse.
+	ParseStream(ctx context.Context, opts ...grpc.CallOption) (Driver_ParseStreamClient, error)
+	AuditStream(ctx context.Context, opts ...grpc.CallOption) (Driver_AuditStreamClient, error)
 	GenRollbackSQL(ctx context.Context, in *GenRollbackSQLRequest, opts ...grpc.CallOption) (*GenRollbackSQLResponse, error)
 	// db executor
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*Empty, error)
@@ -1698,6 +1840,68 @@ func (c *driverClient) Audit(ctx context.Context, in *AuditRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *driverClient) ParseStream(ctx context.Context, opts ...grpc.CallOption) (Driver_ParseStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Driver_serviceDesc.Streams[0], c.cc, "/protoV2.Driver/ParseStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &driverParseStreamClient{stream}
+	return x, nil
+}
+
+type Driver_ParseStreamClient interface {
+	Send(*ParseStreamRequest) error
+	Recv() (*ParseResponse, error)
+	grpc.ClientStream
+}
+
+type driverParseStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *driverParseStreamClient) Send(m *ParseStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *driverParseStreamClient) Recv() (*ParseResponse, error) {
+	m := new(ParseResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *driverClient) AuditStream(ctx context.Context, opts ...grpc.CallOption) (Driver_AuditStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Driver_serviceDesc.Streams[1], c.cc, "/protoV2.Driver/AuditStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &driverAuditStreamClient{stream}
+	return x, nil
+}
+
+type Driver_AuditStreamClient interface {
+	Send(*AuditRequest) error
+	Recv() (*AuditResponse, error)
+	grpc.ClientStream
+}
+
+type driverAuditStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *driverAuditStreamClient) Send(m *AuditRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *driverAuditStreamClient) Recv() (*AuditResponse, error) {
+	m := new(AuditResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *driverClient) GenRollbackSQL(ctx context.Context, in *GenRollbackSQLRequest, opts ...grpc.CallOption) (*GenRollbackSQLResponse, error) {
 	out := new(GenRollbackSQLResponse)
 	err := grpc.Invoke(ctx, "/protoV2.Driver/GenRollbackSQL", in, out, c.cc, opts...)
@@ -1812,6 +2016,11 @@ type DriverServer interface {
 	// db audit
 	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
 	Audit(context.Context, *AuditRequest) (*AuditResponse, error)
+	// ParseStream and AuditStream are Parse and Audit for large SQL text, the
+	// request and response are sent in chunks. They are called only if the
+	// plugin reports the capability StreamAudit in MetasResponse.
+	ParseStream(Driver_ParseStreamServer) error
+	AuditStream(Driver_AuditStreamServer) error
 	GenRollbackSQL(context.Context, *GenRollbackSQLRequest) (*GenRollbackSQLResponse, error)
 	// db executor
 	Ping(context.Context, *PingRequest) (*Empty, error)
@@ -1939,6 +2148,58 @@ func _Driver_Audit_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Driver_ParseStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DriverServer).ParseStream(&driverParseStreamServer{stream})
+}
+
+type Driver_ParseStreamServer interface {
+	Send(*ParseResponse) error
+	Recv() (*ParseStreamRequest, error)
+	grpc.ServerStream
+}
+
+type driverParseStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *driverParseStreamServer) Send(m *ParseResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *driverParseStreamServer) Recv() (*ParseStreamRequest, error) {
+	m := new(ParseStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Driver_AuditStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DriverServer).AuditStream(&driverAuditStreamServer{stream})
+}
+
+type Driver_AuditStreamServer interface {
+	Send(*AuditResponse) error
+	Recv() (*AuditRequest, error)
+	grpc.ServerStream
+}
+
+type driverAuditStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *driverAuditStreamServer) Send(m *AuditResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *driverAuditStreamServer) Recv() (*AuditRequest, error) {
+	m := new(AuditRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Driver_GenRollbackSQL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GenRollbackSQLRequest)
 	if err := dec(in); err != nil {
@@ -2210,134 +2471,164 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Driver_EstimateSQLAffectRows_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ParseStream",
+			Handler:       _Driver_ParseStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "AuditStream",
+			Handler:       _Driver_AuditStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "driver_v2.proto",
 }
 
 func init() { proto.RegisterFile("driver_v2.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1945 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x18, 0xdb, 0x72, 0x14, 0xc7,
-	0x35, 0x7b, 0xd7, 0x9e, 0xbd, 0x68, 0xdd, 0x92, 0x60, 0x59, 0x40, 0x56, 0x1a, 0x90, 0x55, 0x98,
-	0x08, 0x2c, 0x12, 0x5c, 0x98, 0x24, 0x25, 0x2c, 0x29, 0x20, 0x03, 0x8a, 0x68, 0x11, 0x52, 0xe5,
-	0x2a, 0x97, 0xdd, 0xda, 0x69, 0x89, 0x29, 0x66, 0xa7, 0x57, 0xd3, 0xbd, 0x48, 0xfa, 0x82, 0xe4,
-	0x3b, 0xf2, 0x05, 0x79, 0xf1, 0x63, 0x3e, 0x24, 0xf9, 0x9a, 0x54, 0x5f, 0x66, 0xa6, 0x67, 0x76,
-	0xd6, 0x36, 0x5b, 0xe5, 0xa7, 0xe9, 0x3e, 0xb7, 0x3e, 0xb7, 0x3e, 0x73, 0x4e, 0xc3, 0xa2, 0x17,
-	0xf9, 0x1f, 0x58, 0xf4, 0xfd, 0x87, 0xad, 0xcd, 0x71, 0xc4, 0x25, 0x47, 0x0d, 0xfd, 0x79, 0xbb,
-	0x85, 0x1b, 0x50, 0xdb, 0x1b, 0x8d, 0xe5, 0x25, 0xbe, 0x06, 0x8d, 0x23, 0x26, 0x84, 0xcf, 0x43,
-	0xd4, 0x85, 0xb2, 0xef, 0xf5, 0x4b, 0x6b, 0xa5, 0x8d, 0x26, 0x29, 0xfb, 0x1e, 0xfe, 0x3b, 0xd4,
-	0x0e, 0x69, 0x44, 0x47, 0xa8, 0x07, 0x95, 0xf7, 0xec, 0xd2, 0x62, 0xd4, 0x12, 0x2d, 0x43, 0xed,
-	0x03, 0x0d, 0x26, 0xac, 0x5f, 0xd6, 0x30, 0xb3, 0x41, 0x08, 0xaa, 0x1e, 0x13, 0xc3, 0x7e, 0x45,
-	0x03, 0xf5, 0x5a, 0xc1, 0xe4, 0xe5, 0x98, 0xf5, 0xab, 0x06, 0xa6, 0xd6, 0xf8, 0xc7, 0x12, 0x54,
-	0x76, 0x8f, 0x0e, 0x14, 0xee, 0x1d, 0x17, 0xd2, 0x0a, 0xd6, 0x6b, 0x05, 0x1b, 0xf3, 0x48, 0x5a,
-	0xc1, 0x7a, 0xad, 0x60, 0x13, 0xc1, 0xa2, 0x58, 0xae, 0x5a, 0xa3, 0x01, 0x2c, 0x8c, 0xa9, 0x10,
-	0xe7, 0x3c, 0xf2, 0xac, 0xec, 0x64, 0xaf, 0x70, 0x1e, 0x95, 0xf4, 0x98, 0x0a, 0xd6, 0xaf, 0x19,
-	0x5c, 0xbc, 0x47, 0x5f, 0x41, 0x8f, 0x7a, 0x9e, 0x2f, 0x7d, 0x1e, 0xd2, 0x40, 0x9b, 0x27, 0xfa,
-	0xf5, 0xb5, 0xca, 0x46, 0x6b, 0xab, 0xbb, 0x69, 0x9d, 0xb3, 0xa9, 0xc1, 0x64, 0x8a, 0x0e, 0xff,
-	0xaf, 0x04, 0x55, 0x32, 0x09, 0xb4, 0xa1, 0x21, 0x1d, 0xb1, 0x58, 0x71, 0xb5, 0x4e, 0x8c, 0x2f,
-	0x3b, 0xc6, 0x2f, 0x43, 0x2d, 0x60, 0x1f, 0x58, 0x60, 0x35, 0x37, 0x1b, 0xa5, 0xde, 0x90, 0x4a,
-	0x76, 0xca, 0xa3, 0xcb, 0x58, 0xf5, 0x78, 0x8f, 0xd6, 0xa1, 0x3e, 0x36, 0x4a, 0xd5, 0x0a, 0x95,
-	0xb2, 0x58, 0xb4, 0x0a, 0x40, 0xc3, 0x90, 0x4b, 0xaa, 0x14, 0xec, 0xd7, 0xb5, 0x14, 0x07, 0x82,
-	0x1e, 0x40, 0xf3, 0x7d, 0xc8, 0xcf, 0x03, 0xe6, 0x9d, 0xb2, 0x7e, 0x63, 0xad, 0xb4, 0xd1, 0xda,
-	0x42, 0x89, 0xa8, 0x17, 0x31, 0x86, 0xa4, 0x44, 0xf8, 0x0e, 0x34, 0x13, 0x38, 0xea, 0x43, 0x63,
-	0xc8, 0x43, 0xc9, 0xc2, 0x38, 0x38, 0xf1, 0x16, 0xff, 0x58, 0x86, 0xce, 0x2b, 0x26, 0xa9, 0x20,
-	0x4c, 0x8c, 0x79, 0x28, 0x98, 0x52, 0x65, 0x1c, 0x4c, 0x4e, 0xfd, 0xf0, 0x20, 0x75, 0x89, 0x03,
-	0x41, 0x0f, 0x60, 0x29, 0xf6, 0xfe, 0x2e, 0x3b, 0xa1, 0x93, 0x40, 0x1e, 0xc6, 0x01, 0xae, 0x90,
-	0x22, 0x14, 0xfa, 0x06, 0xfa, 0x31, 0xf8, 0x69, 0x3e, 0x56, 0x95, 0x42, 0xb7, 0xcc, 0xa4, 0x47,
-	0xb7, 0xa0, 0x16, 0x4d, 0x02, 0x26, 0xfa, 0x55, 0xcd, 0xd8, 0x49, 0x18, 0x55, 0x20, 0x89, 0xc1,
-	0xa1, 0x57, 0xb0, 0xc2, 0x42, 0x7a, 0x1c, 0x30, 0xef, 0xaf, 0x63, 0xc3, 0xfd, 0x8a, 0x7b, 0x93,
-	0x80, 0xe9, 0x20, 0x74, 0xb7, 0xae, 0x26, 0x4c, 0x59, 0x34, 0x29, 0xe6, 0x52, 0xa9, 0x10, 0xf0,
-	0x53, 0xae, 0xc3, 0xd2, 0x26, 0x7a, 0x8d, 0x09, 0xb4, 0xf6, 0x43, 0x5f, 0x12, 0x76, 0x36, 0x61,
-	0x42, 0xa2, 0x55, 0xa8, 0x78, 0x22, 0xd4, 0xde, 0x6a, 0x6d, 0xb5, 0x13, 0xf9, 0xbb, 0x47, 0x07,
-	0x44, 0x21, 0x52, 0xb5, 0xcb, 0xb3, 0xd5, 0xc6, 0x5f, 0x41, 0xdb, 0xc8, 0xb4, 0x91, 0xb8, 0x0b,
-	0x0d, 0x61, 0xee, 0xb2, 0x15, 0xdc, 0x4b, 0xd8, 0xec, 0x1d, 0x27, 0x31, 0x81, 0xe2, 0xdd, 0x09,
-	0xb8, 0x60, 0xb1, 0x42, 0x1f, 0xc3, 0xbb, 0x0d, 0xe8, 0x85, 0x1f, 0x04, 0x87, 0x11, 0x1f, 0x32,
-	0x21, 0xe6, 0x91, 0xf0, 0x5b, 0x68, 0x1e, 0xd2, 0x48, 0x30, 0xef, 0xe8, 0xf5, 0x4b, 0x75, 0x4b,
-	0xce, 0x26, 0x2c, 0x8a, 0x0b, 0x8c, 0xd9, 0xe0, 0x1f, 0xa0, 0xad, 0x49, 0xe6, 0x10, 0x8f, 0x6e,
-	0x43, 0x45, 0x9c, 0x05, 0x3a, 0xc5, 0xdc, 0xbc, 0x4f, 0x8e, 0x24, 0x0a, 0x8d, 0xff, 0x59, 0x82,
-	0xea, 0x01, 0xf7, 0x74, 0xbc, 0x24, 0xbb, 0x48, 0xea, 0x90, 0x5a, 0x27, 0x75, 0xab, 0x9c, 0xd6,
-	0x2d, 0xb4, 0x06, 0xad, 0x13, 0x3f, 0x3c, 0x65, 0xd1, 0x38, 0xf2, 0x43, 0x69, 0x2f, 0xb5, 0x0b,
-	0x42, 0x37, 0xa0, 0x29, 0x24, 0x8d, 0xe4, 0x4b, 0x3f, 0x34, 0x25, 0xaf, 0x4a, 0x52, 0x80, 0xba,
-	0x55, 0xc7, 0x54, 0x0e, 0xdf, 0xed, 0x7b, 0xba, 0x2c, 0x55, 0x49, 0xbc, 0xc5, 0xbf, 0x87, 0x8e,
-	0x35, 0xd6, 0x86, 0xf2, 0x16, 0xd4, 0x42, 0xee, 0x31, 0xd1, 0x2f, 0xe5, 0xe2, 0xaf, 0x14, 0x26,
-	0x06, 0x87, 0xd7, 0x60, 0xe1, 0xe9, 0xc4, 0xf3, 0xe5, 0x6c, 0x27, 0x52, 0x68, 0x6b, 0x8a, 0x79,
-	0x9c, 0x78, 0x07, 0xaa, 0xe2, 0x2c, 0x88, 0x33, 0xf0, 0x93, 0x84, 0x30, 0x3e, 0x92, 0x68, 0x34,
-	0xfe, 0x16, 0x5a, 0xf6, 0x08, 0x31, 0x09, 0xa4, 0xb2, 0x71, 0xc4, 0x84, 0xa0, 0xa7, 0x71, 0x29,
-	0x88, 0xb7, 0x69, 0x31, 0x2c, 0xbb, 0xc5, 0xf0, 0x3a, 0x34, 0x55, 0x32, 0x7f, 0xaf, 0xeb, 0xa9,
-	0xf1, 0xe8, 0x82, 0x02, 0xa8, 0xd2, 0x81, 0xff, 0x9c, 0xa8, 0xaf, 0x64, 0x0b, 0xb4, 0x09, 0x8d,
-	0xc8, 0x2c, 0xad, 0x5f, 0x96, 0xb3, 0x5a, 0x19, 0x3a, 0x12, 0x13, 0xe1, 0x6f, 0xa0, 0x13, 0xc3,
-	0x8d, 0x5b, 0x1f, 0x43, 0x9b, 0x3a, 0x02, 0xad, 0x94, 0x95, 0x22, 0x29, 0x82, 0x64, 0x48, 0xf1,
-	0x67, 0xb0, 0x78, 0xc0, 0x98, 0x47, 0x78, 0x10, 0x1c, 0xd3, 0xe1, 0xfb, 0xd9, 0x3e, 0xe7, 0xb0,
-	0xf2, 0x8c, 0x85, 0x0e, 0xdd, 0x3c, 0xce, 0xbf, 0xeb, 0x66, 0x70, 0x3f, 0x8d, 0x7e, 0x56, 0x03,
-	0x93, 0xc7, 0x7f, 0x82, 0xd6, 0xcf, 0x6a, 0xe5, 0xc6, 0xa5, 0x9c, 0x89, 0x0b, 0xde, 0x86, 0x2b,
-	0x79, 0x7d, 0xad, 0xb7, 0xd6, 0x8d, 0x12, 0x46, 0xd9, 0xd4, 0xd5, 0x53, 0x0a, 0x3c, 0x86, 0xd6,
-	0xa1, 0x1f, 0x9e, 0xce, 0x53, 0x08, 0x3e, 0x85, 0xc6, 0xde, 0x05, 0x1b, 0xce, 0xf6, 0xe6, 0x77,
-	0xd0, 0x52, 0x04, 0xf3, 0xf8, 0x10, 0xbb, 0x3e, 0x4c, 0xe9, 0xec, 0x79, 0x46, 0xf5, 0x7f, 0x97,
-	0x00, 0x8c, 0x7c, 0x9d, 0xbd, 0x18, 0xda, 0x01, 0x15, 0x72, 0x3f, 0x14, 0x2c, 0x92, 0xfb, 0xa6,
-	0x19, 0xaa, 0x90, 0x0c, 0x0c, 0xdd, 0x83, 0x4f, 0xdc, 0xfd, 0x5e, 0x14, 0xf1, 0xc8, 0xfa, 0x74,
-	0x1a, 0xa1, 0x24, 0x46, 0xfc, 0x5c, 0x3c, 0x3d, 0x39, 0x61, 0x43, 0xc9, 0x3c, 0x9d, 0xe2, 0x15,
-	0x92, 0x81, 0x29, 0x89, 0xee, 0xde, 0x48, 0x34, 0x9d, 0xc1, 0x34, 0x02, 0x7b, 0xd0, 0x53, 0x1a,
-	0x7f, 0xad, 0x4a, 0xc7, 0x7c, 0xc5, 0xd1, 0xbd, 0xd7, 0xd3, 0x7e, 0x31, 0xd7, 0x7a, 0x1b, 0x16,
-	0x9d, 0x53, 0xb4, 0x73, 0x7e, 0x97, 0xbf, 0x7d, 0x4b, 0x19, 0xde, 0xfc, 0xe5, 0x7b, 0x02, 0x6d,
-	0x0b, 0x36, 0xd9, 0xf4, 0x39, 0xd4, 0x0d, 0xca, 0xaa, 0x58, 0xc8, 0x6d, 0x49, 0xf0, 0x77, 0xd0,
-	0x7c, 0x73, 0xf1, 0xeb, 0x59, 0xf7, 0x04, 0x40, 0x89, 0xb7, 0x9a, 0x7d, 0xa4, 0x61, 0x6b, 0xb0,
-	0xf0, 0x5a, 0xe5, 0xe6, 0xec, 0xa4, 0xfd, 0x02, 0x9a, 0x9a, 0x62, 0x87, 0x87, 0x27, 0xe8, 0x36,
-	0x74, 0xa4, 0x3f, 0x62, 0x7c, 0x22, 0x8f, 0xd8, 0x90, 0x87, 0x26, 0xa9, 0x3a, 0x24, 0x0b, 0xc4,
-	0xff, 0x28, 0x41, 0x5b, 0xf3, 0xcc, 0x63, 0xf4, 0x2d, 0x37, 0xd3, 0xd3, 0x4a, 0x1d, 0x6b, 0xa9,
-	0x53, 0x1d, 0xad, 0x43, 0x75, 0xc8, 0xc3, 0x13, 0x9d, 0x81, 0xee, 0x5f, 0x31, 0xd1, 0x94, 0x68,
-	0x3c, 0xf6, 0xa0, 0x63, 0x15, 0x49, 0xca, 0x40, 0x7d, 0xc8, 0x83, 0xc9, 0x28, 0xb4, 0xde, 0x99,
-	0xea, 0x49, 0x0d, 0x16, 0x7d, 0x0e, 0x55, 0x95, 0xad, 0xd6, 0xf5, 0x57, 0xb3, 0x07, 0x58, 0x27,
-	0xf2, 0x73, 0xa2, 0x89, 0xf0, 0x0e, 0x74, 0xb3, 0x70, 0xf4, 0x05, 0xd4, 0xf5, 0x18, 0x11, 0x07,
-	0xe1, 0x5a, 0x91, 0x80, 0xb7, 0x8a, 0x82, 0x58, 0x42, 0xbc, 0x01, 0xbd, 0x3c, 0x2e, 0x1d, 0x4d,
-	0x4a, 0xce, 0x68, 0x82, 0xb1, 0xba, 0xe6, 0xe3, 0x80, 0xfa, 0xe1, 0xec, 0xa8, 0x0d, 0xa1, 0x6b,
-	0x69, 0xe6, 0xfb, 0x5d, 0x3a, 0x31, 0x70, 0x13, 0x28, 0x3e, 0xd5, 0x14, 0x9c, 0xb7, 0xea, 0x5e,
-	0xd9, 0x43, 0xac, 0x7f, 0x77, 0xa0, 0x33, 0x0c, 0xa8, 0x10, 0xbe, 0xcd, 0x34, 0x7b, 0xd6, 0xcd,
-	0xbc, 0x8c, 0x1d, 0x97, 0x88, 0x64, 0x79, 0xf0, 0x36, 0x2c, 0x17, 0x91, 0xa1, 0x0d, 0xa8, 0xaa,
-	0xde, 0x78, 0xaa, 0x88, 0xbf, 0xa1, 0xc7, 0x93, 0x80, 0x46, 0xbb, 0x54, 0x52, 0xa2, 0x29, 0xf0,
-	0x53, 0x58, 0x7a, 0xc6, 0xe4, 0xae, 0x6d, 0xa4, 0xe7, 0x6a, 0xeb, 0x56, 0x61, 0x21, 0xe6, 0x2f,
-	0x9a, 0x91, 0xf0, 0x33, 0x58, 0xce, 0x1e, 0x61, 0x3d, 0x70, 0x1f, 0x9a, 0x71, 0x03, 0x1f, 0x47,
-	0x3f, 0xcd, 0xe2, 0x98, 0x9c, 0xa4, 0x34, 0xf8, 0x21, 0xd4, 0xde, 0xa8, 0xce, 0xbb, 0x70, 0x12,
-	0xbb, 0x02, 0x75, 0x31, 0x7c, 0xc7, 0x46, 0xd4, 0x56, 0x65, 0xbb, 0xc3, 0xa7, 0xda, 0x40, 0xcd,
-	0xa7, 0x26, 0x98, 0xf9, 0xaa, 0x4b, 0x4d, 0x2a, 0x7e, 0x1b, 0xe6, 0xae, 0xeb, 0x4e, 0xd5, 0x97,
-	0x6b, 0x24, 0x7e, 0xae, 0xcd, 0x74, 0x0e, 0xb2, 0x66, 0x3e, 0x80, 0xa6, 0x8c, 0x81, 0xf6, 0x2c,
-	0x94, 0x95, 0xa0, 0xc9, 0x53, 0x22, 0xfc, 0x9f, 0x12, 0x34, 0x13, 0x04, 0x7a, 0x04, 0x2d, 0x73,
-	0xd5, 0xc4, 0x7e, 0x78, 0xc2, 0xa7, 0x42, 0xba, 0x93, 0xe2, 0x88, 0x4b, 0xa8, 0xf8, 0xfc, 0xd0,
-	0x63, 0x17, 0xcc, 0xf0, 0x95, 0x73, 0x7c, 0xfb, 0x29, 0x8e, 0xb8, 0x84, 0x68, 0x1d, 0xba, 0xc3,
-	0x88, 0x51, 0xc9, 0xb4, 0x0a, 0x47, 0xaf, 0x5f, 0xda, 0x06, 0x2d, 0x07, 0x75, 0x7b, 0x8b, 0x6a,
-	0xb6, 0xb7, 0xf8, 0x12, 0x5a, 0x8e, 0x56, 0x1f, 0x91, 0x8c, 0x5f, 0xaa, 0x71, 0x29, 0xd5, 0xe4,
-	0x97, 0x33, 0x3e, 0x86, 0x45, 0x07, 0xf8, 0x9c, 0x51, 0xef, 0x97, 0x4e, 0xeb, 0xaa, 0xc3, 0x73,
-	0xe5, 0xf1, 0x73, 0xa1, 0x0a, 0x85, 0x2f, 0xd9, 0xc8, 0x24, 0x65, 0x93, 0x98, 0x0d, 0xe6, 0xd0,
-	0x72, 0x08, 0xd1, 0x96, 0x1a, 0x96, 0xb5, 0x91, 0x36, 0x77, 0xfb, 0x45, 0xfa, 0x29, 0x55, 0x48,
-	0x4c, 0x88, 0xee, 0x65, 0x6a, 0x65, 0x21, 0x83, 0x52, 0xc0, 0x16, 0xcb, 0xdb, 0xea, 0x57, 0x2a,
-	0x23, 0xaa, 0x9a, 0x80, 0xd9, 0xf5, 0xeb, 0x0c, 0x06, 0x96, 0x4a, 0x47, 0xe6, 0x2f, 0x11, 0x1f,
-	0xcd, 0xd9, 0x7d, 0x7e, 0xe6, 0xd6, 0xb2, 0x15, 0xa7, 0x0e, 0xa5, 0x3a, 0x98, 0x6a, 0xb6, 0x07,
-	0xd7, 0x0b, 0x8f, 0x4c, 0xff, 0x1c, 0x3a, 0x97, 0xc5, 0xd4, 0x9f, 0xc3, 0xdc, 0x17, 0x8b, 0xc5,
-	0x77, 0xa0, 0x63, 0x7a, 0x1c, 0x65, 0xf3, 0x6c, 0x03, 0x25, 0xdc, 0xd8, 0x13, 0xd2, 0x1f, 0x51,
-	0xa9, 0xd2, 0x2e, 0xe5, 0x98, 0xc7, 0xc4, 0x0d, 0xd7, 0xc4, 0x2b, 0xe9, 0x00, 0xe0, 0xaa, 0x61,
-	0x6c, 0xfc, 0x1b, 0xdc, 0x9c, 0x71, 0xaa, 0xb5, 0x72, 0x19, 0x6a, 0x43, 0x3e, 0xb1, 0x4f, 0x25,
-	0x15, 0x62, 0x36, 0x68, 0x15, 0x80, 0x45, 0xd1, 0xab, 0x4c, 0xcf, 0xed, 0x40, 0xf0, 0x1f, 0x60,
-	0x29, 0x33, 0x44, 0xa7, 0xaf, 0x29, 0x0e, 0x5b, 0x29, 0xcf, 0x76, 0xf7, 0x5f, 0x25, 0xe8, 0x4e,
-	0x3d, 0x37, 0x74, 0xb3, 0x0d, 0x7c, 0xef, 0x37, 0xa8, 0x09, 0x35, 0xfd, 0x67, 0xec, 0x95, 0x50,
-	0x4b, 0xb5, 0xd8, 0xfa, 0xcf, 0xd0, 0x2b, 0xa3, 0x1e, 0xb4, 0xdd, 0xd2, 0xd4, 0xab, 0xa0, 0xab,
-	0xb0, 0x54, 0x10, 0xc2, 0x5e, 0x15, 0x5d, 0x83, 0x95, 0x42, 0xbb, 0x7b, 0x35, 0xb4, 0x08, 0x2d,
-	0x47, 0xf7, 0x5e, 0x1d, 0x75, 0xa0, 0x99, 0x74, 0x8b, 0xbd, 0xc6, 0xd6, 0x7f, 0x17, 0xa0, 0xbe,
-	0xab, 0x9f, 0x1e, 0xd1, 0x7d, 0xa8, 0xe9, 0xe7, 0x22, 0x94, 0xc6, 0x5e, 0x3f, 0x3c, 0x0e, 0x52,
-	0x9f, 0x67, 0x9f, 0x93, 0x1e, 0x42, 0x75, 0x3f, 0xf4, 0x25, 0x72, 0xeb, 0x53, 0x32, 0xc0, 0x0e,
-	0x56, 0x72, 0x50, 0xcb, 0xb4, 0x09, 0x35, 0xfd, 0x9a, 0x81, 0x52, 0xbc, 0xfb, 0xba, 0x31, 0xc8,
-	0x1d, 0x8e, 0x9e, 0x67, 0x0c, 0x40, 0xd7, 0xd3, 0xa7, 0xb1, 0xa9, 0x77, 0x8d, 0xc1, 0x8d, 0x62,
-	0xa4, 0x3d, 0xf9, 0x91, 0x7e, 0x24, 0xcd, 0x9c, 0xec, 0x3e, 0x5b, 0x38, 0x66, 0x66, 0x07, 0xfc,
-	0x47, 0x50, 0xd3, 0xc3, 0x26, 0x9a, 0x1a, 0x3e, 0xf3, 0x7c, 0xd9, 0x09, 0xf6, 0x75, 0x3e, 0xd8,
-	0x68, 0x35, 0xa1, 0x2c, 0x1c, 0x3b, 0x07, 0x9f, 0xce, 0xc4, 0x5b, 0x91, 0xf7, 0xa0, 0xaa, 0xc6,
-	0x37, 0xc7, 0xe3, 0xce, 0x34, 0x37, 0xe5, 0xba, 0x87, 0x50, 0x55, 0xa1, 0x76, 0xa8, 0x9d, 0xf9,
-	0x6c, 0xb0, 0x92, 0xef, 0x9c, 0xcd, 0x11, 0xdb, 0x4e, 0x7e, 0xa0, 0x6b, 0x19, 0x1a, 0x77, 0x8e,
-	0x19, 0xf4, 0x8b, 0x50, 0x76, 0xf8, 0x28, 0xbf, 0xb9, 0x40, 0xce, 0xef, 0x32, 0x9e, 0x0e, 0x06,
-	0x4b, 0x19, 0x58, 0xea, 0x5e, 0x9d, 0xff, 0x8e, 0x7b, 0xdd, 0xee, 0xda, 0x71, 0x6f, 0xb6, 0xd7,
-	0xfd, 0x63, 0x72, 0x59, 0xd0, 0xd5, 0x7c, 0xff, 0x55, 0xa4, 0x64, 0xb6, 0x93, 0x7b, 0xa1, 0x6f,
-	0x57, 0xd2, 0xdf, 0xa0, 0x1b, 0x8e, 0xeb, 0xa7, 0x3a, 0xab, 0xc1, 0xcd, 0x19, 0xd8, 0x8c, 0xb0,
-	0xf4, 0xef, 0x9f, 0x11, 0x96, 0xef, 0x62, 0xb2, 0xc2, 0xa6, 0x5b, 0x8f, 0x1f, 0x0a, 0x6f, 0x39,
-	0xba, 0x95, 0xaf, 0xed, 0x05, 0x7f, 0x8e, 0xc1, 0xed, 0x9f, 0x26, 0xb2, 0x27, 0x9c, 0xcc, 0x28,
-	0x17, 0xe8, 0x4e, 0xca, 0xfe, 0x13, 0xc5, 0x7b, 0xb0, 0xfe, 0x73, 0x64, 0xe6, 0x9c, 0xaf, 0xdb,
-	0xdf, 0xc2, 0xe6, 0xfd, 0x27, 0x96, 0xf6, 0xb8, 0xae, 0x17, 0x0f, 0xff, 0x1f, 0x00, 0x00, 0xff,
-	0xff, 0x6f, 0x34, 0xe4, 0x7e, 0xea, 0x18, 0x00, 0x00,
+	// 2223 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x19, 0xcb, 0x72, 0xdb, 0xc8,
+	0x71, 0xc1, 0xa7, 0xd8, 0x24, 0x25, 0x7a, 0xf4, 0x30, 0x0c, 0xbf, 0xb4, 0xb0, 0x2d, 0xab, 0x6c,
+	0x47, 0xb6, 0xe5, 0x64, 0xbd, 0xb6, 0xf3, 0x90, 0x4c, 0xc9, 0x36, 0xfd, 0x50, 0xe4, 0x91, 0xd6,
+	0xa9, 0xa4, 0xb2, 0xe5, 0x85, 0x88, 0x91, 0x8c, 0x32, 0x08, 0x50, 0x98, 0xa1, 0x2d, 0x7d, 0x41,
+	0xf2, 0x1d, 0xb9, 0xe4, 0x9a, 0x4b, 0x8e, 0xf9, 0x8b, 0xfc, 0x41, 0xee, 0xb9, 0xe6, 0x9a, 0x9a,
+	0x07, 0x80, 0x01, 0x08, 0xee, 0x5a, 0xac, 0xda, 0x13, 0x31, 0xfd, 0xee, 0x9e, 0x9e, 0x9e, 0xe9,
+	0x26, 0xcc, 0xb9, 0x91, 0xf7, 0x89, 0x44, 0xef, 0x3f, 0xad, 0xaf, 0x0d, 0xa3, 0x90, 0x85, 0xa8,
+	0x2e, 0x7e, 0xde, 0xad, 0xdb, 0x75, 0xa8, 0x6e, 0x0f, 0x86, 0xec, 0xd4, 0xbe, 0x00, 0xf5, 0x3d,
+	0x42, 0xa9, 0x17, 0x06, 0x68, 0x16, 0x4a, 0x9e, 0x6b, 0x1a, 0xcb, 0xc6, 0x6a, 0x03, 0x97, 0x3c,
+	0xd7, 0xfe, 0x03, 0x54, 0x77, 0x9d, 0xc8, 0x19, 0xa0, 0x0e, 0x94, 0x3f, 0x92, 0x53, 0x85, 0xe1,
+	0x9f, 0x68, 0x01, 0xaa, 0x9f, 0x1c, 0x7f, 0x44, 0xcc, 0x92, 0x80, 0xc9, 0x05, 0x42, 0x50, 0x71,
+	0x09, 0xed, 0x9b, 0x65, 0x01, 0x14, 0xdf, 0x1c, 0xc6, 0x4e, 0x87, 0xc4, 0xac, 0x48, 0x18, 0xff,
+	0xb6, 0xff, 0x69, 0x40, 0x79, 0x6b, 0x6f, 0x87, 0xe3, 0x3e, 0x84, 0x94, 0x29, 0xc1, 0xe2, 0x9b,
+	0xc3, 0x86, 0x61, 0xc4, 0x94, 0x60, 0xf1, 0xcd, 0x61, 0x23, 0x4a, 0xa2, 0x58, 0x2e, 0xff, 0x46,
+	0x16, 0xcc, 0x0c, 0x1d, 0x4a, 0x3f, 0x87, 0x91, 0xab, 0x64, 0x27, 0x6b, 0x8e, 0x73, 0x1d, 0xe6,
+	0x1c, 0x38, 0x94, 0x98, 0x55, 0x89, 0x8b, 0xd7, 0xe8, 0x31, 0x74, 0x1c, 0xd7, 0xf5, 0x98, 0x17,
+	0x06, 0x8e, 0x2f, 0xdc, 0xa3, 0x66, 0x6d, 0xb9, 0xbc, 0xda, 0x5c, 0x9f, 0x5d, 0x53, 0xc1, 0x59,
+	0x13, 0x60, 0x3c, 0x46, 0x67, 0xff, 0xaf, 0x04, 0x15, 0x3c, 0xf2, 0x85, 0xa3, 0x81, 0x33, 0x20,
+	0xb1, 0xe1, 0xfc, 0x3b, 0x71, 0xbe, 0xa4, 0x39, 0xbf, 0x00, 0x55, 0x9f, 0x7c, 0x22, 0xbe, 0xb2,
+	0x5c, 0x2e, 0xb8, 0x79, 0x7d, 0x87, 0x91, 0xa3, 0x30, 0x3a, 0x8d, 0x4d, 0x8f, 0xd7, 0x68, 0x05,
+	0x6a, 0x43, 0x69, 0x54, 0xb5, 0xd0, 0x28, 0x85, 0x45, 0x57, 0x00, 0x9c, 0x20, 0x08, 0x99, 0xc3,
+	0x0d, 0x34, 0x6b, 0x42, 0x8a, 0x06, 0x41, 0xf7, 0xa0, 0xf1, 0x31, 0x08, 0x3f, 0xfb, 0xc4, 0x3d,
+	0x22, 0x66, 0x7d, 0xd9, 0x58, 0x6d, 0xae, 0xa3, 0x44, 0xd4, 0xab, 0x18, 0x83, 0x53, 0x22, 0xd4,
+	0x85, 0x96, 0x77, 0xff, 0xdb, 0x80, 0xfb, 0xd7, 0x0b, 0x0e, 0x43, 0x73, 0x46, 0xe8, 0xbf, 0x9a,
+	0x30, 0x71, 0xc4, 0x5a, 0x4f, 0xa3, 0xd8, 0x0e, 0x58, 0x74, 0x8a, 0x33, 0x4c, 0xd6, 0x3b, 0x38,
+	0x37, 0x46, 0x52, 0x90, 0x3e, 0xb7, 0xf5, 0xf4, 0x69, 0xae, 0x2f, 0x26, 0x4a, 0x74, 0x66, 0x95,
+	0x55, 0x8f, 0x4b, 0xdf, 0x1a, 0xf6, 0x0d, 0x68, 0x24, 0x46, 0x23, 0x13, 0xea, 0xfd, 0x30, 0x60,
+	0x24, 0x88, 0x33, 0x27, 0x5e, 0xda, 0xff, 0x2d, 0x41, 0xfb, 0x0d, 0x61, 0x0e, 0xc5, 0x84, 0x0e,
+	0xc3, 0x80, 0x12, 0x1e, 0xa7, 0xa1, 0x3f, 0x3a, 0xf2, 0x82, 0x9d, 0x74, 0xbf, 0x34, 0x08, 0xba,
+	0x07, 0xf3, 0x71, 0x6a, 0x6c, 0x91, 0x43, 0x67, 0xe4, 0xb3, 0xdd, 0x38, 0xfb, 0xca, 0xb8, 0x08,
+	0x85, 0x5e, 0x82, 0x19, 0x83, 0x37, 0xf3, 0x89, 0x54, 0x2e, 0xdc, 0xb3, 0x89, 0xf4, 0xe8, 0x1a,
+	0x54, 0xa3, 0x91, 0x4f, 0xa8, 0x59, 0x11, 0x8c, 0xed, 0x4c, 0xb0, 0xb1, 0xc4, 0xa1, 0x37, 0xb0,
+	0x48, 0x02, 0xe7, 0xc0, 0x27, 0xee, 0xef, 0x87, 0x92, 0xfb, 0x4d, 0xe8, 0x8e, 0x7c, 0x22, 0x32,
+	0x64, 0x76, 0xfd, 0x7c, 0xc2, 0x94, 0x45, 0xe3, 0x62, 0x2e, 0x9e, 0xa7, 0x7e, 0x78, 0x14, 0x8a,
+	0x9c, 0x69, 0x61, 0xf1, 0x8d, 0x1e, 0x42, 0xab, 0xef, 0x0c, 0x9d, 0x03, 0xcf, 0xf7, 0x98, 0x47,
+	0xa8, 0x59, 0x17, 0x92, 0xe7, 0x13, 0xc9, 0xdd, 0x18, 0x79, 0x8a, 0x33, 0x84, 0x36, 0x86, 0x66,
+	0x2f, 0xf0, 0x18, 0x26, 0xc7, 0x23, 0x42, 0x19, 0xba, 0x02, 0x65, 0x97, 0x06, 0x22, 0xcc, 0xcd,
+	0xf5, 0x56, 0xc2, 0xbe, 0xb5, 0xb7, 0x83, 0x39, 0x22, 0xf5, 0xb7, 0x34, 0xd9, 0x5f, 0xfb, 0x31,
+	0xb4, 0xa4, 0x4c, 0xb5, 0x85, 0xb7, 0xa0, 0x4e, 0x65, 0x85, 0x52, 0x82, 0x3b, 0x09, 0x9b, 0xaa,
+	0x5c, 0x38, 0x26, 0xe0, 0xbc, 0x5d, 0x3f, 0xa4, 0x24, 0x36, 0xe8, 0x2c, 0xbc, 0x1b, 0x80, 0x5e,
+	0x79, 0xbe, 0xbf, 0x1b, 0x85, 0x7d, 0x42, 0xe9, 0x34, 0x12, 0xbe, 0x86, 0xc6, 0xae, 0x13, 0x51,
+	0xe2, 0xee, 0xbd, 0x7d, 0xcd, 0xcf, 0xfe, 0xf1, 0x88, 0x44, 0x71, 0xde, 0xcb, 0x85, 0xfd, 0x03,
+	0xb4, 0x04, 0xc9, 0x14, 0xe2, 0xd1, 0x75, 0x28, 0xd3, 0x63, 0xdf, 0x2c, 0xe5, 0x4e, 0x73, 0xa2,
+	0x12, 0x73, 0xb4, 0xfd, 0x57, 0x03, 0x2a, 0x3b, 0xa1, 0x2b, 0x36, 0x9a, 0x91, 0x93, 0xa4, 0xba,
+	0xf2, 0xef, 0xa4, 0x1a, 0x97, 0xd2, 0x6a, 0x8c, 0x96, 0xa1, 0x79, 0xe8, 0x05, 0x47, 0x24, 0x1a,
+	0x46, 0x5e, 0xc0, 0x54, 0xa9, 0xd2, 0x41, 0xe8, 0x12, 0x34, 0x28, 0x73, 0x22, 0xf6, 0xda, 0x0b,
+	0x64, 0x21, 0xaf, 0xe0, 0x14, 0xc0, 0x8f, 0xe3, 0x81, 0xc3, 0xfa, 0x1f, 0x7a, 0xae, 0x28, 0xb6,
+	0x15, 0x1c, 0x2f, 0xed, 0x5f, 0x42, 0x5b, 0x39, 0xab, 0xb6, 0xf2, 0x1a, 0x54, 0x83, 0xd0, 0x25,
+	0xd4, 0x34, 0x72, 0xfb, 0xcf, 0x0d, 0xc6, 0x12, 0x67, 0x2f, 0xc3, 0xcc, 0xe6, 0xc8, 0xf5, 0xd8,
+	0xe4, 0x20, 0x3a, 0xd0, 0x12, 0x14, 0xd3, 0x04, 0xf1, 0x06, 0x54, 0xe8, 0xb1, 0x1f, 0x67, 0xe0,
+	0xb9, 0x84, 0x30, 0x56, 0x89, 0x05, 0xda, 0xfe, 0x7b, 0x09, 0x9a, 0x4a, 0x07, 0x1d, 0xf9, 0x8c,
+	0x3b, 0x39, 0x20, 0x94, 0x3a, 0x47, 0x71, 0x11, 0x89, 0x97, 0x69, 0x8d, 0x2f, 0xe9, 0x35, 0xfe,
+	0x22, 0x34, 0x78, 0x36, 0xbf, 0x17, 0xd7, 0x84, 0x0c, 0xe9, 0x0c, 0x07, 0x88, 0xa2, 0x73, 0x09,
+	0x1a, 0x8a, 0xbb, 0x17, 0x5f, 0x5e, 0x29, 0x80, 0xef, 0x87, 0x5a, 0x6c, 0x46, 0x47, 0xf2, 0x1e,
+	0x68, 0x60, 0x1d, 0x84, 0x5e, 0xca, 0x52, 0xfd, 0x46, 0x82, 0xe2, 0xfb, 0x6b, 0x25, 0xeb, 0x8b,
+	0x34, 0x7c, 0xad, 0xa7, 0x11, 0x6a, 0x15, 0x3b, 0x06, 0x59, 0xbf, 0x93, 0x15, 0x3b, 0x43, 0xf2,
+	0xa5, 0x17, 0xbe, 0x28, 0xcd, 0xbf, 0x85, 0x96, 0xa6, 0x8f, 0xa2, 0x35, 0xa8, 0x47, 0xf2, 0x53,
+	0xed, 0xf2, 0x42, 0x91, 0x5d, 0x38, 0x26, 0xb2, 0x5f, 0x42, 0x3b, 0x86, 0xcb, 0x24, 0x79, 0x04,
+	0x2d, 0x47, 0x13, 0xa8, 0xa4, 0x2c, 0x16, 0x49, 0xa1, 0x38, 0x43, 0x6a, 0xdf, 0x84, 0xb9, 0x1d,
+	0x42, 0x5c, 0x1c, 0xfa, 0xfe, 0x81, 0xd3, 0xff, 0x38, 0x39, 0x83, 0x42, 0x58, 0x7c, 0x4e, 0x02,
+	0x8d, 0x6e, 0x9a, 0x54, 0xba, 0xa5, 0x9f, 0x47, 0x33, 0xcd, 0xe5, 0xac, 0x05, 0xf2, 0x54, 0xfe,
+	0x06, 0x9a, 0x3f, 0x69, 0x95, 0x9e, 0x64, 0xa5, 0x4c, 0x92, 0xd9, 0x1b, 0xb0, 0x94, 0xb7, 0x57,
+	0x45, 0x6b, 0x45, 0x1a, 0x21, 0x8d, 0x4d, 0x43, 0x3d, 0x66, 0xc0, 0x23, 0x68, 0xee, 0x7a, 0xc1,
+	0xd1, 0x34, 0x65, 0xed, 0x2a, 0xd4, 0xb7, 0x4f, 0x48, 0x7f, 0x72, 0x34, 0xbf, 0x87, 0x26, 0x27,
+	0x98, 0x26, 0x86, 0xb6, 0x1e, 0xc3, 0x94, 0x4e, 0xe9, 0x93, 0xa6, 0xff, 0xc3, 0x00, 0x90, 0xf2,
+	0xc5, 0x51, 0xb4, 0xa1, 0xe5, 0x3b, 0x94, 0xf5, 0x02, 0x4a, 0x22, 0xd6, 0x93, 0x0f, 0xd6, 0x32,
+	0xce, 0xc0, 0xd0, 0x1d, 0x38, 0xa7, 0xaf, 0xb7, 0xa3, 0x28, 0x8c, 0x54, 0x4c, 0xc7, 0x11, 0x5c,
+	0x62, 0x14, 0x7e, 0xa6, 0x9b, 0x87, 0x87, 0xa4, 0xcf, 0x88, 0x2b, 0xce, 0x6b, 0x19, 0x67, 0x60,
+	0x5c, 0xa2, 0xbe, 0x96, 0x12, 0xe5, 0xd9, 0x1d, 0x47, 0xd8, 0x2e, 0x74, 0xb8, 0xc5, 0x4f, 0x79,
+	0x21, 0x9c, 0xae, 0xd4, 0xeb, 0x55, 0x6a, 0x3c, 0x2e, 0xb2, 0x48, 0x6d, 0xc0, 0x9c, 0xa6, 0x45,
+	0x04, 0xe7, 0x17, 0xf9, 0xd3, 0x37, 0x9f, 0xe1, 0xcd, 0x1f, 0xbe, 0x27, 0xd0, 0x52, 0x60, 0x99,
+	0x4d, 0xb7, 0xa1, 0x26, 0x51, 0xca, 0xc4, 0x42, 0x6e, 0x45, 0x62, 0x7f, 0x0f, 0x8d, 0xfd, 0x93,
+	0x9f, 0xcf, 0xbb, 0x27, 0x00, 0xfb, 0x27, 0x89, 0x65, 0x67, 0x74, 0x6c, 0x19, 0x66, 0xde, 0xf2,
+	0xdc, 0x9c, 0x9c, 0xb4, 0xf7, 0xa1, 0x21, 0x28, 0xba, 0x61, 0x70, 0x88, 0xae, 0x43, 0x9b, 0x79,
+	0x03, 0x12, 0x8e, 0xd8, 0x1e, 0xe9, 0x87, 0x81, 0x4c, 0xaa, 0x36, 0xce, 0x02, 0xed, 0xbf, 0x18,
+	0xd0, 0x12, 0x3c, 0xd3, 0x38, 0x7d, 0x4d, 0xcf, 0xf4, 0xf4, 0xde, 0x89, 0xad, 0x14, 0xa9, 0x8e,
+	0x56, 0xa0, 0xd2, 0x0f, 0x83, 0x43, 0xb3, 0x9c, 0xbb, 0xe3, 0x13, 0x4b, 0xb1, 0xc0, 0xdb, 0x2e,
+	0xb4, 0x95, 0x21, 0x49, 0x19, 0xa8, 0xf5, 0x43, 0x7f, 0x34, 0x08, 0x4c, 0xa3, 0xf0, 0x0d, 0xaa,
+	0xb0, 0xe8, 0x36, 0x54, 0x78, 0xb6, 0xaa, 0xd0, 0x9f, 0xcf, 0x2a, 0x50, 0x41, 0x0c, 0x3f, 0x63,
+	0x41, 0x64, 0x77, 0x61, 0x36, 0x0b, 0x47, 0xf7, 0xa1, 0x26, 0x2a, 0x7f, 0xbc, 0x09, 0x17, 0x8a,
+	0x04, 0xbc, 0xe3, 0x14, 0x58, 0x11, 0xda, 0xab, 0xd0, 0xc9, 0xe3, 0xd2, 0xdb, 0xc4, 0xd0, 0x6e,
+	0x13, 0xdb, 0xe6, 0xc7, 0x7c, 0xe8, 0x3b, 0x5e, 0x30, 0x79, 0xd7, 0xfa, 0x30, 0xab, 0x68, 0xa6,
+	0xbb, 0xfc, 0xb5, 0x3d, 0xd0, 0x13, 0x28, 0xd6, 0x2a, 0x0b, 0xce, 0x3b, 0x98, 0x53, 0xa0, 0x24,
+	0xbe, 0x5d, 0x68, 0xf7, 0x7d, 0x87, 0x52, 0x4f, 0x65, 0x9a, 0xd2, 0x75, 0x39, 0x2f, 0xa3, 0xab,
+	0x13, 0xe1, 0x2c, 0x8f, 0xbd, 0x01, 0x0b, 0x45, 0x64, 0x68, 0x15, 0x2a, 0xbc, 0x45, 0x18, 0x2b,
+	0xe2, 0xfb, 0xce, 0xc1, 0xc8, 0x77, 0xa2, 0x2d, 0x87, 0x39, 0x58, 0x50, 0xd8, 0x9b, 0x30, 0xff,
+	0x9c, 0xb0, 0x2d, 0xd5, 0x4f, 0x4c, 0xf5, 0x48, 0xbd, 0x02, 0x33, 0x31, 0x7f, 0x51, 0x1f, 0x6b,
+	0x3f, 0x87, 0x85, 0xac, 0x0a, 0x15, 0x81, 0xbb, 0xd0, 0x88, 0xfb, 0x98, 0x78, 0xf7, 0xd3, 0x2c,
+	0x8e, 0xc9, 0x71, 0x4a, 0x63, 0x3f, 0x80, 0xea, 0x3e, 0x6f, 0x40, 0x8a, 0xb4, 0xa0, 0x25, 0xa8,
+	0xd1, 0xfe, 0x07, 0x32, 0x70, 0x54, 0x55, 0x56, 0x2b, 0xfb, 0x48, 0x38, 0x28, 0xf8, 0x78, 0x23,
+	0x37, 0x5d, 0x75, 0xa9, 0x32, 0xce, 0xaf, 0xb6, 0x79, 0x56, 0x0f, 0x27, 0xef, 0x32, 0x04, 0xd2,
+	0x7e, 0x21, 0xdc, 0xd4, 0x14, 0x29, 0x37, 0xef, 0x41, 0x83, 0xc5, 0x40, 0xd3, 0xc8, 0x1d, 0xc3,
+	0x94, 0x3c, 0x25, 0xb2, 0xff, 0x65, 0x40, 0x23, 0x41, 0xa0, 0x6f, 0xa0, 0x29, 0x8f, 0x1a, 0x15,
+	0x5d, 0x74, 0x7e, 0x4b, 0xbb, 0x29, 0x0e, 0xeb, 0x84, 0x9c, 0xcf, 0x0b, 0x5c, 0x72, 0x42, 0x24,
+	0x5f, 0x29, 0xc7, 0xd7, 0x4b, 0x71, 0x58, 0x27, 0x44, 0x2b, 0x30, 0xdb, 0x8f, 0x88, 0xc3, 0x88,
+	0x30, 0x61, 0xef, 0xed, 0x6b, 0xf5, 0xda, 0xcc, 0x41, 0xf5, 0xb7, 0x45, 0x25, 0xfb, 0xb6, 0x78,
+	0x08, 0x4d, 0xcd, 0xaa, 0x33, 0x24, 0xe3, 0x43, 0xde, 0xfc, 0xa5, 0x96, 0x7c, 0x39, 0xe3, 0x23,
+	0x98, 0xd3, 0x80, 0x2f, 0x88, 0xe3, 0x7e, 0xe9, 0x44, 0xc5, 0xbe, 0x99, 0x61, 0xc5, 0xe1, 0x67,
+	0xca, 0x0b, 0x85, 0xc7, 0xc8, 0x40, 0x26, 0x65, 0x03, 0xcb, 0x85, 0x1d, 0x42, 0x53, 0x23, 0x44,
+	0xeb, 0x7c, 0x66, 0x20, 0x9c, 0x54, 0xb9, 0x6b, 0x16, 0xd9, 0xc7, 0x4d, 0xc1, 0x31, 0x21, 0xba,
+	0x93, 0xa9, 0x95, 0x85, 0x0c, 0xdc, 0x00, 0x55, 0x2c, 0xaf, 0xf3, 0xab, 0x94, 0x45, 0x0e, 0x7f,
+	0x04, 0x4c, 0xae, 0x5f, 0xc7, 0x60, 0x29, 0x2a, 0xb1, 0x33, 0xcf, 0xa2, 0x70, 0x30, 0xe5, 0xeb,
+	0xf3, 0xa6, 0x5e, 0xcb, 0x16, 0xb5, 0x3a, 0x94, 0xda, 0x20, 0xab, 0xd9, 0x36, 0x5c, 0x2c, 0x54,
+	0x99, 0xde, 0x1c, 0x22, 0x97, 0xe9, 0xd8, 0xcd, 0x21, 0xcf, 0x8b, 0xc2, 0xda, 0x37, 0xa0, 0x2d,
+	0xdf, 0x38, 0xdc, 0xe7, 0xc9, 0x0e, 0x32, 0xb8, 0xb4, 0x4d, 0x99, 0x37, 0x70, 0x18, 0x4f, 0xbb,
+	0x94, 0x63, 0x1a, 0x17, 0x57, 0x75, 0x17, 0x97, 0xd2, 0x06, 0x40, 0x37, 0x43, 0xfa, 0xf8, 0x1d,
+	0x5c, 0x9e, 0xa0, 0x55, 0x79, 0xb9, 0x00, 0xd5, 0x7e, 0x38, 0x52, 0x13, 0xa3, 0x32, 0x96, 0x0b,
+	0x3e, 0x1d, 0x22, 0x51, 0xf4, 0x26, 0xf3, 0xe6, 0xd6, 0x20, 0xf6, 0xaf, 0x60, 0x3e, 0x33, 0x12,
+	0x48, 0x87, 0x4a, 0x1a, 0x9b, 0x31, 0xc6, 0xf6, 0x67, 0x40, 0xa2, 0xef, 0xdd, 0x63, 0x11, 0x71,
+	0x06, 0xd3, 0x78, 0x6e, 0xc1, 0x0c, 0x3d, 0xf6, 0xbb, 0x1f, 0x46, 0xc1, 0x47, 0x61, 0x56, 0x0b,
+	0x27, 0x6b, 0xfb, 0xdf, 0x06, 0xb4, 0xf4, 0x39, 0x59, 0x72, 0x4e, 0x0c, 0x6d, 0xf2, 0x98, 0x9d,
+	0x0f, 0x96, 0xc6, 0xe6, 0x83, 0xfa, 0x0c, 0xb2, 0x9c, 0x9b, 0x41, 0x3e, 0x4a, 0x66, 0x90, 0x72,
+	0x2c, 0xf5, 0x75, 0xe1, 0x78, 0x4e, 0x3e, 0x2c, 0x54, 0x4f, 0xa9, 0x18, 0x2c, 0xde, 0x65, 0xa4,
+	0xe0, 0xb3, 0xf4, 0x91, 0xb7, 0xfe, 0x66, 0xc0, 0xec, 0xd8, 0xa8, 0x6a, 0x36, 0xdb, 0xf5, 0x74,
+	0xbe, 0x42, 0x0d, 0xa8, 0x8a, 0xe7, 0x44, 0xc7, 0x40, 0x4d, 0xa8, 0xab, 0xeb, 0xb4, 0x53, 0x42,
+	0x1d, 0x68, 0xe9, 0xf5, 0xbc, 0x53, 0x46, 0xe7, 0x61, 0xbe, 0x20, 0xef, 0x3b, 0x15, 0x74, 0x01,
+	0x16, 0x0b, 0x93, 0xa5, 0x53, 0x45, 0x73, 0xd0, 0xd4, 0x36, 0xbc, 0x53, 0x43, 0x6d, 0x68, 0x24,
+	0x4f, 0xec, 0x4e, 0xfd, 0xd6, 0x23, 0x80, 0x74, 0x16, 0x86, 0x2c, 0x58, 0xea, 0x6e, 0xee, 0x6e,
+	0x3e, 0xed, 0xbd, 0xee, 0xed, 0xff, 0xf1, 0xfd, 0x77, 0x3b, 0x7b, 0xbb, 0xdb, 0xdd, 0xde, 0xb3,
+	0xde, 0xf6, 0x56, 0xe7, 0x2b, 0x2e, 0x49, 0x6e, 0xbf, 0x68, 0x57, 0x3b, 0xc6, 0xfa, 0x7f, 0x1a,
+	0x50, 0xdb, 0x12, 0xe3, 0x78, 0x74, 0x17, 0xaa, 0xdc, 0x46, 0x8a, 0xd2, 0xb3, 0x26, 0x86, 0xf1,
+	0x56, 0x9a, 0xe3, 0xd9, 0x29, 0xe6, 0x03, 0xa8, 0xf0, 0x91, 0x18, 0xd2, 0xef, 0x83, 0x64, 0xfc,
+	0x61, 0x2d, 0xe6, 0xa0, 0x8a, 0x69, 0x0d, 0xaa, 0x62, 0x16, 0x86, 0x52, 0xbc, 0x3e, 0x1b, 0xb3,
+	0x72, 0xca, 0xd1, 0x8b, 0x8c, 0xef, 0xe8, 0x62, 0x3a, 0x2e, 0x1e, 0x9b, 0x8a, 0x59, 0x97, 0x8a,
+	0x91, 0x4a, 0xf3, 0x37, 0xe2, 0x8f, 0x83, 0x8c, 0x66, 0x7d, 0xe8, 0x65, 0x2d, 0xe5, 0xc1, 0x29,
+	0x9f, 0x88, 0x16, 0x1a, 0x6b, 0xf6, 0xf3, 0x7c, 0xd9, 0x89, 0xc1, 0x33, 0x68, 0x6a, 0xe7, 0x4d,
+	0xb3, 0x7c, 0xfc, 0x14, 0x4e, 0xd2, 0xbd, 0x6a, 0xdc, 0x33, 0xd0, 0x86, 0x9a, 0xf9, 0x28, 0x39,
+	0x67, 0xb3, 0x42, 0x48, 0x78, 0x9b, 0xcf, 0x58, 0x74, 0x25, 0xa1, 0x2e, 0x1c, 0x38, 0x58, 0x57,
+	0x27, 0xe2, 0x95, 0x73, 0x77, 0xa0, 0xc2, 0x1b, 0x77, 0x6d, 0xef, 0xb5, 0x3e, 0x7e, 0x6c, 0x13,
+	0x1f, 0x40, 0x85, 0xe7, 0xab, 0x46, 0xad, 0x75, 0xe6, 0xd6, 0x62, 0x0e, 0xaa, 0x54, 0x6c, 0x68,
+	0x49, 0x8e, 0x2e, 0x64, 0x68, 0xf4, 0x0e, 0xd6, 0x32, 0x8b, 0x50, 0xaa, 0xed, 0x2c, 0xed, 0x9f,
+	0x20, 0xed, 0xa1, 0x14, 0xf7, 0x85, 0xd6, 0x7c, 0x06, 0x96, 0x6e, 0xb4, 0x38, 0xc4, 0x5a, 0x88,
+	0xf5, 0xbe, 0xca, 0x5a, 0xca, 0x83, 0x15, 0xdf, 0xaf, 0x93, 0x13, 0x8f, 0xce, 0xe7, 0x5f, 0xde,
+	0x45, 0x46, 0x66, 0xdf, 0xf0, 0xaf, 0x44, 0x89, 0x48, 0x5e, 0xb6, 0xe8, 0x92, 0x16, 0xfa, 0xb1,
+	0x37, 0xb5, 0x75, 0x79, 0x02, 0x36, 0x23, 0x2c, 0x7d, 0xf7, 0x65, 0x84, 0xe5, 0xdf, 0xaf, 0xd6,
+	0xe5, 0x09, 0x58, 0x25, 0xec, 0x87, 0xc2, 0x52, 0x85, 0xae, 0xe5, 0x6f, 0xf5, 0x82, 0x37, 0x83,
+	0x75, 0xfd, 0xc7, 0x89, 0x94, 0x86, 0xc3, 0x09, 0x35, 0x0f, 0xdd, 0x48, 0xd9, 0x7f, 0xe4, 0xda,
+	0xb6, 0x56, 0x7e, 0x8a, 0x4c, 0xea, 0x79, 0xda, 0xfa, 0x13, 0xac, 0xdd, 0x7d, 0xa2, 0x68, 0x0f,
+	0x6a, 0xe2, 0xe3, 0xc1, 0xff, 0x07, 0x00, 0x43, 0x91, 0xc2, 0xc2, 0x88, 0x1c, 0x00, 0x00,
 }
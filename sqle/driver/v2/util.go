@@ -31,6 +31,34 @@ const (
 	DriverTypeTDSQLForInnoDB = "TDSQL For InnoDB"
 )
 
+const (
+	// StreamChunkSize is the max size of SQL text sent in a chunk of ParseStream
+	// and AuditStream.
+	StreamChunkSize = 1 << 20
+	// StreamAuditChunkCount is the max count of SQLs in a chunk of AuditStream.
+	StreamAuditChunkCount = 500
+	// StreamParseChunkCount is the max count of nodes in a response of ParseStream.
+	StreamParseChunkCount = 1000
+)
+
+// SplitAuditChunks splits the SQLs to chunks of AuditStream in order, a chunk
+// has one SQL at least.
+func SplitAuditChunks(sqls []string) [][]string {
+	chunks := [][]string{}
+	start, size := 0, 0
+	for i, sql := range sqls {
+		if i > start && (i-start >= StreamAuditChunkCount || size+len(sql) > StreamChunkSize) {
+			chunks = append(chunks, sqls[start:i])
+			start, size = i, 0
+		}
+		size += len(sql)
+	}
+	if start < len(sqls) {
+		chunks = append(chunks, sqls[start:])
+	}
+	return chunks
+}
+
 type DriverNotSupportedError struct {
 	DriverTyp string
 }
@@ -95,14 +123,53 @@ func ConvertRuleFromProtoToDriver(rule *protoV2.Rule) *Rule {
 		})
 	}
 	return &Rule{
-		Name:       rule.Name,
-		Category:   rule.Category,
-		Desc:       rule.Desc,
-		Annotation: rule.Annotation,
-		Level:      RuleLevel(rule.Level),
-		Params:     ps,
-		Knowledge:  RuleKnowledge{Content: rule.Knowledge.GetContent()},
+		Name:         rule.Name,
+		Category:     rule.Category,
+		Desc:         rule.Desc,
+		Annotation:   rule.Annotation,
+		Level:        RuleLevel(rule.Level),
+		Params:       ps,
+		Knowledge:    RuleKnowledge{Content: rule.Knowledge.GetContent()},
+		I18nRuleInfo: ConvertI18nRuleInfoFromProtoToDriver(rule.I18NRuleInfo),
+	}
+}
+
+func ConvertI18nRuleInfoFromProtoToDriver(infos map[string]*protoV2.I18NRuleInfo) map[string]*RuleI18nInfo {
+	if len(infos) == 0 {
+		return nil
+	}
+	ret := make(map[string]*RuleI18nInfo, len(infos))
+	for lang, info := range infos {
+		if info == nil {
+			continue
+		}
+		ret[lang] = &RuleI18nInfo{
+			Desc:       info.Desc,
+			Annotation: info.Annotation,
+			Category:   info.Category,
+			Params:     info.Params,
+		}
+	}
+	return ret
+}
+
+func ConvertI18nRuleInfoFromDriverToProto(infos map[string]*RuleI18nInfo) map[string]*protoV2.I18NRuleInfo {
+	if len(infos) == 0 {
+		return nil
+	}
+	ret := make(map[string]*protoV2.I18NRuleInfo, len(infos))
+	for lang, info := range infos {
+		if info == nil {
+			continue
+		}
+		ret[lang] = &protoV2.I18NRuleInfo{
+			Desc:       info.Desc,
+			Annotation: info.Annotation,
+			Category:   info.Category,
+			Params:     info.Params,
+		}
 	}
+	return ret
 }
 
 func ConvertRuleFromDriverToProto(rule *Rule) *protoV2.Rule {
@@ -125,6 +192,7 @@ func ConvertRuleFromDriverToProto(rule *Rule) *protoV2.Rule {
 		Knowledge: &protoV2.Knowledge{
 			Content: rule.Knowledge.Content,
 		},
+		I18NRuleInfo: ConvertI18nRuleInfoFromDriverToProto(rule.I18nRuleInfo),
 	}
 }
 
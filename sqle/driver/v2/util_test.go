@@ -0,0 +1,26 @@
+package driverV2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAuditChunks(t *testing.T) {
+	assert.Equal(t, [][]string{}, SplitAuditChunks(nil))
+
+	sqls := make([]string, StreamAuditChunkCount*2+1)
+	for i := range sqls {
+		sqls[i] = "select 1"
+	}
+	chunks := SplitAuditChunks(sqls)
+	assert.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], StreamAuditChunkCount)
+	assert.Len(t, chunks[2], 1)
+
+	// the chunk is split by size, and the SQL larger than chunk size is a chunk.
+	large := strings.Repeat("a", StreamChunkSize)
+	chunks = SplitAuditChunks([]string{"select 1", large, "select 2", "select 3"})
+	assert.Equal(t, [][]string{{"select 1"}, {large}, {"select 2", "select 3"}}, chunks)
+}
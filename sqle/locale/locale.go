@@ -0,0 +1,268 @@
+// Package locale is the message catalogue of SQLE. The messages are keyed by
+// id, such as "rule.<rule name>.desc", and provided in each supported language.
+//
+// The Chinese messages are registered by the packages which define them, the
+// messages in other languages are loaded from the embedded files named as
+// "<name>.<language>.yaml".
+package locale
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	LangZh = "zh"
+	LangEn = "en"
+
+	// SourceLang is the language of the messages written in source code.
+	SourceLang = LangZh
+)
+
+const (
+	RuleFieldDesc       = "desc"
+	RuleFieldAnnotation = "annotation"
+	RuleFieldCategory   = "category"
+	RuleFieldMessage    = "message"
+)
+
+var SupportedLangs = []string{LangZh, LangEn}
+
+var supportedTags = []language.Tag{language.Chinese, language.English}
+
+var matcher = language.NewMatcher(supportedTags)
+
+//go:embed messages/*.yaml
+var messageFiles embed.FS
+
+type catalogue struct {
+	mu          sync.RWMutex
+	defaultLang string
+	messages    map[string]map[string]string
+	// sourceIds is the map of message in SourceLang to its id.
+	sourceIds map[string]string
+}
+
+var c = &catalogue{
+	defaultLang: SourceLang,
+	messages:    map[string]map[string]string{},
+	sourceIds:   map[string]string{},
+}
+
+func init() {
+	if err := loadMessageFiles(); err != nil {
+		panic(fmt.Errorf("load locale message files failed: %v", err))
+	}
+}
+
+func loadMessageFiles() error {
+	entries, err := messageFiles.ReadDir("messages")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		lang := path.Ext(name)
+		if lang == "" {
+			return fmt.Errorf("language is missing in file name %v", entry.Name())
+		}
+		b, err := messageFiles.ReadFile(path.Join("messages", entry.Name()))
+		if err != nil {
+			return err
+		}
+		tree := map[string]interface{}{}
+		if err := yaml.Unmarshal(b, &tree); err != nil {
+			return fmt.Errorf("parse %v failed: %v", entry.Name(), err)
+		}
+		messages := map[string]string{}
+		flatten("", tree, messages)
+		RegisterMessages(strings.TrimPrefix(lang, "."), messages)
+	}
+	return nil
+}
+
+// flatten joins the keys of nested map with ".", so the message files can be
+// written as a tree.
+func flatten(prefix string, tree map[string]interface{}, messages map[string]string) {
+	for k, v := range tree {
+		id := k
+		if prefix != "" {
+			id = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case map[interface{}]interface{}:
+			sub := make(map[string]interface{}, len(v))
+			for subKey, subValue := range v {
+				sub[fmt.Sprintf("%v", subKey)] = subValue
+			}
+			flatten(id, sub, messages)
+		case nil:
+		default:
+			messages[id] = fmt.Sprintf("%v", v)
+		}
+	}
+}
+
+// RuleMessageId returns the id of the field of rule, such as "desc" and "annotation".
+func RuleMessageId(dbType, ruleName, field string) string {
+	return fmt.Sprintf("rule.%s.%s.%s", dbType, ruleName, field)
+}
+
+// RuleParamMessageId returns the id of the description of rule param.
+func RuleParamMessageId(dbType, ruleName, key string) string {
+	return fmt.Sprintf("rule.%s.%s.params.%s", dbType, ruleName, key)
+}
+
+// Register adds the message to catalogue, it replaces the message with the same id and language.
+func Register(lang, id, message string) {
+	if id == "" || message == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.register(lang, id, message)
+}
+
+func (c *catalogue) register(lang, id, message string) {
+	if c.messages[lang] == nil {
+		c.messages[lang] = map[string]string{}
+	}
+	c.messages[lang][id] = message
+}
+
+// RegisterSource adds the message written in SourceLang, the id can be found by
+// the message text later, see IdOf. It is used for the messages which are passed
+// as text, such as the message pattern of audit result.
+func RegisterSource(id, message string) {
+	if id == "" || message == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.register(SourceLang, id, message)
+	// the first id is kept if some messages have the same text
+	if _, ok := c.sourceIds[message]; !ok {
+		c.sourceIds[message] = id
+	}
+}
+
+func RegisterMessages(lang string, messages map[string]string) {
+	for id, message := range messages {
+		Register(lang, id, message)
+	}
+}
+
+// Messages returns a copy of the messages in the language, it does not contain
+// the messages which are not translated.
+func Messages(lang string) map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	messages := make(map[string]string, len(c.messages[lang]))
+	for id, message := range c.messages[lang] {
+		messages[id] = message
+	}
+	return messages
+}
+
+// SetDefaultLang sets the language used when the request does not specify one.
+func SetDefaultLang(lang string) error {
+	l := Normalize(lang)
+	if l == "" {
+		return fmt.Errorf("language %v is not supported, supported languages are %v", lang, SupportedLangs)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultLang = l
+	return nil
+}
+
+func DefaultLang() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.defaultLang
+}
+
+// Normalize returns the supported language of the tag such as "en-US", it returns
+// empty string if the language is not supported.
+func Normalize(lang string) string {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return ""
+	}
+	base, _ := tag.Base()
+	for _, l := range SupportedLangs {
+		if base.String() == l {
+			return l
+		}
+	}
+	return ""
+}
+
+// ParseAcceptLanguage returns the supported language which matches the Accept-Language
+// header best, it returns empty string if none matches.
+func ParseAcceptLanguage(header string) string {
+	if header == "" {
+		return ""
+	}
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return ""
+	}
+	base, _ := supportedTags[index].Base()
+	return base.String()
+}
+
+// Message returns the message of id in the language, or in SourceLang if it is not translated.
+func Message(lang, id string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if message, ok := c.messages[lang][id]; ok {
+		return message, true
+	}
+	message, ok := c.messages[SourceLang][id]
+	return message, ok
+}
+
+// Localize returns the message of id in the language, it returns fallback if the id is unknown.
+func Localize(lang, id, fallback string) string {
+	if message, ok := Message(lang, id); ok {
+		return message
+	}
+	return fallback
+}
+
+// Sprintf formats the message of id in the language with args.
+func Sprintf(lang, id, fallback string, args ...interface{}) string {
+	pattern := Localize(lang, id, fallback)
+	if len(args) == 0 {
+		return pattern
+	}
+	return fmt.Sprintf(pattern, args...)
+}
+
+// IdOf returns the id of the message registered by RegisterSource.
+func IdOf(message string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.sourceIds[message]
+	return id, ok
+}
+
+// Translate translates the message written in SourceLang, such as the rule category
+// stored in database, it returns the message itself if it is not in catalogue.
+func Translate(lang, message string) string {
+	if id, ok := IdOf(message); ok {
+		return Localize(lang, id, message)
+	}
+	return message
+}
@@ -0,0 +1,56 @@
+package locale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t, LangEn, ParseAcceptLanguage("en-US,en;q=0.9"))
+	assert.Equal(t, LangZh, ParseAcceptLanguage("zh-CN,zh;q=0.9,en;q=0.8"))
+	assert.Equal(t, LangEn, ParseAcceptLanguage("fr-FR,en;q=0.5"))
+	assert.Equal(t, "", ParseAcceptLanguage("fr-FR"))
+	assert.Equal(t, "", ParseAcceptLanguage(""))
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, LangEn, Normalize("en-GB"))
+	assert.Equal(t, LangZh, Normalize("zh-Hans"))
+	assert.Equal(t, "", Normalize("fr"))
+	assert.Equal(t, "", Normalize(""))
+	assert.Error(t, SetDefaultLang("fr"))
+	assert.Equal(t, LangZh, DefaultLang())
+}
+
+func TestLocalize(t *testing.T) {
+	RegisterSource("test.table_not_exist", "表 %s 不存在")
+	Register(LangEn, "test.table_not_exist", "table %v does not exist")
+	RegisterSource("test.only_zh", "只有中文")
+
+	assert.Equal(t, "table t1 does not exist", Sprintf(LangEn, "test.table_not_exist", "", "t1"))
+	assert.Equal(t, "表 t1 不存在", Sprintf(LangZh, "test.table_not_exist", "", "t1"))
+	// the message which is not translated is in SourceLang
+	assert.Equal(t, "只有中文", Localize(LangEn, "test.only_zh", "fallback"))
+	assert.Equal(t, "fallback", Localize(LangEn, "test.unknown", "fallback"))
+
+	id, ok := IdOf("表 %s 不存在")
+	assert.True(t, ok)
+	assert.Equal(t, "test.table_not_exist", id)
+	assert.Equal(t, "只有中文", Translate(LangEn, "只有中文"))
+	assert.Equal(t, "unknown", Translate(LangEn, "unknown"))
+
+	// the message registered by Register can not be found by text
+	Register(LangZh, "test.rule_desc", "规则描述")
+	_, ok = IdOf("规则描述")
+	assert.False(t, ok)
+}
+
+func TestMessageFiles(t *testing.T) {
+	messages := Messages(LangEn)
+	assert.Equal(t, "DDL Convention", messages["rule_category.ddl_convention"])
+	assert.Equal(t, "pre-check failed: %v", messages["mysql.audit.check_invalid_error_format"])
+	for id, message := range messages {
+		assert.NotEmpty(t, message, id)
+	}
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+
+	"gorm.io/gorm"
+)
+
+// AuditSuppressionSetting controls the annotations in SQL comments which
+// ignore the audit results of rules, see server/audit_suppression.go.
+type AuditSuppressionSetting struct {
+	Model
+	ProjectId ProjectUID `gorm:"uniqueIndex; not null; type:varchar(255)"`
+	// SuppressibleLevels are the rule levels which are allowed to be suppressed, separated by comma.
+	SuppressibleLevels string `gorm:"type:varchar(255)"`
+	// NeedAcknowledgement requires the approver to acknowledge the suppressions
+	// before approving the workflow.
+	NeedAcknowledgement bool
+}
+
+func NewDefaultAuditSuppressionSetting(projectId ProjectUID) *AuditSuppressionSetting {
+	return &AuditSuppressionSetting{
+		ProjectId: projectId,
+		SuppressibleLevels: strings.Join([]string{
+			string(driverV2.RuleLevelNormal), string(driverV2.RuleLevelNotice), string(driverV2.RuleLevelWarn),
+		}, ","),
+	}
+}
+
+func (s *AuditSuppressionSetting) GetSuppressibleLevels() []string {
+	if s.SuppressibleLevels == "" {
+		return []string{}
+	}
+	return strings.Split(s.SuppressibleLevels, ",")
+}
+
+func (s *AuditSuppressionSetting) CanSuppress(level string) bool {
+	for _, l := range s.GetSuppressibleLevels() {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAuditSuppressionSettingByProjectId returns the default setting if the project has not set it.
+func (s *Storage) GetAuditSuppressionSettingByProjectId(projectId ProjectUID) (*AuditSuppressionSetting, error) {
+	setting := &AuditSuppressionSetting{}
+	err := s.db.Where("project_id = ?", projectId).First(setting).Error
+	if err == gorm.ErrRecordNotFound {
+		return NewDefaultAuditSuppressionSetting(projectId), nil
+	}
+	return setting, errors.ConnectStorageErrWrapper(err)
+}
+
+// GetSuppressedExecuteSQLsByTaskIds returns the SQLs having suppressed audit results.
+func (s *Storage) GetSuppressedExecuteSQLsByTaskIds(taskIds []uint) ([]*ExecuteSQL, error) {
+	executeSQLs := []*ExecuteSQL{}
+	err := s.db.Select("id, task_id, number, audit_results").
+		Where("task_id IN (?)", taskIds).Find(&executeSQLs).Error
+	if err != nil {
+		return nil, errors.ConnectStorageErrWrapper(err)
+	}
+	suppressed := []*ExecuteSQL{}
+	for _, executeSQL := range executeSQLs {
+		for _, result := range executeSQL.AuditResults {
+			if result.Suppressed {
+				suppressed = append(suppressed, executeSQL)
+				break
+			}
+		}
+	}
+	return suppressed, nil
+}
+
+func updateExecuteSQLAuditResults(tx *gorm.DB, executeSQLs []*ExecuteSQL) error {
+	for _, executeSQL := range executeSQLs {
+		if err := tx.Model(&ExecuteSQL{}).Where("id = ?", executeSQL.ID).
+			Update("audit_results", executeSQL.AuditResults).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
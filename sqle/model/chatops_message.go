@@ -0,0 +1,46 @@
+package model
+
+import (
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+// ChatOpsMessage is the interactive approval message sent to the assignee of workflow step
+// through IM platforms like Slack and Microsoft Teams.
+type ChatOpsMessage struct {
+	Model
+	ImType         string `json:"im_type" gorm:"index:idx_chatops_message_ref; type:varchar(255)"`
+	ProjectId      string `json:"project_id" gorm:"type:varchar(255)"`
+	WorkflowId     string `json:"workflow_id" gorm:"index; type:varchar(255)"`
+	WorkflowStepId uint   `json:"workflow_step_id"`
+	// UserId is the uid of SQLE user, ImUserId is the id of the same user in IM platform.
+	UserId    string `json:"user_id" gorm:"type:varchar(255)"`
+	ImUserId  string `json:"im_user_id" gorm:"type:varchar(255)"`
+	ChannelId string `json:"channel_id" gorm:"index:idx_chatops_message_ref; type:varchar(255)"`
+	MessageId string `json:"message_id" gorm:"index:idx_chatops_message_ref; type:varchar(255)"`
+	// Content is the rendered approval message, it is reused when the message is updated.
+	Content JSON   `json:"content" gorm:"type:json"`
+	Status  string `json:"status" gorm:"default:\"initialized\"; type:varchar(255)"`
+}
+
+func (s *Storage) GetChatOpsMessage(imType, channelId, messageId string) (*ChatOpsMessage, bool, error) {
+	message := &ChatOpsMessage{}
+	err := s.db.Where("im_type = ? AND channel_id = ? AND message_id = ?", imType, channelId, messageId).First(message).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return message, true, errors.ConnectStorageErrWrapper(err)
+}
+
+// GetInitializedChatOpsMessagesByWorkflowIds returns the messages of workflows which are still waiting for action.
+func (s *Storage) GetInitializedChatOpsMessagesByWorkflowIds(imType string, workflowIds []string) ([]*ChatOpsMessage, error) {
+	messages := []*ChatOpsMessage{}
+	err := s.db.Where("im_type = ? AND workflow_id IN (?) AND status = ?", imType, workflowIds, ApproveStatusInitialized).
+		Find(&messages).Error
+	return messages, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) UpdateChatOpsMessageStatusByIds(ids []uint, status string) error {
+	err := s.db.Model(&ChatOpsMessage{}).Where("id IN (?)", ids).Update("status", status).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
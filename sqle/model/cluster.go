@@ -0,0 +1,103 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+// clusterLeaderAnchor is the primary key of the only row in cluster_leaders.
+const clusterLeaderAnchor = 1
+
+// ClusterLeader is the lease of the leader in cluster mode. The time of lease
+// is always computed by the database, so the clocks of nodes do not matter.
+type ClusterLeader struct {
+	Anchor        int       `json:"anchor" gorm:"primary_key;auto_increment:false"`
+	ServerId      string    `json:"server_id" gorm:"type:varchar(255);not null"`
+	LeaseExpireAt time.Time `json:"lease_expire_at" gorm:"type:datetime(3)"`
+}
+
+// ClusterNode is a member of the cluster, it is kept alive by heartbeat.
+type ClusterNode struct {
+	ServerId    string    `json:"server_id" gorm:"primary_key;type:varchar(255)"`
+	ReportHost  string    `json:"report_host" gorm:"type:varchar(255)"`
+	StartedAt   time.Time `json:"started_at" gorm:"type:datetime(3)"`
+	HeartbeatAt time.Time `json:"heartbeat_at" gorm:"type:datetime(3)"`
+}
+
+// TryAcquireClusterLeaderLease acquires the lease if it is expired, or renews it
+// if it is held by the server. It returns whether the server holds the lease.
+func (s *Storage) TryAcquireClusterLeaderLease(serverId string, lease time.Duration) (bool, error) {
+	err := s.db.Exec("INSERT IGNORE INTO cluster_leaders (anchor, server_id, lease_expire_at) VALUES (?, '', '1970-01-02 00:00:00')",
+		clusterLeaderAnchor).Error
+	if err != nil {
+		return false, errors.ConnectStorageErrWrapper(err)
+	}
+	result := s.db.Exec("UPDATE cluster_leaders SET server_id = ?, lease_expire_at = DATE_ADD(NOW(3), INTERVAL ? MICROSECOND) "+
+		"WHERE anchor = ? AND (server_id = ? OR lease_expire_at < NOW(3))",
+		serverId, lease.Microseconds(), clusterLeaderAnchor, serverId)
+	if result.Error != nil {
+		return false, errors.ConnectStorageErrWrapper(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseClusterLeaderLease expires the lease held by the server, so another node can take over at once.
+func (s *Storage) ReleaseClusterLeaderLease(serverId string) error {
+	err := s.db.Exec("UPDATE cluster_leaders SET lease_expire_at = DATE_SUB(NOW(3), INTERVAL 1 SECOND) WHERE anchor = ? AND server_id = ?",
+		clusterLeaderAnchor, serverId).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
+
+type ClusterLeaderStatus struct {
+	ServerId      string    `json:"server_id"`
+	LeaseExpireAt time.Time `json:"lease_expire_at"`
+	IsValid       bool      `json:"is_valid"`
+}
+
+// GetClusterLeader returns the current holder of the lease, it returns false if no node has ever held it.
+func (s *Storage) GetClusterLeader() (*ClusterLeaderStatus, bool, error) {
+	leader := &ClusterLeaderStatus{}
+	err := s.db.Model(&ClusterLeader{}).
+		Select("server_id, lease_expire_at, lease_expire_at >= NOW(3) AS is_valid").
+		Where("anchor = ? AND server_id <> ''", clusterLeaderAnchor).
+		Take(leader).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return leader, true, errors.ConnectStorageErrWrapper(err)
+}
+
+// RegisterClusterNode adds the server to the cluster, the start time is reset if it rejoins.
+func (s *Storage) RegisterClusterNode(serverId, reportHost string) error {
+	err := s.db.Exec("INSERT INTO cluster_nodes (server_id, report_host, started_at, heartbeat_at) VALUES (?, ?, NOW(3), NOW(3)) "+
+		"ON DUPLICATE KEY UPDATE report_host = VALUES(report_host), started_at = NOW(3), heartbeat_at = NOW(3)",
+		serverId, reportHost).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) HeartbeatClusterNode(serverId string) error {
+	err := s.db.Exec("UPDATE cluster_nodes SET heartbeat_at = NOW(3) WHERE server_id = ?", serverId).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) RemoveClusterNode(serverId string) error {
+	return errors.ConnectStorageErrWrapper(s.db.Where("server_id = ?", serverId).Delete(&ClusterNode{}).Error)
+}
+
+type ClusterNodeStatus struct {
+	ClusterNode
+	IsAlive bool `json:"is_alive"`
+}
+
+// GetClusterNodes returns all nodes, the node is not alive if it has no heartbeat in the timeout.
+func (s *Storage) GetClusterNodes(timeout time.Duration) ([]*ClusterNodeStatus, error) {
+	nodes := []*ClusterNodeStatus{}
+	err := s.db.Model(&ClusterNode{}).
+		Select("server_id, report_host, started_at, heartbeat_at, "+
+			"heartbeat_at >= DATE_SUB(NOW(3), INTERVAL ? MICROSECOND) AS is_alive", timeout.Microseconds()).
+		Order("server_id").
+		Scan(&nodes).Error
+	return nodes, errors.ConnectStorageErrWrapper(err)
+}
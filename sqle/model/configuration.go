@@ -18,12 +18,24 @@ const (
 	SystemVariableSqleUrl                     = "system_variable_sqle_url"
 	SystemVariableOperationRecordExpiredHours = "system_variable_operation_record_expired_hours"
 	SystemVariableCbOperationLogsExpiredHours = "system_variable_cb_operation_logs_expired_hours"
+	SystemVariableRollbackMode                = "system_variable_rollback_mode"
+
+	SystemVariableMaxConcurrentAudits                   = "system_variable_max_concurrent_audits"
+	SystemVariableMaxConcurrentExecutionsPerInstance    = "system_variable_max_concurrent_executions_per_instance"
+	SystemVariableMaxConcurrentDDLExecutionsPerInstance = "system_variable_max_concurrent_ddl_executions_per_instance"
 )
 
 const (
 	DefaultOperationRecordExpiredHours = 90 * 24
 )
 
+const (
+	// RollbackModeSnapshot generates rollback SQLs from the data queried during audit.
+	RollbackModeSnapshot = "snapshot"
+	// RollbackModeBinlog generates rollback SQLs from the binlog written by the execution, only MySQL is supported.
+	RollbackModeBinlog = "binlog"
+)
+
 // SystemVariable store misc K-V.
 type SystemVariable struct {
 	Key   string `gorm:"primary_key"`
@@ -73,9 +85,64 @@ func (s *Storage) GetAllSystemVariables() (map[string]SystemVariable, error) {
 		}
 	}
 
+	if _, ok := sysVariables[SystemVariableRollbackMode]; !ok {
+		sysVariables[SystemVariableRollbackMode] = SystemVariable{
+			Key:   SystemVariableRollbackMode,
+			Value: RollbackModeSnapshot,
+		}
+	}
+
+	// 0 means unlimited
+	for _, key := range []string{
+		SystemVariableMaxConcurrentAudits,
+		SystemVariableMaxConcurrentExecutionsPerInstance,
+		SystemVariableMaxConcurrentDDLExecutionsPerInstance,
+	} {
+		if _, ok := sysVariables[key]; !ok {
+			sysVariables[key] = SystemVariable{
+				Key:   key,
+				Value: "0",
+			}
+		}
+	}
+
 	return sysVariables, nil
 }
 
+func (s *Storage) GetRollbackMode() (string, error) {
+	sys, err := s.GetAllSystemVariables()
+	if err != nil {
+		return "", err
+	}
+	return sys[SystemVariableRollbackMode].Value, nil
+}
+
+// ConcurrencyLimits limits the actions running at the same time, 0 means unlimited.
+type ConcurrencyLimits struct {
+	MaxAudits                   int
+	MaxExecutionsPerInstance    int
+	MaxDDLExecutionsPerInstance int
+}
+
+func (s *Storage) GetConcurrencyLimits() (*ConcurrencyLimits, error) {
+	sys, err := s.GetAllSystemVariables()
+	if err != nil {
+		return nil, err
+	}
+	limits := &ConcurrencyLimits{}
+	for key, limit := range map[string]*int{
+		SystemVariableMaxConcurrentAudits:                   &limits.MaxAudits,
+		SystemVariableMaxConcurrentExecutionsPerInstance:    &limits.MaxExecutionsPerInstance,
+		SystemVariableMaxConcurrentDDLExecutionsPerInstance: &limits.MaxDDLExecutionsPerInstance,
+	} {
+		*limit, err = strconv.Atoi(sys[key].Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid system variable %s: %v", key, err)
+		}
+	}
+	return limits, nil
+}
+
 func (s *Storage) GetSqleUrl() (string, error) {
 	sys, err := s.GetAllSystemVariables()
 	if err != nil {
@@ -108,6 +175,8 @@ const (
 	ImTypeDingTalk    = "dingTalk"
 	ImTypeFeishuAudit = "feishu_audit"
 	ImTypeWechatAudit = "wechat_audit"
+	ImTypeSlack       = "slack"
+	ImTypeTeams       = "teams"
 )
 
 type IM struct {
@@ -117,6 +186,12 @@ type IM struct {
 	IsEnable         bool   `json:"is_enable" gorm:"column:is_enable"`
 	ProcessCode      string `json:"process_code" gorm:"column:process_code; type:varchar(255)"`
 	EncryptAppSecret string `json:"encrypt_app_secret" gorm:"column:encrypt_app_secret; type:varchar(255)"`
+	// SigningSecret 用于校验IM平台的交互回调请求
+	SigningSecret        string `json:"-" gorm:"-"`
+	EncryptSigningSecret string `json:"encrypt_signing_secret" gorm:"column:encrypt_signing_secret; type:varchar(255)"`
+	TenantId             string `json:"tenant_id" gorm:"column:tenant_id; type:varchar(255)"`
+	// BaseUrl 为空时使用IM平台的官方地址
+	BaseUrl string `json:"base_url" gorm:"column:base_url; type:varchar(255)"`
 	// 类型唯一
 	Type string `json:"type" gorm:"index:unique; type:varchar(255)"`
 }
@@ -139,6 +214,12 @@ func (i *IM) encryptAppSecret(tx *gorm.DB) error {
 		return err
 	}
 	tx.Statement.SetColumn("EncryptAppSecret", data)
+
+	data, err = dmsCommonAes.AesEncrypt(i.SigningSecret)
+	if err != nil {
+		return err
+	}
+	tx.Statement.SetColumn("EncryptSigningSecret", data)
 	return nil
 }
 
@@ -162,6 +243,13 @@ func (i *IM) decryptAppSecret() error {
 		}
 		i.AppSecret = data
 	}
+	if i.SigningSecret == "" && i.EncryptSigningSecret != "" {
+		data, err := dmsCommonAes.AesDecrypt(i.EncryptSigningSecret)
+		if err != nil {
+			return err
+		}
+		i.SigningSecret = data
+	}
 	return nil
 }
 
@@ -0,0 +1,170 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	dmsCommonAes "github.com/actiontech/dms/pkg/dms-common/pkg/aes"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"gorm.io/gorm"
+)
+
+// EventSubscription sends the events of project to the webhook url, the payload is signed by the secret.
+type EventSubscription struct {
+	Model
+	ProjectId     string `json:"project_id" gorm:"index; type:varchar(255)"`
+	Name          string `json:"name" gorm:"type:varchar(255)"`
+	URL           string `json:"url" gorm:"column:url; type:varchar(1024)"`
+	Secret        string `json:"-" gorm:"-"`
+	EncryptSecret string `json:"encrypt_secret" gorm:"type:varchar(255)"`
+	// EventTypes is separated by comma, the subscription receives all events if it is empty.
+	EventTypes   string `json:"event_types" gorm:"type:varchar(1024)"`
+	IsEnabled    bool   `json:"is_enabled"`
+	CreateUserId string `json:"create_user_id" gorm:"type:varchar(255)"`
+}
+
+// BeforeSave is a hook implement gorm model before exec create.
+func (s *EventSubscription) BeforeSave(tx *gorm.DB) error {
+	data, err := dmsCommonAes.AesEncrypt(s.Secret)
+	if err != nil {
+		return err
+	}
+	tx.Statement.SetColumn("EncryptSecret", data)
+	return nil
+}
+
+// AfterFind is a hook implement gorm model after query, ignore err if query from db.
+func (s *EventSubscription) AfterFind(tx *gorm.DB) error {
+	if s.Secret == "" && s.EncryptSecret != "" {
+		data, err := dmsCommonAes.AesDecrypt(s.EncryptSecret)
+		if err != nil {
+			log.NewEntry().Errorf("decrypt secret of event subscription %d failed, error: %v", s.ID, err)
+			return nil
+		}
+		s.Secret = data
+	}
+	return nil
+}
+
+func (s *EventSubscription) GetEventTypes() []string {
+	if s.EventTypes == "" {
+		return []string{}
+	}
+	return strings.Split(s.EventTypes, ",")
+}
+
+func (s *EventSubscription) SetEventTypes(eventTypes []string) {
+	s.EventTypes = strings.Join(eventTypes, ",")
+}
+
+func (s *EventSubscription) Subscribes(eventType string) bool {
+	if s.EventTypes == "" {
+		return true
+	}
+	for _, typ := range s.GetEventTypes() {
+		if typ == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Storage) GetEventSubscriptionsByProject(projectId string) ([]*EventSubscription, error) {
+	subscriptions := []*EventSubscription{}
+	err := s.db.Where("project_id = ?", projectId).Order("id").Find(&subscriptions).Error
+	return subscriptions, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) GetEventSubscriptionByProjectAndId(projectId string, id uint) (*EventSubscription, bool, error) {
+	subscription := &EventSubscription{}
+	err := s.db.Where("project_id = ? AND id = ?", projectId, id).First(subscription).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return subscription, true, errors.ConnectStorageErrWrapper(err)
+}
+
+// GetEnabledEventSubscriptions returns the enabled subscriptions of project, all of them are
+// returned if the project id is empty, which is used by the events of global resources.
+func (s *Storage) GetEnabledEventSubscriptions(projectId string) ([]*EventSubscription, error) {
+	subscriptions := []*EventSubscription{}
+	db := s.db.Where("is_enabled = ?", true)
+	if projectId != "" {
+		db = db.Where("project_id = ?", projectId)
+	}
+	err := db.Find(&subscriptions).Error
+	return subscriptions, errors.ConnectStorageErrWrapper(err)
+}
+
+const (
+	EventDeliveryStatusPending   = "pending"
+	EventDeliveryStatusSucceeded = "succeeded"
+	EventDeliveryStatusFailed    = "failed"
+)
+
+// EventDelivery is the delivery of event to a subscription, it is the item of the retry queue
+// and is kept as the delivery log.
+type EventDelivery struct {
+	Model
+	SubscriptionId uint   `json:"subscription_id" gorm:"index"`
+	EventId        string `json:"event_id" gorm:"type:varchar(255)"`
+	EventType      string `json:"event_type" gorm:"type:varchar(255)"`
+	// Payload is the request body which is signed, it is kept as it is for redelivery.
+	Payload        string     `json:"payload" gorm:"type:mediumtext"`
+	Status         string     `json:"status" gorm:"index:idx_event_delivery_status_next_attempt; type:varchar(32)"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at" gorm:"index:idx_event_delivery_status_next_attempt"`
+	Attempts       uint       `json:"attempts"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `json:"response_body" gorm:"type:varchar(1024)"`
+	Error          string     `json:"error" gorm:"type:varchar(1024)"`
+	DurationMs     int64      `json:"duration_ms"`
+	// RedeliveryOf is the id of the delivery which is redelivered by this one.
+	RedeliveryOf uint `json:"redelivery_of"`
+
+	Subscription *EventSubscription `json:"-" gorm:"foreignkey:SubscriptionId"`
+}
+
+func (s *Storage) CreateEventDeliveries(deliveries []*EventDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return errors.ConnectStorageErrWrapper(s.db.Create(&deliveries).Error)
+}
+
+// GetDueEventDeliveries returns the pending deliveries which should be attempted before the time.
+func (s *Storage) GetDueEventDeliveries(now time.Time, limit int) ([]*EventDelivery, error) {
+	deliveries := []*EventDelivery{}
+	err := s.db.Preload("Subscription").
+		Where("status = ? AND next_attempt_at <= ?", EventDeliveryStatusPending, now).
+		Order("next_attempt_at").Limit(limit).Find(&deliveries).Error
+	return deliveries, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) UpdateEventDelivery(delivery *EventDelivery) error {
+	return errors.ConnectStorageErrWrapper(s.db.Omit("Subscription").Save(delivery).Error)
+}
+
+func (s *Storage) GetEventDeliveriesBySubscription(subscriptionId uint, status string, limit, offset uint32) ([]*EventDelivery, int64, error) {
+	deliveries := []*EventDelivery{}
+	var count int64
+	db := s.db.Model(&EventDelivery{}).Where("subscription_id = ?", subscriptionId)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.ConnectStorageErrWrapper(err)
+	}
+	err := db.Order("id DESC").Limit(int(limit)).Offset(int(offset)).Find(&deliveries).Error
+	return deliveries, count, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) GetEventDeliveryBySubscriptionAndId(subscriptionId, id uint) (*EventDelivery, bool, error) {
+	delivery := &EventDelivery{}
+	err := s.db.Where("subscription_id = ? AND id = ?", subscriptionId, id).First(delivery).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return delivery, true, errors.ConnectStorageErrWrapper(err)
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -135,6 +137,8 @@ type RuleTemplateCustomRule struct {
 	RuleId         string `json:"rule_id" gorm:"primary_key;type:varchar(255)"`
 	RuleLevel      string `json:"level" gorm:"column:level;type:varchar(255)"`
 	RuleDBType     string `json:"rule_db_type" gorm:"column:db_type; not null;type:varchar(255)"`
+	// RuleParams overrides the params of custom rule in the template.
+	RuleParams params.Params `json:"value" gorm:"column:rule_params;type:varchar(1000)"`
 
 	CustomRule *CustomRule `json:"-" gorm:"foreignkey:RuleId;references:RuleId"`
 }
@@ -145,6 +149,7 @@ func NewRuleTemplateCustomRule(t *RuleTemplate, r *CustomRule) RuleTemplateCusto
 		RuleId:         r.RuleId,
 		RuleLevel:      r.Level,
 		RuleDBType:     r.DBType,
+		RuleParams:     r.Params,
 	}
 }
 
@@ -153,6 +158,9 @@ func (rtr *RuleTemplateCustomRule) GetRule() *CustomRule {
 	if rtr.RuleLevel != "" {
 		rule.Level = rtr.RuleLevel
 	}
+	if len(rtr.RuleParams) > 0 {
+		rule.Params = rtr.RuleParams
+	}
 	return rule
 }
 
@@ -515,10 +523,40 @@ type CustomRule struct {
 	Typ         string         `json:"type" gorm:"column:type; not null; type:varchar(255)"`
 	RuleScript  string         `json:"rule_script" gorm:"type:text"`
 	ScriptType  string         `json:"script_type" gorm:"not null; default:\"regular\"; type:varchar(255)"`
+	Params      params.Params  `json:"params" gorm:"type:varchar(1000)"`
+	TestCases   RuleTestCases  `json:"test_cases" gorm:"type:json"`
 	KnowledgeId uint           `json:"knowledge_id"`
 	Knowledge   *RuleKnowledge `json:"knowledge" gorm:"foreignkey:KnowledgeId"`
 }
 
+const (
+	// CustomRuleScriptTypeRegular means the rule script is a regular expression matching SQL.
+	CustomRuleScriptTypeRegular = "regular"
+	// CustomRuleScriptTypeCEL means the rule script is a CEL expression, see driver/mysql/customrule.
+	CustomRuleScriptTypeCEL = "cel"
+)
+
+// RuleTestCase is a sample SQL which is expected to hit the rule or not, the
+// SQL may have several statements and it is hit if any statement is hit.
+type RuleTestCase struct {
+	SQL       string `json:"sql"`
+	ExpectHit bool   `json:"expect_hit"`
+}
+
+type RuleTestCases []RuleTestCase
+
+func (r RuleTestCases) Value() (driver.Value, error) {
+	b, err := json.Marshal(r)
+	return string(b), err
+}
+
+func (r *RuleTestCases) Scan(input interface{}) error {
+	if input == nil {
+		return nil
+	}
+	return json.Unmarshal(input.([]byte), r)
+}
+
 func (s *Storage) GetCustomRuleByRuleId(ruleId string) (*CustomRule, bool, error) {
 	rule := &CustomRule{}
 	err := s.db.Where("rule_id = ?", ruleId).First(rule).Error
@@ -0,0 +1,166 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+const (
+	SchemaDriftObjectTypeTable  = "table"
+	SchemaDriftObjectTypeColumn = "column"
+	SchemaDriftObjectTypeIndex  = "index"
+
+	SchemaDriftChangeTypeCreated  = "created"
+	SchemaDriftChangeTypeDropped  = "dropped"
+	SchemaDriftChangeTypeModified = "modified"
+)
+
+// SchemaSnapshot is a version of the table structures collected by the schema
+// meta audit plan, a new version is saved only when the structures changed.
+type SchemaSnapshot struct {
+	Model
+	AuditPlanId uint   `json:"audit_plan_id" gorm:"index;not null"`
+	Version     uint   `json:"version" gorm:"not null"`
+	Schema      string `json:"schema" gorm:"type:varchar(512)"`
+	// Tables is the map of table name to the output of SHOW CREATE TABLE.
+	Tables     JSON `json:"tables" gorm:"type:json"`
+	TableCount int  `json:"table_count"`
+	// CollectedAt is the start time of the latest collection which got the
+	// same structures, the structures are collected after it.
+	CollectedAt *time.Time `json:"collected_at"`
+
+	Drifts []*SchemaDrift `json:"-" gorm:"foreignkey:SnapshotId"`
+}
+
+// SchemaDrift is a change of table, column or index between a snapshot and its
+// previous version.
+type SchemaDrift struct {
+	Model
+	AuditPlanId uint   `json:"audit_plan_id" gorm:"index;not null"`
+	SnapshotId  uint   `json:"snapshot_id" gorm:"index;not null"`
+	TableName   string `json:"table_name" gorm:"type:varchar(255)"`
+	ObjectType  string `json:"object_type" gorm:"type:varchar(32)"`
+	ObjectName  string `json:"object_name" gorm:"type:varchar(255)"`
+	ChangeType  string `json:"change_type" gorm:"type:varchar(32)"`
+	Before      string `json:"before" gorm:"type:text"`
+	After       string `json:"after" gorm:"type:text"`
+	// OutOfBand is true when no executed workflow task changed the table.
+	OutOfBand bool `json:"out_of_band"`
+	// TaskId is the executed task which changed the table.
+	TaskId uint `json:"task_id"`
+	// ReportId is the audit plan report which carries the drift in its notification.
+	ReportId uint `json:"report_id" gorm:"index"`
+}
+
+func (s *Storage) GetLatestSchemaSnapshot(auditPlanId uint) (*SchemaSnapshot, bool, error) {
+	snapshot := &SchemaSnapshot{}
+	err := s.db.Where("audit_plan_id = ?", auditPlanId).Order("version DESC").First(snapshot).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return snapshot, true, errors.ConnectStorageErrWrapper(err)
+}
+
+// CreateSchemaSnapshot saves the snapshot together with its drifts.
+func (s *Storage) CreateSchemaSnapshot(snapshot *SchemaSnapshot) error {
+	return errors.ConnectStorageErrWrapper(s.db.Create(snapshot).Error)
+}
+
+// TouchSchemaSnapshot records that the structures are unchanged at the latest collection.
+func (s *Storage) TouchSchemaSnapshot(snapshot *SchemaSnapshot, collectedAt time.Time) error {
+	err := s.db.Model(&SchemaSnapshot{}).Where("id = ?", snapshot.ID).Updates(map[string]interface{}{
+		"updated_at":   time.Now(),
+		"collected_at": collectedAt,
+	}).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
+
+// CollectionStartedAt returns the start time of the latest collection which got
+// the structures of snapshot, the snapshots saved before upgrade have no start
+// time, the update time is used.
+func (s *SchemaSnapshot) CollectionStartedAt() time.Time {
+	if s.CollectedAt != nil {
+		return *s.CollectedAt
+	}
+	return s.UpdatedAt
+}
+
+type SchemaSnapshotSummary struct {
+	Id                  uint      `json:"id"`
+	Version             uint      `json:"version"`
+	Schema              string    `json:"schema"`
+	TableCount          int       `json:"table_count"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+	DriftCount          int       `json:"drift_count"`
+	OutOfBandDriftCount int       `json:"out_of_band_drift_count"`
+}
+
+func (s *Storage) GetSchemaSnapshotSummaries(auditPlanId uint, limit, offset int) ([]*SchemaSnapshotSummary, int64, error) {
+	var count int64
+	err := s.db.Model(&SchemaSnapshot{}).Where("audit_plan_id = ?", auditPlanId).Count(&count).Error
+	if err != nil {
+		return nil, 0, errors.ConnectStorageErrWrapper(err)
+	}
+	summaries := []*SchemaSnapshotSummary{}
+	err = s.db.Table("schema_snapshots").
+		Select("schema_snapshots.id, schema_snapshots.version, schema_snapshots.`schema`, schema_snapshots.table_count, "+
+			"schema_snapshots.created_at, schema_snapshots.updated_at, "+
+			"COUNT(schema_drifts.id) AS drift_count, "+
+			"COALESCE(SUM(CASE WHEN schema_drifts.out_of_band THEN 1 ELSE 0 END), 0) AS out_of_band_drift_count").
+		Joins("LEFT JOIN schema_drifts ON schema_drifts.snapshot_id = schema_snapshots.id AND schema_drifts.deleted_at IS NULL").
+		Where("schema_snapshots.audit_plan_id = ? AND schema_snapshots.deleted_at IS NULL", auditPlanId).
+		Group("schema_snapshots.id").
+		Order("schema_snapshots.version DESC").
+		Limit(limit).Offset(offset).
+		Scan(&summaries).Error
+	return summaries, count, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) GetSchemaDrifts(auditPlanId uint, snapshotVersion uint, onlyOutOfBand bool, limit, offset int) ([]*SchemaDrift, int64, error) {
+	query := s.db.Model(&SchemaDrift{}).Where("schema_drifts.audit_plan_id = ?", auditPlanId)
+	if snapshotVersion != 0 {
+		query = query.Joins("JOIN schema_snapshots ON schema_snapshots.id = schema_drifts.snapshot_id").
+			Where("schema_snapshots.version = ?", snapshotVersion)
+	}
+	if onlyOutOfBand {
+		query = query.Where("schema_drifts.out_of_band = ?", true)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, errors.ConnectStorageErrWrapper(err)
+	}
+	drifts := []*SchemaDrift{}
+	err := query.Order("schema_drifts.id DESC").Limit(limit).Offset(offset).Find(&drifts).Error
+	return drifts, count, errors.ConnectStorageErrWrapper(err)
+}
+
+// AttachSchemaDriftsToReport links the drifts not notified yet to the audit plan report.
+func (s *Storage) AttachSchemaDriftsToReport(auditPlanId, reportId uint) error {
+	err := s.db.Model(&SchemaDrift{}).Where("audit_plan_id = ? AND report_id = 0", auditPlanId).
+		Update("report_id", reportId).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) GetSchemaDriftsByReport(reportId uint) ([]*SchemaDrift, error) {
+	drifts := []*SchemaDrift{}
+	err := s.db.Where("report_id = ?", reportId).Order("id").Find(&drifts).Error
+	return drifts, errors.ConnectStorageErrWrapper(err)
+}
+
+// GetExecutedTasksByInstance returns the tasks executing on the instance, and the
+// tasks executed on the instance in the period with their SQLs. The manually
+// executed tasks have no exec_end_at so the update time is used.
+func (s *Storage) GetExecutedTasksByInstance(instanceId uint64, from, to time.Time) ([]*Task, error) {
+	tasks := []*Task{}
+	err := s.db.Preload("ExecuteSQLs").
+		Where("instance_id = ?", instanceId).
+		Where(s.db.Where("status = ?", TaskStatusExecuting).
+			Or("status IN (?) AND COALESCE(exec_end_at, updated_at) BETWEEN ? AND ?",
+				[]string{TaskStatusExecuteSucceeded, TaskStatusExecuteFailed, TaskStatusManuallyExecuted}, from, to)).
+		Order("id").
+		Find(&tasks).Error
+	return tasks, errors.ConnectStorageErrWrapper(err)
+}
@@ -22,6 +22,11 @@ type SQLAuditRecord struct {
 
 	TaskId uint  `gorm:"not null"`
 	Task   *Task `gorm:"foreignkey:TaskId"`
+
+	// the source of SQLs audited from git repository
+	GitRepository string `gorm:"type:varchar(255)"`
+	GitBaseCommit string `gorm:"type:varchar(64)"`
+	GitHeadCommit string `gorm:"type:varchar(64)"`
 }
 
 type SQLAuditRecordUpdateData struct {
@@ -66,3 +71,15 @@ func (s *Storage) GetSQLAuditRecordById(projectId string, SQLAuditRecordId strin
 	}
 	return record, true, nil
 }
+
+func (s *Storage) GetSQLAuditRecordByTaskId(taskId uint) (record *SQLAuditRecord, exist bool, err error) {
+	record = &SQLAuditRecord{}
+	err = s.db.Where("task_id = ?", taskId).First(record).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.New(errors.ConnectStorageError, err)
+	}
+	return record, true, nil
+}
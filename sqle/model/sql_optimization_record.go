@@ -0,0 +1,147 @@
+package model
+
+import (
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+const SQLOptimizationStatusFinish = "finish"
+
+// SQLOptimizationRecord is a batch of SQLs optimized by the built-in rewrite engine.
+type SQLOptimizationRecord struct {
+	Model
+	OptimizationId   string `json:"optimization_id" gorm:"unique;not null;type:varchar(255)"`
+	ProjectId        string `json:"project_id" gorm:"index;not null;type:varchar(255)"`
+	OptimizationName string `json:"optimization_name" gorm:"type:varchar(255)"`
+	InstanceId       uint64 `json:"instance_id"`
+	InstanceName     string `json:"instance_name" gorm:"type:varchar(255)"`
+	SchemaName       string `json:"schema_name" gorm:"type:varchar(255)"`
+	DBType           string `json:"db_type" gorm:"type:varchar(255)"`
+	CreatorId        string `json:"creator_id" gorm:"type:varchar(255)"`
+	Status           string `json:"status" gorm:"type:varchar(32)"`
+	// PerformanceGain is the average cost reduction of the SQLs which are explained before and after rewriting.
+	PerformanceGain        float64 `json:"performance_gain"`
+	NumberOfQuery          int     `json:"number_of_query"`
+	NumberOfSyntaxError    int     `json:"number_of_syntax_error"`
+	NumberOfRewrite        int     `json:"number_of_rewrite"`
+	NumberOfRewrittenQuery int     `json:"number_of_rewritten_query"`
+
+	SQLs []*SQLOptimizationRecordSQL `json:"-" gorm:"foreignkey:OptimizationRecordId"`
+}
+
+// SQLOptimizationRecordSQL is the optimization result of one SQL.
+type SQLOptimizationRecordSQL struct {
+	Model
+	OptimizationRecordId uint   `json:"optimization_record_id" gorm:"index;not null"`
+	Number               uint64 `json:"number"`
+	OriginalSQL          string `json:"original_sql" gorm:"type:text"`
+	OptimizedSQL         string `json:"optimized_sql" gorm:"type:text"`
+	// TriggeredRules is the list of rules fired on the SQL, see server.SQLOptimizationTriggeredRule.
+	TriggeredRules  JSON    `json:"triggered_rules" gorm:"type:json"`
+	NumberOfRewrite int     `json:"number_of_rewrite"`
+	SyntaxError     bool    `json:"syntax_error"`
+	BeforeCost      float64 `json:"before_cost"`
+	AfterCost       float64 `json:"after_cost"`
+	BeforePlan      string  `json:"before_plan" gorm:"type:text"`
+	AfterPlan       string  `json:"after_plan" gorm:"type:text"`
+	// PerformanceImprove is the rate of cost reduction, it is 0 if the SQL is not explained.
+	PerformanceImprove float64 `json:"performance_improve"`
+}
+
+// CreateSQLOptimizationRecord saves the record together with its SQLs.
+func (s *Storage) CreateSQLOptimizationRecord(record *SQLOptimizationRecord) error {
+	return errors.ConnectStorageErrWrapper(s.db.Create(record).Error)
+}
+
+func (s *Storage) GetSQLOptimizationRecord(projectId, optimizationId string) (*SQLOptimizationRecord, bool, error) {
+	record := &SQLOptimizationRecord{}
+	err := s.db.Where("project_id = ? AND optimization_id = ?", projectId, optimizationId).First(record).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return record, true, errors.ConnectStorageErrWrapper(err)
+}
+
+type SQLOptimizationRecordFilter struct {
+	ProjectId      string
+	FuzzySearch    string
+	InstanceName   string
+	CreateTimeFrom string
+	CreateTimeTo   string
+}
+
+func (s *Storage) GetSQLOptimizationRecords(filter SQLOptimizationRecordFilter, limit, offset int) ([]*SQLOptimizationRecord, int64, error) {
+	query := s.db.Model(&SQLOptimizationRecord{}).Where("project_id = ?", filter.ProjectId)
+	if filter.FuzzySearch != "" {
+		fuzzy := "%" + filter.FuzzySearch + "%"
+		query = query.Where("optimization_id LIKE ? OR optimization_name LIKE ?", fuzzy, fuzzy)
+	}
+	if filter.InstanceName != "" {
+		query = query.Where("instance_name = ?", filter.InstanceName)
+	}
+	if filter.CreateTimeFrom != "" {
+		query = query.Where("created_at >= ?", filter.CreateTimeFrom)
+	}
+	if filter.CreateTimeTo != "" {
+		query = query.Where("created_at <= ?", filter.CreateTimeTo)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, errors.ConnectStorageErrWrapper(err)
+	}
+	records := []*SQLOptimizationRecord{}
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&records).Error
+	return records, count, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) GetSQLOptimizationRecordSQLs(recordId uint, limit, offset int) ([]*SQLOptimizationRecordSQL, int64, error) {
+	query := s.db.Model(&SQLOptimizationRecordSQL{}).Where("optimization_record_id = ?", recordId)
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, errors.ConnectStorageErrWrapper(err)
+	}
+	sqls := []*SQLOptimizationRecordSQL{}
+	err := query.Order("number ASC").Limit(limit).Offset(offset).Find(&sqls).Error
+	return sqls, count, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) GetSQLOptimizationRecordSQL(recordId uint, number uint64) (*SQLOptimizationRecordSQL, bool, error) {
+	sql := &SQLOptimizationRecordSQL{}
+	err := s.db.Where("optimization_record_id = ? AND number = ?", recordId, number).First(sql).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return sql, true, errors.ConnectStorageErrWrapper(err)
+}
+
+type SQLOptimizationRecordCount struct {
+	Date  string `json:"date"`
+	Count uint64 `json:"count"`
+}
+
+// GetSQLOptimizationRecordCountsByDay counts the records created in each day of the time range.
+func (s *Storage) GetSQLOptimizationRecordCountsByDay(projectId string, from, to string) ([]*SQLOptimizationRecordCount, error) {
+	counts := []*SQLOptimizationRecordCount{}
+	err := s.db.Model(&SQLOptimizationRecord{}).
+		Select("DATE_FORMAT(created_at, '%Y-%m-%d') AS date, COUNT(*) AS count").
+		Where("project_id = ? AND created_at BETWEEN ? AND ?", projectId, from, to).
+		Group("date").Order("date").
+		Scan(&counts).Error
+	return counts, errors.ConnectStorageErrWrapper(err)
+}
+
+type InstancePerformanceImprove struct {
+	InstanceName          string  `json:"instance_name"`
+	AvgPerformanceImprove float64 `json:"avg_performance_improve"`
+}
+
+// GetAvgPerformanceImproveByInstance averages the performance gain of the records which are optimized with instance.
+func (s *Storage) GetAvgPerformanceImproveByInstance(projectId string) ([]*InstancePerformanceImprove, error) {
+	improves := []*InstancePerformanceImprove{}
+	err := s.db.Model(&SQLOptimizationRecord{}).
+		Select("instance_name, AVG(performance_gain) AS avg_performance_improve").
+		Where("project_id = ? AND instance_name <> '' AND status = ?", projectId, SQLOptimizationStatusFinish).
+		Group("instance_name").
+		Scan(&improves).Error
+	return improves, errors.ConnectStorageErrWrapper(err)
+}
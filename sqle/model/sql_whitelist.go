@@ -1,7 +1,9 @@
 package model
 
 import (
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/errors"
 
@@ -11,6 +13,7 @@ import (
 const (
 	SQLWhitelistExactMatch = "exact_match"
 	SQLWhitelistFPMatch    = "fp_match"
+	SQLWhitelistRegexMatch = "regex_match"
 )
 
 type SqlWhitelist struct {
@@ -23,6 +26,21 @@ type SqlWhitelist struct {
 	// MessageDigest deprecated after 1.1.0, keep it for compatibility.
 	MessageDigest string `json:"message_digest" gorm:"type:char(32) not null comment 'md5 data';" `
 	MatchType     string `json:"match_type" gorm:"default:\"exact_match\""`
+
+	// InstanceNames and SchemaNames limit the scope of whitelist, empty means all.
+	InstanceNames RowList `json:"instance_names" gorm:"type:varchar(1000)"`
+	SchemaNames   RowList `json:"schema_names" gorm:"type:varchar(1000)"`
+	// RuleNames are the rules skipped by whitelist, empty means all rules are skipped.
+	RuleNames RowList    `json:"rule_names" gorm:"type:varchar(2000)"`
+	ExpiredAt *time.Time `json:"expired_at"`
+	Owner     string     `json:"owner" gorm:"type:varchar(255)"`
+
+	MatchedCount    uint64     `json:"matched_count" gorm:"not null;default:0"`
+	LastMatchedTime *time.Time `json:"last_matched_time"`
+}
+
+func (s *SqlWhitelist) IsExpired(now time.Time) bool {
+	return s.ExpiredAt != nil && !s.ExpiredAt.After(now)
 }
 
 // BeforeSave is a hook implement gorm model before exec create
@@ -120,3 +138,26 @@ func (s *Storage) GetSqlWhitelistByProjectId(projectId string) ([]SqlWhitelist,
 // 		Error
 // 	return count, errors.ConnectStorageErrWrapper(err)
 // }
+
+// IncreaseSqlWhitelistMatchedCount adds the matched count of whitelist by id.
+// The rows are updated in the order of id, so the concurrent audits lock them
+// in the same order and do not deadlock.
+func (s *Storage) IncreaseSqlWhitelistMatchedCount(matchedCounts map[uint]uint64, matchedTime time.Time) error {
+	ids := make([]uint, 0, len(matchedCounts))
+	for id := range matchedCounts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return s.Tx(func(tx *gorm.DB) error {
+		for _, id := range ids {
+			err := tx.Model(&SqlWhitelist{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+				"matched_count":     gorm.Expr("matched_count + ?", matchedCounts[id]),
+				"last_matched_time": matchedTime,
+			}).Error
+			if err != nil {
+				return errors.ConnectStorageErrWrapper(err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_IncreaseSqlWhitelistMatchedCount(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("5.7"))
+	InitMockStorage(mockDB)
+
+	matchedTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	// the rows are updated in the order of id
+	for _, id := range []uint{1, 2, 3, 5} {
+		mock.ExpectExec("UPDATE `sql_whitelist` SET `last_matched_time`=?,`matched_count`=matched_count + ? WHERE id = ? AND `sql_whitelist`.`deleted_at` IS NULL").
+			WithArgs(matchedTime, uint64(id*10), id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+	mock.ExpectClose()
+	err = GetStorage().IncreaseSqlWhitelistMatchedCount(map[uint]uint64{5: 50, 2: 20, 3: 30, 1: 10}, matchedTime)
+	assert.NoError(t, err)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/utils"
+	"gorm.io/gorm"
+)
+
+// SqledAction is an audit/execute/rollback action of task which is waiting or
+// running in Sqled. It is persisted so that the action is not lost when SQLE
+// restarts, and the action of a dead node can be picked up by other nodes
+// after its lease is expired.
+type SqledAction struct {
+	ID        uint      `gorm:"primary_key"`
+	CreatedAt time.Time `gorm:"default:current_timestamp(3)"`
+	// there is at most one action for a task at the same time.
+	TaskId uint `gorm:"uniqueIndex;not null"`
+	Type   int  `gorm:"not null"`
+	// LeaseOwner is the id of the node which holds the action.
+	LeaseOwner     string    `gorm:"type:varchar(255);not null"`
+	LeaseExpiredAt time.Time `gorm:"index;not null"`
+	// ClaimToken is changed every time the action is created or claimed, the
+	// lease is renewed and released by the token, so the holder which has lost
+	// the lease does not touch the lease of the new holder.
+	ClaimToken string `gorm:"type:varchar(64);not null;default:''"`
+}
+
+// CreateSqledAction creates the action if there is no action of the task or
+// the lease of the existing action is expired. It returns false if the task
+// has an action held by a node.
+func (s *Storage) CreateSqledAction(action *SqledAction) (bool, error) {
+	created := false
+	err := s.Tx(func(tx *gorm.DB) error {
+		existing := &SqledAction{}
+		err := tx.Where("task_id = ?", action.TaskId).First(existing).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err == nil {
+			if existing.LeaseExpiredAt.After(time.Now()) {
+				return nil
+			}
+			if err := tx.Delete(existing).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(action).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+// RenewSqledActionLeases extends the leases of the actions claimed with the tokens.
+func (s *Storage) RenewSqledActionLeases(tokens []string, expiredAt time.Time) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	err := s.db.Model(&SqledAction{}).
+		Where("claim_token IN (?)", tokens).
+		Update("lease_expired_at", expiredAt).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
+
+// ClaimSqledActions takes over the actions whose lease is expired, and the
+// actions held by owner if claimOwned is true, which are left by the previous
+// process of the node. The actions of excludeTaskIds are running on the node,
+// they are not claimed even if their leases are expired.
+func (s *Storage) ClaimSqledActions(owner string, expiredAt time.Time, claimOwned bool, excludeTaskIds []uint) ([]*SqledAction, error) {
+	candidates := []*SqledAction{}
+	query := s.db.Where("lease_expired_at < ?", time.Now())
+	if claimOwned {
+		query = query.Or("lease_owner = ?", owner)
+	}
+	query = s.db.Where(query)
+	if len(excludeTaskIds) > 0 {
+		query = query.Where("task_id NOT IN (?)", excludeTaskIds)
+	}
+	if err := query.Order("id").Find(&candidates).Error; err != nil {
+		return nil, errors.New(errors.ConnectStorageError, err)
+	}
+
+	claimed := make([]*SqledAction, 0, len(candidates))
+	for _, action := range candidates {
+		token, err := utils.GenUid()
+		if err != nil {
+			return nil, err
+		}
+		// the action may be claimed by other nodes at the same time, only one of them will succeed.
+		result := s.db.Model(&SqledAction{}).
+			Where("id = ? AND claim_token = ?", action.ID, action.ClaimToken).
+			Updates(map[string]interface{}{
+				"lease_owner":      owner,
+				"lease_expired_at": expiredAt,
+				"claim_token":      token,
+			})
+		if result.Error != nil {
+			return nil, errors.New(errors.ConnectStorageError, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		action.LeaseOwner = owner
+		action.LeaseExpiredAt = expiredAt
+		action.ClaimToken = token
+		claimed = append(claimed, action)
+	}
+	return claimed, nil
+}
+
+// DeleteSqledAction deletes the action only if it is still claimed with the token.
+func (s *Storage) DeleteSqledAction(taskId uint, token string) error {
+	err := s.db.Where("task_id = ? AND claim_token = ?", taskId, token).Delete(&SqledAction{}).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
+
+// GetExecutingTasksWithoutSqledAction returns tasks whose status is executing
+// but no node is executing them, they are left by a crashed or upgraded SQLE.
+func (s *Storage) GetExecutingTasksWithoutSqledAction() ([]*Task, error) {
+	tasks := []*Task{}
+	err := s.db.Where("status = ?", TaskStatusExecuting).
+		Where("id NOT IN (?)", s.db.Model(&SqledAction{}).Select("task_id")).
+		Preload("ExecuteSQLs").Find(&tasks).Error
+	return tasks, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) GetWorkflowRecordIdByTaskId(taskId uint) (uint, bool, error) {
+	record := &WorkflowInstanceRecord{}
+	err := s.db.Where("task_id = ?", taskId).First(record).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.New(errors.ConnectStorageError, err)
+	}
+	return record.WorkflowRecordId, true, nil
+}
+
+func (s *Storage) GetAllSqledActions() ([]*SqledAction, error) {
+	actions := []*SqledAction{}
+	err := s.db.Order("id").Find(&actions).Error
+	return actions, errors.ConnectStorageErrWrapper(err)
+}
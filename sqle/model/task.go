@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/utils"
 
 	"gorm.io/gorm"
@@ -55,8 +56,12 @@ type Task struct {
 	CreateUserId    uint64
 	ExecStartAt     *time.Time
 	ExecEndAt       *time.Time
+	RehearsedAt     *time.Time
 	ExecMode        string         `json:"exec_mode" gorm:"default:'sqls';type:varchar(255)" example:"sqls"`
 	FileOrderMethod string         `json:"file_order_method" gorm:"column:file_order_method;type:varchar(255)"`
+	RehearsalStatus string         `json:"rehearsal_status" gorm:"type:varchar(32)"`
+	GhostConfig     JSON           `json:"ghost_config" gorm:"type:json"`
+	ExecCheckpoint  uint           `json:"exec_checkpoint"` // the number of the last ExecuteSQL executed or skipped in order
 	Instance        *Instance      `json:"-" gorm:"-"`
 	ExecuteSQLs     []*ExecuteSQL  `json:"-" gorm:"foreignkey:TaskId"`
 	RollbackSQLs    []*RollbackSQL `json:"-" gorm:"foreignkey:TaskId"`
@@ -70,6 +75,21 @@ func (t *Task) InstanceName() string {
 	return ""
 }
 
+// GetGhostConfig returns the gh-ost config which overrides the config of instance.
+func (t *Task) GetGhostConfig() (map[string]string, error) {
+	config := map[string]string{}
+	if len(t.GhostConfig) == 0 {
+		return config, nil
+	}
+	if err := json.Unmarshal(t.GhostConfig, &config); err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = map[string]string{}
+	}
+	return config, nil
+}
+
 func (t *Task) TaskExecStartAt() string {
 	if t.ExecStartAt == nil {
 		return ""
@@ -98,6 +118,11 @@ const (
 	SQLExecuteStatusManuallyExecuted = "manually_executed"
 	SQLExecuteStatusTerminateSucc    = "terminate_succeeded"
 	SQLExecuteStatusTerminateFailed  = "terminate_failed"
+	// SQLExecuteStatusUnknown means SQLE exited while executing the SQL, it is
+	// unknown whether the SQL is executed successfully.
+	SQLExecuteStatusUnknown = "unknown"
+	// SQLExecuteStatusSkipped means the SQL is skipped by user when resuming the failed execution.
+	SQLExecuteStatusSkipped = "skipped"
 )
 
 type BaseSQL struct {
@@ -137,6 +162,10 @@ func (s *BaseSQL) GetExecStatusDesc() string {
 		return "执行成功"
 	case SQLExecuteStatusManuallyExecuted:
 		return "人工执行"
+	case SQLExecuteStatusUnknown:
+		return "执行结果未知"
+	case SQLExecuteStatusSkipped:
+		return "已跳过"
 	default:
 		return "未知"
 	}
@@ -146,6 +175,44 @@ type AuditResult struct {
 	Level    string `json:"level"`
 	Message  string `json:"message"`
 	RuleName string `json:"rule_name"`
+	// Suppressed reports whether the result is ignored by the annotation in
+	// SQL comment, it does not affect the audit level of SQL.
+	Suppressed     bool   `json:"suppressed,omitempty"`
+	SuppressReason string `json:"suppress_reason,omitempty"`
+	// AcknowledgedBy is the id of approver who acknowledged the suppression.
+	AcknowledgedBy string `json:"acknowledged_by,omitempty"`
+
+	// MessageId and MessageArgs keep the message pattern in locale catalogue, so
+	// the result can be rendered in the language of reader later.
+	MessageId   string   `json:"message_id,omitempty"`
+	MessageArgs []string `json:"message_args,omitempty"`
+	// I18nMessages is the message in other languages given by the plugin.
+	I18nMessages map[string]string `json:"i18n_messages,omitempty"`
+}
+
+// GetMessage returns the message in the language, it returns the stored message
+// if the result can not be rendered in the language.
+func (a *AuditResult) GetMessage(lang string) string {
+	if message, ok := a.I18nMessages[lang]; ok && message != "" {
+		return message
+	}
+	if lang == "" || lang == locale.SourceLang || a.MessageId == "" {
+		return a.Message
+	}
+	pattern, ok := locale.Message(lang, a.MessageId)
+	if !ok {
+		return a.Message
+	}
+	args := make([]interface{}, len(a.MessageArgs))
+	for i, arg := range a.MessageArgs {
+		args[i] = locale.Translate(lang, arg)
+	}
+	message := fmt.Sprintf(pattern, args...)
+	// the args do not match the translated pattern
+	if strings.Contains(message, "%!") {
+		return a.Message
+	}
+	return message
 }
 
 type AuditResults []AuditResult
@@ -159,24 +226,45 @@ func (a *AuditResults) Scan(input interface{}) error {
 	return json.Unmarshal(input.([]byte), a)
 }
 
+const suppressedAuditResultFormat = "[已忽略][%s]%s, 原因: %s"
+
+func init() {
+	locale.Register(locale.SourceLang, "audit_result.suppressed", suppressedAuditResultFormat)
+	locale.Register(locale.SourceLang, "audit_result.passed", auditResultPassedDesc)
+}
+
 func (a *AuditResults) String() string {
+	return a.Render(locale.DefaultLang())
+}
+
+// Render returns the results in the language, one result per line.
+func (a *AuditResults) Render(lang string) string {
 	msgs := make([]string, len(*a))
 	for i := range *a {
 		res := (*a)[i]
-		msg := fmt.Sprintf("[%s]%s", res.Level, res.Message)
+		msg := fmt.Sprintf("[%s]%s", res.Level, res.GetMessage(lang))
+		if res.Suppressed {
+			msg = locale.Sprintf(lang, "audit_result.suppressed", suppressedAuditResultFormat, res.Level, res.GetMessage(lang), res.SuppressReason)
+		}
 		msgs[i] = msg
 	}
 	return strings.Join(msgs, "\n")
 }
 
 func (a *AuditResults) Append(level, ruleName, message string) {
+	a.AppendResult(AuditResult{Level: level, RuleName: ruleName, Message: message})
+}
+
+// AppendResult appends the result which is not suppressed, it is ignored if the
+// same result exists.
+func (a *AuditResults) AppendResult(result AuditResult) {
 	for i := range *a {
 		ar := (*a)[i]
-		if ar.Level == level && ar.RuleName == ruleName && ar.Message == message {
+		if !ar.Suppressed && ar.Level == result.Level && ar.RuleName == result.RuleName && ar.Message == result.Message {
 			return
 		}
 	}
-	*a = append(*a, AuditResult{Level: level, RuleName: ruleName, Message: message})
+	*a = append(*a, result)
 }
 
 type ExecuteSQL struct {
@@ -207,20 +295,22 @@ func (s *ExecuteSQL) GetAuditStatusDesc() string {
 	}
 }
 
-func (s *ExecuteSQL) GetAuditResults() string {
+func (s *ExecuteSQL) GetAuditResults(lang string) string {
 	if len(s.AuditResults) == 0 {
 		return ""
 	}
 
-	return s.AuditResults.String()
+	return s.AuditResults.Render(lang)
 }
 
-func (s *ExecuteSQL) GetAuditResultDesc() string {
+const auditResultPassedDesc = "审核通过"
+
+func (s *ExecuteSQL) GetAuditResultDesc(lang string) string {
 	if len(s.AuditResults) == 0 {
-		return "审核通过"
+		return locale.Localize(lang, "audit_result.passed", auditResultPassedDesc)
 	}
 
-	return s.AuditResults.String()
+	return s.AuditResults.Render(lang)
 }
 
 func (s *Storage) BatchSaveExecuteSqls(models []*ExecuteSQL) error {
@@ -288,6 +378,24 @@ func (t *Task) HasDoingRollback() bool {
 	return false
 }
 
+func (t *Task) GetRollbackSQLByExecuteSQLId(executeSQLId uint) *RollbackSQL {
+	for _, rollbackSQL := range t.RollbackSQLs {
+		if rollbackSQL.ExecuteSQLId == executeSQLId {
+			return rollbackSQL
+		}
+	}
+	return nil
+}
+
+func (t *Task) GetExecuteSQLById(executeSQLId uint) *ExecuteSQL {
+	for _, executeSQL := range t.ExecuteSQLs {
+		if executeSQL.ID == executeSQLId {
+			return executeSQL
+		}
+	}
+	return nil
+}
+
 func (s *Storage) GetTaskStatusByID(id string) (string, error) {
 	task := &Task{}
 	err := s.db.Select("status").Where("id = (?)", id).First(task).Error
@@ -471,12 +579,12 @@ type TaskSQLDetail struct {
 	SQLType       sql.NullString `json:"sql_type"`
 }
 
-func (t *TaskSQLDetail) GetAuditResults() string {
+func (t *TaskSQLDetail) GetAuditResults(lang string) string {
 	if len(t.AuditResults) == 0 {
 		return ""
 	}
 
-	return t.AuditResults.String()
+	return t.AuditResults.Render(lang)
 }
 
 var taskSQLsQueryTpl = `SELECT e_sql.number, e_sql.description, e_sql.content AS exec_sql,  e_sql.source_file AS sql_source_file, e_sql.start_line AS sql_start_line, e_sql.sql_type, r_sql.content AS rollback_sql,
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+const (
+	// ExecutionAttemptOperationExecute executes all SQLs of the task.
+	ExecutionAttemptOperationExecute = "execute"
+	// ExecutionAttemptOperationResume executes the SQLs from the failed one to the end.
+	ExecutionAttemptOperationResume = "resume"
+	// ExecutionAttemptOperationSkip skips the failed SQL and executes the rest.
+	ExecutionAttemptOperationSkip = "skip"
+	// ExecutionAttemptOperationRetry executes the failed SQL only.
+	ExecutionAttemptOperationRetry = "retry"
+)
+
+const (
+	ExecutionAttemptStatusPending   = "pending"
+	ExecutionAttemptStatusExecuting = "executing"
+	ExecutionAttemptStatusSucceeded = "succeeded"
+	ExecutionAttemptStatusFailed    = "failed"
+)
+
+// TaskExecutionAttempt is a run of the task execution, a failed execution can
+// be resumed, skipped or retried in the later attempts.
+type TaskExecutionAttempt struct {
+	Model
+	TaskId         uint   `json:"task_id" gorm:"index;not null"`
+	Operation      string `json:"operation" gorm:"type:varchar(32)"`
+	Status         string `json:"status" gorm:"type:varchar(32)"`
+	OperatorUserId string `json:"operator_user_id" gorm:"type:varchar(255)"`
+	// Reason is required when the failed SQL is skipped.
+	Reason string `json:"reason" gorm:"type:text"`
+	// FromNumber is the number of the first ExecuteSQL handled in this attempt.
+	FromNumber uint `json:"from_number"`
+	// Checkpoint is the checkpoint of task after this attempt.
+	Checkpoint  uint       `json:"checkpoint"`
+	ExecStartAt *time.Time `json:"exec_start_at"`
+	ExecEndAt   *time.Time `json:"exec_end_at"`
+
+	SQLs []*ExecutionAttemptSQL `json:"-" gorm:"foreignkey:AttemptId"`
+}
+
+// ExecutionAttemptSQL keeps the result of an ExecuteSQL in an attempt, since
+// the result saved in ExecuteSQL is overwritten by the later attempts.
+type ExecutionAttemptSQL struct {
+	Model
+	AttemptId    uint   `json:"-" gorm:"index;not null"`
+	ExecuteSQLId uint   `json:"execute_sql_id"`
+	Number       uint   `json:"number"`
+	ExecStatus   string `json:"exec_status" gorm:"type:varchar(32)"`
+	ExecResult   string `json:"exec_result" gorm:"type:text"`
+	RowAffects   int64  `json:"row_affects"`
+}
+
+func (s *Storage) CreateTaskExecutionAttempt(attempt *TaskExecutionAttempt) error {
+	return errors.ConnectStorageErrWrapper(s.db.Create(attempt).Error)
+}
+
+func (s *Storage) DeleteTaskExecutionAttempt(attempt *TaskExecutionAttempt) error {
+	return errors.ConnectStorageErrWrapper(s.db.Unscoped().Delete(attempt).Error)
+}
+
+// GetPendingTaskExecutionAttempt returns the latest attempt of the task which is not started.
+func (s *Storage) GetPendingTaskExecutionAttempt(taskId uint) (*TaskExecutionAttempt, bool, error) {
+	attempt := &TaskExecutionAttempt{}
+	err := s.db.Where("task_id = ? AND status = ?", taskId, ExecutionAttemptStatusPending).
+		Order("id DESC").First(attempt).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return attempt, true, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) GetTaskExecutionAttempts(taskId uint) ([]*TaskExecutionAttempt, error) {
+	attempts := []*TaskExecutionAttempt{}
+	err := s.db.Where("task_id = ?", taskId).Preload("SQLs", func(db *gorm.DB) *gorm.DB {
+		return db.Order("number")
+	}).Order("id").Find(&attempts).Error
+	return attempts, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) UpdateTaskExecutionAttempt(attempt *TaskExecutionAttempt, attrs map[string]interface{}) error {
+	err := s.db.Model(&TaskExecutionAttempt{}).Where("id = ?", attempt.ID).Updates(attrs).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
+
+// FinishTaskExecutionAttempt saves the status and the SQL results of the attempt.
+func (s *Storage) FinishTaskExecutionAttempt(attempt *TaskExecutionAttempt) error {
+	return s.Tx(func(tx *gorm.DB) error {
+		if len(attempt.SQLs) > 0 {
+			if err := tx.Create(attempt.SQLs).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&TaskExecutionAttempt{}).Where("id = ?", attempt.ID).Updates(map[string]interface{}{
+			"status":      attempt.Status,
+			"checkpoint":  attempt.Checkpoint,
+			"exec_end_at": attempt.ExecEndAt,
+		}).Error
+	})
+}
+
+// FailExecutingTaskExecutionAttempts marks the attempts which are interrupted as failed.
+func (s *Storage) FailExecutingTaskExecutionAttempts(taskId uint) error {
+	err := s.db.Model(&TaskExecutionAttempt{}).
+		Where("task_id = ? AND status = ?", taskId, ExecutionAttemptStatusExecuting).
+		Updates(map[string]interface{}{"status": ExecutionAttemptStatusFailed, "exec_end_at": time.Now()}).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) UpdateTaskExecCheckpoint(taskId uint, checkpoint uint) error {
+	err := s.db.Model(&Task{}).Where("id = ?", taskId).Update("exec_checkpoint", checkpoint).Error
+	return errors.ConnectStorageErrWrapper(err)
+}
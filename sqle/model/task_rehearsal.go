@@ -0,0 +1,75 @@
+package model
+
+import (
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+const (
+	RehearsalStatusRehearsing = "rehearsing"
+	RehearsalStatusSucceeded  = "succeeded"
+	RehearsalStatusFailed     = "failed"
+)
+
+const (
+	RehearsalSQLStatusSucceeded = "succeeded"
+	RehearsalSQLStatusFailed    = "failed"
+	RehearsalSQLStatusSkipped   = "skipped"
+)
+
+const (
+	// RehearsalMethodTransaction executes the SQL in a transaction which is always rolled back.
+	RehearsalMethodTransaction = "transaction"
+	// RehearsalMethodGhost executes the SQL by gh-ost with dry-run.
+	RehearsalMethodGhost = "gh-ost"
+)
+
+// RehearsalSQL is the result of rehearsing an execute SQL of task, the SQL is
+// executed without changing the data or schema before the real execution.
+type RehearsalSQL struct {
+	Model
+	TaskId       uint   `json:"-" gorm:"index;not null"`
+	ExecuteSQLId uint   `json:"execute_sql_id" gorm:"not null"`
+	Number       uint   `json:"number"`
+	Content      string `json:"sql" gorm:"type:longtext"`
+	Method       string `json:"method" gorm:"type:varchar(32)"`
+	Status       string `json:"status" gorm:"type:varchar(32)"`
+	RowsAffected int64  `json:"rows_affected"`
+	// ExecutionTime is in milliseconds.
+	ExecutionTime uint64 `json:"execution_time"`
+	Message       string `json:"message" gorm:"type:text"`
+}
+
+// SaveRehearsalSQLs replaces the rehearsal results of the task.
+func (s *Storage) SaveRehearsalSQLs(task *Task, rehearsalSQLs []*RehearsalSQL) error {
+	return s.Tx(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("task_id = ?", task.ID).Delete(&RehearsalSQL{}).Error; err != nil {
+			return err
+		}
+		if len(rehearsalSQLs) > 0 {
+			if err := tx.Create(rehearsalSQLs).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&Task{}).Where("id = ?", task.ID).Updates(map[string]interface{}{
+			"rehearsal_status": task.RehearsalStatus,
+			"rehearsed_at":     task.RehearsedAt,
+		}).Error
+	})
+}
+
+// FailRehearsingTasksWithoutSqledAction marks the tasks left in rehearsing
+// status by a crashed or restarted SQLE failed, the tasks whose rehearsal
+// action is still kept are not changed.
+func (s *Storage) FailRehearsingTasksWithoutSqledAction() (int64, error) {
+	db := s.db.Model(&Task{}).Where("rehearsal_status = ?", RehearsalStatusRehearsing).
+		Where("id NOT IN (?)", s.db.Model(&SqledAction{}).Select("task_id")).
+		Update("rehearsal_status", RehearsalStatusFailed)
+	return db.RowsAffected, errors.ConnectStorageErrWrapper(db.Error)
+}
+
+func (s *Storage) GetRehearsalSQLsByTaskId(taskId uint) ([]*RehearsalSQL, error) {
+	rehearsalSQLs := []*RehearsalSQL{}
+	err := s.db.Where("task_id = ?", taskId).Order("number").Find(&rehearsalSQLs).Error
+	return rehearsalSQLs, errors.ConnectStorageErrWrapper(err)
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditResultsRender(t *testing.T) {
+	locale.RegisterSource("test.index_exist", "索引 %s 已存在")
+	locale.Register(locale.LangEn, "test.index_exist", "index %v already exists")
+	locale.RegisterSource("test.anonymous", "(匿名)")
+	locale.Register(locale.LangEn, "test.anonymous", "(anonymous)")
+
+	results := AuditResults{
+		{Level: "error", Message: "索引 (匿名) 已存在", MessageId: "test.index_exist", MessageArgs: []string{"(匿名)"}},
+		{Level: "warn", Message: "插件的消息", I18nMessages: map[string]string{locale.LangEn: "message of plugin"}},
+		{Level: "notice", Message: "旧的审核结果"},
+		{Level: "warn", Message: "索引 idx 已存在", MessageId: "test.index_exist", MessageArgs: []string{"idx"},
+			Suppressed: true, SuppressReason: "reason"},
+	}
+	assert.Equal(t, "[error]index (anonymous) already exists\n"+
+		"[warn]message of plugin\n"+
+		"[notice]旧的审核结果\n"+
+		"[suppressed][warn]index idx already exists, reason: reason", results.Render(locale.LangEn))
+	assert.Equal(t, "[error]索引 (匿名) 已存在\n"+
+		"[warn]插件的消息\n"+
+		"[notice]旧的审核结果\n"+
+		"[已忽略][warn]索引 idx 已存在, 原因: reason", results.Render(locale.LangZh))
+	assert.Equal(t, results.Render(locale.LangZh), results.String())
+
+	// the args do not match the pattern
+	result := AuditResult{Message: "索引 idx 已存在", MessageId: "test.index_exist"}
+	assert.Equal(t, "索引 idx 已存在", result.GetMessage(locale.LangEn))
+	result.MessageArgs = []string{"idx", "extra"}
+	assert.Equal(t, "索引 idx 已存在", result.GetMessage(locale.LangEn))
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"github.com/actiontech/sqle/sqle/errors"
+	"gorm.io/gorm"
+)
+
+// UserPreference keeps the settings of user in SQLE, the user itself is managed by DMS.
+type UserPreference struct {
+	UserId string `json:"user_id" gorm:"primary_key;type:varchar(255)"`
+	// Language is the language of messages, it is empty if the user does not choose one.
+	Language string `json:"language" gorm:"type:varchar(32)"`
+}
+
+func (s *Storage) GetUserPreference(userId string) (*UserPreference, bool, error) {
+	preference := &UserPreference{}
+	err := s.db.Where("user_id = ?", userId).First(preference).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return preference, true, errors.ConnectStorageErrWrapper(err)
+}
+
+func (s *Storage) SaveUserPreference(preference *UserPreference) error {
+	return errors.ConnectStorageErrWrapper(s.db.Save(preference).Error)
+}
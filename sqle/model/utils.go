@@ -159,6 +159,21 @@ var autoMigrateList = []interface{}{
 	&SQLDevRecord{},
 	&WechatRecord{},
 	&FeishuScheduledRecord{},
+	&SqledAction{},
+	&RehearsalSQL{},
+	&TaskExecutionAttempt{},
+	&ExecutionAttemptSQL{},
+	&AuditSuppressionSetting{},
+	&SchemaSnapshot{},
+	&SchemaDrift{},
+	&SQLOptimizationRecord{},
+	&SQLOptimizationRecordSQL{},
+	&ClusterLeader{},
+	&ClusterNode{},
+	&UserPreference{},
+	&ChatOpsMessage{},
+	&EventSubscription{},
+	&EventDelivery{},
 }
 
 func (s *Storage) AutoMigrate() error {
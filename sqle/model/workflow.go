@@ -699,6 +699,20 @@ func (s *Storage) UpdateWorkflowStep(w *Workflow, operateStep *WorkflowStep) err
 	})
 }
 
+// ApproveWorkflowStep updates the approved workflow step, and the audit results
+// acknowledged by the approver in the same transaction.
+func (s *Storage) ApproveWorkflowStep(w *Workflow, operateStep *WorkflowStep, acknowledgedSQLs []*ExecuteSQL) error {
+	return s.Tx(func(tx *gorm.DB) error {
+		if err := updateExecuteSQLAuditResults(tx, acknowledgedSQLs); err != nil {
+			return err
+		}
+		if err := updateWorkflowStatus(tx, w); err != nil {
+			return err
+		}
+		return updateWorkflowStep(tx, operateStep)
+	})
+}
+
 // UpdateWorkflowExecInstanceRecord， 用于更新SQL上线状态
 func (s *Storage) UpdateWorkflowExecInstanceRecord(w *Workflow, operateStep *WorkflowStep, needExecInstanceRecords []*WorkflowInstanceRecord) error {
 	return s.Tx(func(tx *gorm.DB) error {
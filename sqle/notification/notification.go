@@ -3,7 +3,6 @@ package notification
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -13,8 +12,10 @@ import (
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/dms"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/eventbus"
 )
 
 type Notification interface {
@@ -30,6 +31,13 @@ type WorkflowNotifyConfig struct {
 	SQLEUrl *string
 }
 
+// message returns the message of notification in the language, the notifications
+// are sent to several users at once, so they are in the default language of SQLE.
+// The messages are translated in "locale/messages/notification.<language>.yaml".
+func message(lang, id, fallback string, args ...interface{}) string {
+	return locale.Sprintf(lang, "notification."+id, fallback, args...)
+}
+
 func Notify(notification Notification, userIds []string) error {
 	return dmsobject.Notify(context.TODO(), controller.GetDMSServerAddress(), v1.NotificationReq{
 		Notification: &v1.Notification{
@@ -70,6 +78,7 @@ type WorkflowNotification struct {
 	notifyType WorkflowNotifyType
 	workflow   *model.Workflow
 	config     WorkflowNotifyConfig
+	lang       string
 }
 
 func NewWorkflowNotification(w *model.Workflow, notifyType WorkflowNotifyType, config WorkflowNotifyConfig) *WorkflowNotification {
@@ -77,30 +86,31 @@ func NewWorkflowNotification(w *model.Workflow, notifyType WorkflowNotifyType, c
 		notifyType: notifyType,
 		workflow:   w,
 		config:     config,
+		lang:       locale.DefaultLang(),
 	}
 }
 
-func GetWorkflowStepTypeDesc(s string) string {
+func GetWorkflowStepTypeDesc(lang, s string) string {
 	switch s {
 	case model.WorkflowStepTypeSQLExecute:
-		return "上线"
+		return message(lang, "workflow.step_execute", "上线")
 	default:
-		return "审批"
+		return message(lang, "workflow.step_approve", "审批")
 	}
 }
 
 func (w *WorkflowNotification) NotificationSubject() string {
 	switch w.notifyType {
 	case WorkflowNotifyTypeApprove, WorkflowNotifyTypeCreate:
-		return fmt.Sprintf("SQL工单待%s", GetWorkflowStepTypeDesc(w.workflow.CurrentStep().Template.Typ))
+		return message(w.lang, "workflow.subject_wait", "SQL工单待%s", GetWorkflowStepTypeDesc(w.lang, w.workflow.CurrentStep().Template.Typ))
 	case WorkflowNotifyTypeReject:
-		return "SQL工单已被驳回"
+		return message(w.lang, "workflow.subject_rejected", "SQL工单已被驳回")
 	case WorkflowNotifyTypeExecuteSuccess:
-		return "SQL工单上线成功"
+		return message(w.lang, "workflow.subject_exec_success", "SQL工单上线成功")
 	case WorkflowNotifyTypeExecuteFail:
-		return "SQL工单上线失败"
+		return message(w.lang, "workflow.subject_exec_failed", "SQL工单上线失败")
 	default:
-		return "SQL工单未知请求"
+		return message(w.lang, "workflow.subject_unknown", "SQL工单未知请求")
 	}
 }
 
@@ -109,7 +119,7 @@ func (w *WorkflowNotification) NotificationBody() string {
 	taskIds := w.workflow.GetTaskIds()
 	tasks, _, err := s.GetTasksByIds(taskIds)
 	if err != nil || len(tasks) <= 0 {
-		return fmt.Sprintf(`
+		return message(w.lang, "workflow.body_without_tasks", `
 - 工单主题: %v
 - 工单ID: %v
 - 工单描述: %v
@@ -125,7 +135,7 @@ func (w *WorkflowNotification) NotificationBody() string {
 	}
 
 	buf := bytes.Buffer{}
-	head := fmt.Sprintf(`
+	head := message(w.lang, "workflow.body_head", `
 - 工单主题: %v
 - 工单ID: %v
 - 工单描述: %v
@@ -139,13 +149,13 @@ func (w *WorkflowNotification) NotificationBody() string {
 	buf.WriteString(head)
 
 	if w.config.SQLEUrl != nil {
-		buf.WriteString(fmt.Sprintf("\n- 工单链接: %v/project/%v/exec-workflow/%v",
+		buf.WriteString(message(w.lang, "workflow.body_link", "\n- 工单链接: %v/project/%v/exec-workflow/%v",
 			strings.TrimRight(*w.config.SQLEUrl, "/"),
 			w.workflow.ProjectId,
 			w.workflow.WorkflowId,
 		))
 	} else {
-		buf.WriteString("\n- 工单链接: 请在系统设置-全局配置中补充全局url")
+		buf.WriteString(message(w.lang, "workflow.body_link_missing", "\n- 工单链接: 请在系统设置-全局配置中补充全局url"))
 	}
 
 	instanceIds := make([]uint64, 0, len(tasks))
@@ -155,7 +165,7 @@ func (w *WorkflowNotification) NotificationBody() string {
 
 	instances, err := dms.GetInstancesInProjectByIds(context.Background(), string(w.workflow.ProjectId), instanceIds)
 	if err != nil {
-		buf.WriteString(fmt.Sprintf("\n 获取数据源实例失败: %v\n", err))
+		buf.WriteString(message(w.lang, "workflow.body_get_instances_failed", "\n 获取数据源实例失败: %v\n", err))
 		return buf.String()
 	}
 
@@ -186,7 +196,7 @@ func (w *WorkflowNotification) buildNotifyBody(task *model.Task) string {
 
 	switch w.notifyType {
 	case WorkflowNotifyTypeExecuteSuccess, WorkflowNotifyTypeExecuteFail:
-		return fmt.Sprintf(`
+		return message(w.lang, "workflow.body_task_executed", `
 - 数据源: %v
 - schema: %v
 - 上线开始时间: %v
@@ -205,7 +215,7 @@ func (w *WorkflowNotification) buildNotifyBody(task *model.Task) string {
 				break
 			}
 		}
-		return fmt.Sprintf(`
+		return message(w.lang, "workflow.body_task_rejected", `
 - 数据源: %v
 - schema: %v
 - 驳回原因: %v
@@ -215,7 +225,7 @@ func (w *WorkflowNotification) buildNotifyBody(task *model.Task) string {
 			reason,
 		)
 	default:
-		return fmt.Sprintf(`
+		return message(w.lang, "workflow.body_task_audited", `
 - 数据源: %v
 - schema: %v
 - 工单审核得分: %v
@@ -253,6 +263,21 @@ func (w *WorkflowNotification) notifyUser() []string {
 	}
 }
 
+func getWorkflowEventType(wt WorkflowNotifyType) string {
+	switch wt {
+	case WorkflowNotifyTypeCreate:
+		return eventbus.EventWorkflowCreated
+	case WorkflowNotifyTypeApprove:
+		return eventbus.EventWorkflowApproved
+	case WorkflowNotifyTypeReject:
+		return eventbus.EventWorkflowRejected
+	case WorkflowNotifyTypeExecuteSuccess:
+		return eventbus.EventWorkflowExecuted
+	default:
+		return eventbus.EventWorkflowExecuteFailed
+	}
+}
+
 func notifyWorkflowWebhook(workflow *model.Workflow, wt WorkflowNotifyType) {
 	// dms-todo 使用projectid代替name
 	err := workflowSendRequest(getWorkflowNotifyTypeAction(wt), workflow)
@@ -288,6 +313,7 @@ func NotifyWorkflow(projectId, workflowId string, wt WorkflowNotifyType) {
 	}
 
 	go func() { notifyWorkflowWebhook(workflow, wt) }()
+	eventbus.Publish(getWorkflowEventType(wt), string(workflow.ProjectId), eventbus.NewWorkflowEventData(workflow))
 
 	sqleUrl, err := s.GetSqleUrl()
 	if err != nil {
@@ -300,7 +326,9 @@ func NotifyWorkflow(projectId, workflowId string, wt WorkflowNotifyType) {
 type AuditPlanNotification struct {
 	auditPlan *model.AuditPlan
 	report    *model.AuditPlanReportV2
+	drifts    []*model.SchemaDrift
 	config    AuditPlanNotifyConfig
+	lang      string
 }
 
 type AuditPlanNotifyConfig struct {
@@ -308,21 +336,23 @@ type AuditPlanNotifyConfig struct {
 	ProjectName *string
 }
 
-func NewAuditPlanNotification(auditPlan *model.AuditPlan, report *model.AuditPlanReportV2, config AuditPlanNotifyConfig) *AuditPlanNotification {
+func NewAuditPlanNotification(auditPlan *model.AuditPlan, report *model.AuditPlanReportV2, drifts []*model.SchemaDrift, config AuditPlanNotifyConfig) *AuditPlanNotification {
 	return &AuditPlanNotification{
 		auditPlan: auditPlan,
 		report:    report,
+		drifts:    drifts,
 		config:    config,
+		lang:      locale.DefaultLang(),
 	}
 }
 
 func (a *AuditPlanNotification) NotificationSubject() string {
-	return fmt.Sprintf("SQLE扫描任务[%v]扫描结果[%v]", a.auditPlan.Name, a.report.AuditLevel)
+	return message(a.lang, "audit_plan.subject", "SQLE扫描任务[%v]扫描结果[%v]", a.auditPlan.Name, a.report.AuditLevel)
 }
 
 func (a *AuditPlanNotification) NotificationBody() string {
 	builder := strings.Builder{}
-	builder.WriteString(fmt.Sprintf(`
+	builder.WriteString(message(a.lang, "audit_plan.body", `
 - 扫描任务: %v
 - 审核时间: %v
 - 审核类型: %v
@@ -342,7 +372,7 @@ func (a *AuditPlanNotification) NotificationBody() string {
 	))
 
 	if a.config.SQLEUrl != nil && a.auditPlan.ProjectId != "" {
-		builder.WriteString(fmt.Sprintf("\n- 扫描任务链接: %v/project/%v/auditPlan/detail/%v/report/%v",
+		builder.WriteString(message(a.lang, "audit_plan.body_link", "\n- 扫描任务链接: %v/project/%v/auditPlan/detail/%v/report/%v",
 			strings.TrimRight(*a.config.SQLEUrl, "/"),
 			a.auditPlan.ProjectId,
 			a.auditPlan.Name,
@@ -350,9 +380,55 @@ func (a *AuditPlanNotification) NotificationBody() string {
 		))
 	}
 
+	if len(a.drifts) > 0 {
+		builder.WriteString(message(a.lang, "audit_plan.body_schema_drifts", "\n- 表结构变更: %v处，其中未经工单变更%v处", len(a.drifts), countOutOfBandDrifts(a.drifts)))
+		for i, drift := range a.drifts {
+			if i == maxSchemaDriftsInNotification {
+				builder.WriteString("\n  - ...")
+				break
+			}
+			builder.WriteString("\n  - " + schemaDriftDesc(a.lang, drift))
+		}
+	}
+
 	return builder.String()
 }
 
+const maxSchemaDriftsInNotification = 20
+
+var schemaDriftChangeTypeDesc = map[string]string{
+	model.SchemaDriftChangeTypeCreated:  "新增",
+	model.SchemaDriftChangeTypeDropped:  "删除",
+	model.SchemaDriftChangeTypeModified: "修改",
+}
+
+var schemaDriftObjectTypeDesc = map[string]string{
+	model.SchemaDriftObjectTypeTable:  "表",
+	model.SchemaDriftObjectTypeColumn: "列",
+	model.SchemaDriftObjectTypeIndex:  "索引",
+}
+
+func schemaDriftDesc(lang string, drift *model.SchemaDrift) string {
+	desc := message(lang, "schema_drift.desc", "表%v %v%v %v", drift.TableName,
+		message(lang, "schema_drift.change_type."+drift.ChangeType, schemaDriftChangeTypeDesc[drift.ChangeType]),
+		message(lang, "schema_drift.object_type."+drift.ObjectType, schemaDriftObjectTypeDesc[drift.ObjectType]),
+		drift.ObjectName)
+	if drift.OutOfBand {
+		return message(lang, "schema_drift.out_of_band", "[未经工单] %v", desc)
+	}
+	return message(lang, "schema_drift.in_task", "%v (任务%v)", desc, drift.TaskId)
+}
+
+func countOutOfBandDrifts(drifts []*model.SchemaDrift) int {
+	count := 0
+	for _, drift := range drifts {
+		if drift.OutOfBand {
+			count++
+		}
+	}
+	return count
+}
+
 type TestNotify struct {
 }
 
@@ -392,6 +468,7 @@ func NotifyAuditPlan(auditPlanId uint, report *model.AuditPlanReportV2) error {
 	if err != nil {
 		return err
 	}
+	eventbus.Publish(eventbus.EventAuditPlanReportGenerated, string(ap.ProjectId), eventbus.NewAuditPlanReportEventData(ap, report))
 	// ap.CreateUser, _, err = s.GetUserByID(ap.CreateUserID)
 	// if err != nil {
 	// 	return err
@@ -401,8 +478,14 @@ func NotifyAuditPlan(auditPlanId uint, report *model.AuditPlanReportV2) error {
 		return err
 	}
 
-	if driverV2.RuleLevelLessOrEqual(ap.NotifyLevel, report.AuditLevel) {
-		n := NewAuditPlanNotification(ap, report, config)
+	drifts, err := s.GetSchemaDriftsByReport(report.ID)
+	if err != nil {
+		return err
+	}
+
+	// the schema changed out of workflow is always notified regardless of the audit level
+	if driverV2.RuleLevelLessOrEqual(ap.NotifyLevel, report.AuditLevel) || countOutOfBandDrifts(drifts) > 0 {
+		n := NewAuditPlanNotification(ap, report, drifts, config)
 		return GetAuditPlanNotifier().Notify(n, ap)
 	}
 
@@ -165,9 +165,22 @@ type AuditPlanPayload struct {
 	PassRate         float64 `json:"pass_rate"`         // 审核通过率
 	AuditLevel       string  `json:"audit_level"`       // 审核结果等级
 
+	SchemaDrifts []*SchemaDriftPayload `json:"schema_drifts,omitempty"` // 表结构变更
+
 	SQLEUrl string `json:"sqle_url"` // sqle地址
 }
 
+type SchemaDriftPayload struct {
+	TableName  string `json:"table_name"`
+	ObjectType string `json:"object_type"` // table, column, index
+	ObjectName string `json:"object_name"`
+	ChangeType string `json:"change_type"` // created, dropped, modified
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	OutOfBand  bool   `json:"out_of_band"` // 未经工单变更
+	TaskId     uint   `json:"task_id"`     // 变更表结构的工单任务
+}
+
 func auditPlanSendRequest(auditPlan *model.AuditPlan, report *model.AuditPlanReportV2, config AuditPlanNotifyConfig) (err error) {
 	var s string
 	if config.SQLEUrl != nil {
@@ -179,6 +192,24 @@ func auditPlanSendRequest(auditPlan *model.AuditPlan, report *model.AuditPlanRep
 		return err
 	}
 
+	drifts, err := model.GetStorage().GetSchemaDriftsByReport(report.ID)
+	if err != nil {
+		return err
+	}
+	var driftPayloads []*SchemaDriftPayload
+	for _, drift := range drifts {
+		driftPayloads = append(driftPayloads, &SchemaDriftPayload{
+			TableName:  drift.TableName,
+			ObjectType: drift.ObjectType,
+			ObjectName: drift.ObjectName,
+			ChangeType: drift.ChangeType,
+			Before:     drift.Before,
+			After:      drift.After,
+			OutOfBand:  drift.OutOfBand,
+			TaskId:     drift.TaskId,
+		})
+	}
+
 	reqBody := &webHookAuditPlanRequestBody{
 		Event:     "auditplan",
 		Action:    ManuallyAudit,
@@ -196,6 +227,7 @@ func auditPlanSendRequest(auditPlan *model.AuditPlan, report *model.AuditPlanRep
 				Score:            report.Score,
 				PassRate:         report.PassRate,
 				AuditLevel:       report.AuditLevel,
+				SchemaDrifts:     driftPayloads,
 				SQLEUrl:          s,
 			},
 		},
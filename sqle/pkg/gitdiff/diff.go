@@ -0,0 +1,226 @@
+package gitdiff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	goGit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// LineRange is the range of lines, both Start and End are included and start from 1.
+type LineRange struct {
+	Start uint64
+	End   uint64
+}
+
+// ChangedFile is the file added or modified in the head revision.
+type ChangedFile struct {
+	Path string
+	// BasePath is the path in the base revision, it is empty if the file is added.
+	BasePath string
+	// Lines are the lines added or modified in the head revision, the lines
+	// around the deleted lines are included.
+	Lines []LineRange
+}
+
+// Added returns true if the file does not exist in the base revision.
+func (f *ChangedFile) Added() bool {
+	return f.BasePath == ""
+}
+
+// Overlaps returns true if any line between start and end is changed.
+func (f *ChangedFile) Overlaps(start, end uint64) bool {
+	if f.Added() {
+		return true
+	}
+	for _, r := range f.Lines {
+		if r.Start <= end && start <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff is the changes from the merge base of base revision and head revision
+// to the head revision, as the changes shown in a merge request.
+type Diff struct {
+	BaseCommit string
+	HeadCommit string
+	files      map[string]*ChangedFile
+	baseTree   *object.Tree
+}
+
+// File returns the changed file of the path in the head revision, ok is false
+// if the file is not changed.
+func (d *Diff) File(path string) (f *ChangedFile, ok bool) {
+	f, ok = d.files[strings.TrimPrefix(path, "/")]
+	return f, ok
+}
+
+// Files returns all changed files.
+func (d *Diff) Files() []*ChangedFile {
+	files := make([]*ChangedFile, 0, len(d.files))
+	for _, f := range d.files {
+		files = append(files, f)
+	}
+	return files
+}
+
+// BaseContent returns the content of changed file in the base revision, exist
+// is false if the file is added.
+func (d *Diff) BaseContent(f *ChangedFile) (content string, exist bool, err error) {
+	if f.Added() {
+		return "", false, nil
+	}
+	file, err := d.baseTree.File(f.BasePath)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	content, err = file.Contents()
+	if err != nil {
+		return "", false, err
+	}
+	return content, true, nil
+}
+
+// ResolveCommit returns the commit of revision, the revision can be a commit
+// hash, tag, local branch or branch of remote origin.
+func ResolveCommit(repo *goGit.Repository, revision string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		var originErr error
+		hash, originErr = repo.ResolveRevision(plumbing.Revision("origin/" + revision))
+		if originErr != nil {
+			return nil, fmt.Errorf("resolve revision %s failed: %v", revision, err)
+		}
+	}
+	return repo.CommitObject(*hash)
+}
+
+// Compute returns the diff between base revision and head revision.
+func Compute(ctx context.Context, repo *goGit.Repository, base, head string) (*Diff, error) {
+	baseCommit, err := ResolveCommit(repo, base)
+	if err != nil {
+		return nil, err
+	}
+	headCommit, err := ResolveCommit(repo, head)
+	if err != nil {
+		return nil, err
+	}
+	mergeBases, err := baseCommit.MergeBase(headCommit)
+	if err != nil {
+		return nil, fmt.Errorf("get merge base of %s and %s failed: %v", base, head, err)
+	}
+	if len(mergeBases) > 0 {
+		baseCommit = mergeBases[0]
+	}
+	baseTree, err := baseCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	patch, err := baseCommit.PatchContext(ctx, headCommit)
+	if err != nil {
+		return nil, fmt.Errorf("diff %s and %s failed: %v", base, head, err)
+	}
+
+	d := &Diff{
+		BaseCommit: baseCommit.Hash.String(),
+		HeadCommit: headCommit.Hash.String(),
+		files:      map[string]*ChangedFile{},
+		baseTree:   baseTree,
+	}
+	for _, fp := range patch.FilePatches() {
+		from, to := fp.Files()
+		// the file is deleted in the head revision.
+		if to == nil || fp.IsBinary() {
+			continue
+		}
+		f := &ChangedFile{Path: to.Path()}
+		if from != nil {
+			f.BasePath = from.Path()
+			f.Lines = changedLines(fp.Chunks())
+		}
+		d.files[f.Path] = f
+	}
+	return d, nil
+}
+
+func changedLines(chunks []fdiff.Chunk) []LineRange {
+	ranges := []LineRange{}
+	add := func(start, end uint64) {
+		if n := len(ranges); n > 0 && ranges[n-1].End+1 >= start {
+			if end > ranges[n-1].End {
+				ranges[n-1].End = end
+			}
+			return
+		}
+		ranges = append(ranges, LineRange{Start: start, End: end})
+	}
+
+	line := uint64(1)
+	for i, chunk := range chunks {
+		count := lineCount(chunk.Content())
+		switch chunk.Type() {
+		case fdiff.Equal:
+			line += count
+		case fdiff.Add:
+			add(line, line+count-1)
+			line += count
+		case fdiff.Delete:
+			// the deleted lines are replaced by the added lines.
+			if i+1 < len(chunks) && chunks[i+1].Type() == fdiff.Add {
+				continue
+			}
+			// the statement is changed if lines in it are deleted, so the lines
+			// around the deleted lines are regarded as changed.
+			start := line
+			if start > 1 {
+				start--
+			}
+			add(start, line)
+		}
+	}
+	return ranges
+}
+
+func lineCount(content string) uint64 {
+	count := uint64(strings.Count(content, "\n"))
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		count++
+	}
+	return count
+}
+
+// RepositoryURL removes the user info from repository URL so that it can be saved.
+func RepositoryURL(repositoryURL string) string {
+	u, err := url.Parse(repositoryURL)
+	if err != nil {
+		return repositoryURL
+	}
+	u.User = nil
+	return u.String()
+}
+
+// FileURL returns the web URL of the line of file at the commit, it follows the
+// URL pattern "<repository>/blob/<commit>/<path>#L<line>" which is supported
+// by GitHub, GitLab and Gitea.
+func FileURL(repositoryURL, commit, path string, line uint64) string {
+	if repositoryURL == "" || commit == "" || path == "" {
+		return ""
+	}
+	repositoryURL = strings.TrimSuffix(strings.TrimSuffix(RepositoryURL(repositoryURL), "/"), ".git")
+	fileURL := fmt.Sprintf("%s/blob/%s/%s", repositoryURL, commit, strings.TrimPrefix(path, "/"))
+	if line > 0 {
+		fileURL = fmt.Sprintf("%s#L%d", fileURL, line)
+	}
+	return fileURL
+}
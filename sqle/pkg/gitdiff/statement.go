@@ -0,0 +1,168 @@
+package gitdiff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	xmlParser "github.com/actiontech/mybatis-mapper-2-sql"
+	xmlAst "github.com/actiontech/mybatis-mapper-2-sql/ast"
+)
+
+// SQLChanged returns true if the SQL which starts from startLine in the file is
+// changed, the end line is computed by the lines of SQL text.
+func (d *Diff) SQLChanged(path string, startLine uint64, sql string) bool {
+	f, ok := d.File(path)
+	if !ok {
+		return false
+	}
+	return f.Overlaps(startLine, startLine+uint64(strings.Count(strings.TrimSpace(sql), "\n")))
+}
+
+// ChangedMyBatisStmts returns the statements of MyBatis XML files in the head
+// revision which are added or changed. The statement is identified by its id,
+// it is changed if its SQL is changed, including the change from the SQL
+// fragments it includes. headXMLs should be all XML files in the head revision
+// so that the fragments across namespace can be resolved.
+func (d *Diff) ChangedMyBatisStmts(headXMLs []xmlParser.XmlFile) ([]xmlAst.StmtInfo, error) {
+	baseXMLs := make([]xmlParser.XmlFile, 0, len(headXMLs))
+	changed := false
+	for _, x := range headXMLs {
+		f, ok := d.File(x.FilePath)
+		if !ok {
+			baseXMLs = append(baseXMLs, x)
+			continue
+		}
+		changed = true
+		content, exist, err := d.BaseContent(f)
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			baseXMLs = append(baseXMLs, xmlParser.XmlFile{FilePath: x.FilePath, Content: content})
+		}
+	}
+	// the SQL fragments are only in XML files.
+	if !changed {
+		return []xmlAst.StmtInfo{}, nil
+	}
+
+	headStmts, headKeys, err := parseMyBatisStmts(headXMLs)
+	if err != nil {
+		return nil, err
+	}
+	baseStmts, baseKeys, err := parseMyBatisStmts(baseXMLs)
+	if err != nil {
+		return nil, err
+	}
+	baseSQLs := map[string]string{}
+	for i, stmt := range baseStmts {
+		if baseKeys[i] != "" {
+			baseSQLs[baseKeys[i]] = stmt.SQL
+		}
+	}
+
+	stmts := []xmlAst.StmtInfo{}
+	for i, stmt := range headStmts {
+		key := headKeys[i]
+		if key == "" {
+			// the statement without id is checked by lines.
+			if d.SQLChanged(stmt.FilePath, stmt.StartLine, "") {
+				stmts = append(stmts, stmt)
+			}
+			continue
+		}
+		if baseSQL, ok := baseSQLs[key]; !ok || baseSQL != stmt.SQL {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts, nil
+}
+
+// parseMyBatisStmts parses the statements of XML files, keys are the
+// identities of statements which are "file path:namespace.id".
+func parseMyBatisStmts(xmls []xmlParser.XmlFile) (stmts []xmlAst.StmtInfo, keys []string, err error) {
+	stmts, err = xmlParser.ParseXMLs(xmls, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := map[string]map[uint64]string{}
+	for _, x := range xmls {
+		fileIds, err := mybatisStmtIds(x.Content)
+		if err != nil {
+			// the file is skipped by parser too.
+			continue
+		}
+		ids[x.FilePath] = fileIds
+	}
+	keys = make([]string, len(stmts))
+	for i, stmt := range stmts {
+		if id, ok := ids[stmt.FilePath][stmt.StartLine]; ok && id != "" {
+			keys[i] = fmt.Sprintf("%s:%s", stmt.FilePath, id)
+		}
+	}
+	return stmts, keys, nil
+}
+
+// mybatisStmtIds returns the id of statements in XML by the start line, the
+// start line is got as the MyBatis parser does.
+func mybatisStmtIds(content string) (map[uint64]string, error) {
+	ids := map[uint64]string{}
+	namespace := ""
+	d := xml.NewDecoder(strings.NewReader(content))
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		st, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch st.Name.Local {
+		case "mapper", "sqlMap":
+			namespace = xmlAttr(st, "namespace")
+		case "select", "update", "delete", "insert", "statement":
+			line, _ := d.InputPos()
+			if id := xmlAttr(st, "id"); id != "" {
+				ids[uint64(line)] = fmt.Sprintf("%s.%s", namespace, id)
+			}
+		}
+	}
+}
+
+func xmlAttr(st xml.StartElement, name string) string {
+	for _, attr := range st.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// ChangedSQLs returns the SQLs in head which are not in base, it is used for the
+// SQLs extracted from the file without line, e.g. the SQL literals of Java file.
+func ChangedSQLs(headSQLs, baseSQLs []string) []string {
+	count := map[string]int{}
+	for _, sql := range baseSQLs {
+		count[normalize(sql)]++
+	}
+	sqls := []string{}
+	for _, sql := range headSQLs {
+		key := normalize(sql)
+		if count[key] > 0 {
+			count[key]--
+			continue
+		}
+		sqls = append(sqls, sql)
+	}
+	return sqls
+}
+
+func normalize(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
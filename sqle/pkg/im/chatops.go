@@ -0,0 +1,328 @@
+package im
+
+import (
+	"context"
+	"encoding/json"
+	e "errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/locale"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops"
+	"github.com/actiontech/sqle/sqle/pkg/im/slack"
+	"github.com/actiontech/sqle/sqle/pkg/im/teams"
+	"github.com/actiontech/sqle/sqle/server"
+)
+
+var ErrChatOpsNotEnabled = e.New("the im is not enabled")
+
+// message returns the text of chat-ops message in the language,
+// the messages are translated in "locale/messages/im.<language>.yaml".
+func message(lang, id, fallback string, args ...interface{}) string {
+	return locale.Sprintf(lang, "im.chatops."+id, fallback, args...)
+}
+
+// userLang returns the language chosen by user, the default language is used if the user does not choose one.
+func userLang(userId string) string {
+	preference, exist, err := model.GetStorage().GetUserPreference(userId)
+	if err == nil && exist {
+		if lang := locale.Normalize(preference.Language); lang != "" {
+			return lang
+		}
+	}
+	return locale.DefaultLang()
+}
+
+func IsChatOpsIm(imType string) bool {
+	return imType == model.ImTypeSlack || imType == model.ImTypeTeams
+}
+
+func NewChatOpsAdapter(im model.IM) (chatops.Adapter, error) {
+	switch im.Type {
+	case model.ImTypeSlack:
+		return slack.NewClient(im.AppSecret, im.SigningSecret, im.BaseUrl), nil
+	case model.ImTypeTeams:
+		// the stand-in or proxy of Teams serves the login, graph, connector and
+		// openid metadata api at the same address.
+		cfg := teams.Config{
+			AppId:       im.AppKey,
+			AppPassword: im.AppSecret,
+			TenantId:    im.TenantId,
+			ServiceUrl:  im.BaseUrl,
+			LoginUrl:    im.BaseUrl,
+			GraphUrl:    im.BaseUrl,
+		}
+		if im.BaseUrl != "" {
+			cfg.OpenIdMetadataUrl = strings.TrimSuffix(im.BaseUrl, "/") + teams.OpenIdMetadataPath
+		}
+		return teams.NewClient(cfg), nil
+	default:
+		return nil, fmt.Errorf("im type %s does not support chat-ops", im.Type)
+	}
+}
+
+func buildApprovalMessage(lang string, workflow *model.Workflow, url string) *chatops.ApprovalMessage {
+	msg := &chatops.ApprovalMessage{
+		Title: message(lang, "title", "SQL工单待审批：%v", workflow.Subject),
+		Fields: []chatops.Field{
+			{Name: message(lang, "workflow_id", "工单ID"), Value: workflow.WorkflowId},
+			{Name: message(lang, "applicant", "申请人"), Value: dms.GetUserNameWithDelTag(workflow.CreateUserId)},
+		},
+		Url:         url,
+		UrlText:     message(lang, "view_workflow", "查看工单详情"),
+		ApproveText: message(lang, "approve", "审批通过"),
+		RejectText:  message(lang, "reject", "驳回"),
+		ReasonText:  message(lang, "reason", "驳回原因"),
+	}
+	if workflow.Desc != "" {
+		msg.Fields = append(msg.Fields, chatops.Field{Name: message(lang, "desc", "工单描述"), Value: workflow.Desc})
+	}
+
+	taskIds := make([]uint, 0, len(workflow.Record.InstanceRecords))
+	instanceIds := make([]uint64, 0, len(workflow.Record.InstanceRecords))
+	for _, record := range workflow.Record.InstanceRecords {
+		taskIds = append(taskIds, record.TaskId)
+		instanceIds = append(instanceIds, record.InstanceId)
+	}
+	tasks, _, err := model.GetStorage().GetTasksByIds(taskIds)
+	if err != nil {
+		log.NewEntry().Errorf("get tasks of workflow %s failed: %v", workflow.WorkflowId, err)
+		return msg
+	}
+	instanceNames := map[uint64]string{}
+	if instances, err := dms.GetInstancesByIds(context.TODO(), instanceIds); err == nil {
+		for _, inst := range instances {
+			instanceNames[inst.ID] = inst.Name
+		}
+	}
+	for _, task := range tasks {
+		name := instanceNames[task.InstanceId]
+		if task.Schema != "" {
+			name = fmt.Sprintf("%s.%s", name, task.Schema)
+		}
+		msg.Fields = append(msg.Fields, chatops.Field{
+			Name: message(lang, "task", "数据源 %v", name),
+			Value: message(lang, "task_audit_result", "审核得分 %v，通过率 %v%%，审核结果等级 %v",
+				task.Score, task.PassRate*100, task.AuditLevel),
+		})
+	}
+	return msg
+}
+
+// CreateChatOpsApproval sends the approval message to every assignee of the current workflow step.
+func CreateChatOpsApproval(ctx context.Context, im model.IM, workflow *model.Workflow, assignUsers []*model.User, url string) error {
+	adapter, err := NewChatOpsAdapter(im)
+	if err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	newLog := log.NewEntry()
+	for _, user := range assignUsers {
+		if user.Email == "" {
+			newLog.Errorf("the email of user %s is empty, skip sending %s approval message", user.Name, im.Type)
+			continue
+		}
+		msg := buildApprovalMessage(userLang(user.GetIDStr()), workflow, url)
+		ref, err := adapter.SendApproval(ctx, chatops.Recipient{Email: user.Email}, msg)
+		if err != nil {
+			newLog.Errorf("send %s approval message to user %s failed: %v", im.Type, user.Name, err)
+			continue
+		}
+		content, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		err = s.Save(&model.ChatOpsMessage{
+			ImType:         im.Type,
+			ProjectId:      string(workflow.ProjectId),
+			WorkflowId:     workflow.WorkflowId,
+			WorkflowStepId: workflow.CurrentStep().ID,
+			UserId:         user.GetIDStr(),
+			ImUserId:       ref.UserId,
+			ChannelId:      ref.ChannelId,
+			MessageId:      ref.MessageId,
+			Content:        content,
+			Status:         model.ApproveStatusInitialized,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func approvalResult(lang string, user *model.User, status, reason string) string {
+	switch status {
+	case model.ApproveStatusAgree:
+		return message(lang, "result_approved", "已由 %v 审批通过", user.Name)
+	case model.ApproveStatusRefuse:
+		if reason == "" {
+			return message(lang, "result_rejected", "已由 %v 驳回", user.Name)
+		}
+		return message(lang, "result_rejected_with_reason", "已由 %v 驳回，驳回原因：%v", user.Name, reason)
+	default:
+		return message(lang, "result_canceled", "工单已由 %v 关闭", user.Name)
+	}
+}
+
+// UpdateChatOpsApprovalStatus updates the approval messages of workflows which are waiting for action,
+// the buttons in the messages are replaced by the result.
+func UpdateChatOpsApprovalStatus(ctx context.Context, im model.IM, workflowIds []string, user *model.User, status, reason string) error {
+	adapter, err := NewChatOpsAdapter(im)
+	if err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	records, err := s.GetInitializedChatOpsMessagesByWorkflowIds(im.Type, workflowIds)
+	if err != nil {
+		return err
+	}
+
+	// the workflow has moved to the next step when it is approved, the messages of the
+	// next step may be sent concurrently and should be kept.
+	currentStepIds := map[string]uint{}
+	if status == model.ApproveStatusAgree {
+		for _, workflowId := range workflowIds {
+			workflow, exist, err := s.GetWorkflowDetailWithoutInstancesByWorkflowID("", workflowId)
+			if err != nil {
+				return err
+			}
+			if exist {
+				currentStepIds[workflowId] = workflow.Record.CurrentWorkflowStepId
+			}
+		}
+	}
+
+	ids := make([]uint, 0, len(records))
+	for _, record := range records {
+		if stepId, ok := currentStepIds[record.WorkflowId]; ok && stepId == record.WorkflowStepId {
+			continue
+		}
+		ids = append(ids, record.ID)
+		msg := &chatops.ApprovalMessage{}
+		if err := json.Unmarshal(record.Content, msg); err != nil {
+			log.NewEntry().Errorf("unmarshal %s approval message %d failed: %v", im.Type, record.ID, err)
+			continue
+		}
+		msg.Result = approvalResult(userLang(record.UserId), user, status, reason)
+		ref := &chatops.MessageRef{UserId: record.ImUserId, ChannelId: record.ChannelId, MessageId: record.MessageId}
+		if err := adapter.UpdateApproval(ctx, ref, msg); err != nil {
+			log.NewEntry().Errorf("update %s approval message %d failed: %v", im.Type, record.ID, err)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.UpdateChatOpsMessageStatusByIds(ids, status)
+}
+
+// HandleChatOpsCallback verifies the callback request from IM platform, the action is taken asynchronously
+// because the platform expects the response in a few seconds, the user is replied in IM if the action fails.
+func HandleChatOpsCallback(imType string, r *http.Request) error {
+	im, exist, err := model.GetStorage().GetImConfigByType(imType)
+	if err != nil {
+		return err
+	}
+	if !exist || !im.IsEnable || !IsChatOpsIm(imType) {
+		return ErrChatOpsNotEnabled
+	}
+	adapter, err := NewChatOpsAdapter(*im)
+	if err != nil {
+		return err
+	}
+	cb, err := adapter.ParseCallback(r)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if reply := processChatOpsCallback(adapter, cb); reply != "" {
+			if err := adapter.Reply(context.Background(), cb, reply); err != nil {
+				log.NewEntry().Errorf("reply %s callback failed: %v", imType, err)
+			}
+		}
+	}()
+	return nil
+}
+
+// processChatOpsCallback approves or rejects the workflow as the user in message, it returns the text replied to user.
+func processChatOpsCallback(adapter chatops.Adapter, cb *chatops.Callback) string {
+	newLog := log.NewEntry()
+	s := model.GetStorage()
+	lang := locale.DefaultLang()
+
+	record, exist, err := s.GetChatOpsMessage(adapter.Type(), cb.ChannelId, cb.MessageId)
+	if err != nil {
+		newLog.Errorf("get %s approval message failed: %v", adapter.Type(), err)
+		return message(lang, "failed", "操作失败：%v", err)
+	}
+	// only the recipient of message can take the action.
+	if !exist || record.ImUserId != cb.UserId {
+		return message(lang, "message_not_found", "未找到对应的审批消息")
+	}
+	lang = userLang(record.UserId)
+	if record.Status != model.ApproveStatusInitialized {
+		return message(lang, "handled", "该审批已处理，请在SQLE中查看工单状态")
+	}
+
+	user, err := dms.GetUser(context.TODO(), record.UserId, dms.GetDMSServerAddress())
+	if err != nil {
+		return message(lang, "failed", "操作失败：%v", err)
+	}
+	workflow, err := dms.GetWorkflowDetailByWorkflowId(record.ProjectId, record.WorkflowId, s.GetWorkflowDetailWithoutInstancesByWorkflowID)
+	if err != nil {
+		return message(lang, "failed", "操作失败：%v", err)
+	}
+	if workflow.CurrentStep() == nil || workflow.CurrentStep().ID != record.WorkflowStepId {
+		return message(lang, "handled", "该审批已处理，请在SQLE中查看工单状态")
+	}
+	if err := server.CheckUserCanOperateStep(user, workflow, int(record.WorkflowStepId)); err != nil {
+		return message(lang, "failed", "操作失败：%v", err)
+	}
+
+	switch cb.Action {
+	case chatops.ActionApprove:
+		nextStep := workflow.NextStep()
+		unacknowledged, err := server.GetUnacknowledgedSuppressions(workflow)
+		if err != nil {
+			return message(lang, "failed", "操作失败：%v", err)
+		}
+		// the suppressed audit results should be reviewed in SQLE before approving.
+		if len(unacknowledged) > 0 {
+			return message(lang, "suppressions_unacknowledged", "有%v条SQL的审核结果被注释忽略，请在SQLE中确认后审批", len(unacknowledged))
+		}
+		if err := server.ApproveWorkflowProcess(workflow, user, s, nil); err != nil {
+			return message(lang, "failed", "操作失败：%v", err)
+		}
+		UpdateApprove(workflow.WorkflowId, user, model.ApproveStatusAgree, "")
+		if nextStep != nil {
+			CreateApprove(record.ProjectId, workflow.WorkflowId)
+		}
+	case chatops.ActionReject:
+		for _, inst := range workflow.Record.InstanceRecords {
+			if inst.IsSQLExecuted || inst.ScheduledAt != nil {
+				return message(lang, "reject_executed", "工单中有已上线或定时上线的数据源，无法驳回")
+			}
+		}
+		if err := server.RejectWorkflowProcess(workflow, cb.Reason, user, s); err != nil {
+			return message(lang, "failed", "操作失败：%v", err)
+		}
+		UpdateApprove(workflow.WorkflowId, user, model.ApproveStatusRefuse, cb.Reason)
+	default:
+		return message(lang, "unknown_action", "不支持的操作：%v", cb.Action)
+	}
+	return ""
+}
+
+// SendChatOpsTestMessage sends a message to the user with the email to check the configuration of im.
+func SendChatOpsTestMessage(ctx context.Context, im model.IM, email, lang string) error {
+	adapter, err := NewChatOpsAdapter(im)
+	if err != nil {
+		return err
+	}
+	return adapter.SendText(ctx, chatops.Recipient{Email: email}, message(lang, "test", "这是一条测试消息，SQLE已成功连接到%v", im.Type))
+}
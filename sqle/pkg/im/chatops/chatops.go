@@ -0,0 +1,77 @@
+// Package chatops defines the adapter of IM platforms which support interactive
+// approval messages, the assignees of workflow step can approve or reject the
+// workflow by the buttons in the message.
+package chatops
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	ActionApprove = "approve"
+	ActionReject  = "reject"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature of callback request")
+
+// Recipient is the user who receives the approval message, the IM user is found by email.
+type Recipient struct {
+	Email string
+}
+
+// MessageRef locates the message sent to IM platform, it is used to update the message
+// and to match the callback of the message.
+type MessageRef struct {
+	UserId    string
+	ChannelId string
+	MessageId string
+}
+
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ApprovalMessage is the content of approval message, all texts are rendered before sending.
+type ApprovalMessage struct {
+	Title       string  `json:"title"`
+	Fields      []Field `json:"fields"`
+	Url         string  `json:"url"`
+	UrlText     string  `json:"url_text"`
+	ApproveText string  `json:"approve_text"`
+	RejectText  string  `json:"reject_text"`
+	ReasonText  string  `json:"reason_text"`
+	// Result is the final state of the approval, the buttons are removed from message if it is not empty.
+	Result string `json:"result,omitempty"`
+}
+
+func (m *ApprovalMessage) Closed() bool {
+	return m.Result != ""
+}
+
+// Callback is the action that user takes on the approval message.
+type Callback struct {
+	Action    string
+	Reason    string
+	UserId    string
+	ChannelId string
+	MessageId string
+	// ResponseUrl is used to reply the user if the platform provides it.
+	ResponseUrl string
+}
+
+type Adapter interface {
+	Type() string
+	// SendText sends a plain message to the recipient, it is used to check the configuration.
+	SendText(ctx context.Context, recipient Recipient, text string) error
+	SendApproval(ctx context.Context, recipient Recipient, msg *ApprovalMessage) (*MessageRef, error)
+	// UpdateApproval replaces the content of message sent by SendApproval.
+	UpdateApproval(ctx context.Context, ref *MessageRef, msg *ApprovalMessage) error
+	// ParseCallback verifies the signature of request and parses the action in it,
+	// ErrInvalidSignature is returned if the verification fails.
+	ParseCallback(r *http.Request) (*Callback, error)
+	// Reply tells the user who takes the action the result of callback.
+	Reply(ctx context.Context, cb *Callback, text string) error
+}
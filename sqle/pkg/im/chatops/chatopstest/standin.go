@@ -0,0 +1,241 @@
+// Package chatopstest provides a local HTTP stand-in of the Slack Web API and
+// the Microsoft Teams connector, the adapters in package chatops can send
+// messages to it in tests by setting the base url to StandIn.URL. The stand-in
+// signs the Teams callback with its own key as Bot Framework, and serves the
+// OpenID metadata and signing keys at the same paths as Bot Framework.
+package chatopstest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	SlackResponsePath = "/slack/response"
+
+	teamsOpenIdMetadataPath = "/v1/.well-known/openidconfiguration"
+	teamsKeysPath           = "/v1/.well-known/keys"
+	teamsKeyId              = "stand-in-key"
+	teamsIssuer             = "https://api.botframework.com"
+	TeamsChannelId          = "msteams"
+)
+
+// Message is the message received by stand-in, Body is the request body of last sending or updating.
+type Message struct {
+	ChannelId string
+	MessageId string
+	Text      string
+	Body      map[string]interface{}
+	Updated   int
+}
+
+type StandIn struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	seq      int
+	users    map[string] /*email*/ string
+	messages []*Message
+	replies  []string
+	// teamsKey signs the token of Teams callback.
+	teamsKey *rsa.PrivateKey
+}
+
+func NewStandIn() *StandIn {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	s := &StandIn{users: map[string]string{}, teamsKey: key}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// TeamsToken returns the token which Bot Framework sends to the bot of appId in
+// the Authorization header of callback, the activity should be sent with the
+// service url and channel id "msteams".
+func (s *StandIn) TeamsToken(appId, serviceUrl string, expiredAt time.Time) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":        teamsIssuer,
+		"aud":        appId,
+		"exp":        expiredAt.Unix(),
+		"nbf":        time.Now().Add(-time.Minute).Unix(),
+		"serviceurl": serviceUrl,
+	})
+	token.Header["kid"] = teamsKeyId
+	signed, err := token.SignedString(s.teamsKey)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+// AddUser adds the IM user which can be found by email.
+func (s *StandIn) AddUser(email, userId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[email] = userId
+}
+
+func (s *StandIn) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Message{}, s.messages...)
+}
+
+func (s *StandIn) Message(channelId, messageId string) (*Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.findMessage(channelId, messageId)
+}
+
+// Replies returns the texts replied to the user who takes the action.
+func (s *StandIn) Replies() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.replies...)
+}
+
+func (s *StandIn) findMessage(channelId, messageId string) (*Message, bool) {
+	for _, m := range s.messages {
+		if m.ChannelId == channelId && m.MessageId == messageId {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+func (s *StandIn) nextId(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s%d", prefix, s.seq)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (s *StandIn) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body := map[string]interface{}{}
+	if data, _ := ioutil.ReadAll(r.Body); len(data) > 0 {
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			if err := json.Unmarshal(data, &body); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+		}
+	}
+	text, _ := body["text"].(string)
+
+	path := r.URL.Path
+	switch {
+	// Slack Web API
+	case path == "/users.lookupByEmail":
+		id, ok := s.users[r.URL.Query().Get("email")]
+		if !ok {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"ok": false, "error": "users_not_found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "user": map[string]string{"id": id}})
+	case path == "/chat.postMessage":
+		channel, _ := body["channel"].(string)
+		m := &Message{ChannelId: "D" + channel, MessageId: s.nextId("ts."), Text: text, Body: body}
+		s.messages = append(s.messages, m)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "channel": m.ChannelId, "ts": m.MessageId})
+	case path == "/chat.update":
+		channel, _ := body["channel"].(string)
+		ts, _ := body["ts"].(string)
+		m, ok := s.findMessage(channel, ts)
+		if !ok {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"ok": false, "error": "message_not_found"})
+			return
+		}
+		m.Text, m.Body = text, body
+		m.Updated++
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "channel": channel, "ts": ts})
+	case path == SlackResponsePath:
+		s.replies = append(s.replies, text)
+		w.WriteHeader(http.StatusOK)
+
+	// Microsoft login, graph and Bot Framework connector
+	case path == teamsOpenIdMetadataPath:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"issuer":                                teamsIssuer,
+			"jwks_uri":                              s.URL + teamsKeysPath,
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	case path == teamsKeysPath:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"keys": []map[string]interface{}{{
+			"kty":          "RSA",
+			"use":          "sig",
+			"kid":          teamsKeyId,
+			"n":            base64.RawURLEncoding.EncodeToString(s.teamsKey.N.Bytes()),
+			"e":            base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.teamsKey.E)).Bytes()),
+			"endorsements": []string{TeamsChannelId},
+		}}})
+	case strings.HasSuffix(path, "/oauth2/v2.0/token"):
+		writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": "stand-in-token", "expires_in": 3600})
+	case strings.HasPrefix(path, "/users/"):
+		email, _ := url.PathUnescape(strings.TrimPrefix(path, "/users/"))
+		id, ok := s.users[email]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": map[string]string{"code": "Request_ResourceNotFound"}})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"id": id})
+	case path == "/v3/conversations":
+		writeJSON(w, http.StatusCreated, map[string]string{"id": s.nextId("conversation.")})
+	case strings.HasPrefix(path, "/v3/conversations/"):
+		s.serveActivity(w, r.Method, strings.Split(strings.TrimPrefix(path, "/v3/conversations/"), "/"), text, body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// serveActivity handles "{conversation}/activities" and "{conversation}/activities/{activity}".
+func (s *StandIn) serveActivity(w http.ResponseWriter, method string, parts []string, text string, body map[string]interface{}) {
+	if len(parts) < 2 || parts[1] != "activities" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	conversationId := parts[0]
+	switch {
+	case method == http.MethodPost && len(parts) == 2:
+		if replyToId, ok := body["replyToId"].(string); ok && replyToId != "" {
+			s.replies = append(s.replies, text)
+			writeJSON(w, http.StatusOK, map[string]string{"id": s.nextId("activity.")})
+			return
+		}
+		m := &Message{ChannelId: conversationId, MessageId: s.nextId("activity."), Text: text, Body: body}
+		s.messages = append(s.messages, m)
+		writeJSON(w, http.StatusOK, map[string]string{"id": m.MessageId})
+	case method == http.MethodPut && len(parts) == 3:
+		m, ok := s.findMessage(conversationId, parts[2])
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		m.Text, m.Body = text, body
+		m.Updated++
+		writeJSON(w, http.StatusOK, map[string]string{"id": m.MessageId})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
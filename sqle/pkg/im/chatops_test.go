@@ -0,0 +1,45 @@
+package im
+
+import (
+	"context"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops"
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops/chatopstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalResult(t *testing.T) {
+	user := &model.User{Name: "admin"}
+	assert.Equal(t, "已由 admin 审批通过", approvalResult("zh", user, model.ApproveStatusAgree, ""))
+	assert.Equal(t, "已由 admin 驳回，驳回原因：no index", approvalResult("zh", user, model.ApproveStatusRefuse, "no index"))
+	assert.Equal(t, "Rejected by admin", approvalResult("en", user, model.ApproveStatusRefuse, ""))
+	assert.Equal(t, "Workflow is closed by admin", approvalResult("en", user, model.ApproveStatusCancel, ""))
+}
+
+func TestNewChatOpsAdapter(t *testing.T) {
+	standIn := chatopstest.NewStandIn()
+	defer standIn.Close()
+	standIn.AddUser("admin@example.com", "user-1")
+
+	for _, im := range []model.IM{
+		{Type: model.ImTypeSlack, AppSecret: "xoxb-token", SigningSecret: "secret", BaseUrl: standIn.URL},
+		{Type: model.ImTypeTeams, AppKey: "app", AppSecret: "password", TenantId: "tenant", BaseUrl: standIn.URL},
+	} {
+		adapter, err := NewChatOpsAdapter(im)
+		assert.NoError(t, err)
+		assert.Equal(t, im.Type, adapter.Type())
+		assert.True(t, IsChatOpsIm(im.Type))
+
+		msg := &chatops.ApprovalMessage{Title: "wf1", ApproveText: "approve", RejectText: "reject"}
+		ref, err := adapter.SendApproval(context.Background(), chatops.Recipient{Email: "admin@example.com"}, msg)
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", ref.UserId)
+	}
+	assert.Len(t, standIn.Messages(), 2)
+
+	_, err := NewChatOpsAdapter(model.IM{Type: model.ImTypeDingTalk})
+	assert.Error(t, err)
+	assert.False(t, IsChatOpsIm(model.ImTypeDingTalk))
+}
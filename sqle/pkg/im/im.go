@@ -63,10 +63,6 @@ func CreateApprove(projectId, workflowId string) {
 		newLog.Errorf("get user phone failed err: %v", err)
 		return
 	}
-	if user.Phone == "" {
-		newLog.Error("create user phone is empty")
-		return
-	}
 	if workflow.CurrentStep() == nil {
 		newLog.Infof("workflow %v has no current step, no need to create approve instance", workflow.WorkflowId)
 	}
@@ -108,6 +104,12 @@ func CreateApprove(projectId, workflowId string) {
 			workflowUrl = ""
 		}
 
+		// the approval instances of DingTalk and Feishu are created by the phone of workflow creator.
+		if (im.Type == model.ImTypeDingTalk || im.Type == model.ImTypeFeishuAudit) && user.Phone == "" {
+			newLog.Errorf("create user phone is empty, skip creating %s approve instance", im.Type)
+			continue
+		}
+
 		switch im.Type {
 		case model.ImTypeDingTalk:
 			if err := CreateDingdingAuditInst(context.TODO(), im, workflow, assignUsers, workflowUrl); err != nil {
@@ -119,6 +121,11 @@ func CreateApprove(projectId, workflowId string) {
 				newLog.Errorf("create feishu audit instance error: %v", err)
 				continue
 			}
+		case model.ImTypeSlack, model.ImTypeTeams:
+			if err := CreateChatOpsApproval(context.TODO(), im, workflow, assignUsers, workflowUrl); err != nil {
+				newLog.Errorf("create %s approval message error: %v", im.Type, err)
+				continue
+			}
 		default:
 			newLog.Errorf("im type %s not found", im.Type)
 		}
@@ -151,6 +158,11 @@ func UpdateApprove(workflowId string, user *model.User, status, reason string) {
 				newLog.Errorf("update feishu audit status error: %v", err)
 				continue
 			}
+		case model.ImTypeSlack, model.ImTypeTeams:
+			if err := UpdateChatOpsApprovalStatus(context.Background(), im, []string{workflowId}, user, status, reason); err != nil {
+				newLog.Errorf("update %s approval message error: %v", im.Type, err)
+				continue
+			}
 		}
 	}
 }
@@ -182,6 +194,12 @@ func BatchCancelApprove(workflowIds []string, user *model.User) {
 				newLog.Errorf("cancel feishu audit instance error: %v", err)
 				return
 			}
+		case model.ImTypeSlack, model.ImTypeTeams:
+			err = UpdateChatOpsApprovalStatus(context.TODO(), im, workflowIds, user, model.ApproveStatusCancel, "")
+			if err != nil {
+				newLog.Errorf("cancel %s approval message error: %v", im.Type, err)
+				return
+			}
 		default:
 			newLog.Errorf("im type %s not found", im.Type)
 		}
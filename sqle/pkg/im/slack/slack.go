@@ -0,0 +1,320 @@
+// Package slack sends the interactive approval messages through Slack Web API,
+// see https://api.slack.com/web and https://api.slack.com/interactivity/handling.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops"
+)
+
+const (
+	DefaultBaseUrl = "https://slack.com/api"
+
+	signatureVersion = "v0"
+	headerSignature  = "X-Slack-Signature"
+	headerTimestamp  = "X-Slack-Request-Timestamp"
+	// the requests which are older than it are rejected to prevent replay attack.
+	maxRequestAge = 5 * time.Minute
+
+	reasonBlockId  = "reason"
+	reasonActionId = "reason_input"
+)
+
+type Client struct {
+	baseUrl       string
+	token         string
+	signingSecret string
+	httpClient    *http.Client
+}
+
+// NewClient creates the client with the bot token and signing secret of Slack app,
+// DefaultBaseUrl is used if baseUrl is empty.
+func NewClient(token, signingSecret, baseUrl string) *Client {
+	if baseUrl == "" {
+		baseUrl = DefaultBaseUrl
+	}
+	return &Client{
+		baseUrl:       strings.TrimSuffix(baseUrl, "/"),
+		token:         token,
+		signingSecret: signingSecret,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *Client) Type() string {
+	return model.ImTypeSlack
+}
+
+type baseResp struct {
+	Ok    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+func (c *Client) call(ctx context.Context, method string, body interface{}, res interface{}) error {
+	var req *http.Request
+	var err error
+	if body == nil {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, c.baseUrl+"/"+method, nil)
+	} else {
+		data, e := json.Marshal(body)
+		if e != nil {
+			return e
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.baseUrl+"/"+method, bytes.NewReader(data))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	return c.do(req, method, res)
+}
+
+func (c *Client) do(req *http.Request, method string, res interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request slack %s failed: %v", method, err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request slack %s failed: status=%v, body=%s", method, resp.StatusCode, data)
+	}
+	if res == nil {
+		return nil
+	}
+	base := &baseResp{}
+	if err := json.Unmarshal(data, base); err != nil {
+		return fmt.Errorf("unmarshal response of slack %s failed: %v", method, err)
+	}
+	if !base.Ok {
+		return fmt.Errorf("request slack %s failed: %s", method, base.Error)
+	}
+	return json.Unmarshal(data, res)
+}
+
+func (c *Client) lookupUserByEmail(ctx context.Context, email string) (string, error) {
+	res := &struct {
+		User struct {
+			Id string `json:"id"`
+		} `json:"user"`
+	}{}
+	if err := c.call(ctx, "users.lookupByEmail?email="+url.QueryEscape(email), nil, res); err != nil {
+		return "", err
+	}
+	return res.User.Id, nil
+}
+
+type postMessageResp struct {
+	Channel string `json:"channel"`
+	Ts      string `json:"ts"`
+}
+
+func (c *Client) post(ctx context.Context, recipient chatops.Recipient, text string, blocks []block) (*chatops.MessageRef, error) {
+	userId, err := c.lookupUserByEmail(ctx, recipient.Email)
+	if err != nil {
+		return nil, err
+	}
+	res := &postMessageResp{}
+	// posting to the user id delivers the message to the direct message channel of app.
+	err = c.call(ctx, "chat.postMessage", map[string]interface{}{
+		"channel": userId,
+		"text":    text,
+		"blocks":  blocks,
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+	return &chatops.MessageRef{UserId: userId, ChannelId: res.Channel, MessageId: res.Ts}, nil
+}
+
+func (c *Client) SendText(ctx context.Context, recipient chatops.Recipient, text string) error {
+	_, err := c.post(ctx, recipient, text, nil)
+	return err
+}
+
+func (c *Client) SendApproval(ctx context.Context, recipient chatops.Recipient, msg *chatops.ApprovalMessage) (*chatops.MessageRef, error) {
+	return c.post(ctx, recipient, msg.Title, buildBlocks(msg))
+}
+
+func (c *Client) UpdateApproval(ctx context.Context, ref *chatops.MessageRef, msg *chatops.ApprovalMessage) error {
+	return c.call(ctx, "chat.update", map[string]interface{}{
+		"channel": ref.ChannelId,
+		"ts":      ref.MessageId,
+		"text":    msg.Title,
+		"blocks":  buildBlocks(msg),
+	}, &postMessageResp{})
+}
+
+func (c *Client) Reply(ctx context.Context, cb *chatops.Callback, text string) error {
+	if cb.ResponseUrl == "" {
+		return nil
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"response_type":    "ephemeral",
+		"replace_original": false,
+		"text":             text,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cb.ResponseUrl, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return c.do(req, "response_url", nil)
+}
+
+// Sign returns the signature of request body in the way of Slack, see
+// https://api.slack.com/authentication/verifying-requests-from-slack.
+func Sign(signingSecret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte(fmt.Sprintf("%s:%s:", signatureVersion, timestamp)))
+	mac.Write(body)
+	return signatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Client) verify(header http.Header, body []byte) error {
+	timestamp := header.Get(headerTimestamp)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return chatops.ErrInvalidSignature
+	}
+	age := time.Since(time.Unix(ts, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return chatops.ErrInvalidSignature
+	}
+	if c.signingSecret == "" || !hmac.Equal([]byte(Sign(c.signingSecret, timestamp, body)), []byte(header.Get(headerSignature))) {
+		return chatops.ErrInvalidSignature
+	}
+	return nil
+}
+
+type interactionPayload struct {
+	Type string `json:"type"`
+	User struct {
+		Id string `json:"id"`
+	} `json:"user"`
+	Container struct {
+		ChannelId string `json:"channel_id"`
+		MessageTs string `json:"message_ts"`
+	} `json:"container"`
+	ResponseUrl string `json:"response_url"`
+	Actions     []struct {
+		ActionId string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	State struct {
+		Values map[string]map[string]struct {
+			Value string `json:"value"`
+		} `json:"values"`
+	} `json:"state"`
+}
+
+func (c *Client) ParseCallback(r *http.Request) (*chatops.Callback, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.verify(r.Header, body); err != nil {
+		return nil, err
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("parse slack callback failed: %v", err)
+	}
+	payload := &interactionPayload{}
+	if err := json.Unmarshal([]byte(form.Get("payload")), payload); err != nil {
+		return nil, fmt.Errorf("parse slack callback payload failed: %v", err)
+	}
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		return nil, fmt.Errorf("unsupported slack callback type %s", payload.Type)
+	}
+
+	cb := &chatops.Callback{
+		Action:      payload.Actions[0].ActionId,
+		UserId:      payload.User.Id,
+		ChannelId:   payload.Container.ChannelId,
+		MessageId:   payload.Container.MessageTs,
+		ResponseUrl: payload.ResponseUrl,
+	}
+	if input, ok := payload.State.Values[reasonBlockId][reasonActionId]; ok {
+		cb.Reason = input.Value
+	}
+	return cb, nil
+}
+
+// block is the layout block of Slack message, see https://api.slack.com/reference/block-kit/blocks.
+type block map[string]interface{}
+
+func text(typ, content string) map[string]interface{} {
+	return map[string]interface{}{"type": typ, "text": content}
+}
+
+func button(actionId, label, style string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":      "button",
+		"action_id": actionId,
+		"text":      text("plain_text", label),
+		"style":     style,
+		"value":     actionId,
+	}
+}
+
+func buildBlocks(msg *chatops.ApprovalMessage) []block {
+	blocks := []block{
+		{"type": "header", "text": text("plain_text", msg.Title)},
+	}
+	if len(msg.Fields) > 0 {
+		lines := make([]string, 0, len(msg.Fields))
+		for _, f := range msg.Fields {
+			lines = append(lines, fmt.Sprintf("*%s:* %s", f.Name, f.Value))
+		}
+		blocks = append(blocks, block{"type": "section", "text": text("mrkdwn", strings.Join(lines, "\n"))})
+	}
+	if msg.Url != "" {
+		blocks = append(blocks, block{"type": "section", "text": text("mrkdwn", fmt.Sprintf("<%s|%s>", msg.Url, msg.UrlText))})
+	}
+
+	if msg.Closed() {
+		return append(blocks, block{"type": "context", "elements": []interface{}{text("mrkdwn", msg.Result)}})
+	}
+	return append(blocks,
+		block{
+			"type":     "input",
+			"block_id": reasonBlockId,
+			"optional": true,
+			"label":    text("plain_text", msg.ReasonText),
+			"element":  map[string]interface{}{"type": "plain_text_input", "action_id": reasonActionId},
+		},
+		block{
+			"type": "actions",
+			"elements": []interface{}{
+				button(chatops.ActionApprove, msg.ApproveText, "primary"),
+				button(chatops.ActionReject, msg.RejectText, "danger"),
+			},
+		},
+	)
+}
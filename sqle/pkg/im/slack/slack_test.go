@@ -0,0 +1,116 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops"
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops/chatopstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func testApprovalMessage() *chatops.ApprovalMessage {
+	return &chatops.ApprovalMessage{
+		Title:       "工单待审批",
+		Fields:      []chatops.Field{{Name: "工单名称", Value: "wf1"}},
+		Url:         "http://sqle/project/1/exec-workflow/1",
+		UrlText:     "查看工单",
+		ApproveText: "通过",
+		RejectText:  "驳回",
+		ReasonText:  "驳回原因",
+	}
+}
+
+func blockTypes(body map[string]interface{}) []string {
+	types := []string{}
+	blocks, _ := body["blocks"].([]interface{})
+	for _, b := range blocks {
+		types = append(types, b.(map[string]interface{})["type"].(string))
+	}
+	return types
+}
+
+func TestSendAndUpdateApproval(t *testing.T) {
+	standIn := chatopstest.NewStandIn()
+	defer standIn.Close()
+	standIn.AddUser("admin@example.com", "U1")
+
+	c := NewClient("xoxb-token", "secret", standIn.URL)
+	msg := testApprovalMessage()
+	ref, err := c.SendApproval(context.Background(), chatops.Recipient{Email: "admin@example.com"}, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "U1", ref.UserId)
+
+	m, ok := standIn.Message(ref.ChannelId, ref.MessageId)
+	assert.True(t, ok)
+	assert.Equal(t, "工单待审批", m.Text)
+	assert.Equal(t, []string{"header", "section", "section", "input", "actions"}, blockTypes(m.Body))
+
+	msg.Result = "已由 admin 审批通过"
+	assert.NoError(t, c.UpdateApproval(context.Background(), ref, msg))
+	m, _ = standIn.Message(ref.ChannelId, ref.MessageId)
+	assert.Equal(t, 1, m.Updated)
+	assert.Equal(t, []string{"header", "section", "section", "context"}, blockTypes(m.Body))
+
+	_, err = c.SendApproval(context.Background(), chatops.Recipient{Email: "unknown@example.com"}, msg)
+	assert.Error(t, err)
+}
+
+func newCallbackRequest(secret string, ts time.Time, payload interface{}) *http.Request {
+	data, _ := json.Marshal(payload)
+	body := url.Values{"payload": []string{string(data)}}.Encode()
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	r := httptest.NewRequest(http.MethodPost, "/v1/im/slack/callback", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set(headerTimestamp, timestamp)
+	r.Header.Set(headerSignature, Sign(secret, timestamp, []byte(body)))
+	return r
+}
+
+func TestParseCallback(t *testing.T) {
+	payload := map[string]interface{}{
+		"type":         "block_actions",
+		"user":         map[string]string{"id": "U1"},
+		"container":    map[string]string{"channel_id": "DU1", "message_ts": "ts.1"},
+		"response_url": "http://localhost/slack/response",
+		"actions":      []map[string]string{{"action_id": chatops.ActionReject, "value": chatops.ActionReject}},
+		"state": map[string]interface{}{"values": map[string]interface{}{
+			reasonBlockId: map[string]interface{}{reasonActionId: map[string]string{"type": "plain_text_input", "value": "need index"}},
+		}},
+	}
+	c := NewClient("xoxb-token", "secret", "")
+
+	cb, err := c.ParseCallback(newCallbackRequest("secret", time.Now(), payload))
+	assert.NoError(t, err)
+	assert.Equal(t, &chatops.Callback{
+		Action:      chatops.ActionReject,
+		Reason:      "need index",
+		UserId:      "U1",
+		ChannelId:   "DU1",
+		MessageId:   "ts.1",
+		ResponseUrl: "http://localhost/slack/response",
+	}, cb)
+
+	_, err = c.ParseCallback(newCallbackRequest("other", time.Now(), payload))
+	assert.Equal(t, chatops.ErrInvalidSignature, err)
+
+	_, err = c.ParseCallback(newCallbackRequest("secret", time.Now().Add(-10*time.Minute), payload))
+	assert.Equal(t, chatops.ErrInvalidSignature, err)
+}
+
+func TestReply(t *testing.T) {
+	standIn := chatopstest.NewStandIn()
+	defer standIn.Close()
+
+	c := NewClient("xoxb-token", "secret", standIn.URL)
+	err := c.Reply(context.Background(), &chatops.Callback{ResponseUrl: standIn.URL + chatopstest.SlackResponsePath}, "工单状态已变更")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"工单状态已变更"}, standIn.Replies())
+}
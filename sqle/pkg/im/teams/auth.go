@@ -0,0 +1,172 @@
+package teams
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	OpenIdMetadataPath       = "/v1/.well-known/openidconfiguration"
+	DefaultOpenIdMetadataUrl = "https://login.botframework.com" + OpenIdMetadataPath
+	// BotFrameworkIssuer is the issuer of the token sent by Bot Framework to bot.
+	BotFrameworkIssuer = "https://api.botframework.com"
+
+	// the signing keys are refreshed once a day as Bot Framework suggests, and
+	// refreshed at most once in signingKeysMinRefresh if the key of token is unknown.
+	signingKeysTTL        = 24 * time.Hour
+	signingKeysMinRefresh = 5 * time.Minute
+	tokenClockSkew        = 5 * time.Minute
+)
+
+type signingKey struct {
+	key *rsa.PublicKey
+	// endorsements are the channels which the key can be used for.
+	endorsements []string
+}
+
+type jsonWebKey struct {
+	Kid          string   `json:"kid"`
+	Kty          string   `json:"kty"`
+	N            string   `json:"n"`
+	E            string   `json:"e"`
+	Endorsements []string `json:"endorsements"`
+}
+
+func (k *jsonWebKey) publicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// fetchSigningKeys gets the signing keys of Bot Framework by the OpenID metadata,
+// see https://learn.microsoft.com/en-us/azure/bot-service/rest-api/bot-framework-rest-connector-authentication.
+func (c *Client) fetchSigningKeys(ctx context.Context) (map[string]signingKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.OpenIdMetadataUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &struct {
+		JwksUri string `json:"jwks_uri"`
+	}{}
+	if err := c.do(req, metadata); err != nil {
+		return nil, fmt.Errorf("get bot framework openid metadata failed: %v", err)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, metadata.JwksUri, nil)
+	if err != nil {
+		return nil, err
+	}
+	jwks := &struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}{}
+	if err := c.do(req, jwks); err != nil {
+		return nil, fmt.Errorf("get bot framework signing keys failed: %v", err)
+	}
+	keys := map[string]signingKey{}
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("parse bot framework signing key %s failed: %v", k.Kid, err)
+		}
+		keys[k.Kid] = signingKey{key: key, endorsements: k.Endorsements}
+	}
+	return keys, nil
+}
+
+// getSigningKey returns the signing key by key id, the keys are cached.
+func (c *Client) getSigningKey(ctx context.Context, kid string) (signingKey, error) {
+	c.keysMu.Lock()
+	defer c.keysMu.Unlock()
+	key, ok := c.keys[kid]
+	age := time.Since(c.keysFetchedAt)
+	if (ok && age < signingKeysTTL) || (!ok && age < signingKeysMinRefresh) {
+		if !ok {
+			return signingKey{}, fmt.Errorf("unknown signing key %s", kid)
+		}
+		return key, nil
+	}
+	keys, err := c.fetchSigningKeys(ctx)
+	if err != nil {
+		return signingKey{}, err
+	}
+	c.keys = keys
+	c.keysFetchedAt = time.Now()
+	key, ok = keys[kid]
+	if !ok {
+		return signingKey{}, fmt.Errorf("unknown signing key %s", kid)
+	}
+	return key, nil
+}
+
+// verifyToken verifies the token in Authorization header of the request sent by
+// Bot Framework, the token should be signed by the key of Bot Framework and issued
+// to the bot, and the service url in token should be the same as the activity.
+func (c *Client) verifyToken(r *http.Request, channelId, serviceUrl string) error {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return chatops.ErrInvalidSignature
+	}
+	parser := &jwt.Parser{ValidMethods: []string{"RS256"}, SkipClaimsValidation: true}
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(strings.TrimPrefix(auth, "Bearer "), claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := c.getSigningKey(r.Context(), kid)
+		if err != nil {
+			return nil, err
+		}
+		if len(key.endorsements) > 0 && !contains(key.endorsements, channelId) {
+			return nil, fmt.Errorf("signing key %s is not endorsed for channel %s", kid, channelId)
+		}
+		return key.key, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", chatops.ErrInvalidSignature, err)
+	}
+
+	now := time.Now()
+	switch {
+	case !claims.VerifyIssuer(BotFrameworkIssuer, true):
+		return fmt.Errorf("%w: unexpected issuer", chatops.ErrInvalidSignature)
+	case !claims.VerifyAudience(c.cfg.AppId, true):
+		return fmt.Errorf("%w: unexpected audience", chatops.ErrInvalidSignature)
+	case !claims.VerifyExpiresAt(now.Add(-tokenClockSkew).Unix(), true):
+		return fmt.Errorf("%w: token is expired", chatops.ErrInvalidSignature)
+	case !claims.VerifyNotBefore(now.Add(tokenClockSkew).Unix(), false):
+		return fmt.Errorf("%w: token is not valid yet", chatops.ErrInvalidSignature)
+	}
+	if tokenServiceUrl, _ := claims["serviceurl"].(string); tokenServiceUrl != serviceUrl {
+		return fmt.Errorf("%w: unexpected service url", chatops.ErrInvalidSignature)
+	}
+	return nil
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
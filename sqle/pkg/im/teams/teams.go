@@ -0,0 +1,346 @@
+// Package teams sends the interactive approval messages to Microsoft Teams through
+// the Bot Framework connector, the message is an adaptive card with the actions of
+// approval, see https://learn.microsoft.com/en-us/microsoftteams/platform/bots/how-to/conversations/send-proactive-messages.
+// The actions are sent back to the messaging endpoint of bot by Bot Framework, the
+// request is verified by the token signed by Bot Framework.
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops"
+)
+
+const (
+	DefaultServiceUrl = "https://smba.trafficmanager.net/teams"
+	DefaultLoginUrl   = "https://login.microsoftonline.com"
+	DefaultGraphUrl   = "https://graph.microsoft.com/v1.0"
+
+	botFrameworkScope = "https://api.botframework.com/.default"
+	graphScope        = "https://graph.microsoft.com/.default"
+
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	reasonInputId           = "reason"
+)
+
+type Config struct {
+	AppId       string
+	AppPassword string
+	TenantId    string
+	// ServiceUrl, LoginUrl, GraphUrl and OpenIdMetadataUrl use the default urls of Microsoft if they are empty.
+	ServiceUrl        string
+	LoginUrl          string
+	GraphUrl          string
+	OpenIdMetadataUrl string
+}
+
+type accessToken struct {
+	token     string
+	expiredAt time.Time
+}
+
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	tokens map[string] /*scope*/ accessToken
+
+	keysMu        sync.Mutex
+	keys          map[string] /*key id*/ signingKey
+	keysFetchedAt time.Time
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.ServiceUrl == "" {
+		cfg.ServiceUrl = DefaultServiceUrl
+	}
+	if cfg.LoginUrl == "" {
+		cfg.LoginUrl = DefaultLoginUrl
+	}
+	if cfg.GraphUrl == "" {
+		cfg.GraphUrl = DefaultGraphUrl
+	}
+	if cfg.OpenIdMetadataUrl == "" {
+		cfg.OpenIdMetadataUrl = DefaultOpenIdMetadataUrl
+	}
+	cfg.ServiceUrl = strings.TrimSuffix(cfg.ServiceUrl, "/")
+	cfg.LoginUrl = strings.TrimSuffix(cfg.LoginUrl, "/")
+	cfg.GraphUrl = strings.TrimSuffix(cfg.GraphUrl, "/")
+	return &Client{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		tokens:     map[string]accessToken{},
+	}
+}
+
+func (c *Client) Type() string {
+	return model.ImTypeTeams
+}
+
+// getToken gets the access token by the client credentials of app, the token is cached until it expires.
+func (c *Client) getToken(ctx context.Context, scope string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t, ok := c.tokens[scope]; ok && time.Now().Before(t.expiredAt) {
+		return t.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", c.cfg.AppId)
+	form.Set("client_secret", c.cfg.AppPassword)
+	form.Set("scope", scope)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/%s/oauth2/v2.0/token", c.cfg.LoginUrl, url.PathEscape(c.cfg.TenantId)), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res := &struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{}
+	if err := c.do(req, res); err != nil {
+		return "", fmt.Errorf("get teams access token failed: %v", err)
+	}
+	// refresh the token one minute before it expires.
+	c.tokens[scope] = accessToken{
+		token:     res.AccessToken,
+		expiredAt: time.Now().Add(time.Duration(res.ExpiresIn)*time.Second - time.Minute),
+	}
+	return res.AccessToken, nil
+}
+
+func (c *Client) do(req *http.Request, res interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("request %s failed: status=%v, body=%s", req.URL.Path, resp.StatusCode, data)
+	}
+	if res == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, res)
+}
+
+func (c *Client) call(ctx context.Context, scope, method, url string, body interface{}, res interface{}) error {
+	token, err := c.getToken(ctx, scope)
+	if err != nil {
+		return err
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	return c.do(req, res)
+}
+
+// lookupUserByEmail returns the Azure AD object id of user.
+func (c *Client) lookupUserByEmail(ctx context.Context, email string) (string, error) {
+	res := &struct {
+		Id string `json:"id"`
+	}{}
+	err := c.call(ctx, graphScope, http.MethodGet,
+		fmt.Sprintf("%s/users/%s?$select=id", c.cfg.GraphUrl, url.PathEscape(email)), nil, res)
+	if err != nil {
+		return "", fmt.Errorf("get teams user by email failed: %v", err)
+	}
+	return res.Id, nil
+}
+
+type idResp struct {
+	Id string `json:"id"`
+}
+
+// createConversation creates the personal conversation between the bot and user.
+func (c *Client) createConversation(ctx context.Context, userId string) (string, error) {
+	res := &idResp{}
+	err := c.call(ctx, botFrameworkScope, http.MethodPost, c.cfg.ServiceUrl+"/v3/conversations", map[string]interface{}{
+		"isGroup":     false,
+		"bot":         map[string]string{"id": c.cfg.AppId},
+		"members":     []map[string]string{{"id": userId}},
+		"tenantId":    c.cfg.TenantId,
+		"channelData": map[string]interface{}{"tenant": map[string]string{"id": c.cfg.TenantId}},
+	}, res)
+	if err != nil {
+		return "", fmt.Errorf("create teams conversation failed: %v", err)
+	}
+	return res.Id, nil
+}
+
+func (c *Client) activitiesUrl(conversationId string) string {
+	return fmt.Sprintf("%s/v3/conversations/%s/activities", c.cfg.ServiceUrl, url.PathEscape(conversationId))
+}
+
+func (c *Client) post(ctx context.Context, recipient chatops.Recipient, activity map[string]interface{}) (*chatops.MessageRef, error) {
+	userId, err := c.lookupUserByEmail(ctx, recipient.Email)
+	if err != nil {
+		return nil, err
+	}
+	conversationId, err := c.createConversation(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	res := &idResp{}
+	if err := c.call(ctx, botFrameworkScope, http.MethodPost, c.activitiesUrl(conversationId), activity, res); err != nil {
+		return nil, fmt.Errorf("send teams message failed: %v", err)
+	}
+	return &chatops.MessageRef{UserId: userId, ChannelId: conversationId, MessageId: res.Id}, nil
+}
+
+func (c *Client) SendText(ctx context.Context, recipient chatops.Recipient, text string) error {
+	_, err := c.post(ctx, recipient, map[string]interface{}{"type": "message", "text": text})
+	return err
+}
+
+func (c *Client) SendApproval(ctx context.Context, recipient chatops.Recipient, msg *chatops.ApprovalMessage) (*chatops.MessageRef, error) {
+	return c.post(ctx, recipient, cardActivity(msg))
+}
+
+func (c *Client) UpdateApproval(ctx context.Context, ref *chatops.MessageRef, msg *chatops.ApprovalMessage) error {
+	activity := cardActivity(msg)
+	activity["id"] = ref.MessageId
+	err := c.call(ctx, botFrameworkScope, http.MethodPut,
+		c.activitiesUrl(ref.ChannelId)+"/"+url.PathEscape(ref.MessageId), activity, nil)
+	if err != nil {
+		return fmt.Errorf("update teams message failed: %v", err)
+	}
+	return nil
+}
+
+func (c *Client) Reply(ctx context.Context, cb *chatops.Callback, text string) error {
+	err := c.call(ctx, botFrameworkScope, http.MethodPost, c.activitiesUrl(cb.ChannelId), map[string]interface{}{
+		"type":      "message",
+		"text":      text,
+		"replyToId": cb.MessageId,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("reply teams message failed: %v", err)
+	}
+	return nil
+}
+
+type activity struct {
+	Type       string `json:"type"`
+	ChannelId  string `json:"channelId"`
+	ServiceUrl string `json:"serviceUrl"`
+	From       struct {
+		Id          string `json:"id"`
+		AadObjectId string `json:"aadObjectId"`
+	} `json:"from"`
+	Conversation struct {
+		Id string `json:"id"`
+	} `json:"conversation"`
+	ReplyToId string `json:"replyToId"`
+	Value     struct {
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	} `json:"value"`
+}
+
+func (c *Client) ParseCallback(r *http.Request) (*chatops.Callback, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	a := &activity{}
+	if err := json.Unmarshal(body, a); err != nil {
+		return nil, fmt.Errorf("parse teams callback failed: %v", err)
+	}
+	if err := c.verifyToken(r, a.ChannelId, a.ServiceUrl); err != nil {
+		return nil, err
+	}
+	if a.Type != "message" || a.Value.Action == "" {
+		return nil, fmt.Errorf("unsupported teams callback type %s", a.Type)
+	}
+	return &chatops.Callback{
+		Action:    a.Value.Action,
+		Reason:    a.Value.Reason,
+		UserId:    a.From.AadObjectId,
+		ChannelId: a.Conversation.Id,
+		MessageId: a.ReplyToId,
+	}, nil
+}
+
+func submitAction(action, title string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":  "Action.Submit",
+		"title": title,
+		"data":  map[string]string{"action": action},
+	}
+}
+
+// cardActivity builds the message with adaptive card, see https://adaptivecards.io/explorer/.
+func cardActivity(msg *chatops.ApprovalMessage) map[string]interface{} {
+	facts := make([]map[string]string, 0, len(msg.Fields))
+	for _, f := range msg.Fields {
+		facts = append(facts, map[string]string{"title": f.Name, "value": f.Value})
+	}
+	body := []interface{}{
+		map[string]interface{}{"type": "TextBlock", "text": msg.Title, "weight": "bolder", "size": "medium", "wrap": true},
+		map[string]interface{}{"type": "FactSet", "facts": facts},
+	}
+	actions := []interface{}{}
+	if msg.Closed() {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": msg.Result, "wrap": true})
+	} else {
+		body = append(body, map[string]interface{}{
+			"type": "Input.Text", "id": reasonInputId, "placeholder": msg.ReasonText, "isMultiline": true,
+		})
+		actions = append(actions,
+			submitAction(chatops.ActionApprove, msg.ApproveText),
+			submitAction(chatops.ActionReject, msg.RejectText),
+		)
+	}
+	if msg.Url != "" {
+		actions = append(actions, map[string]interface{}{"type": "Action.OpenUrl", "title": msg.UrlText, "url": msg.Url})
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{
+			map[string]interface{}{
+				"contentType": adaptiveCardContentType,
+				"content": map[string]interface{}{
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"body":    body,
+					"actions": actions,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops"
+	"github.com/actiontech/sqle/sqle/pkg/im/chatops/chatopstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(baseUrl string) *Client {
+	return NewClient(Config{
+		AppId:             "app",
+		AppPassword:       "password",
+		TenantId:          "tenant",
+		ServiceUrl:        baseUrl,
+		LoginUrl:          baseUrl,
+		GraphUrl:          baseUrl,
+		OpenIdMetadataUrl: baseUrl + OpenIdMetadataPath,
+	})
+}
+
+func cardActions(body map[string]interface{}) int {
+	attachments := body["attachments"].([]interface{})
+	content := attachments[0].(map[string]interface{})["content"].(map[string]interface{})
+	return len(content["actions"].([]interface{}))
+}
+
+func TestSendAndUpdateApproval(t *testing.T) {
+	standIn := chatopstest.NewStandIn()
+	defer standIn.Close()
+	standIn.AddUser("admin@example.com", "aad-1")
+
+	c := newTestClient(standIn.URL)
+	msg := &chatops.ApprovalMessage{
+		Title:       "工单待审批",
+		Fields:      []chatops.Field{{Name: "工单名称", Value: "wf1"}},
+		Url:         "http://sqle/project/1/exec-workflow/1",
+		UrlText:     "查看工单",
+		ApproveText: "通过",
+		RejectText:  "驳回",
+		ReasonText:  "驳回原因",
+	}
+	ref, err := c.SendApproval(context.Background(), chatops.Recipient{Email: "admin@example.com"}, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "aad-1", ref.UserId)
+
+	m, ok := standIn.Message(ref.ChannelId, ref.MessageId)
+	assert.True(t, ok)
+	// approve, reject and open url
+	assert.Equal(t, 3, cardActions(m.Body))
+
+	msg.Result = "已由 admin 驳回"
+	assert.NoError(t, c.UpdateApproval(context.Background(), ref, msg))
+	m, _ = standIn.Message(ref.ChannelId, ref.MessageId)
+	assert.Equal(t, 1, m.Updated)
+	assert.Equal(t, 1, cardActions(m.Body))
+
+	assert.NoError(t, c.Reply(context.Background(), &chatops.Callback{ChannelId: ref.ChannelId, MessageId: ref.MessageId}, "ok"))
+	assert.Equal(t, []string{"ok"}, standIn.Replies())
+}
+
+func TestParseCallback(t *testing.T) {
+	standIn := chatopstest.NewStandIn()
+	defer standIn.Close()
+	c := newTestClient(standIn.URL)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"type":         "message",
+		"channelId":    chatopstest.TeamsChannelId,
+		"serviceUrl":   standIn.URL,
+		"from":         map[string]string{"id": "29:1", "aadObjectId": "aad-1"},
+		"conversation": map[string]string{"id": "conversation.1"},
+		"replyToId":    "activity.2",
+		"value":        map[string]string{"action": chatops.ActionApprove, "reason": ""},
+	})
+	newRequest := func(auth string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/v1/im/teams/callback", bytes.NewReader(body))
+		r.Header.Set("Authorization", auth)
+		return r
+	}
+
+	cb, err := c.ParseCallback(newRequest("Bearer " + standIn.TeamsToken("app", standIn.URL, time.Now().Add(time.Hour))))
+	assert.NoError(t, err)
+	assert.Equal(t, &chatops.Callback{
+		Action:    chatops.ActionApprove,
+		UserId:    "aad-1",
+		ChannelId: "conversation.1",
+		MessageId: "activity.2",
+	}, cb)
+
+	for _, auth := range []string{
+		"",
+		"Bearer invalid",
+		// issued to other bot
+		"Bearer " + standIn.TeamsToken("other", standIn.URL, time.Now().Add(time.Hour)),
+		// issued for other service url
+		"Bearer " + standIn.TeamsToken("app", "https://smba.trafficmanager.net/teams", time.Now().Add(time.Hour)),
+		"Bearer " + standIn.TeamsToken("app", standIn.URL, time.Now().Add(-time.Hour)),
+	} {
+		_, err = c.ParseCallback(newRequest(auth))
+		assert.ErrorIs(t, err, chatops.ErrInvalidSignature)
+	}
+
+	// the token signed by other key is rejected.
+	other := chatopstest.NewStandIn()
+	defer other.Close()
+	_, err = c.ParseCallback(newRequest("Bearer " + other.TeamsToken("app", standIn.URL, time.Now().Add(time.Hour))))
+	assert.ErrorIs(t, err, chatops.ErrInvalidSignature)
+}
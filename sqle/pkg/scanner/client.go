@@ -31,6 +31,8 @@ const (
 	PartialUpload = "/sqle/v2/projects/%v/audit_plans/%s/sqls/partial"
 	// Get										                  %v=report_id
 	GetAuditReport = "/sqle/v1/projects/%v/audit_plans/%s/reports/%v/sqls?page_index=%d&page_size=%d"
+	// Post
+	DirectAuditFiles = "/sqle/v2/audit_files"
 )
 
 type (
@@ -40,6 +42,9 @@ type (
 	FullSyncAuditPlanSQLsReq    = v2.FullSyncAuditPlanSQLsReqV2
 	PartialSyncAuditPlanSQLsReq = v1.PartialSyncAuditPlanSQLsReqV1
 	TriggerAuditPlanRes         = v1.TriggerAuditPlanResV1
+	DirectAuditFileReq          = v2.DirectAuditFileReqV2
+	DirectAuditRes              = v2.DirectAuditResV2
+	AuditResData                = v2.AuditResDataV2
 )
 
 type Client struct {
@@ -163,6 +168,37 @@ func (sc *Client) GetAuditReportReq(auditPlanName string, reportID string) error
 	return finalErr
 }
 
+// DirectAuditFilesReq audits the file contents without creating a workflow,
+// the project of the request is set to the project of client.
+func (sc *Client) DirectAuditFilesReq(ctx context.Context, req *DirectAuditFileReq) (*AuditResData, error) {
+	req.ProjectName = sc.project
+	bodyBuf := &bytes.Buffer{}
+	encoder := json.NewEncoder(bodyBuf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(req); err != nil {
+		return nil, err
+	}
+
+	url := sc.baseURL + DirectAuditFiles
+	resBody, err := sc.httpClient.sendRequest(ctx, url, http.MethodPost, sc.token, bodyBuf)
+	if err != nil {
+		return nil, err
+	}
+
+	auditRes := new(DirectAuditRes)
+	err = json.Unmarshal(resBody, auditRes)
+	if err != nil {
+		return nil, err
+	}
+	if auditRes.Code != 0 {
+		return nil, fmt.Errorf("failed to request %s, error:%s", url, auditRes.Message)
+	}
+	if auditRes.Data == nil {
+		return nil, fmt.Errorf("failed to request %s, the audit result is empty", url)
+	}
+	return auditRes.Data, nil
+}
+
 const (
 	DefaultTimeoutNum = 10
 	DefaultTimeout    = time.Second * time.Duration(DefaultTimeoutNum)
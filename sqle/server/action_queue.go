@@ -0,0 +1,307 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ActionQueueBackendStorage = "storage"
+	ActionQueueBackendMemory  = "memory"
+)
+
+// actionQueue is the scheduling backend of Sqled.
+type actionQueue interface {
+	// push adds the action to the queue.
+	push(a *action) error
+	// actions returns the channel which Sqled receives actions from.
+	actions() <-chan *action
+	// done removes the action from the queue after it is done.
+	done(a *action)
+	// start starts the background jobs of the queue.
+	start(s *Sqled)
+}
+
+// newActionQueue keeps the actions in memory unless the storage backend is configured,
+// so the deployments without the configuration behave as before.
+func newActionQueue(backend string, nodeId string) actionQueue {
+	switch backend {
+	case ActionQueueBackendStorage:
+		return newStorageActionQueue(nodeId)
+	default:
+		return newMemoryActionQueue()
+	}
+}
+
+// memoryActionQueue keeps actions in memory, they are lost when SQLE restarts.
+type memoryActionQueue struct {
+	queue chan *action
+}
+
+func newMemoryActionQueue() *memoryActionQueue {
+	return &memoryActionQueue{queue: make(chan *action, 1024)}
+}
+
+func (q *memoryActionQueue) push(a *action) error {
+	q.queue <- a
+	return nil
+}
+
+func (q *memoryActionQueue) actions() <-chan *action {
+	return q.queue
+}
+
+func (q *memoryActionQueue) done(a *action) {}
+
+func (q *memoryActionQueue) start(s *Sqled) {
+	go reconcileTasksWithoutAction(log.NewEntry().WithField("type", "action_queue"), s)
+}
+
+const (
+	actionLeaseTTL           = time.Minute
+	actionLeaseRenewInterval = 15 * time.Second
+)
+
+// storageActionQueue persists actions in the SQLE database with leases.
+// The node holding an action renews its lease until the action is done, the
+// action is taken as orphaned after the lease is expired and will be picked up
+// by another node, or by the same node after it restarts.
+type storageActionQueue struct {
+	sync.Mutex
+	nodeId string
+	queue  chan *action
+	// held is the claim tokens of the actions held by this node, keyed by task id.
+	held  map[uint]string
+	entry *logrus.Entry
+}
+
+func newStorageActionQueue(nodeId string) *storageActionQueue {
+	return &storageActionQueue{
+		nodeId: nodeId,
+		queue:  make(chan *action, 1024),
+		held:   map[uint]string{},
+		entry:  log.NewEntry().WithField("type", "action_queue"),
+	}
+}
+
+func (q *storageActionQueue) push(a *action) error {
+	token, err := utils.GenUid()
+	if err != nil {
+		return err
+	}
+	created, err := model.GetStorage().CreateSqledAction(&model.SqledAction{
+		TaskId:         a.task.ID,
+		Type:           a.typ,
+		LeaseOwner:     q.nodeId,
+		LeaseExpiredAt: time.Now().Add(actionLeaseTTL),
+		ClaimToken:     token,
+	})
+	if err != nil {
+		return err
+	}
+	if !created {
+		return errors.New(errors.TaskRunning, fmt.Errorf("task is running"))
+	}
+	a.claimToken = token
+	q.hold(a.task.ID, token)
+	q.queue <- a
+	return nil
+}
+
+func (q *storageActionQueue) actions() <-chan *action {
+	return q.queue
+}
+
+func (q *storageActionQueue) done(a *action) {
+	q.release(a.task.ID, a.claimToken)
+}
+
+// release deletes the action only if it is still claimed with the token, the
+// action may have been claimed by another node after the lease is expired.
+func (q *storageActionQueue) release(taskId uint, token string) {
+	q.Lock()
+	if q.held[taskId] == token {
+		delete(q.held, taskId)
+	}
+	q.Unlock()
+	if err := model.GetStorage().DeleteSqledAction(taskId, token); err != nil {
+		q.entry.Errorf("delete action of task %d failed, error: %v", taskId, err)
+	}
+}
+
+func (q *storageActionQueue) hold(taskId uint, token string) {
+	q.Lock()
+	q.held[taskId] = token
+	q.Unlock()
+}
+
+func (q *storageActionQueue) heldTaskIds() []uint {
+	q.Lock()
+	defer q.Unlock()
+	ids := make([]uint, 0, len(q.held))
+	for id := range q.held {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (q *storageActionQueue) heldTokens() []string {
+	q.Lock()
+	defer q.Unlock()
+	tokens := make([]string, 0, len(q.held))
+	for _, token := range q.held {
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+func (q *storageActionQueue) start(s *Sqled) {
+	go func() {
+		q.recover(s, true)
+		reconcileTasksWithoutAction(q.entry, s)
+
+		tick := time.NewTicker(actionLeaseRenewInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-s.exit:
+				return
+			case <-tick.C:
+				err := model.GetStorage().RenewSqledActionLeases(q.heldTokens(), time.Now().Add(actionLeaseTTL))
+				if err != nil {
+					q.entry.Errorf("renew action leases failed, error: %v", err)
+				}
+				q.recover(s, false)
+			}
+		}
+	}()
+}
+
+// recover picks up the orphaned actions. The actions held by this node before
+// restart are picked up too if claimOwned is true. The actions still running on
+// this node are not picked up, even if their leases are expired because the
+// leases failed to be renewed.
+func (q *storageActionQueue) recover(s *Sqled, claimOwned bool) {
+	items, err := model.GetStorage().ClaimSqledActions(q.nodeId, time.Now().Add(actionLeaseTTL), claimOwned, q.heldTaskIds())
+	if err != nil {
+		q.entry.Errorf("claim orphaned actions failed, error: %v", err)
+		return
+	}
+	for _, item := range items {
+		q.hold(item.TaskId, item.ClaimToken)
+		entry := q.entry.WithField("task_id", item.TaskId)
+		entry.Infof("recover the orphaned action, type: %d", item.Type)
+
+		if item.Type == ActionTypeExecute {
+			resume, err := reconcileExecutingTask(entry, item.TaskId)
+			if err != nil {
+				entry.Errorf("reconcile executing task failed, error: %v", err)
+				continue
+			}
+			if !resume {
+				q.release(item.TaskId, item.ClaimToken)
+				continue
+			}
+		}
+
+		a, err := s.newAction(strconv.Itoa(int(item.TaskId)), item.Type)
+		if err != nil {
+			entry.Warnf("drop the orphaned action, error: %v", err)
+			q.release(item.TaskId, item.ClaimToken)
+			continue
+		}
+		a.claimToken = item.ClaimToken
+		q.queue <- a
+	}
+}
+
+// reconcileTasksWithoutAction handles the tasks left in executing status
+// without action, such as the tasks executed by SQLE before upgrade, or before
+// restart if the actions are kept in memory.
+func reconcileTasksWithoutAction(l *logrus.Entry, s *Sqled) {
+	failed, err := model.GetStorage().FailRehearsingTasksWithoutSqledAction()
+	if err != nil {
+		l.Errorf("reset rehearsing tasks failed, error: %v", err)
+	} else if failed > 0 {
+		l.Warnf("%d tasks are interrupted while rehearsing, mark them failed", failed)
+	}
+
+	tasks, err := model.GetStorage().GetExecutingTasksWithoutSqledAction()
+	if err != nil {
+		l.Errorf("get executing tasks failed, error: %v", err)
+		return
+	}
+	for _, task := range tasks {
+		entry := l.WithField("task_id", task.ID)
+		resume, err := reconcileExecutingTask(entry, task.ID)
+		if err != nil {
+			entry.Errorf("reconcile executing task failed, error: %v", err)
+			continue
+		}
+		if !resume {
+			continue
+		}
+		entry.Info("resume the execution of task")
+		if err := s.AddTask(task.GetIDStr(), ActionTypeExecute); err != nil {
+			entry.Errorf("resume the execution of task failed, error: %v", err)
+		}
+	}
+}
+
+// reconcileExecutingTask handles the task left in executing status by a dead
+// node. It returns true if the task can be resumed, that is none of its SQLs
+// has been executed. Otherwise executing the SQLs again is unsafe, the SQLs
+// being executed are marked unknown and the task is marked failed.
+func reconcileExecutingTask(entry *logrus.Entry, taskId uint) (bool, error) {
+	st := model.GetStorage()
+	task, exist, err := st.GetTaskDetailById(strconv.Itoa(int(taskId)))
+	if err != nil {
+		return false, err
+	}
+	if !exist || task.Status != model.TaskStatusExecuting {
+		return true, nil
+	}
+	if !task.HasDoingExecute() {
+		return true, nil
+	}
+
+	entry.Warn("the task is interrupted while executing, mark it failed")
+	unknownSQLs := make([]*model.ExecuteSQL, 0)
+	for _, executeSQL := range task.ExecuteSQLs {
+		if executeSQL.ExecStatus == model.SQLExecuteStatusDoing {
+			executeSQL.ExecStatus = model.SQLExecuteStatusUnknown
+			executeSQL.ExecResult = "SQLE 在执行该 SQL 时退出，无法确认 SQL 是否执行成功，请人工确认"
+			unknownSQLs = append(unknownSQLs, executeSQL)
+		}
+	}
+	if err := st.UpdateExecuteSQLs(unknownSQLs); err != nil {
+		return false, err
+	}
+	if err := st.UpdateTask(task, map[string]interface{}{
+		"status":      model.TaskStatusExecuteFailed,
+		"exec_end_at": time.Now(),
+	}); err != nil {
+		return false, err
+	}
+	if err := st.FailExecutingTaskExecutionAttempts(task.ID); err != nil {
+		return false, err
+	}
+
+	recordId, exist, err := st.GetWorkflowRecordIdByTaskId(task.ID)
+	if err != nil {
+		return false, err
+	}
+	if exist {
+		updateStatus(st, &model.Workflow{Record: &model.WorkflowRecord{Model: model.Model{ID: recordId}}}, entry)
+	}
+	return false, nil
+}
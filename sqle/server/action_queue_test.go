@@ -0,0 +1,107 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/agiledragon/gomonkey"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_reconcileExecutingTask(t *testing.T) {
+	newTask := func(statuses ...string) *model.Task {
+		task := &model.Task{Model: model.Model{ID: 1}, Status: model.TaskStatusExecuting}
+		for _, status := range statuses {
+			task.ExecuteSQLs = append(task.ExecuteSQLs, &model.ExecuteSQL{
+				BaseSQL: model.BaseSQL{ExecStatus: status},
+			})
+		}
+		return task
+	}
+
+	tests := []struct {
+		name           string
+		task           *model.Task
+		expectResume   bool
+		expectStatuses []string
+		expectUpdated  bool
+	}{
+		{
+			name:           "none of SQLs is executed",
+			task:           newTask(model.SQLExecuteStatusInitialized, model.SQLExecuteStatusInitialized),
+			expectResume:   true,
+			expectStatuses: []string{model.SQLExecuteStatusInitialized, model.SQLExecuteStatusInitialized},
+		},
+		{
+			name:           "interrupted while executing",
+			task:           newTask(model.SQLExecuteStatusSucceeded, model.SQLExecuteStatusDoing, model.SQLExecuteStatusInitialized),
+			expectResume:   false,
+			expectStatuses: []string{model.SQLExecuteStatusSucceeded, model.SQLExecuteStatusUnknown, model.SQLExecuteStatusInitialized},
+			expectUpdated:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updatedStatus string
+			patches := gomonkey.ApplyMethod(reflect.TypeOf(&model.Storage{}), "GetTaskDetailById", func(_ *model.Storage, _ string) (*model.Task, bool, error) {
+				return tt.task, true, nil
+			})
+			defer patches.Reset()
+			patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateExecuteSQLs", func(_ *model.Storage, _ []*model.ExecuteSQL) error {
+				return nil
+			})
+			patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "UpdateTask", func(_ *model.Storage, _ *model.Task, attr interface{}) error {
+				updatedStatus = attr.(map[string]interface{})["status"].(string)
+				return nil
+			})
+			patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "FailExecutingTaskExecutionAttempts", func(_ *model.Storage, _ uint) error {
+				return nil
+			})
+			patches.ApplyMethod(reflect.TypeOf(&model.Storage{}), "GetWorkflowRecordIdByTaskId", func(_ *model.Storage, _ uint) (uint, bool, error) {
+				return 0, false, nil
+			})
+
+			resume, err := reconcileExecutingTask(log.NewEntry(), tt.task.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectResume, resume)
+			for idx, executeSQL := range tt.task.ExecuteSQLs {
+				assert.Equal(t, tt.expectStatuses[idx], executeSQL.ExecStatus)
+			}
+			if tt.expectUpdated {
+				assert.Equal(t, model.TaskStatusExecuteFailed, updatedStatus)
+			} else {
+				assert.Empty(t, updatedStatus)
+			}
+		})
+	}
+}
+
+func Test_newActionQueue(t *testing.T) {
+	assert.IsType(t, &memoryActionQueue{}, newActionQueue("", "1"))
+	assert.IsType(t, &memoryActionQueue{}, newActionQueue(ActionQueueBackendMemory, "1"))
+	assert.IsType(t, &storageActionQueue{}, newActionQueue(ActionQueueBackendStorage, "1"))
+}
+
+func Test_storageActionQueue_release(t *testing.T) {
+	var deletedToken string
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(&model.Storage{}), "DeleteSqledAction", func(_ *model.Storage, _ uint, token string) error {
+		deletedToken = token
+		return nil
+	})
+	defer patches.Reset()
+
+	q := &storageActionQueue{held: map[uint]string{}, entry: log.NewEntry()}
+	q.hold(1, "new")
+
+	// the action is claimed by this node again after the lease of the old claim is expired.
+	q.release(1, "old")
+	assert.Equal(t, "old", deletedToken)
+	assert.Equal(t, []string{"new"}, q.heldTokens())
+
+	q.release(1, "new")
+	assert.Equal(t, "new", deletedToken)
+	assert.Empty(t, q.heldTokens())
+}
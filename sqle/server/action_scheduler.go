@@ -0,0 +1,268 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+const (
+	// LaneInteractive is for the actions which users are waiting for, such as direct audit.
+	LaneInteractive = "interactive"
+	// LaneBatch is for the actions running in background, such as workflow execution.
+	LaneBatch = "batch"
+)
+
+// lanes in priority order.
+var lanes = []string{LaneInteractive, LaneBatch}
+
+const (
+	SlotKindAudit   = "audit"
+	SlotKindExecute = "execute"
+)
+
+// limitsRefreshInterval is how long the loaded limits are used before being
+// reloaded from storage.
+const limitsRefreshInterval = 10 * time.Second
+
+// SlotRequest describes what an action occupies while running.
+type SlotRequest struct {
+	Lane string `json:"lane"`
+	Kind string `json:"kind"`
+	// TaskId is 0 if the audited SQLs are not saved as task, such as direct audit.
+	TaskId     uint   `json:"task_id"`
+	InstanceId uint64 `json:"instance_id"`
+	// DDL reports whether the SQLs to execute contain DDL.
+	DDL bool `json:"ddl"`
+}
+
+type Slot struct {
+	SlotRequest
+	EnqueuedAt time.Time  `json:"enqueued_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	ready      chan struct{}
+}
+
+// actionScheduler limits the actions running at the same time, the waiting
+// actions in higher priority lanes are started first.
+type actionScheduler struct {
+	sync.Mutex
+	limits     model.ConcurrencyLimits
+	loadLimits func() (*model.ConcurrencyLimits, error)
+	// limitsLoadedAt is zero if the limits are never loaded successfully.
+	limitsLoadedAt time.Time
+	waiting        map[string][]*Slot
+	running        map[*Slot]struct{}
+}
+
+var scheduler = newActionScheduler()
+
+func newActionScheduler() *actionScheduler {
+	return &actionScheduler{
+		loadLimits: func() (*model.ConcurrencyLimits, error) {
+			return model.GetStorage().GetConcurrencyLimits()
+		},
+		waiting: map[string][]*Slot{},
+		running: map[*Slot]struct{}{},
+	}
+}
+
+// acquire blocks until the action is allowed to run, the returned function
+// must be called after the action is done.
+func (s *actionScheduler) acquire(ctx context.Context, req SlotRequest) (func(), error) {
+	s.refreshLimits()
+
+	slot := &Slot{SlotRequest: req, EnqueuedAt: time.Now(), ready: make(chan struct{})}
+	s.Lock()
+	s.waiting[req.Lane] = append(s.waiting[req.Lane], slot)
+	s.dispatch()
+	s.Unlock()
+
+	select {
+	case <-slot.ready:
+		return func() { s.release(slot) }, nil
+	case <-ctx.Done():
+		s.Lock()
+		defer s.Unlock()
+		if slot.StartedAt != nil {
+			// started at the same time the context is done.
+			delete(s.running, slot)
+			s.dispatch()
+		} else {
+			s.removeWaiting(slot)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (s *actionScheduler) release(slot *Slot) {
+	s.Lock()
+	delete(s.running, slot)
+	s.dispatch()
+	s.Unlock()
+}
+
+// refreshLimits reloads the limits if they are loaded before the refresh
+// interval, so that the changes of system variables take effect.
+func (s *actionScheduler) refreshLimits() {
+	s.Lock()
+	fresh := !s.limitsLoadedAt.IsZero() && time.Since(s.limitsLoadedAt) < limitsRefreshInterval
+	s.Unlock()
+	if fresh {
+		return
+	}
+	limits, err := s.loadLimits()
+	if err != nil {
+		log.NewEntry().Errorf("get concurrency limits failed, keep the current limits, error: %v", err)
+		return
+	}
+	s.Lock()
+	s.limits = *limits
+	s.limitsLoadedAt = time.Now()
+	s.Unlock()
+}
+
+// dispatch starts the waiting actions which are allowed to run, it must be
+// called with lock held.
+func (s *actionScheduler) dispatch() {
+	for _, lane := range lanes {
+		waiting := s.waiting[lane][:0]
+		for _, slot := range s.waiting[lane] {
+			if !s.allowed(slot) {
+				waiting = append(waiting, slot)
+				continue
+			}
+			now := time.Now()
+			slot.StartedAt = &now
+			s.running[slot] = struct{}{}
+			close(slot.ready)
+		}
+		s.waiting[lane] = waiting
+	}
+}
+
+func (s *actionScheduler) allowed(slot *Slot) bool {
+	var audits, executions, ddlExecutions int
+	for running := range s.running {
+		switch running.Kind {
+		case SlotKindAudit:
+			audits++
+		case SlotKindExecute:
+			if running.InstanceId != slot.InstanceId {
+				continue
+			}
+			executions++
+			if running.DDL {
+				ddlExecutions++
+			}
+		}
+	}
+
+	switch slot.Kind {
+	case SlotKindAudit:
+		return s.limits.MaxAudits <= 0 || audits < s.limits.MaxAudits
+	case SlotKindExecute:
+		if s.limits.MaxExecutionsPerInstance > 0 && executions >= s.limits.MaxExecutionsPerInstance {
+			return false
+		}
+		if slot.DDL && s.limits.MaxDDLExecutionsPerInstance > 0 && ddlExecutions >= s.limits.MaxDDLExecutionsPerInstance {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *actionScheduler) removeWaiting(slot *Slot) {
+	waiting := s.waiting[slot.Lane]
+	for idx := range waiting {
+		if waiting[idx] == slot {
+			s.waiting[slot.Lane] = append(waiting[:idx], waiting[idx+1:]...)
+			return
+		}
+	}
+}
+
+type ActionQueueStatus struct {
+	Limits  model.ConcurrencyLimits
+	Running []Slot
+	// Waiting is in the order of being started.
+	Waiting []Slot
+}
+
+func (s *actionScheduler) status() *ActionQueueStatus {
+	s.Lock()
+	defer s.Unlock()
+	status := &ActionQueueStatus{Limits: s.limits}
+	for slot := range s.running {
+		status.Running = append(status.Running, *slot)
+	}
+	for _, lane := range lanes {
+		for _, slot := range s.waiting[lane] {
+			status.Waiting = append(status.Waiting, *slot)
+		}
+	}
+	return status
+}
+
+// GetActionQueueStatus returns the actions running or waiting on this node.
+func GetActionQueueStatus() *ActionQueueStatus {
+	return scheduler.status()
+}
+
+func (a *action) slotRequest() SlotRequest {
+	req := SlotRequest{
+		Lane:       LaneBatch,
+		Kind:       SlotKindExecute,
+		TaskId:     a.task.ID,
+		InstanceId: a.task.InstanceId,
+	}
+	switch a.typ {
+	case ActionTypeAudit:
+		req.Kind = SlotKindAudit
+	case ActionTypeExecute, ActionTypeRehearsal, ActionTypeResume:
+		for _, executeSQL := range a.task.ExecuteSQLs {
+			if a.isDDL(executeSQL) {
+				req.DDL = true
+				break
+			}
+		}
+	case ActionTypeRollback:
+		// the rollback SQL is DDL if the SQL it rolls back is DDL.
+		rolledBack := map[uint]struct{}{}
+		for _, rollbackSQL := range a.task.RollbackSQLs {
+			rolledBack[rollbackSQL.ExecuteSQLId] = struct{}{}
+		}
+		for _, executeSQL := range a.task.ExecuteSQLs {
+			if _, ok := rolledBack[executeSQL.ID]; ok && a.isDDL(executeSQL) {
+				req.DDL = true
+				break
+			}
+		}
+	}
+	return req
+}
+
+// isDDL uses the SQL type recorded when the task is created, the SQL is parsed
+// only if the type is not recorded, such as the tasks created before upgrade.
+func (a *action) isDDL(executeSQL *model.ExecuteSQL) bool {
+	if executeSQL.SQLType != "" {
+		return executeSQL.SQLType == driverV2.SQLTypeDDL
+	}
+	if a.plugin == nil {
+		return false
+	}
+	nodes, err := a.plugin.Parse(context.TODO(), executeSQL.Content)
+	if err != nil {
+		return false
+	}
+	for _, node := range nodes {
+		if node.Type == driverV2.SQLTypeDDL {
+			return true
+		}
+	}
+	return false
+}
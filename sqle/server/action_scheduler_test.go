@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestActionScheduler(limits model.ConcurrencyLimits) *actionScheduler {
+	s := newActionScheduler()
+	s.loadLimits = func() (*model.ConcurrencyLimits, error) {
+		return &limits, nil
+	}
+	return s
+}
+
+// enqueue adds a waiting slot without blocking.
+func (s *actionScheduler) enqueue(req SlotRequest) *Slot {
+	slot := &Slot{SlotRequest: req, EnqueuedAt: time.Now(), ready: make(chan struct{})}
+	s.Lock()
+	s.waiting[req.Lane] = append(s.waiting[req.Lane], slot)
+	s.dispatch()
+	s.Unlock()
+	return slot
+}
+
+func isStarted(slot *Slot) bool {
+	select {
+	case <-slot.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestActionScheduler_Priority(t *testing.T) {
+	s := newTestActionScheduler(model.ConcurrencyLimits{MaxAudits: 1})
+	s.refreshLimits()
+
+	running := s.enqueue(SlotRequest{Lane: LaneBatch, Kind: SlotKindAudit, TaskId: 1})
+	batch := s.enqueue(SlotRequest{Lane: LaneBatch, Kind: SlotKindAudit, TaskId: 2})
+	interactive := s.enqueue(SlotRequest{Lane: LaneInteractive, Kind: SlotKindAudit})
+	assert.True(t, isStarted(running))
+	assert.False(t, isStarted(batch))
+	assert.False(t, isStarted(interactive))
+
+	status := s.status()
+	assert.Len(t, status.Running, 1)
+	assert.Len(t, status.Waiting, 2)
+	assert.Equal(t, LaneInteractive, status.Waiting[0].Lane)
+
+	// the interactive audit is started first though it is enqueued later.
+	s.release(running)
+	assert.True(t, isStarted(interactive))
+	assert.False(t, isStarted(batch))
+
+	s.release(interactive)
+	assert.True(t, isStarted(batch))
+}
+
+func TestActionScheduler_ExecutionLimits(t *testing.T) {
+	s := newTestActionScheduler(model.ConcurrencyLimits{MaxExecutionsPerInstance: 2, MaxDDLExecutionsPerInstance: 1})
+	s.refreshLimits()
+
+	ddl1 := s.enqueue(SlotRequest{Lane: LaneBatch, Kind: SlotKindExecute, TaskId: 1, InstanceId: 1, DDL: true})
+	ddl2 := s.enqueue(SlotRequest{Lane: LaneBatch, Kind: SlotKindExecute, TaskId: 2, InstanceId: 1, DDL: true})
+	dml1 := s.enqueue(SlotRequest{Lane: LaneBatch, Kind: SlotKindExecute, TaskId: 3, InstanceId: 1})
+	dml2 := s.enqueue(SlotRequest{Lane: LaneBatch, Kind: SlotKindExecute, TaskId: 4, InstanceId: 1})
+	otherInstance := s.enqueue(SlotRequest{Lane: LaneBatch, Kind: SlotKindExecute, TaskId: 5, InstanceId: 2, DDL: true})
+	audit := s.enqueue(SlotRequest{Lane: LaneBatch, Kind: SlotKindAudit, TaskId: 6, InstanceId: 1})
+
+	assert.True(t, isStarted(ddl1))
+	assert.False(t, isStarted(ddl2))
+	assert.True(t, isStarted(dml1))
+	assert.False(t, isStarted(dml2))
+	assert.True(t, isStarted(otherInstance))
+	assert.True(t, isStarted(audit))
+
+	s.release(ddl1)
+	assert.True(t, isStarted(ddl2))
+	assert.False(t, isStarted(dml2))
+
+	s.release(dml1)
+	assert.True(t, isStarted(dml2))
+}
+
+func TestActionScheduler_AcquireCanceled(t *testing.T) {
+	s := newTestActionScheduler(model.ConcurrencyLimits{MaxAudits: 1})
+
+	release, err := s.acquire(context.Background(), SlotRequest{Lane: LaneBatch, Kind: SlotKindAudit})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = s.acquire(ctx, SlotRequest{Lane: LaneInteractive, Kind: SlotKindAudit})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Len(t, s.status().Waiting, 0)
+
+	release()
+	assert.Len(t, s.status().Running, 0)
+}
+
+func TestAction_slotRequest(t *testing.T) {
+	task := &model.Task{
+		Model:      model.Model{ID: 1},
+		InstanceId: 2,
+		ExecuteSQLs: []*model.ExecuteSQL{
+			{BaseSQL: model.BaseSQL{Model: model.Model{ID: 1}, SQLType: driverV2.SQLTypeDML}},
+			{BaseSQL: model.BaseSQL{Model: model.Model{ID: 2}, SQLType: driverV2.SQLTypeDDL}},
+		},
+		RollbackSQLs: []*model.RollbackSQL{{ExecuteSQLId: 1}},
+	}
+
+	req := (&action{task: task, typ: ActionTypeExecute}).slotRequest()
+	assert.Equal(t, SlotRequest{Lane: LaneBatch, Kind: SlotKindExecute, TaskId: 1, InstanceId: 2, DDL: true}, req)
+
+	// only the DML is rolled back.
+	req = (&action{task: task, typ: ActionTypeRollback}).slotRequest()
+	assert.False(t, req.DDL)
+
+	req = (&action{task: task, typ: ActionTypeAudit}).slotRequest()
+	assert.Equal(t, SlotKindAudit, req.Kind)
+}
+
+func TestActionScheduler_RefreshLimits(t *testing.T) {
+	loads := 0
+	s := newActionScheduler()
+	s.loadLimits = func() (*model.ConcurrencyLimits, error) {
+		loads++
+		return &model.ConcurrencyLimits{MaxAudits: loads}, nil
+	}
+
+	s.refreshLimits()
+	s.refreshLimits()
+	assert.Equal(t, 1, loads)
+	assert.Equal(t, 1, s.status().Limits.MaxAudits)
+
+	// the limits are reloaded after the refresh interval.
+	s.limitsLoadedAt = time.Now().Add(-limitsRefreshInterval)
+	s.refreshLimits()
+	assert.Equal(t, 2, loads)
+	assert.Equal(t, 2, s.status().Limits.MaxAudits)
+}
@@ -22,6 +22,12 @@ func Audit(l *logrus.Entry, task *model.Task, projectId *model.ProjectUID, ruleT
 }
 
 func HookAudit(l *logrus.Entry, task *model.Task, hook AuditHook, projectId *model.ProjectUID, ruleTemplateName string) (err error) {
+	release, err := scheduler.acquire(context.TODO(), SlotRequest{Lane: LaneBatch, Kind: SlotKindAudit, TaskId: task.ID})
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	st := model.GetStorage()
 	rules, customRules, err := st.GetAllRulesByTmpNameAndProjectIdInstanceDBType(ruleTemplateName, string(*projectId), task.Instance, task.DBType)
 	if err != nil {
@@ -43,7 +49,13 @@ func HookAudit(l *logrus.Entry, task *model.Task, hook AuditHook, projectId *mod
 
 const AuditSchema = "AuditSchema"
 
-func DirectAuditByInstance(l *logrus.Entry, sql, schemaName string, instance *model.Instance) (*model.Task, error) {
+func DirectAuditByInstance(ctx context.Context, l *logrus.Entry, sql, schemaName string, instance *model.Instance) (*model.Task, error) {
+	release, err := scheduler.acquire(ctx, SlotRequest{Lane: LaneInteractive, Kind: SlotKindAudit, InstanceId: instance.ID})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	st := model.GetStorage()
 	rules, customRules, err := st.GetAllRulesByTmpNameAndProjectIdInstanceDBType("", "", instance, instance.DbType)
 	if err != nil {
@@ -60,11 +72,18 @@ func DirectAuditByInstance(l *logrus.Entry, sql, schemaName string, instance *mo
 		return nil, err
 	}
 	task.Instance = instance
+	task.DBType = instance.DbType
 
 	return task, audit(l, task, plugin, customRules)
 }
 
-func AuditSQLByDBType(l *logrus.Entry, sql string, dbType string, projectId string, ruleTemplateName string) (*model.Task, error) {
+func AuditSQLByDBType(ctx context.Context, l *logrus.Entry, sql string, dbType string, projectId string, ruleTemplateName string) (*model.Task, error) {
+	release, err := scheduler.acquire(ctx, SlotRequest{Lane: LaneInteractive, Kind: SlotKindAudit})
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	st := model.GetStorage()
 	rules, customRules, err := st.GetAllRulesByTmpNameAndProjectIdInstanceDBType(ruleTemplateName, projectId, nil, dbType)
 	if err != nil {
@@ -76,7 +95,17 @@ func AuditSQLByDBType(l *logrus.Entry, sql string, dbType string, projectId stri
 	}
 	defer plugin.Close(context.TODO())
 
-	return AuditSQLByDriver(l, sql, plugin, customRules)
+	task, err := convertSQLsToTask(sql, plugin)
+	if err != nil {
+		return nil, err
+	}
+	task.DBType = dbType
+	// the task is not saved, the instance only carries the project of the
+	// whitelist and the audit suppression setting.
+	if projectId != "" {
+		task.Instance = &model.Instance{ProjectId: projectId}
+	}
+	return task, audit(l, task, plugin, customRules)
 }
 
 func AuditSQLByDriver(l *logrus.Entry, sql string, p driver.Plugin, customRules []*model.CustomRule) (*model.Task, error) {
@@ -96,8 +125,10 @@ func convertSQLsToTask(sql string, p driver.Plugin) (*model.Task, error) {
 	for n, node := range nodes {
 		task.ExecuteSQLs = append(task.ExecuteSQLs, &model.ExecuteSQL{
 			BaseSQL: model.BaseSQL{
-				Number:  uint(n + 1),
-				Content: node.Text,
+				Number:    uint(n + 1),
+				Content:   node.Text,
+				StartLine: node.StartLine,
+				SQLType:   node.Type,
 			},
 		})
 	}
@@ -139,10 +170,12 @@ func hookAudit(l *logrus.Entry, task *model.Task, p driver.Plugin, hook AuditHoo
 	if err != nil {
 		return err
 	}
+	matcher := newWhitelistMatcher(l, task, p, whitelist)
 
 	auditSqls := []*model.ExecuteSQL{}
 	sqls := []string{}
 	nodes := []driverV2.Node{}
+	skippedRulesList := []map[string]struct{}{}
 	for _, executeSQL := range task.ExecuteSQLs {
 		// We always trust the ExecuteSQL.Content is single SQL.
 		//
@@ -157,23 +190,8 @@ func hookAudit(l *logrus.Entry, task *model.Task, p driver.Plugin, hook AuditHoo
 		if err != nil {
 			return err
 		}
-		var whitelistMatch bool
-		for _, wl := range whitelist {
-			if wl.MatchType == model.SQLWhitelistFPMatch {
-				wlNode, err := parse(l, p, wl.Value)
-				if err != nil {
-					l.Errorf("parse whitelist sql error: %v,please check the accuracy of whitelist SQL: %s", err, wl.Value)
-				}
-				if node.Fingerprint == wlNode.Fingerprint {
-					whitelistMatch = true
-				}
-			} else {
-				if wl.CapitalizedValue == strings.ToUpper(node.Text) {
-					whitelistMatch = true
-				}
-			}
-		}
-		if whitelistMatch {
+		skipAll, skippedRules := matcher.match(node)
+		if skipAll {
 			result := driverV2.NewAuditResults()
 			result.Add(driverV2.RuleLevelNormal, "", "白名单")
 			executeSQL.AuditStatus = model.SQLAuditStatusFinished
@@ -184,13 +202,21 @@ func hookAudit(l *logrus.Entry, task *model.Task, p driver.Plugin, hook AuditHoo
 			auditSqls = append(auditSqls, executeSQL)
 			sqls = append(sqls, executeSQL.Content)
 			nodes = append(nodes, node)
+			skippedRulesList = append(skippedRulesList, skippedRules)
 		}
 	}
+	matcher.saveMatchedCounts()
 	for _, sql := range auditSqls {
 		hook.BeforeAudit(sql)
 	}
 
-	results, err := p.Audit(context.TODO(), sqls)
+	ctx := context.TODO()
+	if task.ID != 0 {
+		var done func()
+		ctx, done = newAuditProgressContext(task.ID, len(sqls))
+		defer done()
+	}
+	results, err := p.Audit(ctx, sqls)
 	if err != nil {
 		return err
 	}
@@ -198,12 +224,19 @@ func hookAudit(l *logrus.Entry, task *model.Task, p driver.Plugin, hook AuditHoo
 		return fmt.Errorf("audit results [%d] does not match the number of SQL [%d]", len(results), len(sqls))
 	}
 	CustomRuleAudit(l, task, sqls, results, customRules)
+	suppressor := newAuditSuppressor(task)
 	for i, sql := range auditSqls {
+		skipRules(results[i], skippedRulesList[i])
+		suppressed, err := suppressor.suppress(sql.Content, results[i])
+		if err != nil {
+			return err
+		}
 		hook.AfterAudit(sql)
 		sql.AuditStatus = model.SQLAuditStatusFinished
 		sql.AuditLevel = string(results[i].Level())
 		sql.AuditFingerprint = utils.Md5String(string(append([]byte(results[i].Message()), []byte(nodes[i].Fingerprint)...)))
 		appendExecuteSqlResults(sql, results[i])
+		sql.AuditResults = append(sql.AuditResults, suppressed...)
 	}
 
 	ReplenishTaskStatistics(task)
@@ -331,8 +364,19 @@ func genRollbackSQL(l *logrus.Entry, task *model.Task, p driver.Plugin) ([]*mode
 		result := driverV2.NewAuditResults()
 		for i := range executeSQL.AuditResults {
 			ar := executeSQL.AuditResults[i]
-			result.Add(driverV2.RuleLevel(ar.Level), ar.RuleName, ar.Message)
+			if ar.Suppressed {
+				continue
+			}
+			result.Results = append(result.Results, &driverV2.AuditResult{
+				Level:        driverV2.RuleLevel(ar.Level),
+				Message:      ar.Message,
+				RuleName:     ar.RuleName,
+				MessageId:    ar.MessageId,
+				MessageArgs:  ar.MessageArgs,
+				I18nMessages: ar.I18nMessages,
+			})
 		}
+		result.SortByLevel()
 		result.Add(driverV2.RuleLevelNotice, "", reason)
 
 		executeSQL.AuditLevel = string(result.Level())
@@ -352,6 +396,17 @@ func genRollbackSQL(l *logrus.Entry, task *model.Task, p driver.Plugin) ([]*mode
 func appendExecuteSqlResults(executeSQL *model.ExecuteSQL, result *driverV2.AuditResults) {
 	for i := range result.Results {
 		ar := result.Results[i]
-		executeSQL.AuditResults.Append(string(ar.Level), ar.RuleName, ar.Message)
+		executeSQL.AuditResults.AppendResult(convertAuditResultToModel(ar))
+	}
+}
+
+func convertAuditResultToModel(ar *driverV2.AuditResult) model.AuditResult {
+	return model.AuditResult{
+		Level:        string(ar.Level),
+		RuleName:     ar.RuleName,
+		Message:      ar.Message,
+		MessageId:    ar.MessageId,
+		MessageArgs:  ar.MessageArgs,
+		I18nMessages: ar.I18nMessages,
 	}
 }
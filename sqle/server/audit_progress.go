@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/actiontech/sqle/sqle/driver"
+)
+
+type AuditProgress struct {
+	TotalSQLCount   int
+	AuditedSQLCount int
+}
+
+var auditingTasks = struct {
+	sync.Mutex
+	progress map[uint]*AuditProgress
+}{progress: map[uint]*AuditProgress{}}
+
+// newAuditProgressContext returns the context for auditing the SQLs of task, the
+// progress is updated when the plugin audits the SQLs in chunks. The returned
+// function should be called after the audit is finished.
+func newAuditProgressContext(taskId uint, total int) (context.Context, func()) {
+	auditingTasks.Lock()
+	auditingTasks.progress[taskId] = &AuditProgress{TotalSQLCount: total}
+	auditingTasks.Unlock()
+
+	ctx := driver.NewAuditProgressContext(context.TODO(), func(audited, total int) {
+		updateAuditProgress(taskId, audited, total)
+	})
+	return ctx, func() {
+		auditingTasks.Lock()
+		delete(auditingTasks.progress, taskId)
+		auditingTasks.Unlock()
+	}
+}
+
+func updateAuditProgress(taskId uint, audited, total int) {
+	auditingTasks.Lock()
+	defer auditingTasks.Unlock()
+	if p, ok := auditingTasks.progress[taskId]; ok {
+		p.AuditedSQLCount = audited
+		p.TotalSQLCount = total
+	}
+}
+
+// GetAuditProgress returns the progress of task which is being audited, the
+// progress is only visible in the SQLE which audits the task.
+func GetAuditProgress(taskId uint) (*AuditProgress, bool) {
+	auditingTasks.Lock()
+	defer auditingTasks.Unlock()
+	p, ok := auditingTasks.progress[taskId]
+	if !ok {
+		return nil, false
+	}
+	return &AuditProgress{TotalSQLCount: p.TotalSQLCount, AuditedSQLCount: p.AuditedSQLCount}, true
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditProgress(t *testing.T) {
+	_, ok := GetAuditProgress(1)
+	assert.False(t, ok)
+
+	_, done := newAuditProgressContext(1, 1000)
+	p, ok := GetAuditProgress(1)
+	assert.True(t, ok)
+	assert.Equal(t, &AuditProgress{TotalSQLCount: 1000}, p)
+
+	updateAuditProgress(1, 500, 1000)
+	p, _ = GetAuditProgress(1)
+	assert.Equal(t, &AuditProgress{TotalSQLCount: 1000, AuditedSQLCount: 500}, p)
+
+	done()
+	_, ok = GetAuditProgress(1)
+	assert.False(t, ok)
+}
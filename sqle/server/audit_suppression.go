@@ -0,0 +1,140 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+// auditSuppressor honours the "sqle:ignore" annotations in SQL comments,
+// the project setting is loaded lazily since most SQLs have no annotation.
+type auditSuppressor struct {
+	projectId model.ProjectUID
+	setting   *model.AuditSuppressionSetting
+}
+
+func newAuditSuppressor(task *model.Task) *auditSuppressor {
+	dbType := task.DBType
+	if dbType == "" && task.Instance != nil {
+		dbType = task.Instance.DbType
+	}
+	// only the comments of MySQL are parsed for now.
+	if dbType != driverV2.DriverTypeMySQL {
+		return nil
+	}
+	projectId := ""
+	if task.Instance != nil {
+		projectId = task.Instance.ProjectId
+	}
+	return &auditSuppressor{projectId: model.ProjectUID(projectId)}
+}
+
+// suppress removes the audit results ignored by the annotations of sql from
+// results, and returns them as the suppressed audit results.
+func (s *auditSuppressor) suppress(sql string, results *driverV2.AuditResults) (model.AuditResults, error) {
+	if s == nil {
+		return nil, nil
+	}
+	suppressions := util.ParseSuppressions(sql)
+	if len(suppressions) == 0 {
+		return nil, nil
+	}
+	if s.setting == nil {
+		setting, err := model.GetStorage().GetAuditSuppressionSettingByProjectId(s.projectId)
+		if err != nil {
+			return nil, err
+		}
+		s.setting = setting
+	}
+	return suppressAuditResults(s.setting, suppressions, results), nil
+}
+
+func suppressAuditResults(setting *model.AuditSuppressionSetting, suppressions []*util.Suppression,
+	results *driverV2.AuditResults) model.AuditResults {
+	// rule name -> reason, the reason is empty if no annotation gives it.
+	reasons := map[string]string{}
+	for _, suppression := range suppressions {
+		for _, ruleName := range suppression.RuleNames {
+			if reasons[ruleName] == "" {
+				reasons[ruleName] = suppression.Reason
+			}
+		}
+	}
+
+	suppressed := model.AuditResults{}
+	kept := make([]*driverV2.AuditResult, 0, len(results.Results))
+	for _, result := range results.Results {
+		reason, ok := reasons[result.RuleName]
+		switch {
+		case !ok || result.RuleName == "":
+			kept = append(kept, result)
+		case reason == "":
+			result.Message = fmt.Sprintf("%s（忽略注释缺少 reason，未生效）", result.Message)
+			clearAuditResultMessageId(result)
+			kept = append(kept, result)
+		case !setting.CanSuppress(string(result.Level)):
+			result.Message = fmt.Sprintf("%s（项目不允许忽略 %s 级别的审核结果）", result.Message, result.Level)
+			clearAuditResultMessageId(result)
+			kept = append(kept, result)
+		default:
+			ar := convertAuditResultToModel(result)
+			ar.Suppressed = true
+			ar.SuppressReason = reason
+			suppressed = append(suppressed, ar)
+		}
+	}
+	results.Results = kept
+	return suppressed
+}
+
+// clearAuditResultMessageId is called after the message is changed, the result
+// can not be rendered in other languages by the message id any more.
+func clearAuditResultMessageId(result *driverV2.AuditResult) {
+	result.MessageId = ""
+	result.MessageArgs = nil
+	result.I18nMessages = nil
+}
+
+// GetUnacknowledgedSuppressions returns the SQLs of workflow whose suppressed
+// audit results must be acknowledged by approver, it is empty if the project
+// does not require acknowledgement.
+func GetUnacknowledgedSuppressions(workflow *model.Workflow) ([]*model.ExecuteSQL, error) {
+	st := model.GetStorage()
+	setting, err := st.GetAuditSuppressionSettingByProjectId(workflow.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	if !setting.NeedAcknowledgement {
+		return nil, nil
+	}
+	taskIds := make([]uint, 0, len(workflow.Record.InstanceRecords))
+	for _, record := range workflow.Record.InstanceRecords {
+		taskIds = append(taskIds, record.TaskId)
+	}
+	executeSQLs, err := st.GetSuppressedExecuteSQLsByTaskIds(taskIds)
+	if err != nil {
+		return nil, err
+	}
+	unacknowledged := []*model.ExecuteSQL{}
+	for _, executeSQL := range executeSQLs {
+		for _, result := range executeSQL.AuditResults {
+			if result.Suppressed && result.AcknowledgedBy == "" {
+				unacknowledged = append(unacknowledged, executeSQL)
+				break
+			}
+		}
+	}
+	return unacknowledged, nil
+}
+
+func acknowledgeSuppressions(executeSQLs []*model.ExecuteSQL, userId string) {
+	for _, executeSQL := range executeSQLs {
+		for i := range executeSQL.AuditResults {
+			if executeSQL.AuditResults[i].Suppressed && executeSQL.AuditResults[i].AcknowledgedBy == "" {
+				executeSQL.AuditResults[i].AcknowledgedBy = userId
+			}
+		}
+	}
+}
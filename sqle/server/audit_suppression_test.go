@@ -0,0 +1,32 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_suppressAuditResults(t *testing.T) {
+	setting := model.NewDefaultAuditSuppressionSetting("1")
+	results := driverV2.NewAuditResults()
+	results.Add(driverV2.RuleLevelWarn, "rule_a", "message a")
+	results.Add(driverV2.RuleLevelError, "rule_b", "message b")
+	results.Add(driverV2.RuleLevelNotice, "rule_c", "message c")
+	results.Add(driverV2.RuleLevelNotice, "rule_d", "message d")
+
+	suppressed := suppressAuditResults(setting, util.ParseSuppressions(
+		`SELECT 1 /* sqle:ignore rule_a,rule_b reason="checked" */ /* sqle:ignore rule_c */`), results)
+
+	assert.Equal(t, model.AuditResults{
+		{Level: "warn", RuleName: "rule_a", Message: "message a", Suppressed: true, SuppressReason: "checked"},
+	}, suppressed)
+	assert.Len(t, results.Results, 3)
+	assert.Equal(t, "message b（项目不允许忽略 error 级别的审核结果）", results.Results[0].Message)
+	assert.Equal(t, "message c（忽略注释缺少 reason，未生效）", results.Results[1].Message)
+	assert.Equal(t, "message d", results.Results[2].Message)
+	assert.Equal(t, driverV2.RuleLevelError, results.Level())
+}
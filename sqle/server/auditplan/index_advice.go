@@ -0,0 +1,95 @@
+package auditplan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/dms"
+	"github.com/actiontech/sqle/sqle/driver/mysql"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/sirupsen/logrus"
+)
+
+var errIndexAdviceNotSupported = errors.New(errors.DataInvalid, fmt.Errorf("index advice only supports audit plan of MySQL instance"))
+
+// workloadSQLWeight 使用执行次数与平均执行时间的乘积作为SQL在负载中的权重，缺少执行时间时使用执行次数
+func workloadSQLWeight(info model.JSON) float64 {
+	var i struct {
+		Counter      float64 `json:"counter"`
+		QueryTimeAvg float64 `json:"query_time_avg"`
+	}
+	if len(info) == 0 || json.Unmarshal(info, &i) != nil {
+		return 1
+	}
+	switch {
+	case i.Counter > 0 && i.QueryTimeAvg > 0:
+		return i.Counter * i.QueryTimeAvg
+	case i.Counter > 0:
+		return i.Counter
+	default:
+		return 1
+	}
+}
+
+// AdviseWorkloadIndexes 汇总扫描任务中所有SQL指纹的候选索引，给出每张表的索引建议
+func AdviseWorkloadIndexes(entry *logrus.Entry, ap *model.AuditPlan, maxIndexPerTable int) ([]*mysql.WorkloadTableIndexAdvice, error) {
+	if ap.DBType != driverV2.DriverTypeMySQL || ap.InstanceName == "" {
+		return nil, errIndexAdviceNotSupported
+	}
+
+	sqls, err := model.GetStorage().GetAuditPlanSQLs(ap.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sqls) == 0 {
+		return nil, errNoSQLInAuditPlan
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+	defer cancel()
+	instance, exist, err := dms.GetInstanceInProjectByName(ctx, string(ap.ProjectId), ap.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.NewInstanceNoExistErr()
+	}
+
+	// explain 在连接的库上执行，因此按SQL所在的库分组
+	schemaSQLs := map[string][]*mysql.WorkloadSQL{}
+	for _, sql := range sqls {
+		schema := sql.Schema
+		if schema == "" {
+			schema = ap.InstanceDatabase
+		}
+		schemaSQLs[schema] = append(schemaSQLs[schema], &mysql.WorkloadSQL{
+			Fingerprint: sql.Fingerprint,
+			SQL:         sql.SQLContent,
+			Weight:      workloadSQLWeight(sql.Info),
+		})
+	}
+
+	advisor := mysql.NewWorkloadIndexAdvisor(maxIndexPerTable)
+	for schema, workload := range schemaSQLs {
+		inspect, err := mysql.NewInspect(entry, &driverV2.Config{
+			DSN: &driverV2.DSN{
+				Host:             instance.Host,
+				Port:             instance.Port,
+				User:             instance.User,
+				Password:         instance.Password,
+				AdditionalParams: instance.AdditionalParams,
+				DatabaseName:     schema,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		advisor.Collect(inspect, workload)
+		inspect.Close(ctx)
+	}
+	return advisor.Advise(), nil
+}
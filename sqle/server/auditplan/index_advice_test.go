@@ -0,0 +1,16 @@
+package auditplan
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloadSQLWeight(t *testing.T) {
+	assert.Equal(t, float64(20), workloadSQLWeight(model.JSON(`{"counter":10,"query_time_avg":2}`)))
+	assert.Equal(t, float64(10), workloadSQLWeight(model.JSON(`{"counter":10,"last_receive_timestamp":"2023-01-01T00:00:00Z"}`)))
+	assert.Equal(t, float64(1), workloadSQLWeight(model.JSON(`{"schema":"db1"}`)))
+	assert.Equal(t, float64(1), workloadSQLWeight(model.JSON(`{"counter":"10"}`)))
+	assert.Equal(t, float64(1), workloadSQLWeight(nil))
+}
@@ -47,6 +47,12 @@ func audit(auditPlanId uint, task Task) (*model.AuditPlanReportV2, error) {
 		return nil, err
 	}
 
+	if _, ok := task.(*SchemaMetaTask); ok {
+		if err := s.AttachSchemaDriftsToReport(auditPlanId, auditPlanReport.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	go func() {
 		syncFromAuditPlan := NewSyncFromAuditPlan(auditPlanReport, auditResultResp.FilteredSqls, taskResp)
 		if err := syncFromAuditPlan.SyncSqlManager(); err != nil {
@@ -32,6 +32,7 @@ const (
 	TypeAllAppExtract         = "all_app_extract"
 	TypeBaiduRdsMySQLSlowLog  = "baidu_rds_mysql_slow_log"
 	TypeSQLFile               = "sql_file"
+	TypeJava                  = "java"
 )
 
 const (
@@ -371,6 +372,12 @@ var Metas = []Meta{
 		InstanceType: InstanceTypeAll,
 		CreateTask:   NewDefaultTask,
 	},
+	{
+		Type:         TypeJava,
+		Desc:         "Java 扫描",
+		InstanceType: InstanceTypeAll,
+		CreateTask:   NewJavaTask,
+	},
 }
 
 var MetaMap = map[string]Meta{}
@@ -0,0 +1,264 @@
+package auditplan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+)
+
+// saveSchemaSnapshot compares the collected tables with the latest snapshot,
+// a new version is saved with the drifts only when the structures changed.
+// collectedAt is the time before the tables are collected.
+func (at *SchemaMetaTask) saveSchemaSnapshot(instance *model.Instance, tables map[string] /*table name*/ string, collectedAt time.Time) error {
+	content, err := json.Marshal(tables)
+	if err != nil {
+		return err
+	}
+	latest, exist, err := at.persist.GetLatestSchemaSnapshot(at.ap.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return at.persist.CreateSchemaSnapshot(&model.SchemaSnapshot{
+			AuditPlanId: at.ap.ID,
+			Version:     1,
+			Schema:      at.ap.InstanceDatabase,
+			Tables:      content,
+			TableCount:  len(tables),
+			CollectedAt: &collectedAt,
+		})
+	}
+
+	before := map[string]string{}
+	if err := json.Unmarshal(latest.Tables, &before); err != nil {
+		return fmt.Errorf("parse tables of schema snapshot %v failed: %v", latest.Version, err)
+	}
+	drifts := diffSchemaTables(before, tables)
+	if len(drifts) == 0 {
+		return at.persist.TouchSchemaSnapshot(latest, collectedAt)
+	}
+
+	// the tables may be changed by the tasks executed after the previous
+	// collection started, include them though the change may be collected.
+	tasks, err := at.persist.GetExecutedTasksByInstance(instance.ID, latest.CollectionStartedAt(), time.Now())
+	if err != nil {
+		return err
+	}
+	markOutOfBandDrifts(drifts, tasks, at.ap.InstanceDatabase)
+	for _, drift := range drifts {
+		drift.AuditPlanId = at.ap.ID
+		if drift.OutOfBand {
+			at.logger.Warnf("table %v was changed out of workflow, %v %v %v", drift.TableName, drift.ChangeType, drift.ObjectType, drift.ObjectName)
+		}
+	}
+	return at.persist.CreateSchemaSnapshot(&model.SchemaSnapshot{
+		AuditPlanId: at.ap.ID,
+		Version:     latest.Version + 1,
+		Schema:      at.ap.InstanceDatabase,
+		Tables:      content,
+		TableCount:  len(tables),
+		CollectedAt: &collectedAt,
+		Drifts:      drifts,
+	})
+}
+
+func diffSchemaTables(before, after map[string]string) []*model.SchemaDrift {
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	drifts := []*model.SchemaDrift{}
+	for _, name := range names {
+		beforeSQL, inBefore := before[name]
+		afterSQL, inAfter := after[name]
+		switch {
+		case !inBefore:
+			drifts = append(drifts, newSchemaDrift(name, model.SchemaDriftObjectTypeTable, name, model.SchemaDriftChangeTypeCreated, "", afterSQL))
+		case !inAfter:
+			drifts = append(drifts, newSchemaDrift(name, model.SchemaDriftObjectTypeTable, name, model.SchemaDriftChangeTypeDropped, beforeSQL, ""))
+		case beforeSQL != afterSQL:
+			drifts = append(drifts, diffTable(name, beforeSQL, afterSQL)...)
+		}
+	}
+	return drifts
+}
+
+func newSchemaDrift(table, objectType, objectName, changeType, before, after string) *model.SchemaDrift {
+	return &model.SchemaDrift{
+		TableName:  table,
+		ObjectType: objectType,
+		ObjectName: objectName,
+		ChangeType: changeType,
+		Before:     before,
+		After:      after,
+	}
+}
+
+type schemaObject struct {
+	name       string
+	definition string
+}
+
+// diffObjects compares the objects by name, the order follows the objects after changed.
+func diffObjects(table, objectType string, before, after []schemaObject) []*model.SchemaDrift {
+	drifts := []*model.SchemaDrift{}
+	beforeMap := make(map[string]string, len(before))
+	for _, o := range before {
+		beforeMap[strings.ToLower(o.name)] = o.definition
+	}
+	afterMap := make(map[string]struct{}, len(after))
+	for _, o := range after {
+		key := strings.ToLower(o.name)
+		afterMap[key] = struct{}{}
+		definition, ok := beforeMap[key]
+		if !ok {
+			drifts = append(drifts, newSchemaDrift(table, objectType, o.name, model.SchemaDriftChangeTypeCreated, "", o.definition))
+		} else if definition != o.definition {
+			drifts = append(drifts, newSchemaDrift(table, objectType, o.name, model.SchemaDriftChangeTypeModified, definition, o.definition))
+		}
+	}
+	for _, o := range before {
+		if _, ok := afterMap[strings.ToLower(o.name)]; !ok {
+			drifts = append(drifts, newSchemaDrift(table, objectType, o.name, model.SchemaDriftChangeTypeDropped, o.definition, ""))
+		}
+	}
+	return drifts
+}
+
+func diffTable(table, beforeSQL, afterSQL string) []*model.SchemaDrift {
+	beforeStmt, err := util.ParseCreateTableStmt(beforeSQL)
+	if err != nil {
+		return []*model.SchemaDrift{newSchemaDrift(table, model.SchemaDriftObjectTypeTable, table, model.SchemaDriftChangeTypeModified, beforeSQL, afterSQL)}
+	}
+	afterStmt, err := util.ParseCreateTableStmt(afterSQL)
+	if err != nil {
+		return []*model.SchemaDrift{newSchemaDrift(table, model.SchemaDriftObjectTypeTable, table, model.SchemaDriftChangeTypeModified, beforeSQL, afterSQL)}
+	}
+
+	drifts := diffObjects(table, model.SchemaDriftObjectTypeColumn, tableColumns(beforeStmt), tableColumns(afterStmt))
+	drifts = append(drifts, diffObjects(table, model.SchemaDriftObjectTypeIndex, tableIndexes(beforeStmt), tableIndexes(afterStmt))...)
+	beforeOptions, afterOptions := tableOptions(beforeStmt), tableOptions(afterStmt)
+	if beforeOptions != afterOptions {
+		drifts = append(drifts, newSchemaDrift(table, model.SchemaDriftObjectTypeTable, table, model.SchemaDriftChangeTypeModified, beforeOptions, afterOptions))
+	}
+	return drifts
+}
+
+func tableColumns(stmt *ast.CreateTableStmt) []schemaObject {
+	objects := make([]schemaObject, 0, len(stmt.Cols))
+	for _, col := range stmt.Cols {
+		objects = append(objects, schemaObject{name: col.Name.Name.O, definition: restoreSchemaObject(col)})
+	}
+	return objects
+}
+
+func tableIndexes(stmt *ast.CreateTableStmt) []schemaObject {
+	objects := make([]schemaObject, 0, len(stmt.Constraints))
+	for _, constraint := range stmt.Constraints {
+		name := constraint.Name
+		if constraint.Tp == ast.ConstraintPrimaryKey {
+			name = "PRIMARY"
+		}
+		objects = append(objects, schemaObject{name: name, definition: restoreSchemaObject(constraint)})
+	}
+	return objects
+}
+
+// tableOptions ignores AUTO_INCREMENT which changes along with the data.
+func tableOptions(stmt *ast.CreateTableStmt) string {
+	options := make([]string, 0, len(stmt.Options))
+	for _, option := range stmt.Options {
+		if option.Tp == ast.TableOptionAutoIncrement {
+			continue
+		}
+		options = append(options, restoreSchemaObject(option))
+	}
+	return strings.Join(options, " ")
+}
+
+func restoreSchemaObject(node interface {
+	Restore(ctx *format.RestoreCtx) error
+}) string {
+	var buf strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &buf)); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// markOutOfBandDrifts links the drifts to the executed tasks which changed the
+// same table, the drifts not made by any task are out of band.
+func markOutOfBandDrifts(drifts []*model.SchemaDrift, tasks []*model.Task, schema string) {
+	changedBy := map[string] /*table name*/ uint /*task id*/ {}
+	for _, task := range tasks {
+		for _, sql := range task.ExecuteSQLs {
+			if sql.ExecStatus != model.SQLExecuteStatusSucceeded && sql.ExecStatus != model.SQLExecuteStatusManuallyExecuted {
+				continue
+			}
+			for _, table := range getDDLTables(sql.Content) {
+				tableSchema := table.Schema.O
+				if tableSchema == "" {
+					tableSchema = task.Schema
+				}
+				if !strings.EqualFold(tableSchema, schema) {
+					continue
+				}
+				changedBy[table.Name.L] = task.ID
+			}
+		}
+	}
+	for _, drift := range drifts {
+		if taskId, ok := changedBy[strings.ToLower(drift.TableName)]; ok {
+			drift.TaskId = taskId
+			continue
+		}
+		drift.OutOfBand = true
+	}
+}
+
+// getDDLTables returns the tables whose structures are changed by the SQL.
+func getDDLTables(sql string) []*ast.TableName {
+	stmts, err := util.ParseSql(sql)
+	if err != nil {
+		return nil
+	}
+	tables := []*ast.TableName{}
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.CreateTableStmt:
+			tables = append(tables, s.Table)
+		case *ast.AlterTableStmt:
+			tables = append(tables, s.Table)
+			for _, spec := range s.Specs {
+				if spec.Tp == ast.AlterTableRenameTable && spec.NewTable != nil {
+					tables = append(tables, spec.NewTable)
+				}
+			}
+		case *ast.DropTableStmt:
+			tables = append(tables, s.Tables...)
+		case *ast.RenameTableStmt:
+			for _, t := range s.TableToTables {
+				tables = append(tables, t.OldTable, t.NewTable)
+			}
+		case *ast.CreateIndexStmt:
+			tables = append(tables, s.Table)
+		case *ast.DropIndexStmt:
+			tables = append(tables, s.Table)
+		}
+	}
+	return tables
+}
@@ -0,0 +1,72 @@
+package auditplan
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSchemaTables(t *testing.T) {
+	before := map[string]string{
+		"t1": "CREATE TABLE `t1` (\n  `id` int NOT NULL AUTO_INCREMENT,\n  `c1` varchar(10) DEFAULT NULL,\n  `c2` int DEFAULT NULL,\n  PRIMARY KEY (`id`),\n  KEY `idx_c2` (`c2`)\n) ENGINE=InnoDB AUTO_INCREMENT=10 DEFAULT CHARSET=utf8mb4",
+		"t2": "CREATE TABLE `t2` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+		"t3": "CREATE TABLE `t3` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4",
+	}
+	after := map[string]string{
+		"t1": "CREATE TABLE `t1` (\n  `id` int NOT NULL AUTO_INCREMENT,\n  `c1` varchar(20) DEFAULT NULL,\n  `c3` int DEFAULT NULL,\n  PRIMARY KEY (`id`),\n  KEY `idx_c3` (`c3`)\n) ENGINE=InnoDB AUTO_INCREMENT=20 DEFAULT CHARSET=utf8mb4",
+		// only AUTO_INCREMENT changed
+		"t3": "CREATE TABLE `t3` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB AUTO_INCREMENT=100 DEFAULT CHARSET=utf8mb4",
+		"t4": "CREATE TABLE `t4` (\n  `id` int NOT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	}
+
+	drifts := diffSchemaTables(before, after)
+	type change struct{ table, objectType, objectName, changeType string }
+	changes := []change{}
+	for _, d := range drifts {
+		changes = append(changes, change{d.TableName, d.ObjectType, d.ObjectName, d.ChangeType})
+	}
+	assert.Equal(t, []change{
+		{"t1", model.SchemaDriftObjectTypeColumn, "c1", model.SchemaDriftChangeTypeModified},
+		{"t1", model.SchemaDriftObjectTypeColumn, "c3", model.SchemaDriftChangeTypeCreated},
+		{"t1", model.SchemaDriftObjectTypeColumn, "c2", model.SchemaDriftChangeTypeDropped},
+		{"t1", model.SchemaDriftObjectTypeIndex, "idx_c3", model.SchemaDriftChangeTypeCreated},
+		{"t1", model.SchemaDriftObjectTypeIndex, "idx_c2", model.SchemaDriftChangeTypeDropped},
+		{"t2", model.SchemaDriftObjectTypeTable, "t2", model.SchemaDriftChangeTypeDropped},
+		{"t4", model.SchemaDriftObjectTypeTable, "t4", model.SchemaDriftChangeTypeCreated},
+	}, changes)
+	assert.Contains(t, drifts[0].Before, "VARCHAR(10)")
+	assert.Contains(t, drifts[0].After, "VARCHAR(20)")
+}
+
+func TestMarkOutOfBandDrifts(t *testing.T) {
+	drifts := []*model.SchemaDrift{
+		{TableName: "t1"},
+		{TableName: "t2"},
+		{TableName: "t3"},
+		{TableName: "t4"},
+	}
+	newSQL := func(content, status string) *model.ExecuteSQL {
+		return &model.ExecuteSQL{BaseSQL: model.BaseSQL{Content: content, ExecStatus: status}}
+	}
+	tasks := []*model.Task{
+		{Model: model.Model{ID: 1}, Schema: "db1", ExecuteSQLs: []*model.ExecuteSQL{
+			newSQL("ALTER TABLE t1 ADD COLUMN c3 INT", model.SQLExecuteStatusSucceeded),
+			// failed SQL does not change the table
+			newSQL("ALTER TABLE t2 ADD COLUMN c3 INT", model.SQLExecuteStatusFailed),
+			// the table in other schema
+			newSQL("CREATE INDEX idx_c1 ON db2.t4(c1)", model.SQLExecuteStatusSucceeded),
+		}},
+		{Model: model.Model{ID: 2}, ExecuteSQLs: []*model.ExecuteSQL{
+			newSQL("RENAME TABLE db1.t0 TO db1.t3", model.SQLExecuteStatusManuallyExecuted),
+		}},
+	}
+	markOutOfBandDrifts(drifts, tasks, "db1")
+
+	assert.False(t, drifts[0].OutOfBand)
+	assert.Equal(t, uint(1), drifts[0].TaskId)
+	assert.True(t, drifts[1].OutOfBand)
+	assert.False(t, drifts[2].OutOfBand)
+	assert.Equal(t, uint(2), drifts[2].TaskId)
+	assert.True(t, drifts[3].OutOfBand)
+}
@@ -269,6 +269,86 @@ func (at *DefaultTask) Audit() (*AuditResultResp, error) {
 	return at.baseTask.audit(task)
 }
 
+// SlowLogTask stores the slow queries uploaded by the slowquery scanner of
+// scannerd, the query time of SQLs with the same fingerprint are merged.
+type SlowLogTask struct {
+	*DefaultTask
+}
+
+func NewSlowLogTask(entry *logrus.Entry, ap *model.AuditPlan) Task {
+	return &SlowLogTask{NewDefaultTask(entry, ap).(*DefaultTask)}
+}
+
+func (at *SlowLogTask) PartialSyncSQLs(sqls []*SQL) error {
+	return at.persist.UpdateSlowLogAuditPlanSQLs(at.ap.ID, convertSQLsToModelSQLs(sqls))
+}
+
+// JavaTask stores the SQLs uploaded by the java scanner of scannerd, the SQLs
+// carry the source file where they are extracted from.
+type JavaTask struct {
+	*DefaultTask
+}
+
+func NewJavaTask(entry *logrus.Entry, ap *model.AuditPlan) Task {
+	return &JavaTask{NewDefaultTask(entry, ap).(*DefaultTask)}
+}
+
+func (at *JavaTask) GetSQLs(args map[string]interface{}) ([]Head, []map[string] /* head name */ string, uint64, error) {
+	auditPlanSQLs, count, err := at.persist.GetAuditPlanSQLsByReq(args)
+	if err != nil {
+		return nil, nil, count, err
+	}
+	head := []Head{
+		{
+			Name: "fingerprint",
+			Desc: "SQL指纹",
+			Type: "sql",
+		},
+		{
+			Name: "sql",
+			Desc: "最后一次匹配到该指纹的语句",
+			Type: "sql",
+		},
+		{
+			Name: "source",
+			Desc: "SQL所在源文件",
+		},
+		{
+			Name: "counter",
+			Desc: "匹配到该指纹的语句数量",
+		},
+		{
+			Name: "last_receive_timestamp",
+			Desc: "最后一次匹配到该指纹的时间",
+		},
+	}
+	rows := make([]map[string]string, 0, len(auditPlanSQLs))
+	for _, sql := range auditPlanSQLs {
+		var info = struct {
+			Counter              uint64 `json:"counter"`
+			LastReceiveTimestamp string `json:"last_receive_timestamp"`
+			FilePath             string `json:"file_path"`
+			StartLine            uint64 `json:"start_line"`
+		}{}
+		err := json.Unmarshal(sql.Info, &info)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		source := info.FilePath
+		if source != "" && info.StartLine > 0 {
+			source = fmt.Sprintf("%s:%d", info.FilePath, info.StartLine)
+		}
+		rows = append(rows, map[string]string{
+			"sql":                    sql.SQLContent,
+			"fingerprint":            sql.Fingerprint,
+			"source":                 source,
+			"counter":                strconv.FormatUint(info.Counter, 10),
+			"last_receive_timestamp": info.LastReceiveTimestamp,
+		})
+	}
+	return head, rows, count, nil
+}
+
 func convertSQLsToModelSQLs(sqls []*SQL) []*model.AuditPlanSQLV2 {
 	as := make([]*model.AuditPlanSQLV2, len(sqls))
 	for i, sql := range sqls {
@@ -416,6 +496,8 @@ func (at *SchemaMetaTask) collectorDo() {
 	}
 	defer db.Db.Close()
 
+	// the tasks executed after the time are compared with the drifts.
+	collectedAt := time.Now()
 	tables, err := db.ShowSchemaTables(at.ap.InstanceDatabase)
 	if err != nil {
 		at.logger.Errorf("get schema table fail, error: %v", err)
@@ -430,6 +512,7 @@ func (at *SchemaMetaTask) collectorDo() {
 		}
 	}
 	sqls := make([]string, 0, len(tables)+len(views))
+	tableSQLs := make(map[string]string, len(tables))
 	for _, table := range tables {
 		sql, err := db.ShowCreateTable(utils.SupplementalQuotationMarks(at.ap.InstanceDatabase), utils.SupplementalQuotationMarks(table))
 		if err != nil {
@@ -437,6 +520,7 @@ func (at *SchemaMetaTask) collectorDo() {
 			return
 		}
 		sqls = append(sqls, sql)
+		tableSQLs[table] = sql
 	}
 	for _, view := range views {
 		sql, err := db.ShowCreateView(utils.SupplementalQuotationMarks(view))
@@ -452,6 +536,9 @@ func (at *SchemaMetaTask) collectorDo() {
 			at.logger.Errorf("save schema meta to storage fail, error: %v", err)
 		}
 	}
+	if err := at.saveSchemaSnapshot(instance, tableSQLs, collectedAt); err != nil {
+		at.logger.Errorf("save schema snapshot fail, error: %v", err)
+	}
 }
 
 func (at *SchemaMetaTask) Audit() (*AuditResultResp, error) {
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/binlog"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+const readBinlogTimeout = 10 * time.Minute
+
+// binlogRecorder records the binlog positions around the execution of SQLs.
+// The row changes between the positions are used to generate rollback SQLs,
+// which match what the execution actually changed, rather than the data
+// queried during audit.
+type binlogRecorder struct {
+	entry *logrus.Entry
+	dsn   *driverV2.DSN
+	conn  *executor.Executor
+}
+
+// newBinlogRecorder returns nil if the task should not be rolled back by binlog.
+func newBinlogRecorder(entry *logrus.Entry, task *model.Task) *binlogRecorder {
+	if task.DBType != driverV2.DriverTypeMySQL || task.Instance == nil {
+		return nil
+	}
+	mode, err := model.GetStorage().GetRollbackMode()
+	if err != nil {
+		entry.Errorf("get rollback mode failed, error: %v", err)
+		return nil
+	}
+	if mode != model.RollbackModeBinlog {
+		return nil
+	}
+
+	dsn := newDSNByTask(task)
+	conn, err := executor.NewExecutor(entry, dsn, task.Schema)
+	if err != nil {
+		entry.Warnf("skip recording binlog position, connect to instance failed, error: %v", err)
+		return nil
+	}
+	if err := binlog.CheckRowFormat(conn); err != nil {
+		entry.Warnf("skip recording binlog position, %v", err)
+		conn.Db.Close()
+		return nil
+	}
+	return &binlogRecorder{entry: entry, dsn: dsn, conn: conn}
+}
+
+func newDSNByTask(task *model.Task) *driverV2.DSN {
+	inst := task.Instance
+	return &driverV2.DSN{
+		Host:             inst.Host,
+		Port:             inst.Port,
+		User:             inst.User,
+		Password:         inst.Password,
+		AdditionalParams: inst.AdditionalParams,
+		DatabaseName:     task.Schema,
+	}
+}
+
+func (r *binlogRecorder) close() {
+	if r != nil {
+		r.conn.Db.Close()
+	}
+}
+
+// position returns the current binlog position, it returns nil if the position is unknown.
+func (r *binlogRecorder) position() *binlog.Position {
+	if r == nil {
+		return nil
+	}
+	pos, err := binlog.GetCurrentPosition(r.conn)
+	if err != nil {
+		r.entry.Warnf("get binlog position failed, error: %v", err)
+		return nil
+	}
+	return pos
+}
+
+// record saves start and the current binlog position to the SQLs which are executed after start.
+func (r *binlogRecorder) record(start *binlog.Position, executeSQLs ...*model.ExecuteSQL) {
+	if r == nil || start == nil {
+		return
+	}
+	end := r.position()
+	if end == nil {
+		return
+	}
+	for _, executeSQL := range executeSQLs {
+		executeSQL.StartBinlogFile = start.File
+		executeSQL.StartBinlogPos = int64(start.Pos)
+		executeSQL.EndBinlogFile = end.File
+		executeSQL.EndBinlogPos = int64(end.Pos)
+	}
+}
+
+// genRollbackSQLs generates rollback SQLs of the executed DMLs from binlog.
+// The rollback SQLs generated during audit are replaced.
+func (r *binlogRecorder) genRollbackSQLs(task *model.Task) ([]*model.RollbackSQL, error) {
+	var executeSQLs []*model.ExecuteSQL
+	var stmts []*binlog.Statement
+	var start, end *binlog.Position
+	for _, executeSQL := range task.ExecuteSQLs {
+		if executeSQL.ExecStatus != model.SQLExecuteStatusSucceeded ||
+			executeSQL.StartBinlogFile == "" || executeSQL.EndBinlogFile == "" ||
+			!binlog.IsDML(executeSQL.Content) {
+			continue
+		}
+		stmt := &binlog.Statement{
+			SQL:   executeSQL.Content,
+			Start: &binlog.Position{File: executeSQL.StartBinlogFile, Pos: uint32(executeSQL.StartBinlogPos)},
+			End:   &binlog.Position{File: executeSQL.EndBinlogFile, Pos: uint32(executeSQL.EndBinlogPos)},
+		}
+		if start == nil || stmt.Start.Compare(start) < 0 {
+			start = stmt.Start
+		}
+		if end == nil || stmt.End.Compare(end) > 0 {
+			end = stmt.End
+		}
+		executeSQLs = append(executeSQLs, executeSQL)
+		stmts = append(stmts, stmt)
+	}
+	if len(stmts) == 0 {
+		return nil, nil
+	}
+
+	reader, err := binlog.NewReader(r.dsn)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), readBinlogTimeout)
+	defer cancel()
+	r.entry.Infof("read binlog from %v to %v", start, end)
+	txs, err := reader.ReadTransactions(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("read binlog failed: %v", err)
+	}
+
+	tables := map[string]*binlog.Table{}
+	getTable := func(schema, table string) (*binlog.Table, error) {
+		key := fmt.Sprintf("%s.%s", schema, table)
+		if t, ok := tables[key]; ok {
+			return t, nil
+		}
+		t, err := binlog.GetTable(r.conn, schema, table)
+		if err != nil {
+			return nil, err
+		}
+		tables[key] = t
+		return t, nil
+	}
+
+	contents := make([]string, len(executeSQLs))
+	for idx, changes := range binlog.MatchChanges(stmts, txs) {
+		sqls, err := binlog.GenerateRollbackSQLs(changes, getTable)
+		if err != nil {
+			return nil, fmt.Errorf("generate rollback SQL of \"%s\" failed: %v", executeSQLs[idx].Content, err)
+		}
+		contents[idx] = strings.Join(sqls, "\n")
+	}
+
+	rollbackSQLs := make([]*model.RollbackSQL, 0, len(executeSQLs))
+	for idx, executeSQL := range executeSQLs {
+		rollbackSQL := task.GetRollbackSQLByExecuteSQLId(executeSQL.ID)
+		if rollbackSQL == nil {
+			rollbackSQL = &model.RollbackSQL{
+				BaseSQL:      model.BaseSQL{TaskId: task.ID},
+				ExecuteSQLId: executeSQL.ID,
+			}
+			task.RollbackSQLs = append(task.RollbackSQLs, rollbackSQL)
+		}
+		rollbackSQL.Content = contents[idx]
+		rollbackSQLs = append(rollbackSQLs, rollbackSQL)
+	}
+	return rollbackSQLs, nil
+}
+
+// genRollbackSQLsByBinlog replaces the rollback SQLs of the task by the ones
+// generated from binlog. The rollback SQLs generated during audit are kept if
+// it fails.
+func (a *action) genRollbackSQLsByBinlog() {
+	if a.binlog == nil {
+		return
+	}
+	rollbackSQLs, err := a.binlog.genRollbackSQLs(a.task)
+	if err != nil {
+		a.entry.Errorf("generate rollback SQLs by binlog failed, keep the rollback SQLs generated during audit, error: %v", err)
+		return
+	}
+	if err := model.GetStorage().UpdateRollbackSQLs(rollbackSQLs); err != nil {
+		a.entry.Errorf("save rollback SQLs generated by binlog failed, error: %v", err)
+	}
+}
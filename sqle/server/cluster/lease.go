@@ -0,0 +1,171 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRenewInterval = 3 * time.Second
+)
+
+// LeaseStorage keeps the leader lease and the membership of the cluster.
+type LeaseStorage interface {
+	TryAcquireClusterLeaderLease(serverId string, lease time.Duration) (bool, error)
+	ReleaseClusterLeaderLease(serverId string) error
+	RegisterClusterNode(serverId, reportHost string) error
+	HeartbeatClusterNode(serverId string) error
+	RemoveClusterNode(serverId string) error
+}
+
+// LeadershipNotifier is implemented by the node which can tell the change of leadership
+// at once, so the leader-only jobs do not wait for the next check.
+type LeadershipNotifier interface {
+	LeadershipChanged() <-chan struct{}
+}
+
+// LeaseNode elects the leader by a lease in the storage. The leader renews the lease
+// on every interval, another node acquires it after it is expired.
+//
+// The node gives up the leadership locally two intervals before the lease expires, it
+// is found by the renewal in between, so the old leader has stopped its jobs when a new
+// leader can be elected.
+type LeaseNode struct {
+	storage       LeaseStorage
+	reportHost    string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+	entry         *logrus.Entry
+
+	mu          sync.Mutex
+	serverId    string
+	leaderUntil time.Time
+	wasLeader   bool
+
+	changedCh chan struct{}
+	exitCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+func NewLeaseNode(storage LeaseStorage, reportHost string, leaseDuration, renewInterval time.Duration) *LeaseNode {
+	return &LeaseNode{
+		storage:       storage,
+		reportHost:    reportHost,
+		leaseDuration: leaseDuration,
+		renewInterval: renewInterval,
+		entry:         log.NewEntry().WithField("type", "cluster"),
+		changedCh:     make(chan struct{}, 1),
+	}
+}
+
+func (n *LeaseNode) Join(serverId string) {
+	n.mu.Lock()
+	n.serverId = serverId
+	n.mu.Unlock()
+	n.entry = n.entry.WithField("server_id", serverId)
+
+	if err := n.storage.RegisterClusterNode(serverId, n.reportHost); err != nil {
+		n.entry.Errorf("register cluster node failed: %v", err)
+	}
+	// try to be the leader before the jobs are started
+	n.renew()
+
+	n.exitCh = make(chan struct{})
+	n.doneCh = make(chan struct{})
+	go func() {
+		defer close(n.doneCh)
+		tick := time.NewTicker(n.renewInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-n.exitCh:
+				return
+			case <-tick.C:
+				if err := n.storage.HeartbeatClusterNode(serverId); err != nil {
+					n.entry.Errorf("heartbeat failed: %v", err)
+				}
+				n.renew()
+			}
+		}
+	}()
+}
+
+func (n *LeaseNode) Leave() {
+	if n.exitCh == nil {
+		return
+	}
+	close(n.exitCh)
+	<-n.doneCh
+
+	n.mu.Lock()
+	isLeader := n.isLeader()
+	n.leaderUntil = time.Time{}
+	n.mu.Unlock()
+	n.notify()
+
+	if isLeader {
+		if err := n.storage.ReleaseClusterLeaderLease(n.serverId); err != nil {
+			n.entry.Errorf("release leader lease failed: %v", err)
+		}
+	}
+	if err := n.storage.RemoveClusterNode(n.serverId); err != nil {
+		n.entry.Errorf("remove cluster node failed: %v", err)
+	}
+}
+
+func (n *LeaseNode) IsLeader() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.isLeader()
+}
+
+func (n *LeaseNode) isLeader() bool {
+	return time.Now().Before(n.leaderUntil)
+}
+
+func (n *LeaseNode) LeadershipChanged() <-chan struct{} {
+	return n.changedCh
+}
+
+func (n *LeaseNode) renew() {
+	// the lease in storage expires after start+leaseDuration at the earliest
+	start := time.Now()
+	acquired, err := n.storage.TryAcquireClusterLeaderLease(n.serverId, n.leaseDuration)
+
+	n.mu.Lock()
+	if err != nil {
+		// keep the leadership until the local deadline, the storage may recover before it
+		n.entry.Errorf("acquire leader lease failed: %v", err)
+	} else if acquired {
+		n.leaderUntil = start.Add(n.leaseDuration - 2*n.renewInterval)
+	} else {
+		n.leaderUntil = time.Time{}
+	}
+	n.mu.Unlock()
+	n.notify()
+}
+
+// notify sends a signal if the leadership is changed since the last notification.
+func (n *LeaseNode) notify() {
+	n.mu.Lock()
+	isLeader := n.isLeader()
+	changed := isLeader != n.wasLeader
+	n.wasLeader = isLeader
+	n.mu.Unlock()
+	if !changed {
+		return
+	}
+	if isLeader {
+		n.entry.Infof("became the leader")
+	} else {
+		n.entry.Infof("lost the leadership")
+	}
+	select {
+	case n.changedCh <- struct{}{}:
+	default:
+	}
+}
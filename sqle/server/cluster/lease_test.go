@@ -0,0 +1,134 @@
+package cluster
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryLeaseStorage struct {
+	mu          sync.Mutex
+	leader      string
+	expireAt    time.Time
+	nodes       map[string]time.Time
+	unavailable bool
+}
+
+func newMemoryLeaseStorage() *memoryLeaseStorage {
+	return &memoryLeaseStorage{nodes: map[string]time.Time{}}
+}
+
+func (m *memoryLeaseStorage) hasNode(serverId string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.nodes[serverId]
+	return ok
+}
+
+func (m *memoryLeaseStorage) setUnavailable(unavailable bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unavailable = unavailable
+}
+
+func (m *memoryLeaseStorage) TryAcquireClusterLeaderLease(serverId string, lease time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.unavailable {
+		return false, errors.New("storage is unavailable")
+	}
+	now := time.Now()
+	if m.leader != serverId && now.Before(m.expireAt) {
+		return false, nil
+	}
+	m.leader = serverId
+	m.expireAt = now.Add(lease)
+	return true, nil
+}
+
+func (m *memoryLeaseStorage) ReleaseClusterLeaderLease(serverId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.leader == serverId {
+		m.expireAt = time.Time{}
+	}
+	return nil
+}
+
+func (m *memoryLeaseStorage) RegisterClusterNode(serverId, reportHost string) error {
+	return m.HeartbeatClusterNode(serverId)
+}
+
+func (m *memoryLeaseStorage) HeartbeatClusterNode(serverId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nodes[serverId] = time.Now()
+	return nil
+}
+
+func (m *memoryLeaseStorage) RemoveClusterNode(serverId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.nodes, serverId)
+	return nil
+}
+
+const (
+	testLeaseDuration = 300 * time.Millisecond
+	testRenewInterval = 50 * time.Millisecond
+)
+
+func TestLeaseNodeFailover(t *testing.T) {
+	storage := newMemoryLeaseStorage()
+	node1 := NewLeaseNode(storage, "127.0.0.1:10000", testLeaseDuration, testRenewInterval)
+	node2 := NewLeaseNode(storage, "127.0.0.2:10000", testLeaseDuration, testRenewInterval)
+
+	node1.Join("1")
+	node2.Join("2")
+	defer node2.Leave()
+	assert.True(t, node1.IsLeader())
+	assert.False(t, node2.IsLeader())
+	assert.True(t, storage.hasNode("1"))
+	assert.True(t, storage.hasNode("2"))
+	<-node1.LeadershipChanged()
+
+	// the leader keeps renewing the lease
+	time.Sleep(2 * testLeaseDuration)
+	assert.True(t, node1.IsLeader())
+	assert.False(t, node2.IsLeader())
+
+	// the lease is released on leaving, so the other node takes over in an interval
+	node1.Leave()
+	assert.False(t, node1.IsLeader())
+	assert.False(t, storage.hasNode("1"))
+	select {
+	case <-node1.LeadershipChanged():
+	default:
+		t.Error("leadership change is not notified")
+	}
+	assert.Eventually(t, node2.IsLeader, testLeaseDuration, testRenewInterval/5)
+}
+
+func TestLeaseNodeStorageUnavailable(t *testing.T) {
+	storage := newMemoryLeaseStorage()
+	node1 := NewLeaseNode(storage, "127.0.0.1:10000", testLeaseDuration, testRenewInterval)
+	node1.Join("1")
+	defer node1.Leave()
+	assert.True(t, node1.IsLeader())
+	<-node1.LeadershipChanged()
+
+	// the leader gives up before the lease in storage expires
+	storage.setUnavailable(true)
+	<-node1.LeadershipChanged()
+	assert.False(t, node1.IsLeader())
+	storage.mu.Lock()
+	assert.True(t, time.Now().Before(storage.expireAt))
+	storage.mu.Unlock()
+
+	storage.setUnavailable(false)
+	<-node1.LeadershipChanged()
+	assert.True(t, node1.IsLeader())
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/customrule"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/sirupsen/logrus"
+)
+
+// customRuleChecker checks whether a SQL is hit by the custom rule.
+type customRuleChecker struct {
+	rule *model.CustomRule
+
+	regexp  *regexp.Regexp
+	program *customrule.Program
+	params  map[string]interface{}
+}
+
+func newCustomRuleChecker(rule *model.CustomRule) (*customRuleChecker, error) {
+	checker := &customRuleChecker{rule: rule}
+	var err error
+	switch rule.ScriptType {
+	case model.CustomRuleScriptTypeRegular, "":
+		checker.regexp, err = regexp.Compile(rule.RuleScript)
+		if err != nil {
+			return nil, fmt.Errorf("compile rule script failed: %v", err)
+		}
+	case model.CustomRuleScriptTypeCEL:
+		if rule.DBType != driverV2.DriverTypeMySQL {
+			return nil, fmt.Errorf("the script type %s only supports %s", rule.ScriptType, driverV2.DriverTypeMySQL)
+		}
+		checker.program, err = customrule.Compile(rule.RuleScript)
+		if err != nil {
+			return nil, err
+		}
+		checker.params, err = customrule.ConvertParams(rule.Params)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown script type %s", rule.ScriptType)
+	}
+	return checker, nil
+}
+
+// check returns the message of audit result if sql is hit, node is nil if
+// sql can not be parsed, and the CEL rules are skipped then.
+func (c *customRuleChecker) check(sql string, node ast.StmtNode, view map[string]interface{}) (bool, string, error) {
+	if c.regexp != nil {
+		return c.regexp.MatchString(sql), c.rule.Desc, nil
+	}
+	if node == nil {
+		return false, "", nil
+	}
+	hit, message, err := c.program.Eval(view, c.params)
+	if message == "" {
+		message = c.rule.Desc
+	}
+	return hit, message, err
+}
+
+func (c *customRuleChecker) isCEL() bool {
+	return c.program != nil
+}
+
+func newCustomRuleCheckers(l *logrus.Entry, customRules []*model.CustomRule) []*customRuleChecker {
+	checkers := make([]*customRuleChecker, 0, len(customRules))
+	for _, rule := range customRules {
+		checker, err := newCustomRuleChecker(rule)
+		if err != nil {
+			l.Errorf("skip invalid custom rule %s: %v", rule.RuleId, err)
+			continue
+		}
+		checkers = append(checkers, checker)
+	}
+	return checkers
+}
+
+// auditByCustomRules adds the audit results of custom rules to results, the
+// table metadata of CEL rules is queried from the instance of task if it is set.
+func auditByCustomRules(l *logrus.Entry, task *model.Task, sqls []string, results []*driverV2.AuditResults,
+	customRules []*model.CustomRule) {
+	checkers := newCustomRuleCheckers(l, customRules)
+	if len(checkers) == 0 {
+		return
+	}
+	var hasCEL bool
+	for _, checker := range checkers {
+		hasCEL = hasCEL || checker.isCEL()
+	}
+
+	ctx := session.NewContext(nil)
+	if hasCEL && task.Instance != nil && task.Instance.Host != "" {
+		conn, err := executor.NewExecutor(l, newDSNByTask(task), task.Schema)
+		if err != nil {
+			l.Warnf("connect to instance for custom rules failed, the table metadata is unavailable: %v", err)
+		} else {
+			defer conn.Db.Close()
+			ctx = session.NewContext(nil, session.WithExecutor(conn))
+		}
+	}
+
+	for i, sql := range sqls {
+		var node ast.StmtNode
+		var view map[string]interface{}
+		if hasCEL {
+			var err error
+			node, err = util.ParseOneSql(sql)
+			if err != nil {
+				l.Warnf("parse sql for custom rules failed, the CEL rules are skipped: %v", err)
+				node = nil
+			} else {
+				view = customrule.NewStatementView(ctx, node)
+			}
+		}
+		for _, checker := range checkers {
+			hit, message, err := checker.check(sql, node, view)
+			if err != nil {
+				l.Errorf("custom rule %s check failed: %v", checker.rule.RuleId, err)
+				continue
+			}
+			if hit {
+				results[i].Add(driverV2.RuleLevel(checker.rule.Level), checker.rule.RuleId, message)
+			}
+		}
+		if node != nil {
+			ctx.UpdateContext(node)
+		}
+	}
+}
+
+const DryRunSchema = "dry_run"
+
+type CustomRuleDryRunResult struct {
+	SQL     string
+	Hit     bool
+	Message string
+	Error   string
+}
+
+// DryRunCustomRule checks the SQLs with the custom rule offline, the table
+// metadata is only available for the tables created by the former SQLs, and
+// the current schema is DryRunSchema.
+func DryRunCustomRule(rule *model.CustomRule, sql string) ([]*CustomRuleDryRunResult, error) {
+	checker, err := newCustomRuleChecker(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	sqls := []string{sql}
+	nodes := []ast.StmtNode{nil}
+	if rule.DBType == driverV2.DriverTypeMySQL {
+		stmts, err := util.ParseSql(sql)
+		if err != nil && checker.isCEL() {
+			return nil, fmt.Errorf("parse sql failed: %v", err)
+		}
+		if err == nil {
+			sqls, nodes = make([]string, 0, len(stmts)), make([]ast.StmtNode, 0, len(stmts))
+			for _, stmt := range stmts {
+				sqls = append(sqls, strings.TrimSpace(stmt.Text()))
+				nodes = append(nodes, stmt)
+			}
+		}
+	}
+
+	ctx := session.NewOfflineContext(DryRunSchema)
+	results := make([]*CustomRuleDryRunResult, 0, len(sqls))
+	for i := range sqls {
+		var view map[string]interface{}
+		if nodes[i] != nil && checker.isCEL() {
+			view = customrule.NewStatementView(ctx, nodes[i])
+		}
+		result := &CustomRuleDryRunResult{SQL: sqls[i]}
+		result.Hit, result.Message, err = checker.check(sqls[i], nodes[i], view)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if !result.Hit {
+			result.Message = ""
+		}
+		results = append(results, result)
+		if nodes[i] != nil {
+			ctx.UpdateContext(nodes[i])
+		}
+	}
+	return results, nil
+}
+
+// ValidateCustomRule returns error if the script of rule can not be compiled
+// or any test case of rule is not passed.
+func ValidateCustomRule(rule *model.CustomRule) error {
+	if _, err := newCustomRuleChecker(rule); err != nil {
+		return err
+	}
+	return CheckCustomRuleTestCases(rule)
+}
+
+// CheckCustomRuleTestCases returns error if any test case of rule is not passed.
+func CheckCustomRuleTestCases(rule *model.CustomRule) error {
+	failed := []string{}
+	for i, testCase := range rule.TestCases {
+		results, err := DryRunCustomRule(rule, testCase.SQL)
+		if err != nil {
+			return fmt.Errorf("test case %d: %v", i+1, err)
+		}
+		hit := false
+		for _, result := range results {
+			if result.Error != "" {
+				return fmt.Errorf("test case %d: %v", i+1, result.Error)
+			}
+			hit = hit || result.Hit
+		}
+		if hit != testCase.ExpectHit {
+			failed = append(failed, fmt.Sprintf("%d", i+1))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("test cases [%s] are not passed", strings.Join(failed, ","))
+	}
+	return nil
+}
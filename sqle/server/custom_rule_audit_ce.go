@@ -10,4 +10,6 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func CustomRuleAudit(l *logrus.Entry, task *model.Task, sqls []string, results []*driverV2.AuditResults, customRules []*model.CustomRule) {}
+func CustomRuleAudit(l *logrus.Entry, task *model.Task, sqls []string, results []*driverV2.AuditResults, customRules []*model.CustomRule) {
+	auditByCustomRules(l, task, sqls, results, customRules)
+}
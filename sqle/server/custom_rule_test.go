@@ -0,0 +1,63 @@
+package server
+
+import (
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRunCustomRule(t *testing.T) {
+	rule := &model.CustomRule{
+		RuleId:     "rule_no_index",
+		Desc:       "查询条件没有使用索引",
+		DBType:     driverV2.DriverTypeMySQL,
+		ScriptType: model.CustomRuleScriptTypeCEL,
+		RuleScript: `stmt.type == "select" && stmt.tables.exists(t, stmt.table_meta[t.name].exists &&
+			!stmt.table_meta[t.name].indexes.exists(i, stmt.where.exists(p, p.column in i.columns)))`,
+	}
+	results, err := DryRunCustomRule(rule, `CREATE TABLE t1 (id INT PRIMARY KEY, name VARCHAR(32));
+SELECT * FROM t1 WHERE name = 'a';
+SELECT * FROM t1 WHERE id = 1;`)
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.False(t, results[0].Hit)
+	assert.True(t, results[1].Hit)
+	assert.Equal(t, "查询条件没有使用索引", results[1].Message)
+	assert.Equal(t, "SELECT * FROM t1 WHERE name = 'a';", results[1].SQL)
+	assert.False(t, results[2].Hit)
+
+	rule.TestCases = model.RuleTestCases{
+		{SQL: "CREATE TABLE t1 (id INT PRIMARY KEY, name VARCHAR(32)); SELECT * FROM t1 WHERE name = 'a'", ExpectHit: true},
+		{SQL: "SELECT * FROM t1 WHERE name = 'a'", ExpectHit: false},
+	}
+	assert.NoError(t, CheckCustomRuleTestCases(rule))
+	rule.TestCases[1].ExpectHit = true
+	assert.EqualError(t, CheckCustomRuleTestCases(rule), "test cases [2] are not passed")
+
+	_, err = DryRunCustomRule(&model.CustomRule{DBType: driverV2.DriverTypeMySQL, ScriptType: model.CustomRuleScriptTypeCEL,
+		RuleScript: "stmt.limit > params.max", Params: params.Params{{Key: "max", Value: "x", Type: params.ParamTypeInt}}}, "SELECT 1")
+	assert.Error(t, err)
+}
+
+func Test_auditByCustomRules(t *testing.T) {
+	customRules := []*model.CustomRule{
+		{RuleId: "rule_regular", Desc: "禁止使用 SLEEP", Level: "error", DBType: driverV2.DriverTypeMySQL,
+			RuleScript: `(?i)sleep\(`},
+		{RuleId: "rule_cel", Desc: "DELETE 语句需要有 WHERE 条件", Level: "warn", DBType: driverV2.DriverTypeMySQL,
+			ScriptType: model.CustomRuleScriptTypeCEL, RuleScript: `stmt.type == "delete" && !stmt.has_where`},
+		{RuleId: "rule_invalid", Level: "warn", DBType: driverV2.DriverTypeMySQL,
+			ScriptType: model.CustomRuleScriptTypeCEL, RuleScript: `stmt.type ==`},
+	}
+	sqls := []string{"SELECT SLEEP(1)", "DELETE FROM t1", "DELETE FROM t1 WHERE id = 1"}
+	results := []*driverV2.AuditResults{driverV2.NewAuditResults(), driverV2.NewAuditResults(), driverV2.NewAuditResults()}
+	auditByCustomRules(log.NewEntry(), &model.Task{}, sqls, results, customRules)
+
+	assert.Equal(t, "[error]禁止使用 SLEEP", results[0].Message())
+	assert.Equal(t, "[warn]DELETE 语句需要有 WHERE 条件", results[1].Message())
+	assert.Equal(t, "", results[2].Message())
+}
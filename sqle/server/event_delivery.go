@@ -0,0 +1,54 @@
+package server
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/eventbus"
+	"github.com/sirupsen/logrus"
+)
+
+// EventDeliveryJob sends the due deliveries of events, it is woken up when the events are
+// published on this node, and the ticker picks up the retries and the events of other nodes.
+type EventDeliveryJob struct {
+	BaseJob
+	bus *eventbus.Bus
+}
+
+func NewEventDeliveryJob(entry *logrus.Entry) ServerJob {
+	entry = entry.WithField("job", "event_delivery")
+	j := &EventDeliveryJob{bus: eventbus.NewBus(model.GetStorage())}
+	j.BaseJob = *NewBaseJob(entry, 5*time.Second, j.job)
+	return j
+}
+
+func (j *EventDeliveryJob) Start() {
+	go func() {
+		j.entry.Infof("start, internal is %s", j.internal)
+		defer j.entry.Infof("stop")
+
+		tick := time.NewTicker(j.internal)
+		defer tick.Stop()
+		for {
+			select {
+			case <-j.exitCh:
+				j.doneCh <- struct{}{}
+				return
+			case <-tick.C:
+				j.jobFn(j.entry)
+			case <-eventbus.Wakeup():
+				j.jobFn(j.entry)
+			}
+		}
+	}()
+}
+
+func (j *EventDeliveryJob) job(entry *logrus.Entry) {
+	// a full batch means there may be more due deliveries, the rest is left to the next tick
+	// if they are too many, so the job can be stopped in time.
+	for i := 0; i < 10; i++ {
+		if j.bus.DeliverDue(entry) < eventbus.DeliveryBatchSize {
+			return
+		}
+	}
+}
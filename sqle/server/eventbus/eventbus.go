@@ -0,0 +1,302 @@
+// Package eventbus publishes the domain events of SQLE to the webhooks subscribed by projects.
+//
+// The events are persisted as deliveries of the subscriptions when they are published, the
+// deliveries are sent by the leader and retried with backoff until they succeed or run out
+// of attempts, so the events are not lost if the receiver or SQLE is down for a while.
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	EventWorkflowCreated          = "workflow.created"
+	EventWorkflowApproved         = "workflow.approved"
+	EventWorkflowRejected         = "workflow.rejected"
+	EventWorkflowExecuted         = "workflow.executed"
+	EventWorkflowExecuteFailed    = "workflow.execute_failed"
+	EventTaskAudited              = "task.audited"
+	EventAuditPlanReportGenerated = "audit_plan.report_generated"
+	EventRuleTemplateChanged      = "rule_template.changed"
+	EventSqlManageStatusChanged   = "sql_manage.status_changed"
+)
+
+var EventTypes = []string{
+	EventWorkflowCreated,
+	EventWorkflowApproved,
+	EventWorkflowRejected,
+	EventWorkflowExecuted,
+	EventWorkflowExecuteFailed,
+	EventTaskAudited,
+	EventAuditPlanReportGenerated,
+	EventRuleTemplateChanged,
+	EventSqlManageStatusChanged,
+}
+
+func IsValidEventType(eventType string) bool {
+	for _, typ := range EventTypes {
+		if typ == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	HeaderEvent     = "X-SQLE-Event"
+	HeaderDelivery  = "X-SQLE-Delivery"
+	HeaderTimestamp = "X-SQLE-Timestamp"
+	HeaderSignature = "X-SQLE-Signature"
+)
+
+const (
+	MaxDeliveryAttempts = 8
+	// the interval is doubled after each failed attempt, the deliveries are given up in about an hour.
+	FirstRetryInterval = 30 * time.Second
+	MaxRetryInterval   = time.Hour
+
+	DeliveryBatchSize   = 100
+	maxResponseBodySize = 1024
+)
+
+// Event is the request body sent to the webhook.
+type Event struct {
+	Id        string      `json:"id"`
+	Type      string      `json:"type"`
+	ProjectId string      `json:"project_uid"`
+	Timestamp string      `json:"timestamp"` // time.RFC3339
+	Data      interface{} `json:"data"`
+}
+
+// Storage keeps the subscriptions and the deliveries of events.
+type Storage interface {
+	GetEnabledEventSubscriptions(projectId string) ([]*model.EventSubscription, error)
+	CreateEventDeliveries(deliveries []*model.EventDelivery) error
+	GetDueEventDeliveries(now time.Time, limit int) ([]*model.EventDelivery, error)
+	UpdateEventDelivery(delivery *model.EventDelivery) error
+}
+
+type Bus struct {
+	storage    Storage
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func NewBus(storage Storage) *Bus {
+	return &Bus{
+		storage:    storage,
+		httpClient: newHTTPClient(allowPrivateNetwork()),
+		now:        time.Now,
+	}
+}
+
+// wakeCh tells the delivery job that there are new deliveries, so it does not wait for the next tick.
+var wakeCh = make(chan struct{}, 1)
+
+func Wakeup() <-chan struct{} {
+	return wakeCh
+}
+
+func wakeup() {
+	select {
+	case wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// Publish creates the deliveries of the event for the enabled subscriptions which subscribe it.
+// The event of global resource whose project id is empty is sent to the subscriptions of all projects.
+func (b *Bus) Publish(eventType, projectId string, data interface{}) error {
+	subscriptions, err := b.storage.GetEnabledEventSubscriptions(projectId)
+	if err != nil {
+		return err
+	}
+	var matched []*model.EventSubscription
+	for _, subscription := range subscriptions {
+		if subscription.Subscribes(eventType) {
+			matched = append(matched, subscription)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	id, err := utils.GenUid()
+	if err != nil {
+		return err
+	}
+	now := b.now()
+	payload, err := json.Marshal(&Event{
+		Id:        id,
+		Type:      eventType,
+		ProjectId: projectId,
+		Timestamp: now.Format(time.RFC3339),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]*model.EventDelivery, 0, len(matched))
+	for _, subscription := range matched {
+		deliveries = append(deliveries, &model.EventDelivery{
+			SubscriptionId: subscription.ID,
+			EventId:        id,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         model.EventDeliveryStatusPending,
+			NextAttemptAt:  &now,
+		})
+	}
+	if err := b.storage.CreateEventDeliveries(deliveries); err != nil {
+		return err
+	}
+	wakeup()
+	return nil
+}
+
+// Redeliver creates a new delivery with the payload of the delivery, it is sent as soon as possible.
+func (b *Bus) Redeliver(delivery *model.EventDelivery) (*model.EventDelivery, error) {
+	now := b.now()
+	redelivery := &model.EventDelivery{
+		SubscriptionId: delivery.SubscriptionId,
+		EventId:        delivery.EventId,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         model.EventDeliveryStatusPending,
+		NextAttemptAt:  &now,
+		RedeliveryOf:   delivery.ID,
+	}
+	if err := b.storage.CreateEventDeliveries([]*model.EventDelivery{redelivery}); err != nil {
+		return nil, err
+	}
+	wakeup()
+	return redelivery, nil
+}
+
+// DeliverDue sends the deliveries which are due, it returns the number of deliveries attempted.
+func (b *Bus) DeliverDue(entry *logrus.Entry) int {
+	deliveries, err := b.storage.GetDueEventDeliveries(b.now(), DeliveryBatchSize)
+	if err != nil {
+		entry.Errorf("get due event deliveries failed: %v", err)
+		return 0
+	}
+	for _, delivery := range deliveries {
+		b.deliver(delivery)
+		if err := b.storage.UpdateEventDelivery(delivery); err != nil {
+			entry.Errorf("update event delivery %d failed: %v", delivery.ID, err)
+		}
+	}
+	return len(deliveries)
+}
+
+// Sign returns the signature of the payload, the receiver verifies the request by computing
+// the HMAC-SHA256 of "<timestamp>.<body>" with the secret of subscription.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func retryInterval(attempts uint) time.Duration {
+	interval := FirstRetryInterval
+	for i := uint(1); i < attempts; i++ {
+		interval *= 2
+		if interval >= MaxRetryInterval {
+			return MaxRetryInterval
+		}
+	}
+	return interval
+}
+
+func (b *Bus) deliver(delivery *model.EventDelivery) {
+	now := b.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus, delivery.ResponseBody, delivery.Error = 0, "", ""
+
+	subscription := delivery.Subscription
+	if subscription == nil || !subscription.IsEnabled {
+		delivery.Status = model.EventDeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		delivery.Error = "the subscription is deleted or disabled"
+		return
+	}
+
+	err := b.send(delivery, subscription)
+	delivery.DurationMs = b.now().Sub(now).Milliseconds()
+	if err == nil {
+		delivery.Status = model.EventDeliveryStatusSucceeded
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	delivery.Error = truncate(err.Error(), maxResponseBodySize)
+	if delivery.Attempts >= MaxDeliveryAttempts {
+		delivery.Status = model.EventDeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(retryInterval(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+func truncate(s string, size int) string {
+	runes := []rune(s)
+	if len(runes) <= size {
+		return s
+	}
+	return string(runes[:size])
+}
+
+func (b *Bus) send(delivery *model.EventDelivery, subscription *model.EventSubscription) error {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(b.now().Unix(), 10)
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = truncate(string(respBody), maxResponseBodySize)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Publish publishes the event by the storage of SQLE, the error is logged because
+// the event should not break the operation which produces it.
+func Publish(eventType, projectId string, data interface{}) {
+	if err := NewBus(model.GetStorage()).Publish(eventType, projectId, data); err != nil {
+		log.NewEntry().Errorf("publish event %s of project %s failed: %v", eventType, projectId, err)
+	}
+}
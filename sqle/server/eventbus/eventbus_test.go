@@ -0,0 +1,209 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStorage struct {
+	subscriptions []*model.EventSubscription
+	deliveries    []*model.EventDelivery
+}
+
+func (s *fakeStorage) GetEnabledEventSubscriptions(projectId string) ([]*model.EventSubscription, error) {
+	subscriptions := []*model.EventSubscription{}
+	for _, subscription := range s.subscriptions {
+		if subscription.IsEnabled && (projectId == "" || subscription.ProjectId == projectId) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (s *fakeStorage) CreateEventDeliveries(deliveries []*model.EventDelivery) error {
+	for _, delivery := range deliveries {
+		delivery.ID = uint(len(s.deliveries) + 1)
+		s.deliveries = append(s.deliveries, delivery)
+	}
+	return nil
+}
+
+func (s *fakeStorage) GetDueEventDeliveries(now time.Time, limit int) ([]*model.EventDelivery, error) {
+	deliveries := []*model.EventDelivery{}
+	for _, delivery := range s.deliveries {
+		if delivery.Status != model.EventDeliveryStatusPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		delivery.Subscription = nil
+		for _, subscription := range s.subscriptions {
+			if subscription.ID == delivery.SubscriptionId {
+				delivery.Subscription = subscription
+			}
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (s *fakeStorage) UpdateEventDelivery(delivery *model.EventDelivery) error {
+	return nil
+}
+
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver() *receiver {
+	r := &receiver{status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		w.WriteHeader(r.status)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	return r
+}
+
+func newTestBus(storage *fakeStorage, now *time.Time) *Bus {
+	b := NewBus(storage)
+	// the receiver listens on loopback
+	b.httpClient = newHTTPClient(true)
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func newSubscription(id uint, projectId, url string, eventTypes ...string) *model.EventSubscription {
+	subscription := &model.EventSubscription{ProjectId: projectId, URL: url, Secret: "secret", IsEnabled: true}
+	subscription.ID = id
+	subscription.SetEventTypes(eventTypes)
+	return subscription
+}
+
+func TestPublishAndDeliver(t *testing.T) {
+	r := newReceiver()
+	defer r.Close()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := &fakeStorage{subscriptions: []*model.EventSubscription{
+		newSubscription(1, "p1", r.URL),
+		newSubscription(2, "p1", r.URL, EventWorkflowCreated),
+		newSubscription(3, "p2", r.URL),
+	}}
+	b := newTestBus(storage, &now)
+
+	assert.NoError(t, b.Publish(EventTaskAudited, "p1", &TaskEventData{TaskId: 1}))
+	// only the subscriptions of project which subscribe the event are delivered
+	assert.Len(t, storage.deliveries, 1)
+	assert.Equal(t, uint(1), storage.deliveries[0].SubscriptionId)
+
+	assert.Equal(t, 1, b.DeliverDue(log.NewEntry()))
+	delivery := storage.deliveries[0]
+	assert.Equal(t, model.EventDeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, uint(1), delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.ResponseStatus)
+	assert.Equal(t, "ok", delivery.ResponseBody)
+
+	assert.Len(t, r.requests, 1)
+	req := r.requests[0]
+	assert.Equal(t, EventTaskAudited, req.Header.Get(HeaderEvent))
+	assert.Equal(t, "1", req.Header.Get(HeaderDelivery))
+	assert.Equal(t, Sign("secret", req.Header.Get(HeaderTimestamp), r.bodies[0]), req.Header.Get(HeaderSignature))
+
+	event := &Event{}
+	assert.NoError(t, json.Unmarshal(r.bodies[0], event))
+	assert.Equal(t, EventTaskAudited, event.Type)
+	assert.Equal(t, "p1", event.ProjectId)
+	assert.Equal(t, delivery.EventId, event.Id)
+
+	// the event of global resource is sent to all projects
+	assert.NoError(t, b.Publish(EventRuleTemplateChanged, "", &RuleTemplateEventData{Name: "t1"}))
+	assert.Len(t, storage.deliveries, 3)
+}
+
+func TestDeliverRetry(t *testing.T) {
+	r := newReceiver()
+	defer r.Close()
+	r.status = http.StatusInternalServerError
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := &fakeStorage{subscriptions: []*model.EventSubscription{newSubscription(1, "p1", r.URL)}}
+	b := newTestBus(storage, &now)
+
+	assert.NoError(t, b.Publish(EventWorkflowCreated, "p1", &WorkflowEventData{WorkflowId: "1"}))
+	delivery := storage.deliveries[0]
+
+	b.DeliverDue(log.NewEntry())
+	assert.Equal(t, model.EventDeliveryStatusPending, delivery.Status)
+	assert.Equal(t, http.StatusInternalServerError, delivery.ResponseStatus)
+	assert.NotEmpty(t, delivery.Error)
+	assert.Equal(t, now.Add(FirstRetryInterval), *delivery.NextAttemptAt)
+
+	// it is not due before the next attempt
+	assert.Equal(t, 0, b.DeliverDue(log.NewEntry()))
+
+	for i := 1; i < MaxDeliveryAttempts; i++ {
+		now = *delivery.NextAttemptAt
+		assert.Equal(t, 1, b.DeliverDue(log.NewEntry()))
+	}
+	assert.Equal(t, model.EventDeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, uint(MaxDeliveryAttempts), delivery.Attempts)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.Len(t, r.requests, MaxDeliveryAttempts)
+
+	// redelivery sends the same payload again
+	r.status = http.StatusOK
+	redelivery, err := b.Redeliver(delivery)
+	assert.NoError(t, err)
+	assert.Equal(t, delivery.ID, redelivery.RedeliveryOf)
+	assert.Equal(t, 1, b.DeliverDue(log.NewEntry()))
+	assert.Equal(t, model.EventDeliveryStatusSucceeded, redelivery.Status)
+	assert.Equal(t, r.bodies[0], r.bodies[len(r.bodies)-1])
+}
+
+func TestDeliverToDeletedSubscription(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := &fakeStorage{subscriptions: []*model.EventSubscription{newSubscription(1, "p1", "http://127.0.0.1:1")}}
+	b := newTestBus(storage, &now)
+
+	assert.NoError(t, b.Publish(EventWorkflowCreated, "p1", &WorkflowEventData{WorkflowId: "1"}))
+	storage.subscriptions = nil
+	b.DeliverDue(log.NewEntry())
+	assert.Equal(t, model.EventDeliveryStatusFailed, storage.deliveries[0].Status)
+}
+
+func TestRetryInterval(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryInterval(1))
+	assert.Equal(t, time.Minute, retryInterval(2))
+	assert.Equal(t, 16*time.Minute, retryInterval(6))
+	assert.Equal(t, 32*time.Minute, retryInterval(7))
+	assert.Equal(t, time.Hour, retryInterval(8))
+	assert.Equal(t, time.Hour, retryInterval(20))
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163", Sign("secret", "1700000000", []byte("{}")))
+}
+
+func TestIsValidEventType(t *testing.T) {
+	for _, eventType := range EventTypes {
+		assert.True(t, IsValidEventType(eventType))
+	}
+	assert.True(t, IsValidEventType("sql_manage.status_changed"))
+	assert.False(t, IsValidEventType("sql_manage.unknown"))
+	assert.False(t, IsValidEventType(""))
+}
@@ -0,0 +1,140 @@
+package eventbus
+
+import (
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+type WorkflowEventData struct {
+	WorkflowId    string           `json:"workflow_id"`
+	Subject       string           `json:"subject"`
+	Desc          string           `json:"desc"`
+	Status        string           `json:"status"`
+	CreateUserId  string           `json:"create_user_id"`
+	CurrentStepId uint             `json:"current_step_id,omitempty"`
+	Tasks         []*TaskEventData `json:"tasks"`
+}
+
+type TaskEventData struct {
+	TaskId       uint    `json:"task_id"`
+	InstanceName string  `json:"instance_name,omitempty"`
+	Schema       string  `json:"instance_schema"`
+	DBType       string  `json:"db_type"`
+	Status       string  `json:"status"`
+	AuditLevel   string  `json:"audit_level"`
+	PassRate     float64 `json:"pass_rate"`
+	Score        int32   `json:"score"`
+	SQLCount     int     `json:"sql_count"`
+}
+
+type AuditPlanReportEventData struct {
+	AuditPlanId   uint    `json:"audit_plan_id"`
+	AuditPlanName string  `json:"audit_plan_name"`
+	AuditPlanType string  `json:"audit_plan_type"`
+	InstanceName  string  `json:"instance_name"`
+	ReportId      uint    `json:"report_id"`
+	AuditLevel    string  `json:"audit_level"`
+	PassRate      float64 `json:"pass_rate"`
+	Score         int32   `json:"score"`
+}
+
+const (
+	RuleTemplateActionCreated = "created"
+	RuleTemplateActionUpdated = "updated"
+	RuleTemplateActionDeleted = "deleted"
+)
+
+type RuleTemplateEventData struct {
+	Name           string `json:"name"`
+	DBType         string `json:"db_type"`
+	Action         string `json:"action"`
+	IsGlobal       bool   `json:"is_global"`
+	OperatorUserId string `json:"operator_user_id"`
+}
+
+type SqlManageStatusEventData struct {
+	SqlManageIds   []uint `json:"sql_manage_ids"`
+	Status         string `json:"status"`
+	OperatorUserId string `json:"operator_user_id"`
+}
+
+func NewWorkflowEventData(workflow *model.Workflow) *WorkflowEventData {
+	data := &WorkflowEventData{
+		WorkflowId:   workflow.WorkflowId,
+		Subject:      workflow.Subject,
+		Desc:         workflow.Desc,
+		CreateUserId: workflow.CreateUserId,
+		Tasks:        []*TaskEventData{},
+	}
+	if workflow.Record == nil {
+		return data
+	}
+	data.Status = workflow.Record.Status
+	data.CurrentStepId = workflow.Record.CurrentWorkflowStepId
+	for _, record := range workflow.Record.InstanceRecords {
+		if record.Task == nil {
+			continue
+		}
+		task := NewTaskEventData(record.Task)
+		if task.InstanceName == "" && record.Instance != nil {
+			task.InstanceName = record.Instance.Name
+		}
+		data.Tasks = append(data.Tasks, task)
+	}
+	return data
+}
+
+func NewTaskEventData(task *model.Task) *TaskEventData {
+	data := &TaskEventData{
+		TaskId:     task.ID,
+		Schema:     task.Schema,
+		DBType:     task.DBType,
+		Status:     task.Status,
+		AuditLevel: task.AuditLevel,
+		PassRate:   task.PassRate,
+		Score:      task.Score,
+		SQLCount:   len(task.ExecuteSQLs),
+	}
+	if task.Instance != nil {
+		data.InstanceName = task.Instance.Name
+	}
+	return data
+}
+
+func NewAuditPlanReportEventData(ap *model.AuditPlan, report *model.AuditPlanReportV2) *AuditPlanReportEventData {
+	return &AuditPlanReportEventData{
+		AuditPlanId:   ap.ID,
+		AuditPlanName: ap.Name,
+		AuditPlanType: ap.Type,
+		InstanceName:  ap.InstanceName,
+		ReportId:      report.ID,
+		AuditLevel:    report.AuditLevel,
+		PassRate:      report.PassRate,
+		Score:         report.Score,
+	}
+}
+
+// PublishRuleTemplateChanged publishes the change of rule template, the change of global
+// template is sent to all projects.
+func PublishRuleTemplateChanged(projectId string, template *model.RuleTemplate, action, operatorUserId string) {
+	data := &RuleTemplateEventData{
+		Name:           template.Name,
+		DBType:         template.DBType,
+		Action:         action,
+		IsGlobal:       projectId == model.ProjectIdForGlobalRuleTemplate,
+		OperatorUserId: operatorUserId,
+	}
+	if data.IsGlobal {
+		projectId = ""
+	}
+	Publish(EventRuleTemplateChanged, projectId, data)
+}
+
+// PublishSqlManageStatusChanged publishes the status change of the managed SQLs, it is called
+// by the handler which updates the status.
+func PublishSqlManageStatusChanged(projectId string, sqlManageIds []uint, status, operatorUserId string) {
+	Publish(EventSqlManageStatusChanged, projectId, &SqlManageStatusEventData{
+		SqlManageIds:   sqlManageIds,
+		Status:         status,
+		OperatorUserId: operatorUserId,
+	})
+}
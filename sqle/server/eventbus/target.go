@@ -0,0 +1,81 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/config"
+)
+
+// ErrForbiddenTarget is returned when the webhook points to the network of SQLE itself,
+// such as loopback, private, link-local and the metadata service of cloud.
+var ErrForbiddenTarget = errors.New("webhook target is in private network")
+
+// carrierGradeNAT is 100.64.0.0/10, the metadata service of some clouds is in it.
+var carrierGradeNAT = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isForbiddenIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() ||
+		carrierGradeNAT.Contains(ip)
+}
+
+func allowPrivateNetwork() bool {
+	return config.GetOptions().SqleOptions.Service.EventWebhookAllowPrivateNetwork
+}
+
+// CheckWebhookHost resolves the host of webhook and checks the addresses are allowed,
+// it gives the early feedback when the subscription is saved. The addresses are checked
+// again when the webhook is sent, since the host may be resolved to others later.
+func CheckWebhookHost(ctx context.Context, host string) error {
+	if allowPrivateNetwork() {
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if isForbiddenIP(addr.IP) {
+			return fmt.Errorf("%w: %s is resolved to %s", ErrForbiddenTarget, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// newHTTPClient returns the client which sends the webhooks, the address is checked in
+// the dialer after it is resolved, so the redirects and DNS rebinding are checked too.
+func newHTTPClient(allowPrivateNetwork bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivateNetwork {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isForbiddenIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenTarget, address)
+			}
+			return nil
+		}
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		// the proxy is not used, otherwise the dialer checks the address of proxy
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsForbiddenIP(t *testing.T) {
+	for ip, forbidden := range map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"100.100.100.200": true,
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00:ec2::254":   true,
+		"8.8.8.8":         false,
+		"2001:4860::8888": false,
+	} {
+		assert.Equal(t, forbidden, isForbiddenIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestNewHTTPClient_ForbidPrivateNetwork(t *testing.T) {
+	r := newReceiver()
+	defer r.Close()
+
+	_, err := newHTTPClient(false).Post(r.URL, "application/json", nil)
+	assert.True(t, errors.Is(err, ErrForbiddenTarget), err)
+
+	resp, err := newHTTPClient(true).Post(r.URL, "application/json", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestCheckWebhookHost(t *testing.T) {
+	err := CheckWebhookHost(context.Background(), "127.0.0.1")
+	assert.True(t, errors.Is(err, ErrForbiddenTarget), err)
+	assert.NoError(t, CheckWebhookHost(context.Background(), "8.8.8.8"))
+}
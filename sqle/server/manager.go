@@ -18,6 +18,7 @@ var OnlyRunOnLeaderJobs = []func(entry *logrus.Entry) ServerJob{
 	NewDingTalkJob,
 	NewFeishuJob,
 	NewWechatJob,
+	NewEventDeliveryJob,
 }
 
 var RunOnAllJobs = []func(entry *logrus.Entry) ServerJob{
@@ -49,22 +50,20 @@ func (s *ServerJobManager) Start() {
 	entry.Infof("start job manager")
 
 	defer s.startRunOnAllJob(entry)
+
+	var leadershipChanged <-chan struct{}
+	if notifier, ok := s.clusterNode.(cluster.LeadershipNotifier); ok {
+		leadershipChanged = notifier.LeadershipChanged()
+	}
 	go func() {
 		tick := time.NewTicker(5 * time.Second)
 		defer tick.Stop()
 		for {
 			select {
 			case <-tick.C:
-				isLeader := s.clusterNode.IsLeader()
-				if s.isLeader == isLeader {
-					continue // leader not change. do nothing
-				}
-				s.isLeader = isLeader
-				if isLeader {
-					s.startOnlyRunOnLeaderJob(entry)
-				} else {
-					s.stopOnlyRunOnLeaderJob()
-				}
+				s.switchOnlyRunOnLeaderJob(entry)
+			case <-leadershipChanged:
+				s.switchOnlyRunOnLeaderJob(entry)
 			case <-s.exitCh:
 				s.stopRunOnAllJob()
 				s.stopOnlyRunOnLeaderJob()
@@ -76,6 +75,23 @@ func (s *ServerJobManager) Start() {
 	}()
 }
 
+// switchOnlyRunOnLeaderJob starts the leader-only jobs when the node becomes the leader,
+// and stops them when it loses the leadership.
+func (s *ServerJobManager) switchOnlyRunOnLeaderJob(entry *logrus.Entry) {
+	isLeader := s.clusterNode.IsLeader()
+	if s.isLeader == isLeader {
+		return // leader not change. do nothing
+	}
+	s.isLeader = isLeader
+	if isLeader {
+		entry.Infof("start leader-only jobs")
+		s.startOnlyRunOnLeaderJob(entry)
+	} else {
+		entry.Infof("stop leader-only jobs")
+		s.stopOnlyRunOnLeaderJob()
+	}
+}
+
 func (s *ServerJobManager) Stop() {
 	s.exitCh <- struct{}{}
 	<-s.doneCh
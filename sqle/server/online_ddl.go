@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/onlineddl"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	GhostActionThrottle        = "throttle"
+	GhostActionUnthrottle      = "unthrottle"
+	GhostActionPostponeCutOver = "postpone_cut_over"
+	GhostActionCutOver         = "cut_over"
+)
+
+func ghostMigrationKey(taskId, number uint) string {
+	return fmt.Sprintf("%d:%d", taskId, number)
+}
+
+// applyTaskGhostConfig overrides the gh-ost config of task instance by the
+// config of task, the instance is loaded for the task so it can be modified.
+func applyTaskGhostConfig(task *model.Task) error {
+	if task.Instance == nil || task.DBType != driverV2.DriverTypeMySQL {
+		return nil
+	}
+	config, err := task.GetGhostConfig()
+	if err != nil {
+		return err
+	}
+	if len(config) == 0 {
+		return nil
+	}
+	additionalParams := make(params.Params, 0, len(task.Instance.AdditionalParams)+len(config))
+	for _, p := range task.Instance.AdditionalParams {
+		key := strings.TrimPrefix(p.Key, onlineddl.ParamKeyPrefix)
+		if _, ok := config[key]; ok && strings.HasPrefix(p.Key, onlineddl.ParamKeyPrefix) && !onlineddl.IsInstanceOnly(key) {
+			continue
+		}
+		additionalParams = append(additionalParams, p)
+	}
+	for key, value := range config {
+		// the instance only config is set by admin, it is not overridden by the task.
+		if onlineddl.IsInstanceOnly(key) {
+			continue
+		}
+		additionalParams = append(additionalParams, &params.Param{
+			Key:   onlineddl.ParamKeyPrefix + key,
+			Value: value,
+			Type:  params.ParamTypeString,
+		})
+	}
+	task.Instance.AdditionalParams = additionalParams
+	return nil
+}
+
+// newGhostContext returns the context for executing SQL, the progress of gh-ost
+// migration is saved to the exec result of SQL while it is running.
+func newGhostContext(l *logrus.Entry, task *model.Task, executeSQL *model.ExecuteSQL) context.Context {
+	if task.DBType != driverV2.DriverTypeMySQL {
+		return context.TODO()
+	}
+	id := strconv.Itoa(int(executeSQL.ID))
+	return onlineddl.NewContext(context.TODO(), ghostMigrationKey(task.ID, executeSQL.Number), func(p *onlineddl.Progress) {
+		err := model.GetStorage().UpdateExecuteSQLById(id, map[string]interface{}{"exec_result": p.String()})
+		if err != nil {
+			l.Errorf("save gh-ost progress failed: %v", err)
+		}
+	})
+}
+
+type GhostMigrationProgress struct {
+	Number uint
+	*onlineddl.Progress
+}
+
+// GetGhostProgress returns the progress of gh-ost migrations running in the
+// task, the migrations are only visible in the SQLE which executes the task.
+func GetGhostProgress(taskId uint) []*GhostMigrationProgress {
+	prefix := fmt.Sprintf("%d:", taskId)
+	progress := []*GhostMigrationProgress{}
+	for key, p := range onlineddl.ListProgress(prefix) {
+		number, err := strconv.Atoi(strings.TrimPrefix(key, prefix))
+		if err != nil {
+			continue
+		}
+		progress = append(progress, &GhostMigrationProgress{Number: uint(number), Progress: p})
+	}
+	sort.Slice(progress, func(i, j int) bool {
+		return progress[i].Number < progress[j].Number
+	})
+	return progress
+}
+
+func OperateGhostMigration(taskId, number uint, action string) error {
+	key := ghostMigrationKey(taskId, number)
+	switch action {
+	case GhostActionThrottle:
+		return onlineddl.Throttle(key)
+	case GhostActionUnthrottle:
+		return onlineddl.Unthrottle(key)
+	case GhostActionPostponeCutOver:
+		return onlineddl.PostponeCutOver(key)
+	case GhostActionCutOver:
+		return onlineddl.CutOver(key)
+	default:
+		return fmt.Errorf("unknown gh-ost action %s", action)
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/onlineddl"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTaskGhostConfig(t *testing.T) {
+	task := &model.Task{
+		DBType:      driverV2.DriverTypeMySQL,
+		GhostConfig: model.JSON(`{"chunk_size":"500","postpone_cut_over":"true","max_load":"Threads_running=1000"}`),
+		Instance: &model.Instance{AdditionalParams: params.Params{
+			{Key: "ghost_chunk_size", Value: "1000"},
+			{Key: "ghost_max_lag_millis", Value: "3000"},
+			{Key: "ghost_max_load", Value: "Threads_running=80"},
+			{Key: "other", Value: "1"},
+		}},
+	}
+	assert.NoError(t, applyTaskGhostConfig(task))
+	// the instance only config is not overridden by the task
+	assert.Equal(t, map[string]string{
+		"chunk_size":        "500",
+		"max_lag_millis":    "3000",
+		"max_load":          "Threads_running=80",
+		"postpone_cut_over": "true",
+	}, onlineddl.ConfigFromParams(task.Instance.AdditionalParams))
+	assert.Equal(t, "1", task.Instance.AdditionalParams.GetParam("other").Value)
+
+	// no config
+	task = &model.Task{DBType: driverV2.DriverTypeMySQL, Instance: &model.Instance{}}
+	assert.NoError(t, applyTaskGhostConfig(task))
+	assert.Len(t, task.Instance.AdditionalParams, 0)
+}
+
+func TestOperateGhostMigration(t *testing.T) {
+	assert.Len(t, GetGhostProgress(1), 0)
+	assert.Equal(t, onlineddl.ErrMigrationNotRunning, OperateGhostMigration(1, 1, GhostActionThrottle))
+	assert.Error(t, OperateGhostMigration(1, 1, "unknown"))
+}
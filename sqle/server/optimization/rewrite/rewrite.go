@@ -0,0 +1,202 @@
+// Package rewrite is the built-in SQL rewrite engine of MySQL, it implements
+// the rule codes of the optimization rule catalogue over the pingcap AST.
+package rewrite
+
+import (
+	"fmt"
+
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	optimization "github.com/actiontech/sqle/sqle/server/optimization/rule"
+	"github.com/pingcap/parser/ast"
+)
+
+// GetCreateTableFunc returns the CREATE TABLE statement of the table, the
+// rules depending on the table structure are skipped when it is nil.
+type GetCreateTableFunc func(table *ast.TableName) (*ast.CreateTableStmt, bool)
+
+type TriggeredRule struct {
+	RuleCode string
+	Message  string
+	// RewrittenSQL is the SQL after the rule applied, it is empty when the rule only gives a warning.
+	RewrittenSQL string
+}
+
+func (r *TriggeredRule) IsRewritten() bool {
+	return r.RewrittenSQL != ""
+}
+
+type Result struct {
+	OriginalSQL    string
+	RewrittenSQL   string
+	TriggeredRules []*TriggeredRule
+}
+
+func (r *Result) IsRewritten() bool {
+	for _, rule := range r.TriggeredRules {
+		if rule.IsRewritten() {
+			return true
+		}
+	}
+	return false
+}
+
+// the rules with a threshold use the same param key as the audit rules
+const ruleParamKey = rulepkg.DefaultSingleParamKeyName
+
+type rewriteContext struct {
+	// originalSQL is the SQL before rewritten, some rules check the text written by user.
+	originalSQL    string
+	params         params.Params
+	getCreateTable GetCreateTableFunc
+}
+
+func (c *rewriteContext) intParam(defaultValue int) int {
+	if c.params == nil {
+		return defaultValue
+	}
+	p := c.params.GetParam(ruleParamKey)
+	if p == nil || p.Int() <= 0 {
+		return defaultValue
+	}
+	return p.Int()
+}
+
+func (c *rewriteContext) createTable(table *ast.TableName) (*ast.CreateTableStmt, bool) {
+	if c.getCreateTable == nil || table == nil {
+		return nil, false
+	}
+	return c.getCreateTable(table)
+}
+
+// rewriteFunc checks the statement, the new statement is nil when the rule only gives a warning.
+type rewriteFunc func(c *rewriteContext, stmt ast.StmtNode) (newStmt ast.StmtNode, message string, fired bool)
+
+type rewriteRule struct {
+	code string
+	fn   rewriteFunc
+}
+
+// the rules are applied in order, the rules simplifying the statement go first
+var rewriteRules = []rewriteRule{
+	{optimization.RuleUseEqual4NullRewrite, rewriteEqual4Null},
+	{optimization.RuleNoWildcardInPredicateLikeWarning, rewriteNoWildcardLike},
+	{optimization.RuleUseNonstandardNotEqualOperator, checkNonstandardNotEqual},
+	{optimization.RuleDiffDataTypeInPredicateWrite, rewriteDiffDataTypeInPredicate},
+	{optimization.RuleFuncWithColumnInPredicate, rewriteFuncWithColumnInPredicate},
+	{optimization.RuleCntGtThanZeroRewrite, rewriteCntGtThanZero},
+	{optimization.RuleAllQualifierSubQueryRewrite, rewriteAllQualifierSubQuery},
+	{optimization.RuleQualifierSubQueryRewrite, rewriteQualifierSubQuery},
+	{optimization.RuleNotInNullableSubQueryRewrite, checkNotInNullableSubQuery},
+	{optimization.RuleDistinctEliminationRewrite, rewriteDistinctElimination},
+	{optimization.RuleOrderEliminationInSubqueryRewrite, rewriteOrderEliminationInSubquery},
+	{optimization.RuleInSubqueryRewrite, rewriteInSubquery},
+	{optimization.RuleExists2JoinRewrite, rewriteExists2Join},
+	{optimization.RuleQueryFoldingRewrite, rewriteQueryFolding},
+	{optimization.RuleFilterPredicatePushDownRewrite, rewriteFilterPredicatePushDown},
+	{optimization.RuleProjectionPushdownRewrite, rewriteProjectionPushdown},
+	{optimization.RuleOuter2InnerConversionRewrite, rewriteOuter2InnerConversion},
+	{optimization.RuleJoinEliminationRewrite, rewriteJoinElimination},
+	{optimization.RuleSATTCRewrite, rewriteSATTC},
+	{optimization.RuleHavingCond2WhereCondRewrite, rewriteHavingCond2WhereCond},
+	{optimization.RuleMaxMinAggRewrite, rewriteMaxMinAgg},
+	{optimization.RuleNPERewrite, rewriteNPE},
+	{optimization.RuleAddOrderByNullRewrite, rewriteAddOrderByNull},
+	{optimization.RuleMoveOrder2LeadingRewrite, rewriteMoveOrder2Leading},
+	{optimization.RuleGroupingFromDiffTablesRewrite, checkGroupingFromDiffTables},
+	{optimization.RuleOrderingFromDiffTablesRewrite, checkOrderingFromDiffTables},
+	{optimization.RuleDiffOrderingSpecTypeWarning, checkDiffOrderingSpecType},
+	{optimization.RuleLimitClausePushDownRewrite, rewriteLimitClausePushDown},
+	{optimization.RuleLargeOffset, checkLargeOffset},
+	{optimization.RuleOrCond4SelectRewrite, rewriteOrCond4Select},
+	{optimization.RuleOrCond4UpDeleteRewrite, checkOrCond4UpDelete},
+	{optimization.RuleDelete2TruncateRewrite, rewriteDelete2Truncate},
+}
+
+// SupportedRuleCodes returns the rule codes implemented by the engine.
+func SupportedRuleCodes() []string {
+	codes := make([]string, 0, len(rewriteRules))
+	for _, rule := range rewriteRules {
+		codes = append(codes, rule.code)
+	}
+	return codes
+}
+
+type Rewriter struct {
+	// rules is the map of enabled rule code to the params of rule.
+	rules          map[string]params.Params
+	getCreateTable GetCreateTableFunc
+}
+
+// NewRewriter creates a rewriter with the enabled rules, all rules are enabled when rules is nil.
+func NewRewriter(rules map[string]params.Params, getCreateTable GetCreateTableFunc) *Rewriter {
+	if rules == nil {
+		rules = map[string]params.Params{}
+		for _, rule := range rewriteRules {
+			rules[rule.code] = nil
+		}
+	}
+	return &Rewriter{
+		rules:          rules,
+		getCreateTable: getCreateTable,
+	}
+}
+
+// Rewrite applies the enabled rules to the SQL one by one, every rule works
+// on the SQL rewritten by the previous rules.
+func (r *Rewriter) Rewrite(sql string) (*Result, error) {
+	if _, err := parseOneStmt(sql); err != nil {
+		return nil, err
+	}
+	result := &Result{
+		OriginalSQL:  sql,
+		RewrittenSQL: sql,
+	}
+	for _, rule := range rewriteRules {
+		p, enabled := r.rules[rule.code]
+		if !enabled {
+			continue
+		}
+		// the rule may change the AST even if it does not fire, so parse the SQL for every rule
+		stmt, err := parseOneStmt(result.RewrittenSQL)
+		if err != nil {
+			return nil, err
+		}
+		newStmt, message, fired := rule.fn(&rewriteContext{originalSQL: sql, params: p, getCreateTable: r.getCreateTable}, stmt)
+		if !fired {
+			continue
+		}
+		triggered := &TriggeredRule{RuleCode: rule.code, Message: message}
+		if newStmt != nil {
+			rewritten, err := restore(newStmt)
+			if err != nil {
+				continue
+			}
+			// the rewritten SQL must be valid for the later rules
+			if _, err := parseOneStmt(rewritten); err != nil {
+				continue
+			}
+			if rewritten != result.RewrittenSQL {
+				triggered.RewrittenSQL = rewritten
+				result.RewrittenSQL = rewritten
+			}
+		}
+		result.TriggeredRules = append(result.TriggeredRules, triggered)
+	}
+	return result, nil
+}
+
+func parseOneStmt(sql string) (ast.StmtNode, error) {
+	stmts, err := util.ParseSql(sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) != 1 {
+		return nil, fmt.Errorf("expect one statement, but got %d", len(stmts))
+	}
+	if _, ok := stmts[0].(*ast.UnparsedStmt); ok {
+		return nil, fmt.Errorf("the statement is not supported by the parser")
+	}
+	return stmts[0], nil
+}
@@ -0,0 +1,180 @@
+package rewrite
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	optimization "github.com/actiontech/sqle/sqle/server/optimization/rule"
+	"github.com/pingcap/parser/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTables = map[string]string{
+	"t1": "CREATE TABLE t1 (id INT NOT NULL PRIMARY KEY, a INT, b VARCHAR(10), c INT)",
+	"t2": "CREATE TABLE t2 (id INT NOT NULL, a INT, b VARCHAR(10), c INT, PRIMARY KEY (id))",
+}
+
+func getTestCreateTable(table *ast.TableName) (*ast.CreateTableStmt, bool) {
+	sql, ok := testTables[table.Name.L]
+	if !ok {
+		return nil, false
+	}
+	stmt, err := util.ParseCreateTableStmt(sql)
+	return stmt, err == nil
+}
+
+func TestRewrite(t *testing.T) {
+	cases := []struct {
+		rule      string
+		sql       string
+		rewritten string // empty when the rule only gives a warning
+	}{
+		{optimization.RuleUseEqual4NullRewrite, "SELECT * FROM t1 WHERE a = NULL AND b <> NULL",
+			"SELECT * FROM `t1` WHERE `a` IS NULL AND `b` IS NOT NULL"},
+		{optimization.RuleNoWildcardInPredicateLikeWarning, "SELECT * FROM t1 WHERE b LIKE 'abc' AND c NOT LIKE 'x%'",
+			"SELECT * FROM `t1` WHERE `b`='abc' AND `c` NOT LIKE 'x%'"},
+		{optimization.RuleUseNonstandardNotEqualOperator, "SELECT * FROM t1 WHERE a != 1", ""},
+		{optimization.RuleDiffDataTypeInPredicateWrite, "SELECT * FROM t1 WHERE b = 123 AND a = '5'",
+			"SELECT * FROM `t1` WHERE `b`='123' AND `a`=5"},
+		{optimization.RuleFuncWithColumnInPredicate, "SELECT * FROM t1 WHERE a + 1 > 5 AND 10 = c - 2",
+			"SELECT * FROM `t1` WHERE `a`>5-1 AND 10+2=`c`"},
+		{optimization.RuleFuncWithColumnInPredicate, "SELECT * FROM t1 WHERE DATE(b) = '2020-01-01'", ""},
+		{optimization.RuleCntGtThanZeroRewrite, "SELECT * FROM t1 WHERE (SELECT COUNT(*) FROM t2 WHERE t2.a = t1.a) > 0",
+			"SELECT * FROM `t1` WHERE EXISTS (SELECT 1 FROM `t2` WHERE `t2`.`a`=`t1`.`a`)"},
+		{optimization.RuleAllQualifierSubQueryRewrite, "SELECT * FROM t1 WHERE a > ALL (SELECT a FROM t2) AND c <> ALL (SELECT c FROM t2)",
+			"SELECT * FROM `t1` WHERE `a`>ALL (SELECT `a` FROM `t2`) AND `c` NOT IN (SELECT `c` FROM `t2`)"},
+		// "> ALL" is true for the empty result, it is not equivalent to "> MAX"
+		{optimization.RuleAllQualifierSubQueryRewrite, "SELECT * FROM t1 WHERE a > ALL (SELECT id FROM t2)", ""},
+		{optimization.RuleQualifierSubQueryRewrite, "SELECT * FROM t1 WHERE a = ANY (SELECT a FROM t2) AND c > ANY (SELECT c FROM t2)",
+			"SELECT * FROM `t1` WHERE `a` IN (SELECT `a` FROM `t2`) AND `c`>(SELECT MIN(`c`) FROM `t2`)"},
+		{optimization.RuleNotInNullableSubQueryRewrite, "SELECT * FROM t1 WHERE a NOT IN (SELECT a FROM t2) AND c NOT IN (SELECT id FROM t2)",
+			"SELECT * FROM `t1` WHERE `a` NOT IN (SELECT `a` FROM `t2` WHERE `a` IS NOT NULL) AND `c` NOT IN (SELECT `id` FROM `t2`)"},
+		{optimization.RuleDistinctEliminationRewrite, "SELECT * FROM t1 WHERE EXISTS (SELECT DISTINCT a FROM t2 WHERE t2.a = t1.a)",
+			"SELECT * FROM `t1` WHERE EXISTS (SELECT `a` FROM `t2` WHERE `t2`.`a`=`t1`.`a`)"},
+		{optimization.RuleOrderEliminationInSubqueryRewrite, "SELECT * FROM t1 WHERE a IN (SELECT a FROM t2 ORDER BY a)",
+			"SELECT * FROM `t1` WHERE `a` IN (SELECT `a` FROM `t2`)"},
+		{optimization.RuleInSubqueryRewrite, "SELECT * FROM t1 WHERE a IN (SELECT a FROM t2 WHERE c = 1)",
+			"SELECT * FROM `t1` WHERE EXISTS (SELECT 1 FROM `t2` WHERE `c`=1 AND `t2`.`a`=`t1`.`a`)"},
+		{optimization.RuleExists2JoinRewrite, "SELECT t1.a FROM t1 WHERE EXISTS (SELECT 1 FROM t2 WHERE t2.id = t1.a AND t2.c = 1)",
+			"SELECT `t1`.`a` FROM `t1` JOIN `t2` ON `t2`.`id`=`t1`.`a` AND `t2`.`c`=1"},
+		{optimization.RuleQueryFoldingRewrite, "SELECT dt.a, dt.x FROM (SELECT a, b AS x FROM t1 WHERE c = 1) dt WHERE dt.a > 1 ORDER BY dt.x",
+			"SELECT `a`,`b` AS `x` FROM `t1` WHERE `c`=1 AND `a`>1 ORDER BY `b`"},
+		{optimization.RuleFilterPredicatePushDownRewrite, "SELECT dt.a, dt.cnt FROM (SELECT a, COUNT(*) AS cnt FROM t1 GROUP BY a) dt WHERE dt.a = 1",
+			"SELECT `dt`.`a`,`dt`.`cnt` FROM (SELECT `a`,COUNT(1) AS `cnt` FROM (`t1`) WHERE `a`=1 GROUP BY `a`) AS `dt`"},
+		{optimization.RuleProjectionPushdownRewrite, "SELECT dt.a FROM (SELECT a, COUNT(*) AS cnt FROM t1 GROUP BY a) dt",
+			"SELECT `dt`.`a` FROM (SELECT `a` FROM (`t1`) GROUP BY `a`) AS `dt`"},
+		{optimization.RuleOuter2InnerConversionRewrite, "SELECT t1.a FROM t1 LEFT JOIN t2 ON t1.a = t2.a WHERE t2.c = 1",
+			"SELECT `t1`.`a` FROM `t1` JOIN `t2` ON `t1`.`a`=`t2`.`a` WHERE `t2`.`c`=1"},
+		{optimization.RuleJoinEliminationRewrite, "SELECT t1.a FROM t1 LEFT JOIN t2 ON t1.a = t2.id WHERE t1.c = 1",
+			"SELECT `t1`.`a` FROM `t1` WHERE `t1`.`c`=1"},
+		{optimization.RuleSATTCRewrite, "SELECT * FROM t1 JOIN t2 ON t1.a = t2.a WHERE t2.a = 5",
+			"SELECT * FROM `t1` JOIN `t2` ON `t1`.`a`=`t2`.`a` WHERE `t2`.`a`=5 AND `t1`.`a`=5"},
+		{optimization.RuleSATTCRewrite, "SELECT * FROM t1 WHERE a = 1 AND a = 2", ""},
+		{optimization.RuleHavingCond2WhereCondRewrite, "SELECT a, COUNT(*) FROM t1 GROUP BY a HAVING a > 1 AND COUNT(*) > 2",
+			"SELECT `a`,COUNT(1) FROM `t1` WHERE `a`>1 GROUP BY `a` HAVING COUNT(1)>2"},
+		{optimization.RuleMaxMinAggRewrite, "SELECT * FROM t1 WHERE a = (SELECT MAX(c) FROM t2)",
+			"SELECT * FROM `t1` WHERE `a`=(SELECT `c` FROM `t2` WHERE `c` IS NOT NULL ORDER BY `c` DESC LIMIT 1)"},
+		{optimization.RuleNPERewrite, "SELECT a, SUM(c), AVG(c) AS av FROM t1 GROUP BY a",
+			"SELECT `a`,IFNULL(SUM(`c`), 0) AS `SUM(c)`,IFNULL(AVG(`c`), 0) AS `av` FROM `t1` GROUP BY `a`"},
+		{optimization.RuleAddOrderByNullRewrite, "SELECT a, COUNT(*) FROM t1 GROUP BY a",
+			"SELECT `a`,COUNT(1) FROM `t1` GROUP BY `a` ORDER BY NULL"},
+		{optimization.RuleMoveOrder2LeadingRewrite, "SELECT a, b FROM t1 GROUP BY a, b ORDER BY b",
+			"SELECT `a`,`b` FROM `t1` GROUP BY `b`,`a` ORDER BY `b`"},
+		{optimization.RuleGroupingFromDiffTablesRewrite, "SELECT t1.a, t2.b FROM t1 JOIN t2 ON t1.a = t2.a GROUP BY t1.a, t2.a, t1.b",
+			"SELECT `t1`.`a`,`t2`.`b` FROM `t1` JOIN `t2` ON `t1`.`a`=`t2`.`a` GROUP BY `t1`.`a`,`t1`.`b`"},
+		{optimization.RuleOrderingFromDiffTablesRewrite, "SELECT t1.a, t2.b FROM t1 JOIN t2 ON t1.a = t2.a ORDER BY t1.a, t2.b", ""},
+		{optimization.RuleDiffOrderingSpecTypeWarning, "SELECT * FROM t1 ORDER BY a, b DESC", ""},
+		{optimization.RuleLimitClausePushDownRewrite, "SELECT a FROM t1 UNION ALL SELECT a FROM t2 ORDER BY a LIMIT 10, 20",
+			"(SELECT `a` FROM `t1` ORDER BY `a` LIMIT 30) UNION ALL (SELECT `a` FROM `t2` ORDER BY `a` LIMIT 30) ORDER BY `a` LIMIT 10,20"},
+		{optimization.RuleLargeOffset, "SELECT * FROM t1 LIMIT 1000, 10", ""},
+		{optimization.RuleOrCond4SelectRewrite, "SELECT * FROM t1 WHERE a = 1 OR b = 'x'",
+			"SELECT * FROM `t1` WHERE `a`=1 UNION ALL SELECT * FROM `t1` WHERE `b`='x' AND (`a`=1) IS NOT TRUE"},
+		{optimization.RuleOrCond4UpDeleteRewrite, "UPDATE t1 SET c = 1 WHERE a = 1 OR b = 'x'", ""},
+		{optimization.RuleDelete2TruncateRewrite, "DELETE FROM t1", "TRUNCATE TABLE `t1`"},
+	}
+	for _, c := range cases {
+		t.Run(c.rule, func(t *testing.T) {
+			result, err := NewRewriter(map[string]params.Params{c.rule: nil}, getTestCreateTable).Rewrite(c.sql)
+			assert.NoError(t, err)
+			if assert.Len(t, result.TriggeredRules, 1) {
+				assert.Equal(t, c.rule, result.TriggeredRules[0].RuleCode)
+				assert.NotEmpty(t, result.TriggeredRules[0].Message)
+				assert.Equal(t, c.rewritten, result.TriggeredRules[0].RewrittenSQL)
+			}
+			if c.rewritten == "" {
+				assert.Equal(t, c.sql, result.RewrittenSQL)
+			} else {
+				assert.Equal(t, c.rewritten, result.RewrittenSQL)
+			}
+		})
+	}
+}
+
+func TestRewriteNotFired(t *testing.T) {
+	sqls := []string{
+		// the IN sub query can not be correlated when the outer column is ambiguous
+		"SELECT * FROM t1 JOIN t2 ON t1.id = t2.id WHERE a IN (SELECT a FROM t2)",
+		// the joined table is used by the query
+		"SELECT t1.a, t2.c FROM t1 LEFT JOIN t2 ON t1.a = t2.id",
+		// the OR condition on the same column can use index
+		"SELECT * FROM t1 WHERE a = 1 OR a = 2",
+		// the DELETE has condition
+		"DELETE FROM t1 WHERE a = 1",
+		// the offset does not exceed the threshold
+		"SELECT * FROM t1 LIMIT 10, 10",
+	}
+	for _, sql := range sqls {
+		result, err := NewRewriter(nil, getTestCreateTable).Rewrite(sql)
+		assert.NoError(t, err)
+		assert.Empty(t, result.TriggeredRules, sql)
+		assert.False(t, result.IsRewritten())
+	}
+}
+
+func TestRewriteNotEquivalent(t *testing.T) {
+	cases := []struct {
+		rule string
+		sql  string
+	}{
+		// the derived column is evaluated again if the condition is pushed down
+		{optimization.RuleFilterPredicatePushDownRewrite, "SELECT dt.r FROM (SELECT a, RAND() AS r FROM t1) dt WHERE dt.r < 0.5"},
+		{optimization.RuleFilterPredicatePushDownRewrite, "SELECT dt.u FROM (SELECT a, UUID() AS u FROM t1) dt WHERE dt.u > 'a'"},
+		{optimization.RuleFilterPredicatePushDownRewrite, "SELECT dt.n FROM (SELECT a, NOW() AS n FROM t1) dt WHERE dt.n > '2024-01-01'"},
+		{optimization.RuleFilterPredicatePushDownRewrite, "SELECT dt.v FROM (SELECT a, @v := @v + 1 AS v FROM t1) dt WHERE dt.v = 1"},
+		{optimization.RuleFilterPredicatePushDownRewrite, "SELECT dt.a FROM (SELECT a FROM t1 GROUP BY a) dt WHERE dt.a > RAND()"},
+		// the MAX sub query is not rewritten from "> ALL"
+		{optimization.RuleMaxMinAggRewrite, "SELECT * FROM t1 WHERE a > ALL (SELECT c FROM t2)"},
+		{optimization.RuleMaxMinAggRewrite, "SELECT * FROM t1 WHERE a < ALL (SELECT c FROM t2)"},
+	}
+	for _, c := range cases {
+		rules := map[string]params.Params{c.rule: nil, optimization.RuleAllQualifierSubQueryRewrite: nil}
+		result, err := NewRewriter(rules, getTestCreateTable).Rewrite(c.sql)
+		assert.NoError(t, err)
+		for _, triggered := range result.TriggeredRules {
+			assert.NotEqual(t, c.rule, triggered.RuleCode, c.sql)
+		}
+		assert.Equal(t, c.sql, result.RewrittenSQL, c.sql)
+	}
+}
+
+func TestRewriteWithoutMetadata(t *testing.T) {
+	// the rules depending on the table structures are skipped
+	result, err := NewRewriter(nil, nil).Rewrite("SELECT t1.a FROM t1 LEFT JOIN t2 ON t1.a = t2.id WHERE t1.b = 1")
+	assert.NoError(t, err)
+	assert.Empty(t, result.TriggeredRules)
+}
+
+func TestRewriteParams(t *testing.T) {
+	rules := map[string]params.Params{
+		optimization.RuleLargeOffset: {{Key: ruleParamKey, Value: "2000", Type: params.ParamTypeInt}},
+	}
+	result, err := NewRewriter(rules, nil).Rewrite("SELECT * FROM t1 LIMIT 1000, 10")
+	assert.NoError(t, err)
+	assert.Empty(t, result.TriggeredRules)
+}
+
+func TestRewriteInvalidSQL(t *testing.T) {
+	_, err := NewRewriter(nil, nil).Rewrite("SELECT * FROM")
+	assert.Error(t, err)
+}
@@ -0,0 +1,363 @@
+package rewrite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/opcode"
+)
+
+// rewriteAddOrderByNull adds ORDER BY NULL to the GROUP BY without ORDER BY,
+// the GROUP BY sorts the result implicitly before MySQL 5.7.
+func rewriteAddOrderByNull(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, unionBranches := selectStmts(stmt)
+	fired := false
+	for _, sel := range selects {
+		if sel.GroupBy == nil || sel.OrderBy != nil {
+			continue
+		}
+		if unionBranches[sel] && !sel.IsInBraces {
+			continue
+		}
+		sel.OrderBy = &ast.OrderByClause{Items: []*ast.ByItem{{Expr: newValue(nil)}}}
+		fired = true
+	}
+	return stmt, "MySQL 5.7之前的版本中GROUP BY会对结果隐式排序，已添加ORDER BY NULL取消排序", fired
+}
+
+func columnItems(items []*ast.ByItem) ([]*ast.ColumnNameExpr, bool) {
+	cols := make([]*ast.ColumnNameExpr, 0, len(items))
+	for _, item := range items {
+		col, ok := item.Expr.(*ast.ColumnNameExpr)
+		if !ok {
+			return nil, false
+		}
+		cols = append(cols, col)
+	}
+	return cols, true
+}
+
+// rewriteMoveOrder2Leading moves the GROUP BY columns used by ORDER BY to the
+// leading, so the grouping and the ordering can share one sort.
+func rewriteMoveOrder2Leading(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	fired := false
+	for _, sel := range selects {
+		if sel.GroupBy == nil || sel.OrderBy == nil || len(sel.OrderBy.Items) == 0 {
+			continue
+		}
+		orderCols, ok := columnItems(sel.OrderBy.Items)
+		if !ok || len(orderCols) > len(sel.GroupBy.Items) {
+			continue
+		}
+		if _, ok := columnItems(sel.GroupBy.Items); !ok {
+			continue
+		}
+		desc := sel.OrderBy.Items[0].Desc
+		groupItems := map[string]*ast.ByItem{}
+		for _, item := range sel.GroupBy.Items {
+			groupItems[exprKey(item.Expr)] = item
+		}
+		leading := make([]*ast.ByItem, 0, len(sel.GroupBy.Items))
+		used := map[string]struct{}{}
+		for i, col := range orderCols {
+			item, ok := groupItems[exprKey(col)]
+			if !ok || sel.OrderBy.Items[i].Desc != desc {
+				leading = nil
+				break
+			}
+			leading = append(leading, item)
+			used[exprKey(col)] = struct{}{}
+		}
+		if leading == nil {
+			continue
+		}
+		items := leading
+		for _, item := range sel.GroupBy.Items {
+			if _, ok := used[exprKey(item.Expr)]; !ok {
+				items = append(items, item)
+			}
+		}
+		changed := false
+		for i := range items {
+			if items[i] != sel.GroupBy.Items[i] {
+				changed = true
+			}
+		}
+		if changed {
+			sel.GroupBy.Items = items
+			fired = true
+		}
+	}
+	return stmt, "已调整GROUP BY字段的顺序使其与ORDER BY字段的顺序一致，分组和排序可以共用一次排序操作", fired
+}
+
+// equivalentColumns returns the columns equal to each other by the WHERE and
+// the inner join conditions.
+func equivalentColumns(sel *ast.SelectStmt) map[string][]*ast.ColumnNameExpr {
+	conditions := append(splitAnd(sel.Where), innerJoinConditions(sel)...)
+	equivalents := map[string][]*ast.ColumnNameExpr{}
+	for _, condition := range conditions {
+		b, ok := condition.(*ast.BinaryOperationExpr)
+		if !ok || b.Op != opcode.EQ {
+			continue
+		}
+		l, lIsCol := b.L.(*ast.ColumnNameExpr)
+		r, rIsCol := b.R.(*ast.ColumnNameExpr)
+		if lIsCol && rIsCol {
+			equivalents[exprKey(l)] = append(equivalents[exprKey(l)], r)
+			equivalents[exprKey(r)] = append(equivalents[exprKey(r)], l)
+		}
+	}
+	return equivalents
+}
+
+// unifyItemTables replaces the columns from other tables with the equivalent
+// columns from the table of the first item, it returns the tables of the items
+// and whether the items are from one table after replaced.
+func unifyItemTables(sel *ast.SelectStmt, items []*ast.ByItem) (newItems []*ast.ByItem, tables []string, unified bool, ok bool) {
+	if len(tableSources(sel)) < 2 {
+		return nil, nil, false, false
+	}
+	cols, ok := columnItems(items)
+	if !ok || len(cols) < 2 {
+		return nil, nil, false, false
+	}
+	tableSet := map[string]struct{}{}
+	for _, col := range cols {
+		if col.Name.Table.L == "" {
+			return nil, nil, false, false
+		}
+		if _, ok := tableSet[col.Name.Table.L]; !ok {
+			tableSet[col.Name.Table.L] = struct{}{}
+			tables = append(tables, col.Name.Table.O)
+		}
+	}
+	if len(tables) < 2 {
+		return nil, nil, false, false
+	}
+	target := cols[0].Name.Table.L
+	equivalents := equivalentColumns(sel)
+	replacements := map[int]*ast.ColumnNameExpr{}
+	for i, col := range cols {
+		if col.Name.Table.L == target {
+			continue
+		}
+		for _, equivalent := range equivalents[exprKey(col)] {
+			if equivalent.Name.Table.L == target {
+				replacements[i] = equivalent
+				break
+			}
+		}
+		if _, ok := replacements[i]; !ok {
+			return nil, tables, false, true
+		}
+	}
+	// the items equal to the previous items are redundant after replaced
+	seen := map[string]struct{}{}
+	for i, item := range items {
+		if col, ok := replacements[i]; ok {
+			item.Expr = col
+		}
+		if _, ok := seen[exprKey(item.Expr)]; ok {
+			continue
+		}
+		seen[exprKey(item.Expr)] = struct{}{}
+		newItems = append(newItems, item)
+	}
+	return newItems, tables, true, true
+}
+
+// checkGroupingFromDiffTables unifies the GROUP BY columns from different tables by the equalities.
+func checkGroupingFromDiffTables(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	rewritten, warned := []string{}, []string{}
+	for _, sel := range selects {
+		if sel.GroupBy == nil {
+			continue
+		}
+		items, tables, unified, ok := unifyItemTables(sel, sel.GroupBy.Items)
+		if !ok {
+			continue
+		}
+		if unified {
+			sel.GroupBy.Items = items
+			rewritten = append(rewritten, tables...)
+		} else {
+			warned = append(warned, tables...)
+		}
+	}
+	switch {
+	case len(rewritten) > 0:
+		return stmt, fmt.Sprintf("GROUP BY字段来自不同的表%s，已根据等值条件替换为来自同一张表的字段", quoteNames(rewritten)), true
+	case len(warned) > 0:
+		return nil, fmt.Sprintf("GROUP BY字段来自不同的表%s，无法利用索引的有序性避免排序", quoteNames(warned)), true
+	}
+	return nil, "", false
+}
+
+// checkOrderingFromDiffTables unifies the ORDER BY columns from different tables by the equalities.
+func checkOrderingFromDiffTables(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	rewritten, warned := []string{}, []string{}
+	for _, sel := range selects {
+		if sel.OrderBy == nil {
+			continue
+		}
+		items, tables, unified, ok := unifyItemTables(sel, sel.OrderBy.Items)
+		if !ok {
+			continue
+		}
+		if unified {
+			sel.OrderBy.Items = items
+			rewritten = append(rewritten, tables...)
+		} else {
+			warned = append(warned, tables...)
+		}
+	}
+	switch {
+	case len(rewritten) > 0:
+		return stmt, fmt.Sprintf("ORDER BY字段来自不同的表%s，已根据等值条件替换为来自同一张表的字段", quoteNames(rewritten)), true
+	case len(warned) > 0:
+		return nil, fmt.Sprintf("ORDER BY字段来自不同的表%s，无法利用索引避免排序", quoteNames(warned)), true
+	}
+	return nil, "", false
+}
+
+// checkDiffOrderingSpecType warns the ORDER BY mixing ASC and DESC.
+func checkDiffOrderingSpecType(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	fired := false
+	inspect(stmt, func(n ast.Node) bool {
+		order, ok := n.(*ast.OrderByClause)
+		if !ok {
+			return true
+		}
+		for _, item := range order.Items {
+			if item.Desc != order.Items[0].Desc {
+				fired = true
+			}
+		}
+		return !fired
+	})
+	if !fired {
+		return nil, "", false
+	}
+	return nil, "ORDER BY子句中的字段排序方向不一致，无法利用索引避免排序", true
+}
+
+// unionOutputColumn returns the expression of the branch selected as the column of UNION.
+func unionOutputColumn(branch *ast.SelectStmt, name string) (ast.ExprNode, bool) {
+	if branch.Fields == nil {
+		return nil, false
+	}
+	for _, field := range branch.Fields.Fields {
+		if field.AsName.L == name && field.Expr != nil {
+			return field.Expr, true
+		}
+		if col, ok := field.Expr.(*ast.ColumnNameExpr); ok && field.AsName.L == "" && col.Name.Name.L == name {
+			return col, true
+		}
+	}
+	return nil, false
+}
+
+// rewriteLimitClausePushDown pushes the LIMIT of UNION ALL down to its branches.
+func rewriteLimitClausePushDown(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	threshold := int64(c.intParam(1000))
+	fired := false
+	inspect(stmt, func(n ast.Node) bool {
+		union, ok := n.(*ast.UnionStmt)
+		if !ok || union.Limit == nil || union.SelectList == nil {
+			return true
+		}
+		count, ok := intValue(union.Limit.Count)
+		if !ok {
+			return true
+		}
+		var offset int64
+		if union.Limit.Offset != nil {
+			if offset, ok = intValue(union.Limit.Offset); !ok || offset > threshold {
+				return true
+			}
+		}
+		orders := make([][]*ast.ByItem, len(union.SelectList.Selects))
+		for i, branch := range union.SelectList.Selects {
+			if branch.Limit != nil || (i > 0 && branch.IsAfterUnionDistinct) {
+				return true
+			}
+			if union.OrderBy == nil {
+				continue
+			}
+			for _, item := range union.OrderBy.Items {
+				col, ok := item.Expr.(*ast.ColumnNameExpr)
+				if !ok || col.Name.Table.L != "" {
+					return true
+				}
+				expr, ok := unionOutputColumn(branch, col.Name.Name.L)
+				if !ok {
+					return true
+				}
+				orders[i] = append(orders[i], &ast.ByItem{Expr: expr, Desc: item.Desc})
+			}
+		}
+		for i, branch := range union.SelectList.Selects {
+			branch.IsInBraces = true
+			branch.OrderBy = nil
+			if len(orders[i]) > 0 {
+				branch.OrderBy = &ast.OrderByClause{Items: orders[i]}
+			}
+			branch.Limit = &ast.Limit{Count: newValue(count + offset)}
+		}
+		fired = true
+		return true
+	})
+	return stmt, "已将UNION ALL的LIMIT下推到各个分支，提前减少中间结果集的大小", fired
+}
+
+func exprText(node ast.Node) string {
+	var buf strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(format.RestoreKeyWordUppercase|format.RestoreStringSingleQuotes, &buf)); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// rewriteNPE wraps the SUM and AVG of the query result with IFNULL, they return NULL when all values are NULL.
+func rewriteNPE(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	var selects []*ast.SelectStmt
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		selects = []*ast.SelectStmt{s}
+	case *ast.UnionStmt:
+		if s.SelectList != nil {
+			// the names of the first branch are the names of result
+			selects = s.SelectList.Selects[:1]
+		}
+	}
+	fired := false
+	for _, sel := range selects {
+		if sel.Fields == nil {
+			continue
+		}
+		for _, field := range sel.Fields.Fields {
+			agg, ok := field.Expr.(*ast.AggregateFuncExpr)
+			if !ok {
+				continue
+			}
+			switch strings.ToLower(agg.F) {
+			case ast.AggFuncSum, ast.AggFuncAvg:
+			default:
+				continue
+			}
+			if field.AsName.L == "" {
+				field.AsName = model.NewCIStr(exprText(agg))
+			}
+			field.Expr = &ast.FuncCallExpr{FnName: model.NewCIStr("IFNULL"), Args: []ast.ExprNode{agg, newValue(0)}}
+			fired = true
+		}
+	}
+	return stmt, "SUM/AVG在聚合字段全为NULL时返回NULL，可能导致应用程序出现空指针异常，已使用IFNULL处理", fired
+}
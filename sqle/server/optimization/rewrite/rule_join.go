@@ -0,0 +1,491 @@
+package rewrite
+
+import (
+	"fmt"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/opcode"
+)
+
+// derivedTable returns the SELECT of the derived table.
+func derivedTable(source *ast.TableSource) (*ast.SelectStmt, bool) {
+	sel, ok := source.Source.(*ast.SelectStmt)
+	if !ok || source.AsName.L == "" || sel.Fields == nil || hasWildcard(sel) {
+		return nil, false
+	}
+	return sel, true
+}
+
+// derivedColumns maps the output names of the derived table to its fields.
+func derivedColumns(sel *ast.SelectStmt) map[string]*ast.SelectField {
+	fields := map[string]*ast.SelectField{}
+	for _, field := range sel.Fields.Fields {
+		name := field.AsName.L
+		if col, ok := field.Expr.(*ast.ColumnNameExpr); ok && name == "" {
+			name = col.Name.Name.L
+		}
+		if name != "" {
+			fields[name] = field
+		}
+	}
+	return fields
+}
+
+func selectClauses(sel *ast.SelectStmt) []ast.Node {
+	clauses := []ast.Node{}
+	if sel.Fields != nil {
+		clauses = append(clauses, sel.Fields)
+	}
+	if sel.Where != nil {
+		clauses = append(clauses, sel.Where)
+	}
+	if sel.GroupBy != nil {
+		clauses = append(clauses, sel.GroupBy)
+	}
+	if sel.Having != nil {
+		clauses = append(clauses, sel.Having)
+	}
+	if sel.OrderBy != nil {
+		clauses = append(clauses, sel.OrderBy)
+	}
+	return clauses
+}
+
+// rewriteQueryFolding merges the simple derived table into the query referencing it.
+func rewriteQueryFolding(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	fired := false
+	// fold from the inner most query
+	for i := len(selects) - 1; i >= 0; i-- {
+		if foldDerivedTable(selects[i]) {
+			fired = true
+		}
+	}
+	return stmt, "DT子查询只进行了简单的过滤和投影，已展开并与外部查询合并", fired
+}
+
+func foldDerivedTable(outer *ast.SelectStmt) bool {
+	source, ok := singleTableSource(outer.From)
+	if !ok {
+		return false
+	}
+	inner, ok := derivedTable(source)
+	if !ok || !isPlainSelect(inner) || inner.OrderBy != nil || inner.From == nil || len(inner.TableHints) != 0 {
+		return false
+	}
+	for _, field := range inner.Fields.Fields {
+		if _, ok := field.Expr.(*ast.ColumnNameExpr); !ok {
+			return false
+		}
+	}
+	clauses := selectClauses(outer)
+	for _, clause := range clauses {
+		if hasSubquery(clause) {
+			return false
+		}
+	}
+	fields := derivedColumns(inner)
+	aliases := map[string]struct{}{}
+	for _, field := range outer.Fields.Fields {
+		if field.AsName.L != "" {
+			aliases[field.AsName.L] = struct{}{}
+		}
+	}
+	dtName := source.AsName.L
+	mapping := map[*ast.ColumnNameExpr]*ast.ColumnNameExpr{}
+	valid := true
+	for _, clause := range clauses {
+		inspect(clause, func(n ast.Node) bool {
+			col, ok := n.(*ast.ColumnNameExpr)
+			if !ok {
+				return true
+			}
+			if col.Name.Table.L != "" && col.Name.Table.L != dtName {
+				valid = false
+				return false
+			}
+			field, ok := fields[col.Name.Name.L]
+			if !ok {
+				// the alias of outer field is kept
+				if _, isAlias := aliases[col.Name.Name.L]; !isAlias || col.Name.Table.L != "" {
+					valid = false
+				}
+				return true
+			}
+			mapping[col] = field.Expr.(*ast.ColumnNameExpr)
+			return true
+		})
+	}
+	if !valid {
+		return false
+	}
+
+	newFields := make([]*ast.SelectField, 0, len(outer.Fields.Fields))
+	for _, field := range outer.Fields.Fields {
+		if field.WildCard != nil {
+			for _, innerField := range inner.Fields.Fields {
+				newFields = append(newFields, &ast.SelectField{Expr: innerField.Expr, AsName: innerField.AsName})
+			}
+			continue
+		}
+		// keep the name of result column
+		if col, ok := field.Expr.(*ast.ColumnNameExpr); ok && field.AsName.L == "" {
+			if mapped, ok := mapping[col]; ok && mapped.Name.Name.L != col.Name.Name.L {
+				field.AsName = col.Name.Name
+			}
+		}
+		newFields = append(newFields, field)
+	}
+	outer.Fields.Fields = newFields
+	replace := func(n ast.Node) ast.Node {
+		if col, ok := n.(*ast.ColumnNameExpr); ok {
+			if mapped, ok := mapping[col]; ok {
+				return mapped
+			}
+		}
+		return n
+	}
+	for _, field := range outer.Fields.Fields {
+		field.Expr = replaceExpr(field.Expr, replace)
+	}
+	outer.Where = joinAnd(append(splitAnd(inner.Where), splitAnd(replaceExpr(outer.Where, replace))...)...)
+	if outer.GroupBy != nil {
+		replaceNodes(outer.GroupBy, replace)
+	}
+	if outer.Having != nil {
+		replaceNodes(outer.Having, replace)
+	}
+	if outer.OrderBy != nil {
+		replaceNodes(outer.OrderBy, replace)
+	}
+	outer.From = inner.From
+	return true
+}
+
+// hasOuterJoin checks the FROM clause has LEFT or RIGHT JOIN.
+func hasOuterJoin(from *ast.TableRefsClause) bool {
+	found := false
+	if from == nil {
+		return false
+	}
+	inspect(from.TableRefs, func(n ast.Node) bool {
+		if j, ok := n.(*ast.Join); ok && j.Right != nil && (j.Tp == ast.LeftJoin || j.Tp == ast.RightJoin) {
+			found = true
+		}
+		_, isSource := n.(*ast.TableSource)
+		return !found && !isSource
+	})
+	return found
+}
+
+// rewriteFilterPredicatePushDown pushes the WHERE conditions on the columns of
+// derived table down to the derived table.
+func rewriteFilterPredicatePushDown(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	fired := false
+	for _, outer := range selects {
+		if outer.Where == nil || outer.From == nil || hasOuterJoin(outer.From) {
+			continue
+		}
+		sources := tableSources(outer)
+		for _, source := range sources {
+			inner, ok := derivedTable(source)
+			if !ok || inner.Limit != nil || len(inner.WindowSpecs) != 0 {
+				continue
+			}
+			groupColumns := map[string]struct{}{}
+			if inner.GroupBy != nil {
+				for _, item := range inner.GroupBy.Items {
+					groupColumns[exprKey(item.Expr)] = struct{}{}
+				}
+			}
+			fields := derivedColumns(inner)
+			dtName := source.AsName.L
+			// mapColumns returns the columns of derived table used by the condition
+			mapColumns := func(expr ast.ExprNode) (map[*ast.ColumnNameExpr]ast.ExprNode, bool) {
+				if hasSubquery(expr) || hasAggregate(expr) || !isDeterministic(expr) {
+					return nil, false
+				}
+				mapping := map[*ast.ColumnNameExpr]ast.ExprNode{}
+				valid := true
+				inspect(expr, func(n ast.Node) bool {
+					col, ok := n.(*ast.ColumnNameExpr)
+					if !ok {
+						return true
+					}
+					if col.Name.Table.L != dtName && !(col.Name.Table.L == "" && len(sources) == 1) {
+						valid = false
+						return false
+					}
+					field, ok := fields[col.Name.Name.L]
+					// the non-deterministic column is evaluated again if the condition is pushed down
+					if !ok || field.Expr == nil || hasAggregate(field.Expr) || !isDeterministic(field.Expr) {
+						valid = false
+						return false
+					}
+					if inner.GroupBy != nil {
+						if _, ok := groupColumns[exprKey(field.Expr)]; !ok {
+							valid = false
+							return false
+						}
+					}
+					mapping[col] = field.Expr
+					return true
+				})
+				return mapping, valid && len(mapping) > 0
+			}
+
+			kept, pushed := []ast.ExprNode{}, []ast.ExprNode{}
+			for _, conjunct := range splitAnd(outer.Where) {
+				mapping, ok := mapColumns(conjunct)
+				if !ok {
+					kept = append(kept, conjunct)
+					continue
+				}
+				pushed = append(pushed, replaceExpr(conjunct, func(n ast.Node) ast.Node {
+					if col, ok := n.(*ast.ColumnNameExpr); ok {
+						if mapped, ok := mapping[col]; ok {
+							return mapped
+						}
+					}
+					return n
+				}))
+			}
+			if len(pushed) == 0 {
+				continue
+			}
+			inner.Where = joinAnd(append(splitAnd(inner.Where), pushed...)...)
+			outer.Where = joinAnd(kept...)
+			fired = true
+			if outer.Where == nil {
+				break
+			}
+		}
+	}
+	return stmt, "已将外部查询中DT子查询字段上的过滤条件下推到DT子查询中，提前减少数据处理量", fired
+}
+
+// rewriteProjectionPushdown removes the fields of derived table which are not used by the outer query.
+func rewriteProjectionPushdown(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	removed := []string{}
+	for _, outer := range selects {
+		if outer.From == nil {
+			continue
+		}
+		wildcard := false
+		for _, field := range outer.Fields.Fields {
+			if field.WildCard != nil {
+				wildcard = true
+			}
+		}
+		if wildcard {
+			continue
+		}
+		for _, source := range tableSources(outer) {
+			inner, ok := derivedTable(source)
+			if !ok || inner.Distinct || len(inner.Fields.Fields) < 2 {
+				continue
+			}
+			used := map[string]struct{}{}
+			inspect(outer, func(n ast.Node) bool {
+				if n == source {
+					return false
+				}
+				if col, ok := n.(*ast.ColumnNameExpr); ok && (col.Name.Table.L == "" || col.Name.Table.L == source.AsName.L) {
+					used[col.Name.Name.L] = struct{}{}
+				}
+				return true
+			})
+			positional := false
+			clauses := []ast.Node{}
+			if inner.GroupBy != nil {
+				clauses = append(clauses, inner.GroupBy)
+			}
+			if inner.Having != nil {
+				clauses = append(clauses, inner.Having)
+			}
+			if inner.OrderBy != nil {
+				clauses = append(clauses, inner.OrderBy)
+			}
+			for _, clause := range clauses {
+				inspect(clause, func(n ast.Node) bool {
+					switch e := n.(type) {
+					case *ast.PositionExpr:
+						positional = true
+					case *ast.ColumnNameExpr:
+						// the alias of field may be used
+						if e.Name.Table.L == "" {
+							used[e.Name.Name.L] = struct{}{}
+						}
+					}
+					return true
+				})
+			}
+			if positional {
+				continue
+			}
+			fields := make([]*ast.SelectField, 0, len(inner.Fields.Fields))
+			for _, field := range inner.Fields.Fields {
+				name := field.AsName.L
+				if col, ok := field.Expr.(*ast.ColumnNameExpr); ok && name == "" {
+					name = col.Name.Name.L
+				}
+				if _, ok := used[name]; name != "" && !ok && !hasSubquery(field.Expr) {
+					removedName := field.AsName.O
+					if removedName == "" {
+						removedName = exprText(field.Expr)
+					}
+					removed = append(removed, fmt.Sprintf("%s.%s", source.AsName.O, removedName))
+					continue
+				}
+				fields = append(fields, field)
+			}
+			if len(fields) == 0 {
+				fields = inner.Fields.Fields[:1]
+			}
+			inner.Fields.Fields = fields
+		}
+	}
+	if len(removed) == 0 {
+		return nil, "", false
+	}
+	return stmt, fmt.Sprintf("DT子查询中的字段%s没有被外部查询使用，已删除", quoteNames(removed)), true
+}
+
+// rewriteOuter2InnerConversion converts the outer join to inner join when the
+// WHERE conditions reject the NULL of the inner table.
+func rewriteOuter2InnerConversion(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	converted := []string{}
+	for _, sel := range selects {
+		if sel.Where == nil || sel.From == nil {
+			continue
+		}
+		conjuncts := splitAnd(sel.Where)
+		rejected := func(name string) bool {
+			if name == "" {
+				return false
+			}
+			for _, conjunct := range conjuncts {
+				if isNullRejecting(conjunct, name) {
+					return true
+				}
+			}
+			return false
+		}
+		var visit func(node ast.ResultSetNode)
+		visit = func(node ast.ResultSetNode) {
+			j, ok := node.(*ast.Join)
+			if !ok || j.Right == nil {
+				return
+			}
+			var inner ast.ResultSetNode
+			switch j.Tp {
+			case ast.LeftJoin:
+				inner = j.Right
+			case ast.RightJoin:
+				inner = j.Left
+			}
+			if source, ok := inner.(*ast.TableSource); ok && rejected(sourceName(source)) {
+				j.Tp = ast.CrossJoin
+				converted = append(converted, sourceName(source))
+			}
+			visit(j.Left)
+			visit(j.Right)
+		}
+		visit(sel.From.TableRefs)
+	}
+	if len(converted) == 0 {
+		return nil, "", false
+	}
+	return stmt, fmt.Sprintf("WHERE条件拒绝了外连接表%s的NULL值，已将外连接转换为内连接", quoteNames(converted)), true
+}
+
+// rewriteJoinElimination removes the LEFT JOIN on the unique key of the table not used by the query.
+func rewriteJoinElimination(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	eliminated := []string{}
+	for _, sel := range selects {
+		if sel.From == nil || sel.From.TableRefs == nil {
+			continue
+		}
+		j := sel.From.TableRefs
+		if j.Right == nil || j.Tp != ast.LeftJoin || j.On == nil || len(j.Using) != 0 || j.NaturalJoin {
+			continue
+		}
+		right, ok := j.Right.(*ast.TableSource)
+		if !ok {
+			continue
+		}
+		tableName, ok := right.Source.(*ast.TableName)
+		if !ok {
+			continue
+		}
+		name := sourceName(right)
+		table, ok := c.createTable(tableName)
+		if !ok || !joinOnUniqueKey(j.On.Expr, name, table) {
+			continue
+		}
+		if usesTable(sel, j, name, table) {
+			continue
+		}
+		left, ok := j.Left.(*ast.Join)
+		if !ok {
+			left = &ast.Join{Left: j.Left}
+		}
+		sel.From.TableRefs = left
+		eliminated = append(eliminated, right.AsName.O)
+		if right.AsName.L == "" {
+			eliminated[len(eliminated)-1] = tableName.Name.O
+		}
+	}
+	if len(eliminated) == 0 {
+		return nil, "", false
+	}
+	return stmt, fmt.Sprintf("表%s通过唯一键左连接且没有被查询使用，不影响查询结果，已删除该表连接", quoteNames(eliminated)), true
+}
+
+// joinOnUniqueKey checks the join condition is an equality on the unique key of the table.
+func joinOnUniqueKey(on ast.ExprNode, name string, table *ast.CreateTableStmt) bool {
+	b, ok := on.(*ast.BinaryOperationExpr)
+	if !ok || b.Op != opcode.EQ {
+		return false
+	}
+	l, lIsCol := b.L.(*ast.ColumnNameExpr)
+	r, rIsCol := b.R.(*ast.ColumnNameExpr)
+	if !lIsCol || !rIsCol {
+		return false
+	}
+	if r.Name.Table.L == name {
+		l, r = r, l
+	}
+	return l.Name.Table.L == name && r.Name.Table.L != "" && r.Name.Table.L != name && isUniqueColumn(table, l.Name.Name.L)
+}
+
+// usesTable checks the columns of table are used by the query besides the join condition.
+func usesTable(sel *ast.SelectStmt, join *ast.Join, name string, table *ast.CreateTableStmt) bool {
+	used := false
+	for _, field := range sel.Fields.Fields {
+		if field.WildCard != nil && (field.WildCard.Table.L == "" || field.WildCard.Table.L == name) {
+			return true
+		}
+	}
+	inspect(sel, func(n ast.Node) bool {
+		if n == join.On {
+			return false
+		}
+		col, ok := n.(*ast.ColumnNameExpr)
+		if !ok {
+			return !used
+		}
+		if col.Name.Table.L == name {
+			used = true
+		}
+		if _, exist := getColumnDef(table, col.Name.Name.L); exist && col.Name.Table.L == "" {
+			used = true
+		}
+		return !used
+	})
+	return used
+}
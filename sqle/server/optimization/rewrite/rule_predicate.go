@@ -0,0 +1,531 @@
+package rewrite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/opcode"
+	"github.com/pingcap/parser/types"
+)
+
+// rewriteEqual4Null rewrites "= NULL" to "IS NULL" and "<> NULL" to "IS NOT NULL".
+func rewriteEqual4Null(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	fired := false
+	replaceNodes(stmt, func(n ast.Node) ast.Node {
+		b, ok := n.(*ast.BinaryOperationExpr)
+		if !ok || (b.Op != opcode.EQ && b.Op != opcode.NE) {
+			return n
+		}
+		var expr ast.ExprNode
+		switch {
+		case isNullValue(b.R) && !isNullValue(b.L):
+			expr = b.L
+		case isNullValue(b.L) && !isNullValue(b.R):
+			expr = b.R
+		default:
+			return n
+		}
+		fired = true
+		return &ast.IsNullExpr{Expr: expr, Not: b.Op == opcode.NE}
+	})
+	return stmt, "使用=NULL或<>NULL判断空值的条件永远不为真，已改写为IS NULL或IS NOT NULL", fired
+}
+
+// rewriteNoWildcardLike rewrites the LIKE without wildcard to equal.
+func rewriteNoWildcardLike(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	fired := false
+	replaceNodes(stmt, func(n ast.Node) ast.Node {
+		like, ok := n.(*ast.PatternLikeExpr)
+		if !ok {
+			return n
+		}
+		v, ok := like.Pattern.(ast.ValueExpr)
+		if !ok {
+			return n
+		}
+		pattern, ok := v.GetValue().(string)
+		if !ok || strings.ContainsAny(pattern, "%_") {
+			return n
+		}
+		fired = true
+		op := opcode.EQ
+		if like.Not {
+			op = opcode.NE
+		}
+		return &ast.BinaryOperationExpr{Op: op, L: like.Expr, R: like.Pattern}
+	})
+	return stmt, "LIKE条件中没有通配符，已改写为等值条件", fired
+}
+
+// checkNonstandardNotEqual checks the text written by user, the parser does not keep the operator.
+func checkNonstandardNotEqual(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	if !containsOperator(c.originalSQL, "!=") {
+		return nil, "", false
+	}
+	return nil, "'!='是非标准的不等于运算符，建议使用'<>'", true
+}
+
+// containsOperator searches the operator out of the quoted strings and comments.
+func containsOperator(sql, op string) bool {
+	for i := 0; i < len(sql); i++ {
+		switch ch := sql[i]; {
+		case ch == '\'' || ch == '"' || ch == '`':
+			for i++; i < len(sql) && sql[i] != ch; i++ {
+				if sql[i] == '\\' && ch != '`' {
+					i++
+				}
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return false
+			}
+			i += end + 3
+		case strings.HasPrefix(sql[i:], "-- ") || ch == '#':
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return false
+			}
+			i += end
+		case strings.HasPrefix(sql[i:], op):
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteDiffDataTypeInPredicate converts the constant to the type of the column compared with it.
+func rewriteDiffDataTypeInPredicate(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	rewritten, warnings := []string{}, []string{}
+	convert := func(sources []*ast.TableSource, col *ast.ColumnNameExpr, value ast.ExprNode) (ast.ExprNode, bool) {
+		v, ok := value.(ast.ValueExpr)
+		if !ok || v.GetValue() == nil {
+			return nil, false
+		}
+		def, _, ok := c.resolveColumn(sources, col.Name)
+		if !ok || def.Tp == nil {
+			return nil, false
+		}
+		switch def.Tp.EvalType() {
+		case types.ETString:
+			if _, isString := v.GetValue().(string); isString {
+				return nil, false
+			}
+			rewritten = append(rewritten, col.Name.Name.O)
+			return newValue(exprKey(v)), true
+		case types.ETInt, types.ETReal, types.ETDecimal:
+			s, isString := v.GetValue().(string)
+			if !isString {
+				return nil, false
+			}
+			if i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err == nil {
+				rewritten = append(rewritten, col.Name.Name.O)
+				return newValue(i), true
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+				rewritten = append(rewritten, col.Name.Name.O)
+				return newValue(f), true
+			}
+			warnings = append(warnings, col.Name.Name.O)
+		}
+		return nil, false
+	}
+
+	for _, scope := range predicateScopes(stmt) {
+		sources := scope.sources
+		*scope.where = replaceOuterExpr(*scope.where, func(n ast.Node) ast.Node {
+			switch e := n.(type) {
+			case *ast.BinaryOperationExpr:
+				if !isCompareOp(e.Op) {
+					return n
+				}
+				if col, ok := e.L.(*ast.ColumnNameExpr); ok {
+					if value, ok := convert(sources, col, e.R); ok {
+						e.R = value
+					}
+				} else if col, ok := e.R.(*ast.ColumnNameExpr); ok {
+					if value, ok := convert(sources, col, e.L); ok {
+						e.L = value
+					}
+				}
+			case *ast.PatternInExpr:
+				col, ok := e.Expr.(*ast.ColumnNameExpr)
+				if !ok {
+					return n
+				}
+				for i, item := range e.List {
+					if value, ok := convert(sources, col, item); ok {
+						e.List[i] = value
+					}
+				}
+			}
+			return n
+		})
+	}
+	switch {
+	case len(rewritten) > 0:
+		return stmt, fmt.Sprintf("字段%s与比较值的数据类型不一致，会引发隐式类型转换导致索引失效，已将比较值转换为字段的类型", quoteNames(rewritten)), true
+	case len(warnings) > 0:
+		return nil, fmt.Sprintf("字段%s与比较值的数据类型不一致，会引发隐式类型转换导致索引失效", quoteNames(warnings)), true
+	}
+	return nil, "", false
+}
+
+func quoteNames(names []string) string {
+	quoted := make([]string, 0, len(names))
+	seen := map[string]struct{}{}
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		quoted = append(quoted, fmt.Sprintf("`%s`", name))
+	}
+	return strings.Join(quoted, ",")
+}
+
+// rewriteFuncWithColumnInPredicate moves the addition and subtraction on the
+// column to the constant side, the other operations on column are warned.
+func rewriteFuncWithColumnInPredicate(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	rewritten, warnings := []string{}, []string{}
+	// moveArithmetic returns the column and the constant expression after the arithmetic moved
+	moveArithmetic := func(expr, constant ast.ExprNode) (ast.ExprNode, ast.ExprNode, bool) {
+		b, ok := expr.(*ast.BinaryOperationExpr)
+		if !ok || (b.Op != opcode.Plus && b.Op != opcode.Minus) {
+			return nil, nil, false
+		}
+		col, isCol := b.L.(*ast.ColumnNameExpr)
+		if isCol && isConstant(b.R) {
+			op := opcode.Minus
+			if b.Op == opcode.Minus {
+				op = opcode.Plus
+			}
+			return col, &ast.BinaryOperationExpr{Op: op, L: constant, R: b.R}, true
+		}
+		// "constant - column" reverses the comparison, it is left to user
+		col, isCol = b.R.(*ast.ColumnNameExpr)
+		if isCol && isConstant(b.L) && b.Op == opcode.Plus {
+			return col, &ast.BinaryOperationExpr{Op: opcode.Minus, L: constant, R: b.L}, true
+		}
+		return nil, nil, false
+	}
+	operatedOnColumn := func(expr ast.ExprNode) bool {
+		if _, ok := expr.(*ast.ColumnNameExpr); ok {
+			return false
+		}
+		return len(columns(expr)) > 0 && !hasSubquery(expr) && !hasAggregate(expr)
+	}
+
+	for _, scope := range predicateScopes(stmt) {
+		*scope.where = replaceOuterExpr(*scope.where, func(n ast.Node) ast.Node {
+			b, ok := n.(*ast.BinaryOperationExpr)
+			if !ok || !isCompareOp(b.Op) {
+				return n
+			}
+			if isConstant(b.R) {
+				if col, constant, ok := moveArithmetic(b.L, b.R); ok {
+					rewritten = append(rewritten, exprKey(b.L))
+					b.L, b.R = col, constant
+					return b
+				}
+			} else if isConstant(b.L) {
+				if col, constant, ok := moveArithmetic(b.R, b.L); ok {
+					rewritten = append(rewritten, exprKey(b.R))
+					b.L, b.R = constant, col
+					return b
+				}
+			}
+			for _, side := range []ast.ExprNode{b.L, b.R} {
+				if operatedOnColumn(side) {
+					warnings = append(warnings, exprKey(side))
+				}
+			}
+			return n
+		})
+	}
+	switch {
+	case len(rewritten) > 0:
+		return stmt, fmt.Sprintf("条件中的表达式%s在字段上进行运算会导致索引失效，已将运算转换到常量一侧", quoteNames(rewritten)), true
+	case len(warnings) > 0:
+		return nil, fmt.Sprintf("条件中的表达式%s在字段上进行运算会导致索引失效，建议将运算转换到常量一侧", quoteNames(warnings)), true
+	}
+	return nil, "", false
+}
+
+// rewriteSATTC infers the new constant conditions by the transitive equalities
+// and finds the contradictory equalities.
+func rewriteSATTC(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	inferred, contradictions := []string{}, []string{}
+	for _, sel := range selects {
+		if sel.Where == nil {
+			continue
+		}
+		conjuncts := splitAnd(sel.Where)
+		conditions := append(append([]ast.ExprNode{}, conjuncts...), innerJoinConditions(sel)...)
+		constants := map[string] /*column*/ ast.ExprNode{}
+		cols := map[string]*ast.ColumnNameExpr{}
+		type equality struct{ l, r string }
+		equalities := []equality{}
+		for _, condition := range conditions {
+			b, ok := condition.(*ast.BinaryOperationExpr)
+			if !ok || b.Op != opcode.EQ {
+				continue
+			}
+			lCol, lIsCol := b.L.(*ast.ColumnNameExpr)
+			rCol, rIsCol := b.R.(*ast.ColumnNameExpr)
+			switch {
+			case lIsCol && rIsCol:
+				cols[exprKey(lCol)], cols[exprKey(rCol)] = lCol, rCol
+				equalities = append(equalities, equality{exprKey(lCol), exprKey(rCol)})
+			case lIsCol && isConstant(b.R):
+				key := exprKey(lCol)
+				if exist, ok := constants[key]; ok && isDifferentValue(exist, b.R) {
+					contradictions = append(contradictions, key)
+				}
+				cols[key], constants[key] = lCol, b.R
+			case rIsCol && isConstant(b.L):
+				key := exprKey(rCol)
+				if exist, ok := constants[key]; ok && isDifferentValue(exist, b.L) {
+					contradictions = append(contradictions, key)
+				}
+				cols[key], constants[key] = rCol, b.L
+			}
+		}
+		added := []ast.ExprNode{}
+		for changed := true; changed; {
+			changed = false
+			for _, e := range equalities {
+				for _, pair := range [][2]string{{e.l, e.r}, {e.r, e.l}} {
+					from, to := pair[0], pair[1]
+					constant, ok := constants[from]
+					if !ok {
+						continue
+					}
+					if exist, ok := constants[to]; ok {
+						if isDifferentValue(exist, constant) {
+							contradictions = append(contradictions, to)
+						}
+						continue
+					}
+					constants[to] = constant
+					added = append(added, &ast.BinaryOperationExpr{Op: opcode.EQ, L: cols[to], R: constant})
+					inferred = append(inferred, to)
+					changed = true
+				}
+			}
+		}
+		if len(added) > 0 {
+			sel.Where = joinAnd(append(conjuncts, added...)...)
+		}
+	}
+	switch {
+	case len(contradictions) > 0:
+		return nil, fmt.Sprintf("字段%s的等值条件相互矛盾，查询条件永远为假", quoteNames(contradictions)), true
+	case len(inferred) > 0:
+		return stmt, fmt.Sprintf("根据等值传递为字段%s推导出了新的常量条件", quoteNames(inferred)), true
+	}
+	return nil, "", false
+}
+
+func isDifferentValue(a, b ast.ExprNode) bool {
+	_, aIsValue := a.(ast.ValueExpr)
+	_, bIsValue := b.(ast.ValueExpr)
+	return aIsValue && bIsValue && exprKey(a) != exprKey(b)
+}
+
+// rewriteHavingCond2WhereCond moves the HAVING conditions on the grouping columns to WHERE.
+func rewriteHavingCond2WhereCond(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	fired := false
+	for _, sel := range selects {
+		if sel.GroupBy == nil || sel.Having == nil {
+			continue
+		}
+		groupColumns := map[string]struct{}{}
+		for _, item := range sel.GroupBy.Items {
+			if col, ok := item.Expr.(*ast.ColumnNameExpr); ok {
+				groupColumns[exprKey(col)] = struct{}{}
+			}
+		}
+		aliases := map[string]struct{}{}
+		for _, field := range sel.Fields.Fields {
+			if field.AsName.L != "" {
+				aliases[field.AsName.L] = struct{}{}
+			}
+		}
+		movable := func(expr ast.ExprNode) bool {
+			if hasAggregate(expr) || hasSubquery(expr) {
+				return false
+			}
+			cols := columns(expr)
+			for _, col := range cols {
+				if _, ok := groupColumns[exprKey(col)]; !ok {
+					return false
+				}
+				// the alias of field can only be referenced in HAVING
+				if _, ok := aliases[col.Name.L]; ok && col.Table.L == "" {
+					return false
+				}
+			}
+			return len(cols) > 0
+		}
+		kept, moved := []ast.ExprNode{}, []ast.ExprNode{}
+		for _, conjunct := range splitAnd(sel.Having.Expr) {
+			if movable(conjunct) {
+				moved = append(moved, conjunct)
+			} else {
+				kept = append(kept, conjunct)
+			}
+		}
+		if len(moved) == 0 {
+			continue
+		}
+		fired = true
+		sel.Where = joinAnd(append(splitAnd(sel.Where), moved...)...)
+		if len(kept) == 0 {
+			sel.Having = nil
+		} else {
+			sel.Having.Expr = joinAnd(kept...)
+		}
+	}
+	return stmt, "HAVING子句中分组字段上的条件可以在分组前过滤数据，已下推到WHERE子句", fired
+}
+
+// orDisjuncts returns the disjuncts of the OR condition whose disjuncts use different columns.
+func orDisjuncts(expr ast.ExprNode) ([]ast.ExprNode, bool) {
+	disjuncts := splitOr(expr)
+	if len(disjuncts) < 2 {
+		return nil, false
+	}
+	columnSets := map[string]struct{}{}
+	for _, d := range disjuncts {
+		if hasSubquery(d) {
+			return nil, false
+		}
+		keys := []string{}
+		for _, col := range columns(d) {
+			keys = append(keys, exprKey(col))
+		}
+		if len(keys) == 0 {
+			return nil, false
+		}
+		columnSets[strings.Join(keys, ",")] = struct{}{}
+	}
+	return disjuncts, len(columnSets) > 1
+}
+
+// rewriteOrCond4Select rewrites the OR condition on different columns to UNION,
+// every branch excludes the rows matched by the previous branches.
+func rewriteOrCond4Select(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	const maxBranches = 4
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || sel.Where == nil || sel.From == nil || sel.OrderBy != nil || sel.Limit != nil ||
+		sel.GroupBy != nil || sel.Having != nil || fieldsHaveAggregate(sel) || len(sel.WindowSpecs) != 0 ||
+		sel.LockTp != ast.SelectLockNone || sel.SelectIntoOpt != nil {
+		return nil, "", false
+	}
+	orIndex := -1
+	for i, conjunct := range splitAnd(sel.Where) {
+		disjuncts, ok := orDisjuncts(conjunct)
+		if !ok || len(disjuncts) > maxBranches {
+			continue
+		}
+		if orIndex >= 0 {
+			// only one OR condition is rewritten
+			return nil, "", false
+		}
+		orIndex = i
+	}
+	if orIndex < 0 {
+		return nil, "", false
+	}
+
+	text, err := restore(sel)
+	if err != nil {
+		return nil, "", false
+	}
+	branchCount := len(splitOr(splitAnd(sel.Where)[orIndex]))
+	branches := make([]*ast.SelectStmt, 0, branchCount)
+	for i := 0; i < branchCount; i++ {
+		// every branch needs its own AST
+		node, err := parseOneStmt(text)
+		if err != nil {
+			return nil, "", false
+		}
+		branch := node.(*ast.SelectStmt)
+		conjuncts := splitAnd(branch.Where)
+		disjuncts := splitOr(conjuncts[orIndex])
+		where := make([]ast.ExprNode, 0, len(conjuncts)+i)
+		where = append(where, conjuncts[:orIndex]...)
+		where = append(where, disjuncts[i])
+		if !sel.Distinct {
+			for _, previous := range disjuncts[:i] {
+				where = append(where, &ast.IsTruthExpr{Expr: &ast.ParenthesesExpr{Expr: previous}, Not: true, True: 1})
+			}
+		}
+		where = append(where, conjuncts[orIndex+1:]...)
+		branch.Where = joinAnd(where...)
+		branch.IsAfterUnionDistinct = sel.Distinct && i > 0
+		branches = append(branches, branch)
+	}
+	return &ast.UnionStmt{SelectList: &ast.UnionSelectList{Selects: branches}},
+		"OR条件连接了不同字段上的过滤条件，可能无法使用索引，已改写为UNION查询", true
+}
+
+// checkOrCond4UpDelete warns the UPDATE and DELETE whose OR condition uses different columns.
+func checkOrCond4UpDelete(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	var where ast.ExprNode
+	switch s := stmt.(type) {
+	case *ast.UpdateStmt:
+		where = s.Where
+	case *ast.DeleteStmt:
+		where = s.Where
+	default:
+		return nil, "", false
+	}
+	for _, conjunct := range splitAnd(where) {
+		if _, ok := orDisjuncts(conjunct); ok {
+			return nil, "UPDATE或DELETE语句中OR条件连接了不同字段上的过滤条件，可能无法使用索引，建议拆分为多条语句执行", true
+		}
+	}
+	return nil, "", false
+}
+
+// rewriteDelete2Truncate rewrites the DELETE without condition to TRUNCATE.
+func rewriteDelete2Truncate(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	del, ok := stmt.(*ast.DeleteStmt)
+	if !ok || del.IsMultiTable || del.Where != nil || del.Limit != nil || del.Order != nil || del.TableRefs == nil {
+		return nil, "", false
+	}
+	source, ok := singleTableSource(del.TableRefs)
+	if !ok {
+		return nil, "", false
+	}
+	table, ok := source.Source.(*ast.TableName)
+	if !ok {
+		return nil, "", false
+	}
+	return &ast.TruncateTableStmt{Table: table},
+		"无条件的DELETE已改写为TRUNCATE，TRUNCATE无法回滚且不会触发DELETE触发器，请确认后执行", true
+}
+
+// checkLargeOffset warns the LIMIT whose offset exceeds the threshold.
+func checkLargeOffset(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	threshold := int64(c.intParam(100))
+	var offset int64
+	inspect(stmt, func(n ast.Node) bool {
+		if limit, ok := n.(*ast.Limit); ok && limit.Offset != nil {
+			if v, ok := intValue(limit.Offset); ok && v > threshold && v > offset {
+				offset = v
+			}
+		}
+		return true
+	})
+	if offset == 0 {
+		return nil, "", false
+	}
+	return nil, fmt.Sprintf("OFFSET的值%d超过了阈值%d，建议使用上一页最后一条记录的排序字段作为条件进行分页", offset, threshold), true
+}
@@ -0,0 +1,474 @@
+package rewrite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/opcode"
+)
+
+// singleFieldSelect returns the sub query which selects one expression from one table.
+func singleFieldSelect(sub *ast.SubqueryExpr) (*ast.SelectStmt, *ast.SelectField, bool) {
+	sel, ok := simpleSelect(sub.Query)
+	if !ok || sel.Fields == nil || len(sel.Fields.Fields) != 1 || sel.From == nil {
+		return nil, nil, false
+	}
+	field := sel.Fields.Fields[0]
+	if field.WildCard != nil || field.Expr == nil {
+		return nil, nil, false
+	}
+	return sel, field, true
+}
+
+// predicateSubqueries returns the sub queries used by IN, EXISTS, ANY and ALL,
+// the rows of these sub queries are only tested for the existence.
+func predicateSubqueries(stmt ast.Node) []*ast.SubqueryExpr {
+	result := []*ast.SubqueryExpr{}
+	inspect(stmt, func(n ast.Node) bool {
+		var sel ast.ExprNode
+		switch e := n.(type) {
+		case *ast.PatternInExpr:
+			sel = e.Sel
+		case *ast.ExistsSubqueryExpr:
+			sel = e.Sel
+		case *ast.CompareSubqueryExpr:
+			sel = e.R
+		}
+		if sub, ok := sel.(*ast.SubqueryExpr); ok {
+			result = append(result, sub)
+		}
+		return true
+	})
+	return result
+}
+
+func isCountAll(expr ast.ExprNode) bool {
+	agg, ok := expr.(*ast.AggregateFuncExpr)
+	if !ok || strings.ToLower(agg.F) != ast.AggFuncCount || agg.Distinct || len(agg.Args) != 1 {
+		return false
+	}
+	// COUNT(*) is parsed as COUNT(1)
+	return isConstant(agg.Args[0]) && !isNullValue(agg.Args[0])
+}
+
+// rewriteCntGtThanZero rewrites "(SELECT COUNT(*) ...) > 0" to "EXISTS (SELECT 1 ...)".
+func rewriteCntGtThanZero(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	fired := false
+	replaceNodes(stmt, func(n ast.Node) ast.Node {
+		b, ok := n.(*ast.BinaryOperationExpr)
+		if !ok {
+			return n
+		}
+		sub, isSub := b.L.(*ast.SubqueryExpr)
+		op, value := b.Op, b.R
+		if !isSub {
+			sub, isSub = b.R.(*ast.SubqueryExpr)
+			op, value = reverseCompareOp(b.Op), b.L
+		}
+		if !isSub {
+			return n
+		}
+		v, ok := intValue(value)
+		if !ok || !((op == opcode.GT && v == 0) || (op == opcode.GE && v == 1) || (op == opcode.NE && v == 0)) {
+			return n
+		}
+		sel, field, ok := singleFieldSelect(sub)
+		if !ok || !isCountAll(field.Expr) || sel.GroupBy != nil || sel.Having != nil || sel.Limit != nil {
+			return n
+		}
+		fired = true
+		sel.Fields.Fields = []*ast.SelectField{{Expr: newValue(1)}}
+		sel.OrderBy = nil
+		return &ast.ExistsSubqueryExpr{Sel: sub}
+	})
+	return stmt, "使用COUNT标量子查询判断数据是否存在需要进行聚集运算，已改写为EXISTS子查询", fired
+}
+
+// aggregateSubquery replaces the field of sub query with the MAX or MIN of it.
+func aggregateSubquery(sub *ast.SubqueryExpr, fn string) bool {
+	sel, field, ok := singleFieldSelect(sub)
+	if !ok || sel.GroupBy != nil || sel.Having != nil || sel.Limit != nil || hasAggregate(field.Expr) {
+		return false
+	}
+	sel.Distinct = false
+	sel.OrderBy = nil
+	field.Expr = &ast.AggregateFuncExpr{F: fn, Args: []ast.ExprNode{field.Expr}}
+	field.AsName.O, field.AsName.L = "", ""
+	return true
+}
+
+// rewriteAllQualifierSubQuery rewrites "<> ALL" to the equivalent "NOT IN".
+//
+// "> ALL" is not rewritten to "> MAX", it is true when the sub query returns no
+// rows, and it is not true when the result has NULL, but the MAX sub query is NULL
+// and ignores NULLs respectively. The rule only warns about it.
+func rewriteAllQualifierSubQuery(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	rewritten, compared := false, false
+	replaceNodes(stmt, func(n ast.Node) ast.Node {
+		e, ok := n.(*ast.CompareSubqueryExpr)
+		if !ok || !e.All {
+			return n
+		}
+		sub, ok := e.R.(*ast.SubqueryExpr)
+		if !ok {
+			return n
+		}
+		switch e.Op {
+		case opcode.NE:
+			rewritten = true
+			return &ast.PatternInExpr{Expr: e.L, Sel: sub, Not: true}
+		case opcode.GT, opcode.GE, opcode.LT, opcode.LE:
+			compared = true
+		}
+		return n
+	})
+	messages := []string{}
+	if rewritten {
+		messages = append(messages, "<> ALL子查询已改写为等价的NOT IN子查询")
+	}
+	if compared {
+		messages = append(messages, "ALL修饰的比较子查询结果中存在NULL时条件不成立，子查询结果为空时条件恒成立，"+
+			"不能直接改写为MAX/MIN子查询，请确认子查询字段非空且结果不为空后再改写")
+	}
+	if !rewritten {
+		return nil, strings.Join(messages, "；"), compared
+	}
+	return stmt, strings.Join(messages, "；"), true
+}
+
+// rewriteQualifierSubQuery rewrites "= ANY" to "IN", "> ANY" to "> MIN" and "< ANY" to "< MAX".
+func rewriteQualifierSubQuery(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	fired := false
+	replaceNodes(stmt, func(n ast.Node) ast.Node {
+		e, ok := n.(*ast.CompareSubqueryExpr)
+		if !ok || e.All {
+			return n
+		}
+		sub, ok := e.R.(*ast.SubqueryExpr)
+		if !ok {
+			return n
+		}
+		switch e.Op {
+		case opcode.EQ:
+			fired = true
+			return &ast.PatternInExpr{Expr: e.L, Sel: sub}
+		case opcode.GT, opcode.GE:
+			if aggregateSubquery(sub, ast.AggFuncMin) {
+				fired = true
+				return &ast.BinaryOperationExpr{Op: e.Op, L: e.L, R: sub}
+			}
+		case opcode.LT, opcode.LE:
+			if aggregateSubquery(sub, ast.AggFuncMax) {
+				fired = true
+				return &ast.BinaryOperationExpr{Op: e.Op, L: e.L, R: sub}
+			}
+		}
+		return n
+	})
+	return stmt, "ANY/SOME修饰的子查询需要逐行比较，已改写为IN或MAX/MIN子查询", fired
+}
+
+// checkNotInNullableSubQuery adds the not null condition to the NOT IN sub query
+// whose field is nullable, a NULL in the result makes the condition never true.
+func checkNotInNullableSubQuery(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	nullable := []string{}
+	inspect(stmt, func(n ast.Node) bool {
+		in, ok := n.(*ast.PatternInExpr)
+		if !ok || !in.Not {
+			return true
+		}
+		sub, ok := in.Sel.(*ast.SubqueryExpr)
+		if !ok {
+			return true
+		}
+		sel, field, ok := singleFieldSelect(sub)
+		if !ok || sel.GroupBy != nil {
+			return true
+		}
+		col, ok := field.Expr.(*ast.ColumnNameExpr)
+		if !ok {
+			return true
+		}
+		_, table, ok := c.resolveColumn(tableSources(sel), col.Name)
+		if !ok || isNotNullColumn(table, col.Name.Name.L) {
+			return true
+		}
+		nullable = append(nullable, col.Name.Name.O)
+		sel.Where = joinAnd(append(splitAnd(sel.Where), &ast.IsNullExpr{Expr: col, Not: true})...)
+		return true
+	})
+	if len(nullable) == 0 {
+		return nil, "", false
+	}
+	return stmt, fmt.Sprintf("NOT IN子查询的字段%s可以为NULL，子查询结果中存在NULL时查询结果为空，已在子查询中增加非空条件", quoteNames(nullable)), true
+}
+
+// rewriteDistinctElimination removes the DISTINCT of the sub queries which are only tested for the existence.
+func rewriteDistinctElimination(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	fired := false
+	for _, sub := range predicateSubqueries(stmt) {
+		sel, ok := simpleSelect(sub.Query)
+		if !ok || !sel.Distinct || sel.Limit != nil {
+			continue
+		}
+		sel.Distinct = false
+		fired = true
+	}
+	return stmt, "IN/EXISTS子查询仅用于判断数据是否存在，其中的DISTINCT没有意义，已删除", fired
+}
+
+// rewriteOrderEliminationInSubquery removes the ORDER BY without LIMIT of the sub queries and derived tables.
+func rewriteOrderEliminationInSubquery(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	fired := false
+	eliminate := func(query ast.Node) {
+		sel, ok := query.(*ast.SelectStmt)
+		if ok && sel.OrderBy != nil && sel.Limit == nil {
+			sel.OrderBy = nil
+			fired = true
+		}
+	}
+	inspect(stmt, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.SubqueryExpr:
+			eliminate(e.Query)
+		case *ast.TableSource:
+			eliminate(e.Source)
+		}
+		return true
+	})
+	return stmt, "子查询中没有LIMIT的排序不影响查询结果，已删除ORDER BY子句", fired
+}
+
+// correlatedColumn qualifies the column of outer query which is used in the sub query.
+func correlatedColumn(outer *ast.SelectStmt, col *ast.ColumnName) (*ast.ColumnName, bool) {
+	if col.Table.L != "" {
+		return col, true
+	}
+	sources := tableSources(outer)
+	if len(sources) != 1 || sourceName(sources[0]) == "" {
+		return nil, false
+	}
+	return qualify(col, sourceName(sources[0])), true
+}
+
+// conflictSourceName checks the name of the inner table is used by the outer query.
+func conflictSourceName(outer *ast.SelectStmt, name string) bool {
+	if name == "" {
+		return true
+	}
+	for _, source := range tableSources(outer) {
+		if sourceName(source) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteInSubquery rewrites the IN sub query in the WHERE conditions to the correlated EXISTS sub query.
+func rewriteInSubquery(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	fired := false
+	for _, outer := range selects {
+		if outer.Where == nil || outer.From == nil {
+			continue
+		}
+		conjuncts := splitAnd(outer.Where)
+		changed := false
+		for i, conjunct := range conjuncts {
+			in, ok := conjunct.(*ast.PatternInExpr)
+			if !ok || in.Not {
+				continue
+			}
+			outerCol, ok := in.Expr.(*ast.ColumnNameExpr)
+			if !ok {
+				continue
+			}
+			sub, ok := in.Sel.(*ast.SubqueryExpr)
+			if !ok {
+				continue
+			}
+			inner, field, ok := singleFieldSelect(sub)
+			if !ok || inner.GroupBy != nil || inner.Having != nil || inner.Limit != nil || fieldsHaveAggregate(inner) {
+				continue
+			}
+			innerCol, ok := field.Expr.(*ast.ColumnNameExpr)
+			if !ok {
+				continue
+			}
+			innerSource, ok := singleTableSource(inner.From)
+			if !ok || conflictSourceName(outer, sourceName(innerSource)) {
+				continue
+			}
+			outerName, ok := correlatedColumn(outer, outerCol.Name)
+			if !ok || outerName.Table.L == sourceName(innerSource) {
+				continue
+			}
+			inner.Distinct = false
+			inner.OrderBy = nil
+			inner.Fields.Fields = []*ast.SelectField{{Expr: newValue(1)}}
+			inner.Where = joinAnd(append(splitAnd(inner.Where), &ast.BinaryOperationExpr{
+				Op: opcode.EQ,
+				L:  &ast.ColumnNameExpr{Name: qualify(innerCol.Name, sourceName(innerSource))},
+				R:  &ast.ColumnNameExpr{Name: outerName},
+			})...)
+			conjuncts[i] = &ast.ExistsSubqueryExpr{Sel: sub}
+			changed = true
+		}
+		if changed {
+			outer.Where = joinAnd(conjuncts...)
+			fired = true
+		}
+	}
+	return stmt, "IN子查询已改写为等价的相关EXISTS子查询，从而为子查询产生新的过滤条件", fired
+}
+
+// rewriteExists2Join rewrites the EXISTS sub query to inner join when the sub
+// query is correlated by an unique column, which joins at most one row.
+func rewriteExists2Join(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	selects, _ := selectStmts(stmt)
+	fired := false
+	for _, outer := range selects {
+		if outer.Where == nil || outer.From == nil || outer.From.TableRefs == nil {
+			continue
+		}
+		unqualifiedWildcard := false
+		if outer.Fields != nil {
+			for _, field := range outer.Fields.Fields {
+				if field.WildCard != nil && field.WildCard.Table.L == "" {
+					unqualifiedWildcard = true
+				}
+			}
+		}
+		if unqualifiedWildcard {
+			continue
+		}
+		conjuncts := splitAnd(outer.Where)
+		for i := 0; i < len(conjuncts); i++ {
+			exists, ok := conjuncts[i].(*ast.ExistsSubqueryExpr)
+			if !ok || exists.Not {
+				continue
+			}
+			sub, ok := exists.Sel.(*ast.SubqueryExpr)
+			if !ok {
+				continue
+			}
+			inner, ok := simpleSelect(sub.Query)
+			if !ok || !isPlainSelect(inner) {
+				continue
+			}
+			innerSource, ok := singleTableSource(inner.From)
+			if !ok {
+				continue
+			}
+			innerTable, ok := innerSource.Source.(*ast.TableName)
+			innerName := sourceName(innerSource)
+			if !ok || conflictSourceName(outer, innerName) {
+				continue
+			}
+			createTable, ok := c.createTable(innerTable)
+			if !ok {
+				continue
+			}
+			on, ok := exists2JoinCondition(outer, inner, innerName, createTable)
+			if !ok {
+				continue
+			}
+			refs := outer.From.TableRefs
+			var left ast.ResultSetNode = refs
+			if refs.Right == nil {
+				left = refs.Left
+			}
+			outer.From.TableRefs = &ast.Join{Left: left, Right: innerSource, Tp: ast.CrossJoin, On: &ast.OnCondition{Expr: on}}
+			conjuncts = append(conjuncts[:i], conjuncts[i+1:]...)
+			outer.Where = joinAnd(conjuncts...)
+			fired = true
+			break
+		}
+	}
+	return stmt, "EXISTS子查询通过唯一键与外部查询关联，已改写为表连接", fired
+}
+
+// exists2JoinCondition returns the join condition when the sub query is
+// correlated by the equality on an unique not null column.
+func exists2JoinCondition(outer, inner *ast.SelectStmt, innerName string, table *ast.CreateTableStmt) (ast.ExprNode, bool) {
+	conjuncts := splitAnd(inner.Where)
+	outerNames := map[string]struct{}{}
+	for _, source := range tableSources(outer) {
+		outerNames[sourceName(source)] = struct{}{}
+	}
+	unique := false
+	for _, conjunct := range conjuncts {
+		if hasSubquery(conjunct) {
+			return nil, false
+		}
+		for _, col := range columns(conjunct) {
+			_, isOuter := outerNames[col.Table.L]
+			if col.Table.L != innerName && !isOuter {
+				// the unqualified columns are ambiguous after joined
+				return nil, false
+			}
+		}
+		b, ok := conjunct.(*ast.BinaryOperationExpr)
+		if !ok || b.Op != opcode.EQ {
+			continue
+		}
+		l, lIsCol := b.L.(*ast.ColumnNameExpr)
+		r, rIsCol := b.R.(*ast.ColumnNameExpr)
+		if !lIsCol || !rIsCol {
+			continue
+		}
+		for _, pair := range [][2]*ast.ColumnNameExpr{{l, r}, {r, l}} {
+			innerCol, outerCol := pair[0], pair[1]
+			_, isOuter := outerNames[outerCol.Name.Table.L]
+			if innerCol.Name.Table.L == innerName && isOuter &&
+				isUniqueColumn(table, innerCol.Name.Name.L) && isNotNullColumn(table, innerCol.Name.Name.L) {
+				unique = true
+			}
+		}
+	}
+	if !unique {
+		return nil, false
+	}
+	return joinAnd(conjuncts...), true
+}
+
+// rewriteMaxMinAgg rewrites the scalar sub query "SELECT MAX(c) ..." to
+// "SELECT c ... WHERE c IS NOT NULL ORDER BY c DESC LIMIT 1" which can use the
+// order of index. They are equivalent: MAX ignores NULLs, and both are NULL when
+// there are no rows. The sub queries of ANY/ALL/IN/EXISTS are not scalar, they
+// are skipped.
+func rewriteMaxMinAgg(c *rewriteContext, stmt ast.StmtNode) (ast.StmtNode, string, bool) {
+	predicates := map[*ast.SubqueryExpr]struct{}{}
+	for _, sub := range predicateSubqueries(stmt) {
+		predicates[sub] = struct{}{}
+	}
+	fired := false
+	for _, sub := range subqueries(stmt) {
+		if _, ok := predicates[sub]; ok {
+			continue
+		}
+		sel, field, ok := singleFieldSelect(sub)
+		if !ok || sel.GroupBy != nil || sel.Having != nil || sel.Limit != nil || sel.Distinct {
+			continue
+		}
+		agg, ok := field.Expr.(*ast.AggregateFuncExpr)
+		if !ok || agg.Distinct || len(agg.Args) != 1 {
+			continue
+		}
+		fn := strings.ToLower(agg.F)
+		if fn != ast.AggFuncMax && fn != ast.AggFuncMin {
+			continue
+		}
+		col, ok := agg.Args[0].(*ast.ColumnNameExpr)
+		if !ok {
+			continue
+		}
+		field.Expr = col
+		sel.Where = joinAnd(append(splitAnd(sel.Where), &ast.IsNullExpr{Expr: col, Not: true})...)
+		sel.OrderBy = &ast.OrderByClause{Items: []*ast.ByItem{{Expr: col, Desc: fn == ast.AggFuncMax}}}
+		sel.Limit = &ast.Limit{Count: newValue(1)}
+		fired = true
+	}
+	return stmt, "MAX/MIN子查询已改写为排序后取第一条记录，从而可以利用索引的有序性避免聚集运算", fired
+}
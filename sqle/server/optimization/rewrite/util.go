@@ -0,0 +1,533 @@
+package rewrite
+
+import (
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+)
+
+func restore(node ast.Node) (string, error) {
+	return util.RestoreToSql(node)
+}
+
+// exprKey identifies the expression by its text, it is used to compare expressions.
+func exprKey(expr ast.Node) string {
+	var buf strings.Builder
+	if err := expr.Restore(format.NewRestoreCtx(format.RestoreKeyWordUppercase|format.RestoreStringSingleQuotes, &buf)); err != nil {
+		return ""
+	}
+	return strings.ToLower(buf.String())
+}
+
+// nodeInspector calls the enter function on every node, the children are skipped when it returns false.
+type nodeInspector struct {
+	enter func(n ast.Node) bool
+}
+
+func (v *nodeInspector) Enter(n ast.Node) (ast.Node, bool) {
+	return n, !v.enter(n)
+}
+
+func (v *nodeInspector) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+func inspect(node ast.Node, enter func(n ast.Node) bool) {
+	if node == nil {
+		return
+	}
+	node.Accept(&nodeInspector{enter: enter})
+}
+
+// inspectOuter is like inspect but the sub queries are skipped.
+func inspectOuter(node ast.Node, enter func(n ast.Node) bool) {
+	inspect(node, func(n ast.Node) bool {
+		if _, ok := n.(*ast.SubqueryExpr); ok {
+			return false
+		}
+		return enter(n)
+	})
+}
+
+// nodeReplacer replaces every node by the result of the leave function.
+type nodeReplacer struct {
+	leave        func(n ast.Node) ast.Node
+	skipSubquery bool
+}
+
+func (v *nodeReplacer) Enter(n ast.Node) (ast.Node, bool) {
+	if _, ok := n.(*ast.SubqueryExpr); ok && v.skipSubquery {
+		return n, true
+	}
+	return n, false
+}
+
+func (v *nodeReplacer) Leave(n ast.Node) (ast.Node, bool) {
+	return v.leave(n), true
+}
+
+func replaceNodes(node ast.Node, leave func(n ast.Node) ast.Node) ast.Node {
+	if node == nil {
+		return nil
+	}
+	newNode, _ := node.Accept(&nodeReplacer{leave: leave})
+	return newNode
+}
+
+func replaceExpr(expr ast.ExprNode, leave func(n ast.Node) ast.Node) ast.ExprNode {
+	if expr == nil {
+		return nil
+	}
+	return replaceNodes(expr, leave).(ast.ExprNode)
+}
+
+// replaceOuterExpr replaces the nodes of the expression out of the sub queries.
+func replaceOuterExpr(expr ast.ExprNode, leave func(n ast.Node) ast.Node) ast.ExprNode {
+	if expr == nil {
+		return nil
+	}
+	newNode, _ := expr.Accept(&nodeReplacer{leave: leave, skipSubquery: true})
+	return newNode.(ast.ExprNode)
+}
+
+// predicateScope is a WHERE clause and the tables its columns come from.
+type predicateScope struct {
+	sources []*ast.TableSource
+	where   *ast.ExprNode
+}
+
+func predicateScopes(stmt ast.Node) []predicateScope {
+	scopes := []predicateScope{}
+	inspect(stmt, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.SelectStmt:
+			if s.Where != nil {
+				scopes = append(scopes, predicateScope{sources: tableSources(s), where: &s.Where})
+			}
+		case *ast.UpdateStmt:
+			if s.Where != nil && s.TableRefs != nil {
+				scopes = append(scopes, predicateScope{sources: util.GetTableSources(s.TableRefs.TableRefs), where: &s.Where})
+			}
+		case *ast.DeleteStmt:
+			if s.Where != nil && s.TableRefs != nil {
+				scopes = append(scopes, predicateScope{sources: util.GetTableSources(s.TableRefs.TableRefs), where: &s.Where})
+			}
+		}
+		return true
+	})
+	return scopes
+}
+
+// selectStmts returns all the SELECT statements in the statement including
+// the sub queries, the branches of UNION are marked.
+func selectStmts(stmt ast.Node) (selects []*ast.SelectStmt, unionBranches map[*ast.SelectStmt]bool) {
+	unionBranches = map[*ast.SelectStmt]bool{}
+	inspect(stmt, func(n ast.Node) bool {
+		switch s := n.(type) {
+		case *ast.SelectStmt:
+			selects = append(selects, s)
+		case *ast.UnionStmt:
+			if s.SelectList != nil {
+				for _, branch := range s.SelectList.Selects {
+					unionBranches[branch] = true
+				}
+			}
+		}
+		return true
+	})
+	return selects, unionBranches
+}
+
+// subqueries returns the sub queries used in expressions, the derived tables are not included.
+func subqueries(stmt ast.Node) []*ast.SubqueryExpr {
+	result := []*ast.SubqueryExpr{}
+	inspect(stmt, func(n ast.Node) bool {
+		if s, ok := n.(*ast.SubqueryExpr); ok {
+			result = append(result, s)
+		}
+		return true
+	})
+	return result
+}
+
+// simpleSelect returns the SELECT statement of the sub query when it is not a UNION.
+func simpleSelect(query ast.ResultSetNode) (*ast.SelectStmt, bool) {
+	stmt, ok := query.(*ast.SelectStmt)
+	return stmt, ok
+}
+
+func splitAnd(expr ast.ExprNode) []ast.ExprNode {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case *ast.BinaryOperationExpr:
+		if e.Op == opcode.LogicAnd {
+			return append(splitAnd(e.L), splitAnd(e.R)...)
+		}
+	case *ast.ParenthesesExpr:
+		if b, ok := e.Expr.(*ast.BinaryOperationExpr); ok && b.Op == opcode.LogicAnd {
+			return splitAnd(b)
+		}
+	}
+	return []ast.ExprNode{expr}
+}
+
+func splitOr(expr ast.ExprNode) []ast.ExprNode {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case *ast.BinaryOperationExpr:
+		if e.Op == opcode.LogicOr {
+			return append(splitOr(e.L), splitOr(e.R)...)
+		}
+	case *ast.ParenthesesExpr:
+		if b, ok := e.Expr.(*ast.BinaryOperationExpr); ok && b.Op == opcode.LogicOr {
+			return splitOr(b)
+		}
+	}
+	return []ast.ExprNode{expr}
+}
+
+// joinAnd connects the expressions by AND, the expressions with lower precedence are wrapped by parentheses.
+func joinAnd(exprs ...ast.ExprNode) ast.ExprNode {
+	var result ast.ExprNode
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		if b, ok := expr.(*ast.BinaryOperationExpr); ok && (b.Op == opcode.LogicOr || b.Op == opcode.LogicXor) {
+			expr = &ast.ParenthesesExpr{Expr: expr}
+		}
+		if result == nil {
+			result = expr
+			continue
+		}
+		result = &ast.BinaryOperationExpr{Op: opcode.LogicAnd, L: result, R: expr}
+	}
+	return result
+}
+
+// columns returns the columns used by the expression, the columns in sub queries are included.
+func columns(node ast.Node) []*ast.ColumnName {
+	cols := []*ast.ColumnName{}
+	inspect(node, func(n ast.Node) bool {
+		if c, ok := n.(*ast.ColumnNameExpr); ok {
+			cols = append(cols, c.Name)
+		}
+		return true
+	})
+	return cols
+}
+
+func hasSubquery(node ast.Node) bool {
+	found := false
+	inspect(node, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// nonDeterministicFuncs returns different results in one query, or depends on the time the row is evaluated.
+var nonDeterministicFuncs = map[string]struct{}{
+	ast.Rand: {}, ast.UUID: {}, ast.UUIDShort: {}, ast.RandomBytes: {}, ast.Sleep: {},
+	ast.Now: {}, ast.Sysdate: {}, ast.CurrentTimestamp: {}, ast.LocalTime: {}, ast.LocalTimestamp: {},
+	ast.Curdate: {}, ast.CurrentDate: {}, ast.Curtime: {}, ast.CurrentTime: {},
+	ast.UTCDate: {}, ast.UTCTime: {}, ast.UTCTimestamp: {}, ast.UnixTimestamp: {},
+	ast.FoundRows: {}, ast.LastInsertId: {}, ast.RowCount: {},
+}
+
+// isDeterministic checks the expression has no non-deterministic functions and variables,
+// such expression can not be moved to where it is evaluated for different rows or times.
+func isDeterministic(node ast.Node) bool {
+	deterministic := true
+	inspect(node, func(n ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.FuncCallExpr:
+			if _, ok := nonDeterministicFuncs[e.FnName.L]; ok {
+				deterministic = false
+			}
+		case *ast.VariableExpr:
+			deterministic = false
+		}
+		return deterministic
+	})
+	return deterministic
+}
+
+// hasAggregate checks the aggregate functions out of the sub queries.
+func hasAggregate(node ast.Node) bool {
+	found := false
+	inspect(node, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.AggregateFuncExpr, *ast.WindowFuncExpr:
+			found = true
+		case *ast.SubqueryExpr:
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+func fieldsHaveAggregate(stmt *ast.SelectStmt) bool {
+	if stmt.Fields == nil {
+		return false
+	}
+	for _, field := range stmt.Fields.Fields {
+		if field.Expr != nil && hasAggregate(field.Expr) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasWildcard(stmt *ast.SelectStmt) bool {
+	if stmt.Fields == nil {
+		return false
+	}
+	for _, field := range stmt.Fields.Fields {
+		if field.WildCard != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlainSelect checks the SELECT has no grouping, aggregation, window or limit.
+func isPlainSelect(stmt *ast.SelectStmt) bool {
+	return stmt.GroupBy == nil && stmt.Having == nil && stmt.Limit == nil && !stmt.Distinct &&
+		len(stmt.WindowSpecs) == 0 && stmt.LockTp == ast.SelectLockNone && stmt.SelectIntoOpt == nil &&
+		!fieldsHaveAggregate(stmt)
+}
+
+func tableSources(stmt *ast.SelectStmt) []*ast.TableSource {
+	if stmt.From == nil {
+		return nil
+	}
+	return util.GetTableSources(stmt.From.TableRefs)
+}
+
+// innerJoinConditions returns the conditions of the inner joins, they filter the rows like WHERE.
+func innerJoinConditions(sel *ast.SelectStmt) []ast.ExprNode {
+	conditions := []ast.ExprNode{}
+	var visit func(node ast.ResultSetNode)
+	visit = func(node ast.ResultSetNode) {
+		j, ok := node.(*ast.Join)
+		if !ok {
+			return
+		}
+		if j.Right != nil && j.Tp == ast.CrossJoin && j.On != nil {
+			conditions = append(conditions, splitAnd(j.On.Expr)...)
+		}
+		visit(j.Left)
+		visit(j.Right)
+	}
+	if sel.From != nil {
+		visit(sel.From.TableRefs)
+	}
+	return conditions
+}
+
+// singleTableSource returns the table source when the FROM clause has only one table.
+func singleTableSource(from *ast.TableRefsClause) (*ast.TableSource, bool) {
+	if from == nil || from.TableRefs == nil || from.TableRefs.Right != nil {
+		return nil, false
+	}
+	source, ok := from.TableRefs.Left.(*ast.TableSource)
+	return source, ok
+}
+
+// sourceName returns the name used to reference the table source in the statement.
+func sourceName(source *ast.TableSource) string {
+	return util.GetTableNameFromTableSource(source)
+}
+
+func columnInSource(col *ast.ColumnName, source *ast.TableSource) bool {
+	return col.Table.L != "" && col.Table.L == sourceName(source)
+}
+
+// qualify sets the table of the column when it is not set.
+func qualify(col *ast.ColumnName, table string) *ast.ColumnName {
+	if col.Table.L != "" {
+		return col
+	}
+	return &ast.ColumnName{Schema: col.Schema, Table: model.NewCIStr(table), Name: col.Name}
+}
+
+func newColumn(table string, name model.CIStr) *ast.ColumnNameExpr {
+	return &ast.ColumnNameExpr{Name: &ast.ColumnName{Table: model.NewCIStr(table), Name: name}}
+}
+
+func newValue(value interface{}) ast.ExprNode {
+	return ast.NewValueExpr(value, "", "")
+}
+
+func isNullValue(expr ast.ExprNode) bool {
+	v, ok := expr.(ast.ValueExpr)
+	return ok && v.GetValue() == nil
+}
+
+func isConstant(expr ast.ExprNode) bool {
+	switch e := expr.(type) {
+	case ast.ValueExpr, ast.ParamMarkerExpr:
+		return true
+	case *ast.UnaryOperationExpr:
+		return isConstant(e.V)
+	}
+	return false
+}
+
+func intValue(expr ast.ExprNode) (int64, bool) {
+	v, ok := expr.(ast.ValueExpr)
+	if !ok {
+		return 0, false
+	}
+	switch i := v.GetValue().(type) {
+	case int64:
+		return i, true
+	case uint64:
+		return int64(i), true
+	}
+	return 0, false
+}
+
+func isCompareOp(op opcode.Op) bool {
+	switch op {
+	case opcode.EQ, opcode.NE, opcode.LT, opcode.LE, opcode.GT, opcode.GE, opcode.NullEQ:
+		return true
+	}
+	return false
+}
+
+// reverseCompareOp returns the operator after the operands are swapped.
+func reverseCompareOp(op opcode.Op) opcode.Op {
+	switch op {
+	case opcode.LT:
+		return opcode.GT
+	case opcode.LE:
+		return opcode.GE
+	case opcode.GT:
+		return opcode.LT
+	case opcode.GE:
+		return opcode.LE
+	}
+	return op
+}
+
+// isNullRejecting checks the predicate is false or unknown when the columns of the table are NULL.
+func isNullRejecting(expr ast.ExprNode, table string) bool {
+	var col ast.ExprNode
+	switch e := expr.(type) {
+	case *ast.BinaryOperationExpr:
+		if !isCompareOp(e.Op) || e.Op == opcode.NullEQ {
+			return false
+		}
+		if c, ok := e.L.(*ast.ColumnNameExpr); ok && c.Name.Table.L == table {
+			col = c
+		} else if c, ok := e.R.(*ast.ColumnNameExpr); ok && c.Name.Table.L == table {
+			col = c
+		}
+	case *ast.IsNullExpr:
+		if e.Not {
+			col = e.Expr
+		}
+	case *ast.PatternInExpr:
+		if !e.Not {
+			col = e.Expr
+		}
+	case *ast.PatternLikeExpr:
+		if !e.Not {
+			col = e.Expr
+		}
+	case *ast.BetweenExpr:
+		if !e.Not {
+			col = e.Expr
+		}
+	}
+	c, ok := col.(*ast.ColumnNameExpr)
+	return ok && c.Name.Table.L == table
+}
+
+func getColumnDef(table *ast.CreateTableStmt, name string) (*ast.ColumnDef, bool) {
+	for _, col := range table.Cols {
+		if col.Name.Name.L == strings.ToLower(name) {
+			return col, true
+		}
+	}
+	return nil, false
+}
+
+// isUniqueColumn checks the column is the primary key or has an unique index by itself.
+func isUniqueColumn(table *ast.CreateTableStmt, name string) bool {
+	name = strings.ToLower(name)
+	col, ok := getColumnDef(table, name)
+	if !ok {
+		return false
+	}
+	if util.HasOneInOptions(col.Options, ast.ColumnOptionPrimaryKey, ast.ColumnOptionUniqKey) {
+		return true
+	}
+	for _, constraint := range table.Constraints {
+		switch constraint.Tp {
+		case ast.ConstraintPrimaryKey, ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+			if len(constraint.Keys) == 1 && constraint.Keys[0].Column != nil && constraint.Keys[0].Column.Name.L == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isNotNullColumn(table *ast.CreateTableStmt, name string) bool {
+	col, ok := getColumnDef(table, name)
+	if !ok {
+		return false
+	}
+	if col.Tp != nil && mysql.HasNotNullFlag(col.Tp.Flag) {
+		return true
+	}
+	if util.HasOneInOptions(col.Options, ast.ColumnOptionNotNull, ast.ColumnOptionPrimaryKey) {
+		return true
+	}
+	pk, _ := util.GetPrimaryKey(table)
+	_, ok = pk[col.Name.Name.L]
+	return ok
+}
+
+// resolveColumn finds the definition of the column in the tables of the SELECT.
+func (c *rewriteContext) resolveColumn(sources []*ast.TableSource, col *ast.ColumnName) (*ast.ColumnDef, *ast.CreateTableStmt, bool) {
+	var found *ast.ColumnDef
+	var foundTable *ast.CreateTableStmt
+	for _, source := range sources {
+		tableName, ok := source.Source.(*ast.TableName)
+		if !ok {
+			continue
+		}
+		if col.Table.L != "" && col.Table.L != sourceName(source) {
+			continue
+		}
+		table, ok := c.createTable(tableName)
+		if !ok {
+			continue
+		}
+		def, ok := getColumnDef(table, col.Name.L)
+		if !ok {
+			continue
+		}
+		if found != nil {
+			// the column is ambiguous
+			return nil, nil, false
+		}
+		found, foundTable = def, table
+	}
+	return found, foundTable, found != nil
+}
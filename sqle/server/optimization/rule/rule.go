@@ -76,6 +76,14 @@ func init() {
 	OptimizationRuleMap = make(map[string][]OptimizationRuleHandler)
 	OptimizationRuleMap["MySQL"] = MySQLOptimizationRuleHandler
 	OptimizationRuleMap["Oracle"] = OracleOptimizationRuleHandler
+	for dbType, handlers := range OptimizationRuleMap {
+		rules := make([]*driverV2.Rule, 0, len(handlers))
+		for i := range handlers {
+			rules = append(rules, &handlers[i].Rule)
+		}
+		// the rules of the plugins are registered again when the plugins are loaded
+		driverV2.RegisterRuleMessages(dbType, rules)
+	}
 
 	// SQL优化规则知识库
 	defaultRulesKnowledge, err := getDefaultRulesKnowledge()
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/session"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/server/optimization/rewrite"
+	optimization "github.com/actiontech/sqle/sqle/server/optimization/rule"
+	"github.com/pingcap/parser/ast"
+	"github.com/sirupsen/logrus"
+)
+
+// SQLOptimizationTriggeredRule is the element of model.SQLOptimizationRecordSQL.TriggeredRules.
+type SQLOptimizationTriggeredRule struct {
+	RuleCode     string `json:"rule_code"`
+	RuleName     string `json:"rule_name"`
+	Message      string `json:"message"`
+	RewrittenSQL string `json:"rewritten_sql"`
+}
+
+// OptimizeSQLs rewrites the SQLs by the built-in rewrite engine and fills the result into record.
+// The SQLs are explained before and after rewriting if instance is not nil.
+func OptimizeSQLs(l *logrus.Entry, record *model.SQLOptimizationRecord, instance *model.Instance, sqls []string) error {
+	if record.DBType != driverV2.DriverTypeMySQL {
+		return fmt.Errorf("sql optimization of db type %v is not supported", record.DBType)
+	}
+	rules, err := getSQLOptimizationRules(instance)
+	if err != nil {
+		return err
+	}
+
+	var conn *executor.Executor
+	var getCreateTable rewrite.GetCreateTableFunc
+	if instance != nil {
+		conn, err = executor.NewExecutor(l, &driverV2.DSN{
+			Host:             instance.Host,
+			Port:             instance.Port,
+			User:             instance.User,
+			Password:         instance.Password,
+			AdditionalParams: instance.AdditionalParams,
+			DatabaseName:     record.SchemaName,
+		}, record.SchemaName)
+		if err != nil {
+			return fmt.Errorf("connect to instance failed: %v", err)
+		}
+		defer conn.Db.Close()
+
+		ctx := session.NewContext(nil, session.WithExecutor(conn))
+		ctx.SetCurrentSchema(record.SchemaName)
+		getCreateTable = func(table *ast.TableName) (*ast.CreateTableStmt, bool) {
+			stmt, exist, err := ctx.GetCreateTableStmt(table)
+			if err != nil {
+				l.Warnf("get create table statement of %v failed: %v", table.Name.O, err)
+				return nil, false
+			}
+			return stmt, exist
+		}
+	}
+	rewriter := rewrite.NewRewriter(rules, getCreateTable)
+
+	var explainedCount int
+	var totalImprove float64
+	for i, sql := range sqls {
+		optimizedSQL := &model.SQLOptimizationRecordSQL{
+			Number:       uint64(i + 1),
+			OriginalSQL:  sql,
+			OptimizedSQL: sql,
+		}
+		record.SQLs = append(record.SQLs, optimizedSQL)
+
+		result, err := rewriter.Rewrite(sql)
+		if err != nil {
+			optimizedSQL.SyntaxError = true
+			record.NumberOfSyntaxError++
+			continue
+		}
+		triggeredRules := make([]SQLOptimizationTriggeredRule, 0, len(result.TriggeredRules))
+		for _, triggered := range result.TriggeredRules {
+			rule := SQLOptimizationTriggeredRule{
+				RuleCode:     triggered.RuleCode,
+				Message:      triggered.Message,
+				RewrittenSQL: triggered.RewrittenSQL,
+			}
+			if r, ok := optimization.GetOptimizationRuleByRuleCode(triggered.RuleCode, record.DBType); ok {
+				rule.RuleName = r.Name
+			}
+			triggeredRules = append(triggeredRules, rule)
+		}
+		optimizedSQL.TriggeredRules, err = json.Marshal(triggeredRules)
+		if err != nil {
+			return err
+		}
+		optimizedSQL.OptimizedSQL = result.RewrittenSQL
+		optimizedSQL.NumberOfRewrite = len(result.TriggeredRules)
+		record.NumberOfRewrite += len(result.TriggeredRules)
+		if result.IsRewritten() {
+			record.NumberOfRewrittenQuery++
+		}
+
+		if conn == nil {
+			continue
+		}
+		optimizedSQL.BeforeCost, optimizedSQL.BeforePlan, err = explainSQLCost(conn, sql)
+		if err != nil {
+			l.Warnf("explain sql %v failed: %v", sql, err)
+			continue
+		}
+		optimizedSQL.AfterCost, optimizedSQL.AfterPlan = optimizedSQL.BeforeCost, optimizedSQL.BeforePlan
+		if result.IsRewritten() {
+			optimizedSQL.AfterCost, optimizedSQL.AfterPlan, err = explainSQLCost(conn, result.RewrittenSQL)
+			if err != nil {
+				l.Warnf("explain rewritten sql %v failed: %v", result.RewrittenSQL, err)
+				continue
+			}
+		}
+		if optimizedSQL.BeforeCost > 0 {
+			optimizedSQL.PerformanceImprove = (optimizedSQL.BeforeCost - optimizedSQL.AfterCost) / optimizedSQL.BeforeCost
+			totalImprove += optimizedSQL.PerformanceImprove
+			explainedCount++
+		}
+	}
+	record.NumberOfQuery = len(sqls)
+	if explainedCount > 0 {
+		record.PerformanceGain = totalImprove / float64(explainedCount)
+	}
+	record.Status = model.SQLOptimizationStatusFinish
+	return nil
+}
+
+// getSQLOptimizationRules returns the rewrite rules enabled in the rule template of instance,
+// it returns nil which means all rules are enabled if instance is nil.
+func getSQLOptimizationRules(instance *model.Instance) (map[string]params.Params, error) {
+	if instance == nil {
+		return nil, nil
+	}
+	rules, err := model.GetStorage().GetAllOptimizationRulesByInstance(instance)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		return nil, nil
+	}
+	ruleCodes := make(map[string]string)
+	for _, handler := range optimization.OptimizationRuleMap[instance.DbType] {
+		ruleCodes[handler.Rule.Name] = handler.RuleCode
+	}
+	enabled := make(map[string]params.Params, len(rules))
+	for _, rule := range rules {
+		if code, ok := ruleCodes[rule.Name]; ok {
+			enabled[code] = rule.Params
+		}
+	}
+	return enabled, nil
+}
+
+// explainSQLCost returns the query cost and the plan in JSON format of the SQL.
+func explainSQLCost(conn *executor.Executor, sql string) (float64, string, error) {
+	_, rows, err := conn.Db.QueryWithContext(context.TODO(), fmt.Sprintf("EXPLAIN FORMAT=JSON %s", sql))
+	if err != nil {
+		return 0, "", err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return 0, "", fmt.Errorf("no explain record for sql %v", sql)
+	}
+	plan := rows[0][0].String
+	cost, err := parseExplainCost(plan)
+	return cost, plan, err
+}
+
+func parseExplainCost(plan string) (float64, error) {
+	explain := struct {
+		QueryBlock struct {
+			CostInfo struct {
+				QueryCost json.RawMessage `json:"query_cost"`
+			} `json:"cost_info"`
+		} `json:"query_block"`
+	}{}
+	if err := json.Unmarshal([]byte(plan), &explain); err != nil {
+		return 0, err
+	}
+	cost := explain.QueryBlock.CostInfo.QueryCost
+	if len(cost) == 0 {
+		// the plan has no cost info, such as the plan of UPDATE or DELETE
+		return 0, nil
+	}
+	// the query cost is a string in MySQL 5.7 and later
+	if unquoted, err := strconv.Unquote(string(cost)); err == nil {
+		return strconv.ParseFloat(unquoted, 64)
+	}
+	return strconv.ParseFloat(string(cost), 64)
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	optimization "github.com/actiontech/sqle/sqle/server/optimization/rule"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimizeSQLsWithoutInstance(t *testing.T) {
+	record := &model.SQLOptimizationRecord{DBType: driverV2.DriverTypeMySQL}
+	err := OptimizeSQLs(log.NewEntry(), record, nil, []string{
+		"DELETE FROM t1",
+		"SELECT * FROM t1 WHERE a = 1",
+		"SELECT * FROM",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.SQLOptimizationStatusFinish, record.Status)
+	assert.Equal(t, 3, record.NumberOfQuery)
+	assert.Equal(t, 1, record.NumberOfSyntaxError)
+	assert.Equal(t, 1, record.NumberOfRewrite)
+	assert.Equal(t, 1, record.NumberOfRewrittenQuery)
+	assert.Len(t, record.SQLs, 3)
+
+	assert.Equal(t, "TRUNCATE TABLE `t1`", record.SQLs[0].OptimizedSQL)
+	rules := []SQLOptimizationTriggeredRule{}
+	assert.NoError(t, json.Unmarshal(record.SQLs[0].TriggeredRules, &rules))
+	assert.Len(t, rules, 1)
+	assert.Equal(t, optimization.RuleDelete2TruncateRewrite, rules[0].RuleCode)
+	assert.Equal(t, rulepkg.DMLHintUseTruncateInsteadOfDelete, rules[0].RuleName)
+
+	assert.Equal(t, 0, record.SQLs[1].NumberOfRewrite)
+	assert.True(t, record.SQLs[2].SyntaxError)
+
+	err = OptimizeSQLs(log.NewEntry(), &model.SQLOptimizationRecord{DBType: "Oracle"}, nil, []string{"SELECT 1 FROM dual"})
+	assert.Error(t, err)
+}
+
+func TestParseExplainCost(t *testing.T) {
+	cost, err := parseExplainCost(`{"query_block": {"select_id": 1, "cost_info": {"query_cost": "12.50"}}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 12.5, cost)
+
+	cost, err = parseExplainCost(`{"query_block": {"cost_info": {"query_cost": 3}}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 3.0, cost)
+
+	cost, err = parseExplainCost(`{"query_block": {"select_id": 1, "table": {"update": true}}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, cost)
+
+	_, err = parseExplainCost("not json")
+	assert.Error(t, err)
+}
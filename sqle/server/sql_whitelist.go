@@ -0,0 +1,169 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+// whitelistCacheSize limits the memory of cache, the cache is reset when it is full.
+const whitelistCacheSize = 10000
+
+// whitelistCache caches the fingerprints and compiled regexps of whitelist,
+// the key contains the update time so that the updated whitelist is reloaded.
+type whitelistCache struct {
+	sync.Mutex
+	entries map[string]*whitelistCacheEntry
+}
+
+type whitelistCacheEntry struct {
+	fingerprint string
+	regexp      *regexp.Regexp
+	err         error
+}
+
+var sqlWhitelistCache = &whitelistCache{entries: map[string]*whitelistCacheEntry{}}
+
+func (c *whitelistCache) get(l *logrus.Entry, p driver.Plugin, dbType string, wl *model.SqlWhitelist) *whitelistCacheEntry {
+	key := fmt.Sprintf("%s:%d:%d", dbType, wl.ID, wl.UpdatedAt.UnixNano())
+	c.Lock()
+	entry, ok := c.entries[key]
+	c.Unlock()
+	if ok {
+		return entry
+	}
+
+	entry = &whitelistCacheEntry{}
+	switch wl.MatchType {
+	case model.SQLWhitelistFPMatch:
+		node, err := parse(l, p, wl.Value)
+		entry.fingerprint, entry.err = node.Fingerprint, err
+	case model.SQLWhitelistRegexMatch:
+		entry.regexp, entry.err = regexp.Compile(wl.Value)
+	}
+	// the whitelist created before saving is not cached, its id is zero.
+	if wl.ID == 0 {
+		return entry
+	}
+	c.Lock()
+	if len(c.entries) >= whitelistCacheSize {
+		c.entries = map[string]*whitelistCacheEntry{}
+	}
+	c.entries[key] = entry
+	c.Unlock()
+	return entry
+}
+
+// whitelistMatcher matches the audited SQLs with the whitelist in the scope of task.
+type whitelistMatcher struct {
+	l         *logrus.Entry
+	p         driver.Plugin
+	dbType    string
+	whitelist []*model.SqlWhitelist
+	// matchedCounts records the hits of whitelist by id.
+	matchedCounts map[uint]uint64
+}
+
+func newWhitelistMatcher(l *logrus.Entry, task *model.Task, p driver.Plugin, whitelist []model.SqlWhitelist) *whitelistMatcher {
+	instanceName := ""
+	dbType := task.DBType
+	if task.Instance != nil {
+		instanceName = task.Instance.Name
+		if dbType == "" {
+			dbType = task.Instance.DbType
+		}
+	}
+	now := time.Now()
+	m := &whitelistMatcher{l: l, p: p, dbType: dbType, matchedCounts: map[uint]uint64{}}
+	for i := range whitelist {
+		wl := &whitelist[i]
+		if wl.IsExpired(now) || !inScope(wl.InstanceNames, instanceName) || !inScope(wl.SchemaNames, task.Schema) {
+			continue
+		}
+		m.whitelist = append(m.whitelist, wl)
+	}
+	return m
+}
+
+// inScope returns true if the scope is empty or contains the name.
+func inScope(scope []string, name string) bool {
+	if len(scope) == 0 {
+		return true
+	}
+	for _, s := range scope {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns whether all rules are skipped for the node, otherwise returns
+// the names of rules skipped by the matched whitelist.
+func (m *whitelistMatcher) match(node driverV2.Node) (skipAll bool, skippedRules map[string]struct{}) {
+	skippedRules = map[string]struct{}{}
+	for _, wl := range m.whitelist {
+		if !m.matchOne(wl, node) {
+			continue
+		}
+		m.matchedCounts[wl.ID]++
+		if len(wl.RuleNames) == 0 {
+			skipAll = true
+		}
+		for _, ruleName := range wl.RuleNames {
+			skippedRules[ruleName] = struct{}{}
+		}
+	}
+	return skipAll, skippedRules
+}
+
+func (m *whitelistMatcher) matchOne(wl *model.SqlWhitelist, node driverV2.Node) bool {
+	switch wl.MatchType {
+	case model.SQLWhitelistFPMatch, model.SQLWhitelistRegexMatch:
+		entry := sqlWhitelistCache.get(m.l, m.p, m.dbType, wl)
+		if entry.err != nil {
+			m.l.Errorf("invalid whitelist %d: %v, please check the accuracy of whitelist SQL: %s", wl.ID, entry.err, wl.Value)
+			return false
+		}
+		if entry.regexp != nil {
+			return entry.regexp.MatchString(node.Text)
+		}
+		return node.Fingerprint == entry.fingerprint
+	default:
+		return wl.CapitalizedValue == strings.ToUpper(node.Text)
+	}
+}
+
+// saveMatchedCounts updates the hit counters of whitelist, the audit is not
+// failed if it can not be saved.
+func (m *whitelistMatcher) saveMatchedCounts() {
+	if len(m.matchedCounts) == 0 {
+		return
+	}
+	if err := model.GetStorage().IncreaseSqlWhitelistMatchedCount(m.matchedCounts, time.Now()); err != nil {
+		m.l.Errorf("update matched count of whitelist failed: %v", err)
+	}
+}
+
+// skipRules removes the audit results of skipped rules from results.
+func skipRules(results *driverV2.AuditResults, skippedRules map[string]struct{}) {
+	if len(skippedRules) == 0 {
+		return
+	}
+	kept := make([]*driverV2.AuditResult, 0, len(results.Results))
+	for _, result := range results.Results {
+		if _, ok := skippedRules[result.RuleName]; ok && result.RuleName != "" {
+			continue
+		}
+		kept = append(kept, result)
+	}
+	results.Results = kept
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhitelistMatcher(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	notExpired := time.Now().Add(time.Hour)
+	whitelist := []model.SqlWhitelist{
+		{Model: model.Model{ID: 1}, MatchType: model.SQLWhitelistExactMatch, Value: "select 1", CapitalizedValue: "SELECT 1"},
+		{Model: model.Model{ID: 2}, MatchType: model.SQLWhitelistRegexMatch, Value: `(?i)^delete from t1\b`,
+			RuleNames: model.RowList{"dml_check_where_is_invalid"}, ExpiredAt: &notExpired},
+		{Model: model.Model{ID: 3}, MatchType: model.SQLWhitelistExactMatch, Value: "select 2", CapitalizedValue: "SELECT 2",
+			ExpiredAt: &expired},
+		{Model: model.Model{ID: 4}, MatchType: model.SQLWhitelistExactMatch, Value: "select 3", CapitalizedValue: "SELECT 3",
+			InstanceNames: model.RowList{"inst_1"}, SchemaNames: model.RowList{"db_1"}},
+		{Model: model.Model{ID: 5}, MatchType: model.SQLWhitelistRegexMatch, Value: `(`},
+	}
+	task := &model.Task{Instance: &model.Instance{Name: "inst_1"}, Schema: "db_2"}
+	m := newWhitelistMatcher(log.NewEntry(), task, &mockDriver{}, whitelist)
+
+	skipAll, skippedRules := m.match(driverV2.Node{Text: "SELECT 1"})
+	assert.True(t, skipAll)
+	assert.Len(t, skippedRules, 0)
+
+	skipAll, skippedRules = m.match(driverV2.Node{Text: "DELETE FROM t1"})
+	assert.False(t, skipAll)
+	assert.Equal(t, map[string]struct{}{"dml_check_where_is_invalid": {}}, skippedRules)
+
+	// expired
+	skipAll, _ = m.match(driverV2.Node{Text: "select 2"})
+	assert.False(t, skipAll)
+
+	// out of scope
+	skipAll, _ = m.match(driverV2.Node{Text: "select 3"})
+	assert.False(t, skipAll)
+	task.Schema = "db_1"
+	m2 := newWhitelistMatcher(log.NewEntry(), task, &mockDriver{}, whitelist)
+	skipAll, _ = m2.match(driverV2.Node{Text: "select 3"})
+	assert.True(t, skipAll)
+
+	assert.Equal(t, map[uint]uint64{1: 1, 2: 1}, m.matchedCounts)
+}
+
+func TestWhitelistCache(t *testing.T) {
+	wl := &model.SqlWhitelist{Model: model.Model{ID: 100, UpdatedAt: time.Now()}, MatchType: model.SQLWhitelistFPMatch, Value: "select 1"}
+	entry := sqlWhitelistCache.get(log.NewEntry(), &mockDriver{parseError: true}, driverV2.DriverTypeMySQL, wl)
+	assert.Error(t, entry.err)
+	// the parse result is cached until the whitelist is updated.
+	entry = sqlWhitelistCache.get(log.NewEntry(), &mockDriver{}, driverV2.DriverTypeMySQL, wl)
+	assert.Error(t, entry.err)
+	wl.UpdatedAt = wl.UpdatedAt.Add(time.Second)
+	entry = sqlWhitelistCache.get(log.NewEntry(), &mockDriver{}, driverV2.DriverTypeMySQL, wl)
+	assert.NoError(t, entry.err)
+}
+
+func TestSkipRules(t *testing.T) {
+	results := driverV2.NewAuditResults()
+	results.Add(driverV2.RuleLevelError, "rule_1", "message 1")
+	results.Add(driverV2.RuleLevelWarn, "rule_2", "message 2")
+	results.Add(driverV2.RuleLevelError, "", "schema not exist")
+	skipRules(results, map[string]struct{}{"rule_1": {}, "": {}})
+	assert.Len(t, results.Results, 2)
+	assert.Equal(t, "", results.Results[0].RuleName)
+	assert.Equal(t, "rule_2", results.Results[1].RuleName)
+}
@@ -9,16 +9,19 @@ import (
 	"sync"
 	"time"
 
+	"github.com/actiontech/sqle/sqle/config"
 	"github.com/actiontech/sqle/sqle/dms"
 	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/utils"
 	"github.com/go-sql-driver/mysql"
 
 	_ "github.com/actiontech/sqle/sqle/driver/mysql"
+	_ "github.com/actiontech/sqle/sqle/driver/postgresql"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/eventbus"
 	xerrors "github.com/pkg/errors"
 
 	"github.com/sirupsen/logrus"
@@ -40,15 +43,16 @@ type Sqled struct {
 	// currentTask record the current task before execution,
 	// and delete it after execution.
 	currentTask map[string]struct{}
-	// queue is a chan used to receive tasks.
-	queue chan *action
+	// queue is used to receive tasks.
+	queue actionQueue
 }
 
 func InitSqled(exit chan struct{}) {
+	opts := config.GetOptions().SqleOptions
 	sqled = &Sqled{
 		exit:        exit,
 		currentTask: map[string]struct{}{},
-		queue:       make(chan *action, 1024),
+		queue:       newActionQueue(opts.Service.ActionQueueBackend, fmt.Sprintf("%v", opts.ID)),
 	}
 	sqled.Start()
 }
@@ -63,6 +67,23 @@ func (s *Sqled) HasTask(taskId string) bool {
 // addTask receive taskId and action type, using taskId and typ to create an action;
 // action will be validated, and sent to Sqled.queue.
 func (s *Sqled) addTask(taskId string, typ int) (*action, error) {
+	action, err := s.newAction(taskId, typ)
+	if err != nil {
+		return action, err
+	}
+	if err := s.queue.push(action); err != nil {
+		action.plugin.Close(context.TODO())
+		s.Lock()
+		delete(s.currentTask, taskId)
+		s.Unlock()
+		return action, err
+	}
+	return action, nil
+}
+
+// newAction creates a validated action, the task is taken as running until
+// the action is done.
+func (s *Sqled) newAction(taskId string, typ int) (*action, error) {
 	var err error
 	var p driver.Plugin
 	var rules []*model.Rule
@@ -106,6 +127,9 @@ func (s *Sqled) addTask(taskId string, typ int) (*action, error) {
 		}
 
 		task.Instance = instance
+		if err = applyTaskGhostConfig(task); err != nil {
+			goto Error
+		}
 	}
 
 	if err = action.validation(task); err != nil {
@@ -126,8 +150,6 @@ func (s *Sqled) addTask(taskId string, typ int) (*action, error) {
 	action.customRules = customRules
 	action.rules = rules
 
-	s.queue <- action
-
 	return action, nil
 
 Error:
@@ -152,6 +174,7 @@ func (s *Sqled) AddTaskWaitResult(taskId string, typ int) (*model.Task, error) {
 }
 
 func (s *Sqled) Start() {
+	s.queue.start(s)
 	go s.taskLoop()
 }
 
@@ -161,8 +184,14 @@ func (s *Sqled) taskLoop() {
 		select {
 		case <-s.exit:
 			return
-		case action := <-s.queue:
+		case action := <-s.queue.actions():
 			go func() {
+				release, err := scheduler.acquire(context.Background(), action.slotRequest())
+				if err != nil {
+					log.NewEntry().Error("sqled task loop acquire slot failed, error:", err)
+					return
+				}
+				defer release()
 				if err := s.do(action); err != nil {
 					log.NewEntry().Error("sqled task loop do action failed, error:", err)
 				}
@@ -180,6 +209,10 @@ func (s *Sqled) do(action *action) error {
 		err = action.execute()
 	case ActionTypeRollback:
 		err = action.rollback()
+	case ActionTypeRehearsal:
+		err = action.rehearse()
+	case ActionTypeResume:
+		err = action.execute()
 	}
 	if err != nil {
 		action.err = err
@@ -187,6 +220,8 @@ func (s *Sqled) do(action *action) error {
 
 	action.plugin.Close(context.TODO())
 
+	s.queue.done(action)
+
 	s.Lock()
 	taskId := fmt.Sprintf("%d", action.task.ID)
 	delete(s.currentTask, taskId)
@@ -201,6 +236,12 @@ const (
 	ActionTypeAudit = iota + 1
 	ActionTypeExecute
 	ActionTypeRollback
+	// ActionTypeRehearsal executes the SQLs of task without changing the data
+	// or schema, to find the problems before the real execution.
+	ActionTypeRehearsal
+	// ActionTypeResume continues the failed execution of task from its
+	// checkpoint, see model.TaskExecutionAttempt.
+	ActionTypeResume
 )
 
 // Action is an action for the task;
@@ -222,6 +263,17 @@ type action struct {
 
 	customRules []*model.CustomRule
 	rules       []*model.Rule
+
+	// binlog is not nil if the rollback SQLs are generated by binlog after execution.
+	binlog *binlogRecorder
+
+	// attempt is the execution attempt of execute and resume action.
+	attempt *model.TaskExecutionAttempt
+	// executeSQLs are the SQLs to execute in the attempt.
+	executeSQLs []*model.ExecuteSQL
+
+	// claimToken is the claim token of the action persisted by the storage action queue.
+	claimToken string
 }
 
 const (
@@ -261,6 +313,11 @@ var (
 	ErrActionRollbackOnRollbackedTask    = _errors.New("task has been rollbacked, can not do rollback on it")
 	ErrActionRollbackOnExecuteFailedTask = _errors.New("task has been executed failed, can not do rollback on it")
 	ErrActionRollbackOnNonExecutedTask   = _errors.New("task has not been executed, can not do rollback on it")
+	ErrActionRehearseOnExecutedTask      = _errors.New("task has been executed, can not do rehearsal on it")
+	ErrActionRehearseOnNonAuditedTask    = _errors.New("task has not been audited, can not do rehearsal on it")
+	ErrActionRehearseOnUnsupportedDBType = _errors.New("rehearsal only supports MySQL")
+	ErrActionResumeOnNonFailedTask       = _errors.New("task is not executed failed, can not resume it")
+	ErrActionResumeOnSqlFileModeTask     = _errors.New("task is executed in sql file mode, can not resume it")
 )
 
 // validation validate whether task can do action type(a.typ) or not.
@@ -286,6 +343,23 @@ func (a *action) validation(task *model.Task) error {
 		if !task.HasDoingExecute() {
 			return errors.New(errors.TaskActionInvalid, ErrActionRollbackOnNonExecutedTask)
 		}
+	case ActionTypeRehearsal:
+		if task.DBType != driverV2.DriverTypeMySQL {
+			return errors.New(errors.TaskActionInvalid, ErrActionRehearseOnUnsupportedDBType)
+		}
+		if task.HasDoingExecute() {
+			return errors.New(errors.TaskActionDone, ErrActionRehearseOnExecutedTask)
+		}
+		if !task.HasDoingAudit() {
+			return errors.New(errors.TaskActionInvalid, ErrActionRehearseOnNonAuditedTask)
+		}
+	case ActionTypeResume:
+		if task.Status != model.TaskStatusExecuteFailed {
+			return errors.New(errors.TaskActionInvalid, ErrActionResumeOnNonFailedTask)
+		}
+		if task.ExecMode == model.ExecModeSqlFile {
+			return errors.New(errors.TaskActionInvalid, ErrActionResumeOnSqlFileModeTask)
+		}
 	}
 	return nil
 }
@@ -335,6 +409,9 @@ func (a *action) audit() (err error) {
 		a.entry.Errorf("update task error:%v", err)
 		return err
 	}
+	if a.task.Instance != nil {
+		eventbus.Publish(eventbus.EventTaskAudited, a.task.Instance.ProjectId, eventbus.NewTaskEventData(a.task))
+	}
 	return nil
 }
 
@@ -352,6 +429,10 @@ func (a *action) execute() (err error) {
 
 	a.entry.Info("start execution...")
 
+	if err = a.startExecutionAttempt(); err != nil {
+		return err
+	}
+
 	attrs := map[string]interface{}{
 		"status":        model.TaskStatusExecuting,
 		"exec_start_at": time.Now(),
@@ -360,6 +441,9 @@ func (a *action) execute() (err error) {
 		return err
 	}
 
+	a.binlog = newBinlogRecorder(a.entry, task)
+	defer a.binlog.close()
+
 	exeErrChan := make(chan error)
 	terminateErrChan := make(chan error)
 
@@ -407,6 +491,11 @@ func (a *action) execute() (err error) {
 				break
 			}
 		}
+		// the SQLs after the retried one are not executed yet.
+		if a.typ == ActionTypeResume && !a.isCheckpointAtEnd() {
+			taskStatus = model.TaskStatusExecuteFailed
+		}
+		a.genRollbackSQLsByBinlog()
 
 	case terminationErr := <-terminateErrChan:
 		if terminationErr != nil {
@@ -438,6 +527,7 @@ func (a *action) execute() (err error) {
 		Infof("execution is completed, err:%v", err)
 
 	a.task.Status = taskStatus
+	a.finishExecutionAttempt(taskStatus)
 
 	attrs = map[string]interface{}{
 		"status":      taskStatus,
@@ -483,11 +573,11 @@ func (a *action) execTask() (err error) {
 
 func (a *action) execSqlSqlMode() error {
 	// txSQLs keep adjacent DMLs, execute in one transaction.
-	task := a.task
+	executeSQLs := a.executeSQLs
 	var txSQLs []*model.ExecuteSQL
 	var err error
-	for i := range task.ExecuteSQLs {
-		executeSQL := task.ExecuteSQLs[i]
+	for i := range executeSQLs {
+		executeSQL := executeSQLs[i]
 		var nodes []driverV2.Node
 		if nodes, err = a.plugin.Parse(context.TODO(), executeSQL.Content); err != nil {
 			return err
@@ -496,10 +586,11 @@ func (a *action) execSqlSqlMode() error {
 		switch nodes[0].Type {
 		case driverV2.SQLTypeDML, driverV2.SQLTypeDQL:
 			txSQLs = append(txSQLs, executeSQL)
-			if i == len(task.ExecuteSQLs)-1 {
+			if i == len(executeSQLs)-1 {
 				if err = a.execSQLs(txSQLs); err != nil {
 					return err
 				}
+				a.saveCheckpoint(executeSQL)
 			}
 
 		default:
@@ -507,11 +598,13 @@ func (a *action) execSqlSqlMode() error {
 				if err = a.execSQLs(txSQLs); err != nil {
 					return err
 				}
+				a.saveCheckpoint(txSQLs[len(txSQLs)-1])
 				txSQLs = nil
 			}
 			if err = a.execSQL(executeSQL); err != nil {
 				return err
 			}
+			a.saveCheckpoint(executeSQL)
 		}
 	}
 	return nil
@@ -620,7 +713,9 @@ func (a *action) executeSQLBatch(executeSQLs []*model.ExecuteSQL) error {
 		sqls = append(sqls, sql.Content)
 	}
 
+	start := a.binlog.position()
 	results, execErr := a.plugin.ExecBatch(context.TODO(), sqls...)
+	a.binlog.record(start, executeSQLs...)
 	if execErr != nil {
 		for idx, executeSQL := range executeSQLs {
 			executeSQL.ExecStatus = model.SQLExecuteStatusFailed
@@ -658,7 +753,9 @@ func (a *action) execSQL(executeSQL *model.ExecuteSQL) error {
 		return err
 	}
 
-	_, execErr := a.plugin.Exec(context.TODO(), executeSQL.Content)
+	start := a.binlog.position()
+	_, execErr := a.plugin.Exec(newGhostContext(a.entry, a.task, executeSQL), executeSQL.Content)
+	a.binlog.record(start, executeSQL)
 	if execErr != nil {
 		executeSQL.ExecStatus = model.SQLExecuteStatusFailed
 		executeSQL.ExecResult = execErr.Error()
@@ -694,7 +791,9 @@ func (a *action) execSQLs(executeSQLs []*model.ExecuteSQL) error {
 		qs = append(qs, executeSQL.Content)
 	}
 
+	start := a.binlog.position()
 	results, txErr := a.plugin.Tx(context.TODO(), qs...)
+	a.binlog.record(start, executeSQLs...)
 	for idx, executeSQL := range executeSQLs {
 		if txErr != nil {
 			executeSQL.ExecStatus = model.SQLExecuteStatusFailed
@@ -737,6 +836,11 @@ ExecSQLs:
 		if rollbackSQL.Content == "" {
 			continue
 		}
+		// the SQL skipped by user changes nothing.
+		if executeSQL := task.GetExecuteSQLById(rollbackSQL.ExecuteSQLId); executeSQL != nil &&
+			executeSQL.ExecStatus == model.SQLExecuteStatusSkipped {
+			continue
+		}
 		if err = st.UpdateRollbackSqlStatus(&rollbackSQL.BaseSQL, model.SQLExecuteStatusDoing, ""); err != nil {
 			return err
 		}
@@ -171,7 +171,13 @@ func Test_action_audit_UpdateTask(t *testing.T) {
 
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sql_whitelist` WHERE sql_whitelist.project_id = ? AND `sql_whitelist`.`deleted_at` IS NULL")).
 		WithArgs("").
-		WillReturnRows(sqlmock.NewRows([]string{"value", "match_type"}).AddRow(whitelist.Value, whitelist.MatchType))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "value", "match_type"}).AddRow(1, whitelist.Value, whitelist.MatchType))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sql_whitelist` SET `last_matched_time`=?,`matched_count`=matched_count + ? WHERE id = ?")).
+		WithArgs(sqlmock.AnyArg(), 1, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `execute_sql_detail`")).
@@ -213,6 +219,14 @@ func Test_action_execute(t *testing.T) {
 			return nil
 		})
 
+		gomonkey.ApplyMethod(reflect.TypeOf(&model.Storage{}), "CreateTaskExecutionAttempt", func(_ *model.Storage, _ *model.TaskExecutionAttempt) error {
+			return nil
+		})
+		gomonkey.ApplyMethod(reflect.TypeOf(&model.Storage{}), "FinishTaskExecutionAttempt", func(_ *model.Storage, attempt *model.TaskExecutionAttempt) error {
+			assert.Equal(t, model.ExecutionAttemptStatusFailed, attempt.Status)
+			return nil
+		})
+
 		gomonkey.ApplyMethod(reflect.TypeOf(&model.Storage{}), "GetRulesFromRuleTemplateByName", func(_ *model.Storage, _ []string, _ string) ([]*model.Rule, []*model.CustomRule, error) {
 			return nil, nil, nil
 		})
@@ -22,7 +22,7 @@ func NewModuleStatusChecker(driverType string, moduleName string) (StatusChecker
 	case executeSqlFileMode:
 		return executeSqlFileChecker{driverType: driverType, moduleName: moduleName}, nil
 	case sqlOptimization:
-		return sqlOptimizationChecker{}, nil
+		return sqlOptimizationChecker{driverType: driverType}, nil
 	}
 	return nil, fmt.Errorf("no checker mached")
 }
@@ -36,9 +36,15 @@ func (checker executeSqlFileChecker) CheckIsSupport() bool {
 	return driver.GetPluginManager().IsOptionalModuleEnabled(checker.driverType, driverV2.OptionalExecBatch)
 }
 
-type sqlOptimizationChecker struct{}
+type sqlOptimizationChecker struct {
+	driverType string
+}
 
 func (s sqlOptimizationChecker) CheckIsSupport() bool {
+	// MySQL is optimized by the built-in rewrite engine without the external optimization service
+	if s.driverType == driverV2.DriverTypeMySQL {
+		return true
+	}
 	return config.GetOptions().SqleOptions.OptimizationConfig.OptimizationKey != "" &&
 		config.GetOptions().SqleOptions.OptimizationConfig.OptimizationURL != ""
 }
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	"github.com/actiontech/sqle/sqle/driver/mysql/rehearsal"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+// rehearse executes the SQLs of task without changing the data or schema, the
+// results are saved as the rehearsal report of task.
+func (a *action) rehearse() (err error) {
+	st := model.GetStorage()
+	task := a.task
+
+	if err = st.UpdateTask(task, map[string]interface{}{"rehearsal_status": model.RehearsalStatusRehearsing}); err != nil {
+		return err
+	}
+
+	rehearsalSQLs, err := a.rehearseSQLs()
+	now := time.Now()
+	task.RehearsedAt = &now
+	task.RehearsalStatus = model.RehearsalStatusSucceeded
+	if err != nil {
+		a.entry.Errorf("rehearse task failed, error: %v", err)
+		task.RehearsalStatus = model.RehearsalStatusFailed
+	}
+	for _, rehearsalSQL := range rehearsalSQLs {
+		if rehearsalSQL.Status == model.RehearsalSQLStatusFailed {
+			task.RehearsalStatus = model.RehearsalStatusFailed
+		}
+	}
+	if saveErr := st.SaveRehearsalSQLs(task, rehearsalSQLs); saveErr != nil {
+		a.entry.Errorf("save rehearsal SQLs error: %v", saveErr)
+		return saveErr
+	}
+	return err
+}
+
+func (a *action) rehearseSQLs() ([]*model.RehearsalSQL, error) {
+	task := a.task
+	conn, err := executor.NewExecutor(a.entry, newDSNByTask(task), task.Schema)
+	if err != nil {
+		return nil, err
+	}
+	// the uncommitted changes are rolled back when the connection is closed.
+	defer conn.Db.Close()
+
+	sqls := make([]string, 0, len(task.ExecuteSQLs))
+	for _, executeSQL := range task.ExecuteSQLs {
+		sqls = append(sqls, executeSQL.Content)
+	}
+	results, err := rehearsal.NewRehearser(a.entry, conn, newDSNByTask(task), task.Schema).
+		Rehearse(context.TODO(), sqls)
+
+	rehearsalSQLs := make([]*model.RehearsalSQL, 0, len(results))
+	for idx, result := range results {
+		executeSQL := task.ExecuteSQLs[idx]
+		rehearsalSQLs = append(rehearsalSQLs, &model.RehearsalSQL{
+			TaskId:        task.ID,
+			ExecuteSQLId:  executeSQL.ID,
+			Number:        executeSQL.Number,
+			Content:       executeSQL.Content,
+			Method:        result.Method,
+			Status:        result.Status,
+			RowsAffected:  result.RowsAffected,
+			ExecutionTime: uint64(result.Duration.Milliseconds()),
+			Message:       result.Message,
+		})
+	}
+	return rehearsalSQLs, err
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/notification"
+)
+
+// ResumeTaskExecution creates an attempt to resume the failed execution of
+// task, the task is executed in background.
+func ResumeTaskExecution(workflow *model.Workflow, taskId uint, operation, reason, userId string) error {
+	s := model.GetStorage()
+	l := log.NewEntry().WithField("task_id", taskId)
+
+	// check the operation before the attempt is created, so the invalid operation is refused at once.
+	task, exist, err := s.GetTaskDetailById(strconv.Itoa(int(taskId)))
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.NewTaskNoExistOrNoAccessErr()
+	}
+	if _, err := getResumeExecuteSQLs(task, operation); err != nil {
+		return err
+	}
+
+	attempt := &model.TaskExecutionAttempt{
+		TaskId:         taskId,
+		Operation:      operation,
+		Status:         model.ExecutionAttemptStatusPending,
+		OperatorUserId: userId,
+		Reason:         reason,
+	}
+	if err := s.CreateTaskExecutionAttempt(attempt); err != nil {
+		return err
+	}
+	action, err := GetSqled().addTask(strconv.Itoa(int(taskId)), ActionTypeResume)
+	if err != nil {
+		if err := s.DeleteTaskExecutionAttempt(attempt); err != nil {
+			l.Errorf("delete execution attempt failed, error: %v", err)
+		}
+		return err
+	}
+
+	if err := s.UpdateWorkflowRecordByID(workflow.Record.ID, map[string]interface{}{
+		"status": model.WorkflowStatusExecuting,
+	}); err != nil {
+		l.Errorf("update workflow record status failed: %v", err)
+	}
+
+	go func() {
+		<-action.done
+		updateStatus(s, workflow, l)
+		if action.err != nil || action.task.Status == model.TaskStatusExecuteFailed {
+			notification.NotifyWorkflow(string(workflow.ProjectId), workflow.WorkflowId, notification.WorkflowNotifyTypeExecuteFail)
+		} else {
+			notification.NotifyWorkflow(string(workflow.ProjectId), workflow.WorkflowId, notification.WorkflowNotifyTypeExecuteSuccess)
+		}
+	}()
+	return nil
+}
+
+// startExecutionAttempt prepares the attempt and the SQLs to execute.
+func (a *action) startExecutionAttempt() error {
+	st := model.GetStorage()
+	task := a.task
+	now := time.Now()
+
+	if a.typ != ActionTypeResume {
+		a.executeSQLs = task.ExecuteSQLs
+		a.attempt = &model.TaskExecutionAttempt{
+			TaskId:      task.ID,
+			Operation:   model.ExecutionAttemptOperationExecute,
+			Status:      model.ExecutionAttemptStatusExecuting,
+			ExecStartAt: &now,
+		}
+		if len(task.ExecuteSQLs) > 0 {
+			a.attempt.FromNumber = task.ExecuteSQLs[0].Number
+		}
+		return st.CreateTaskExecutionAttempt(a.attempt)
+	}
+
+	attempt, exist, err := st.GetPendingTaskExecutionAttempt(task.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.New(errors.DataNotExist, fmt.Errorf("pending execution attempt of task is not exist"))
+	}
+	a.attempt = attempt
+
+	executeSQLs, err := getResumeExecuteSQLs(task, attempt.Operation)
+	if err != nil {
+		if err := st.UpdateTaskExecutionAttempt(attempt, map[string]interface{}{
+			"status":      model.ExecutionAttemptStatusFailed,
+			"exec_end_at": now,
+		}); err != nil {
+			a.entry.Errorf("update execution attempt failed, error: %v", err)
+		}
+		return err
+	}
+	attempt.FromNumber = executeSQLs[0].Number
+	attempt.Status = model.ExecutionAttemptStatusExecuting
+	attempt.ExecStartAt = &now
+	if err := st.UpdateTaskExecutionAttempt(attempt, map[string]interface{}{
+		"from_number":   attempt.FromNumber,
+		"status":        attempt.Status,
+		"exec_start_at": attempt.ExecStartAt,
+	}); err != nil {
+		return err
+	}
+
+	if attempt.Operation == model.ExecutionAttemptOperationSkip {
+		skipped := executeSQLs[0]
+		skipped.ExecStatus = model.SQLExecuteStatusSkipped
+		skipped.ExecResult = attempt.Reason
+		skipped.RowAffects = 0
+		if err := st.Save(skipped); err != nil {
+			return err
+		}
+		a.saveCheckpoint(skipped)
+		executeSQLs = executeSQLs[1:]
+	}
+	a.executeSQLs = executeSQLs
+	return nil
+}
+
+// getResumeExecuteSQLs returns the SQLs handled by the resume operation, the
+// first one is the SQL after the checkpoint of task.
+func getResumeExecuteSQLs(task *model.Task, operation string) ([]*model.ExecuteSQL, error) {
+	idx := -1
+	for i, executeSQL := range task.ExecuteSQLs {
+		if executeSQL.Number > task.ExecCheckpoint {
+			idx = i
+			break
+		}
+		switch executeSQL.ExecStatus {
+		case model.SQLExecuteStatusSucceeded, model.SQLExecuteStatusSkipped, model.SQLExecuteStatusManuallyExecuted:
+		default:
+			return nil, errors.New(errors.TaskActionInvalid,
+				fmt.Errorf("SQL %v before the checkpoint is not executed successfully, can not resume the task", executeSQL.Number))
+		}
+	}
+	if idx < 0 {
+		return nil, errors.New(errors.TaskActionInvalid, fmt.Errorf("all SQLs of task are executed, no need to resume"))
+	}
+	// the checkpoint of task executed before it is supported is always 0.
+	if task.ExecuteSQLs[idx].ExecStatus == model.SQLExecuteStatusSucceeded {
+		return nil, errors.New(errors.TaskActionInvalid,
+			fmt.Errorf("SQL %v after the checkpoint has been executed successfully, the checkpoint of task is unknown", task.ExecuteSQLs[idx].Number))
+	}
+
+	switch operation {
+	case model.ExecutionAttemptOperationSkip:
+		// the SQLs executed in one transaction are all marked failed, the failed one is unknown.
+		if failed := countBatchFailedSQLs(task.ExecuteSQLs[idx:]); failed > 1 {
+			return nil, errors.New(errors.TaskActionInvalid,
+				fmt.Errorf("SQL %v-%v failed in one transaction, the failed SQL is unknown and can not be skipped, please retry or resume the task",
+					task.ExecuteSQLs[idx].Number, task.ExecuteSQLs[idx+failed-1].Number))
+		}
+		return task.ExecuteSQLs[idx:], nil
+	case model.ExecutionAttemptOperationResume:
+		return task.ExecuteSQLs[idx:], nil
+	case model.ExecutionAttemptOperationRetry:
+		return task.ExecuteSQLs[idx : idx+1], nil
+	default:
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("unknown resume operation: %v", operation))
+	}
+}
+
+// countBatchFailedSQLs returns the number of the leading SQLs which are failed
+// or terminated, the execution stops at the failed SQL, so there are more than
+// one only if they are executed in one transaction.
+func countBatchFailedSQLs(executeSQLs []*model.ExecuteSQL) int {
+	count := 0
+	for _, executeSQL := range executeSQLs {
+		if executeSQL.ExecStatus != model.SQLExecuteStatusFailed && executeSQL.ExecStatus != model.SQLExecuteStatusTerminateSucc {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// saveCheckpoint saves the checkpoint of task after the SQL is executed
+// successfully or skipped. The SQLs are always executed in order, so the
+// checkpoint only moves forward.
+func (a *action) saveCheckpoint(executeSQL *model.ExecuteSQL) {
+	a.task.ExecCheckpoint = executeSQL.Number
+	if err := model.GetStorage().UpdateTaskExecCheckpoint(a.task.ID, executeSQL.Number); err != nil {
+		a.entry.Errorf("save execution checkpoint %v failed, error: %v", executeSQL.Number, err)
+	}
+}
+
+func (a *action) isCheckpointAtEnd() bool {
+	executeSQLs := a.task.ExecuteSQLs
+	return len(executeSQLs) == 0 || a.task.ExecCheckpoint >= executeSQLs[len(executeSQLs)-1].Number
+}
+
+// finishExecutionAttempt keeps the results of the SQLs handled in the attempt.
+func (a *action) finishExecutionAttempt(taskStatus string) {
+	attempt := a.attempt
+	if attempt == nil {
+		return
+	}
+	now := time.Now()
+	attempt.ExecEndAt = &now
+	attempt.Checkpoint = a.task.ExecCheckpoint
+	attempt.Status = model.ExecutionAttemptStatusSucceeded
+	if taskStatus != model.TaskStatusExecuteSucceeded {
+		attempt.Status = model.ExecutionAttemptStatusFailed
+	}
+	for _, executeSQL := range a.task.ExecuteSQLs {
+		if executeSQL.Number < attempt.FromNumber || executeSQL.ExecStatus == model.SQLExecuteStatusInitialized {
+			continue
+		}
+		if !a.isHandledInAttempt(executeSQL) {
+			continue
+		}
+		attempt.SQLs = append(attempt.SQLs, &model.ExecutionAttemptSQL{
+			AttemptId:    attempt.ID,
+			ExecuteSQLId: executeSQL.ID,
+			Number:       executeSQL.Number,
+			ExecStatus:   executeSQL.ExecStatus,
+			ExecResult:   executeSQL.ExecResult,
+			RowAffects:   executeSQL.RowAffects,
+		})
+	}
+	if err := model.GetStorage().FinishTaskExecutionAttempt(attempt); err != nil {
+		a.entry.Errorf("save execution attempt failed, error: %v", err)
+	}
+}
+
+func (a *action) isHandledInAttempt(executeSQL *model.ExecuteSQL) bool {
+	if a.attempt.Operation == model.ExecutionAttemptOperationSkip && executeSQL.Number == a.attempt.FromNumber {
+		return true
+	}
+	if a.task.ExecMode == model.ExecModeSqlFile {
+		return true
+	}
+	for _, handled := range a.executeSQLs {
+		if handled == executeSQL {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getResumeExecuteSQLs(t *testing.T) {
+	newTask := func(checkpoint uint, statuses ...string) *model.Task {
+		task := &model.Task{ExecCheckpoint: checkpoint}
+		for idx, status := range statuses {
+			task.ExecuteSQLs = append(task.ExecuteSQLs, &model.ExecuteSQL{
+				BaseSQL: model.BaseSQL{Number: uint(idx + 1), ExecStatus: status},
+			})
+		}
+		return task
+	}
+	numbers := func(executeSQLs []*model.ExecuteSQL) []uint {
+		result := []uint{}
+		for _, executeSQL := range executeSQLs {
+			result = append(result, executeSQL.Number)
+		}
+		return result
+	}
+
+	failedAtSecond := newTask(1, model.SQLExecuteStatusSucceeded, model.SQLExecuteStatusFailed, model.SQLExecuteStatusInitialized)
+	tests := []struct {
+		name      string
+		task      *model.Task
+		operation string
+		expect    []uint
+		expectErr bool
+	}{
+		{"resume", failedAtSecond, model.ExecutionAttemptOperationResume, []uint{2, 3}, false},
+		{"skip", failedAtSecond, model.ExecutionAttemptOperationSkip, []uint{2, 3}, false},
+		{"retry", failedAtSecond, model.ExecutionAttemptOperationRetry, []uint{2}, false},
+		{"unknown operation", failedAtSecond, "unknown", nil, true},
+		{"all executed", newTask(2, model.SQLExecuteStatusSucceeded, model.SQLExecuteStatusSkipped), model.ExecutionAttemptOperationResume, nil, true},
+		{"checkpoint is unknown", newTask(0, model.SQLExecuteStatusSucceeded, model.SQLExecuteStatusFailed), model.ExecutionAttemptOperationResume, nil, true},
+		{"SQL before checkpoint failed", newTask(2, model.SQLExecuteStatusFailed, model.SQLExecuteStatusSucceeded, model.SQLExecuteStatusFailed), model.ExecutionAttemptOperationResume, nil, true},
+		{"terminated", newTask(0, model.SQLExecuteStatusTerminateSucc, model.SQLExecuteStatusInitialized), model.ExecutionAttemptOperationResume, []uint{1, 2}, false},
+		{"skip transaction", newTask(1, model.SQLExecuteStatusSucceeded, model.SQLExecuteStatusFailed, model.SQLExecuteStatusFailed, model.SQLExecuteStatusInitialized), model.ExecutionAttemptOperationSkip, nil, true},
+		{"resume transaction", newTask(1, model.SQLExecuteStatusSucceeded, model.SQLExecuteStatusFailed, model.SQLExecuteStatusFailed, model.SQLExecuteStatusInitialized), model.ExecutionAttemptOperationResume, []uint{2, 3, 4}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executeSQLs, err := getResumeExecuteSQLs(tt.task, tt.operation)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, numbers(executeSQLs))
+		})
+	}
+}
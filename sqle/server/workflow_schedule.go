@@ -210,7 +210,9 @@ func updateStatus(s *model.Storage, workflow *model.Workflow, l *logrus.Entry) {
 	}
 }
 
-func ApproveWorkflowProcess(workflow *model.Workflow, user *model.User, s *model.Storage) error {
+// ApproveWorkflowProcess approves the current step of workflow, the suppressed
+// audit results of acknowledgedSQLs are acknowledged by the user at the same time.
+func ApproveWorkflowProcess(workflow *model.Workflow, user *model.User, s *model.Storage, acknowledgedSQLs []*model.ExecuteSQL) error {
 	currentStep := workflow.CurrentStep()
 
 	if workflow.Record.Status == model.WorkflowStatusWaitForExecution {
@@ -227,8 +229,9 @@ func ApproveWorkflowProcess(workflow *model.Workflow, user *model.User, s *model
 	if nextStep.Template.Typ == model.WorkflowStepTypeSQLExecute {
 		workflow.Record.Status = model.WorkflowStatusWaitForExecution
 	}
+	acknowledgeSuppressions(acknowledgedSQLs, user.GetIDStr())
 
-	err := s.UpdateWorkflowStep(workflow, currentStep)
+	err := s.ApproveWorkflowStep(workflow, currentStep, acknowledgedSQLs)
 	if err != nil {
 		return fmt.Errorf("update workflow status failed, %v", err)
 	}
@@ -15,6 +15,7 @@ import (
 	// "github.com/actiontech/sqle/sqle/api/cloudbeaver_wrapper/service"
 	"github.com/actiontech/sqle/sqle/config"
 	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/locale"
 	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/server"
@@ -44,6 +45,12 @@ func Run(options *config.SqleOptions) error {
 		return fmt.Errorf("report host is required on cluster mode")
 	}
 
+	if sqleCnf.DefaultLanguage != "" {
+		if err := locale.SetDefaultLang(sqleCnf.DefaultLanguage); err != nil {
+			return err
+		}
+	}
+
 	secretKey := options.SecretKey
 	if secretKey != "" {
 		// reset jwt singing key, default dms token
@@ -110,6 +117,16 @@ func Run(options *config.SqleOptions) error {
 		if err := s.CreateDefaultTemplateIfNotExist(model.ProjectIdForGlobalRuleTemplate, driver.GetPluginManager().GetAllRules()); err != nil {
 			return fmt.Errorf("create default template failed while auto migrating table: %v", err)
 		}
+		// the plugins added or updated by reloading may have new rules.
+		driver.GetPluginManager().OnPluginsReloaded(func() {
+			rules := model.MergeOptimizationRules(driver.GetPluginManager().GetAllRules(), opt.OptimizationRuleMap)
+			if err := s.CreateRulesIfNotExist(rules); err != nil {
+				log.Logger().Errorf("create rules of reloaded plugins failed: %v", err)
+			}
+			if err := s.CreateDefaultTemplateIfNotExist(model.ProjectIdForGlobalRuleTemplate, driver.GetPluginManager().GetAllRules()); err != nil {
+				log.Logger().Errorf("create default template of reloaded plugins failed: %v", err)
+			}
+		})
 	}
 	exitChan := make(chan struct{})
 	server.InitSqled(exitChan)
@@ -118,6 +135,7 @@ func Run(options *config.SqleOptions) error {
 	if sqleCnf.EnableClusterMode {
 		cluster.IsClusterMode = true
 		log.Logger().Infoln("running sqled server on cluster mode")
+		cluster.DefaultNode = cluster.NewLeaseNode(s, options.ReportHost, cluster.DefaultLeaseDuration, cluster.DefaultRenewInterval)
 		node = cluster.DefaultNode
 		node.Join(fmt.Sprintf("%v", options.ID))
 		defer node.Leave()
@@ -3,4 +3,5 @@ package utils
 const (
 	MybatisFileSuffix = "xml"
 	SQLFileSuffix     = "sql"
+	JavaFileSuffix    = "java"
 )